@@ -1,14 +1,16 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"os"
+	"time"
 
 	"github.com/ernoaapa/eliot/cmd"
 	"github.com/ernoaapa/eliot/pkg/api"
 	"github.com/ernoaapa/eliot/pkg/cmd/ui"
 	"github.com/ernoaapa/eliot/pkg/term"
-	"github.com/pkg/errors"
 	"github.com/urfave/cli"
 )
 
@@ -24,6 +26,24 @@ var attachCommand = cli.Command{
 
 	 # If pod contains multiple containers, you must define container id
 	 eli attach --container some-id my-pod
+
+	 # Close the attach if there's no input or output in one minute
+	 eli attach --idle-timeout=1m my-pod
+
+	 # Keep stdout and stderr lines separated when the process logs heavily to both
+	 eli attach --line-buffered my-pod
+
+	 # Attach again automatically if the connection breaks, e.g. over flaky cellular link
+	 eli attach --reconnect my-pod
+
+	 # Receive only the error output, the node doesn't send stdout at all
+	 eli attach --output stderr my-pod
+
+	 # Detach with Ctrl-X instead of the default Ctrl-P Ctrl-Q, the container keeps running
+	 eli attach --detach-keys ctrl-x my-pod
+
+	 # Attach right after creating the pod, wait up to 30 seconds for the container to start
+	 eli create -f ./pod.yml && eli attach --wait 30s my-pod
 `,
 	Flags: []cli.Flag{
 		cli.BoolFlag{
@@ -34,6 +54,30 @@ var attachCommand = cli.Command{
 			Name:  "container, c",
 			Usage: "Target container in the pod",
 		},
+		cli.DurationFlag{
+			Name:  "idle-timeout",
+			Usage: "Close the attach if no data is sent or received within the duration, zero disables the timeout",
+		},
+		cli.BoolFlag{
+			Name:  "line-buffered",
+			Usage: "Write the output line by line so that stdout and stderr lines don't get mixed. Not for interactive or binary output",
+		},
+		cli.BoolFlag{
+			Name:  "reconnect",
+			Usage: "Attach again if the connection breaks. Input typed while disconnected is lost",
+		},
+		cli.StringFlag{
+			Name:  "output",
+			Usage: "Receive only stdout or stderr output stream (default: both)",
+		},
+		cli.StringFlag{
+			Name:  "detach-keys",
+			Usage: "Key sequence to detach from the container without stopping it, e.g. ctrl-x (default: ctrl-p,ctrl-q)",
+		},
+		cli.DurationFlag{
+			Name:  "wait",
+			Usage: "Wait up to the duration for the container to start, e.g. right after the pod is created. Missing pod fails right away",
+		},
 	},
 	Action: func(clicontext *cli.Context) error {
 		var (
@@ -43,7 +87,10 @@ var attachCommand = cli.Command{
 		)
 
 		config := cmd.GetConfigProvider(clicontext)
-		client := cmd.GetClient(config)
+		client := cmd.GetClient(config, cmd.GetClientOpts(clicontext)...)
+		defer client.Close()
+		ctx, cancel := cmd.StreamContext()
+		defer cancel()
 
 		if clicontext.NArg() == 0 || clicontext.Args().First() == "" {
 			return fmt.Errorf("You must give Pod name as first argument")
@@ -51,16 +98,11 @@ var attachCommand = cli.Command{
 		podName := clicontext.Args().First()
 		containerName := clicontext.String("container")
 
-		pod, err := client.GetPod(podName)
+		containerID, err := resolveAttachContainerID(ctx, client, podName, containerName, clicontext.Duration("wait"))
 		if err != nil {
 			return err
 		}
 
-		containerID, err := cmd.ResolveContainerID(pod.Status.ContainerStatuses, containerName)
-		if err != nil {
-			return errors.Wrapf(err, "Failed to resolve containerID for pod [%s]", podName)
-		}
-
 		term := term.TTY{
 			Out: stdout,
 		}
@@ -70,12 +112,49 @@ var attachCommand = cli.Command{
 			term.Raw = true
 		}
 
+		attachIO := api.NewAttachIO(term.In, term.Out, stderr)
+		if term.IsTerminalIn() {
+			attachIO.Resize = term.MonitorSize(term.GetSize())
+		}
+		attachIO.IdleTimeout = clicontext.Duration("idle-timeout")
+		attachIO.LineBuffered = clicontext.Bool("line-buffered")
+		attachIO.Streams = api.AttachStreams(clicontext.String("output"))
+		if keys := clicontext.String("detach-keys"); keys != "" {
+			attachIO.DetachKeys, err = api.ParseDetachKeys(keys)
+			if err != nil {
+				return err
+			}
+		}
+
 		// Stop updating ui lines, let the std piping take the terminal
 		ui.Stop()
 		defer ui.Start()
 
-		return term.Safe(func() error {
-			return client.Attach(containerID, api.NewAttachIO(term.In, term.Out, stderr))
+		err = term.Safe(func() error {
+			if clicontext.Bool("reconnect") {
+				return client.AttachWithReconnect(ctx, containerID, attachIO, api.DefaultReconnectPolicy)
+			}
+			return client.Attach(ctx, containerID, attachIO)
 		})
+		if errors.Is(err, api.ErrDetached) {
+			return nil
+		}
+		return err
 	},
 }
+
+// resolveAttachContainerID return the container ID, and with wait, waits the container to be running first
+func resolveAttachContainerID(ctx context.Context, client *api.Client, podName, containerName string, wait time.Duration) (string, error) {
+	if wait > 0 {
+		uiline := ui.NewLine().Loadingf("Waiting for container to start...")
+		status, err := client.WaitForContainerRunning(ctx, podName, containerName, wait)
+		if err != nil {
+			uiline.Errorf("Container in pod [%s] didn't start: %s", podName, err)
+			return "", err
+		}
+		uiline.Donef("Container [%s] running", status.Name)
+		return status.ContainerID, nil
+	}
+
+	return client.ResolveContainerID(ctx, podName, containerName)
+}
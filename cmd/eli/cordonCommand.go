@@ -0,0 +1,70 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/ernoaapa/eliot/cmd"
+	"github.com/ernoaapa/eliot/pkg/cmd/ui"
+	"github.com/urfave/cli"
+)
+
+var cordonCommand = cli.Command{
+	Name:        "cordon",
+	HelpName:    "cordon",
+	Usage:       "Stop restarting pod containers",
+	Description: "You can use this command to keep crash looping container stopped while debugging it, without deleting the pod",
+	UsageText: `eli cordon POD_NAME
+
+	 # Don't restart the pod containers until the pod gets uncordoned
+	 eli cordon my-pod
+`,
+	Action: func(clicontext *cli.Context) error {
+		return setPodCordoned(clicontext, true)
+	},
+}
+
+var uncordonCommand = cli.Command{
+	Name:        "uncordon",
+	HelpName:    "uncordon",
+	Usage:       "Resume restarting pod containers",
+	Description: "You can use this command to restart the pod containers again by the restart policy after cordon",
+	UsageText: `eli uncordon POD_NAME
+
+	 # Restart the stopped pod containers by the restart policy again
+	 eli uncordon my-pod
+`,
+	Action: func(clicontext *cli.Context) error {
+		return setPodCordoned(clicontext, false)
+	},
+}
+
+func setPodCordoned(clicontext *cli.Context, cordoned bool) error {
+	if clicontext.NArg() == 0 || clicontext.Args().First() == "" {
+		return fmt.Errorf("You must give Pod name as first argument")
+	}
+	podName := clicontext.Args().First()
+
+	config := cmd.GetConfigProvider(clicontext)
+	client := cmd.GetClient(config, cmd.GetClientOpts(clicontext)...)
+	defer client.Close()
+	ctx, cancel := cmd.RequestContext()
+	defer cancel()
+
+	if cordoned {
+		line := ui.NewLine().Loadingf("Cordon pod %s...", podName)
+		if _, err := client.CordonPod(ctx, podName); err != nil {
+			line.Errorf("Failed to cordon pod [%s]: %s", podName, err)
+			return err
+		}
+		line.Donef("Pod %s cordoned, containers don't get restarted", podName)
+		return nil
+	}
+
+	line := ui.NewLine().Loadingf("Uncordon pod %s...", podName)
+	if _, err := client.UncordonPod(ctx, podName); err != nil {
+		line.Errorf("Failed to uncordon pod [%s]: %s", podName, err)
+		return err
+	}
+	line.Donef("Pod %s uncordoned", podName)
+	return nil
+}
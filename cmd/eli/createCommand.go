@@ -4,9 +4,9 @@ import (
 	"os"
 
 	"github.com/ernoaapa/eliot/cmd"
+	"github.com/ernoaapa/eliot/pkg/api"
 	pods "github.com/ernoaapa/eliot/pkg/api/services/pods/v1"
 	"github.com/ernoaapa/eliot/pkg/printers"
-	"github.com/ernoaapa/eliot/pkg/progress"
 	"github.com/ernoaapa/eliot/pkg/resolve"
 	"github.com/pkg/errors"
 	"github.com/urfave/cli"
@@ -21,6 +21,12 @@ var createCommand = cli.Command{
 
 	 # Create pod based on pod.yml
 	 eli create -f ./pod.yml
+
+	 # Substitute ${HOSTNAME} and ${VERSION} references in pod.yml
+	 eli create --expand-env --var VERSION=1.2.3 -f ./pod.yml
+
+	 # Render pod.yml as template with {{ .device }} and the node info, e.g. {{ .node.labels.region }}
+	 eli create --set device=pi42 -f ./pod.yml
 `,
 	Flags: []cli.Flag{
 		cli.StringSliceFlag{
@@ -28,47 +34,91 @@ var createCommand = cli.Command{
 
 			Usage: "Filename, directory, or URL to files to use to create the resource",
 		},
+		cli.BoolFlag{
+			Name:  "expand-env",
+			Usage: "Substitute the ${VAR} references in the files with the environment variables",
+		},
+		cli.StringSliceFlag{
+			Name:  "var",
+			Usage: "Substitute the ${NAME} references in the files with the VALUE, e.g. --var NAME=VALUE",
+		},
+		cli.StringSliceFlag{
+			Name:  "set",
+			Usage: "Render the files as templates with the {{ .KEY }} set to the VALUE, e.g. --set KEY=VALUE",
+		},
 	},
 	Subcommands: []cli.Command{
 		createPodCommand,
 	},
 	Action: func(clicontext *cli.Context) (err error) {
+		sources := clicontext.StringSlice("file")
+		if len(sources) == 0 {
+			return errors.New("You need to give --file flag")
+		}
+		values := cmd.GetTemplateValues(clicontext)
+		if values != nil && (clicontext.Bool("expand-env") || len(clicontext.StringSlice("var")) > 0) {
+			return errors.New("The --set flag cannot be combined with --var or --expand-env")
+		}
+
+		config := cmd.GetConfigProvider(clicontext)
+		client := cmd.GetClient(config, cmd.GetClientOpts(clicontext)...)
+		defer client.Close()
+		ctx, cancel := cmd.StreamContext()
+		defer cancel()
+
 		pods := []*pods.Pod{}
-		if len(clicontext.StringSlice("file")) > 0 {
-			pods, err = resolve.Pods(clicontext.StringSlice("file"))
+		if values != nil {
+			manifests, err := resolve.Manifests(sources)
 			if err != nil {
 				return err
 			}
+			for _, m := range manifests {
+				pod, err := client.ExpandTemplate(ctx, m.Data, values)
+				if err != nil {
+					return errors.Wrapf(err, "Failed to render pod template %s", m.Source)
+				}
+				pods = append(pods, pod)
+			}
 		} else {
-			return errors.New("You need to give --file flag")
+			pods, err = resolve.Pods(sources, cmd.GetManifestOpts(clicontext)...)
+			if err != nil {
+				return err
+			}
 		}
 
-		config := cmd.GetConfigProvider(clicontext)
-		client := cmd.GetClient(config)
+		progressc := make(chan api.PodsImageFetchProgress)
+		shown := cmd.ShowPodsDownloadProgress(ctx, progressc)
 
-		for _, pod := range pods {
-			progressc := make(chan []*progress.ImageFetch)
-			go cmd.ShowDownloadProgress(progressc)
+		createErr := client.CreatePods(ctx, progressc, pods)
+		close(progressc)
+		<-shown
 
-			err := client.CreatePod(progressc, pod)
-			close(progressc)
-			if err != nil {
-				return err
+		created := []string{}
+		switch e := createErr.(type) {
+		case nil:
+			for _, pod := range pods {
+				created = append(created, pod.Metadata.Name)
 			}
+		case *api.CreatePodsError:
+			created = e.Created
+		default:
+			return createErr
+		}
+
+		writer := printers.GetNewTabWriter(os.Stdout)
+		defer writer.Flush()
+		printer := cmd.GetPrinter(clicontext)
 
-			result, err := client.StartPod(pod.Metadata.Name)
+		for _, name := range created {
+			result, err := client.StartPod(ctx, name)
 			if err != nil {
 				return err
 			}
 
-			writer := printers.GetNewTabWriter(os.Stdout)
-			defer writer.Flush()
-			printer := cmd.GetPrinter(clicontext)
-
 			if err := printer.PrintPod(result, writer); err != nil {
 				return err
 			}
 		}
-		return nil
+		return createErr
 	},
 }
@@ -4,7 +4,9 @@ import (
 	"os"
 
 	"github.com/ernoaapa/eliot/cmd"
+	"github.com/ernoaapa/eliot/pkg/api"
 	pods "github.com/ernoaapa/eliot/pkg/api/services/pods/v1"
+	"github.com/ernoaapa/eliot/pkg/cmd/ui"
 	"github.com/ernoaapa/eliot/pkg/printers"
 	"github.com/ernoaapa/eliot/pkg/progress"
 	"github.com/ernoaapa/eliot/pkg/resolve"
@@ -21,12 +23,19 @@ var createPodCommand = cli.Command{
 
 	 # Create new pod 'my-pod' and create single container
 	 eli create pod --image alpine my-pod
+
+	 # Check what would be created, without creating anything
+	 eli create pod --dry-run --image alpine my-pod
 `,
 	Flags: []cli.Flag{
 		cli.StringSliceFlag{
 			Name:  "image",
 			Usage: "The container image to run. You can pass as many images you want",
 		},
+		cli.BoolFlag{
+			Name:  "dry-run",
+			Usage: "Only print the pod what would be created, without creating it",
+		},
 	},
 	Action: func(clicontext *cli.Context) error {
 		var (
@@ -46,26 +55,42 @@ var createPodCommand = cli.Command{
 		}
 
 		config := cmd.GetConfigProvider(clicontext)
-		client := cmd.GetClient(config)
+		opts := cmd.GetClientOpts(clicontext)
+		if clicontext.Bool("dry-run") {
+			opts = append(opts, api.WithDryRun())
+		}
+		client := cmd.GetClient(config, opts...)
+		defer client.Close()
+		ctx, cancel := cmd.StreamContext()
+		defer cancel()
+
+		writer := printers.GetNewTabWriter(os.Stdout)
+		defer writer.Flush()
+		printer := cmd.GetPrinter(clicontext)
+
+		if client.IsDryRun() {
+			if err := client.CreatePod(ctx, nil, pod); err != nil {
+				return err
+			}
+			ui.NewLine().Donef("Dry run, pod %s would be created, nothing changed", pod.Metadata.Name)
+			return printer.PrintPod(pod, writer)
+		}
 
 		progressc := make(chan []*progress.ImageFetch)
-		go cmd.ShowDownloadProgress(progressc)
+		shown := cmd.ShowDownloadProgress(ctx, progressc)
 
-		err := client.CreatePod(progressc, pod)
+		err := client.CreatePod(ctx, progressc, pod)
 		close(progressc)
+		<-shown
 		if err != nil {
 			return err
 		}
 
-		result, err := client.StartPod(pod.Metadata.Name)
+		result, err := client.StartPod(ctx, pod.Metadata.Name)
 		if err != nil {
 			return err
 		}
 
-		writer := printers.GetNewTabWriter(os.Stdout)
-		defer writer.Flush()
-		printer := cmd.GetPrinter(clicontext)
-
 		return printer.PrintPod(result, writer)
 	},
 }
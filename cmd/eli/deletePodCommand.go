@@ -1,7 +1,10 @@
 package main
 
 import (
+	"strings"
+
 	"github.com/ernoaapa/eliot/cmd"
+	"github.com/ernoaapa/eliot/pkg/api"
 	"github.com/ernoaapa/eliot/pkg/cmd/ui"
 	"github.com/urfave/cli"
 )
@@ -16,15 +19,46 @@ var deletePodCommand = cli.Command{
 	 eli delete pods
 
 	 # Delete all 'my-pod' pod
-	 eli delete pod my-pod`,
+	 eli delete pod my-pod
+
+	 # Kill 'my-pod' containers immediately
+	 eli delete pod --grace-period=0 my-pod
+
+	 # Check which pods would be deleted, without deleting anything
+	 eli delete pods --dry-run
+
+	 # Remove also the 'my-pod' volumes, e.g. the eli up sync directories
+	 eli delete pod --cascade my-pod`,
+	Flags: []cli.Flag{
+		cli.DurationFlag{
+			Name:  "grace-period",
+			Usage: "Time to wait containers to exit after SIGTERM before killing them",
+			Value: api.DefaultGracePeriod,
+		},
+		cli.BoolFlag{
+			Name:  "dry-run",
+			Usage: "Only print the pods what would be deleted, without deleting them",
+		},
+		cli.BoolFlag{
+			Name:  "cascade",
+			Usage: "Remove also the pod volumes, except the ones other pods still mount",
+		},
+	},
 	Action: func(clicontext *cli.Context) error {
 		config := cmd.GetConfigProvider(clicontext)
-		client := cmd.GetClient(config)
+		opts := cmd.GetClientOpts(clicontext)
+		if clicontext.Bool("dry-run") {
+			opts = append(opts, api.WithDryRun())
+		}
+		client := cmd.GetClient(config, opts...)
+		defer client.Close()
+		ctx, cancel := cmd.RequestContext()
+		defer cancel()
 
 		podName := clicontext.Args().First()
 
 		uiline := ui.NewLine().Loading("Fetch pods...")
-		pods, err := client.GetPods()
+		pods, err := client.GetPods(ctx)
 		if err != nil {
 			uiline.Fatalf("Failed to fetch pods information: %s", err)
 		}
@@ -42,14 +76,36 @@ var deletePodCommand = cli.Command{
 			}
 		}
 
+		gracePeriod := clicontext.Duration("grace-period")
 		for _, pod := range pods {
 			uiline = ui.NewLine().Loadingf("Deleting pod %s", pod.Metadata.Name)
-			deleted, err := client.DeletePod(pod)
+			// Deleting waits the containers to stop, so the request can take the whole grace period
+			deleteCtx, cancelDelete := cmd.RequestContextWithWait(gracePeriod)
+			result, err := client.DeletePodWithResult(deleteCtx, pod, api.WithGracePeriod(gracePeriod), api.WithCascade(clicontext.Bool("cascade")))
+			cancelDelete()
 			if err != nil {
 				return err
 			}
-			uiline.Donef("Deleted pod %s", deleted.Metadata.Name)
+			if client.IsDryRun() {
+				uiline.Donef("Dry run, pod %s would be deleted, nothing changed", result.Pod.Metadata.Name)
+			} else {
+				uiline.Donef("Deleted pod %s", result.Pod.Metadata.Name)
+			}
+			printDeletedVolumes(result, client.IsDryRun())
 		}
 		return nil
 	},
 }
+
+func printDeletedVolumes(result *api.DeleteResult, dryRun bool) {
+	for _, volume := range result.RemovedVolumes {
+		if dryRun {
+			ui.NewLine().Infof("Volume %s would be removed", volume)
+		} else {
+			ui.NewLine().Donef("Removed volume %s", volume)
+		}
+	}
+	for volume, pods := range result.RetainedVolumes {
+		ui.NewLine().Warnf("Retained volume %s, still mounted by %s", volume, strings.Join(pods, ", "))
+	}
+}
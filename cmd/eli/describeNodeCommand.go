@@ -43,7 +43,14 @@ var describeNodeCommand = cli.Command{
 		defer writer.Flush()
 		printer := cmd.GetPrinter(clicontext)
 		for _, endpoint := range endpoints {
-			info, err := api.NewClient(cfg.GetNamespace(), endpoint).GetInfo()
+			client, err := api.NewClient(cfg.GetNamespace(), endpoint, cmd.GetClientOpts(clicontext)...)
+			if err != nil {
+				return err
+			}
+			ctx, cancel := cmd.RequestContext()
+			info, err := client.GetInfo(ctx)
+			cancel()
+			client.Close()
 			if err != nil {
 				return errors.Wrap(err, "Failed to fetch node info")
 			}
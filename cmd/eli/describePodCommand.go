@@ -22,11 +22,14 @@ var describePodCommand = cli.Command{
 `,
 	Action: func(clicontext *cli.Context) error {
 		config := cmd.GetConfigProvider(clicontext)
-		client := cmd.GetClient(config)
+		client := cmd.GetClient(config, cmd.GetClientOpts(clicontext)...)
+		defer client.Close()
+		ctx, cancel := cmd.RequestContext()
+		defer cancel()
 
 		podName := clicontext.Args().First()
 
-		pods, err := client.GetPods()
+		pods, err := client.GetPods(ctx)
 		if err != nil {
 			return err
 		}
@@ -56,13 +56,16 @@ var execCommand = cli.Command{
 		)
 
 		config := cmd.GetConfigProvider(clicontext)
-		client := cmd.GetClient(config)
+		client := cmd.GetClient(config, cmd.GetClientOpts(clicontext)...)
+		defer client.Close()
+		ctx, cancel := cmd.StreamContext()
+		defer cancel()
 
 		if clicontext.NArg() == 0 || clicontext.Args().First() == "" {
 			return fmt.Errorf("You must give Pod name as first argument")
 		}
 
-		pod, err := client.GetPod(podName)
+		pod, err := client.GetPod(ctx, podName)
 		if err != nil {
 			return err
 		}
@@ -81,12 +84,27 @@ var execCommand = cli.Command{
 			term.Raw = true
 		}
 
+		attachIO := api.NewAttachIO(term.In, term.Out, stderr)
+		if term.IsTerminalIn() {
+			attachIO.Resize = term.MonitorSize(term.GetSize())
+		}
+
 		// Stop updating ui lines, let the std piping take the terminal
 		ui.Stop()
 		defer ui.Start()
 
-		return term.Safe(func() error {
-			return client.Exec(containerID, args, tty, api.NewAttachIO(term.In, term.Out, stderr))
+		var exitCode int
+		err = term.Safe(func() (err error) {
+			exitCode, err = client.Exec(ctx, containerID, args, tty, attachIO)
+			return err
 		})
+		if err != nil {
+			return err
+		}
+
+		if exitCode != 0 {
+			return cli.NewExitError("", exitCode)
+		}
+		return nil
 	},
 }
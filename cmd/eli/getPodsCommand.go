@@ -15,12 +15,24 @@ var getPodsCommand = cli.Command{
 	UsageText: `eli get pods [options]
 			 
 	 # Get table of running pods
-	 eli get pods`,
+	 eli get pods
+
+	 # Get pods by labels
+	 eli get pods -l app=nginx,env!=dev`,
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name:  "selector, l",
+			Usage: "Label selector to filter pods, supports 'key=value', 'key!=value' and 'key'",
+		},
+	},
 	Action: func(clicontext *cli.Context) error {
 		config := cmd.GetConfigProvider(clicontext)
-		client := cmd.GetClient(config)
+		client := cmd.GetClient(config, cmd.GetClientOpts(clicontext)...)
+		defer client.Close()
+		ctx, cancel := cmd.RequestContext()
+		defer cancel()
 
-		pods, err := client.GetPods()
+		pods, err := client.GetPodsBySelector(ctx, clicontext.String("selector"))
 		if err != nil {
 			return err
 		}
@@ -0,0 +1,61 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/ernoaapa/eliot/cmd"
+	"github.com/pkg/errors"
+	"github.com/urfave/cli"
+)
+
+var inspectCommand = cli.Command{
+	Name:        "inspect",
+	HelpName:    "inspect",
+	Usage:       "Print all details of pod container in JSON",
+	Description: "You can use this command to troubleshoot the container, it prints the container command, mounts, namespaces, cgroups path, image digest and the full OCI spec",
+	UsageText: `eli inspect [options] POD_NAME
+
+	 # Print the details of the pod container
+	 eli inspect my-pod
+
+	 # If pod contains multiple containers, you must define container name
+	 eli inspect --container some-name my-pod
+`,
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name:  "container, c",
+			Usage: "Target container in the pod",
+		},
+	},
+	Action: func(clicontext *cli.Context) error {
+		if clicontext.NArg() == 0 || clicontext.Args().First() == "" {
+			return fmt.Errorf("You must give Pod name as first argument")
+		}
+		podName := clicontext.Args().First()
+
+		config := cmd.GetConfigProvider(clicontext)
+		client := cmd.GetClient(config, cmd.GetClientOpts(clicontext)...)
+		defer client.Close()
+		ctx, cancel := cmd.RequestContext()
+		defer cancel()
+
+		containerID, err := client.ResolveContainerID(ctx, podName, clicontext.String("container"))
+		if err != nil {
+			return err
+		}
+
+		inspect, err := client.Inspect(ctx, containerID)
+		if err != nil {
+			return err
+		}
+
+		data, err := json.MarshalIndent(inspect, "", "  ")
+		if err != nil {
+			return errors.Wrap(err, "Failed to format the container details")
+		}
+		fmt.Fprintln(os.Stdout, string(data))
+		return nil
+	},
+}
@@ -0,0 +1,60 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/ernoaapa/eliot/cmd"
+	"github.com/ernoaapa/eliot/pkg/api"
+	"github.com/urfave/cli"
+)
+
+var killCommand = cli.Command{
+	Name:        "kill",
+	HelpName:    "kill",
+	Usage:       "Send signal to pod containers",
+	Description: "You can use this command to send signal to the containers, e.g. SIGHUP to reload the configuration",
+	UsageText: `eli kill [options] POD_NAME
+
+	 # Send SIGTERM to all running containers in the pod
+	 eli kill my-pod
+
+	 # Ask the process to reload the configuration
+	 eli kill --signal HUP my-pod
+
+	 # Send the signal only to single container
+	 eli kill -s SIGUSR1 --container some-name my-pod
+`,
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name:  "signal, s",
+			Usage: "Signal name or number, e.g. TERM, SIGHUP or 9",
+			Value: "TERM",
+		},
+		cli.StringFlag{
+			Name:  "container, c",
+			Usage: "Target container in the pod (default: all running containers)",
+		},
+	},
+	Action: func(clicontext *cli.Context) error {
+		if clicontext.NArg() == 0 || clicontext.Args().First() == "" {
+			return fmt.Errorf("You must give Pod name as first argument")
+		}
+		podName := clicontext.Args().First()
+
+		signal, err := api.ParseSignal(clicontext.String("signal"))
+		if err != nil {
+			return err
+		}
+
+		config := cmd.GetConfigProvider(clicontext)
+		client := cmd.GetClient(config, cmd.GetClientOpts(clicontext)...)
+		defer client.Close()
+		ctx, cancel := cmd.RequestContext()
+		defer cancel()
+
+		if containerName := clicontext.String("container"); containerName != "" {
+			return client.SignalContainer(ctx, podName, containerName, signal)
+		}
+		return client.SignalPod(ctx, podName, signal)
+	},
+}
@@ -0,0 +1,116 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/ernoaapa/eliot/cmd"
+	"github.com/ernoaapa/eliot/pkg/api"
+	pods "github.com/ernoaapa/eliot/pkg/api/services/pods/v1"
+	"github.com/ernoaapa/eliot/pkg/cmd/ui"
+	"github.com/fatih/color"
+	"github.com/pkg/errors"
+	"github.com/urfave/cli"
+)
+
+var logsCommand = cli.Command{
+	Name:        "logs",
+	HelpName:    "logs",
+	Usage:       "Print container logs",
+	Description: "You can use this command to print latest output lines of the container and follow the new lines",
+	UsageText: `eli logs [options] POD_NAME
+
+	 # View pod logs
+	 eli logs my-pod
+
+	 # View last 100 lines and follow the new lines
+	 eli logs --tail 100 --follow my-pod
+
+	 # If pod contains multiple containers, the lines of all containers get prefixed with the container name
+	 eli logs --follow my-pod
+
+	 # View only single container logs
+	 eli logs --container some-name my-pod
+`,
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name:  "container, c",
+			Usage: "Target container in the pod",
+		},
+		cli.BoolFlag{
+			Name:  "follow, f",
+			Usage: "Keep printing new log lines",
+		},
+		cli.IntFlag{
+			Name:  "tail",
+			Usage: "Print only last N lines (default: all lines)",
+		},
+		cli.DurationFlag{
+			Name:  "since",
+			Usage: "Print only lines newer than relative duration, e.g. 10m",
+		},
+	},
+	Action: func(clicontext *cli.Context) error {
+		config := cmd.GetConfigProvider(clicontext)
+		client := cmd.GetClient(config, cmd.GetClientOpts(clicontext)...)
+		defer client.Close()
+		ctx, cancel := cmd.StreamContext()
+		defer cancel()
+
+		if clicontext.NArg() == 0 || clicontext.Args().First() == "" {
+			return fmt.Errorf("You must give Pod name as first argument")
+		}
+		podName := clicontext.Args().First()
+		containerName := clicontext.String("container")
+
+		pod, err := client.GetPod(ctx, podName)
+		if err != nil {
+			return err
+		}
+
+		opts := api.LogOptions{
+			Follow: clicontext.Bool("follow"),
+			Tail:   clicontext.Int("tail"),
+		}
+		if since := clicontext.Duration("since"); since > 0 {
+			opts.Since = time.Now().Add(-since)
+		}
+
+		if containerName == "" && len(pod.Status.ContainerStatuses) > 1 {
+			opts.Prefix = newColorLogPrefix(pod)
+			ui.Stop()
+			defer ui.Start()
+			return client.PodLogs(ctx, podName, os.Stdout, opts)
+		}
+
+		containerID, err := cmd.ResolveContainerID(pod.Status.ContainerStatuses, containerName)
+		if err != nil {
+			return errors.Wrapf(err, "Failed to resolve containerID for pod [%s]", podName)
+		}
+
+		// Stop updating ui lines, let the log lines take the terminal
+		ui.Stop()
+		defer ui.Start()
+
+		return client.FollowLogs(ctx, containerID, opts, os.Stdout)
+	},
+}
+
+// logPrefixColors are the colors of the container name prefixes, so the containers are easy to tell apart
+var logPrefixColors = []color.Attribute{color.FgCyan, color.FgYellow, color.FgGreen, color.FgMagenta, color.FgBlue}
+
+// newColorLogPrefix returns PodLogs prefix which colors each container name in the pod with different color
+func newColorLogPrefix(pod *pods.Pod) func(containerName string) string {
+	colors := map[string]*color.Color{}
+	for i, status := range pod.Status.ContainerStatuses {
+		colors[status.Name] = color.New(logPrefixColors[i%len(logPrefixColors)])
+	}
+	return func(containerName string) string {
+		c, ok := colors[containerName]
+		if !ok {
+			return api.DefaultLogPrefix(containerName)
+		}
+		return c.Sprintf("[%s]", containerName) + " "
+	}
+}
@@ -57,8 +57,29 @@ func main() {
 			Usage:  "Use specific node by name. E.g. 'somehost.local'",
 			EnvVar: "ELIOT_NODE",
 		},
+		cli.BoolFlag{
+			Name:   "tls",
+			Usage:  "Use TLS when connecting to the node. Enabled automatically if any other --tls-* flag is given",
+			EnvVar: "ELIOT_TLS",
+		},
+		cli.StringFlag{
+			Name:   "tls-cert",
+			Usage:  "Client certificate file for mutual TLS",
+			EnvVar: "ELIOT_TLS_CERT",
+		},
+		cli.StringFlag{
+			Name:   "tls-key",
+			Usage:  "Client certificate key file for mutual TLS",
+			EnvVar: "ELIOT_TLS_KEY",
+		},
+		cli.StringFlag{
+			Name:   "tls-ca",
+			Usage:  "CA certificate file to verify the node certificate (default: system root CAs)",
+			EnvVar: "ELIOT_TLS_CA",
+		},
 	}, cmd.GlobalFlags...)
 	app.Version = fmt.Sprintf("Version: %s, Commit: %s, Build at: %s", version, commit, date)
+	cmd.Version = version
 	app.Before = cmd.GlobalBefore
 
 	app.Commands = []cli.Command{
@@ -66,12 +87,22 @@ func main() {
 		describeCommand,
 		deleteCommand,
 		attachCommand,
+		logsCommand,
 		runCommand,
 		upCommand,
 		execCommand,
+		portForwardCommand,
+		killCommand,
+		waitCommand,
+		inspectCommand,
+		cordonCommand,
+		uncordonCommand,
+		setenvCommand,
+		pruneCommand,
 		createCommand,
 		configCommand,
 		buildCommand,
+		versionCommand,
 	}
 
 	err := app.Run(os.Args)
@@ -0,0 +1,125 @@
+package main
+
+import (
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+
+	"github.com/ernoaapa/eliot/cmd"
+	"github.com/ernoaapa/eliot/pkg/cmd/ui"
+	"github.com/pkg/errors"
+	"github.com/urfave/cli"
+)
+
+var portForwardCommand = cli.Command{
+	Name:        "port-forward",
+	HelpName:    "port-forward",
+	Usage:       "Forward local ports to a container in the pod",
+	Description: "You can use this command to connect to a service listening inside the container without exposing it on the device",
+	UsageText: `eli port-forward [options] POD_NAME [LOCAL_PORT:]REMOTE_PORT [...[LOCAL_PORT_N:]REMOTE_PORT_N]
+
+	 # Listen local port 8080 and forward to port 8080 in the pod
+	 eli port-forward my-pod 8080
+
+	 # Listen local ports 5000 and 6000 and forward to ports 5000 and 6000 in the pod
+	 eli port-forward my-pod 5000 6000
+
+	 # Listen local port 8888 and forward to port 8080 in the pod
+	 eli port-forward my-pod 8888:8080
+
+	 # If pod contains multiple containers, you must define container name
+	 eli port-forward --container some-name my-pod 8080
+`,
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name:  "container, c",
+			Usage: "Target container in the pod",
+		},
+		cli.StringFlag{
+			Name:  "address",
+			Usage: "Local address to listen",
+			Value: "localhost",
+		},
+	},
+	Action: func(clicontext *cli.Context) error {
+		config := cmd.GetConfigProvider(clicontext)
+		client := cmd.GetClient(config, cmd.GetClientOpts(clicontext)...)
+		defer client.Close()
+
+		if clicontext.NArg() < 2 || clicontext.Args().First() == "" {
+			return fmt.Errorf("You must give Pod name as first argument and at least one port")
+		}
+		podName := clicontext.Args().First()
+		containerName := clicontext.String("container")
+
+		ctx, cancel := cmd.StreamContext()
+		defer cancel()
+
+		pod, err := client.GetPod(ctx, podName)
+		if err != nil {
+			return err
+		}
+
+		containerID, err := cmd.ResolveContainerID(pod.Status.ContainerStatuses, containerName)
+		if err != nil {
+			return errors.Wrapf(err, "Failed to resolve containerID for pod [%s]", podName)
+		}
+
+		listeners := []net.Listener{}
+		remotePorts := []int{}
+		for _, spec := range clicontext.Args().Tail() {
+			localPort, remotePort, err := parsePortSpec(spec)
+			if err != nil {
+				return err
+			}
+			listener, err := net.Listen("tcp", net.JoinHostPort(clicontext.String("address"), strconv.Itoa(localPort)))
+			if err != nil {
+				return errors.Wrapf(err, "Unable to listen local port %d", localPort)
+			}
+			defer listener.Close()
+			listeners = append(listeners, listener)
+			remotePorts = append(remotePorts, remotePort)
+		}
+
+		// Stop updating ui lines, the forwarding runs until interrupted
+		ui.Stop()
+		defer ui.Start()
+
+		errc := make(chan error, len(listeners))
+		for i, listener := range listeners {
+			fmt.Printf("Forwarding from %s -> %d\n", listener.Addr(), remotePorts[i])
+			go func(listener net.Listener, remotePort int) {
+				errc <- client.PortForwardListener(ctx, containerID, listener, remotePort)
+			}(listener, remotePorts[i])
+		}
+
+		err = <-errc
+		if ctx.Err() != nil {
+			return nil
+		}
+		return err
+	},
+}
+
+// parsePortSpec parses [LOCAL_PORT:]REMOTE_PORT, local port defaults to the remote port
+func parsePortSpec(spec string) (localPort, remotePort int, err error) {
+	parts := strings.Split(spec, ":")
+	if len(parts) > 2 {
+		return 0, 0, fmt.Errorf("Invalid port [%s], must be in format [LOCAL_PORT:]REMOTE_PORT", spec)
+	}
+
+	remotePort, err = strconv.Atoi(parts[len(parts)-1])
+	if err != nil || remotePort < 1 || remotePort > 65535 {
+		return 0, 0, fmt.Errorf("Invalid remote port [%s], must be number between 1 and 65535", parts[len(parts)-1])
+	}
+
+	localPort = remotePort
+	if len(parts) == 2 {
+		localPort, err = strconv.Atoi(parts[0])
+		if err != nil || localPort < 0 || localPort > 65535 {
+			return 0, 0, fmt.Errorf("Invalid local port [%s], must be number between 0 and 65535", parts[0])
+		}
+	}
+	return localPort, remotePort, nil
+}
@@ -0,0 +1,60 @@
+package main
+
+import (
+	"github.com/c2h5oh/datasize"
+	"github.com/ernoaapa/eliot/cmd"
+	"github.com/ernoaapa/eliot/pkg/api"
+	"github.com/ernoaapa/eliot/pkg/cmd/ui"
+	"github.com/urfave/cli"
+)
+
+var pruneCommand = cli.Command{
+	Name:        "prune",
+	HelpName:    "prune",
+	Usage:       "Remove finished pods and unused images",
+	Description: "You can use this command to free disk space in the device. Pods which have running containers or containers which get restarted are never removed",
+	UsageText: `eli prune [options]
+
+	 # Remove all finished pods and the images which no container use
+	 eli prune
+
+	 # Keep the pods which finished and the images which were pulled within the last day
+	 eli prune --keep 24h
+
+	 # Remove only the finished migration pods and their images
+	 eli prune --selector app=migrate
+`,
+	Flags: []cli.Flag{
+		cli.DurationFlag{
+			Name:  "keep",
+			Usage: "Keep the pods which finished and the images which were pulled within the duration",
+		},
+		cli.StringFlag{
+			Name:  "selector, l",
+			Usage: "Remove only the pods matching the label selector, e.g. app=migrate, and only their images",
+		},
+	},
+	Action: func(clicontext *cli.Context) error {
+		config := cmd.GetConfigProvider(clicontext)
+		client := cmd.GetClient(config, cmd.GetClientOpts(clicontext)...)
+		defer client.Close()
+		ctx, cancel := cmd.RequestContext()
+		defer cancel()
+
+		line := ui.NewLine().Loading("Remove finished pods and unused images...")
+		result, err := client.Prune(ctx, api.PruneOptions{
+			KeepFor:  clicontext.Duration("keep"),
+			Selector: clicontext.String("selector"),
+		})
+		if err != nil {
+			line.Errorf("Failed to prune: %s", err)
+			return err
+		}
+
+		line.Donef("Removed %d containers and %d images, reclaimed %s", len(result.Containers), len(result.Images), datasize.ByteSize(result.ReclaimedBytes).HumanReadable())
+		for _, image := range result.Images {
+			ui.NewLine().Infof("Removed image %s", image)
+		}
+		return nil
+	},
+}
@@ -86,7 +86,10 @@ var runCommand = cli.Command{
 		}
 
 		conf := cmd.GetConfigProvider(clicontext)
-		client := cmd.GetClient(conf)
+		client := cmd.GetClient(conf, cmd.GetClientOpts(clicontext)...)
+		defer client.Close()
+		ctx, cancel := cmd.StreamContext()
+		defer cancel()
 
 		if name == "" {
 			// Default to current directory name
@@ -121,10 +124,11 @@ var runCommand = cli.Command{
 		}
 
 		progressc := make(chan []*progress.ImageFetch)
-		go cmd.ShowDownloadProgress(progressc)
+		shown := cmd.ShowDownloadProgress(ctx, progressc)
 
-		createErr := client.CreatePod(progressc, pod)
+		createErr := client.CreatePod(ctx, progressc, pod)
 		close(progressc)
+		<-shown
 		if createErr != nil {
 			return errors.Wrapf(createErr, "Error in creating pod")
 		}
@@ -132,7 +136,10 @@ var runCommand = cli.Command{
 		if rm {
 			defer func() {
 				uiline := ui.NewLine().Loadingf("Delete pod %s", pod.Metadata.Name)
-				_, err := client.DeletePod(pod)
+				// The command context is cancelled already if the command got interrupted
+				deleteCtx, cancelDelete := cmd.RequestContextWithWait(api.DefaultGracePeriod)
+				defer cancelDelete()
+				_, err := client.DeletePod(deleteCtx, pod)
 				if err != nil {
 					uiline.Errorf("Error while deleting pod [%s]: %s", pod.Metadata.Name, err)
 				} else {
@@ -141,7 +148,7 @@ var runCommand = cli.Command{
 			}()
 		}
 
-		result, err := client.StartPod(name)
+		result, err := client.StartPod(ctx, name)
 		if err != nil {
 			return errors.Wrapf(err, "Error in starting pod")
 		}
@@ -160,17 +167,24 @@ var runCommand = cli.Command{
 			term.Raw = true
 		} else {
 			sigc := cmd.ForwardAllSignals(func(signal syscall.Signal) error {
-				return client.Signal(attachContainerID, signal)
+				signalCtx, cancelSignal := cmd.RequestContext()
+				defer cancelSignal()
+				return client.Signal(signalCtx, attachContainerID, signal)
 			})
 			defer cmd.StopCatch(sigc)
 		}
 
+		attachIO := api.NewAttachIO(term.In, term.Out, stderr)
+		if term.IsTerminalIn() {
+			attachIO.Resize = term.MonitorSize(term.GetSize())
+		}
+
 		// Stop updating ui lines, let the std piping take the terminal
 		ui.Stop()
 		defer ui.Start()
 
 		return term.Safe(func() error {
-			return client.Attach(attachContainerID, api.NewAttachIO(term.In, term.Out, stderr))
+			return client.Attach(ctx, attachContainerID, attachIO)
 		})
 	},
 }
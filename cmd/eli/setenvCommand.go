@@ -0,0 +1,83 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"syscall"
+
+	"github.com/ernoaapa/eliot/cmd"
+	"github.com/ernoaapa/eliot/pkg/api"
+	"github.com/ernoaapa/eliot/pkg/cmd/ui"
+	"github.com/urfave/cli"
+)
+
+var setenvCommand = cli.Command{
+	Name:        "setenv",
+	HelpName:    "setenv",
+	Usage:       "Set container environment variables",
+	Description: "You can use this command to change single environment variable without updating the whole pod. The running process keeps the old environment, the new values take effect when the container gets started next time",
+	UsageText: `eli setenv [options] POD_NAME NAME=VALUE...
+
+	 # Use debug log level after the next restart
+	 eli setenv my-pod LOG_LEVEL=debug
+
+	 # Stop the process right away, so the restart policy starts it again with the new environment
+	 eli setenv --signal TERM --container some-name my-pod LOG_LEVEL=debug
+`,
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name:  "signal, s",
+			Usage: "Signal name or number to send to the container process after the update, e.g. TERM (default: no signal)",
+		},
+		cli.StringFlag{
+			Name:  "container, c",
+			Usage: "Target container in the pod, can be left out if the pod has only one container",
+		},
+	},
+	Action: func(clicontext *cli.Context) error {
+		if clicontext.NArg() < 2 || clicontext.Args().First() == "" {
+			return fmt.Errorf("You must give Pod name as first argument and at least one NAME=VALUE")
+		}
+		podName := clicontext.Args().First()
+
+		env := map[string]string{}
+		for _, param := range clicontext.Args().Tail() {
+			pair := strings.SplitN(param, "=", 2)
+			if len(pair) != 2 {
+				return fmt.Errorf("Invalid environment variable [%s], must be in NAME=VALUE format", param)
+			}
+			env[pair[0]] = pair[1]
+		}
+
+		var signal syscall.Signal
+		if name := clicontext.String("signal"); name != "" {
+			var err error
+			if signal, err = api.ParseSignal(name); err != nil {
+				return err
+			}
+		}
+
+		config := cmd.GetConfigProvider(clicontext)
+		client := cmd.GetClient(config, cmd.GetClientOpts(clicontext)...)
+		defer client.Close()
+		ctx, cancel := cmd.RequestContext()
+		defer cancel()
+
+		containerID, err := client.ResolveContainerID(ctx, podName, clicontext.String("container"))
+		if err != nil {
+			return err
+		}
+
+		line := ui.NewLine().Loadingf("Update container %s environment...", containerID)
+		if err := client.SetContainerEnvAndSignal(ctx, containerID, env, signal); err != nil {
+			line.Errorf("Failed to update container [%s] environment: %s", containerID, err)
+			return err
+		}
+		if signal != 0 {
+			line.Donef("Container %s environment updated and signal %d sent", containerID, signal)
+		} else {
+			line.Donef("Container %s environment updated, takes effect when the container starts next time", containerID)
+		}
+		return nil
+	},
+}
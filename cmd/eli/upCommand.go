@@ -111,7 +111,10 @@ var upCommand = cli.Command{
 		}
 
 		conf := cmd.GetConfigProvider(clicontext)
-		client := cmd.GetClient(conf)
+		client := cmd.GetClient(conf, cmd.GetClientOpts(clicontext)...)
+		defer client.Close()
+		ctx, cancel := cmd.StreamContext()
+		defer cancel()
 
 		if image == "" {
 			log := ui.NewLine().Loading("Resolve image for the project...")
@@ -140,7 +143,7 @@ var upCommand = cli.Command{
 
 			for _, sync := range syncs {
 				syncDestinations = append(syncDestinations, sync.Destination)
-				mounts = append(mounts, cmd.MustParseBindFlag(fmt.Sprintf("/var/lib/volumes/%s/%s:%s:rw,rshared", name, strings.Replace(sync.Destination, "/", "_", -1), sync.Destination)))
+				mounts = append(mounts, cmd.MustParseBindFlag(fmt.Sprintf("%s/%s/%s:%s:rw,rshared", model.PodVolumesDir, name, strings.Replace(sync.Destination, "/", "_", -1), sync.Destination)))
 			}
 
 			projectConfig.SyncContainer.Env = append(projectConfig.SyncContainer.Env, fmt.Sprintf("VOLUMES=%s", strings.Join(syncDestinations, " ")))
@@ -176,15 +179,16 @@ var upCommand = cli.Command{
 		}
 
 		progressc := make(chan []*progress.ImageFetch)
-		go cmd.ShowDownloadProgress(progressc)
+		shown := cmd.ShowDownloadProgress(ctx, progressc)
 
-		createErr := client.CreatePod(progressc, pod, opts...)
+		createErr := client.CreatePod(ctx, progressc, pod, opts...)
 		close(progressc)
+		<-shown
 		if createErr != nil {
 			return errors.Wrapf(createErr, "Error in creating pod")
 		}
 
-		result, err := client.StartPod(name)
+		result, err := client.StartPod(ctx, name)
 		if err != nil {
 			return errors.Wrapf(err, "Error in starting pod")
 		}
@@ -192,7 +196,10 @@ var upCommand = cli.Command{
 		if rm {
 			defer func() {
 				log := ui.NewLine().Loadingf("Delete pod %s", pod.Metadata.Name)
-				_, err := client.DeletePod(pod)
+				// The command context is cancelled already if the command got interrupted
+				deleteCtx, cancelDelete := cmd.RequestContextWithWait(api.DefaultGracePeriod)
+				defer cancelDelete()
+				_, err := client.DeletePod(deleteCtx, pod)
 				if err != nil {
 					log.Errorf("Error while deleting pod [%s]: %s", pod.Metadata.Name, err)
 				} else {
@@ -222,7 +229,9 @@ var upCommand = cli.Command{
 			term.Raw = true
 		} else {
 			sigc := cmd.ForwardAllSignals(func(signal syscall.Signal) error {
-				return client.Signal(attachContainerID, signal)
+				signalCtx, cancelSignal := cmd.RequestContext()
+				defer cancelSignal()
+				return client.Signal(signalCtx, attachContainerID, signal)
 			})
 			defer cmd.StopCatch(sigc)
 		}
@@ -232,7 +241,7 @@ var upCommand = cli.Command{
 		defer ui.Start()
 
 		return term.Safe(func() error {
-			return client.Attach(attachContainerID, api.NewAttachIO(term.In, term.Out, stderr), hooks...)
+			return client.Attach(ctx, attachContainerID, api.NewAttachIO(term.In, term.Out, stderr), hooks...)
 		})
 	},
 }
@@ -0,0 +1,67 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/ernoaapa/eliot/cmd"
+	"github.com/ernoaapa/eliot/pkg/api"
+	"github.com/ernoaapa/eliot/pkg/printers"
+	"github.com/urfave/cli"
+)
+
+var versionCommand = cli.Command{
+	Name:  "version",
+	Usage: "Print client and node versions",
+	UsageText: `eli version [options]
+
+	# Print client version and versions of all configured nodes
+	eli version
+
+	# Print client version and version of single node
+	eli version --endpoint 192.168.1.2
+`,
+	Action: func(clicontext *cli.Context) error {
+		cfg := cmd.GetConfigProvider(clicontext)
+
+		writer := printers.GetNewTabWriter(os.Stdout)
+		defer writer.Flush()
+
+		fmt.Fprintf(writer, "Client:\n")
+		fmt.Fprintf(writer, "  Version:\t%s\n", version)
+		fmt.Fprintf(writer, "  Commit:\t%s\n", commit)
+		fmt.Fprintf(writer, "  API version:\t%s\n", api.APIVersion)
+
+		for _, endpoint := range cfg.GetEndpoints() {
+			fmt.Fprintf(writer, "\nNode %s (%s):\n", endpoint.Name, endpoint.URL)
+
+			client, err := api.NewClient(cfg.GetNamespace(), endpoint, cmd.GetClientOpts(clicontext)...)
+			if err != nil {
+				return err
+			}
+			ctx, cancel := cmd.RequestContext()
+			info, err := client.GetVersion(ctx)
+			cancel()
+			client.Close()
+			if err != nil {
+				fmt.Fprintf(writer, "  Error:\t%s\n", err)
+				continue
+			}
+
+			fmt.Fprintf(writer, "  Version:\t%s\n", info.ServerVersion)
+			fmt.Fprintf(writer, "  API version:\t%s\n", valueOrUnknown(info.APIVersion))
+			fmt.Fprintf(writer, "  Runtime:\t%s\n", valueOrUnknown(info.RuntimeVersion))
+			if !info.IsCompatible() {
+				fmt.Fprintf(writer, "  Warning:\tNode API version %s is not compatible with client API version %s\n", info.APIVersion, api.APIVersion)
+			}
+		}
+		return nil
+	},
+}
+
+func valueOrUnknown(value string) string {
+	if value == "" {
+		return "unknown"
+	}
+	return value
+}
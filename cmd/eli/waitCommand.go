@@ -0,0 +1,67 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ernoaapa/eliot/cmd"
+	"github.com/urfave/cli"
+)
+
+var waitCommand = cli.Command{
+	Name:        "wait",
+	HelpName:    "wait",
+	Usage:       "Wait the pod container to exit and print its exit code",
+	Description: "You can use this command in scripts to block until the container exits, like docker wait",
+	UsageText: `eli wait [options] POD_NAME
+
+	 # Wait the pod container to exit
+	 eli wait my-pod
+
+	 # If pod contains multiple containers, you must define container name
+	 eli wait --container some-name my-pod
+
+	 # Give up if the container is still running after ten minutes
+	 eli wait --timeout 10m my-pod
+`,
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name:  "container, c",
+			Usage: "Target container in the pod",
+		},
+		cli.DurationFlag{
+			Name:  "timeout",
+			Usage: "Maximum time to wait, zero waits until the container exits",
+		},
+	},
+	Action: func(clicontext *cli.Context) error {
+		if clicontext.NArg() == 0 || clicontext.Args().First() == "" {
+			return fmt.Errorf("You must give Pod name as first argument")
+		}
+		podName := clicontext.Args().First()
+
+		config := cmd.GetConfigProvider(clicontext)
+		client := cmd.GetClient(config, cmd.GetClientOpts(clicontext)...)
+		defer client.Close()
+		ctx, cancel := cmd.StreamContext()
+		defer cancel()
+
+		containerID, err := client.ResolveContainerID(ctx, podName, clicontext.String("container"))
+		if err != nil {
+			return err
+		}
+
+		if timeout := clicontext.Duration("timeout"); timeout > 0 {
+			var cancelTimeout context.CancelFunc
+			ctx, cancelTimeout = context.WithTimeout(ctx, timeout)
+			defer cancelTimeout()
+		}
+
+		exitCode, err := client.WaitContainer(ctx, containerID)
+		if err != nil {
+			return err
+		}
+		fmt.Println(exitCode)
+		return nil
+	},
+}
@@ -17,6 +17,7 @@ import (
 	log "github.com/sirupsen/logrus"
 	"github.com/thejerf/suture"
 	"github.com/urfave/cli"
+	"google.golang.org/grpc"
 )
 
 // Get overrided at build time
@@ -59,7 +60,7 @@ func main() {
 		},
 		cli.BoolTFlag{
 			Name:   "lifecycle-controller",
-			Usage:  "Enable container lifecycle controller",
+			Usage:  "Enable container lifecycle controller, which restarts the containers and runs the readiness probes",
 			EnvVar: "ELIOT_LIFECYCLE_CONTROLLER",
 		},
 		cli.BoolTFlag{
@@ -73,6 +74,21 @@ func main() {
 			EnvVar: "ELIOT_GRPC_API_LISTEN",
 			Value:  "localhost:5000",
 		},
+		cli.StringFlag{
+			Name:   "grpc-api-tls-cert",
+			Usage:  "Server certificate file to enable TLS in GRPC API",
+			EnvVar: "ELIOT_GRPC_API_TLS_CERT",
+		},
+		cli.StringFlag{
+			Name:   "grpc-api-tls-key",
+			Usage:  "Server certificate key file to enable TLS in GRPC API",
+			EnvVar: "ELIOT_GRPC_API_TLS_KEY",
+		},
+		cli.StringFlag{
+			Name:   "grpc-api-tls-client-ca",
+			Usage:  "CA certificate file to verify client certificates. If set, clients must authenticate with certificate (mutual TLS)",
+			EnvVar: "ELIOT_GRPC_API_TLS_CLIENT_CA",
+		},
 		cli.BoolTFlag{
 			Name:   "discovery",
 			Usage:  "Enable discover GRPC server over zeroconf",
@@ -120,13 +136,23 @@ func main() {
 
 		if clicontext.Bool("grpc-api") {
 			log.Infoln("grpc-api enabled")
-			supervisor.Add(api.NewServer(grpcListen, client, resolver))
+			serverOpts := []grpc.ServerOption{}
+			if certFile := clicontext.String("grpc-api-tls-cert"); certFile != "" {
+				creds, err := api.NewServerTLSCredentials(certFile, clicontext.String("grpc-api-tls-key"), clicontext.String("grpc-api-tls-client-ca"))
+				if err != nil {
+					return err
+				}
+				log.Infoln("grpc-api TLS enabled")
+				serverOpts = append(serverOpts, grpc.Creds(creds))
+			}
+			supervisor.Add(api.NewServer(grpcListen, client, resolver, serverOpts...))
 			serviceCount++
 		}
 
 		if clicontext.Bool("lifecycle-controller") {
 			log.Infoln("lifecycle-controller enabled")
 			supervisor.Add(controller.NewLifecycle(client))
+			supervisor.Add(controller.NewReadiness(client))
 			serviceCount++
 		}
 
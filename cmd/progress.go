@@ -1,34 +1,160 @@
 package cmd
 
 import (
+	"fmt"
+
+	"golang.org/x/net/context"
+
+	"github.com/ernoaapa/eliot/pkg/api"
 	ui "github.com/ernoaapa/eliot/pkg/cmd/ui"
 	"github.com/ernoaapa/eliot/pkg/progress"
 )
 
+// download is single image download shown in own UI line
+type download struct {
+	// key identifies the line, label is how the download is described in the line
+	key   string
+	label string
+	fetch *progress.ImageFetch
+}
+
+// transfer is the UI line texts of image download or upload
+type transfer struct {
+	active, extracting, done, completed, cancelled string
+}
+
+var (
+	downloadTransfer = transfer{active: "Download", extracting: "Extract", done: "Downloaded", completed: "Completed", cancelled: "Cancelled download"}
+	pushTransfer     = transfer{active: "Push", done: "Pushed", completed: "Completed", cancelled: "Cancelled push"}
+)
+
 // ShowDownloadProgress prints UI "downloading" lines and updates until
-// the progress channel closes
-func ShowDownloadProgress(progressc <-chan []*progress.ImageFetch) {
-	lines := map[string]ui.Line{}
-	for fetches := range progressc {
+// the progress channel closes. The returned channel closes when the final lines
+// are rendered, wait it before printing anything else.
+// If the context gets cancelled, the unfinished downloads are shown as cancelled.
+func ShowDownloadProgress(ctx context.Context, progressc <-chan []*progress.ImageFetch) <-chan struct{} {
+	return goShowDownloads(ctx, downloadTransfer, func() ([]download, bool) {
+		fetches, ok := <-progressc
+		downloads := []download{}
 		for _, fetch := range fetches {
-			if _, ok := lines[fetch.Image]; !ok {
-				lines[fetch.Image] = ui.NewLine().Loadingf("Download %s", fetch.Image)
+			downloads = append(downloads, download{key: fetch.Image, label: fetch.Image, fetch: fetch})
+		}
+		return downloads, ok
+	})
+}
+
+// ShowPodsDownloadProgress is like ShowDownloadProgress, but prints single line
+// for each pod image, until the progress channel closes
+func ShowPodsDownloadProgress(ctx context.Context, progressc <-chan api.PodsImageFetchProgress) <-chan struct{} {
+	return goShowDownloads(ctx, downloadTransfer, func() ([]download, bool) {
+		fetches, ok := <-progressc
+		downloads := []download{}
+		for _, fetch := range fetches {
+			downloads = append(downloads, download{
+				key:   fmt.Sprintf("%s %s", fetch.Pod, fetch.Image),
+				label: fmt.Sprintf("%s (%s)", fetch.Image, fetch.Pod),
+				fetch: fetch.ImageFetch,
+			})
+		}
+		return downloads, ok
+	})
+}
+
+// ShowPushProgress is like ShowDownloadProgress, but for the PushImage upload progress.
+// Return the push error from the last progress value, if the push failed.
+// Returns when the progress channel closes.
+func ShowPushProgress(ctx context.Context, progressc <-chan api.ImagePushProgress) (err error) {
+	showDownloads(ctx, pushTransfer, func() ([]download, bool) {
+		push, ok := <-progressc
+		if push.Err != nil {
+			err = push.Err
+		}
+		if push.ImageFetch == nil {
+			return nil, ok
+		}
+		return []download{{key: push.Image, label: push.Image, fetch: push.ImageFetch}}, ok
+	})
+	return err
+}
+
+// goShowDownloads runs showDownloads in background, the returned channel closes when it returns
+func goShowDownloads(ctx context.Context, t transfer, next func() ([]download, bool)) <-chan struct{} {
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		showDownloads(ctx, t, next)
+	}()
+	return done
+}
+
+// showDownloads updates the UI lines with the downloads returned by next, until next returns false.
+// Once the context is cancelled, the lines which are not failed or done get marked cancelled and
+// don't get updated anymore, next is still called so that the sender doesn't block.
+func showDownloads(ctx context.Context, t transfer, next func() ([]download, bool)) {
+	lines := map[string]ui.Line{}
+	labels := map[string]string{}
+	failed := map[string]bool{}
+	done := map[string]bool{}
+	cancelled := false
+	cancel := func() {
+		cancelled = true
+		for key, line := range lines {
+			if !failed[key] && !done[key] {
+				line.Warnf("%s %s", t.cancelled, labels[key])
+			}
+		}
+		ui.Update()
+	}
+
+	for downloads, ok := next(); ok; downloads, ok = next() {
+		if cancelled {
+			continue
+		}
+		if ctx.Err() != nil {
+			cancel()
+			continue
+		}
+		for _, d := range downloads {
+			if _, ok := lines[d.key]; !ok {
+				lines[d.key] = ui.NewLine().Loadingf("%s %s", t.active, d.label)
+				labels[d.key] = d.label
 			}
 
-			if fetch.IsDone() {
-				if fetch.Failed {
-					lines[fetch.Image].Errorf("Failed %s", fetch.Image)
-				} else {
-					lines[fetch.Image].Donef("Downloaded %s", fetch.Image)
-				}
+			if d.fetch.Failed {
+				failed[d.key] = true
+				lines[d.key].Errorf("Failed %s", d.label)
+			} else if isExtracting(d.fetch) {
+				lines[d.key].Loadingf("%s %s", t.extracting, d.label)
+			} else if d.fetch.IsDone() {
+				done[d.key] = true
+				lines[d.key].Donef("%s %s", t.done, d.label)
 			} else {
-				current, total := fetch.GetProgress()
-				lines[fetch.Image].WithProgress(current, total)
+				current, total := d.fetch.GetProgress()
+				lines[d.key].WithProgress(current, total)
 			}
 		}
 	}
 
-	for image, line := range lines {
-		line.Donef("Completed %s", image)
+	if ctx.Err() != nil {
+		if !cancelled {
+			cancel()
+		}
+		return
+	}
+	for key, line := range lines {
+		if !failed[key] {
+			line.Donef("%s %s", t.completed, labels[key])
+		}
+	}
+	ui.Update()
+}
+
+// isExtracting return true if some of the image layers is downloaded and being extracted
+func isExtracting(fetch *progress.ImageFetch) bool {
+	for _, layer := range fetch.Snapshot() {
+		if layer.Status == progress.StatusExtracting {
+			return true
+		}
 	}
+	return false
 }
@@ -26,6 +26,7 @@ import (
 	pods "github.com/ernoaapa/eliot/pkg/api/services/pods/v1"
 	"github.com/ernoaapa/eliot/pkg/config"
 	"github.com/ernoaapa/eliot/pkg/fs"
+	"github.com/ernoaapa/eliot/pkg/manifest"
 	"github.com/ernoaapa/eliot/pkg/runtime"
 	"github.com/urfave/cli"
 )
@@ -33,9 +34,16 @@ import (
 const (
 	outputHuman = "human"
 	outputYaml  = "yaml"
+
+	connectTimeout = 10 * time.Second
+	dialTimeout    = 5 * time.Second
+	requestTimeout = 30 * time.Second
 )
 
 var (
+	// Version is the CLI version sent to the node in the client user agent, set by the main package
+	Version = "master"
+
 	// GlobalFlags are flags what all commands have common
 	GlobalFlags = []cli.Flag{
 		cli.BoolFlag{
@@ -72,8 +80,31 @@ func GlobalBefore(context *cli.Context) error {
 	return nil
 }
 
+// GetClientOpts return API client options resolved from CLI parameters
+func GetClientOpts(clicontext *cli.Context) []api.ClientOpts {
+	var (
+		certFile = clicontext.GlobalString("tls-cert")
+		keyFile  = clicontext.GlobalString("tls-key")
+		caFile   = clicontext.GlobalString("tls-ca")
+	)
+
+	opts := []api.ClientOpts{
+		api.WithDialTimeout(dialTimeout),
+		api.WithLogger(logrus.NewEntry(logrus.StandardLogger())),
+		api.WithUserAgent(api.UserAgent(Version)),
+	}
+	// The terminal UI is hidden when the output is not a terminal, print the image pull progress as plain lines instead
+	if cmd.IsPipingOut() && !clicontext.GlobalBool("quiet") && clicontext.GlobalString("output") == outputHuman {
+		opts = append(opts, api.WithProgressWriter(os.Stdout))
+	}
+	if clicontext.GlobalBool("tls") || certFile != "" || keyFile != "" || caFile != "" {
+		return append(opts, api.WithTLS(certFile, keyFile, caFile))
+	}
+	return append(opts, api.WithInsecure())
+}
+
 // GetClient creates new cloud API client
-func GetClient(config *config.Provider) *api.Client {
+func GetClient(config *config.Provider, opts ...api.ClientOpts) *api.Client {
 	uiline := ui.NewLine()
 
 	endpoints := config.GetEndpoints()
@@ -83,13 +114,24 @@ func GetClient(config *config.Provider) *api.Client {
 		return nil
 	case 1:
 		uiline.Loadingf("Connecting to %s (%s)", endpoints[0].Name, endpoints[0].URL)
-		client := api.NewClient(config.GetNamespace(), endpoints[0])
-		info, err := client.GetInfo()
+		client, err := api.NewClient(config.GetNamespace(), endpoints[0], opts...)
+		if err != nil {
+			uiline.Fatalf("Failed to create client for %s (%s): %s", endpoints[0].Name, endpoints[0].URL, err)
+		}
+		ctx, cancel := cancelOnInterrupt(context.WithTimeout(context.Background(), connectTimeout))
+		defer cancel()
+		if err := client.Ping(ctx); err != nil {
+			uiline.Fatalf("Cannot connect to %s (%s): %s", endpoints[0].Name, endpoints[0].URL, err)
+		}
+		info, err := client.GetInfo(ctx)
 		if err != nil {
 			logrus.Debugf("Connection failure: %s", err)
 			uiline.Fatalf("Failed connect to %s (%s)", endpoints[0].Name, endpoints[0].URL)
 		}
 		uiline.Donef("Connected to %s (%s)", info.Hostname, endpoints[0].URL)
+		if version := api.MapVersionInfo(info); !version.IsCompatible() {
+			ui.NewLine().Warnf("Node API version %s is not compatible with client API version %s, some commands may fail", version.APIVersion, api.APIVersion)
+		}
 		return client
 	default:
 		uiline.Fatalf("%d node found. You must give target node. E.g. --endpoint=192.168.1.2", len(endpoints))
@@ -97,6 +139,42 @@ func GetClient(config *config.Provider) *api.Client {
 	}
 }
 
+// RequestContext returns context for commands which make only short requests, e.g. get pods.
+// The context get cancelled after the request timeout or when the command gets interrupted (^C).
+func RequestContext() (context.Context, context.CancelFunc) {
+	return RequestContextWithWait(0)
+}
+
+// RequestContextWithWait is like RequestContext but gives the request extra time, e.g. when delete waits the containers to stop
+func RequestContextWithWait(wait time.Duration) (context.Context, context.CancelFunc) {
+	return cancelOnInterrupt(context.WithTimeout(context.Background(), requestTimeout+wait))
+}
+
+// StreamContext returns context for commands which stream until done, e.g. follow logs or attach to container.
+// The context doesn't have deadline, it get cancelled only when the command gets interrupted (^C).
+func StreamContext() (context.Context, context.CancelFunc) {
+	return cancelOnInterrupt(context.WithCancel(context.Background()))
+}
+
+// cancelOnInterrupt cancels the context when receives interrupt or terminate signal.
+// The returned cancel function must be called to stop listening the signals.
+func cancelOnInterrupt(ctx context.Context, cancel context.CancelFunc) (context.Context, context.CancelFunc) {
+	interrupt := make(chan os.Signal, 1)
+	signal.Notify(interrupt, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		select {
+		case <-interrupt:
+			logrus.Debugf("Interrupted, cancel the command")
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+	return ctx, func() {
+		signal.Stop(interrupt)
+		cancel()
+	}
+}
+
 // GetConfig parse yaml config and return the file representation
 // In normal cases, you should use GetConfigProvider
 func GetConfig(clicontext *cli.Context) *config.Config {
@@ -189,6 +267,43 @@ func GetLabels(clicontext *cli.Context) map[string]string {
 	return labels
 }
 
+// GetManifestOpts return the manifest loading options from --expand-env and --var CLI parameters
+func GetManifestOpts(clicontext *cli.Context) []manifest.LoadOpts {
+	opts := []manifest.LoadOpts{}
+	if clicontext.Bool("expand-env") {
+		opts = append(opts, manifest.WithEnvironmentVars())
+	}
+
+	vars := map[string]string{}
+	for _, param := range clicontext.StringSlice("var") {
+		pair := strings.SplitN(param, "=", 2)
+		if len(pair) != 2 || pair[0] == "" {
+			ui.NewLine().Fatalf("Invalid --var parameter [%s]. It must be in NAME=VALUE format. E.g. '--var VERSION=1.2.3'", param)
+		}
+		vars[pair[0]] = pair[1]
+	}
+	if len(vars) > 0 {
+		opts = append(opts, manifest.WithVars(vars))
+	}
+	return opts
+}
+
+// GetTemplateValues return the manifest template values from --set CLI parameters, nil if there is none
+func GetTemplateValues(clicontext *cli.Context) map[string]interface{} {
+	var values map[string]interface{}
+	for _, param := range clicontext.StringSlice("set") {
+		pair := strings.SplitN(param, "=", 2)
+		if len(pair) != 2 || pair[0] == "" {
+			ui.NewLine().Fatalf("Invalid --set parameter [%s]. It must be in KEY=VALUE format. E.g. '--set device=pi42'", param)
+		}
+		if values == nil {
+			values = map[string]interface{}{}
+		}
+		values[pair[0]] = pair[1]
+	}
+	return values
+}
+
 // GetRuntimeClient initialises new runtime client from CLI parameters
 func GetRuntimeClient(clicontext *cli.Context, hostname string) runtime.Client {
 	return runtime.NewContainerdClient(
@@ -0,0 +1,97 @@
+package api
+
+import (
+	"errors"
+
+	pods "github.com/ernoaapa/eliot/pkg/api/services/pods/v1"
+	"github.com/golang/protobuf/proto"
+	"golang.org/x/net/context"
+)
+
+// applyMaxAttempts is how many times ApplyPod starts over when the pod gets changed by someone else at the same time
+const applyMaxAttempts = 3
+
+// ApplyAction tells what ApplyPod did to get the pod to the desired state
+type ApplyAction string
+
+// ApplyPod actions
+const (
+	ApplyCreated   ApplyAction = "created"
+	ApplyUpdated   ApplyAction = "updated"
+	ApplyUnchanged ApplyAction = "unchanged"
+)
+
+// ApplyResult is the result of ApplyPod
+type ApplyResult struct {
+	Action ApplyAction
+	// Pod is the pod in the node after the apply, nil in dry run if the pod doesn't exist
+	Pod *pods.Pod
+	// Diff is the difference between the pod before the apply and the desired pod
+	Diff *PodDiff
+}
+
+// ApplyPod makes the pod in the node match the desired pod. It creates and starts the pod if it doesn't exist,
+// updates it if it differs from the desired pod and does nothing if it's already up to date.
+// The update is done only if the pod hasn't changed since it was compared. If someone else creates or updates
+// the pod at the same time, ApplyPod compares the pod again and retries, so that neither change gets lost silently.
+// In dry run mode (WithDryRun), nothing is changed and the result tells what would be done.
+func (c *Client) ApplyPod(ctx context.Context, desired *pods.Pod) (*ApplyResult, error) {
+	if err := ValidatePod(desired); err != nil {
+		return nil, err
+	}
+
+	var err error
+	for attempt := 1; attempt <= applyMaxAttempts; attempt++ {
+		var result *ApplyResult
+		result, err = c.applyPod(ctx, desired)
+		if err == nil {
+			return result, nil
+		}
+		if !errors.Is(err, ErrPodVersionConflict) && !errors.Is(err, ErrAlreadyExists) {
+			return nil, err
+		}
+		c.logger.Debugf("Pod [%s] changed while applying (attempt %d/%d): %s", desired.GetMetadata().GetName(), attempt, applyMaxAttempts, err)
+	}
+	return nil, err
+}
+
+func (c *Client) applyPod(ctx context.Context, desired *pods.Pod) (*ApplyResult, error) {
+	name := desired.GetMetadata().GetName()
+	current, err := c.GetPod(ctx, name)
+	if err != nil {
+		if !errors.Is(err, ErrNotFound) {
+			return nil, err
+		}
+		current = nil
+	}
+
+	diff := diffPod(current, desired)
+	switch {
+	case diff.IsEmpty():
+		return &ApplyResult{Action: ApplyUnchanged, Pod: current, Diff: diff}, nil
+	case c.dryRun && diff.Create:
+		return &ApplyResult{Action: ApplyCreated, Diff: diff}, nil
+	case c.dryRun:
+		return &ApplyResult{Action: ApplyUpdated, Pod: current, Diff: diff}, nil
+	}
+
+	// Don't modify the caller's pod, CreatePod and UpdatePod update the metadata
+	pod := proto.Clone(desired).(*pods.Pod)
+	if diff.Create {
+		if err := c.CreatePod(ctx, nil, pod); err != nil {
+			return nil, err
+		}
+		started, err := c.StartPod(ctx, name)
+		if err != nil {
+			return nil, err
+		}
+		return &ApplyResult{Action: ApplyCreated, Pod: started, Diff: diff}, nil
+	}
+
+	pod.Metadata.ResourceVersion = current.GetMetadata().GetResourceVersion()
+	resp, err := c.UpdatePod(ctx, pod)
+	if err != nil {
+		return nil, err
+	}
+	return &ApplyResult{Action: ApplyUpdated, Pod: resp.GetPod(), Diff: diff}, nil
+}
@@ -0,0 +1,232 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"sync"
+	"testing"
+
+	"github.com/ernoaapa/eliot/pkg/api/core"
+	"github.com/ernoaapa/eliot/pkg/api/mapping"
+	containers "github.com/ernoaapa/eliot/pkg/api/services/containers/v1"
+	pods "github.com/ernoaapa/eliot/pkg/api/services/pods/v1"
+	"github.com/ernoaapa/eliot/pkg/config"
+	"github.com/ernoaapa/eliot/pkg/model"
+	"github.com/ernoaapa/eliot/pkg/runtime"
+	"github.com/golang/protobuf/proto"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// applyPodsServer keeps single pod in memory and bumps its version on each change
+type applyPodsServer struct {
+	pods.PodsServer
+	mu      sync.Mutex
+	pod     *pods.Pod
+	version int
+	calls   []string
+	// conflicts is how many updates fail as if someone else updated the pod just before
+	conflicts int
+}
+
+func (s *applyPodsServer) call(name string) {
+	s.calls = append(s.calls, name)
+}
+
+func (s *applyPodsServer) List(ctx context.Context, req *pods.ListPodsRequest) (*pods.ListPodsResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.call("list")
+	if s.pod == nil {
+		return &pods.ListPodsResponse{}, nil
+	}
+	return &pods.ListPodsResponse{Pods: []*pods.Pod{proto.Clone(s.pod).(*pods.Pod)}}, nil
+}
+
+func (s *applyPodsServer) Create(req *pods.CreatePodRequest, server pods.Pods_CreateServer) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.call("create")
+	s.pod = proto.Clone(req.Pod).(*pods.Pod)
+	s.version++
+	s.pod.Metadata.ResourceVersion = fmt.Sprintf("v%d", s.version)
+	return nil
+}
+
+func (s *applyPodsServer) Start(ctx context.Context, req *pods.StartPodRequest) (*pods.StartPodResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.call("start")
+	return &pods.StartPodResponse{Pod: s.pod}, nil
+}
+
+func (s *applyPodsServer) Update(ctx context.Context, req *pods.UpdatePodRequest) (*pods.UpdatePodResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.call("update")
+	if s.conflicts > 0 {
+		s.conflicts--
+		s.version++
+		s.pod.Metadata.ResourceVersion = fmt.Sprintf("v%d", s.version)
+	}
+	if req.Pod.Metadata.ResourceVersion != s.pod.Metadata.ResourceVersion {
+		return nil, status.Errorf(codes.Aborted, "Pod changed since version [%s]", req.Pod.Metadata.ResourceVersion)
+	}
+	s.pod = proto.Clone(req.Pod).(*pods.Pod)
+	s.version++
+	s.pod.Metadata.ResourceVersion = fmt.Sprintf("v%d", s.version)
+	return &pods.UpdatePodResponse{Pod: s.pod}, nil
+}
+
+func newApplyTestClient(t *testing.T, fake *applyPodsServer) (*Client, func()) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+
+	server := grpc.NewServer()
+	pods.RegisterPodsServer(server, fake)
+	go server.Serve(listener)
+
+	client, err := NewClient("eliot", config.Endpoint{URL: listener.Addr().String()}, WithInsecure())
+	assert.NoError(t, err)
+	return client, func() {
+		client.Close()
+		server.Stop()
+	}
+}
+
+func newApplyTestPod(image string) *pods.Pod {
+	return &pods.Pod{
+		Metadata: &core.ResourceMetadata{Name: "foo", Namespace: "eliot"},
+		Spec:     &pods.PodSpec{Containers: []*containers.Container{{Name: "foo", Image: image}}},
+	}
+}
+
+func TestApplyPodCreatesAndUpdates(t *testing.T) {
+	fake := &applyPodsServer{}
+	client, stop := newApplyTestClient(t, fake)
+	defer stop()
+
+	result, err := client.ApplyPod(context.Background(), newApplyTestPod("docker.io/library/alpine:3.7"))
+	assert.NoError(t, err)
+	assert.Equal(t, ApplyCreated, result.Action)
+	assert.Equal(t, []string{"list", "create", "start"}, fake.calls)
+
+	fake.calls = nil
+	result, err = client.ApplyPod(context.Background(), newApplyTestPod("docker.io/library/alpine:3.7"))
+	assert.NoError(t, err)
+	assert.Equal(t, ApplyUnchanged, result.Action)
+	assert.Equal(t, []string{"list"}, fake.calls)
+
+	fake.calls = nil
+	desired := newApplyTestPod("docker.io/library/alpine:3.8")
+	result, err = client.ApplyPod(context.Background(), desired)
+	assert.NoError(t, err)
+	assert.Equal(t, ApplyUpdated, result.Action)
+	assert.Equal(t, []string{"list", "update"}, fake.calls)
+	assert.Equal(t, "docker.io/library/alpine:3.8", result.Pod.Spec.Containers[0].Image)
+	assert.Empty(t, desired.Metadata.ResourceVersion, "should not modify the desired pod")
+}
+
+func TestApplyPodRetriesOnConflict(t *testing.T) {
+	fake := &applyPodsServer{pod: newApplyTestPod("docker.io/library/alpine:3.7"), conflicts: 1}
+	fake.pod.Metadata.ResourceVersion = "v0"
+	client, stop := newApplyTestClient(t, fake)
+	defer stop()
+
+	result, err := client.ApplyPod(context.Background(), newApplyTestPod("docker.io/library/alpine:3.8"))
+	assert.NoError(t, err)
+	assert.Equal(t, ApplyUpdated, result.Action)
+	assert.Equal(t, []string{"list", "update", "list", "update"}, fake.calls, "should compare again after conflict")
+}
+
+func TestApplyPodGivesUpAfterConflicts(t *testing.T) {
+	fake := &applyPodsServer{pod: newApplyTestPod("docker.io/library/alpine:3.7"), conflicts: applyMaxAttempts}
+	fake.pod.Metadata.ResourceVersion = "v0"
+	client, stop := newApplyTestClient(t, fake)
+	defer stop()
+
+	_, err := client.ApplyPod(context.Background(), newApplyTestPod("docker.io/library/alpine:3.8"))
+	assert.Error(t, err)
+	assert.True(t, errors.Is(err, ErrPodVersionConflict), "should return the conflict but got %v", err)
+}
+
+func TestServerUpdateRejectsStaleVersion(t *testing.T) {
+	fake := &fakeRuntime{created: map[string]bool{"foo": true}}
+	server := NewServer("", fake, nil)
+
+	pod := newApplyTestPod("docker.io/library/alpine:3.8")
+	pod.Metadata.ResourceVersion = "stale"
+	_, err := server.Update(context.Background(), &pods.UpdatePodRequest{Pod: pod})
+	assert.Equal(t, codes.Aborted, status.Code(err))
+	assert.Equal(t, 0, fake.pulls, "should not change anything")
+}
+
+// podRuntime keeps single running pod in memory
+type podRuntime struct {
+	runtime.Client
+	mu       sync.Mutex
+	pod      model.Pod
+	relabels int
+}
+
+func (r *podRuntime) GetPod(namespace, name string) (model.Pod, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.pod.Metadata.Name != name {
+		return model.Pod{}, runtime.ErrWithMessagef(runtime.ErrNotFound, "Pod [%s] not found", name)
+	}
+	return r.pod, nil
+}
+
+func (r *podRuntime) GetPods(namespace string) ([]model.Pod, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return []model.Pod{r.pod}, nil
+}
+
+func (r *podRuntime) SetPodLabels(namespace, name string, labels map[string]string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.relabels++
+	r.pod.Metadata.Labels = labels
+	return nil
+}
+
+func TestApplyPodUpdatesOnlyLabels(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+
+	desired := newApplyTestPod("docker.io/library/alpine:3.7")
+	pod := mapping.MapPodToInternalModel(desired)
+	for _, container := range pod.Spec.Containers {
+		pod.Status.ContainerStatuses = append(pod.Status.ContainerStatuses, model.ContainerStatus{
+			Name:        container.Name,
+			ContainerID: "foo-1",
+			SpecHash:    model.GetContainerSpecHash(pod.Spec, container),
+		})
+	}
+	fake := &podRuntime{pod: pod}
+	server := NewServer(listener.Addr().String(), fake, nil)
+	go server.grpc.Serve(listener)
+	defer server.grpc.Stop()
+
+	client, err := NewClient("eliot", config.Endpoint{URL: listener.Addr().String()}, WithInsecure())
+	assert.NoError(t, err)
+	defer client.Close()
+
+	desired.Metadata.Labels = map[string]string{"app": "foo"}
+	result, err := client.ApplyPod(context.Background(), desired)
+	assert.NoError(t, err)
+	assert.Equal(t, ApplyUpdated, result.Action)
+	assert.Equal(t, map[string]string{"app": "foo"}, result.Pod.Metadata.Labels)
+	assert.Equal(t, 1, fake.relabels)
+
+	result, err = client.ApplyPod(context.Background(), desired)
+	assert.NoError(t, err)
+	assert.Equal(t, ApplyUnchanged, result.Action, "should not update again after the labels got applied")
+	assert.Equal(t, 1, fake.relabels)
+}
@@ -0,0 +1,362 @@
+package api
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"io/ioutil"
+	"net"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	containers "github.com/ernoaapa/eliot/pkg/api/services/containers/v1"
+	pods "github.com/ernoaapa/eliot/pkg/api/services/pods/v1"
+	"github.com/ernoaapa/eliot/pkg/api/stream"
+	"github.com/ernoaapa/eliot/pkg/config"
+)
+
+type fakeContainersServer struct {
+	containers.ContainersServer
+	attach  func(server containers.Containers_AttachServer) error
+	exec    func(server containers.Containers_ExecServer) error
+	stats   func(req *containers.StatsRequest) (*containers.StatsResponse, error)
+	logs    func(req *containers.LogsRequest, server containers.Containers_LogsServer) error
+	watch   func(req *containers.WatchFileRequest, server containers.Containers_WatchFileServer) error
+	status  func(req *containers.StatusRequest) (*containers.StatusResponse, error)
+	inspect func(req *containers.InspectRequest) (*containers.InspectResponse, error)
+	wait    func(req *containers.WaitRequest) (*containers.WaitResponse, error)
+}
+
+func (s *fakeContainersServer) Attach(server containers.Containers_AttachServer) error {
+	return s.attach(server)
+}
+
+func (s *fakeContainersServer) Exec(server containers.Containers_ExecServer) error {
+	return s.exec(server)
+}
+
+func (s *fakeContainersServer) Stats(ctx context.Context, req *containers.StatsRequest) (*containers.StatsResponse, error) {
+	if s.stats == nil {
+		return &containers.StatsResponse{Stats: &containers.ContainerStats{}}, nil
+	}
+	return s.stats(req)
+}
+
+func (s *fakeContainersServer) Logs(req *containers.LogsRequest, server containers.Containers_LogsServer) error {
+	return s.logs(req, server)
+}
+
+func (s *fakeContainersServer) Status(ctx context.Context, req *containers.StatusRequest) (*containers.StatusResponse, error) {
+	return s.status(req)
+}
+
+func (s *fakeContainersServer) Inspect(ctx context.Context, req *containers.InspectRequest) (*containers.InspectResponse, error) {
+	return s.inspect(req)
+}
+
+func (s *fakeContainersServer) Wait(ctx context.Context, req *containers.WaitRequest) (*containers.WaitResponse, error) {
+	return s.wait(req)
+}
+
+func (s *fakeContainersServer) WatchFile(req *containers.WatchFileRequest, server containers.Containers_WatchFileServer) error {
+	return s.watch(req, server)
+}
+
+func startFakeContainersServer(t *testing.T, attach func(server containers.Containers_AttachServer) error) (*Client, func()) {
+	return startFakeServer(t, nil, &fakeContainersServer{attach: attach})
+}
+
+// startFakeServer serves the fake pods and containers servers and return client connected to them.
+// Nil fake is not registered, so its calls fail with Unimplemented.
+func startFakeServer(t *testing.T, podsServer pods.PodsServer, fake *fakeContainersServer, opts ...ClientOpts) (*Client, func()) {
+	return startTestServer(t, func(address string) *grpc.Server {
+		server := grpc.NewServer()
+		if podsServer != nil {
+			pods.RegisterPodsServer(server, podsServer)
+		}
+		if fake != nil {
+			containers.RegisterContainersServer(server, fake)
+		}
+		return server
+	}, opts...)
+}
+
+// startTestServer starts the gRPC server what newServer creates for the listen address, and return client
+// connected to it with WithInsecure and the opts. The returned function closes the client and stops the server.
+func startTestServer(t *testing.T, newServer func(address string) *grpc.Server, opts ...ClientOpts) (*Client, func()) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+
+	server := newServer(listener.Addr().String())
+	go server.Serve(listener)
+
+	client, err := NewClient("eliot", config.Endpoint{URL: listener.Addr().String()}, append([]ClientOpts{WithInsecure()}, opts...)...)
+	assert.NoError(t, err)
+
+	return client, func() {
+		client.Close()
+		server.Stop()
+	}
+}
+
+func TestAttachReturnsWhenProcessExitsWhileStdinBlocks(t *testing.T) {
+	client, stop := startFakeContainersServer(t, func(server containers.Containers_AttachServer) error {
+		return server.Send(&containers.StdoutStreamResponse{Output: []byte("hello")})
+	})
+	defer stop()
+
+	stdin, _ := io.Pipe() // never written, Read blocks forever
+	stdout := &bytes.Buffer{}
+
+	errc := make(chan error)
+	go func() {
+		errc <- client.Attach(context.Background(), "foo", NewAttachIO(stdin, stdout, stdout))
+	}()
+
+	select {
+	case err := <-errc:
+		assert.NoError(t, err)
+		assert.Equal(t, "hello", stdout.String())
+	case <-time.After(5 * time.Second):
+		t.Fatal("Attach didn't return after the process exit")
+	}
+}
+
+func TestAttachClosesStdinWhenInputEnds(t *testing.T) {
+	client, stop := startFakeContainersServer(t, func(server containers.Containers_AttachServer) error {
+		// Like `wc -c`, output only after the end of input
+		input, err := ioutil.ReadAll(stream.NewReader(server))
+		if err != nil {
+			return err
+		}
+		return server.Send(&containers.StdoutStreamResponse{Output: []byte(strconv.Itoa(len(input)))})
+	})
+	defer stop()
+
+	stdout := &bytes.Buffer{}
+	attachIO := NewAttachIO(bytes.NewReader(bytes.Repeat([]byte("x"), 100000)), stdout, stdout)
+	attachIO.StdinBufferSize = 1000
+
+	errc := make(chan error)
+	go func() {
+		errc <- client.Attach(context.Background(), "foo", attachIO)
+	}()
+
+	select {
+	case err := <-errc:
+		assert.NoError(t, err)
+		assert.Equal(t, "100000", stdout.String())
+	case <-time.After(5 * time.Second):
+		t.Fatal("Server didn't get end of input")
+	}
+}
+
+func TestAttachLineBuffered(t *testing.T) {
+	client, stop := startFakeContainersServer(t, func(server containers.Containers_AttachServer) error {
+		for _, resp := range []*containers.StdoutStreamResponse{
+			{Output: []byte("out")},
+			{Output: []byte("err\n"), Stderr: true},
+			{Output: []byte("put\n")},
+			{Output: []byte("last")},
+		} {
+			if err := server.Send(resp); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	defer stop()
+
+	output := &bytes.Buffer{}
+	attachIO := NewAttachIO(nil, output, output)
+	attachIO.LineBuffered = true
+
+	err := client.Attach(context.Background(), "foo", attachIO)
+	assert.NoError(t, err)
+	assert.Equal(t, "err\noutput\nlast", output.String(), "should write complete lines and flush the last line")
+}
+
+func TestAttachReturnsWhenContextCancelled(t *testing.T) {
+	client, stop := startFakeContainersServer(t, func(server containers.Containers_AttachServer) error {
+		<-server.Context().Done()
+		return nil
+	})
+	defer stop()
+
+	stdin, _ := io.Pipe()
+	ctx, cancel := context.WithCancel(context.Background())
+
+	errc := make(chan error)
+	go func() {
+		errc <- client.Attach(ctx, "foo", NewAttachIO(stdin, &bytes.Buffer{}, &bytes.Buffer{}))
+	}()
+	cancel()
+
+	select {
+	case err := <-errc:
+		assert.Error(t, err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("Attach didn't return after context cancel")
+	}
+}
+
+func TestAttachIdleTimeout(t *testing.T) {
+	client, stop := startFakeContainersServer(t, func(server containers.Containers_AttachServer) error {
+		if err := server.Send(&containers.StdoutStreamResponse{Output: []byte("hello")}); err != nil {
+			return err
+		}
+		<-server.Context().Done()
+		return nil
+	})
+	defer stop()
+
+	stdin, _ := io.Pipe()
+	attachIO := NewAttachIO(stdin, &bytes.Buffer{}, &bytes.Buffer{})
+	attachIO.IdleTimeout = 200 * time.Millisecond
+
+	errc := make(chan error)
+	go func() {
+		errc <- client.Attach(context.Background(), "foo", attachIO)
+	}()
+
+	select {
+	case err := <-errc:
+		assert.True(t, errors.Is(err, ErrAttachIdleTimeout))
+		assert.True(t, errors.Is(err, ErrDeadlineExceeded))
+	case <-time.After(5 * time.Second):
+		t.Fatal("Attach didn't return after the idle timeout")
+	}
+}
+
+func TestAttachReadOnly(t *testing.T) {
+	client, stop := startFakeContainersServer(t, func(server containers.Containers_AttachServer) error {
+		md, _ := metadata.FromIncomingContext(server.Context())
+		assert.Equal(t, "false", getMetadataValue(md, "stdin"), "should tell the server not to attach stdin")
+		return server.Send(&containers.StdoutStreamResponse{Output: []byte("hello")})
+	})
+	defer stop()
+
+	stdout := &bytes.Buffer{}
+	assert.NoError(t, client.AttachReadOnly(context.Background(), "foo", stdout, stdout))
+	assert.Equal(t, "hello", stdout.String())
+}
+
+func TestAttachStderrOnly(t *testing.T) {
+	client, stop := startFakeContainersServer(t, func(server containers.Containers_AttachServer) error {
+		md, _ := metadata.FromIncomingContext(server.Context())
+		assert.Equal(t, "stderr", getMetadataValue(md, "streams"), "should tell the server to send only stderr")
+		// Server which doesn't support the selection sends both streams
+		if err := server.Send(&containers.StdoutStreamResponse{Output: []byte("out")}); err != nil {
+			return err
+		}
+		return server.Send(&containers.StdoutStreamResponse{Output: []byte("err"), Stderr: true})
+	})
+	defer stop()
+
+	stdout, stderr := &bytes.Buffer{}, &bytes.Buffer{}
+	attachIO := NewAttachIO(nil, stdout, stderr)
+	attachIO.Streams = AttachStderrOnly
+	assert.NoError(t, client.Attach(context.Background(), "foo", attachIO))
+	assert.Empty(t, stdout.String(), "should not write stdout")
+	assert.Equal(t, "err", stderr.String())
+
+	attachIO.Streams = "stdin"
+	assert.True(t, errors.Is(client.Attach(context.Background(), "foo", attachIO), ErrInvalidArgument))
+}
+
+func TestAttachStatsCountsPartialTransferOnError(t *testing.T) {
+	client, stop := startFakeContainersServer(t, func(server containers.Containers_AttachServer) error {
+		req, err := server.Recv()
+		if err != nil {
+			return err
+		}
+		assert.Equal(t, "input", string(req.Input))
+		if err := server.Send(&containers.StdoutStreamResponse{Output: []byte("hello")}); err != nil {
+			return err
+		}
+		if err := server.Send(&containers.StdoutStreamResponse{Output: []byte("oops"), Stderr: true}); err != nil {
+			return err
+		}
+		return status.Error(codes.Internal, "container process failed")
+	})
+	defer stop()
+
+	stdin, stdinWriter := io.Pipe()
+	go stdinWriter.Write([]byte("input"))
+
+	attachIO := NewAttachIO(stdin, &bytes.Buffer{}, &bytes.Buffer{})
+	attachIO.Stats = &AttachStats{}
+
+	err := client.Attach(context.Background(), "foo", attachIO)
+	assert.Error(t, err)
+	assert.Equal(t, int64(5), attachIO.Stats.StdinBytes())
+	assert.Equal(t, int64(5), attachIO.Stats.StdoutBytes())
+	assert.Equal(t, int64(4), attachIO.Stats.StderrBytes())
+}
+
+func TestAttachReturnsConnectionLostWhenServerGoesAway(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+
+	sent := make(chan struct{})
+	server := grpc.NewServer()
+	containers.RegisterContainersServer(server, &fakeContainersServer{attach: func(server containers.Containers_AttachServer) error {
+		if err := server.Send(&containers.StdoutStreamResponse{Output: []byte("hello")}); err != nil {
+			return err
+		}
+		close(sent)
+		<-server.Context().Done()
+		return nil
+	}})
+	go server.Serve(listener)
+
+	client, err := NewClient("eliot", config.Endpoint{URL: listener.Addr().String()}, WithInsecure())
+	assert.NoError(t, err)
+	defer client.Close()
+
+	go func() {
+		<-sent
+		server.Stop()
+	}()
+
+	err = client.Attach(context.Background(), "foo", NewAttachIO(nil, &bytes.Buffer{}, &bytes.Buffer{}))
+	assert.True(t, errors.Is(err, ErrConnectionLost), "should return ErrConnectionLost but got %v", err)
+	assert.True(t, errors.Is(err, ErrUnavailable), "should match also to ErrUnavailable but got %v", err)
+}
+
+func TestAttachReturnsTypedServerError(t *testing.T) {
+	client, stop := startFakeContainersServer(t, func(server containers.Containers_AttachServer) error {
+		return status.Error(codes.NotFound, "Container [foo] in namespace [eliot] not found")
+	})
+	defer stop()
+
+	err := client.Attach(context.Background(), "foo", NewAttachIO(nil, &bytes.Buffer{}, &bytes.Buffer{}))
+	assert.True(t, errors.Is(err, ErrNotFound), "should return ErrNotFound but got %v", err)
+}
+
+func TestExecReturnsTypedServerError(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+
+	server := grpc.NewServer()
+	containers.RegisterContainersServer(server, &fakeContainersServer{exec: func(server containers.Containers_ExecServer) error {
+		return status.Error(codes.NotFound, "Container [foo] in namespace [eliot] not found")
+	}})
+	go server.Serve(listener)
+	defer server.Stop()
+
+	client, err := NewClient("eliot", config.Endpoint{URL: listener.Addr().String()}, WithInsecure())
+	assert.NoError(t, err)
+	defer client.Close()
+
+	_, err = client.Exec(context.Background(), "foo", []string{"sh"}, false, NewAttachIO(nil, &bytes.Buffer{}, &bytes.Buffer{}))
+	assert.True(t, errors.Is(err, ErrNotFound), "should return ErrNotFound but got %v", err)
+}
@@ -0,0 +1,164 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+
+	"golang.org/x/net/context"
+	"google.golang.org/grpc/codes"
+)
+
+// MultiAttachOpts is option for AttachMulti
+type MultiAttachOpts func(options *multiAttachOptions)
+
+type multiAttachOptions struct {
+	active string
+	prefix func(containerID string) string
+}
+
+// WithActiveContainer routes the AttachMulti stdin to the container, by default the first container gets it
+func WithActiveContainer(containerID string) MultiAttachOpts {
+	return func(options *multiAttachOptions) {
+		options.active = containerID
+	}
+}
+
+// WithAttachPrefix formats the AttachMulti output line prefix, by default "[containerID] "
+func WithAttachPrefix(prefix func(containerID string) string) MultiAttachOpts {
+	return func(options *multiAttachOptions) {
+		options.prefix = prefix
+	}
+}
+
+// AttachMulti attaches to multiple containers at once, e.g. to the main process and the proxy sidecar.
+// The output of every container is written to the AttachIO Stdout and Stderr, each line prefixed with
+// the container ID. Use AttachIO LineBuffered to keep the lines of different containers from getting mixed.
+// Stdin and terminal size changes go only to the active container, see WithActiveContainer,
+// nil Stdin makes all the attaches read-only.
+// Returns when all containers have exited, so when one exits the others stay attached, or the context get
+// cancelled. Detaching from the active container detaches from all and returns ErrDetached.
+// If some attach fails, returns MultiAttachError which tells the containers and the errors.
+func (c *Client) AttachMulti(ctx context.Context, containerIDs []string, attachIO AttachIO, opts ...MultiAttachOpts) error {
+	if len(containerIDs) == 0 {
+		return &Error{Code: codes.InvalidArgument, Message: "Cannot attach, no containers given"}
+	}
+	options := &multiAttachOptions{active: containerIDs[0], prefix: DefaultLogPrefix}
+	for _, opt := range opts {
+		opt(options)
+	}
+
+	active := false
+	for _, containerID := range containerIDs {
+		active = active || containerID == options.active
+	}
+	if !active && attachIO.Stdin != nil {
+		return &Error{Code: codes.InvalidArgument, Message: fmt.Sprintf("Active container [%s] is not one of the attached containers [%s]", options.active, strings.Join(containerIDs, ", "))}
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		lock     sync.Mutex
+		wg       sync.WaitGroup
+		errsLock sync.Mutex
+		failed   = map[string]error{}
+		detached error
+	)
+	for _, containerID := range containerIDs {
+		containerIO := attachIO
+		containerIO.Stdout = newPrefixWriter(&lock, attachIO.Stdout, options.prefix(containerID))
+		containerIO.Stderr = newPrefixWriter(&lock, attachIO.Stderr, options.prefix(containerID))
+		if containerID != options.active {
+			containerIO.Stdin = nil
+			containerIO.Resize = nil
+		}
+
+		wg.Add(1)
+		go func(containerID string) {
+			defer wg.Done()
+			err := c.Attach(ctx, containerID, containerIO)
+
+			errsLock.Lock()
+			defer errsLock.Unlock()
+			switch {
+			case err == nil:
+			case errors.Is(err, ErrDetached):
+				detached = err
+				cancel()
+			case detached == nil:
+				failed[containerID] = err
+			}
+		}(containerID)
+	}
+	wg.Wait()
+
+	if detached != nil {
+		return detached
+	}
+	if len(failed) > 0 {
+		return &MultiAttachError{Failed: failed}
+	}
+	return nil
+}
+
+// MultiAttachError is returned by AttachMulti when attaching to some of the containers failed
+type MultiAttachError struct {
+	Failed map[string]error
+}
+
+func (e *MultiAttachError) Error() string {
+	ids := []string{}
+	for id := range e.Failed {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	problems := []string{}
+	for _, id := range ids {
+		problems = append(problems, fmt.Sprintf("container [%s]: %s", id, e.Failed[id]))
+	}
+	return fmt.Sprintf("Attach failed to %d containers: %s", len(ids), strings.Join(problems, ", "))
+}
+
+// prefixWriter writes the prefix to the beginning of each line. The writers of all containers
+// share the lock, so that single write doesn't get mixed with the other containers output.
+type prefixWriter struct {
+	lock    *sync.Mutex
+	w       io.Writer
+	prefix  []byte
+	midLine bool
+}
+
+// newPrefixWriter creates new prefixWriter, returns nil for nil writer
+func newPrefixWriter(lock *sync.Mutex, w io.Writer, prefix string) io.Writer {
+	if w == nil {
+		return nil
+	}
+	return &prefixWriter{lock: lock, w: w, prefix: []byte(prefix)}
+}
+
+func (w *prefixWriter) Write(p []byte) (int, error) {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	out := make([]byte, 0, len(p)+len(w.prefix))
+	for _, b := range p {
+		if !w.midLine {
+			out = append(out, w.prefix...)
+			w.midLine = true
+		}
+		out = append(out, b)
+		if b == '\n' {
+			w.midLine = false
+		}
+	}
+	if _, err := w.w.Write(out); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
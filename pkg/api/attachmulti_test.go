@@ -0,0 +1,87 @@
+package api
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	containers "github.com/ernoaapa/eliot/pkg/api/services/containers/v1"
+)
+
+func TestAttachMultiPrefixesOutputAndRoutesStdin(t *testing.T) {
+	client, stop := startFakeContainersServer(t, func(server containers.Containers_AttachServer) error {
+		md, _ := metadata.FromIncomingContext(server.Context())
+		switch getMetadataValue(md, "container") {
+		case "app":
+			assert.Equal(t, "true", getMetadataValue(md, "stdin"))
+			req, err := server.Recv()
+			if err != nil {
+				return err
+			}
+			return server.Send(&containers.StdoutStreamResponse{Output: []byte("got " + string(req.Input) + "\n")})
+		case "proxy":
+			assert.Equal(t, "false", getMetadataValue(md, "stdin"), "should attach stdin only to the active container")
+			return server.Send(&containers.StdoutStreamResponse{Output: []byte("proxy up\n"), Stderr: true})
+		}
+		return status.Error(codes.NotFound, "not found")
+	})
+	defer stop()
+
+	stdout, stderr := &bytes.Buffer{}, &bytes.Buffer{}
+	err := client.AttachMulti(context.Background(), []string{"proxy", "app"}, NewAttachIO(strings.NewReader("ping"), stdout, stderr), WithActiveContainer("app"))
+
+	assert.NoError(t, err)
+	assert.Equal(t, "[app] got ping\n", stdout.String(), "app should stay attached after proxy exits")
+	assert.Equal(t, "[proxy] proxy up\n", stderr.String())
+}
+
+func TestAttachMultiReturnsFailedContainers(t *testing.T) {
+	client, stop := startFakeContainersServer(t, func(server containers.Containers_AttachServer) error {
+		md, _ := metadata.FromIncomingContext(server.Context())
+		if getMetadataValue(md, "container") == "missing" {
+			return status.Error(codes.NotFound, "Container [missing] not found")
+		}
+		return server.Send(&containers.StdoutStreamResponse{Output: []byte("hello\n")})
+	})
+	defer stop()
+
+	stdout := &bytes.Buffer{}
+	err := client.AttachMulti(context.Background(), []string{"app", "missing"}, NewAttachIO(nil, stdout, stdout))
+
+	multiErr, ok := err.(*MultiAttachError)
+	assert.True(t, ok, "should return MultiAttachError, got: %v", err)
+	assert.Len(t, multiErr.Failed, 1)
+	assert.True(t, errors.Is(multiErr.Failed["missing"], ErrNotFound))
+	assert.Equal(t, "[app] hello\n", stdout.String())
+}
+
+func TestAttachMultiInvalidActiveContainer(t *testing.T) {
+	client, stop := startFakeContainersServer(t, nil)
+	defer stop()
+
+	err := client.AttachMulti(context.Background(), []string{"app"}, NewAttachIO(strings.NewReader(""), nil, nil), WithActiveContainer("other"))
+	assert.True(t, errors.Is(err, ErrInvalidArgument))
+
+	err = client.AttachMulti(context.Background(), []string{}, NewAttachIO(nil, nil, nil))
+	assert.True(t, errors.Is(err, ErrInvalidArgument))
+}
+
+func TestPrefixWriterPrefixesEachLine(t *testing.T) {
+	out := &bytes.Buffer{}
+	w := newPrefixWriter(&sync.Mutex{}, out, "[app] ")
+
+	w.Write([]byte("one\ntw"))
+	w.Write([]byte("o\n"))
+	w.Write([]byte("three"))
+	assert.Equal(t, "[app] one\n[app] two\n[app] three", out.String())
+
+	assert.Nil(t, newPrefixWriter(&sync.Mutex{}, nil, "[app] "))
+}
@@ -0,0 +1,123 @@
+package api
+
+import (
+	"fmt"
+	"io"
+
+	node "github.com/ernoaapa/eliot/pkg/api/services/node/v1"
+	"github.com/ernoaapa/eliot/pkg/image"
+	"github.com/pkg/errors"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc/codes"
+)
+
+// BuildImage streams the build context tar archive, which must have Dockerfile in the root, to the node
+// and builds the image there with BuildKit. The build output lines are sent to the channel while the build runs,
+// the lines which tell why the build failed have Error set. The last event has the built image digest,
+// so the image can be used right away in CreatePod, e.g. as myapp:latest@sha256:..., or Err if the build failed.
+// The channel get closed after the last event. Returns ErrInvalidArgument if some tag is malformed.
+func (c *Client) BuildImage(ctx context.Context, contextTar io.Reader, opts BuildOptions) (<-chan BuildEvent, error) {
+	tags, err := normalizeBuildTags(opts.Tags)
+	if err != nil {
+		return nil, err
+	}
+
+	conn, err := c.getConnection()
+	if err != nil {
+		return nil, err
+	}
+
+	streamCtx, cancel := context.WithCancel(ctx)
+	s, err := node.NewNodeClient(conn).Build(streamCtx)
+	if err != nil {
+		cancel()
+		return nil, translateError(err)
+	}
+
+	// sendErr is the failure to read the build context, which cancels the stream
+	sendErr := make(chan error, 1)
+	go func() {
+		req := &node.BuildRequest{
+			Namespace: c.Namespace,
+			Tags:      tags,
+			BuildArgs: opts.BuildArgs,
+			Target:    opts.Target,
+		}
+		buf := make([]byte, copyChunkSize)
+		for {
+			n, readErr := contextTar.Read(buf)
+			if n > 0 || req.Namespace != "" {
+				req.Data = buf[:n]
+				if err := s.Send(req); err != nil {
+					// Server closed the stream, the actual error is returned by Recv
+					return
+				}
+				req = &node.BuildRequest{}
+			}
+			if readErr == io.EOF {
+				s.CloseSend()
+				return
+			}
+			if readErr != nil {
+				sendErr <- errors.Wrapf(readErr, "Failed to read build context")
+				cancel()
+				return
+			}
+		}
+	}()
+
+	result := make(chan BuildEvent)
+	go func() {
+		defer close(result)
+		defer cancel()
+		for {
+			resp, err := s.Recv()
+			if err != nil {
+				select {
+				case readErr := <-sendErr:
+					err = readErr
+				default:
+					if err == io.EOF {
+						err = &Error{Code: codes.Internal, Message: "Build ended without image digest"}
+					}
+				}
+				select {
+				case result <- BuildEvent{Err: translateError(err)}:
+				case <-ctx.Done():
+				}
+				return
+			}
+
+			event := BuildEvent{Line: resp.GetLine(), Error: resp.GetError(), Digest: resp.GetDigest()}
+			select {
+			case result <- event:
+			case <-ctx.Done():
+				return
+			}
+			if event.Digest != "" {
+				return
+			}
+		}
+	}()
+	return result, nil
+}
+
+// normalizeBuildTags expands the tags to fully qualified form, e.g. myapp -> docker.io/library/myapp:latest
+func normalizeBuildTags(tags []string) ([]string, error) {
+	if len(tags) == 0 {
+		return nil, &Error{Code: codes.InvalidArgument, Message: "Image build requires at least one tag"}
+	}
+
+	result := []string{}
+	for _, tag := range tags {
+		ref, err := image.ParseRef(expandImage(tag))
+		if err != nil {
+			return nil, &Error{Code: codes.InvalidArgument, Message: err.Error()}
+		}
+		if ref.IsPinned() {
+			return nil, &Error{Code: codes.InvalidArgument, Message: fmt.Sprintf("Invalid build tag [%s], tag cannot have digest", tag)}
+		}
+		result = append(result, resolvableImage(ref))
+	}
+	return result, nil
+}
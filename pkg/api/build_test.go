@@ -0,0 +1,147 @@
+package api
+
+import (
+	"archive/tar"
+	"bytes"
+	"errors"
+	"io"
+	"io/ioutil"
+	"testing"
+	"time"
+
+	"github.com/ernoaapa/eliot/pkg/runtime"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/net/context"
+)
+
+// buildRuntime reads the Dockerfile from the build context and replays the build output
+type buildRuntime struct {
+	runtime.Client
+	opts       runtime.BuildOptions
+	dockerfile string
+	logs       []runtime.BuildLog
+	err        error
+}
+
+func (r *buildRuntime) BuildImage(namespace string, buildContext io.Reader, opts runtime.BuildOptions, done <-chan struct{}, handler func(runtime.BuildLog) error) (string, error) {
+	r.opts = opts
+	archive := tar.NewReader(buildContext)
+	for {
+		header, err := archive.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return "", err
+		}
+		if header.Name == "Dockerfile" {
+			data, err := ioutil.ReadAll(archive)
+			if err != nil {
+				return "", err
+			}
+			r.dockerfile = string(data)
+		}
+	}
+
+	for _, line := range r.logs {
+		if err := handler(line); err != nil {
+			return "", err
+		}
+	}
+	if r.err != nil {
+		return "", r.err
+	}
+	return "sha256:abc", nil
+}
+
+func newBuildContext(t *testing.T, dockerfile string) io.Reader {
+	buf := &bytes.Buffer{}
+	archive := tar.NewWriter(buf)
+	assert.NoError(t, archive.WriteHeader(&tar.Header{Name: "Dockerfile", Mode: 0644, Size: int64(len(dockerfile))}))
+	_, err := archive.Write([]byte(dockerfile))
+	assert.NoError(t, err)
+	assert.NoError(t, archive.Close())
+	return buf
+}
+
+func receiveBuildEvents(t *testing.T, events <-chan BuildEvent) (result []BuildEvent) {
+	timeout := time.After(5 * time.Second)
+	for {
+		select {
+		case event, ok := <-events:
+			if !ok {
+				return result
+			}
+			result = append(result, event)
+		case <-timeout:
+			t.Fatal("BuildImage didn't close the channel")
+			return result
+		}
+	}
+}
+
+func TestBuildImage(t *testing.T) {
+	fake := &buildRuntime{logs: []runtime.BuildLog{
+		{Line: "#1 [internal] load build definition from Dockerfile"},
+		{Line: "#2 DONE 0.1s"},
+	}}
+	client, stop := startDiskUsageServer(t, fake)
+	defer stop()
+
+	events, err := client.BuildImage(context.Background(), newBuildContext(t, "FROM alpine\n"), BuildOptions{
+		Tags:      []string{"myapp", "registry.local/team/myapp:1.0"},
+		BuildArgs: map[string]string{"VERSION": "1.0"},
+		Target:    "release",
+	})
+	assert.NoError(t, err)
+
+	received := receiveBuildEvents(t, events)
+	assert.Equal(t, []BuildEvent{
+		{Line: "#1 [internal] load build definition from Dockerfile"},
+		{Line: "#2 DONE 0.1s"},
+		{Digest: "sha256:abc"},
+	}, received)
+
+	assert.Equal(t, "FROM alpine\n", fake.dockerfile)
+	assert.Equal(t, []string{"docker.io/library/myapp:latest", "registry.local/team/myapp:1.0"}, fake.opts.Tags)
+	assert.Equal(t, map[string]string{"VERSION": "1.0"}, fake.opts.BuildArgs)
+	assert.Equal(t, "release", fake.opts.Target)
+}
+
+func TestBuildImageStreamsErrorLines(t *testing.T) {
+	fake := &buildRuntime{
+		logs: []runtime.BuildLog{
+			{Line: "#5 [2/2] RUN make"},
+			{Line: "#5 ERROR: executor failed running [/bin/sh -c make]: exit code: 2", Error: true},
+			{Line: "error: failed to solve: exit code: 2", Error: true},
+		},
+		err: errors.New("Image build failed: #5 ERROR: executor failed running [/bin/sh -c make]: exit code: 2"),
+	}
+	client, stop := startDiskUsageServer(t, fake)
+	defer stop()
+
+	events, err := client.BuildImage(context.Background(), newBuildContext(t, "FROM alpine\nRUN make\n"), BuildOptions{Tags: []string{"myapp"}})
+	assert.NoError(t, err)
+
+	received := receiveBuildEvents(t, events)
+	assert.Len(t, received, 4)
+	assert.False(t, received[0].Error)
+	assert.True(t, received[1].Error)
+	assert.True(t, received[2].Error)
+
+	last := received[3]
+	assert.Empty(t, last.Digest)
+	assert.Error(t, last.Err)
+	assert.Contains(t, last.Err.Error(), "Image build failed")
+}
+
+func TestBuildImageValidatesTags(t *testing.T) {
+	client, stop := startDiskUsageServer(t, &buildRuntime{})
+	defer stop()
+
+	_, err := client.BuildImage(context.Background(), newBuildContext(t, "FROM alpine\n"), BuildOptions{})
+	assert.True(t, errors.Is(err, ErrInvalidArgument), "should require at least one tag")
+
+	_, err = client.BuildImage(context.Background(), newBuildContext(t, "FROM alpine\n"), BuildOptions{Tags: []string{"myapp@sha256:abc"}})
+	assert.True(t, errors.Is(err, ErrInvalidArgument), "should reject digest in tag")
+}
@@ -0,0 +1,76 @@
+package api
+
+import (
+	"fmt"
+	"strings"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// callOptions are the WithCallOptions options by the RPC method name, empty name for all methods
+type callOptions map[string][]grpc.CallOption
+
+// apply return the options for the method call, first the ones for all methods, then the method
+// specific ones and last the options given to the call, so the later ones override the earlier
+func (o callOptions) apply(fullMethod string, opts []grpc.CallOption) []grpc.CallOption {
+	if len(o) == 0 {
+		return opts
+	}
+	method := fullMethod[strings.LastIndex(fullMethod, "/")+1:]
+	result := append([]grpc.CallOption{}, o[""]...)
+	result = append(result, o[method]...)
+	return append(result, opts...)
+}
+
+// WithCallOptions adds the gRPC call options to the calls of the given methods, or all calls if no methods are given.
+// The method names are the RPC names: List, Attach, Logs, CopyFrom, etc. Method specific options override
+// the options for all methods. See also WithMaxRecvMsgSize and WithMaxSendMsgSize.
+func WithCallOptions(opts []grpc.CallOption, methods ...string) ClientOpts {
+	return func(client *Client) error {
+		if client.callOptions == nil {
+			client.callOptions = callOptions{}
+		}
+		if len(methods) == 0 {
+			client.callOptions[""] = append(client.callOptions[""], opts...)
+			return nil
+		}
+		for _, method := range methods {
+			if method == "" || strings.Contains(method, "/") {
+				return fmt.Errorf("Invalid call options method [%s], must be RPC method name, e.g. Attach", method)
+			}
+			client.callOptions[method] = append(client.callOptions[method], opts...)
+		}
+		return nil
+	}
+}
+
+// WithMaxRecvMsgSize sets the largest message the client accepts from the server in the given methods,
+// or all calls if no methods are given. By default the limit is 4MiB, which e.g. List of many pods can exceed.
+// Too large message fails the call with ErrMessageTooLarge.
+func WithMaxRecvMsgSize(bytes int, methods ...string) ClientOpts {
+	return func(client *Client) error {
+		if bytes <= 0 {
+			return fmt.Errorf("Invalid max receive message size [%d], must be positive", bytes)
+		}
+		return WithCallOptions([]grpc.CallOption{grpc.MaxCallRecvMsgSize(bytes)}, methods...)(client)
+	}
+}
+
+// WithMaxSendMsgSize sets the largest message the client sends to the server in the given methods,
+// or all calls if no methods are given. The server has its own limit for the messages it receives.
+// Too large message fails the call with ErrMessageTooLarge.
+func WithMaxSendMsgSize(bytes int, methods ...string) ClientOpts {
+	return func(client *Client) error {
+		if bytes <= 0 {
+			return fmt.Errorf("Invalid max send message size [%d], must be positive", bytes)
+		}
+		return WithCallOptions([]grpc.CallOption{grpc.MaxCallSendMsgSize(bytes)}, methods...)(client)
+	}
+}
+
+// isMessageTooLarge return true if gRPC failed the call because the message exceeds the size limit
+func isMessageTooLarge(s *status.Status) bool {
+	return s.Code() == codes.ResourceExhausted && strings.Contains(s.Message(), "message larger than max")
+}
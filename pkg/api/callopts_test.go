@@ -0,0 +1,74 @@
+package api
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc"
+
+	core "github.com/ernoaapa/eliot/pkg/api/core"
+	pods "github.com/ernoaapa/eliot/pkg/api/services/pods/v1"
+	"github.com/ernoaapa/eliot/pkg/config"
+)
+
+func startLargeListServer(t *testing.T, opts ...ClientOpts) (*Client, func()) {
+	large := &pods.Pod{Metadata: &core.ResourceMetadata{
+		Name:   "foo",
+		Labels: map[string]string{"data": strings.Repeat("x", 5*1024*1024)},
+	}}
+	return startFakeServer(t, &fakePodsServer{list: func(req *pods.ListPodsRequest) (*pods.ListPodsResponse, error) {
+		return &pods.ListPodsResponse{Pods: []*pods.Pod{large}}, nil
+	}}, nil, opts...)
+}
+
+func TestLargeResponseReturnsErrMessageTooLarge(t *testing.T) {
+	client, stop := startLargeListServer(t)
+	defer stop()
+
+	_, err := client.GetPods(context.Background())
+	assert.True(t, errors.Is(err, ErrMessageTooLarge), "should return ErrMessageTooLarge, got: %v", err)
+	assert.True(t, errors.Is(err, ErrResourceExhausted))
+	assert.Contains(t, err.Error(), "WithMaxRecvMsgSize")
+}
+
+func TestWithMaxRecvMsgSize(t *testing.T) {
+	client, stop := startLargeListServer(t, WithMaxRecvMsgSize(16*1024*1024, "List"))
+	defer stop()
+
+	result, err := client.GetPods(context.Background())
+	assert.NoError(t, err)
+	assert.Len(t, result, 1)
+}
+
+func TestWithMaxRecvMsgSizeOnlyForGivenMethods(t *testing.T) {
+	client, stop := startLargeListServer(t, WithMaxRecvMsgSize(16*1024*1024, "Attach"))
+	defer stop()
+
+	_, err := client.GetPods(context.Background())
+	assert.True(t, errors.Is(err, ErrMessageTooLarge), "should keep the default limit for List, got: %v", err)
+}
+
+type testCallOption struct {
+	grpc.EmptyCallOption
+	id int
+}
+
+func TestCallOptionsOrder(t *testing.T) {
+	all, attach, call := testCallOption{id: 1}, testCallOption{id: 2}, testCallOption{id: 3}
+	opts := callOptions{"": {all}, "Attach": {attach}}
+
+	assert.Equal(t, []grpc.CallOption{all, attach, call}, opts.apply("/eliot.services.containers.v1.Containers/Attach", []grpc.CallOption{call}))
+	assert.Equal(t, []grpc.CallOption{all}, opts.apply("/eliot.services.pods.v1.Pods/List", nil))
+	assert.Nil(t, callOptions(nil).apply("/eliot.services.pods.v1.Pods/List", nil))
+}
+
+func TestWithCallOptionsInvalid(t *testing.T) {
+	_, err := NewClient("eliot", config.Endpoint{URL: "127.0.0.1:1"}, WithCallOptions(nil, "pods.Pods/List"))
+	assert.Error(t, err)
+
+	_, err = NewClient("eliot", config.Endpoint{URL: "127.0.0.1:1"}, WithMaxSendMsgSize(0))
+	assert.Error(t, err)
+}
@@ -0,0 +1,69 @@
+package api
+
+import (
+	"os"
+	"time"
+
+	"github.com/pkg/errors"
+	"golang.org/x/net/context"
+)
+
+// runCleanupTimeout is how long RunAndCapture waits the pod deletion, also when the context is already cancelled
+const runCleanupTimeout = DefaultGracePeriod + 10*time.Second
+
+// RunAndCapture runs one-shot container, like `eli run --rm`, and writes the container stdout and stderr combined
+// to the file in outPath. The file gets created or truncated after the spec is validated and is left on disk,
+// also when the run fails.
+// Returns the container exit code after the container exits, the pod gets deleted then.
+// The container is never restarted. The output is received by attaching to the container after it has started,
+// so output which the container writes right away at start can be missed.
+// If the context gets cancelled, the pod is still deleted, which stops the container.
+func (c *Client) RunAndCapture(ctx context.Context, spec RunSpec, outPath string) (exitCode int, err error) {
+	opts := []RunOpts{WithRunRestartPolicy(RestartNever), WithRunEnv(spec.Env)}
+	if len(spec.Command) > 0 {
+		opts = append(opts, WithRunCommand(spec.Command...))
+	}
+	pod, err := c.newRunPod(spec.Name, spec.Image, append(opts, spec.Opts...)...)
+	if err != nil {
+		return -1, err
+	}
+	if err := ValidatePod(pod); err != nil {
+		return -1, err
+	}
+
+	out, err := os.Create(outPath)
+	if err != nil {
+		return -1, errors.Wrapf(err, "Failed to create output file [%s]", outPath)
+	}
+	defer func() {
+		if closeErr := out.Close(); closeErr != nil && err == nil {
+			err = errors.Wrapf(closeErr, "Failed to write output file [%s]", outPath)
+		}
+	}()
+
+	if err := c.CreatePod(ctx, nil, pod); err != nil {
+		return -1, err
+	}
+	defer func() {
+		cleanupCtx, cancel := context.WithTimeout(context.Background(), runCleanupTimeout)
+		defer cancel()
+		if _, deleteErr := c.DeletePod(cleanupCtx, pod); deleteErr != nil && err == nil {
+			err = errors.Wrapf(deleteErr, "Failed to delete pod [%s]", spec.Name)
+		}
+	}()
+
+	started, err := c.StartPod(ctx, spec.Name)
+	if err != nil {
+		return -1, err
+	}
+	containerID, err := findContainerID(started, spec.Name)
+	if err != nil {
+		return -1, err
+	}
+
+	// The container might have exited already before the attach, then Wait still returns the exit code
+	if err := c.AttachReadOnly(ctx, containerID, out, out); err != nil && !isContainerGone(err) {
+		return -1, err
+	}
+	return c.WaitContainer(ctx, containerID)
+}
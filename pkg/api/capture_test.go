@@ -0,0 +1,110 @@
+package api
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/net/context"
+
+	containers "github.com/ernoaapa/eliot/pkg/api/services/containers/v1"
+	pods "github.com/ernoaapa/eliot/pkg/api/services/pods/v1"
+	"github.com/ernoaapa/eliot/pkg/config"
+)
+
+// capturePodsServer starts the created pod with single running container, which has the pod name as the ID
+type capturePodsServer struct {
+	pods.PodsServer
+	mu      sync.Mutex
+	pod     *pods.Pod
+	calls   []string
+	deleted string
+}
+
+func (s *capturePodsServer) Create(req *pods.CreatePodRequest, server pods.Pods_CreateServer) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.calls = append(s.calls, "create")
+	s.pod = req.Pod
+	return nil
+}
+
+func (s *capturePodsServer) Start(ctx context.Context, req *pods.StartPodRequest) (*pods.StartPodResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.calls = append(s.calls, "start")
+	s.pod.Status = &pods.PodStatus{ContainerStatuses: []*containers.ContainerStatus{
+		{ContainerID: req.Name, Name: req.Name, State: "running"},
+	}}
+	return &pods.StartPodResponse{Pod: s.pod}, nil
+}
+
+func (s *capturePodsServer) Delete(ctx context.Context, req *pods.DeletePodRequest) (*pods.DeletePodResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.calls = append(s.calls, "delete")
+	s.deleted = req.Name
+	return &pods.DeletePodResponse{Pod: s.pod}, nil
+}
+
+func TestRunAndCapture(t *testing.T) {
+	fake := &capturePodsServer{}
+	client, stop := startFakeServer(t, fake, &fakeContainersServer{
+		attach: func(server containers.Containers_AttachServer) error {
+			if err := server.Send(&containers.StdoutStreamResponse{Output: []byte("out\n")}); err != nil {
+				return err
+			}
+			return server.Send(&containers.StdoutStreamResponse{Output: []byte("err\n"), Stderr: true})
+		},
+		wait: func(req *containers.WaitRequest) (*containers.WaitResponse, error) {
+			assert.Equal(t, "job", req.ContainerID)
+			return &containers.WaitResponse{ExitCode: 3}, nil
+		},
+	})
+	defer stop()
+
+	dir, err := ioutil.TempDir("", "capture")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+	outPath := filepath.Join(dir, "job.log")
+
+	exitCode, err := client.RunAndCapture(context.Background(), RunSpec{
+		Name:    "job",
+		Image:   "alpine",
+		Command: []string{"sh", "-c", "echo out; echo err >&2; exit 3"},
+		Env:     map[string]string{"FOO": "bar"},
+	}, outPath)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, exitCode)
+	assert.Equal(t, []string{"create", "start", "delete"}, fake.calls)
+	assert.Equal(t, "job", fake.deleted)
+
+	container := fake.pod.Spec.Containers[0]
+	assert.Equal(t, "docker.io/library/alpine:latest", container.Image)
+	assert.Equal(t, []string{"sh", "-c", "echo out; echo err >&2; exit 3"}, container.Args)
+	assert.Equal(t, []string{"FOO=bar"}, container.Env)
+	assert.Equal(t, "never", fake.pod.Spec.RestartPolicy)
+
+	output, err := ioutil.ReadFile(outPath)
+	assert.NoError(t, err)
+	assert.Equal(t, "out\nerr\n", string(output))
+}
+
+func TestRunAndCaptureInvalidSpec(t *testing.T) {
+	dir, err := ioutil.TempDir("", "capture")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	client, err := NewClient("eliot", config.Endpoint{URL: "127.0.0.1:1"}, WithInsecure())
+	assert.NoError(t, err)
+	defer client.Close()
+
+	outPath := filepath.Join(dir, "job.log")
+	_, err = client.RunAndCapture(context.Background(), RunSpec{Name: "Invalid_Name", Image: "alpine"}, outPath)
+	assert.Error(t, err)
+	_, err = os.Stat(outPath)
+	assert.True(t, os.IsNotExist(err), "should not create the output file for invalid spec")
+}
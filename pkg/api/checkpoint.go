@@ -0,0 +1,191 @@
+package api
+
+import (
+	"io"
+	"os"
+
+	containers "github.com/ernoaapa/eliot/pkg/api/services/containers/v1"
+	"github.com/ernoaapa/eliot/pkg/runtime"
+	"github.com/pkg/errors"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc/codes"
+)
+
+// WithCheckpointOnNode stores the checkpoint in the checkpoint directory in the node, instead of streaming it
+// to the client, e.g. to restore the container later in the same node without transferring the memory dump.
+func WithCheckpointOnNode() CheckpointOpts {
+	return func(req *containers.CheckpointRequest) error {
+		req.StoreOnNode = true
+		return nil
+	}
+}
+
+// WithRestoreFromNode restores the container from the checkpoint directory in the node,
+// what Checkpoint with WithCheckpointOnNode has stored, instead of streaming the checkpoint from the client.
+func WithRestoreFromNode() RestoreOpts {
+	return func(req *containers.RestoreRequest) error {
+		req.FromNode = true
+		return nil
+	}
+}
+
+// Checkpoint checkpoints the running container memory state and filesystem changes with CRIU,
+// the container keeps running. By default the checkpoint is streamed to the local checkpoint directory,
+// with WithCheckpointOnNode it's stored in the node.
+// Returns ErrContainerNotRunning if the container is not running and ErrCheckpointUnsupported
+// if the node container runtime doesn't support CRIU.
+func (c *Client) Checkpoint(ctx context.Context, containerID, checkpointDir string, opts ...CheckpointOpts) error {
+	req := &containers.CheckpointRequest{
+		Namespace:   c.Namespace,
+		ContainerID: containerID,
+		Path:        checkpointDir,
+	}
+	for _, opt := range opts {
+		if err := opt(req); err != nil {
+			return err
+		}
+	}
+
+	if !req.StoreOnNode {
+		if err := os.MkdirAll(checkpointDir, 0755); err != nil {
+			return errors.Wrapf(err, "Failed to create checkpoint directory [%s]", checkpointDir)
+		}
+	}
+
+	conn, err := c.getConnection()
+	if err != nil {
+		return err
+	}
+
+	s, err := containers.NewContainersClient(conn).Checkpoint(ctx, req)
+	if err != nil {
+		return translateCheckpointError(err)
+	}
+
+	if req.StoreOnNode {
+		// The node sends no data, just wait the checkpoint to complete
+		for {
+			if _, err := s.Recv(); err != nil {
+				if err == io.EOF {
+					return nil
+				}
+				return translateCheckpointError(err)
+			}
+		}
+	}
+
+	if err := runtime.ExtractArchive(checkpointDir, "/", &checkpointReader{stream: s}); err != nil {
+		return translateCheckpointError(err)
+	}
+	return nil
+}
+
+// Restore restores the pod container from the checkpoint, the current container process gets killed.
+// By default the checkpoint is streamed from the local checkpoint directory, with WithRestoreFromNode
+// it's read from the node. The container name can be empty if the pod has only one container.
+// Returns ErrCheckpointUnsupported if the node container runtime doesn't support CRIU.
+func (c *Client) Restore(ctx context.Context, podName, containerName, checkpointDir string, opts ...RestoreOpts) error {
+	pod, err := c.GetPod(ctx, podName)
+	if err != nil {
+		return err
+	}
+	status, err := findContainerStatus(pod, containerName)
+	if err != nil {
+		return err
+	}
+
+	req := &containers.RestoreRequest{
+		Namespace: c.Namespace,
+		Pod:       podName,
+		Container: status.GetName(),
+		Path:      checkpointDir,
+	}
+	for _, opt := range opts {
+		if err := opt(req); err != nil {
+			return err
+		}
+	}
+
+	if !req.FromNode {
+		if _, err := runtime.ReadCheckpointManifest(checkpointDir); err != nil {
+			return &Error{Code: codes.NotFound, Message: err.Error()}
+		}
+	}
+
+	conn, err := c.getConnection()
+	if err != nil {
+		return err
+	}
+
+	s, err := containers.NewContainersClient(conn).Restore(ctx)
+	if err != nil {
+		return translateCheckpointError(err)
+	}
+
+	if err := s.Send(req); err != nil {
+		// Server closed the stream, the actual error is returned by CloseAndRecv
+		_, err = s.CloseAndRecv()
+		return translateCheckpointError(err)
+	}
+
+	if !req.FromNode {
+		r, w := io.Pipe()
+		go func() {
+			w.CloseWithError(runtime.CreateArchive(checkpointDir, "/", w))
+		}()
+
+		buf := make([]byte, copyChunkSize)
+		for {
+			n, readErr := r.Read(buf)
+			if n > 0 {
+				if err := s.Send(&containers.RestoreRequest{Data: buf[:n]}); err != nil {
+					r.CloseWithError(err)
+					break
+				}
+			}
+			if readErr == io.EOF {
+				break
+			}
+			if readErr != nil {
+				return errors.Wrapf(readErr, "Failed to read checkpoint [%s]", checkpointDir)
+			}
+		}
+	}
+
+	_, err = s.CloseAndRecv()
+	return translateCheckpointError(err)
+}
+
+// translateCheckpointError converts the server error to ErrCheckpointUnsupported if the node doesn't support
+// checkpoints, ErrContainerNotRunning if the container is not running and ErrContainerNotFound if it doesn't exist
+func translateCheckpointError(err error) error {
+	err = translateContainerError(err)
+	if e, ok := err.(*Error); ok {
+		switch e.Code {
+		case codes.Unimplemented:
+			return &Error{Code: e.Code, Message: e.Message, cause: ErrCheckpointUnsupported}
+		case codes.FailedPrecondition:
+			return &Error{Code: e.Code, Message: e.Message, cause: ErrContainerNotRunning}
+		}
+	}
+	return err
+}
+
+// checkpointReader is io.Reader implementation what reads checkpoint archive chunks from RPC stream
+type checkpointReader struct {
+	data   []byte
+	stream containers.Containers_CheckpointClient
+}
+
+func (r *checkpointReader) Read(p []byte) (int, error) {
+	for len(r.data) == 0 {
+		resp, err := r.stream.Recv()
+		if err != nil {
+			return 0, err
+		}
+		r.data = resp.GetData()
+	}
+	n := copy(p, r.data)
+	r.data = r.data[n:]
+	return n, nil
+}
@@ -0,0 +1,135 @@
+package api
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ernoaapa/eliot/pkg/model"
+	"github.com/ernoaapa/eliot/pkg/runtime"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/net/context"
+)
+
+// checkpointRuntime writes fake checkpoint to the directory and records the restored checkpoint
+type checkpointRuntime struct {
+	runtime.Client
+	unsupported bool
+	dir         string
+	restored    string
+	memory      []byte
+}
+
+func (r *checkpointRuntime) GetPods(namespace string) ([]model.Pod, error) {
+	pod, err := r.GetPod(namespace, "foo")
+	return []model.Pod{pod}, err
+}
+
+func (r *checkpointRuntime) GetPod(namespace, name string) (model.Pod, error) {
+	return model.Pod{
+		Metadata: model.Metadata{Name: name, Namespace: namespace},
+		Status: model.PodStatus{ContainerStatuses: []model.ContainerStatus{
+			{ContainerID: "foo-redis", Name: "redis", State: "running"},
+		}},
+	}, nil
+}
+
+func (r *checkpointRuntime) CheckpointContainer(namespace, name, dir string) error {
+	if r.unsupported {
+		return runtime.ErrWithMessagef(runtime.ErrNotSupported, "Cannot checkpoint container [%s], the node doesn't support CRIU", name)
+	}
+	r.dir = dir
+	if err := os.MkdirAll(filepath.Join(dir, "blobs", "sha256"), 0755); err != nil {
+		return err
+	}
+	if err := ioutil.WriteFile(filepath.Join(dir, "blobs", "sha256", "abc"), []byte("memory of "+name), 0644); err != nil {
+		return err
+	}
+	return ioutil.WriteFile(filepath.Join(dir, runtime.CheckpointManifestFile), []byte(`{"ContainerID":"`+name+`"}`), 0644)
+}
+
+func (r *checkpointRuntime) RestoreContainer(namespace, name, dir string, io runtime.IOSet) (model.ContainerStatus, error) {
+	if r.unsupported {
+		return model.ContainerStatus{}, runtime.ErrWithMessagef(runtime.ErrNotSupported, "Cannot restore container [%s], the node doesn't support CRIU", name)
+	}
+	manifest, err := runtime.ReadCheckpointManifest(dir)
+	if err != nil {
+		return model.ContainerStatus{}, err
+	}
+	r.restored = manifest.ContainerID
+	r.dir = dir
+	r.memory, err = ioutil.ReadFile(filepath.Join(dir, "blobs", "sha256", "abc"))
+	return model.ContainerStatus{ContainerID: name, Name: "redis", State: "running"}, err
+}
+
+func TestCheckpointAndRestoreStreamsCheckpoint(t *testing.T) {
+	dir, err := ioutil.TempDir("", "checkpoint-test")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+	checkpointDir := filepath.Join(dir, "redis")
+
+	fake := &checkpointRuntime{}
+	client, stop := startDiskUsageServer(t, fake)
+	defer stop()
+
+	assert.NoError(t, client.Checkpoint(context.Background(), "foo-redis", checkpointDir))
+	assert.NotEqual(t, checkpointDir, fake.dir, "should checkpoint to temporary directory in the node")
+
+	data, err := ioutil.ReadFile(filepath.Join(checkpointDir, "blobs", "sha256", "abc"))
+	assert.NoError(t, err)
+	assert.Equal(t, "memory of foo-redis", string(data))
+
+	assert.NoError(t, client.Restore(context.Background(), "foo", "redis", checkpointDir))
+	assert.Equal(t, "foo-redis", fake.restored)
+	assert.Equal(t, "memory of foo-redis", string(fake.memory))
+}
+
+func TestCheckpointOnNode(t *testing.T) {
+	dir, err := ioutil.TempDir("", "checkpoint-test")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	fake := &checkpointRuntime{}
+	client, stop := startDiskUsageServer(t, fake)
+	defer stop()
+
+	assert.NoError(t, client.Checkpoint(context.Background(), "foo-redis", dir, WithCheckpointOnNode()))
+	assert.Equal(t, dir, fake.dir)
+
+	assert.NoError(t, client.Restore(context.Background(), "foo", "", dir, WithRestoreFromNode()))
+	assert.Equal(t, dir, fake.dir, "should restore from the node path")
+	assert.Equal(t, "foo-redis", fake.restored)
+}
+
+func TestCheckpointUnsupported(t *testing.T) {
+	dir, err := ioutil.TempDir("", "checkpoint-test")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	client, stop := startDiskUsageServer(t, &checkpointRuntime{unsupported: true})
+	defer stop()
+
+	err = client.Checkpoint(context.Background(), "foo-redis", dir)
+	assert.True(t, errors.Is(err, ErrCheckpointUnsupported), "should return ErrCheckpointUnsupported, got: %v", err)
+	assert.True(t, errors.Is(err, ErrUnimplemented))
+
+	err = client.Restore(context.Background(), "foo", "redis", dir, WithRestoreFromNode())
+	assert.True(t, errors.Is(err, ErrCheckpointUnsupported), "should return ErrCheckpointUnsupported, got: %v", err)
+}
+
+func TestRestoreRequiresCheckpoint(t *testing.T) {
+	dir, err := ioutil.TempDir("", "checkpoint-test")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	client, stop := startDiskUsageServer(t, &checkpointRuntime{})
+	defer stop()
+
+	err = client.Restore(context.Background(), "foo", "redis", dir)
+	assert.True(t, errors.Is(err, ErrNotFound), "should fail if the directory doesn't contain checkpoint, got: %v", err)
+
+	err = client.Restore(context.Background(), "foo", "missing", dir)
+	assert.True(t, errors.Is(err, ErrContainerNotFound))
+}
@@ -3,14 +3,24 @@ package api
 import (
 	"fmt"
 	"io"
+	"io/ioutil"
+	"sort"
 	"strconv"
 	"strings"
+	"sync"
 	"syscall"
+	"time"
 
 	log "github.com/sirupsen/logrus"
 	"golang.org/x/net/context"
+	"golang.org/x/time/rate"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/connectivity"
+	"google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/keepalive"
 	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
 
 	"github.com/ernoaapa/eliot/pkg/api/mapping"
 	containers "github.com/ernoaapa/eliot/pkg/api/services/containers/v1"
@@ -18,36 +28,188 @@ import (
 	pods "github.com/ernoaapa/eliot/pkg/api/services/pods/v1"
 	"github.com/ernoaapa/eliot/pkg/api/stream"
 	"github.com/ernoaapa/eliot/pkg/config"
+	"github.com/ernoaapa/eliot/pkg/model"
 	"github.com/ernoaapa/eliot/pkg/progress"
+	"github.com/ernoaapa/eliot/pkg/term"
+	"github.com/pkg/errors"
 	"github.com/rs/xid"
 )
 
 // Client connects directly to node RPC API
 type Client struct {
 	Namespace string
-	Endpoint  config.Endpoint
-	ctx       context.Context
+	// Endpoint is the server given to NewClient, with WithServers use ActiveEndpoint to get the currently connected server
+	Endpoint        config.Endpoint
+	transport       grpc.DialOption
+	dialOpts        []grpc.DialOption
+	retry           retryPolicy
+	dialTimeout     time.Duration
+	keepalive       keepalive.ClientParameters
+	compression     *compression
+	callOptions     callOptions
+	progressHandler func(ImageFetchProgress)
+	progressWriter  io.Writer
+	idempotencyKey  string
+	metadata        metadata.MD
+	perCallMetadata func(ctx context.Context) metadata.MD
+	dryRun          bool
+	logger          *log.Entry
+	metrics         MetricsRecorder
+	limiter         *rate.Limiter
+
+	servers []config.Endpoint
+	pool    *connectionPool
+	// connStateHandler gets called on state changes of every connection in the pool
+	connStateHandler func(connectivity.State)
+	// waitForContainer is how long AttachToContainer waits the container to start, zero means don't wait
+	waitForContainer time.Duration
+	// userAgent identifies the client build in the server logs
+	userAgent string
+	// unaryInterceptors and streamInterceptors are the WithUnaryInterceptors and WithStreamInterceptors chains
+	unaryInterceptors  []grpc.UnaryClientInterceptor
+	streamInterceptors []grpc.StreamClientInterceptor
 }
 
 // NewClient creates new RPC server client
-func NewClient(namespace string, endpoint config.Endpoint) *Client {
-	return &Client{
-		namespace,
-		endpoint,
-		context.Background(),
+// You must give either WithTLS or WithInsecure option to define the transport security.
+// The connection to the server is opened on first call and reused by all following calls, see WithConnectionPool
+// to use multiple connections.
+// If alternative servers are given with WithServers, ActiveEndpoint returns the currently connected server.
+func NewClient(namespace string, endpoint config.Endpoint, opts ...ClientOpts) (*Client, error) {
+	client := &Client{
+		Namespace: namespace,
+		Endpoint:  endpoint,
+		servers:   []config.Endpoint{endpoint},
+		pool:      newConnectionPool(),
+		logger:    discardLogger,
+		keepalive: defaultKeepalive(),
+		userAgent: UserAgent("unknown"),
+	}
+	for _, o := range opts {
+		if err := o(client); err != nil {
+			return nil, err
+		}
+	}
+	client.retry.logger = client.logger
+	return client, nil
+}
+
+// IsDryRun return true if the client is created with WithDryRun option
+func (c *Client) IsDryRun() bool {
+	return c.dryRun
+}
+
+// WithNamespace returns copy of the client which operates in the given namespace.
+// The copy shares the connection with the original client, so closing one of them closes the connection of both.
+func (c *Client) WithNamespace(namespace string) *Client {
+	client := *c
+	client.Namespace = namespace
+	return &client
+}
+
+// getConnection returns connection from the pool and dials it on first use.
+// The connection is used to create the service client, the calls themselves get connection from the pool
+// in the interceptors, so the returned connection can be shared by any number of calls.
+// Failed dial is not cached so next call will try to connect again.
+func (c *Client) getConnection() (*grpc.ClientConn, error) {
+	pc, err := c.acquireConnection()
+	if err != nil {
+		return nil, err
+	}
+	c.releaseConnection(pc)
+	return pc.conn, nil
+}
+
+// dial opens new connection to the server, or with multiple servers, to the first reachable one.
+// Must be called without holding the pool lock because it can block until the dial timeout.
+func (c *Client) dial() (*grpc.ClientConn, error) {
+	if c.transport == nil {
+		return nil, fmt.Errorf("No transport security defined for connection to [%s], you must use WithTLS or WithInsecure option", c.ActiveEndpoint().URL)
+	}
+
+	opts := append([]grpc.DialOption{
+		c.transport,
+		clientKeepalive(c.keepalive),
+		grpc.WithUserAgent(c.userAgent),
+		grpc.WithUnaryInterceptor(c.unaryInterceptor),
+		grpc.WithStreamInterceptor(c.streamInterceptor),
+	}, c.dialOpts...)
+	if len(c.servers) > 1 {
+		return c.dialFailover(opts)
+	}
+
+	if c.dialTimeout > 0 {
+		return dialWithTimeout(c.Endpoint, c.dialTimeout, opts)
+	}
+
+	return grpc.Dial(c.Endpoint.GetAddress(), opts...)
+}
+
+// dialWithTimeout blocks until the connection to the server is up or the timeout expires
+func dialWithTimeout(endpoint config.Endpoint, timeout time.Duration, opts []grpc.DialOption) (*grpc.ClientConn, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	conn, err := grpc.DialContext(ctx, endpoint.GetAddress(), append(opts, grpc.WithBlock())...)
+	if err != nil {
+		return nil, &Error{
+			Code:    codes.Unavailable,
+			Message: fmt.Sprintf("Could not connect to [%s] within %s", endpoint.URL, timeout),
+			cause:   err,
+		}
+	}
+	return conn, nil
+}
+
+// Close releases all connections to the server, calls in progress get cancelled.
+// Client can still be used after Close, the next call opens new connection.
+func (c *Client) Close() error {
+	return c.pool.closeAll()
+}
+
+// Ping checks with the standard gRPC health check that the server is reachable and serving.
+// Use it to fail fast with clear error before starting long running calls like Attach.
+func (c *Client) Ping(ctx context.Context) error {
+	conn, err := c.getConnection()
+	if err != nil {
+		return err
+	}
+
+	client := grpc_health_v1.NewHealthClient(conn)
+	resp, err := client.Check(ctx, &grpc_health_v1.HealthCheckRequest{}, grpc.FailFast(false))
+	if err != nil {
+		if status.Code(err) == codes.Unimplemented {
+			// Older server without health service, but it answered so it's reachable
+			return nil
+		}
+		return &Error{
+			Code:    codes.Unavailable,
+			Message: fmt.Sprintf("Server at [%s] is not reachable: %s", c.ActiveEndpoint().URL, translateError(err)),
+		}
 	}
+
+	if resp.Status != grpc_health_v1.HealthCheckResponse_SERVING {
+		return &Error{
+			Code:    codes.Unavailable,
+			Message: fmt.Sprintf("Server at [%s] is not serving (status %s)", c.ActiveEndpoint().URL, resp.Status),
+		}
+	}
+	return nil
 }
 
 // GetInfo calls server and get node info
-func (c *Client) GetInfo() (*node.Info, error) {
-	conn, err := grpc.Dial(c.Endpoint.URL, grpc.WithInsecure())
+func (c *Client) GetInfo(ctx context.Context) (*node.Info, error) {
+	conn, err := c.getConnection()
 	if err != nil {
 		return nil, err
 	}
-	defer conn.Close()
 
 	client := node.NewNodeClient(conn)
-	resp, err := client.Info(c.ctx, &node.InfoRequest{})
+	var resp *node.InfoResponse
+	err = c.retry.do(ctx, func() (err error) {
+		resp, err = client.Info(ctx, &node.InfoRequest{})
+		return translateError(err)
+	})
 	if err != nil {
 		return nil, err
 	}
@@ -55,17 +217,76 @@ func (c *Client) GetInfo() (*node.Info, error) {
 	return resp.GetInfo(), nil
 }
 
+// GetVersion return the server, API and container runtime versions of the node
+func (c *Client) GetVersion(ctx context.Context) (*VersionInfo, error) {
+	info, err := c.GetInfo(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return MapVersionInfo(info), nil
+}
+
+// ListNamespaces return names of all namespaces in the node
+func (c *Client) ListNamespaces(ctx context.Context) ([]string, error) {
+	conn, err := c.getConnection()
+	if err != nil {
+		return nil, err
+	}
+
+	client := pods.NewPodsClient(conn)
+	var resp *pods.ListNamespacesResponse
+	err = c.retry.do(ctx, func() (err error) {
+		resp, err = client.ListNamespaces(ctx, &pods.ListNamespacesRequest{})
+		return translateError(err)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return resp.GetNamespaces(), nil
+}
+
+// CreateNamespace creates new namespace in the node.
+// Returns error with AlreadyExists code if the namespace already exist.
+func (c *Client) CreateNamespace(ctx context.Context, name string) error {
+	conn, err := c.getConnection()
+	if err != nil {
+		return err
+	}
+
+	client := pods.NewPodsClient(conn)
+	_, err = client.CreateNamespace(ctx, &pods.CreateNamespaceRequest{
+		Name: name,
+	})
+	return translateError(err)
+}
+
 // GetPods calls server and fetches all pods information
-func (c *Client) GetPods() ([]*pods.Pod, error) {
-	conn, err := grpc.Dial(c.Endpoint.URL, grpc.WithInsecure())
+func (c *Client) GetPods(ctx context.Context) ([]*pods.Pod, error) {
+	return c.GetPodsBySelector(ctx, "")
+}
+
+// GetPodsByLabel fetches pods which labels contain all the selector labels and values
+func (c *Client) GetPodsByLabel(ctx context.Context, selector map[string]string) ([]*pods.Pod, error) {
+	return c.GetPodsBySelector(ctx, model.SelectorFromMap(selector).String())
+}
+
+// GetPodsBySelector fetches pods matching to the label selector, e.g. "app=nginx,env!=dev,tier".
+// See model.ParseSelector for the syntax.
+func (c *Client) GetPodsBySelector(ctx context.Context, selector string) ([]*pods.Pod, error) {
+	conn, err := c.getConnection()
 	if err != nil {
 		return nil, err
 	}
-	defer conn.Close()
 
 	client := pods.NewPodsClient(conn)
-	resp, err := client.List(c.ctx, &pods.ListPodsRequest{
-		Namespace: c.Namespace,
+	var resp *pods.ListPodsResponse
+	err = c.retry.do(ctx, func() (err error) {
+		resp, err = client.List(ctx, &pods.ListPodsRequest{
+			Namespace:     c.Namespace,
+			LabelSelector: selector,
+		})
+		return translateError(err)
 	})
 	if err != nil {
 		return nil, err
@@ -74,23 +295,96 @@ func (c *Client) GetPods() ([]*pods.Pod, error) {
 	return resp.GetPods(), nil
 }
 
-// GetPod return Pod by name
-func (c *Client) GetPod(podName string) (*pods.Pod, error) {
-	pods, err := c.GetPods()
+// defaultPageSize is the page size ForEachPod uses to fetch the pods
+const defaultPageSize = 100
+
+// GetPodsPage fetches single page of pods sorted by name.
+// Give empty pageToken to get the first page and the returned nextToken to get the next page.
+// The nextToken is empty when there's no more pods.
+func (c *Client) GetPodsPage(ctx context.Context, pageSize int, pageToken string) (result []*pods.Pod, nextToken string, err error) {
+	conn, err := c.getConnection()
+	if err != nil {
+		return nil, "", err
+	}
+
+	client := pods.NewPodsClient(conn)
+	var resp *pods.ListPodsResponse
+	err = c.retry.do(ctx, func() (err error) {
+		resp, err = client.List(ctx, &pods.ListPodsRequest{
+			Namespace: c.Namespace,
+			PageSize:  int32(pageSize),
+			PageToken: pageToken,
+		})
+		return translateError(err)
+	})
+	if err != nil {
+		return nil, "", err
+	}
+
+	return resp.GetPods(), resp.GetNextPageToken(), nil
+}
+
+// ForEachPod calls the function for each pod, fetching the pods page by page.
+// Stops and returns the error if the function returns error.
+func (c *Client) ForEachPod(ctx context.Context, fn func(*pods.Pod) error) error {
+	pageToken := ""
+	for {
+		page, nextToken, err := c.GetPodsPage(ctx, defaultPageSize, pageToken)
+		if err != nil {
+			return err
+		}
+
+		for _, pod := range page {
+			if err := fn(pod); err != nil {
+				return err
+			}
+		}
+
+		if nextToken == "" {
+			return nil
+		}
+		pageToken = nextToken
+	}
+}
+
+// GetPod return Pod by name. With WithContainerStatus option, the container statuses get replaced
+// with the live status from the container runtime.
+func (c *Client) GetPod(ctx context.Context, podName string, opts ...GetOpts) (*pods.Pod, error) {
+	options := &getOptions{}
+	for _, opt := range opts {
+		opt(options)
+	}
+
+	pods, err := c.GetPods(ctx)
 	if err != nil {
 		return nil, err
 	}
 
 	for _, pod := range pods {
 		if pod.Metadata.Name == podName {
+			if options.containerStatus {
+				c.fetchContainerStatuses(ctx, pod)
+			}
 			return pod, nil
 		}
 	}
-	return nil, fmt.Errorf("Pod with name [%s] not found", podName)
+	return nil, &Error{
+		Code:    codes.NotFound,
+		Message: fmt.Sprintf("Pod with name [%s] not found", podName),
+		cause:   ErrPodNotFound,
+	}
 }
 
 // CreatePod creates new pod to the node
-func (c *Client) CreatePod(status chan<- []*progress.ImageFetch, pod *pods.Pod, opts ...PodOpts) error {
+// The image pull progress is sent to the status channel, to the WithProgressHandler handler
+// and printed as plain lines to the WithProgressWriter writer.
+// The status channel can be nil if the progress is not needed or handled with the handler.
+// The pod is validated with ValidatePod before sending it to the server.
+// In dry run mode (WithDryRun), nothing is created and the pod gets updated to the one the server would create.
+// Cancelling the context aborts the image pull in the server, which removes the partially downloaded layers,
+// and returns ErrCanceled or ErrDeadlineExceeded. The WithProgressWriter writer gets "Cancelled" line for each
+// image which was not pulled yet.
+func (c *Client) CreatePod(ctx context.Context, status chan<- []*progress.ImageFetch, pod *pods.Pod, opts ...PodOpts) error {
 	for _, o := range opts {
 		err := o(pod)
 		if err != nil {
@@ -98,18 +392,150 @@ func (c *Client) CreatePod(status chan<- []*progress.ImageFetch, pod *pods.Pod,
 		}
 	}
 
-	conn, err := grpc.Dial(c.Endpoint.URL, grpc.WithInsecure())
+	if err := ValidatePod(pod); err != nil {
+		return err
+	}
+
+	var writer *progress.Writer
+	if c.progressWriter != nil {
+		writer = progress.NewWriter(c.progressWriter)
+	}
+
+	err := c.createPod(ctx, pod, func(images ImageFetchProgress) {
+		if writer != nil {
+			writer.Update(images)
+		}
+		if c.progressHandler != nil {
+			c.progressHandler(images)
+		}
+		if status != nil {
+			status <- images
+		}
+	})
+	if writer != nil && ctx.Err() != nil {
+		writer.Cancel()
+	}
+	return err
+}
+
+// createPodsConcurrency is how many pods CreatePods creates at the same time
+const createPodsConcurrency = 4
+
+// CreatePods creates multiple pods to the node, at most createPodsConcurrency pods at the time.
+// The image pull progress of all pods is sent combined to the status channel, which can be nil,
+// and printed to the WithProgressWriter writer.
+// All pods are validated before creating any of them.
+// If some of the pods fail, returns CreatePodsError which tells which pods were created and which failed.
+func (c *Client) CreatePods(ctx context.Context, status chan<- PodsImageFetchProgress, podList []*pods.Pod, opts ...PodOpts) error {
+	names := map[string]bool{}
+	for _, pod := range podList {
+		for _, o := range opts {
+			if err := o(pod); err != nil {
+				return err
+			}
+		}
+		if err := ValidatePod(pod); err != nil {
+			return err
+		}
+		name := pod.GetMetadata().GetName()
+		if names[name] {
+			return &ValidationError{Problems: []string{fmt.Sprintf("pod name [%s] is defined more than once", name)}}
+		}
+		names[name] = true
+	}
+
+	var (
+		mu      sync.Mutex
+		wg      sync.WaitGroup
+		result  = &CreatePodsError{Failed: map[string]error{}}
+		fetches = map[string]ImageFetchProgress{}
+		writers = map[string]*progress.Writer{}
+		slots   = make(chan struct{}, createPodsConcurrency)
+		// sendMu keeps the progress updates in order without holding mu while the receiver is busy
+		sendMu sync.Mutex
+		report = func(name string, images ImageFetchProgress) {
+			mu.Lock()
+			fetches[name] = images
+			if c.progressWriter != nil {
+				if _, ok := writers[name]; !ok {
+					writers[name] = progress.NewWriter(c.progressWriter)
+				}
+				writers[name].Update(images)
+			}
+			if status == nil {
+				mu.Unlock()
+				return
+			}
+			snapshot := combineImageFetchProgress(podList, fetches)
+			sendMu.Lock()
+			mu.Unlock()
+			defer sendMu.Unlock()
+			status <- snapshot
+		}
+	)
+
+	for _, pod := range podList {
+		wg.Add(1)
+		go func(pod *pods.Pod) {
+			defer wg.Done()
+			slots <- struct{}{}
+			defer func() { <-slots }()
+
+			name := pod.GetMetadata().GetName()
+			err := c.createPod(ctx, pod, func(images ImageFetchProgress) {
+				report(name, images)
+			})
+
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				result.Failed[name] = err
+			} else {
+				result.Created = append(result.Created, name)
+			}
+		}(pod)
+	}
+	wg.Wait()
+
+	if ctx.Err() != nil {
+		for _, writer := range writers {
+			writer.Cancel()
+		}
+	}
+	if len(result.Failed) > 0 {
+		sort.Strings(result.Created)
+		return result
+	}
+	return nil
+}
+
+// createPod sends the create with idempotency key, so that it can be retried safely when the server is unavailable.
+// Without WithIdempotencyKey, new key is generated for each create.
+func (c *Client) createPod(ctx context.Context, pod *pods.Pod, onProgress func(ImageFetchProgress)) error {
+	key := c.idempotencyKey
+	if key == "" {
+		key = xid.New().String()
+	}
+	ctx = metadata.AppendToOutgoingContext(ctx, idempotencyKeyMetadata, key)
+
+	return c.retry.do(ctx, func() error {
+		return c.sendCreatePod(ctx, pod, onProgress)
+	})
+}
+
+func (c *Client) sendCreatePod(ctx context.Context, pod *pods.Pod, onProgress func(ImageFetchProgress)) error {
+	conn, err := c.getConnection()
 	if err != nil {
 		return err
 	}
-	defer conn.Close()
 
 	client := pods.NewPodsClient(conn)
-	stream, err := client.Create(c.ctx, &pods.CreatePodRequest{
-		Pod: pod,
+	stream, err := client.Create(ctx, &pods.CreatePodRequest{
+		Pod:    pod,
+		DryRun: c.dryRun,
 	})
 	if err != nil {
-		return err
+		return translateError(err)
 	}
 
 	for {
@@ -119,25 +545,49 @@ func (c *Client) CreatePod(status chan<- []*progress.ImageFetch, pod *pods.Pod,
 			return err
 		}
 		if err != nil {
-			return err
+			if ctx.Err() != nil {
+				// The stream get cancelled with the context, which aborts the pull in the server
+				return translateError(ctx.Err())
+			}
+			return translateError(err)
+		}
+
+		if resp.Pod != nil {
+			// Dry run, update the pod to what the server would create
+			*pod = *resp.Pod
+			continue
 		}
+		onProgress(mapping.MapAPIModelToImageFetchProgress(resp.Images))
+	}
+}
 
-		status <- mapping.MapAPIModelToImageFetchProgress(resp.Images)
+// combineImageFetchProgress return the progress of all pods in the pod list order
+func combineImageFetchProgress(podList []*pods.Pod, fetches map[string]ImageFetchProgress) PodsImageFetchProgress {
+	result := PodsImageFetchProgress{}
+	for _, pod := range podList {
+		name := pod.GetMetadata().GetName()
+		for _, fetch := range fetches[name] {
+			result = append(result, &PodImageFetch{Pod: name, ImageFetch: fetch})
+		}
 	}
+	return result
 }
 
 // StartPod starts created pod in node
-func (c *Client) StartPod(name string) (*pods.Pod, error) {
-	conn, err := grpc.Dial(c.Endpoint.URL, grpc.WithInsecure())
+func (c *Client) StartPod(ctx context.Context, name string) (*pods.Pod, error) {
+	conn, err := c.getConnection()
 	if err != nil {
 		return nil, err
 	}
-	defer conn.Close()
 
 	client := pods.NewPodsClient(conn)
-	resp, err := client.Start(c.ctx, &pods.StartPodRequest{
-		Namespace: c.Namespace,
-		Name:      name,
+	var resp *pods.StartPodResponse
+	err = c.retry.do(ctx, func() (err error) {
+		resp, err = client.Start(ctx, &pods.StartPodRequest{
+			Namespace: c.Namespace,
+			Name:      name,
+		})
+		return translateError(err)
 	})
 	if err != nil {
 		return nil, err
@@ -147,135 +597,1043 @@ func (c *Client) StartPod(name string) (*pods.Pod, error) {
 }
 
 // DeletePod removes pod from the node
-func (c *Client) DeletePod(pod *pods.Pod) (*pods.Pod, error) {
-	conn, err := grpc.Dial(c.Endpoint.URL, grpc.WithInsecure())
+// Containers get SIGTERM and DefaultGracePeriod time to exit before they get killed, use WithGracePeriod to change it.
+// In dry run mode (WithDryRun), nothing is deleted and the pod what would be deleted is returned.
+// Use DeletePodWithResult to see the volumes removed with WithCascade.
+func (c *Client) DeletePod(ctx context.Context, pod *pods.Pod, opts ...DeleteOpts) (*pods.Pod, error) {
+	result, err := c.DeletePodWithResult(ctx, pod, opts...)
 	if err != nil {
 		return nil, err
 	}
-	defer conn.Close()
+	return result.Pod, nil
+}
 
-	client := pods.NewPodsClient(conn)
+// DeleteResult tells what DeletePodWithResult removed
+type DeleteResult struct {
+	// Pod is the deleted pod
+	Pod *pods.Pod
+	// RemovedVolumes are the paths of the removed pod volumes, with WithCascade
+	RemovedVolumes []string
+	// RetainedVolumes are the pod volumes which were not removed because other pods still mount them,
+	// by the path, each with the pods as namespace/name
+	RetainedVolumes map[string][]string
+}
 
-	resp, err := client.Delete(c.ctx, &pods.DeletePodRequest{
-		Namespace: pod.Metadata.Namespace,
-		Name:      pod.Metadata.Name,
-	})
+// DeletePodWithResult is like DeletePod, but return also the pod volumes which got removed or retained with WithCascade.
+// In dry run mode, the result tells what would be removed.
+func (c *Client) DeletePodWithResult(ctx context.Context, pod *pods.Pod, opts ...DeleteOpts) (*DeleteResult, error) {
+	req := &pods.DeletePodRequest{
+		Namespace:   pod.Metadata.Namespace,
+		Name:        pod.Metadata.Name,
+		GracePeriod: int64(DefaultGracePeriod),
+		DryRun:      c.dryRun,
+	}
+	for _, o := range opts {
+		if err := o(req); err != nil {
+			return nil, err
+		}
+	}
+
+	conn, err := c.getConnection()
 	if err != nil {
 		return nil, err
 	}
-	return resp.GetPod(), nil
-}
-
-// Attach hooks to container main process stdin/stout
-func (c *Client) Attach(containerID string, attachIO AttachIO, hooks ...AttachHooks) (err error) {
-	done := make(chan struct{})
-	errc := make(chan error)
 
-	md := metadata.Pairs(
-		"namespace", c.Namespace,
-		"container", containerID,
-	)
-	ctx, cancel := context.WithCancel(metadata.NewOutgoingContext(c.ctx, md))
-	defer cancel()
+	client := pods.NewPodsClient(conn)
 
-	conn, err := grpc.Dial(c.Endpoint.URL, grpc.WithInsecure())
+	resp, err := client.Delete(ctx, req)
 	if err != nil {
-		return err
+		return nil, translateError(err)
 	}
-	defer conn.Close()
 
-	client := containers.NewContainersClient(conn)
-	log.Debugf("Open connection to server to start stdin/stdout streaming")
-	s, err := client.Attach(ctx)
+	result := &DeleteResult{
+		Pod:             resp.GetPod(),
+		RemovedVolumes:  resp.GetRemovedVolumes(),
+		RetainedVolumes: map[string][]string{},
+	}
+	for _, volume := range resp.GetRetainedVolumes() {
+		result.RetainedVolumes[volume.GetPath()] = volume.GetPods()
+	}
+	return result, nil
+}
+
+// deletePodsConcurrency is how many pods DeletePodsByLabel deletes at the same time
+const deletePodsConcurrency = 4
+
+// DeletePodsByLabel deletes all pods which labels contain all the selector labels and values,
+// at most deletePodsConcurrency pods at the time. Returns the deleted pods sorted by name.
+// Empty selector is rejected with ErrInvalidArgument, so that a bug in the caller cannot delete every pod in the namespace.
+// If some of the pods fail, the rest still get deleted and DeletePodsError tells which pods failed.
+func (c *Client) DeletePodsByLabel(ctx context.Context, selector map[string]string, opts ...DeleteOpts) ([]*pods.Pod, error) {
+	if len(selector) == 0 {
+		return nil, &Error{Code: codes.InvalidArgument, Message: "Refusing to delete pods with empty label selector, it would delete all pods"}
+	}
+
+	matching, err := c.GetPodsByLabel(ctx, selector)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	go func() {
-		errc <- stream.PipeStdout(s, attachIO.Stdout, attachIO.Stderr)
-	}()
+	var (
+		mu      sync.Mutex
+		wg      sync.WaitGroup
+		deleted = []*pods.Pod{}
+		result  = &DeletePodsError{Failed: map[string]error{}}
+		slots   = make(chan struct{}, deletePodsConcurrency)
+	)
+	for _, pod := range matching {
+		wg.Add(1)
+		go func(pod *pods.Pod) {
+			defer wg.Done()
+			slots <- struct{}{}
+			defer func() { <-slots }()
 
-	if attachIO.Stdin != nil {
-		go func() {
-			errc <- stream.PipeStdin(s, attachIO.Stdin)
-		}()
+			resp, err := c.DeletePod(ctx, pod, opts...)
+
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				result.Failed[pod.GetMetadata().GetName()] = err
+			} else {
+				deleted = append(deleted, resp)
+				result.Deleted = append(result.Deleted, pod.GetMetadata().GetName())
+			}
+		}(pod)
 	}
+	wg.Wait()
 
-	for _, hook := range hooks {
-		go hook(c.Endpoint, done)
+	sort.Slice(deleted, func(i, j int) bool {
+		return deleted[i].GetMetadata().GetName() < deleted[j].GetMetadata().GetName()
+	})
+	if len(result.Failed) > 0 {
+		sort.Strings(result.Deleted)
+		return deleted, result
 	}
+	return deleted, nil
+}
 
-	for {
-		err := <-errc
-		close(done)
-		return err
+// RestartPod stops and starts again all containers in the pod.
+// Containers get SIGTERM and DefaultGracePeriod time to exit before they get killed.
+// The pod name, labels and spec stay the same, but the containers get new IDs.
+func (c *Client) RestartPod(ctx context.Context, name string) (*pods.Pod, error) {
+	conn, err := c.getConnection()
+	if err != nil {
+		return nil, err
+	}
+
+	client := pods.NewPodsClient(conn)
+	resp, err := client.Restart(ctx, &pods.RestartPodRequest{
+		Namespace:   c.Namespace,
+		Name:        name,
+		GracePeriod: int64(DefaultGracePeriod),
+	})
+	if err != nil {
+		return nil, translateError(err)
 	}
+	return resp.GetPod(), nil
 }
 
-// Exec executes command inside some container
-func (c *Client) Exec(containerID string, args []string, tty bool, attachIO AttachIO, hooks ...AttachHooks) (err error) {
-	done := make(chan struct{})
-	errc := make(chan error)
+// RemoveLabel is label value which removes the label in UpdatePodLabels merge mode
+const RemoveLabel = "\x00"
 
-	md := metadata.Pairs(
-		"namespace", c.Namespace,
-		"container", containerID,
-		"execid", xid.New().String(),
-		"args", strings.Join(args, " "),
-		"tty", strconv.FormatBool(tty),
-	)
-	ctx, cancel := context.WithCancel(metadata.NewOutgoingContext(c.ctx, md))
-	defer cancel()
+// UpdatePodLabels changes the pod labels without recreating the containers.
+// With merge, the labels are added to the existing labels and labels with RemoveLabel value get removed.
+// Without merge, the labels replace all existing labels.
+func (c *Client) UpdatePodLabels(ctx context.Context, name string, labels map[string]string, merge bool) (*pods.Pod, error) {
+	req := &pods.UpdatePodLabelsRequest{
+		Namespace: c.Namespace,
+		Name:      name,
+		Labels:    map[string]string{},
+		Merge:     merge,
+	}
+	for key, value := range labels {
+		if value == RemoveLabel {
+			req.Remove = append(req.Remove, key)
+			continue
+		}
+		req.Labels[key] = value
+	}
 
-	conn, err := grpc.Dial(c.Endpoint.URL, grpc.WithInsecure())
+	conn, err := c.getConnection()
 	if err != nil {
-		return err
+		return nil, err
 	}
-	defer conn.Close()
 
-	client := containers.NewContainersClient(conn)
-	log.Debugf("Open connection to server to start stdin/stdout streaming")
-	s, err := client.Exec(ctx)
+	client := pods.NewPodsClient(conn)
+	resp, err := client.UpdateLabels(ctx, req)
 	if err != nil {
-		return err
+		return nil, translateError(err)
 	}
+	return resp.GetPod(), nil
+}
 
-	go func() {
-		errc <- stream.PipeStdout(s, attachIO.Stdout, attachIO.Stderr)
-	}()
-
-	if attachIO.Stdin != nil {
-		go func() {
-			errc <- stream.PipeStdin(s, attachIO.Stdin)
-		}()
+// RenamePod changes the pod name without recreating the containers.
+// Returns ErrAlreadyExists if pod with the new name already exist.
+func (c *Client) RenamePod(ctx context.Context, name, newName string) (*pods.Pod, error) {
+	if problem := validatePodName(newName); problem != "" {
+		return nil, &ValidationError{Problems: []string{problem}}
 	}
 
-	for _, hook := range hooks {
-		go hook(c.Endpoint, done)
+	conn, err := c.getConnection()
+	if err != nil {
+		return nil, err
 	}
 
-	for {
-		err := <-errc
-		close(done)
-		return err
+	client := pods.NewPodsClient(conn)
+	resp, err := client.Rename(ctx, &pods.RenamePodRequest{
+		Namespace: c.Namespace,
+		Name:      name,
+		NewName:   newName,
+	})
+	if err != nil {
+		return nil, translateError(err)
 	}
+	return resp.GetPod(), nil
 }
 
-// Signal sends kill signal to container process
-func (c *Client) Signal(containerID string, signal syscall.Signal) (err error) {
-	conn, err := grpc.Dial(c.Endpoint.URL, grpc.WithInsecure())
+// CordonPod stops the node from restarting the pod containers, e.g. to keep crash looping container stopped
+// while debugging it. The pod spec is kept and the running containers keep running.
+// The containers which get recreated, e.g. by UpdatePod, stay cordoned. GetPod status tells if the pod is cordoned.
+func (c *Client) CordonPod(ctx context.Context, name string) (*pods.Pod, error) {
+	return c.setPodCordoned(ctx, name, true)
+}
+
+// UncordonPod resumes restarting the pod containers by the restart policy
+func (c *Client) UncordonPod(ctx context.Context, name string) (*pods.Pod, error) {
+	return c.setPodCordoned(ctx, name, false)
+}
+
+func (c *Client) setPodCordoned(ctx context.Context, name string, cordoned bool) (*pods.Pod, error) {
+	conn, err := c.getConnection()
 	if err != nil {
-		return err
+		return nil, err
 	}
-	defer conn.Close()
-
-	client := containers.NewContainersClient(conn)
 
-	_, err = client.Signal(c.ctx, &containers.SignalRequest{
-		Namespace:   c.Namespace,
-		ContainerID: containerID,
-		Signal:      int32(signal),
+	client := pods.NewPodsClient(conn)
+	resp, err := client.Cordon(ctx, &pods.CordonPodRequest{
+		Namespace: c.Namespace,
+		Name:      name,
+		Cordoned:  cordoned,
 	})
+	if err != nil {
+		return nil, translateError(err)
+	}
+	return resp.GetPod(), nil
+}
 
-	return err
+// UpdatePod updates the pod spec in node without deleting the pod.
+// Only the containers which spec have changed get recreated, others keep running.
+// The response tells which containers were added, removed or restarted.
+// If the pod metadata has ResourceVersion, e.g. the pod is modified version of the GetPod result,
+// returns ErrPodVersionConflict when the pod has been changed since.
+func (c *Client) UpdatePod(ctx context.Context, pod *pods.Pod, opts ...PodOpts) (*pods.UpdatePodResponse, error) {
+	for _, o := range opts {
+		if err := o(pod); err != nil {
+			return nil, err
+		}
+	}
+
+	conn, err := c.getConnection()
+	if err != nil {
+		return nil, err
+	}
+
+	client := pods.NewPodsClient(conn)
+	resp, err := client.Update(ctx, &pods.UpdatePodRequest{
+		Pod: pod,
+	})
+	if err != nil {
+		return nil, translateUpdateError(err)
+	}
+	return resp, nil
+}
+
+func translateUpdateError(err error) error {
+	err = translateError(err)
+	if e, ok := err.(*Error); ok && e.Code == codes.Aborted {
+		return &Error{Code: e.Code, Message: e.Message, cause: ErrPodVersionConflict}
+	}
+	return err
+}
+
+// WatchPods streams pod changes in the namespace. Empty namespace means the client namespace.
+// First there's Added event for each existing pod, then event for each change.
+// The channel get closed when the context is cancelled or the server closes the stream.
+func (c *Client) WatchPods(ctx context.Context, namespace string) (<-chan PodEvent, error) {
+	if namespace == "" {
+		namespace = c.Namespace
+	}
+
+	conn, err := c.getConnection()
+	if err != nil {
+		return nil, err
+	}
+
+	client := pods.NewPodsClient(conn)
+	stream, err := client.Watch(ctx, &pods.WatchPodsRequest{
+		Namespace: namespace,
+	})
+	if err != nil {
+		return nil, translateError(err)
+	}
+
+	events := make(chan PodEvent)
+	go func() {
+		defer close(events)
+		for {
+			resp, err := stream.Recv()
+			if err != nil {
+				if err != io.EOF && ctx.Err() == nil {
+					c.logger.Warnf("Pod watch stream closed with error: %s", translateError(err))
+				}
+				return
+			}
+
+			select {
+			case events <- PodEvent{Type: PodEventType(resp.Type), Pod: resp.Pod}:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return events, nil
+}
+
+// ContainerStats returns the container current CPU, memory and block IO usage.
+// Returns ErrContainerNotRunning if the container has exited.
+func (c *Client) ContainerStats(ctx context.Context, containerID string) (*Stats, error) {
+	conn, err := c.getConnection()
+	if err != nil {
+		return nil, err
+	}
+
+	client := containers.NewContainersClient(conn)
+	resp, err := client.Stats(ctx, &containers.StatsRequest{
+		Namespace:   c.Namespace,
+		ContainerID: containerID,
+	})
+	if err != nil {
+		return nil, translateStatsError(err)
+	}
+	return mapStats(resp.Stats), nil
+}
+
+// GetContainerStatus return the state, restart count and last exit of single container, without fetching
+// the whole pod like GetPod does. Use it e.g. in health check loop which polls the container often.
+// Returns ErrContainerNotFound if the container doesn't exist.
+func (c *Client) GetContainerStatus(ctx context.Context, containerID string) (*containers.ContainerStatus, error) {
+	conn, err := c.getConnection()
+	if err != nil {
+		return nil, err
+	}
+
+	client := containers.NewContainersClient(conn)
+	resp, err := client.Status(ctx, &containers.StatusRequest{
+		Namespace:   c.Namespace,
+		ContainerID: containerID,
+	})
+	if err != nil {
+		return nil, translateContainerError(err)
+	}
+	return resp.GetStatus(), nil
+}
+
+// translateContainerError translates the error of call by container ID, NotFound means the container doesn't exist
+func translateContainerError(err error) error {
+	err = translateError(err)
+	if e, ok := err.(*Error); ok && e.Code == codes.NotFound {
+		return &Error{Code: e.Code, Message: e.Message, cause: ErrContainerNotFound}
+	}
+	return err
+}
+
+// StreamStats sends the container resource usage to the channel periodically.
+// The channel get closed when the context is cancelled or the container stops.
+// Returns ErrContainerNotRunning if the container is not running.
+func (c *Client) StreamStats(ctx context.Context, containerID string) (<-chan *Stats, error) {
+	conn, err := c.getConnection()
+	if err != nil {
+		return nil, err
+	}
+
+	client := containers.NewContainersClient(conn)
+	stream, err := client.StreamStats(ctx, &containers.StatsRequest{
+		Namespace:   c.Namespace,
+		ContainerID: containerID,
+	})
+	if err != nil {
+		return nil, translateStatsError(err)
+	}
+
+	// Receive the first stats before returning so that not running container gets reported as error
+	first, err := stream.Recv()
+	if err != nil {
+		return nil, translateStatsError(err)
+	}
+
+	result := make(chan *Stats)
+	go func() {
+		defer close(result)
+		for resp := first; ; {
+			select {
+			case result <- mapStats(resp.Stats):
+			case <-ctx.Done():
+				return
+			}
+
+			resp, err = stream.Recv()
+			if err != nil {
+				if err != io.EOF && ctx.Err() == nil {
+					c.logger.Warnf("Container stats stream closed with error: %s", translateError(err))
+				}
+				return
+			}
+		}
+	}()
+	return result, nil
+}
+
+func translateStatsError(err error) error {
+	err = translateError(err)
+	if e, ok := err.(*Error); ok && e.Code == codes.FailedPrecondition {
+		return &Error{Code: e.Code, Message: e.Message, cause: ErrContainerNotRunning}
+	}
+	return err
+}
+
+func mapStats(stats *containers.ContainerStats) *Stats {
+	return &Stats{
+		Time:             time.Unix(0, stats.GetTime()),
+		CPUNanoseconds:   stats.GetCpuNanoseconds(),
+		MemoryUsageBytes: stats.GetMemoryUsageBytes(),
+		MemoryLimitBytes: stats.GetMemoryLimitBytes(),
+		BlkioReadBytes:   stats.GetBlkioReadBytes(),
+		BlkioWriteBytes:  stats.GetBlkioWriteBytes(),
+	}
+}
+
+// Top returns the processes running inside the container, sorted by the PID.
+// The container main process is marked with Init.
+// Returns ErrContainerNotRunning if the container has exited.
+func (c *Client) Top(ctx context.Context, containerID string) ([]Process, error) {
+	conn, err := c.getConnection()
+	if err != nil {
+		return nil, err
+	}
+
+	client := containers.NewContainersClient(conn)
+	resp, err := client.Top(ctx, &containers.TopRequest{
+		Namespace:   c.Namespace,
+		ContainerID: containerID,
+	})
+	if err != nil {
+		return nil, translateStatsError(err)
+	}
+	return mapProcesses(resp.GetProcesses()), nil
+}
+
+func mapProcesses(processes []*containers.Process) []Process {
+	result := []Process{}
+	for _, process := range processes {
+		result = append(result, Process{
+			PID:        process.GetPid(),
+			PPID:       process.GetPpid(),
+			HostPID:    process.GetHostPid(),
+			Command:    process.GetCommand(),
+			CPUPercent: process.GetCpuPercent(),
+			RSSBytes:   process.GetRssBytes(),
+			Init:       process.GetInit(),
+		})
+	}
+	return result
+}
+
+// AttachReadOnly hooks to container main process stdout and stderr only, e.g. to watch the output.
+// The container stdin is never opened and nothing is sent to the server, so the terminal input stays with the shell.
+// Returns when the container process exits or the context get cancelled.
+func (c *Client) AttachReadOnly(ctx context.Context, containerID string, stdout, stderr io.Writer, hooks ...AttachHooks) error {
+	return c.Attach(ctx, containerID, AttachIO{Stdout: stdout, Stderr: stderr}, hooks...)
+}
+
+// Attach hooks to container main process stdin/stout
+// Returns when the container process exits, stdin reading fails or the context get cancelled.
+// Note that blocking stdin Read cannot be interrupted, the stdin goroutine exits after the next Read returns.
+// If AttachIO Stdin is nil, the attach is read-only, see AttachReadOnly.
+// If AttachIO IdleTimeout is set, returns ErrAttachIdleTimeout when no data is sent or received within the timeout.
+// If AttachIO Stats is set, it counts the transferred bytes, also when Attach returns error.
+// Returns ErrDetached when the AttachIO DetachKeys sequence is read from stdin, by default Ctrl-P Ctrl-Q.
+func (c *Client) Attach(ctx context.Context, containerID string, attachIO AttachIO, hooks ...AttachHooks) (err error) {
+	done := make(chan struct{})
+	// Buffered so that the goroutines can always exit, even if Attach already returned
+	outc := make(chan error, 1)
+	inc := make(chan error, 1)
+
+	switch attachIO.Streams {
+	case AttachAllStreams:
+	case AttachStdoutOnly:
+		// Older servers send both streams anyway
+		attachIO.Stderr = ioutil.Discard
+	case AttachStderrOnly:
+		attachIO.Stdout = ioutil.Discard
+	default:
+		return &Error{Code: codes.InvalidArgument, Message: fmt.Sprintf("Invalid attach streams [%s], must be one of [stdout, stderr] or empty for both", attachIO.Streams)}
+	}
+
+	md := metadata.Pairs(
+		"namespace", c.Namespace,
+		"container", containerID,
+		"stdin", strconv.FormatBool(attachIO.Stdin != nil),
+		"streams", string(attachIO.Streams),
+	)
+	ctx, cancel := context.WithCancel(metadata.NewOutgoingContext(ctx, md))
+	defer cancel()
+
+	conn, err := c.getConnection()
+	if err != nil {
+		return err
+	}
+
+	client := containers.NewContainersClient(conn)
+	c.logger.Debugf("Open connection to server to start stdin/stdout streaming")
+	s, err := client.Attach(ctx)
+	if err != nil {
+		return translateError(err)
+	}
+
+	watcher := newIdleWatcher(attachIO.IdleTimeout)
+	stdout, stderr, flush := lineBuffered(attachIO.LineBuffered, attachIO.Stdout, attachIO.Stderr)
+	in, stdout, stderr := attachIO.Stats.wrap(attachIO.Stdin, stdout, stderr)
+	if in != nil {
+		in = stream.NewDetachReader(in, getDetachKeys(attachIO.DetachKeys))
+	}
+	go func() {
+		err := stream.PipeStdout(s, watcher.Writer(stdout), watcher.Writer(stderr))
+		flush()
+		outc <- err
+	}()
+
+	if in != nil {
+		stdin := stream.NewLockedStdinStream(s)
+		go c.pipeResize(stdin, attachIO.Resize, done)
+		go func() {
+			inc <- stream.PipeStdin(stdin, watcher.Reader(in), attachIO.StdinBufferSize)
+		}()
+	}
+
+	endpoint := c.ActiveEndpoint()
+	for _, hook := range hooks {
+		go hook(endpoint, done)
+	}
+	defer close(done)
+	idle := watcher.Watch(done)
+
+	for {
+		select {
+		case err := <-inc:
+			if err == stream.ErrDetached {
+				// Closing the stream right away could drop the detach message, the server ends the stream
+				// once it gets it. Older servers don't, so don't wait for ever.
+				select {
+				case <-outc:
+				case <-time.After(detachTimeout):
+				case <-ctx.Done():
+				}
+				return &Error{
+					Code:    codes.Canceled,
+					Message: fmt.Sprintf("Detached from container [%s]", containerID),
+					cause:   ErrDetached,
+				}
+			}
+			if err != nil {
+				return translateError(err)
+			}
+			// Stdin reached the end, keep reading output until the container exits
+		case err := <-outc:
+			return translateError(err)
+		case <-idle:
+			return &Error{
+				Code:    codes.DeadlineExceeded,
+				Message: fmt.Sprintf("No data sent or received within %s, closed the attach to container [%s]", attachIO.IdleTimeout, containerID),
+				cause:   ErrAttachIdleTimeout,
+			}
+		case <-ctx.Done():
+			return translateError(ctx.Err())
+		}
+	}
+}
+
+// Exec executes command inside some container and return the command exit code
+// If tty is true, stderr is merged into stdout because terminal have only single output stream
+func (c *Client) Exec(ctx context.Context, containerID string, args []string, tty bool, attachIO AttachIO, hooks ...AttachHooks) (exitCode int, err error) {
+	done := make(chan struct{})
+	outc := make(chan error, 1)
+	inc := make(chan error, 1)
+
+	md := metadata.Pairs(
+		"namespace", c.Namespace,
+		"container", containerID,
+		"execid", xid.New().String(),
+		"args", strings.Join(args, " "),
+		"tty", strconv.FormatBool(tty),
+	)
+	ctx, cancel := context.WithCancel(metadata.NewOutgoingContext(ctx, md))
+	defer cancel()
+
+	conn, err := c.getConnection()
+	if err != nil {
+		return -1, err
+	}
+
+	client := containers.NewContainersClient(conn)
+	c.logger.Debugf("Open connection to server to start stdin/stdout streaming")
+	s, err := client.Exec(ctx)
+	if err != nil {
+		return -1, translateError(err)
+	}
+
+	stderr := attachIO.Stderr
+	if tty {
+		stderr = attachIO.Stdout
+	}
+
+	stdout, stderr, flush := lineBuffered(attachIO.LineBuffered, attachIO.Stdout, stderr)
+	in, stdout, stderr := attachIO.Stats.wrap(attachIO.Stdin, stdout, stderr)
+	go func() {
+		err := stream.PipeStdout(s, stdout, stderr)
+		flush()
+		outc <- err
+	}()
+
+	if in != nil {
+		stdin := stream.NewLockedStdinStream(s)
+		go c.pipeResize(stdin, attachIO.Resize, done)
+		go func() {
+			inc <- stream.PipeStdin(stdin, in, attachIO.StdinBufferSize)
+		}()
+	}
+
+	endpoint := c.ActiveEndpoint()
+	for _, hook := range hooks {
+		go hook(endpoint, done)
+	}
+	defer close(done)
+
+	for {
+		select {
+		case err := <-inc:
+			if err != nil {
+				return -1, translateError(err)
+			}
+			// Stdin reached the end, keep reading output until the command exits
+		case err := <-outc:
+			if err != nil {
+				return -1, translateError(err)
+			}
+			return getExitCode(s.Trailer())
+		case <-ctx.Done():
+			return -1, translateError(ctx.Err())
+		}
+	}
+}
+
+// lineBuffered wraps the writers so that they write only complete lines, if enabled.
+// The flush writes the last lines which don't end with newline.
+func lineBuffered(enabled bool, stdout, stderr io.Writer) (io.Writer, io.Writer, func()) {
+	if !enabled {
+		return stdout, stderr, func() {}
+	}
+
+	stdoutLines, stderrLines := stream.NewLineWriter(stdout), stream.NewLineWriter(stderr)
+	return stdoutLines, stderrLines, func() {
+		stdoutLines.Flush()
+		stderrLines.Flush()
+	}
+}
+
+// pipeResize sends the terminal size changes to the stream, if the stdin is terminal
+func (c *Client) pipeResize(s stream.StdinStreamClient, sizes term.TerminalSizeQueue, done <-chan struct{}) {
+	if sizes == nil {
+		return
+	}
+	if err := stream.PipeResize(s, sizes, done); err != nil {
+		c.logger.Debugf("Stopped sending terminal size changes: %s", err)
+	}
+}
+
+// CopyToContainer extracts tar archive to the destination path in the container, like `docker cp`.
+// If the destination is existing directory, the archive content is extracted into it.
+// The destination parent directory must exist.
+func (c *Client) CopyToContainer(ctx context.Context, containerID, destPath string, r io.Reader) error {
+	return c.copyToContainer(ctx, containerID, destPath, r, nil)
+}
+
+// copyToContainer is CopyToContainer which first removes the paths relative to the destination path
+func (c *Client) copyToContainer(ctx context.Context, containerID, destPath string, r io.Reader, remove []string) error {
+	conn, err := c.getConnection()
+	if err != nil {
+		return err
+	}
+
+	client := containers.NewContainersClient(conn)
+	s, err := client.CopyTo(ctx)
+	if err != nil {
+		return translateError(err)
+	}
+
+	req := &containers.CopyToRequest{
+		Namespace:   c.Namespace,
+		ContainerID: containerID,
+		Path:        destPath,
+		Remove:      remove,
+	}
+	buf := make([]byte, copyChunkSize)
+	for {
+		n, readErr := r.Read(buf)
+		if n > 0 || req.Path != "" {
+			req.Data = buf[:n]
+			if err := s.Send(req); err != nil {
+				// Server closed the stream, the actual error is returned by CloseAndRecv
+				break
+			}
+			req = &containers.CopyToRequest{}
+		}
+		if readErr == io.EOF {
+			break
+		}
+		if readErr != nil {
+			return errors.Wrapf(readErr, "Failed to read archive to copy")
+		}
+	}
+
+	_, err = s.CloseAndRecv()
+	return translateError(err)
+}
+
+// CopyFromContainer writes tar archive of the source path in the container, like `docker cp`.
+// The source path itself is the top level entry in the archive.
+func (c *Client) CopyFromContainer(ctx context.Context, containerID, srcPath string, w io.Writer) error {
+	conn, err := c.getConnection()
+	if err != nil {
+		return err
+	}
+
+	client := containers.NewContainersClient(conn)
+	s, err := client.CopyFrom(ctx, &containers.CopyFromRequest{
+		Namespace:   c.Namespace,
+		ContainerID: containerID,
+		Path:        srcPath,
+	})
+	if err != nil {
+		return translateError(err)
+	}
+
+	for {
+		resp, err := s.Recv()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return translateError(err)
+		}
+		if _, err := w.Write(resp.Data); err != nil {
+			return errors.Wrapf(err, "Failed to write archive")
+		}
+	}
+}
+
+// Signal sends kill signal to container process
+func (c *Client) Signal(ctx context.Context, containerID string, signal syscall.Signal) (err error) {
+	conn, err := c.getConnection()
+	if err != nil {
+		return err
+	}
+
+	client := containers.NewContainersClient(conn)
+
+	_, err = client.Signal(ctx, &containers.SignalRequest{
+		Namespace:   c.Namespace,
+		ContainerID: containerID,
+		Signal:      int32(signal),
+	})
+
+	return translateError(err)
+}
+
+// SetContainerEnv sets the environment variables of the container, other variables are kept as is.
+// The change is persisted in the node, but the running process keeps the environment it was started with,
+// the new values take effect when the container gets started next time, e.g. restarted by the restart policy
+// or with RestartPod. The pod spec in the manifest is not changed, so updating the pod from the manifest
+// replaces the values. To make the container restart right away, use SetContainerEnvAndSignal.
+// Returns ErrInvalidArgument if some variable name is empty or contains '='.
+func (c *Client) SetContainerEnv(ctx context.Context, containerID string, env map[string]string) error {
+	return c.SetContainerEnvAndSignal(ctx, containerID, env, 0)
+}
+
+// SetContainerEnvAndSignal is like SetContainerEnv, but sends the signal to the container process after the update,
+// e.g. SIGTERM to stop the process so that the restart policy starts it again with the new environment.
+// Zero signal sends no signal.
+func (c *Client) SetContainerEnvAndSignal(ctx context.Context, containerID string, env map[string]string, signal syscall.Signal) error {
+	for name := range env {
+		if name == "" || strings.Contains(name, "=") {
+			return &Error{
+				Code:    codes.InvalidArgument,
+				Message: fmt.Sprintf("Invalid environment variable name [%s], must not be empty or contain '='", name),
+			}
+		}
+	}
+
+	conn, err := c.getConnection()
+	if err != nil {
+		return err
+	}
+
+	_, err = containers.NewContainersClient(conn).SetEnv(ctx, &containers.SetEnvRequest{
+		Namespace:   c.Namespace,
+		ContainerID: containerID,
+		Env:         env,
+		Signal:      int32(signal),
+	})
+	return translateContainerError(err)
+}
+
+// AttachToContainer is like Attach, but resolves the container by the pod and container name.
+// The container name can be empty if the pod has only one container.
+// With WithWaitForContainer option, waits the container to start before attaching, see WaitForContainerRunning.
+func (c *Client) AttachToContainer(ctx context.Context, podName, containerName string, attachIO AttachIO, hooks ...AttachHooks) error {
+	if c.waitForContainer > 0 {
+		status, err := c.WaitForContainerRunning(ctx, podName, containerName, c.waitForContainer)
+		if err != nil {
+			return err
+		}
+		return c.Attach(ctx, status.GetContainerID(), attachIO, hooks...)
+	}
+
+	containerID, err := c.ResolveContainerID(ctx, podName, containerName)
+	if err != nil {
+		return err
+	}
+	return c.Attach(ctx, containerID, attachIO, hooks...)
+}
+
+// SignalContainer is like Signal, but resolves the container by the pod and container name.
+// The container name can be empty if the pod has only one container.
+func (c *Client) SignalContainer(ctx context.Context, podName, containerName string, signal syscall.Signal) error {
+	containerID, err := c.ResolveContainerID(ctx, podName, containerName)
+	if err != nil {
+		return err
+	}
+	return c.Signal(ctx, containerID, signal)
+}
+
+// SignalPod sends the signal to all running containers in the pod, e.g. SIGHUP to reload the configuration.
+// Containers which are not running are skipped. If some container is skipped or signaling fails,
+// the other containers still get the signal and SignalPodError tells which containers didn't get it.
+func (c *Client) SignalPod(ctx context.Context, podName string, signal syscall.Signal) error {
+	pod, err := c.GetPod(ctx, podName)
+	if err != nil {
+		return err
+	}
+
+	result := &SignalPodError{Pod: podName, Failed: map[string]error{}}
+	for _, status := range pod.GetStatus().GetContainerStatuses() {
+		if !MapContainerState(status).Running {
+			result.Skipped = append(result.Skipped, status.GetName())
+			continue
+		}
+		if err := c.Signal(ctx, status.GetContainerID(), signal); err != nil {
+			result.Failed[status.GetName()] = err
+		}
+	}
+
+	if len(result.Skipped) > 0 || len(result.Failed) > 0 {
+		return result
+	}
+	return nil
+}
+
+// ResolveContainerID return ID of the container in the pod. The container name can be empty if the pod has only one container.
+// If the container is not found, the error is ErrContainerNotFound and the message lists the containers in the pod.
+// The pod is fetched on every call, nothing is cached, because the container ID changes when the container gets recreated.
+// Resolve the ID once per operation and use it within the operation, e.g. for reconnecting.
+func (c *Client) ResolveContainerID(ctx context.Context, podName, containerName string) (string, error) {
+	pod, err := c.GetPod(ctx, podName)
+	if err != nil {
+		return "", err
+	}
+	return findContainerID(pod, containerName)
+}
+
+// findContainerID return ID of the container in the pod, containerName can be empty if there's only one container
+func findContainerID(pod *pods.Pod, containerName string) (string, error) {
+	status, err := findContainerStatus(pod, containerName)
+	if err != nil {
+		return "", err
+	}
+	return status.GetContainerID(), nil
+}
+
+// findContainerStatus return status of the container in the pod, containerName can be empty if there's only one container
+func findContainerStatus(pod *pods.Pod, containerName string) (*containers.ContainerStatus, error) {
+	statuses := pod.GetStatus().GetContainerStatuses()
+	if containerName == "" && len(statuses) == 1 {
+		return statuses[0], nil
+	}
+	if len(statuses) == 0 {
+		return nil, &Error{
+			Code:    codes.NotFound,
+			Message: fmt.Sprintf("Pod [%s] doesn't have any containers", pod.GetMetadata().GetName()),
+			cause:   ErrContainerNotFound,
+		}
+	}
+
+	names := []string{}
+	for _, status := range statuses {
+		if status.GetName() == containerName {
+			return status, nil
+		}
+		names = append(names, status.GetName())
+	}
+
+	message := fmt.Sprintf("Container [%s] not found in pod [%s], available containers: [%s]", containerName, pod.GetMetadata().GetName(), strings.Join(names, ", "))
+	if containerName == "" {
+		message = fmt.Sprintf("Pod [%s] contains %d containers, you must define container name: [%s]", pod.GetMetadata().GetName(), len(names), strings.Join(names, ", "))
+	}
+	return nil, &Error{
+		Code:    codes.NotFound,
+		Message: message,
+		cause:   ErrContainerNotFound,
+	}
+}
+
+// GetContainerLogs return the container output lines currently kept in the node log buffer.
+// The format defines the order of the lines and whether the line ending is kept.
+func (c *Client) GetContainerLogs(ctx context.Context, containerID string, format LogFormat) ([]LogLine, error) {
+	conn, err := c.getConnection()
+	if err != nil {
+		return nil, err
+	}
+
+	client := containers.NewContainersClient(conn)
+	stream, err := client.Logs(ctx, &containers.LogsRequest{
+		Namespace:   c.Namespace,
+		ContainerID: containerID,
+	})
+	if err != nil {
+		return nil, translateError(err)
+	}
+
+	lines := []*containers.LogLine{}
+	for {
+		resp, err := stream.Recv()
+		if err == io.EOF {
+			return mapLogLines(lines, format), nil
+		}
+		if err != nil {
+			return nil, translateError(err)
+		}
+		lines = append(lines, resp.Lines...)
+	}
+}
+
+// FollowLogs writes container output lines prefixed with timestamp to the writer.
+// With Follow option, keeps writing new lines until the context get cancelled.
+func (c *Client) FollowLogs(ctx context.Context, containerID string, opts LogOptions, w io.Writer) error {
+	return c.streamLogs(ctx, containerID, opts, func(line *containers.LogLine) error {
+		_, err := fmt.Fprintf(w, "%s %s", formatLogTime(line.Time), line.Line)
+		return err
+	})
+}
+
+// streamLogs calls the handler for each container log line until the stream ends or the context get cancelled
+func (c *Client) streamLogs(ctx context.Context, containerID string, opts LogOptions, handler func(line *containers.LogLine) error) error {
+	conn, err := c.getConnection()
+	if err != nil {
+		return err
+	}
+
+	client := containers.NewContainersClient(conn)
+
+	req := &containers.LogsRequest{
+		Namespace:   c.Namespace,
+		ContainerID: containerID,
+		Follow:      opts.Follow,
+		Tail:        int32(opts.Tail),
+	}
+	if !opts.Since.IsZero() {
+		req.Since = opts.Since.UnixNano()
+	}
+
+	stream, err := client.Logs(ctx, req)
+	if err != nil {
+		return translateError(err)
+	}
+
+	for {
+		resp, err := stream.Recv()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return translateError(err)
+		}
+
+		for _, line := range resp.Lines {
+			if err := handler(line); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func formatLogTime(unixNano int64) string {
+	return time.Unix(0, unixNano).Format(time.RFC3339Nano)
+}
+
+func mapLogLines(lines []*containers.LogLine, format LogFormat) []LogLine {
+	stdout := []LogLine{}
+	stderr := []LogLine{}
+	result := []LogLine{}
+	for _, line := range lines {
+		mapped := LogLine{
+			Time:    time.Unix(0, line.GetTime()),
+			Stream:  LogStreamStdout,
+			Message: string(line.GetLine()),
+		}
+		if line.GetStderr() {
+			mapped.Stream = LogStreamStderr
+		}
+		if format != LogFormatRaw {
+			mapped.Message = strings.TrimRight(mapped.Message, "\r\n")
+		}
+
+		switch {
+		case format != LogFormatSplit:
+			result = append(result, mapped)
+		case mapped.Stream == LogStreamStderr:
+			stderr = append(stderr, mapped)
+		default:
+			stdout = append(stdout, mapped)
+		}
+	}
+
+	if format == LogFormatSplit {
+		return append(stdout, stderr...)
+	}
+	return result
+}
+
+func getExitCode(md metadata.MD) (int, error) {
+	value, ok := md["exitcode"]
+	if !ok || len(value) == 0 {
+		return -1, fmt.Errorf("Server did not return exit code for the command")
+	}
+
+	exitCode, err := strconv.Atoi(value[0])
+	if err != nil {
+		return -1, errors.Wrapf(err, "Server returned invalid exit code [%s]", value[0])
+	}
+	return exitCode, nil
 }
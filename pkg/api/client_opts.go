@@ -0,0 +1,308 @@
+package api
+
+import (
+	"fmt"
+	"io"
+	"runtime"
+	"strings"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"golang.org/x/net/context"
+	"golang.org/x/time/rate"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/connectivity"
+	"google.golang.org/grpc/encoding"
+	"google.golang.org/grpc/keepalive"
+	"google.golang.org/grpc/metadata"
+
+	"github.com/ernoaapa/eliot/pkg/config"
+)
+
+// WithDialOptions adds options used when dialing the connection to the server
+func WithDialOptions(opts ...grpc.DialOption) ClientOpts {
+	return func(client *Client) error {
+		client.dialOpts = append(client.dialOpts, opts...)
+		return nil
+	}
+}
+
+// WithUserAgent sets the user agent the client sends to the server, so that the server logs tell which client
+// build made the call, e.g. UserAgent(version). The gRPC library version gets appended to it.
+// By default the user agent is UserAgent("unknown").
+func WithUserAgent(userAgent string) ClientOpts {
+	return func(client *Client) error {
+		if strings.TrimSpace(userAgent) == "" {
+			return fmt.Errorf("Invalid user agent [%s], must not be empty", userAgent)
+		}
+		client.userAgent = userAgent
+		return nil
+	}
+}
+
+// UserAgent formats the client user agent for the version, e.g. "eliot/v0.2.0 (linux/arm64)"
+func UserAgent(version string) string {
+	return fmt.Sprintf("eliot/%s (%s/%s)", version, runtime.GOOS, runtime.GOARCH)
+}
+
+// WithInsecure disables transport security for the connection.
+// All data, including container stdin/stdout, is sent in plaintext.
+func WithInsecure() ClientOpts {
+	return func(client *Client) error {
+		client.transport = grpc.WithInsecure()
+		return nil
+	}
+}
+
+// WithTLS secures the connection with TLS.
+// If certFile and keyFile are given, the client authenticates itself to the server with the certificate (mutual TLS).
+// Empty caFile means the host root CA set is used to verify the server.
+func WithTLS(certFile, keyFile, caFile string) ClientOpts {
+	return func(client *Client) error {
+		creds, err := NewClientTLSCredentials(certFile, keyFile, caFile)
+		if err != nil {
+			return err
+		}
+		client.transport = grpc.WithTransportCredentials(creds)
+		return nil
+	}
+}
+
+// WithDialTimeout makes the connection to be opened in blocking mode so that unreachable
+// server address fails the first call within the timeout instead of hanging in it.
+// By default the connection is opened in the background and calls wait until it's up.
+func WithDialTimeout(timeout time.Duration) ClientOpts {
+	return func(client *Client) error {
+		if timeout <= 0 {
+			return fmt.Errorf("Invalid dial timeout [%s], must be greater than zero", timeout)
+		}
+		client.dialTimeout = timeout
+		return nil
+	}
+}
+
+// WithKeepalive configures how often the client pings the server to detect dead connection, e.g. when
+// cellular link drops without closing the TCP connection. When the server doesn't respond in time,
+// the connection gets closed and streams return ErrConnectionLost instead of blocking forever.
+// The server allows pings only while there are active calls and at most every 10 seconds.
+// By default the client pings every 30 seconds and waits the response 10 seconds.
+func WithKeepalive(params keepalive.ClientParameters) ClientOpts {
+	return func(client *Client) error {
+		if params.Time < keepaliveMinTime {
+			return fmt.Errorf("Invalid keepalive time [%s], must be at least %s", params.Time, keepaliveMinTime)
+		}
+		if params.Timeout <= 0 {
+			return fmt.Errorf("Invalid keepalive timeout [%s], must be greater than zero", params.Timeout)
+		}
+		if params.PermitWithoutStream {
+			return fmt.Errorf("Invalid keepalive parameters, the server doesn't allow pings without active calls")
+		}
+		client.keepalive = params
+		return nil
+	}
+}
+
+// WithCompression compresses the messages of the given methods, e.g. WithCompression(api.GzipCompression, "Logs"),
+// or all calls if no methods are given. The method names are the RPC names: Logs, Attach, Exec, CopyFrom, etc.
+// The server compresses the responses too when the request is compressed, so it's most useful for the bulk
+// transfers like logs and copying files over metered link. Compression costs CPU time in both ends and adds
+// latency to every message, so it benefits less interactive Attach and Exec which send small messages.
+// Requires server which supports the compression, older servers fail the calls with ErrUnimplemented.
+func WithCompression(compressor string, methods ...string) ClientOpts {
+	return func(client *Client) error {
+		if encoding.GetCompressor(compressor) == nil {
+			return fmt.Errorf("Invalid compression [%s], must be one of [%s]", compressor, GzipCompression)
+		}
+		c := &compression{compressor: compressor, methods: map[string]bool{}}
+		for _, method := range methods {
+			if method == "" || strings.Contains(method, "/") {
+				return fmt.Errorf("Invalid compression method [%s], must be RPC method name, e.g. Logs", method)
+			}
+			c.methods[method] = true
+		}
+		client.compression = c
+		return nil
+	}
+}
+
+// WithServers adds alternative servers which are tried in given order when the connection to
+// the primary server fails. The client keeps using the connected server until it becomes unavailable.
+// Calls are moved to the next server only when they fail to reach the server, already established
+// streams, e.g. Attach, are not moved to another server.
+func WithServers(addresses []string) ClientOpts {
+	return func(client *Client) error {
+		for _, address := range addresses {
+			if address == "" {
+				return fmt.Errorf("Invalid server address, address cannot be empty")
+			}
+			client.servers = append(client.servers, config.Endpoint{
+				Name: address,
+				URL:  address,
+			})
+		}
+		return nil
+	}
+}
+
+// WithProgressHandler sets function which gets called with the image pull progress when CreatePod pulls the images.
+// E.g. to show the progress in GUI or log the progress events.
+func WithProgressHandler(handler func(ImageFetchProgress)) ClientOpts {
+	return func(client *Client) error {
+		client.progressHandler = handler
+		return nil
+	}
+}
+
+// WithProgressWriter prints the image pull progress of CreatePod to the writer as plain lines,
+// one line for each image download percentage step and one when the image is pulled.
+// Unlike the terminal UI, the lines don't contain control codes, so use it when the output is not a terminal.
+func WithProgressWriter(w io.Writer) ClientOpts {
+	return func(client *Client) error {
+		if w == nil {
+			return fmt.Errorf("Progress writer cannot be nil")
+		}
+		client.progressWriter = w
+		return nil
+	}
+}
+
+// WithRetry retries idempotent calls (e.g. GetPods, StartPod) when the server is unavailable.
+// The wait between attempts doubles after each attempt starting from backoff and is randomised
+// so that many devices don't reconnect at the same time. Streaming calls are never retried, except CreatePod
+// which the server deduplicates with the idempotency key.
+func WithRetry(maxAttempts int, backoff time.Duration) ClientOpts {
+	return func(client *Client) error {
+		if maxAttempts < 1 {
+			return fmt.Errorf("Invalid retry max attempts [%d], must be at least one", maxAttempts)
+		}
+		client.retry = retryPolicy{
+			maxAttempts: maxAttempts,
+			backoff:     backoff,
+		}
+		return nil
+	}
+}
+
+// WithIdempotencyKey sets the key sent with every CreatePod and CreatePods call. If the create gets retried,
+// e.g. after timeout when it's unknown whether the pod got created, the server recognises the key and pod name
+// and returns the original result instead of creating the pod again or failing with ErrAlreadyExists.
+// The server remembers successful creates for ten minutes. By default, new key is generated for each create.
+func WithIdempotencyKey(key string) ClientOpts {
+	return func(client *Client) error {
+		if strings.TrimSpace(key) == "" {
+			return fmt.Errorf("Invalid idempotency key [%s], must not be empty", key)
+		}
+		client.idempotencyKey = key
+		return nil
+	}
+}
+
+// WithConnectionPool lets the client open up to maxSize connections to the server, so that high number of
+// concurrent calls don't queue up in single connection. New connection is opened only when all the existing
+// ones have calls in progress. Connections which have been unused for idleTimeout get closed, zero idleTimeout
+// keeps them open until Close. By default the client uses single connection.
+func WithConnectionPool(maxSize int, idleTimeout time.Duration) ClientOpts {
+	return func(client *Client) error {
+		if maxSize < 1 {
+			return fmt.Errorf("Invalid connection pool max size [%d], must be at least one", maxSize)
+		}
+		if idleTimeout < 0 {
+			return fmt.Errorf("Invalid connection pool idle timeout [%s], cannot be negative", idleTimeout)
+		}
+		client.pool.maxSize = maxSize
+		client.pool.idleTimeout = idleTimeout
+		return nil
+	}
+}
+
+// WithConnStateHandler sets function which gets called when the connection to the server changes state,
+// e.g. to show "reconnecting" in UI when the connection goes to TransientFailure during network break.
+// The handler is called first with the state right after the dial and then on every change, until Shutdown
+// when the connection get closed. The calls for one connection come in order from their own goroutine,
+// but with WithConnectionPool there's one goroutine per pooled connection.
+func WithConnStateHandler(handler func(connectivity.State)) ClientOpts {
+	return func(client *Client) error {
+		if handler == nil {
+			return fmt.Errorf("Connection state handler cannot be nil")
+		}
+		client.connStateHandler = handler
+		return nil
+	}
+}
+
+// WithLogger sets the logger where the client writes its internal debug and warning messages.
+// By default the client doesn't log anything.
+func WithLogger(logger *log.Entry) ClientOpts {
+	return func(client *Client) error {
+		if logger == nil {
+			return fmt.Errorf("Logger cannot be nil")
+		}
+		client.logger = logger
+		return nil
+	}
+}
+
+// WithMetadata adds the metadata to every call, e.g. the authorization header required by the gateway in front of the node.
+// The metadata is merged with the metadata the call itself sets.
+func WithMetadata(md metadata.MD) ClientOpts {
+	return func(client *Client) error {
+		client.metadata = metadata.Join(client.metadata, md)
+		return nil
+	}
+}
+
+// WithPerCallMetadata sets function which gets called before every call to get metadata for the call.
+// Use it instead of WithMetadata when the value changes, e.g. authorization token which gets rotated.
+func WithPerCallMetadata(fn func(ctx context.Context) metadata.MD) ClientOpts {
+	return func(client *Client) error {
+		client.perCallMetadata = fn
+		return nil
+	}
+}
+
+// WithMetrics records the metrics of every call with the recorder, e.g. call counts, latencies and errors by method.
+// If the recorder implements StreamMetricsRecorder, it receives also the stream durations and transferred bytes.
+func WithMetrics(recorder MetricsRecorder) ClientOpts {
+	return func(client *Client) error {
+		client.metrics = recorder
+		return nil
+	}
+}
+
+// WithRateLimit limits the number of calls sent to the server to r per second, allowing bursts of burst calls.
+// The call waits until the limit allows it or the context get cancelled. Streaming calls take one token when
+// the stream is opened, the messages in the stream are not limited. Use it to protect small server from
+// e.g. reconciliation loop which calls GetPods in many namespaces at once.
+func WithRateLimit(r rate.Limit, burst int) ClientOpts {
+	return func(client *Client) error {
+		if r <= 0 {
+			return fmt.Errorf("Invalid rate limit [%v], must be greater than zero", r)
+		}
+		if burst < 1 {
+			return fmt.Errorf("Invalid rate limit burst [%d], must be at least one", burst)
+		}
+		client.limiter = rate.NewLimiter(r, burst)
+		return nil
+	}
+}
+
+// WithWaitForContainer makes AttachToContainer wait up to the timeout for the container to start,
+// so that the pod can be attached right after creating it. Missing pod fails right away, see WaitForContainerRunning.
+func WithWaitForContainer(timeout time.Duration) ClientOpts {
+	return func(client *Client) error {
+		if timeout <= 0 {
+			return fmt.Errorf("Invalid wait for container timeout [%s], must be greater than zero", timeout)
+		}
+		client.waitForContainer = timeout
+		return nil
+	}
+}
+
+// WithDryRun makes CreatePod and DeletePod only simulate the change.
+// The server validates the request and returns the result, but doesn't create or delete anything.
+func WithDryRun() ClientOpts {
+	return func(client *Client) error {
+		client.dryRun = true
+		return nil
+	}
+}
@@ -0,0 +1,456 @@
+package api
+
+import (
+	"bytes"
+	"errors"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ernoaapa/eliot/pkg/api/core"
+	containers "github.com/ernoaapa/eliot/pkg/api/services/containers/v1"
+	pods "github.com/ernoaapa/eliot/pkg/api/services/pods/v1"
+	"github.com/ernoaapa/eliot/pkg/config"
+	"github.com/ernoaapa/eliot/pkg/progress"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	grpcstatus "google.golang.org/grpc/status"
+)
+
+func TestGetExitCode(t *testing.T) {
+	exitCode, err := getExitCode(metadata.Pairs("exitcode", "137"))
+	assert.NoError(t, err)
+	assert.Equal(t, 137, exitCode)
+
+	_, err = getExitCode(metadata.MD{})
+	assert.Error(t, err, "should return error if exit code is missing")
+
+	_, err = getExitCode(metadata.Pairs("exitcode", "foo"))
+	assert.Error(t, err, "should return error if exit code is not a number")
+}
+
+func TestWithNamespace(t *testing.T) {
+	client, err := NewClient("foo", config.Endpoint{Name: "test", URL: "localhost:5000"})
+	assert.NoError(t, err)
+
+	other := client.WithNamespace("bar")
+	assert.Equal(t, "foo", client.Namespace, "should not change the original client")
+	assert.Equal(t, "bar", other.Namespace)
+	assert.True(t, client.pool == other.pool, "should share the connection")
+}
+
+func TestWithLogger(t *testing.T) {
+	client, err := NewClient("foo", config.Endpoint{Name: "test", URL: "localhost:5000"})
+	assert.NoError(t, err)
+	assert.Equal(t, discardLogger, client.logger, "should not log by default")
+
+	logger := logrus.NewEntry(logrus.New())
+	client, err = NewClient("foo", config.Endpoint{Name: "test", URL: "localhost:5000"}, WithRetry(3, time.Second), WithLogger(logger))
+	assert.NoError(t, err)
+	assert.Equal(t, logger, client.logger)
+	assert.Equal(t, logger, client.retry.logger, "should log retries with the same logger")
+
+	_, err = NewClient("foo", config.Endpoint{Name: "test", URL: "localhost:5000"}, WithLogger(nil))
+	assert.Error(t, err)
+}
+
+func TestMapLogLines(t *testing.T) {
+	lines := []*containers.LogLine{
+		{Time: 1, Line: []byte("foo\n")},
+		{Time: 2, Stderr: true, Line: []byte("error\n")},
+		{Time: 3, Line: []byte("bar\n")},
+	}
+
+	raw := mapLogLines(lines, LogFormatRaw)
+	assert.Equal(t, []LogLine{
+		{Time: time.Unix(0, 1), Stream: LogStreamStdout, Message: "foo\n"},
+		{Time: time.Unix(0, 2), Stream: LogStreamStderr, Message: "error\n"},
+		{Time: time.Unix(0, 3), Stream: LogStreamStdout, Message: "bar\n"},
+	}, raw)
+
+	json := mapLogLines(lines, LogFormatJSON)
+	assert.Equal(t, []string{"foo", "error", "bar"}, []string{json[0].Message, json[1].Message, json[2].Message})
+
+	split := mapLogLines(lines, LogFormatSplit)
+	assert.Equal(t, []LogLine{
+		{Time: time.Unix(0, 1), Stream: LogStreamStdout, Message: "foo"},
+		{Time: time.Unix(0, 3), Stream: LogStreamStdout, Message: "bar"},
+		{Time: time.Unix(0, 2), Stream: LogStreamStderr, Message: "error"},
+	}, split)
+}
+
+func TestFindContainerID(t *testing.T) {
+	single := &pods.Pod{
+		Metadata: &core.ResourceMetadata{Name: "my-pod"},
+		Status: &pods.PodStatus{
+			ContainerStatuses: []*containers.ContainerStatus{
+				{Name: "foo", ContainerID: "foo-id"},
+			},
+		},
+	}
+	containerID, err := findContainerID(single, "")
+	assert.NoError(t, err)
+	assert.Equal(t, "foo-id", containerID, "should return the only container if name not defined")
+
+	multi := &pods.Pod{
+		Metadata: &core.ResourceMetadata{Name: "my-pod"},
+		Status: &pods.PodStatus{
+			ContainerStatuses: []*containers.ContainerStatus{
+				{Name: "foo", ContainerID: "foo-id"},
+				{Name: "bar", ContainerID: "bar-id"},
+			},
+		},
+	}
+	containerID, err = findContainerID(multi, "bar")
+	assert.NoError(t, err)
+	assert.Equal(t, "bar-id", containerID)
+
+	_, err = findContainerID(multi, "")
+	assert.True(t, errors.Is(err, ErrContainerNotFound), "should require the name if multiple containers")
+
+	_, err = findContainerID(multi, "baz")
+	assert.True(t, errors.Is(err, ErrNotFound))
+	assert.Contains(t, err.Error(), "foo, bar", "should list available containers")
+}
+
+func TestResolveContainerIDFetchesPodOnEveryCall(t *testing.T) {
+	var mu sync.Mutex
+	containerID := "first-id"
+	client, stop := startFakeWaitServer(t, func() []*pods.Pod {
+		mu.Lock()
+		defer mu.Unlock()
+		return []*pods.Pod{{
+			Metadata: &core.ResourceMetadata{Name: "my-pod", Namespace: "eliot"},
+			Status: &pods.PodStatus{
+				ContainerStatuses: []*containers.ContainerStatus{{Name: "foo", ContainerID: containerID}},
+			},
+		}}
+	})
+	defer stop()
+
+	resolved, err := client.ResolveContainerID(context.Background(), "my-pod", "")
+	assert.NoError(t, err)
+	assert.Equal(t, "first-id", resolved)
+
+	// The container got recreated
+	mu.Lock()
+	containerID = "second-id"
+	mu.Unlock()
+
+	resolved, err = client.ResolveContainerID(context.Background(), "my-pod", "foo")
+	assert.NoError(t, err)
+	assert.Equal(t, "second-id", resolved, "should not return stale ID")
+
+	_, err = client.ResolveContainerID(context.Background(), "my-pod", "bar")
+	assert.True(t, errors.Is(err, ErrContainerNotFound))
+	assert.Contains(t, err.Error(), "available containers: [foo]")
+
+	_, err = client.ResolveContainerID(context.Background(), "other-pod", "")
+	assert.True(t, errors.Is(err, ErrPodNotFound))
+}
+
+func TestCombineImageFetchProgress(t *testing.T) {
+	podList := []*pods.Pod{
+		{Metadata: &core.ResourceMetadata{Name: "foo"}},
+		{Metadata: &core.ResourceMetadata{Name: "bar"}},
+	}
+	fetches := map[string]ImageFetchProgress{
+		"bar": {progress.NewImageFetch("bar-1", "docker.io/library/nginx:latest")},
+		"foo": {progress.NewImageFetch("foo-1", "docker.io/library/nginx:latest")},
+	}
+
+	result := combineImageFetchProgress(podList, fetches)
+	assert.Len(t, result, 2)
+	assert.Equal(t, "foo", result[0].Pod, "should be in the pod list order")
+	assert.Equal(t, "bar", result[1].Pod)
+	assert.Equal(t, "docker.io/library/nginx:latest", result[1].Image)
+}
+
+type fakePodsServer struct {
+	pods.PodsServer
+	create func(req *pods.CreatePodRequest, server pods.Pods_CreateServer) error
+	list   func(req *pods.ListPodsRequest) (*pods.ListPodsResponse, error)
+	delete func(req *pods.DeletePodRequest) (*pods.DeletePodResponse, error)
+}
+
+func (s *fakePodsServer) Create(req *pods.CreatePodRequest, server pods.Pods_CreateServer) error {
+	return s.create(req, server)
+}
+
+func (s *fakePodsServer) List(ctx context.Context, req *pods.ListPodsRequest) (*pods.ListPodsResponse, error) {
+	return s.list(req)
+}
+
+func (s *fakePodsServer) Delete(ctx context.Context, req *pods.DeletePodRequest) (*pods.DeletePodResponse, error) {
+	return s.delete(req)
+}
+
+// Watch is not supported by the fake, so WaitForPodReady falls back to polling
+func (s *fakePodsServer) Watch(req *pods.WatchPodsRequest, server pods.Pods_WatchServer) error {
+	return grpcstatus.Error(codes.Unimplemented, "Watch not implemented")
+}
+
+func TestCreatePodCancelAbortsImagePull(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+
+	aborted := make(chan struct{})
+	server := grpc.NewServer()
+	pods.RegisterPodsServer(server, &fakePodsServer{create: func(req *pods.CreatePodRequest, server pods.Pods_CreateServer) error {
+		if err := server.Send(&pods.CreatePodStreamResponse{}); err != nil {
+			return err
+		}
+		// Simulates long image pull which is aborted when the client cancels
+		<-server.Context().Done()
+		close(aborted)
+		return server.Context().Err()
+	}})
+	go server.Serve(listener)
+	defer server.Stop()
+
+	client, err := NewClient("eliot", config.Endpoint{URL: listener.Addr().String()}, WithInsecure())
+	assert.NoError(t, err)
+	defer client.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	status := make(chan []*progress.ImageFetch)
+	go func() {
+		<-status
+		cancel()
+	}()
+
+	pod := &pods.Pod{
+		Metadata: &core.ResourceMetadata{Name: "foo"},
+		Spec:     &pods.PodSpec{Containers: []*containers.Container{{Name: "foo", Image: "docker.io/library/alpine:latest"}}},
+	}
+	err = client.CreatePod(ctx, status, pod)
+	assert.True(t, errors.Is(err, ErrCanceled), "should return ErrCanceled but got %v", err)
+
+	select {
+	case <-aborted:
+	case <-time.After(time.Second):
+		t.Fatal("Server didn't get the cancellation")
+	}
+}
+
+func TestCreatePodWithProgressWriter(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+
+	server := grpc.NewServer()
+	pods.RegisterPodsServer(server, &fakePodsServer{create: func(req *pods.CreatePodRequest, server pods.Pods_CreateServer) error {
+		for _, offset := range []int64{0, 20, 25, 100, 100} {
+			err := server.Send(&pods.CreatePodStreamResponse{Images: []*pods.ImageFetch{{
+				ContainerID: "foo",
+				Image:       "docker.io/library/alpine:latest",
+				Resolved:    true,
+				Layers:      []*pods.ImageLayerStatus{{Ref: "layer", Offset: offset, Total: 100}},
+			}}})
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	}})
+	go server.Serve(listener)
+	defer server.Stop()
+
+	out := &bytes.Buffer{}
+	client, err := NewClient("eliot", config.Endpoint{URL: listener.Addr().String()}, WithInsecure(), WithProgressWriter(out))
+	assert.NoError(t, err)
+	defer client.Close()
+
+	pod := &pods.Pod{
+		Metadata: &core.ResourceMetadata{Name: "foo"},
+		Spec:     &pods.PodSpec{Containers: []*containers.Container{{Name: "foo", Image: "docker.io/library/alpine:latest"}}},
+	}
+	assert.NoError(t, client.CreatePod(context.Background(), nil, pod))
+	assert.Equal(t, "Downloading docker.io/library/alpine:latest 0%\n"+
+		"Downloading docker.io/library/alpine:latest 20%\n"+
+		"Pulled docker.io/library/alpine:latest\n", out.String())
+
+	_, err = NewClient("eliot", config.Endpoint{URL: listener.Addr().String()}, WithProgressWriter(nil))
+	assert.Error(t, err)
+}
+
+func TestCreatePodWithProgressWriterCancelled(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+
+	server := grpc.NewServer()
+	pods.RegisterPodsServer(server, &fakePodsServer{create: func(req *pods.CreatePodRequest, server pods.Pods_CreateServer) error {
+		err := server.Send(&pods.CreatePodStreamResponse{Images: []*pods.ImageFetch{{
+			ContainerID: "foo",
+			Image:       "docker.io/library/alpine:latest",
+			Resolved:    true,
+			Layers:      []*pods.ImageLayerStatus{{Ref: "layer", Offset: 30, Total: 100}},
+		}}})
+		if err != nil {
+			return err
+		}
+		<-server.Context().Done()
+		return server.Context().Err()
+	}})
+	go server.Serve(listener)
+	defer server.Stop()
+
+	out := &bytes.Buffer{}
+	ctx, cancel := context.WithCancel(context.Background())
+	client, err := NewClient("eliot", config.Endpoint{URL: listener.Addr().String()}, WithInsecure(), WithProgressWriter(out), WithProgressHandler(func(ImageFetchProgress) {
+		cancel()
+	}))
+	assert.NoError(t, err)
+	defer client.Close()
+
+	pod := &pods.Pod{
+		Metadata: &core.ResourceMetadata{Name: "foo"},
+		Spec:     &pods.PodSpec{Containers: []*containers.Container{{Name: "foo", Image: "docker.io/library/alpine:latest"}}},
+	}
+	err = client.CreatePod(ctx, nil, pod)
+	assert.True(t, errors.Is(err, ErrCanceled), "should return ErrCanceled but got %v", err)
+	assert.Equal(t, "Downloading docker.io/library/alpine:latest 30%\n"+
+		"Cancelled pulling docker.io/library/alpine:latest\n", out.String())
+}
+
+func TestCreatePodProgressHandlerLayers(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+
+	server := grpc.NewServer()
+	pods.RegisterPodsServer(server, &fakePodsServer{create: func(req *pods.CreatePodRequest, server pods.Pods_CreateServer) error {
+		return server.Send(&pods.CreatePodStreamResponse{Images: []*pods.ImageFetch{{
+			ContainerID: "foo",
+			Image:       "docker.io/library/alpine:latest",
+			Resolved:    true,
+			Layers: []*pods.ImageLayerStatus{
+				{Ref: "b", Status: "extracting", Offset: 100, Total: 100},
+				// Older servers don't send the status
+				{Ref: "a", Offset: 20, Total: 100},
+			},
+		}}})
+	}})
+	go server.Serve(listener)
+	defer server.Stop()
+
+	var layers []progress.LayerProgress
+	client, err := NewClient("eliot", config.Endpoint{URL: listener.Addr().String()}, WithInsecure(), WithProgressHandler(func(images ImageFetchProgress) {
+		layers = images.Layers()
+	}))
+	assert.NoError(t, err)
+	defer client.Close()
+
+	pod := &pods.Pod{
+		Metadata: &core.ResourceMetadata{Name: "foo"},
+		Spec:     &pods.PodSpec{Containers: []*containers.Container{{Name: "foo", Image: "docker.io/library/alpine:latest"}}},
+	}
+	assert.NoError(t, client.CreatePod(context.Background(), nil, pod))
+	assert.Equal(t, []progress.LayerProgress{
+		{Image: "docker.io/library/alpine:latest", Ref: "a", Total: 100, Downloaded: 20, Status: progress.StatusDownloading},
+		{Image: "docker.io/library/alpine:latest", Ref: "b", Total: 100, Downloaded: 100, Status: progress.StatusExtracting},
+	}, layers)
+}
+
+func TestDeletePodsByLabelContinuesAfterFailure(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+
+	server := grpc.NewServer()
+	pods.RegisterPodsServer(server, &fakePodsServer{
+		list: func(req *pods.ListPodsRequest) (*pods.ListPodsResponse, error) {
+			assert.Equal(t, "app=test", req.LabelSelector)
+			return &pods.ListPodsResponse{Pods: []*pods.Pod{
+				{Metadata: &core.ResourceMetadata{Name: "foo", Namespace: "eliot"}},
+				{Metadata: &core.ResourceMetadata{Name: "stuck", Namespace: "eliot"}},
+				{Metadata: &core.ResourceMetadata{Name: "bar", Namespace: "eliot"}},
+			}}, nil
+		},
+		delete: func(req *pods.DeletePodRequest) (*pods.DeletePodResponse, error) {
+			if req.Name == "stuck" {
+				return nil, grpcstatus.Error(codes.Internal, "failed to stop container")
+			}
+			return &pods.DeletePodResponse{Pod: &pods.Pod{Metadata: &core.ResourceMetadata{Name: req.Name, Namespace: req.Namespace}}}, nil
+		},
+	})
+	go server.Serve(listener)
+	defer server.Stop()
+
+	client, err := NewClient("eliot", config.Endpoint{URL: listener.Addr().String()}, WithInsecure())
+	assert.NoError(t, err)
+	defer client.Close()
+
+	deleted, err := client.DeletePodsByLabel(context.Background(), map[string]string{"app": "test"})
+	if assert.Len(t, deleted, 2) {
+		assert.Equal(t, "bar", deleted[0].Metadata.Name)
+		assert.Equal(t, "foo", deleted[1].Metadata.Name)
+	}
+
+	deleteErr, ok := err.(*DeletePodsError)
+	if assert.True(t, ok, "should return DeletePodsError but got %v", err) {
+		assert.Equal(t, []string{"bar", "foo"}, deleteErr.Deleted)
+		assert.Contains(t, deleteErr.Failed, "stuck")
+	}
+}
+
+func TestDeletePodsByLabelRejectsEmptySelector(t *testing.T) {
+	client, err := NewClient("eliot", config.Endpoint{URL: "localhost:5000"}, WithInsecure())
+	assert.NoError(t, err)
+
+	_, err = client.DeletePodsByLabel(context.Background(), map[string]string{})
+	assert.True(t, errors.Is(err, ErrInvalidArgument), "should return ErrInvalidArgument but got %v", err)
+}
+
+func TestAttachToContainerReturnsPodNotFound(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+
+	server := grpc.NewServer()
+	pods.RegisterPodsServer(server, &fakePodsServer{
+		list: func(req *pods.ListPodsRequest) (*pods.ListPodsResponse, error) {
+			return &pods.ListPodsResponse{}, nil
+		},
+	})
+	go server.Serve(listener)
+	defer server.Stop()
+
+	client, err := NewClient("eliot", config.Endpoint{URL: listener.Addr().String()}, WithInsecure())
+	assert.NoError(t, err)
+	defer client.Close()
+
+	err = client.AttachToContainer(context.Background(), "missing", "", NewAttachIO(nil, &bytes.Buffer{}, &bytes.Buffer{}))
+	assert.True(t, errors.Is(err, ErrPodNotFound), "should return ErrPodNotFound but got %v", err)
+	assert.True(t, errors.Is(err, ErrNotFound), "should match also to ErrNotFound but got %v", err)
+}
+
+func TestGetContainerStatus(t *testing.T) {
+	client, stop := startFakeServer(t, nil, &fakeContainersServer{status: func(req *containers.StatusRequest) (*containers.StatusResponse, error) {
+		if req.ContainerID != "foo" {
+			return nil, grpcstatus.Errorf(codes.NotFound, "Container [%s] not found", req.ContainerID)
+		}
+		return &containers.StatusResponse{Status: &containers.ContainerStatus{ContainerID: "foo", State: "stopped", RestartCount: 2, ExitCode: 1}}, nil
+	}})
+	defer stop()
+
+	status, err := client.GetContainerStatus(context.Background(), "foo")
+	assert.NoError(t, err)
+	assert.Equal(t, "stopped", status.State)
+	assert.Equal(t, int32(2), status.RestartCount)
+	assert.Equal(t, int32(1), status.ExitCode)
+
+	_, err = client.GetContainerStatus(context.Background(), "bar")
+	assert.True(t, errors.Is(err, ErrContainerNotFound))
+	assert.True(t, errors.Is(err, ErrNotFound))
+}
+
+func TestSetContainerEnvValidatesNames(t *testing.T) {
+	client, err := NewClient("eliot", config.Endpoint{URL: "127.0.0.1:1"}, WithInsecure())
+	assert.NoError(t, err)
+	defer client.Close()
+
+	err = client.SetContainerEnv(context.Background(), "foo", map[string]string{"BAD=NAME": "value"})
+	assert.True(t, errors.Is(err, ErrInvalidArgument))
+}
@@ -0,0 +1,94 @@
+package api
+
+import (
+	"compress/gzip"
+	"io"
+	"strings"
+	"sync"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/encoding"
+)
+
+// GzipCompression is the gzip message compression, see WithCompression
+const GzipCompression = "gzip"
+
+func init() {
+	// Registered in both client and server, the server compresses the responses when the request is compressed
+	encoding.RegisterCompressor(&gzipCompressor{})
+}
+
+// gzipCompressor is gRPC compressor which reuses the gzip writers and readers between messages
+type gzipCompressor struct {
+	writers sync.Pool
+	readers sync.Pool
+}
+
+func (c *gzipCompressor) Compress(w io.Writer) (io.WriteCloser, error) {
+	if z, ok := c.writers.Get().(*gzipWriter); ok {
+		z.Writer.Reset(w)
+		return z, nil
+	}
+	return &gzipWriter{Writer: gzip.NewWriter(w), pool: &c.writers}, nil
+}
+
+func (c *gzipCompressor) Decompress(r io.Reader) (io.Reader, error) {
+	z, ok := c.readers.Get().(*gzipReader)
+	if !ok {
+		reader, err := gzip.NewReader(r)
+		if err != nil {
+			return nil, err
+		}
+		return &gzipReader{Reader: reader, pool: &c.readers}, nil
+	}
+	if err := z.Reader.Reset(r); err != nil {
+		c.readers.Put(z)
+		return nil, err
+	}
+	return z, nil
+}
+
+func (c *gzipCompressor) Name() string {
+	return GzipCompression
+}
+
+type gzipWriter struct {
+	*gzip.Writer
+	pool *sync.Pool
+}
+
+func (z *gzipWriter) Close() error {
+	defer z.pool.Put(z)
+	return z.Writer.Close()
+}
+
+type gzipReader struct {
+	*gzip.Reader
+	pool *sync.Pool
+}
+
+func (z *gzipReader) Read(p []byte) (n int, err error) {
+	n, err = z.Reader.Read(p)
+	if err == io.EOF {
+		z.pool.Put(z)
+	}
+	return n, err
+}
+
+// compression is the WithCompression configuration
+type compression struct {
+	compressor string
+	// methods are the method names to compress, e.g. Logs, all methods if empty
+	methods map[string]bool
+}
+
+// callOptions return the call options to compress the method call, if enabled for it
+func (c *compression) callOptions(fullMethod string, opts []grpc.CallOption) []grpc.CallOption {
+	if c == nil {
+		return opts
+	}
+	if len(c.methods) > 0 && !c.methods[fullMethod[strings.LastIndex(fullMethod, "/")+1:]] {
+		return opts
+	}
+	return append([]grpc.CallOption{grpc.UseCompressor(c.compressor)}, opts...)
+}
@@ -0,0 +1,82 @@
+package api
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net"
+	"strings"
+	"testing"
+
+	containers "github.com/ernoaapa/eliot/pkg/api/services/containers/v1"
+	"github.com/ernoaapa/eliot/pkg/config"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/transport"
+)
+
+func TestGzipCompressorRoundTrip(t *testing.T) {
+	compressor := &gzipCompressor{}
+	input := strings.Repeat("2018-04-10T18:25:43.014340551Z Hello world!\n", 100)
+
+	for i := 0; i < 3; i++ {
+		compressed := &bytes.Buffer{}
+		w, err := compressor.Compress(compressed)
+		assert.NoError(t, err)
+		w.Write([]byte(input))
+		assert.NoError(t, w.Close())
+		assert.True(t, compressed.Len() < len(input))
+
+		r, err := compressor.Decompress(compressed)
+		assert.NoError(t, err)
+		output, err := ioutil.ReadAll(r)
+		assert.NoError(t, err)
+		assert.Equal(t, input, string(output), "pooled writer and reader should work the same")
+	}
+}
+
+func TestCompressionCallOptions(t *testing.T) {
+	var none *compression
+	assert.Len(t, none.callOptions("/eliot.services.containers.v1.Containers/Logs", nil), 0)
+
+	logsOnly := &compression{compressor: GzipCompression, methods: map[string]bool{"Logs": true}}
+	assert.Len(t, logsOnly.callOptions("/eliot.services.containers.v1.Containers/Logs", nil), 1)
+	assert.Len(t, logsOnly.callOptions("/eliot.services.containers.v1.Containers/Attach", nil), 0)
+
+	all := &compression{compressor: GzipCompression, methods: map[string]bool{}}
+	assert.Len(t, all.callOptions("/eliot.services.containers.v1.Containers/Attach", nil), 1)
+}
+
+func TestWithCompressionValidates(t *testing.T) {
+	endpoint := config.Endpoint{Name: "node", URL: "localhost:5000"}
+
+	_, err := NewClient("eliot", endpoint, WithCompression("snappy"))
+	assert.Error(t, err)
+
+	_, err = NewClient("eliot", endpoint, WithCompression(GzipCompression, "/eliot.services.containers.v1.Containers/Logs"))
+	assert.Error(t, err)
+}
+
+func TestAttachWithCompression(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+
+	encodings := make(chan string, 1)
+	server := grpc.NewServer()
+	containers.RegisterContainersServer(server, &fakeContainersServer{attach: func(server containers.Containers_AttachServer) error {
+		stream, _ := transport.StreamFromContext(server.Context())
+		encodings <- stream.RecvCompress()
+		return server.Send(&containers.StdoutStreamResponse{Output: []byte("hello")})
+	}})
+	go server.Serve(listener)
+	defer server.Stop()
+
+	client, err := NewClient("eliot", config.Endpoint{URL: listener.Addr().String()}, WithInsecure(), WithCompression(GzipCompression, "Attach"))
+	assert.NoError(t, err)
+	defer client.Close()
+
+	stdout := &bytes.Buffer{}
+	assert.NoError(t, client.AttachReadOnly(context.Background(), "foo", stdout, stdout))
+	assert.Equal(t, "hello", stdout.String())
+	assert.Equal(t, GzipCompression, <-encodings)
+}
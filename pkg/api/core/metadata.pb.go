@@ -37,6 +37,11 @@ type ResourceMetadata struct {
 	// An empty namespace is equivalent to the default namespace.
 	// Cannot be updated.
 	Namespace string `protobuf:"bytes,2,opt,name=namespace" json:"namespace,omitempty"`
+	// Labels are key value pairs for organizing and selecting resources
+	Labels map[string]string `protobuf:"bytes,3,rep,name=labels" json:"labels,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	// ResourceVersion changes every time the resource changes. Set by the server, read-only.
+	// If set in update, the update fails if the resource has changed since.
+	ResourceVersion string `protobuf:"bytes,4,opt,name=resourceVersion" json:"resourceVersion,omitempty"`
 }
 
 func (m *ResourceMetadata) Reset()                    { *m = ResourceMetadata{} }
@@ -58,6 +63,20 @@ func (m *ResourceMetadata) GetNamespace() string {
 	return ""
 }
 
+func (m *ResourceMetadata) GetLabels() map[string]string {
+	if m != nil {
+		return m.Labels
+	}
+	return nil
+}
+
+func (m *ResourceMetadata) GetResourceVersion() string {
+	if m != nil {
+		return m.ResourceVersion
+	}
+	return ""
+}
+
 func init() {
 	proto.RegisterType((*ResourceMetadata)(nil), "cand.core.ResourceMetadata")
 }
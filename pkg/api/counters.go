@@ -0,0 +1,77 @@
+package api
+
+import (
+	"io"
+	"sync/atomic"
+)
+
+// AttachStats counts the bytes that flowed through the attach or exec stream.
+// The counters get updated while the data flows, so they are accurate also when the session ends with error.
+// Safe to read while the session is still running.
+type AttachStats struct {
+	// Only int64 fields so that they stay 64-bit aligned for atomic access on 32-bit ARM
+	stdin  int64
+	stdout int64
+	stderr int64
+}
+
+// StdinBytes returns how many bytes have been read from the stdin and sent to the container
+func (s *AttachStats) StdinBytes() int64 {
+	return atomic.LoadInt64(&s.stdin)
+}
+
+// StdoutBytes returns how many bytes have been received from the container stdout
+func (s *AttachStats) StdoutBytes() int64 {
+	return atomic.LoadInt64(&s.stdout)
+}
+
+// StderrBytes returns how many bytes have been received from the container stderr
+func (s *AttachStats) StderrBytes() int64 {
+	return atomic.LoadInt64(&s.stderr)
+}
+
+// wrap returns stdin, stdout and stderr which update the counters, return them as is if stats is nil
+func (s *AttachStats) wrap(stdin io.Reader, stdout, stderr io.Writer) (io.Reader, io.Writer, io.Writer) {
+	if s == nil {
+		return stdin, stdout, stderr
+	}
+	return countReader(stdin, &s.stdin), countWriter(stdout, &s.stdout), countWriter(stderr, &s.stderr)
+}
+
+// countReader wraps the reader so that read bytes get added to the counter, return nil for nil reader
+func countReader(r io.Reader, counter *int64) io.Reader {
+	if r == nil {
+		return nil
+	}
+	return &countingReader{r, counter}
+}
+
+// countWriter wraps the writer so that written bytes get added to the counter, return nil for nil writer
+func countWriter(w io.Writer, counter *int64) io.Writer {
+	if w == nil {
+		return nil
+	}
+	return &countingWriter{w, counter}
+}
+
+type countingReader struct {
+	io.Reader
+	counter *int64
+}
+
+func (r *countingReader) Read(p []byte) (int, error) {
+	n, err := r.Reader.Read(p)
+	atomic.AddInt64(r.counter, int64(n))
+	return n, err
+}
+
+type countingWriter struct {
+	io.Writer
+	counter *int64
+}
+
+func (w *countingWriter) Write(p []byte) (int, error) {
+	n, err := w.Writer.Write(p)
+	atomic.AddInt64(w.counter, int64(n))
+	return n, err
+}
@@ -0,0 +1,30 @@
+package api
+
+import (
+	"fmt"
+	"time"
+
+	pods "github.com/ernoaapa/eliot/pkg/api/services/pods/v1"
+)
+
+// WithGracePeriod defines how long containers have time to exit after SIGTERM before they get killed.
+// Zero grace period kills the containers immediately.
+func WithGracePeriod(gracePeriod time.Duration) DeleteOpts {
+	return func(req *pods.DeletePodRequest) error {
+		if gracePeriod < 0 {
+			return fmt.Errorf("Invalid grace period [%s], cannot be negative", gracePeriod)
+		}
+		req.GracePeriod = int64(gracePeriod)
+		return nil
+	}
+}
+
+// WithCascade removes also the pod volumes, the bind mounts from model.PodVolumesDir, e.g. the eli up sync directories.
+// The volumes which other pods mount are retained, see DeleteResult. By default, the volumes are kept.
+// The pod has no other resources to clean up, the network namespaces get removed with the containers.
+func WithCascade(cascade bool) DeleteOpts {
+	return func(req *pods.DeletePodRequest) error {
+		req.Cascade = cascade
+		return nil
+	}
+}
@@ -0,0 +1,59 @@
+package api
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"google.golang.org/grpc/codes"
+)
+
+// detachTimeout is how long Attach waits the server to end the stream after the detach
+const detachTimeout = 2 * time.Second
+
+// DefaultDetachKeys is the Attach detach key sequence Ctrl-P Ctrl-Q, same as in Docker
+var DefaultDetachKeys = []byte{16, 17}
+
+// getDetachKeys return the detach key sequence, nil means the default
+func getDetachKeys(keys []byte) []byte {
+	if keys == nil {
+		return DefaultDetachKeys
+	}
+	return keys
+}
+
+// ParseDetachKeys parses comma separated key sequence, e.g. "ctrl-p,ctrl-q", for AttachIO DetachKeys.
+// Each key is either single character or ctrl- followed by a letter or one of @[\]^_
+// Returns ErrInvalidArgument if some key is invalid.
+func ParseDetachKeys(value string) ([]byte, error) {
+	keys := []byte{}
+	for _, key := range strings.Split(value, ",") {
+		switch {
+		case len(key) == 1:
+			keys = append(keys, key[0])
+		case strings.HasPrefix(strings.ToLower(key), "ctrl-") && len(key) == len("ctrl-")+1:
+			code, ok := getCtrlKeyCode(key[len(key)-1])
+			if !ok {
+				return nil, &Error{Code: codes.InvalidArgument, Message: fmt.Sprintf("Invalid detach key [%s], no such ctrl key", key)}
+			}
+			keys = append(keys, code)
+		default:
+			return nil, &Error{Code: codes.InvalidArgument, Message: fmt.Sprintf("Invalid detach key [%s], must be single character or ctrl-<key>", key)}
+		}
+	}
+	return keys, nil
+}
+
+// getCtrlKeyCode return the control character what terminal sends for ctrl and the key
+func getCtrlKeyCode(key byte) (byte, bool) {
+	switch {
+	case key >= 'a' && key <= 'z':
+		return key - 'a' + 1, true
+	case key >= 'A' && key <= 'Z':
+		return key - 'A' + 1, true
+	case key >= '@' && key <= '_':
+		// @ [ \ ] ^ _ map to 0 and 27-31
+		return key - '@', true
+	}
+	return 0, false
+}
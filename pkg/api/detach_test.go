@@ -0,0 +1,113 @@
+package api
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"io/ioutil"
+	"testing"
+	"time"
+
+	containers "github.com/ernoaapa/eliot/pkg/api/services/containers/v1"
+	"github.com/ernoaapa/eliot/pkg/api/stream"
+	"github.com/ernoaapa/eliot/pkg/runtime"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/net/context"
+)
+
+func TestAttachDetachesWithKeySequence(t *testing.T) {
+	received := make(chan []byte, 1)
+	client, stop := startFakeContainersServer(t, func(server containers.Containers_AttachServer) error {
+		input, err := ioutil.ReadAll(stream.NewReader(server))
+		if err != stream.ErrDetached {
+			t.Errorf("Server should receive detach, got: %v", err)
+		}
+		received <- input
+		return nil
+	})
+	defer stop()
+
+	stdout := &bytes.Buffer{}
+	errc := make(chan error)
+	go func() {
+		errc <- client.Attach(context.Background(), "foo", NewAttachIO(bytes.NewReader([]byte("ls\n\x10\x11exit\n")), stdout, stdout))
+	}()
+
+	select {
+	case err := <-errc:
+		assert.True(t, errors.Is(err, ErrDetached), "should return ErrDetached, got: %v", err)
+		assert.True(t, errors.Is(err, ErrCanceled))
+		assert.Equal(t, "ls\n", string(<-received), "should not forward the detach keys to the container")
+	case <-time.After(5 * time.Second):
+		t.Fatal("Attach didn't return after the detach keys")
+	}
+}
+
+func TestAttachWithCustomDetachKeys(t *testing.T) {
+	received := make(chan []byte, 1)
+	client, stop := startFakeContainersServer(t, func(server containers.Containers_AttachServer) error {
+		input, _ := ioutil.ReadAll(stream.NewReader(server))
+		received <- input
+		return nil
+	})
+	defer stop()
+
+	keys, err := ParseDetachKeys("ctrl-x,q")
+	assert.NoError(t, err)
+
+	attachIO := NewAttachIO(bytes.NewReader([]byte("\x10\x11\x18q")), ioutil.Discard, ioutil.Discard)
+	attachIO.DetachKeys = keys
+	err = client.Attach(context.Background(), "foo", attachIO)
+	assert.True(t, errors.Is(err, ErrDetached), "should return ErrDetached, got: %v", err)
+	assert.Equal(t, "\x10\x11", string(<-received), "default keys should be sent when custom keys are set")
+}
+
+// attachRuntime writes output until the write fails and records how the stdin ended
+type attachRuntime struct {
+	runtime.Client
+	stdinErr chan error
+	writeErr chan error
+}
+
+func (r *attachRuntime) Attach(namespace, podName string, attachIO runtime.AttachIO) error {
+	go func() {
+		_, err := io.Copy(ioutil.Discard, attachIO.Stdin)
+		r.stdinErr <- err
+	}()
+	for {
+		if _, err := attachIO.Stdout.Write([]byte("tick\n")); err != nil {
+			r.writeErr <- err
+			return err
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestServerEndsAttachOnDetach(t *testing.T) {
+	fake := &attachRuntime{stdinErr: make(chan error, 1), writeErr: make(chan error, 1)}
+	client, stop := startDiskUsageServer(t, fake)
+	defer stop()
+
+	start := time.Now()
+	err := client.Attach(context.Background(), "foo", NewAttachIO(bytes.NewReader([]byte("x\x10\x11")), ioutil.Discard, ioutil.Discard))
+	assert.True(t, errors.Is(err, ErrDetached), "should return ErrDetached, got: %v", err)
+	assert.True(t, time.Since(start) < detachTimeout, "server should end the stream right after the detach")
+
+	assert.Equal(t, stream.ErrDetached, <-fake.stdinErr, "container stdin should not get end of input")
+	assert.Equal(t, stream.ErrDetached, <-fake.writeErr, "output should not be written after the detach")
+}
+
+func TestParseDetachKeys(t *testing.T) {
+	keys, err := ParseDetachKeys("ctrl-p,ctrl-q")
+	assert.NoError(t, err)
+	assert.Equal(t, DefaultDetachKeys, keys)
+
+	keys, err = ParseDetachKeys("ctrl-@,ctrl-[,ctrl-A,x")
+	assert.NoError(t, err)
+	assert.Equal(t, []byte{0, 27, 1, 'x'}, keys)
+
+	for _, value := range []string{"", "ctrl-", "ctrl-1", "alt-p", "ctrl-p,,ctrl-q"} {
+		_, err := ParseDetachKeys(value)
+		assert.True(t, errors.Is(err, ErrInvalidArgument), "should reject [%s]", value)
+	}
+}
@@ -0,0 +1,225 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	containers "github.com/ernoaapa/eliot/pkg/api/services/containers/v1"
+	pods "github.com/ernoaapa/eliot/pkg/api/services/pods/v1"
+	"github.com/ernoaapa/eliot/pkg/utils"
+	"golang.org/x/net/context"
+)
+
+// PodDiff is the difference between the pod running in the node and the desired pod
+type PodDiff struct {
+	Name string
+	// Create is true if the pod doesn't exist yet, then all desired containers are listed in AddedContainers
+	Create bool
+
+	AddedContainers   []string
+	RemovedContainers []string
+	// ChangedContainers are the existing containers which spec differs, they get recreated on update
+	ChangedContainers []string
+
+	Changes []FieldChange
+}
+
+// FieldChange is single field which differs between the running and the desired pod
+type FieldChange struct {
+	// Field is the path to the field, e.g. "spec.containers[foo].image"
+	Field   string
+	Current string
+	Desired string
+	// RequiresRestart is true if the change gets applied only by recreating the containers.
+	// Metadata changes, like labels, are cosmetic and don't require restart.
+	RequiresRestart bool
+}
+
+// IsEmpty return true if the running pod matches the desired pod
+func (d *PodDiff) IsEmpty() bool {
+	return !d.Create && len(d.AddedContainers) == 0 && len(d.RemovedContainers) == 0 && len(d.Changes) == 0
+}
+
+// RequiresRestart return true if applying the diff requires creating, removing or recreating containers
+func (d *PodDiff) RequiresRestart() bool {
+	if d.Create || len(d.AddedContainers) > 0 || len(d.RemovedContainers) > 0 {
+		return true
+	}
+	for _, change := range d.Changes {
+		if change.RequiresRestart {
+			return true
+		}
+	}
+	return false
+}
+
+// DiffPod fetches the pod with the desired pod name and compares it to the desired pod.
+// If the pod doesn't exist, the diff is marked to be created.
+func (c *Client) DiffPod(ctx context.Context, desired *pods.Pod) (*PodDiff, error) {
+	current, err := c.GetPod(ctx, desired.GetMetadata().GetName())
+	if err != nil {
+		if errors.Is(err, ErrNotFound) {
+			return diffPod(nil, desired), nil
+		}
+		return nil, err
+	}
+	return diffPod(current, desired), nil
+}
+
+// diffPod compares the current pod to the desired pod, nil current pod means the pod doesn't exist.
+// The values set by the image, like environment variables, the command and the working directory,
+// are compared only if the desired pod defines them.
+func diffPod(current, desired *pods.Pod) *PodDiff {
+	diff := &PodDiff{Name: desired.GetMetadata().GetName()}
+	if current == nil {
+		diff.Create = true
+		for _, container := range desired.GetSpec().GetContainers() {
+			diff.AddedContainers = append(diff.AddedContainers, container.GetName())
+		}
+		return diff
+	}
+
+	diff.compareLabels(current.GetMetadata().GetLabels(), desired.GetMetadata().GetLabels())
+
+	currentSpec, desiredSpec := current.GetSpec(), desired.GetSpec()
+	diff.compare("spec.hostNetwork", fmt.Sprint(currentSpec.GetHostNetwork()), fmt.Sprint(desiredSpec.GetHostNetwork()), true)
+	diff.compare("spec.hostPID", fmt.Sprint(currentSpec.GetHostPID()), fmt.Sprint(desiredSpec.GetHostPID()), true)
+	if desiredSpec.GetRestartPolicy() != "" {
+		diff.compare("spec.restartPolicy", currentSpec.GetRestartPolicy(), desiredSpec.GetRestartPolicy(), true)
+	}
+
+	currentContainers := map[string]*containers.Container{}
+	for _, container := range currentSpec.GetContainers() {
+		currentContainers[container.GetName()] = container
+	}
+
+	desiredNames := map[string]bool{}
+	for _, container := range desiredSpec.GetContainers() {
+		desiredNames[container.GetName()] = true
+
+		existing, ok := currentContainers[container.GetName()]
+		if !ok {
+			diff.AddedContainers = append(diff.AddedContainers, container.GetName())
+			continue
+		}
+		if diff.compareContainer(existing, container) {
+			diff.ChangedContainers = append(diff.ChangedContainers, container.GetName())
+		}
+	}
+
+	for _, container := range currentSpec.GetContainers() {
+		if !desiredNames[container.GetName()] {
+			diff.RemovedContainers = append(diff.RemovedContainers, container.GetName())
+		}
+	}
+	return diff
+}
+
+// compareContainer adds the changed container fields to the diff and return true if there was any changes
+func (d *PodDiff) compareContainer(current, desired *containers.Container) (changed bool) {
+	prefix := fmt.Sprintf("spec.containers[%s].", desired.GetName())
+	before := len(d.Changes)
+
+	d.compare(prefix+"image", expandImage(current.GetImage()), expandImage(desired.GetImage()), true)
+	d.compare(prefix+"tty", fmt.Sprint(current.GetTty()), fmt.Sprint(desired.GetTty()), true)
+	if desired.GetWorkingDir() != "" {
+		d.compare(prefix+"workingDir", current.GetWorkingDir(), desired.GetWorkingDir(), true)
+	}
+	if len(desired.GetArgs()) > 0 {
+		d.compare(prefix+"args", strings.Join(current.GetArgs(), " "), strings.Join(desired.GetArgs(), " "), true)
+	}
+	d.compareEnv(prefix+"env", current.GetEnv(), desired.GetEnv())
+	d.compare(prefix+"mounts", formatMounts(current.GetMounts()), formatMounts(desired.GetMounts()), true)
+	d.compare(prefix+"pipe", formatPipe(current.GetPipe()), formatPipe(desired.GetPipe()), true)
+	if desired.GetRestartPolicy() != "" {
+		d.compare(prefix+"restartPolicy", current.GetRestartPolicy(), desired.GetRestartPolicy(), true)
+	}
+
+	return len(d.Changes) > before
+}
+
+func (d *PodDiff) compare(field, current, desired string, requiresRestart bool) {
+	if current != desired {
+		d.Changes = append(d.Changes, FieldChange{
+			Field:           field,
+			Current:         current,
+			Desired:         desired,
+			RequiresRestart: requiresRestart,
+		})
+	}
+}
+
+// compareEnv compares only the desired variables, because the running container has also the image variables
+func (d *PodDiff) compareEnv(field string, current, desired []string) {
+	values := map[string]string{}
+	for _, env := range current {
+		key, value := splitEnv(env)
+		values[key] = value
+	}
+
+	for _, env := range desired {
+		key, value := splitEnv(env)
+		existing, ok := values[key]
+		switch {
+		case !ok:
+			d.compare(fmt.Sprintf("%s[%s]", field, key), "", value, true)
+		case existing != value:
+			d.compare(fmt.Sprintf("%s[%s]", field, key), existing, value, true)
+		}
+	}
+}
+
+func (d *PodDiff) compareLabels(current, desired map[string]string) {
+	keys := map[string]bool{}
+	for key := range current {
+		keys[key] = true
+	}
+	for key := range desired {
+		keys[key] = true
+	}
+
+	sorted := []string{}
+	for key := range keys {
+		sorted = append(sorted, key)
+	}
+	sort.Strings(sorted)
+
+	for _, key := range sorted {
+		d.compare(fmt.Sprintf("metadata.labels[%s]", key), current[key], desired[key], false)
+	}
+}
+
+func splitEnv(env string) (key, value string) {
+	parts := strings.SplitN(env, "=", 2)
+	if len(parts) == 1 {
+		return parts[0], ""
+	}
+	return parts[0], parts[1]
+}
+
+// expandImage expands short image name to fully qualified form, e.g. nginx@sha256:... -> docker.io/library/nginx:latest@sha256:...
+// Empty image is returned as is so that validation can report it.
+func expandImage(image string) string {
+	if image == "" {
+		return ""
+	}
+	if i := strings.Index(image, "@"); i >= 0 {
+		return utils.ExpandToFQIN(image[:i]) + image[i:]
+	}
+	return utils.ExpandToFQIN(image)
+}
+
+func formatMounts(mounts []*containers.Mount) string {
+	result := []string{}
+	for _, mount := range mounts {
+		result = append(result, fmt.Sprintf("%s:%s:%s", mount.GetSource(), mount.GetDestination(), strings.Join(mount.GetOptions(), ",")))
+	}
+	sort.Strings(result)
+	return strings.Join(result, " ")
+}
+
+func formatPipe(pipe *containers.PipeSet) string {
+	return pipe.GetStdout().GetStdin().GetName()
+}
@@ -0,0 +1,74 @@
+package api
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ernoaapa/eliot/pkg/api/core"
+	containers "github.com/ernoaapa/eliot/pkg/api/services/containers/v1"
+	pods "github.com/ernoaapa/eliot/pkg/api/services/pods/v1"
+)
+
+func newDiffTestPod(labels map[string]string, containerList ...*containers.Container) *pods.Pod {
+	return &pods.Pod{
+		Metadata: &core.ResourceMetadata{Name: "my-pod", Labels: labels},
+		Spec:     &pods.PodSpec{Containers: containerList},
+	}
+}
+
+func TestDiffPodNotExist(t *testing.T) {
+	diff := diffPod(nil, newDiffTestPod(nil, &containers.Container{Name: "foo", Image: "alpine"}))
+
+	assert.True(t, diff.Create)
+	assert.Equal(t, []string{"foo"}, diff.AddedContainers)
+	assert.True(t, diff.RequiresRestart())
+}
+
+func TestDiffPodNoChanges(t *testing.T) {
+	current := newDiffTestPod(nil, &containers.Container{
+		Name:  "foo",
+		Image: "docker.io/library/alpine:latest",
+		Args:  []string{"/bin/sh"},
+		Env:   []string{"PATH=/bin", "FOO=bar"},
+	})
+	desired := newDiffTestPod(nil, &containers.Container{
+		Name:  "foo",
+		Image: "alpine",
+		Env:   []string{"FOO=bar"},
+	})
+
+	diff := diffPod(current, desired)
+	assert.True(t, diff.IsEmpty(), "should ignore the image defaults, but found changes: %v", diff.Changes)
+}
+
+func TestDiffPodContainerChanges(t *testing.T) {
+	current := newDiffTestPod(map[string]string{"app": "foo"},
+		&containers.Container{Name: "foo", Image: "alpine:3.7", Env: []string{"FOO=bar"}},
+		&containers.Container{Name: "old", Image: "alpine"},
+	)
+	desired := newDiffTestPod(map[string]string{"app": "bar"},
+		&containers.Container{Name: "foo", Image: "alpine:3.8", Env: []string{"FOO=baz"}},
+		&containers.Container{Name: "new", Image: "alpine"},
+	)
+
+	diff := diffPod(current, desired)
+	assert.False(t, diff.Create)
+	assert.Equal(t, []string{"new"}, diff.AddedContainers)
+	assert.Equal(t, []string{"old"}, diff.RemovedContainers)
+	assert.Equal(t, []string{"foo"}, diff.ChangedContainers)
+	assert.Equal(t, []FieldChange{
+		{Field: "metadata.labels[app]", Current: "foo", Desired: "bar"},
+		{Field: "spec.containers[foo].image", Current: "docker.io/library/alpine:3.7", Desired: "docker.io/library/alpine:3.8", RequiresRestart: true},
+		{Field: "spec.containers[foo].env[FOO]", Current: "bar", Desired: "baz", RequiresRestart: true},
+	}, diff.Changes)
+	assert.True(t, diff.RequiresRestart())
+}
+
+func TestDiffPodCosmeticChanges(t *testing.T) {
+	container := &containers.Container{Name: "foo", Image: "alpine"}
+	diff := diffPod(newDiffTestPod(nil, container), newDiffTestPod(map[string]string{"app": "foo"}, container))
+
+	assert.False(t, diff.IsEmpty())
+	assert.False(t, diff.RequiresRestart(), "label change should not require restart")
+}
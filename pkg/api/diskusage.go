@@ -0,0 +1,93 @@
+package api
+
+import (
+	"fmt"
+	"io"
+	"time"
+
+	containers "github.com/ernoaapa/eliot/pkg/api/services/containers/v1"
+	"golang.org/x/net/context"
+)
+
+// MinDiskUsageInterval is the shortest StreamDiskUsage sampling interval, because resolving the mount usage walks
+// through the mounted directories
+const MinDiskUsageInterval = time.Second
+
+// WithSamplingInterval defines how often StreamDiskUsage sends the disk usage, by default the node sends it every 10s.
+func WithSamplingInterval(interval time.Duration) DiskUsageOpts {
+	return func(req *containers.DiskUsageRequest) error {
+		if interval < MinDiskUsageInterval {
+			return fmt.Errorf("Invalid sampling interval [%s], must be at least %s", interval, MinDiskUsageInterval)
+		}
+		req.Interval = int64(interval)
+		return nil
+	}
+}
+
+// StreamDiskUsage sends the container writable layer size and the usage of each bind mount to the channel
+// periodically, e.g. to alert when the usage grows too fast. Read-only container has zero writable usage.
+// The channel get closed when the context is cancelled or the container stops.
+// Returns ErrContainerNotRunning if the container is not running.
+func (c *Client) StreamDiskUsage(ctx context.Context, containerID string, opts ...DiskUsageOpts) (<-chan DiskUsage, error) {
+	req := &containers.DiskUsageRequest{
+		Namespace:   c.Namespace,
+		ContainerID: containerID,
+	}
+	for _, opt := range opts {
+		if err := opt(req); err != nil {
+			return nil, err
+		}
+	}
+
+	conn, err := c.getConnection()
+	if err != nil {
+		return nil, err
+	}
+
+	stream, err := containers.NewContainersClient(conn).StreamDiskUsage(ctx, req)
+	if err != nil {
+		return nil, translateStatsError(err)
+	}
+
+	// Receive the first usage before returning so that not running container gets reported as error
+	first, err := stream.Recv()
+	if err != nil {
+		return nil, translateStatsError(err)
+	}
+
+	result := make(chan DiskUsage)
+	go func() {
+		defer close(result)
+		for resp := first; ; {
+			select {
+			case result <- mapDiskUsage(resp):
+			case <-ctx.Done():
+				return
+			}
+
+			resp, err = stream.Recv()
+			if err != nil {
+				if err != io.EOF && ctx.Err() == nil {
+					c.logger.Warnf("Container disk usage stream closed with error: %s", translateError(err))
+				}
+				return
+			}
+		}
+	}()
+	return result, nil
+}
+
+func mapDiskUsage(resp *containers.DiskUsageResponse) DiskUsage {
+	usage := DiskUsage{
+		Time:          time.Unix(0, resp.GetTime()),
+		WritableBytes: resp.GetWritableBytes(),
+	}
+	for _, mount := range resp.GetMounts() {
+		usage.Mounts = append(usage.Mounts, MountUsage{
+			Source:      mount.GetSource(),
+			Destination: mount.GetDestination(),
+			UsedBytes:   mount.GetUsedBytes(),
+		})
+	}
+	return usage
+}
@@ -0,0 +1,83 @@
+package api
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ernoaapa/eliot/pkg/runtime"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc"
+)
+
+// diskUsageRuntime returns the usages one by one and then reports that the container has stopped
+type diskUsageRuntime struct {
+	runtime.Client
+	mu     sync.Mutex
+	usages []runtime.DiskUsage
+}
+
+func (r *diskUsageRuntime) GetContainerFilesystemUsage(namespace, name string) (runtime.DiskUsage, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if len(r.usages) == 0 {
+		return runtime.DiskUsage{}, runtime.ErrWithMessagef(runtime.ErrNotRunning, "Container [%s] is not running", name)
+	}
+	usage := r.usages[0]
+	r.usages = r.usages[1:]
+	return usage, nil
+}
+
+// startDiskUsageServer serves the API server with the fake runtime, without node resolver
+func startDiskUsageServer(t *testing.T, fake runtime.Client) (*Client, func()) {
+	return startTestServer(t, func(address string) *grpc.Server {
+		return NewServer(address, fake, nil).grpc
+	})
+}
+
+func TestStreamDiskUsageEndsWhenContainerStops(t *testing.T) {
+	client, stop := startDiskUsageServer(t, &diskUsageRuntime{usages: []runtime.DiskUsage{
+		{Time: time.Unix(1, 0), WritableBytes: 100, Mounts: []runtime.MountUsage{{Source: "/var/lib/volumes/foo", Destination: "/data", UsedBytes: 10}}},
+		// Read-only container
+		{Time: time.Unix(2, 0)},
+	}})
+	defer stop()
+
+	usages, err := client.StreamDiskUsage(context.Background(), "foo", WithSamplingInterval(MinDiskUsageInterval))
+	assert.NoError(t, err)
+
+	received := []DiskUsage{}
+	timeout := time.After(5 * MinDiskUsageInterval)
+	for done := false; !done; {
+		select {
+		case usage, ok := <-usages:
+			if !ok {
+				done = true
+				break
+			}
+			received = append(received, usage)
+		case <-timeout:
+			t.Fatal("StreamDiskUsage didn't close the channel after the container stopped")
+		}
+	}
+
+	assert.Equal(t, []DiskUsage{
+		{Time: time.Unix(1, 0), WritableBytes: 100, Mounts: []MountUsage{{Source: "/var/lib/volumes/foo", Destination: "/data", UsedBytes: 10}}},
+		{Time: time.Unix(2, 0)},
+	}, received)
+}
+
+func TestStreamDiskUsageNotRunning(t *testing.T) {
+	client, stop := startDiskUsageServer(t, &diskUsageRuntime{})
+	defer stop()
+
+	_, err := client.StreamDiskUsage(context.Background(), "foo")
+	assert.True(t, errors.Is(err, ErrContainerNotRunning))
+}
+
+func TestWithSamplingInterval(t *testing.T) {
+	_, err := (&Client{}).StreamDiskUsage(context.Background(), "foo", WithSamplingInterval(100*time.Millisecond))
+	assert.EqualError(t, err, "Invalid sampling interval [100ms], must be at least 1s")
+}
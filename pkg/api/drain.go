@@ -0,0 +1,153 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"syscall"
+	"time"
+
+	"golang.org/x/net/context"
+	"google.golang.org/grpc/codes"
+
+	containers "github.com/ernoaapa/eliot/pkg/api/services/containers/v1"
+	pods "github.com/ernoaapa/eliot/pkg/api/services/pods/v1"
+)
+
+// drainConcurrency is how many pods DrainNamespace stops at the same time
+const drainConcurrency = 4
+
+// DrainResult tells how DrainNamespace stopped the pods, the pod names are sorted
+type DrainResult struct {
+	// Stopped pods exited within the grace period after SIGTERM, or were not running at all
+	Stopped []string
+	// Killed pods had containers which didn't exit within the grace period and got SIGKILL
+	Killed []string
+}
+
+// DrainNamespace stops all pods in the namespace gracefully: containers get SIGTERM and have the grace period
+// time to exit before they get killed with SIGKILL. The pods get cordoned first, so that the restart policy
+// doesn't start the stopped containers again, use UncordonPod to start them. The pods are not deleted.
+// Pods are drained concurrently. If some pod cannot be drained, the others still get drained and
+// DrainError tells which pods failed, the result has the drained ones.
+func (c *Client) DrainNamespace(ctx context.Context, namespace string, grace time.Duration) (*DrainResult, error) {
+	if grace < 0 {
+		return nil, &Error{Code: codes.InvalidArgument, Message: fmt.Sprintf("Invalid grace period [%s], must not be negative", grace)}
+	}
+
+	client := c.WithNamespace(namespace)
+	all, err := client.GetPods(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		mu     sync.Mutex
+		wg     sync.WaitGroup
+		result = &DrainResult{Stopped: []string{}, Killed: []string{}}
+		failed = map[string]error{}
+		slots  = make(chan struct{}, drainConcurrency)
+	)
+	for _, pod := range all {
+		wg.Add(1)
+		go func(name string) {
+			defer wg.Done()
+			slots <- struct{}{}
+			defer func() { <-slots }()
+
+			killed, err := client.drainPod(ctx, name, grace)
+
+			mu.Lock()
+			defer mu.Unlock()
+			switch {
+			case err != nil:
+				failed[name] = err
+			case killed:
+				result.Killed = append(result.Killed, name)
+			default:
+				result.Stopped = append(result.Stopped, name)
+			}
+		}(pod.GetMetadata().GetName())
+	}
+	wg.Wait()
+
+	sort.Strings(result.Stopped)
+	sort.Strings(result.Killed)
+	if len(failed) > 0 {
+		return result, &DrainError{Namespace: namespace, Result: result, Failed: failed}
+	}
+	return result, nil
+}
+
+// drainPod cordons the pod and stops its running containers, return true if some container had to be killed
+func (c *Client) drainPod(ctx context.Context, name string, grace time.Duration) (killed bool, err error) {
+	pod, err := c.CordonPod(ctx, name)
+	if err != nil {
+		return false, err
+	}
+
+	running := runningContainerIDs(pod)
+	for _, id := range running {
+		if err := c.Signal(ctx, id, syscall.SIGTERM); err != nil && !isContainerGone(err) {
+			return false, err
+		}
+	}
+
+	graceCtx, cancel := context.WithTimeout(ctx, grace)
+	defer cancel()
+
+	var (
+		mu        sync.Mutex
+		wg        sync.WaitGroup
+		remaining = []string{}
+		waitErr   error
+	)
+	for _, id := range running {
+		wg.Add(1)
+		go func(id string) {
+			defer wg.Done()
+			_, err := c.WaitContainer(graceCtx, id)
+
+			mu.Lock()
+			defer mu.Unlock()
+			switch {
+			case err == nil || isContainerGone(err):
+			case ctx.Err() == nil && graceCtx.Err() != nil:
+				remaining = append(remaining, id)
+			default:
+				waitErr = err
+			}
+		}(id)
+	}
+	wg.Wait()
+	if waitErr != nil {
+		return false, waitErr
+	}
+
+	for _, id := range remaining {
+		if err := c.Signal(ctx, id, syscall.SIGKILL); err != nil && !isContainerGone(err) {
+			return false, err
+		}
+	}
+	return len(remaining) > 0, nil
+}
+
+// runningContainerIDs return the IDs of the pod init and main containers which are running
+func runningContainerIDs(pod *pods.Pod) []string {
+	ids := []string{}
+	for _, statuses := range [][]*containers.ContainerStatus{pod.GetStatus().GetInitContainerStatuses(), pod.GetStatus().GetContainerStatuses()} {
+		for _, status := range statuses {
+			if MapContainerState(status).Running {
+				ids = append(ids, status.GetContainerID())
+			}
+		}
+	}
+	return ids
+}
+
+// isContainerGone return true if the error tells the container has already exited or been removed
+func isContainerGone(err error) bool {
+	err = translateStatsError(translateContainerError(err))
+	return errors.Is(err, ErrContainerNotRunning) || errors.Is(err, ErrContainerNotFound)
+}
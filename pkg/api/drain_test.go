@@ -0,0 +1,136 @@
+package api
+
+import (
+	"errors"
+	"sync"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/ernoaapa/eliot/pkg/model"
+	"github.com/ernoaapa/eliot/pkg/runtime"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/net/context"
+)
+
+// drainRuntime has pod per container, the container exits on SIGKILL and on SIGTERM unless it ignores it
+type drainRuntime struct {
+	runtime.Client
+	mu       sync.Mutex
+	states   map[string]string
+	ignore   map[string]bool
+	exits    map[string]chan struct{}
+	signals  map[string][]syscall.Signal
+	cordoned map[string]bool
+}
+
+func newDrainRuntime(states map[string]string) *drainRuntime {
+	r := &drainRuntime{
+		states:   states,
+		ignore:   map[string]bool{},
+		exits:    map[string]chan struct{}{},
+		signals:  map[string][]syscall.Signal{},
+		cordoned: map[string]bool{},
+	}
+	for name := range states {
+		r.exits[name] = make(chan struct{})
+	}
+	return r
+}
+
+func (r *drainRuntime) GetPods(namespace string) ([]model.Pod, error) {
+	result := []model.Pod{}
+	for name := range r.states {
+		pod, _ := r.GetPod(namespace, name)
+		result = append(result, pod)
+	}
+	return result, nil
+}
+
+func (r *drainRuntime) GetPod(namespace, name string) (model.Pod, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return model.Pod{
+		Metadata: model.Metadata{Name: name, Namespace: namespace},
+		Status: model.PodStatus{ContainerStatuses: []model.ContainerStatus{
+			{ContainerID: name, Name: name, State: r.states[name]},
+		}},
+	}, nil
+}
+
+func (r *drainRuntime) SetPodCordoned(namespace, name string, cordoned bool) error {
+	if name == "broken" {
+		return errors.New("cordon failed")
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.cordoned[name] = cordoned
+	return nil
+}
+
+func (r *drainRuntime) Signal(namespace, name string, signal syscall.Signal) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.signals[name] = append(r.signals[name], signal)
+	if signal == syscall.SIGKILL || !r.ignore[name] {
+		r.states[name] = "stopped"
+		close(r.exits[name])
+	}
+	return nil
+}
+
+func (r *drainRuntime) WaitContainer(namespace, name string, done <-chan struct{}) (int, error) {
+	r.mu.Lock()
+	exit := r.exits[name]
+	r.mu.Unlock()
+	select {
+	case <-exit:
+		return 0, nil
+	case <-done:
+		return -1, runtime.ErrWithMessagef(context.Canceled, "Wait of container [%s] cancelled", name)
+	}
+}
+
+func TestDrainNamespace(t *testing.T) {
+	fake := newDrainRuntime(map[string]string{"web": "running", "stubborn": "running", "done": "stopped"})
+	fake.ignore["stubborn"] = true
+	client, stop := startDiskUsageServer(t, fake)
+	defer stop()
+
+	start := time.Now()
+	result, err := client.DrainNamespace(context.Background(), "apps", 200*time.Millisecond)
+	assert.NoError(t, err)
+	assert.True(t, time.Since(start) < 5*time.Second, "should not wait longer than the grace period, took %s", time.Since(start))
+
+	assert.Equal(t, []string{"done", "web"}, result.Stopped)
+	assert.Equal(t, []string{"stubborn"}, result.Killed)
+
+	assert.Equal(t, []syscall.Signal{syscall.SIGTERM}, fake.signals["web"])
+	assert.Equal(t, []syscall.Signal{syscall.SIGTERM, syscall.SIGKILL}, fake.signals["stubborn"])
+	assert.Empty(t, fake.signals["done"], "should not signal stopped containers")
+	assert.Equal(t, map[string]bool{"web": true, "stubborn": true, "done": true}, fake.cordoned)
+}
+
+func TestDrainNamespaceReportsFailedPods(t *testing.T) {
+	fake := newDrainRuntime(map[string]string{"web": "running", "broken": "running"})
+	client, stop := startDiskUsageServer(t, fake)
+	defer stop()
+
+	result, err := client.DrainNamespace(context.Background(), "apps", time.Second)
+	assert.Error(t, err)
+	drainErr, ok := err.(*DrainError)
+	assert.True(t, ok, "should return DrainError, got: %T", err)
+	assert.Contains(t, drainErr.Failed["broken"].Error(), "cordon failed")
+	assert.Contains(t, err.Error(), "Failed to drain 1 of 2 pods in namespace [apps]")
+
+	assert.Equal(t, []string{"web"}, result.Stopped)
+	assert.Empty(t, fake.signals["broken"], "should not stop pod which couldn't be cordoned")
+}
+
+func TestDrainNamespaceInvalidGracePeriod(t *testing.T) {
+	client, stop := startDiskUsageServer(t, newDrainRuntime(map[string]string{}))
+	defer stop()
+
+	_, err := client.DrainNamespace(context.Background(), "apps", -time.Second)
+	assert.True(t, errors.Is(err, ErrInvalidArgument))
+}
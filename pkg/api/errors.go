@@ -0,0 +1,278 @@
+package api
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/ernoaapa/eliot/pkg/runtime"
+	"github.com/pkg/errors"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Definitions of common error types returned by the Client.
+// The server errors get translated from the gRPC status code to these errors
+// so you can check the type with errors.Is, e.g. errors.Is(err, api.ErrUnavailable)
+var (
+	ErrNotFound           = &Error{Code: codes.NotFound, Message: "not found"}
+	ErrAlreadyExists      = &Error{Code: codes.AlreadyExists, Message: "already exists"}
+	ErrInvalidArgument    = &Error{Code: codes.InvalidArgument, Message: "invalid argument"}
+	ErrFailedPrecondition = &Error{Code: codes.FailedPrecondition, Message: "failed precondition"}
+	ErrPermissionDenied   = &Error{Code: codes.PermissionDenied, Message: "permission denied"}
+	ErrUnauthenticated    = &Error{Code: codes.Unauthenticated, Message: "unauthenticated"}
+	ErrUnavailable        = &Error{Code: codes.Unavailable, Message: "unavailable"}
+	ErrDeadlineExceeded   = &Error{Code: codes.DeadlineExceeded, Message: "deadline exceeded"}
+	ErrCanceled           = &Error{Code: codes.Canceled, Message: "canceled"}
+	ErrAborted            = &Error{Code: codes.Aborted, Message: "aborted"}
+	ErrResourceExhausted  = &Error{Code: codes.ResourceExhausted, Message: "resource exhausted"}
+	ErrUnimplemented      = &Error{Code: codes.Unimplemented, Message: "unimplemented"}
+	ErrInternal           = &Error{Code: codes.Internal, Message: "internal error"}
+
+	// ErrConnectionLost is returned when the connection breaks in the middle of stream, e.g. in Attach or WatchPods,
+	// because the server didn't respond to the keepalive ping. See WithKeepalive.
+	// The error matches also to ErrUnavailable.
+	ErrConnectionLost = errors.New("connection lost")
+
+	// ErrPodNotFound is returned when pod with the name doesn't exist.
+	// The error matches also to ErrNotFound.
+	ErrPodNotFound = errors.New("pod not found")
+
+	// ErrContainerNotFound is returned when the pod doesn't have container with the name.
+	// The error matches also to ErrNotFound.
+	ErrContainerNotFound = errors.New("container not found")
+
+	// ErrContainerNotRunning is returned when the operation requires running container, e.g. ContainerStats.
+	// The error matches also to ErrFailedPrecondition.
+	ErrContainerNotRunning = errors.New("container not running")
+
+	// ErrAttachIdleTimeout is returned when no data is sent or received within the AttachIO IdleTimeout.
+	// The error matches also to ErrDeadlineExceeded.
+	ErrAttachIdleTimeout = errors.New("attach idle timeout")
+
+	// ErrDetached is returned by Attach when the user typed the AttachIO DetachKeys sequence.
+	// The container keeps running and can be attached again. The error matches also to ErrCanceled.
+	ErrDetached = errors.New("detached")
+
+	// ErrPodNotReady is returned when the pod containers are not running and ready within the WaitForPodReady timeout.
+	// The error matches also to ErrDeadlineExceeded.
+	ErrPodNotReady = errors.New("pod not ready")
+
+	// ErrInitContainerFailed is returned by WaitForPodReady when init container has failed and won't be restarted,
+	// so the pod containers never get started. The error matches also to ErrFailedPrecondition.
+	ErrInitContainerFailed = errors.New("init container failed")
+
+	// ErrPodVersionConflict is returned by UpdatePod when the pod has changed since the resource version
+	// in the pod metadata, e.g. because someone else updated it at the same time.
+	// The error matches also to ErrAborted.
+	ErrPodVersionConflict = errors.New("pod version conflict")
+
+	// ErrCheckpointUnsupported is returned by Checkpoint and Restore when the node container runtime
+	// cannot checkpoint or restore containers, usually because CRIU is not installed.
+	// The error matches also to ErrUnimplemented.
+	ErrCheckpointUnsupported = errors.New("checkpoint unsupported")
+
+	// ErrRegistryUnauthorized is returned by PushImage when the registry rejects the PushOptions credentials,
+	// or requires credentials but none were given. The error matches also to ErrUnauthenticated.
+	ErrRegistryUnauthorized = errors.New("registry unauthorized")
+
+	// ErrMessageTooLarge is returned when the request or response message exceeds the gRPC message size limit,
+	// see WithMaxRecvMsgSize and WithMaxSendMsgSize. The error matches also to ErrResourceExhausted.
+	ErrMessageTooLarge = errors.New("message too large")
+)
+
+// Error is error returned by the Client which carries the gRPC status code
+type Error struct {
+	Code    codes.Code
+	Message string
+	cause   error
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+// Is reports whether the target error has the same status code,
+// this makes errors.Is(err, ErrUnavailable) to match any Unavailable error
+func (e *Error) Is(target error) bool {
+	t, ok := target.(*Error)
+	return ok && t.Code == e.Code
+}
+
+// Unwrap returns the more specific error, e.g. ErrPodNotFound
+func (e *Error) Unwrap() error {
+	return e.cause
+}
+
+// translateError converts gRPC status errors returned by the server to Error
+func translateError(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	if _, ok := err.(*Error); ok {
+		return err
+	}
+
+	switch err {
+	case context.Canceled:
+		return &Error{Code: codes.Canceled, Message: err.Error()}
+	case context.DeadlineExceeded:
+		return &Error{Code: codes.DeadlineExceeded, Message: err.Error()}
+	}
+
+	// Stream helpers wrap the status error with context, e.g. "Received error while reading attach stream"
+	if s, ok := status.FromError(errors.Cause(err)); ok {
+		if isConnectionLost(s) {
+			return &Error{Code: s.Code(), Message: s.Message(), cause: ErrConnectionLost}
+		}
+		if isMessageTooLarge(s) {
+			return &Error{
+				Code:    s.Code(),
+				Message: fmt.Sprintf("%s, the client limits can be raised with WithMaxRecvMsgSize and WithMaxSendMsgSize", s.Message()),
+				cause:   ErrMessageTooLarge,
+			}
+		}
+		return &Error{Code: s.Code(), Message: s.Message()}
+	}
+	return err
+}
+
+// toStatusError converts runtime errors to gRPC status errors so the client can resolve the error type
+func toStatusError(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	cause := errors.Cause(err)
+	if s, ok := status.FromError(cause); ok {
+		if cause == err {
+			return err
+		}
+		return status.Error(s.Code(), err.Error())
+	}
+
+	switch cause {
+	case runtime.ErrNotFound:
+		return status.Error(codes.NotFound, err.Error())
+	case runtime.ErrAlreadyExists:
+		return status.Error(codes.AlreadyExists, err.Error())
+	case runtime.ErrNotSupported:
+		return status.Error(codes.FailedPrecondition, err.Error())
+	case runtime.ErrNotRunning:
+		return status.Error(codes.FailedPrecondition, err.Error())
+	case runtime.ErrUnauthorized:
+		return status.Error(codes.Unauthenticated, err.Error())
+	case context.Canceled:
+		return status.Error(codes.Canceled, err.Error())
+	case context.DeadlineExceeded:
+		return status.Error(codes.DeadlineExceeded, err.Error())
+	}
+	return status.Error(codes.Unknown, err.Error())
+}
+
+func unaryErrorInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	resp, err := handler(ctx, req)
+	return resp, toStatusError(err)
+}
+
+func streamErrorInterceptor(srv interface{}, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	return toStatusError(handler(srv, stream))
+}
+
+// CreatePodsError is returned by CreatePods when some of the pods fail.
+// Created lists the pods which were created successfully, so only the failed ones need to be retried.
+type CreatePodsError struct {
+	Created []string
+	Failed  map[string]error
+}
+
+func (e *CreatePodsError) Error() string {
+	names := []string{}
+	for name := range e.Failed {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	failures := []string{}
+	for _, name := range names {
+		failures = append(failures, fmt.Sprintf("%s: %s", name, e.Failed[name]))
+	}
+	return fmt.Sprintf("Failed to create %d of %d pods: %s", len(e.Failed), len(e.Failed)+len(e.Created), strings.Join(failures, ", "))
+}
+
+// DeletePodsError is returned by DeletePodsByLabel when some of the pods fail.
+// Deleted lists the pods which were deleted successfully.
+type DeletePodsError struct {
+	Deleted []string
+	Failed  map[string]error
+}
+
+func (e *DeletePodsError) Error() string {
+	names := []string{}
+	for name := range e.Failed {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	failures := []string{}
+	for _, name := range names {
+		failures = append(failures, fmt.Sprintf("%s: %s", name, e.Failed[name]))
+	}
+	return fmt.Sprintf("Failed to delete %d of %d pods: %s", len(e.Failed), len(e.Failed)+len(e.Deleted), strings.Join(failures, ", "))
+}
+
+// SignalPodError is returned by SignalPod when some of the containers didn't get the signal.
+// Skipped lists the containers which were not running, Failed the containers where signaling failed.
+type SignalPodError struct {
+	Pod     string
+	Skipped []string
+	Failed  map[string]error
+}
+
+func (e *SignalPodError) Error() string {
+	problems := []string{}
+	if len(e.Skipped) > 0 {
+		problems = append(problems, fmt.Sprintf("skipped not running containers [%s]", strings.Join(e.Skipped, ", ")))
+	}
+
+	names := []string{}
+	for name := range e.Failed {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		problems = append(problems, fmt.Sprintf("failed to signal container [%s]: %s", name, e.Failed[name]))
+	}
+	return fmt.Sprintf("Not all containers in pod [%s] got the signal: %s", e.Pod, strings.Join(problems, ", "))
+}
+
+// IsOnlySkipped return true if all running containers got the signal and only the stopped ones were skipped
+func (e *SignalPodError) IsOnlySkipped() bool {
+	return len(e.Failed) == 0
+}
+
+// DrainError is returned by DrainNamespace when some of the pods couldn't be drained.
+// Result tells how the other pods got stopped.
+type DrainError struct {
+	Namespace string
+	Result    *DrainResult
+	Failed    map[string]error
+}
+
+func (e *DrainError) Error() string {
+	names := []string{}
+	for name := range e.Failed {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	failures := []string{}
+	for _, name := range names {
+		failures = append(failures, fmt.Sprintf("%s: %s", name, e.Failed[name]))
+	}
+	total := len(e.Failed) + len(e.Result.Stopped) + len(e.Result.Killed)
+	return fmt.Sprintf("Failed to drain %d of %d pods in namespace [%s]: %s", len(e.Failed), total, e.Namespace, strings.Join(failures, ", "))
+}
@@ -0,0 +1,86 @@
+package api
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/ernoaapa/eliot/pkg/runtime"
+	pkgerrors "github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestTranslateError(t *testing.T) {
+	assert.NoError(t, translateError(nil))
+
+	err := translateError(status.Error(codes.Unavailable, "connection refused"))
+	assert.True(t, errors.Is(err, ErrUnavailable))
+	assert.False(t, errors.Is(err, ErrNotFound))
+	assert.Equal(t, "connection refused", err.Error())
+
+	assert.True(t, errors.Is(translateError(context.DeadlineExceeded), ErrDeadlineExceeded))
+	assert.True(t, errors.Is(translateError(status.Error(codes.InvalidArgument, "bad")), ErrInvalidArgument))
+}
+
+func TestPodNotFoundErrorMatchesNotFound(t *testing.T) {
+	err := &Error{Code: codes.NotFound, Message: "Pod with name [foo] not found", cause: ErrPodNotFound}
+	assert.True(t, errors.Is(err, ErrPodNotFound))
+	assert.True(t, errors.Is(err, ErrNotFound))
+	assert.False(t, errors.Is(err, ErrUnavailable))
+}
+
+func TestToStatusError(t *testing.T) {
+	assert.NoError(t, toStatusError(nil))
+
+	err := toStatusError(pkgerrors.Wrapf(runtime.ErrWithMessagef(runtime.ErrNotFound, "Pod [foo] not found"), "Failed to start"))
+	assert.Equal(t, codes.NotFound, status.Code(err))
+	assert.Equal(t, "Failed to start: Pod [foo] not found: not found", status.Convert(err).Message())
+
+	assert.Equal(t, codes.AlreadyExists, status.Code(toStatusError(runtime.ErrAlreadyExists)))
+	assert.Equal(t, codes.Unauthenticated, status.Code(toStatusError(runtime.ErrUnauthorized)))
+	assert.Equal(t, codes.InvalidArgument, status.Code(toStatusError(pkgerrors.Wrapf(status.Error(codes.InvalidArgument, "invalid"), "Cannot start"))))
+	assert.Equal(t, codes.Unknown, status.Code(toStatusError(errors.New("something"))))
+}
+
+func TestTranslateStatsError(t *testing.T) {
+	err := translateStatsError(status.Error(codes.FailedPrecondition, "Container [foo] is not running"))
+	assert.True(t, errors.Is(err, ErrContainerNotRunning))
+	assert.True(t, errors.Is(err, ErrFailedPrecondition))
+
+	assert.False(t, errors.Is(translateStatsError(status.Error(codes.NotFound, "not found")), ErrContainerNotRunning))
+}
+
+func TestCreatePodsError(t *testing.T) {
+	err := &CreatePodsError{
+		Created: []string{"foo"},
+		Failed: map[string]error{
+			"baz": errors.New("image not found"),
+			"bar": errors.New("already exists"),
+		},
+	}
+	assert.Equal(t, "Failed to create 2 of 3 pods: bar: already exists, baz: image not found", err.Error())
+}
+
+func TestDeletePodsError(t *testing.T) {
+	err := &DeletePodsError{
+		Deleted: []string{"foo", "bar"},
+		Failed: map[string]error{
+			"baz": errors.New("failed to stop container"),
+		},
+	}
+	assert.Equal(t, "Failed to delete 1 of 3 pods: baz: failed to stop container", err.Error())
+}
+
+func TestSignalPodError(t *testing.T) {
+	err := &SignalPodError{
+		Pod:     "my-pod",
+		Skipped: []string{"foo"},
+		Failed:  map[string]error{"bar": errors.New("permission denied")},
+	}
+	assert.Equal(t, "Not all containers in pod [my-pod] got the signal: skipped not running containers [foo], failed to signal container [bar]: permission denied", err.Error())
+	assert.False(t, err.IsOnlySkipped())
+
+	assert.True(t, (&SignalPodError{Pod: "my-pod", Skipped: []string{"foo"}}).IsOnlySkipped())
+}
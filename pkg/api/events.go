@@ -0,0 +1,174 @@
+package api
+
+import (
+	"fmt"
+	"io"
+	"time"
+
+	node "github.com/ernoaapa/eliot/pkg/api/services/node/v1"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc/codes"
+)
+
+// eventsReconnectBackoff is the initial wait before opening the events stream again after the connection broke
+const eventsReconnectBackoff = time.Second
+
+// EventType tells what happened in the node runtime
+type EventType string
+
+// Node event types
+const (
+	EventImagePulled          EventType = "ImagePulled"
+	EventImagePullFailed      EventType = "ImagePullFailed"
+	EventContainerCreated     EventType = "ContainerCreated"
+	EventContainerStarted     EventType = "ContainerStarted"
+	EventContainerStartFailed EventType = "ContainerStartFailed"
+	EventContainerExited      EventType = "ContainerExited"
+	EventContainerOOMKilled   EventType = "ContainerOOMKilled"
+	EventContainerDeleted     EventType = "ContainerDeleted"
+)
+
+var eventTypes = []EventType{
+	EventImagePulled,
+	EventImagePullFailed,
+	EventContainerCreated,
+	EventContainerStarted,
+	EventContainerStartFailed,
+	EventContainerExited,
+	EventContainerOOMKilled,
+	EventContainerDeleted,
+}
+
+// Event is single thing that happened in the node runtime, e.g. container got killed because out of memory
+type Event struct {
+	Type      EventType
+	Namespace string
+	// Pod is empty if the event is not related to any pod, e.g. image events
+	Pod string
+	// ContainerID is empty if the event is not related to any container
+	ContainerID string
+	// Image is set in image events
+	Image   string
+	Time    time.Time
+	Message string
+}
+
+// String returns human readable description of the event
+func (e Event) String() string {
+	switch {
+	case e.ContainerID != "":
+		return fmt.Sprintf("%s %s/%s (%s): %s", e.Type, e.Namespace, e.Pod, e.ContainerID, e.Message)
+	case e.Image != "":
+		return fmt.Sprintf("%s %s: %s", e.Type, e.Image, e.Message)
+	}
+	return fmt.Sprintf("%s: %s", e.Type, e.Message)
+}
+
+// EventsOptions defines which events to stream
+type EventsOptions struct {
+	// Types limits the events to given types, empty means all types
+	Types []EventType
+	// AllNamespaces streams the events in every namespace instead of only the client namespace
+	AllNamespaces bool
+}
+
+// Events streams the node runtime events, e.g. image pull failures, container exits and OOM kills.
+// Returns ErrInvalidArgument if some of the types is unknown.
+// If the connection breaks, the stream gets opened again and the events in between are lost.
+// The channel get closed when the context is cancelled or the server closes the stream with non retryable error,
+// in which case the error is logged.
+func (c *Client) Events(ctx context.Context, opts EventsOptions) (<-chan Event, error) {
+	req := &node.EventsRequest{}
+	if !opts.AllNamespaces {
+		req.Namespace = c.Namespace
+	}
+	for _, t := range opts.Types {
+		if !isEventType(t) {
+			return nil, &Error{Code: codes.InvalidArgument, Message: fmt.Sprintf("Invalid event type [%s], must be one of %v", t, eventTypes)}
+		}
+		req.Types = append(req.Types, string(t))
+	}
+
+	stream, err := c.openEvents(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	events := make(chan Event)
+	go func() {
+		defer close(events)
+		reconnect := retryPolicy{backoff: eventsReconnectBackoff, logger: c.logger}
+		for attempt := 0; ; {
+			resp, err := stream.Recv()
+			if err == nil {
+				attempt = 0
+				select {
+				case events <- mapEvent(resp):
+				case <-ctx.Done():
+					return
+				}
+				continue
+			}
+
+			err = translateError(err)
+			if ctx.Err() != nil {
+				return
+			}
+			if err != io.EOF && !isRetryable(err) {
+				c.logger.Warnf("Events stream closed with error: %s", err)
+				return
+			}
+
+			attempt++
+			wait := reconnect.getBackoff(attempt)
+			c.logger.Debugf("Events stream closed, open again in %s: %s", wait, err)
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(wait):
+			}
+
+			if next, err := c.openEvents(ctx, req); err == nil {
+				stream = next
+			} else if ctx.Err() == nil && !isRetryable(err) {
+				c.logger.Warnf("Failed to open events stream again: %s", err)
+				return
+			}
+		}
+	}()
+	return events, nil
+}
+
+func (c *Client) openEvents(ctx context.Context, req *node.EventsRequest) (node.Node_EventsClient, error) {
+	conn, err := c.getConnection()
+	if err != nil {
+		return nil, err
+	}
+
+	stream, err := node.NewNodeClient(conn).Events(ctx, req)
+	if err != nil {
+		return nil, translateError(err)
+	}
+	return stream, nil
+}
+
+func isEventType(eventType EventType) bool {
+	for _, t := range eventTypes {
+		if t == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+func mapEvent(event *node.Event) Event {
+	return Event{
+		Type:        EventType(event.Type),
+		Namespace:   event.Namespace,
+		Pod:         event.Pod,
+		ContainerID: event.ContainerID,
+		Image:       event.Image,
+		Time:        time.Unix(0, event.Time),
+		Message:     event.Message,
+	}
+}
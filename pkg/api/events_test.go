@@ -0,0 +1,98 @@
+package api
+
+import (
+	"errors"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/ernoaapa/eliot/pkg/config"
+	"github.com/ernoaapa/eliot/pkg/runtime"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/net/context"
+)
+
+// eventsRuntime is runtime which sends the events and then waits until the stream is done
+type eventsRuntime struct {
+	runtime.Client
+	events    []runtime.Event
+	namespace chan string
+}
+
+func (r *eventsRuntime) Events(namespace string, done <-chan struct{}, handler func(runtime.Event) error) error {
+	r.namespace <- namespace
+	for _, event := range r.events {
+		if err := handler(event); err != nil {
+			return err
+		}
+	}
+	<-done
+	return nil
+}
+
+func startEventsServer(t *testing.T, events ...runtime.Event) (*Client, *eventsRuntime) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+	fake := &eventsRuntime{events: events, namespace: make(chan string, 10)}
+	server := NewServer(listener.Addr().String(), fake, nil)
+	go server.grpc.Serve(listener)
+
+	client, err := NewClient("eliot", config.Endpoint{URL: listener.Addr().String()}, WithInsecure())
+	assert.NoError(t, err)
+	return client, fake
+}
+
+func TestEventsFiltersByType(t *testing.T) {
+	now := time.Unix(0, time.Now().UnixNano())
+	client, fake := startEventsServer(t,
+		runtime.Event{Type: runtime.EventContainerStarted, Namespace: "eliot", PodName: "foo", ContainerID: "bar", Time: now},
+		runtime.Event{Type: runtime.EventContainerOOMKilled, Namespace: "eliot", PodName: "foo", ContainerID: "bar", Time: now, Message: "out of memory"},
+		runtime.Event{Type: runtime.EventImagePullFailed, Namespace: "eliot", Image: "docker.io/library/foo:latest", Time: now},
+	)
+	defer client.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	events, err := client.Events(ctx, EventsOptions{Types: []EventType{EventContainerOOMKilled, EventImagePullFailed}})
+	assert.NoError(t, err)
+	assert.Equal(t, "eliot", <-fake.namespace)
+
+	assert.Equal(t, Event{
+		Type:        EventContainerOOMKilled,
+		Namespace:   "eliot",
+		Pod:         "foo",
+		ContainerID: "bar",
+		Time:        now,
+		Message:     "out of memory",
+	}, <-events)
+	event := <-events
+	assert.Equal(t, EventImagePullFailed, event.Type)
+	assert.Equal(t, "docker.io/library/foo:latest", event.Image)
+
+	cancel()
+	select {
+	case _, ok := <-events:
+		assert.False(t, ok, "should not receive more events")
+	case <-time.After(5 * time.Second):
+		t.Fatal("Events channel didn't close when the context got cancelled")
+	}
+}
+
+func TestEventsAllNamespaces(t *testing.T) {
+	client, fake := startEventsServer(t)
+	defer client.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	_, err := client.Events(ctx, EventsOptions{AllNamespaces: true})
+	assert.NoError(t, err)
+	assert.Equal(t, "", <-fake.namespace)
+}
+
+func TestEventsRejectsUnknownType(t *testing.T) {
+	client, err := NewClient("eliot", config.Endpoint{URL: "localhost:5000"}, WithInsecure())
+	assert.NoError(t, err)
+
+	_, err = client.Events(context.Background(), EventsOptions{Types: []EventType{"Unknown"}})
+	assert.True(t, errors.Is(err, ErrInvalidArgument), "expected invalid argument, but got %v", err)
+}
@@ -0,0 +1,118 @@
+package api
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"golang.org/x/net/context"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// failoverDialTimeout is how long to wait connection to single server before trying the next one,
+// if not defined with WithDialTimeout
+const failoverDialTimeout = 5 * time.Second
+
+// dialFailover connects to the first reachable server, starting from the currently active one.
+// Must be called without holding the pool lock.
+func (c *Client) dialFailover(opts []grpc.DialOption) (*grpc.ClientConn, error) {
+	timeout := c.dialTimeout
+	if timeout <= 0 {
+		timeout = failoverDialTimeout
+	}
+
+	c.pool.mu.Lock()
+	active := c.pool.active
+	c.pool.mu.Unlock()
+
+	failures := []string{}
+	for i := 0; i < len(c.servers); i++ {
+		index := (active + i) % len(c.servers)
+		server := c.servers[index]
+
+		conn, err := dialWithTimeout(server, timeout, opts)
+		if err != nil {
+			c.logger.Debugf("Failed to connect to server [%s], trying next one: %s", server.URL, err)
+			failures = append(failures, server.URL)
+			continue
+		}
+
+		c.pool.mu.Lock()
+		c.pool.active = index
+		c.pool.mu.Unlock()
+		return conn, nil
+	}
+
+	return nil, &Error{
+		Code:    codes.Unavailable,
+		Message: fmt.Sprintf("Failed to connect to any of the servers [%s]", strings.Join(failures, ", ")),
+	}
+}
+
+// connectionFailed drops the connections to the failed server so that the next call connects to the next server.
+// Does nothing if the client has already switched to another server.
+func (c *Client) connectionFailed(pc *pooledConn) {
+	c.pool.mu.Lock()
+	defer c.pool.mu.Unlock()
+
+	if c.servers[c.pool.active].GetAddress() != pc.address {
+		return
+	}
+
+	c.logger.Debugf("Connection to server [%s] failed, switching to next server", c.servers[c.pool.active].URL)
+	c.pool.discardAddress(pc.address)
+	c.pool.active = (c.pool.active + 1) % len(c.servers)
+}
+
+// failoverUnaryInterceptor gets called by invokeUnary when there's multiple servers.
+// It calls the method again through the next server when the current one is unavailable
+func (c *Client) failoverUnaryInterceptor(ctx context.Context, invoke func(conn *grpc.ClientConn) error) error {
+	var err error
+	for attempt := 0; attempt < len(c.servers); attempt++ {
+		pc, dialErr := c.acquireConnection()
+		if dialErr != nil {
+			if err != nil {
+				return err
+			}
+			return dialErr
+		}
+
+		err = invoke(pc.conn)
+		c.releaseConnection(pc)
+		if status.Code(err) != codes.Unavailable || ctx.Err() != nil {
+			return err
+		}
+		c.connectionFailed(pc)
+	}
+	return err
+}
+
+// failoverStreamInterceptor gets called by openStream when there's multiple servers.
+// It opens the stream through the next server when the current one is unavailable.
+// Once the stream is established, it's bound to the server and errors in the middle of stream are returned as is.
+func (c *Client) failoverStreamInterceptor(ctx context.Context, open func(conn *grpc.ClientConn) (grpc.ClientStream, error)) (grpc.ClientStream, error) {
+	var err error
+	for attempt := 0; attempt < len(c.servers); attempt++ {
+		pc, dialErr := c.acquireConnection()
+		if dialErr != nil {
+			if err != nil {
+				return nil, err
+			}
+			return nil, dialErr
+		}
+
+		var stream grpc.ClientStream
+		stream, err = open(pc.conn)
+		if err == nil {
+			return newPooledStream(ctx, stream, func() { c.releaseConnection(pc) }), nil
+		}
+		c.releaseConnection(pc)
+		if status.Code(err) != codes.Unavailable || ctx.Err() != nil {
+			return nil, err
+		}
+		c.connectionFailed(pc)
+	}
+	return nil, err
+}
@@ -0,0 +1,126 @@
+package api
+
+import (
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc"
+
+	node "github.com/ernoaapa/eliot/pkg/api/services/node/v1"
+	"github.com/ernoaapa/eliot/pkg/config"
+)
+
+type fakeNodeServer struct {
+	node.NodeServer
+	hostname string
+}
+
+func (s *fakeNodeServer) Info(context.Context, *node.InfoRequest) (*node.InfoResponse, error) {
+	return &node.InfoResponse{Info: &node.Info{Hostname: s.hostname}}, nil
+}
+
+func startFakeNodeServer(t *testing.T, hostname string) (address string, stop func()) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+
+	server := grpc.NewServer()
+	node.RegisterNodeServer(server, &fakeNodeServer{hostname: hostname})
+	go server.Serve(listener)
+
+	return listener.Addr().String(), server.Stop
+}
+
+func getUnusedAddress(t *testing.T) string {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+	defer listener.Close()
+	return listener.Addr().String()
+}
+
+func TestFailoverToNextServer(t *testing.T) {
+	unused := getUnusedAddress(t)
+	backup, stop := startFakeNodeServer(t, "backup")
+	defer stop()
+
+	client, err := NewClient("eliot", config.Endpoint{Name: "primary", URL: unused}, WithInsecure(), WithDialTimeout(500*time.Millisecond), WithServers([]string{backup}))
+	assert.NoError(t, err)
+	defer client.Close()
+
+	info, err := client.GetInfo(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, "backup", info.Hostname)
+	assert.Equal(t, backup, client.ActiveEndpoint().URL, "should return the connected server")
+	assert.Equal(t, unused, client.Endpoint.URL, "should keep the given endpoint")
+	assert.Equal(t, backup, client.WithNamespace("other").ActiveEndpoint().URL, "should share the active server with the namespace client")
+}
+
+func TestFailoverConcurrentCalls(t *testing.T) {
+	unused := getUnusedAddress(t)
+	backup, stop := startFakeNodeServer(t, "backup")
+	defer stop()
+
+	client, err := NewClient("eliot", config.Endpoint{Name: "primary", URL: unused}, WithInsecure(), WithDialTimeout(500*time.Millisecond), WithServers([]string{backup}))
+	assert.NoError(t, err)
+	defer client.Close()
+	other := client.WithNamespace("other")
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func(client *Client) {
+			defer wg.Done()
+			_, err := client.GetInfo(context.Background())
+			assert.NoError(t, err)
+			assert.Equal(t, backup, client.ActiveEndpoint().URL)
+		}([]*Client{client, other}[i%2])
+	}
+	wg.Wait()
+}
+
+func TestFailoverWhenActiveServerStops(t *testing.T) {
+	first, stopFirst := startFakeNodeServer(t, "first")
+	second, stopSecond := startFakeNodeServer(t, "second")
+	defer stopSecond()
+
+	client, err := NewClient("eliot", config.Endpoint{Name: "first", URL: first}, WithInsecure(), WithDialTimeout(500*time.Millisecond), WithServers([]string{second}))
+	assert.NoError(t, err)
+	defer client.Close()
+
+	info, err := client.GetInfo(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, "first", info.Hostname)
+
+	info, err = client.GetInfo(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, "first", info.Hostname, "should keep using the active server")
+
+	stopFirst()
+
+	info, err = client.GetInfo(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, "second", info.Hostname)
+}
+
+func TestFailoverAllServersUnavailable(t *testing.T) {
+	client, err := NewClient("eliot", config.Endpoint{URL: getUnusedAddress(t)}, WithInsecure(), WithDialTimeout(500*time.Millisecond), WithServers([]string{getUnusedAddress(t)}))
+	assert.NoError(t, err)
+
+	_, err = client.GetInfo(context.Background())
+	assert.Error(t, err)
+	assert.True(t, isRetryable(err), "should return Unavailable error")
+}
+
+func TestDialTimeout(t *testing.T) {
+	client, err := NewClient("eliot", config.Endpoint{URL: getUnusedAddress(t)}, WithInsecure(), WithDialTimeout(200*time.Millisecond))
+	assert.NoError(t, err)
+
+	start := time.Now()
+	_, err = client.GetInfo(context.Background())
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "Could not connect")
+	assert.True(t, time.Since(start) < 2*time.Second, "should fail within the dial timeout")
+}
@@ -0,0 +1,113 @@
+package api
+
+import (
+	"io"
+	"time"
+
+	containers "github.com/ernoaapa/eliot/pkg/api/services/containers/v1"
+	"golang.org/x/net/context"
+)
+
+// watchFileReconnectBackoff is the initial wait before opening the file watch stream again after the connection broke
+const watchFileReconnectBackoff = time.Second
+
+// FileOp is the kind of change to the watched file
+type FileOp string
+
+// File operations what WatchFile reports
+const (
+	FileCreated FileOp = "create"
+	FileWritten FileOp = "write"
+	FileRemoved FileOp = "remove"
+)
+
+// FileEvent is change to the watched file in the container
+type FileEvent struct {
+	Op   FileOp
+	Path string
+	Time time.Time
+}
+
+// WatchFile streams the changes of the file in the container, e.g. to reload when config file gets written.
+// The file doesn't need to exist, the create event is sent when it gets created.
+// Write event is sent when the file get closed after writing, so each save is reported once.
+// If the connection breaks, the stream gets opened again and the changes in between are lost.
+// The channel get closed when the context is cancelled or the server closes the stream with non retryable error,
+// in which case the error is logged.
+func (c *Client) WatchFile(ctx context.Context, containerID, path string) (<-chan FileEvent, error) {
+	req := &containers.WatchFileRequest{
+		Namespace:   c.Namespace,
+		ContainerID: containerID,
+		Path:        path,
+	}
+
+	stream, err := c.openWatchFile(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	events := make(chan FileEvent)
+	go func() {
+		defer close(events)
+		reconnect := retryPolicy{backoff: watchFileReconnectBackoff, logger: c.logger}
+		for attempt := 0; ; {
+			resp, err := stream.Recv()
+			if err == nil {
+				attempt = 0
+				select {
+				case events <- mapFileEvent(resp):
+				case <-ctx.Done():
+					return
+				}
+				continue
+			}
+
+			err = translateError(err)
+			if ctx.Err() != nil {
+				return
+			}
+			if err != io.EOF && !isRetryable(err) {
+				c.logger.Warnf("Watch of file [%s] closed with error: %s", path, err)
+				return
+			}
+
+			attempt++
+			wait := reconnect.getBackoff(attempt)
+			c.logger.Debugf("Watch of file [%s] closed, open again in %s: %s", path, wait, err)
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(wait):
+			}
+
+			if next, err := c.openWatchFile(ctx, req); err == nil {
+				stream = next
+			} else if ctx.Err() == nil && !isRetryable(err) {
+				c.logger.Warnf("Failed to open watch of file [%s] again: %s", path, err)
+				return
+			}
+		}
+	}()
+	return events, nil
+}
+
+func (c *Client) openWatchFile(ctx context.Context, req *containers.WatchFileRequest) (containers.Containers_WatchFileClient, error) {
+	conn, err := c.getConnection()
+	if err != nil {
+		return nil, err
+	}
+
+	stream, err := containers.NewContainersClient(conn).WatchFile(ctx, req)
+	if err != nil {
+		return nil, translateError(err)
+	}
+	return stream, nil
+}
+
+func mapFileEvent(event *containers.FileEvent) FileEvent {
+	return FileEvent{
+		Op:   FileOp(event.Op),
+		Path: event.Path,
+		Time: time.Unix(0, event.Time),
+	}
+}
@@ -0,0 +1,42 @@
+package api
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/net/context"
+
+	containers "github.com/ernoaapa/eliot/pkg/api/services/containers/v1"
+)
+
+func TestWatchFile(t *testing.T) {
+	requests := make(chan *containers.WatchFileRequest, 1)
+	client, stop := startFakeServer(t, nil, &fakeContainersServer{watch: func(req *containers.WatchFileRequest, server containers.Containers_WatchFileServer) error {
+		requests <- req
+		server.Send(&containers.FileEvent{Op: "create", Path: req.Path, Time: 1})
+		server.Send(&containers.FileEvent{Op: "write", Path: req.Path, Time: 2})
+		<-server.Context().Done()
+		return nil
+	}})
+	defer stop()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	events, err := client.WatchFile(ctx, "foo", "/etc/app/config.yml")
+	assert.NoError(t, err)
+
+	req := <-requests
+	assert.Equal(t, "eliot", req.Namespace)
+	assert.Equal(t, "foo", req.ContainerID)
+
+	assert.Equal(t, FileEvent{Op: FileCreated, Path: "/etc/app/config.yml", Time: time.Unix(0, 1)}, <-events)
+	assert.Equal(t, FileEvent{Op: FileWritten, Path: "/etc/app/config.yml", Time: time.Unix(0, 2)}, <-events)
+
+	cancel()
+	select {
+	case _, ok := <-events:
+		assert.False(t, ok, "should close the channel when context is cancelled")
+	case <-time.After(time.Second):
+		t.Fatal("WatchFile didn't close the channel when context was cancelled")
+	}
+}
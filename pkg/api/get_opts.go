@@ -0,0 +1,47 @@
+package api
+
+import (
+	containers "github.com/ernoaapa/eliot/pkg/api/services/containers/v1"
+	pods "github.com/ernoaapa/eliot/pkg/api/services/pods/v1"
+	"golang.org/x/net/context"
+)
+
+type getOptions struct {
+	containerStatus bool
+}
+
+// WithContainerStatus makes GetPod fetch the live status of each container, init containers included,
+// from the container runtime, instead of the status what the pod had when it was listed.
+// It takes one extra call per container. If fetching some status fails, the container keeps
+// the listed status and the ContainerStatus StatusError tells why, the other containers are not affected.
+func WithContainerStatus() GetOpts {
+	return func(options *getOptions) {
+		options.containerStatus = true
+	}
+}
+
+// fetchContainerStatuses replaces the pod container statuses with the live statuses
+func (c *Client) fetchContainerStatuses(ctx context.Context, pod *pods.Pod) {
+	if pod.Status == nil {
+		return
+	}
+	pod.Status.InitContainerStatuses = c.fetchStatuses(ctx, pod.Status.InitContainerStatuses)
+	pod.Status.ContainerStatuses = c.fetchStatuses(ctx, pod.Status.ContainerStatuses)
+}
+
+func (c *Client) fetchStatuses(ctx context.Context, statuses []*containers.ContainerStatus) []*containers.ContainerStatus {
+	result := make([]*containers.ContainerStatus, 0, len(statuses))
+	for _, status := range statuses {
+		live, err := c.GetContainerStatus(ctx, status.GetContainerID())
+		if err != nil {
+			status.StatusError = err.Error()
+			result = append(result, status)
+			continue
+		}
+		if live.Name == "" {
+			live.Name = status.GetName()
+		}
+		result = append(result, live)
+	}
+	return result
+}
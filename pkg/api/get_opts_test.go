@@ -0,0 +1,59 @@
+package api
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	containers "github.com/ernoaapa/eliot/pkg/api/services/containers/v1"
+	pods "github.com/ernoaapa/eliot/pkg/api/services/pods/v1"
+)
+
+func startFakeStatusServer(t *testing.T, pod *pods.Pod, statusFunc func(req *containers.StatusRequest) (*containers.StatusResponse, error)) (*Client, func()) {
+	return startFakeServer(t, &fakePodsServer{list: func(req *pods.ListPodsRequest) (*pods.ListPodsResponse, error) {
+		return &pods.ListPodsResponse{Pods: []*pods.Pod{pod}}, nil
+	}}, &fakeContainersServer{status: statusFunc})
+}
+
+func TestGetPodWithContainerStatus(t *testing.T) {
+	pod := newLogsTestPod(
+		&containers.ContainerStatus{ContainerID: "1", Name: "app", State: "running"},
+		&containers.ContainerStatus{ContainerID: "2", Name: "sidecar", State: "running"},
+	)
+	pod.Status.InitContainerStatuses = []*containers.ContainerStatus{{ContainerID: "0", Name: "init", State: "running"}}
+
+	calls := 0
+	client, stop := startFakeStatusServer(t, pod, func(req *containers.StatusRequest) (*containers.StatusResponse, error) {
+		calls++
+		if req.ContainerID == "2" {
+			return nil, status.Errorf(codes.NotFound, "Container [%s] not found", req.ContainerID)
+		}
+		return &containers.StatusResponse{Status: &containers.ContainerStatus{ContainerID: req.ContainerID, State: "stopped", ExitCode: 1}}, nil
+	})
+	defer stop()
+
+	result, err := client.GetPod(context.Background(), "foo")
+	assert.NoError(t, err)
+	assert.Equal(t, "running", result.Status.ContainerStatuses[0].State)
+	assert.Equal(t, 0, calls, "should not fetch the statuses without the option")
+
+	result, err = client.GetPod(context.Background(), "foo", WithContainerStatus())
+	assert.NoError(t, err, "should not fail if some status cannot be fetched")
+	assert.Equal(t, 3, calls)
+
+	assert.Equal(t, "stopped", result.Status.InitContainerStatuses[0].State)
+	assert.Equal(t, "init", result.Status.InitContainerStatuses[0].Name, "should keep the container name")
+
+	app := result.Status.ContainerStatuses[0]
+	assert.Equal(t, "app", app.Name)
+	assert.Equal(t, "stopped", app.State)
+	assert.Equal(t, int32(1), app.ExitCode)
+	assert.Empty(t, app.StatusError)
+
+	sidecar := result.Status.ContainerStatuses[1]
+	assert.Equal(t, "running", sidecar.State, "should keep the listed status")
+	assert.Contains(t, sidecar.StatusError, "Container [2] not found")
+}
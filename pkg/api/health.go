@@ -0,0 +1,34 @@
+package api
+
+import (
+	"golang.org/x/net/context"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+)
+
+// servingServices are the service names what the health check recognises, empty means the whole server
+var servingServices = map[string]bool{
+	"":                           true,
+	"cand.services.pods.v1.Pods": true,
+	"eliot.services.containers.v1.Containers": true,
+	"eliot.services.containers.v1.Node":       true,
+}
+
+// Check is the standard gRPC health checking protocol implementation
+// Server is serving only if the container runtime is reachable
+func (s *Server) Check(ctx context.Context, req *grpc_health_v1.HealthCheckRequest) (*grpc_health_v1.HealthCheckResponse, error) {
+	if !servingServices[req.Service] {
+		return nil, status.Errorf(codes.NotFound, "Unknown service [%s]", req.Service)
+	}
+
+	if _, err := s.client.GetNamespaces(); err != nil {
+		return &grpc_health_v1.HealthCheckResponse{
+			Status: grpc_health_v1.HealthCheckResponse_NOT_SERVING,
+		}, nil
+	}
+
+	return &grpc_health_v1.HealthCheckResponse{
+		Status: grpc_health_v1.HealthCheckResponse_SERVING,
+	}, nil
+}
@@ -0,0 +1,86 @@
+package api
+
+import (
+	"sync"
+	"time"
+
+	pods "github.com/ernoaapa/eliot/pkg/api/services/pods/v1"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc/metadata"
+)
+
+// idempotencyKeyMetadata is the metadata key of the CreatePod idempotency key
+const idempotencyKeyMetadata = "idempotency-key"
+
+// idempotencyKeyTTL is how long the server remembers the successful creates
+const idempotencyKeyTTL = 10 * time.Minute
+
+// createRequests tracks the pod creates by the idempotency key, so that retried create
+// waits the original one and returns the original result instead of creating the pod again
+type createRequests struct {
+	mu      sync.Mutex
+	entries map[string]*createRequest
+}
+
+type createRequest struct {
+	done     chan struct{}
+	images   []*pods.ImageFetch
+	err      error
+	finished time.Time
+}
+
+// begin return the create with the key and true if it's new and the caller must do the create
+func (r *createRequests) begin(key string) (*createRequest, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.entries == nil {
+		r.entries = map[string]*createRequest{}
+	}
+	for k, entry := range r.entries {
+		if !entry.finished.IsZero() && time.Since(entry.finished) > idempotencyKeyTTL {
+			delete(r.entries, k)
+		}
+	}
+
+	if entry, ok := r.entries[key]; ok {
+		return entry, false
+	}
+	entry := &createRequest{done: make(chan struct{})}
+	r.entries[key] = entry
+	return entry, true
+}
+
+// finish stores the create result and releases the waiting retries.
+// Failed create is forgotten so that the retry can try again.
+func (r *createRequests) finish(key string, entry *createRequest, images []*pods.ImageFetch, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	entry.images = images
+	entry.err = err
+	entry.finished = time.Now()
+	if err != nil && r.entries[key] == entry {
+		delete(r.entries, key)
+	}
+	close(entry.done)
+}
+
+// forget removes the create, e.g. when the pod has been deleted after it was created
+func (r *createRequests) forget(key string, entry *createRequest) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.entries[key] == entry {
+		delete(r.entries, key)
+	}
+}
+
+// getIdempotencyKey return the idempotency key from the incoming metadata, empty if not given
+func getIdempotencyKey(ctx context.Context) string {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return ""
+	}
+	return getMetadataValue(md, idempotencyKeyMetadata)
+}
@@ -0,0 +1,101 @@
+package api
+
+import (
+	"errors"
+	"net"
+	"sync"
+	"testing"
+
+	"github.com/ernoaapa/eliot/pkg/api/core"
+	containers "github.com/ernoaapa/eliot/pkg/api/services/containers/v1"
+	pods "github.com/ernoaapa/eliot/pkg/api/services/pods/v1"
+	"github.com/ernoaapa/eliot/pkg/config"
+	"github.com/ernoaapa/eliot/pkg/model"
+	"github.com/ernoaapa/eliot/pkg/progress"
+	"github.com/ernoaapa/eliot/pkg/runtime"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/net/context"
+)
+
+func TestCreateRequestsForgetFailed(t *testing.T) {
+	requests := &createRequests{}
+
+	entry, isNew := requests.begin("foo")
+	assert.True(t, isNew)
+	same, isNew := requests.begin("foo")
+	assert.False(t, isNew, "should wait the create in progress")
+	assert.Equal(t, entry, same)
+
+	requests.finish("foo", entry, nil, errors.New("failed"))
+	<-same.done
+	assert.Error(t, same.err)
+
+	_, isNew = requests.begin("foo")
+	assert.True(t, isNew, "should try again after failed create")
+}
+
+// fakeRuntime is runtime which keeps the created containers in memory
+type fakeRuntime struct {
+	runtime.Client
+	mu      sync.Mutex
+	created map[string]bool
+	pulls   int
+}
+
+func (r *fakeRuntime) GetPod(namespace, name string) (model.Pod, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if !r.created[name] {
+		return model.Pod{}, runtime.ErrWithMessagef(runtime.ErrNotFound, "Pod [%s] not found", name)
+	}
+	return model.Pod{Metadata: model.Metadata{Name: name, Namespace: namespace}}, nil
+}
+
+func (r *fakeRuntime) PullImage(namespace, ref string, status *progress.ImageFetch, cancel <-chan struct{}) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.pulls++
+	return nil
+}
+
+func (r *fakeRuntime) CreateContainer(pod model.Pod, container model.Container) (model.ContainerStatus, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.created[pod.Metadata.Name] = true
+	return model.ContainerStatus{}, nil
+}
+
+func TestCreatePodWithIdempotencyKey(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+
+	fake := &fakeRuntime{created: map[string]bool{}}
+	server := NewServer(listener.Addr().String(), fake, nil)
+	go server.grpc.Serve(listener)
+	defer server.grpc.Stop()
+
+	newPod := func() *pods.Pod {
+		return &pods.Pod{
+			Metadata: &core.ResourceMetadata{Name: "foo", Namespace: "eliot"},
+			Spec:     &pods.PodSpec{Containers: []*containers.Container{{Name: "foo", Image: "docker.io/library/alpine:latest"}}},
+		}
+	}
+
+	client, err := NewClient("eliot", config.Endpoint{URL: listener.Addr().String()}, WithInsecure(), WithIdempotencyKey("deploy-1"))
+	assert.NoError(t, err)
+	defer client.Close()
+
+	assert.NoError(t, client.CreatePod(context.Background(), nil, newPod()))
+	assert.NoError(t, client.CreatePod(context.Background(), nil, newPod()), "retry with same key should return the original result")
+	assert.Equal(t, 1, fake.pulls, "should not create the pod twice")
+
+	other, err := NewClient("eliot", config.Endpoint{URL: listener.Addr().String()}, WithInsecure())
+	assert.NoError(t, err)
+	defer other.Close()
+
+	err = other.CreatePod(context.Background(), nil, newPod())
+	assert.True(t, errors.Is(err, ErrAlreadyExists), "create with another key should fail, but got %v", err)
+
+	_, err = NewClient("eliot", config.Endpoint{URL: listener.Addr().String()}, WithIdempotencyKey(" "))
+	assert.Error(t, err)
+}
@@ -0,0 +1,91 @@
+package api
+
+import (
+	"io"
+	"sync/atomic"
+	"time"
+)
+
+// idleWatcher tracks the last time data was sent or received through the stream
+type idleWatcher struct {
+	timeout time.Duration
+	last    int64 // unix nanoseconds, accessed atomically
+}
+
+func newIdleWatcher(timeout time.Duration) *idleWatcher {
+	return &idleWatcher{
+		timeout: timeout,
+		last:    time.Now().UnixNano(),
+	}
+}
+
+func (w *idleWatcher) touch() {
+	atomic.StoreInt64(&w.last, time.Now().UnixNano())
+}
+
+// Watch return channel which get closed when there's been no activity for the timeout.
+// Returns nil channel, which never fires, if the timeout is zero.
+func (w *idleWatcher) Watch(done <-chan struct{}) <-chan struct{} {
+	if w.timeout <= 0 {
+		return nil
+	}
+
+	idle := make(chan struct{})
+	go func() {
+		timer := time.NewTimer(w.timeout)
+		defer timer.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case <-timer.C:
+				elapsed := time.Since(time.Unix(0, atomic.LoadInt64(&w.last)))
+				if elapsed >= w.timeout {
+					close(idle)
+					return
+				}
+				timer.Reset(w.timeout - elapsed)
+			}
+		}
+	}()
+	return idle
+}
+
+// Reader wraps the reader so that each read marks activity, return nil for nil reader
+func (w *idleWatcher) Reader(r io.Reader) io.Reader {
+	if r == nil {
+		return nil
+	}
+	return &idleReader{r, w}
+}
+
+// Writer wraps the writer so that each write marks activity, return nil for nil writer
+func (w *idleWatcher) Writer(wr io.Writer) io.Writer {
+	if wr == nil {
+		return nil
+	}
+	return &idleWriter{wr, w}
+}
+
+type idleReader struct {
+	io.Reader
+	watcher *idleWatcher
+}
+
+func (r *idleReader) Read(p []byte) (int, error) {
+	n, err := r.Reader.Read(p)
+	if n > 0 {
+		r.watcher.touch()
+	}
+	return n, err
+}
+
+type idleWriter struct {
+	io.Writer
+	watcher *idleWatcher
+}
+
+func (w *idleWriter) Write(p []byte) (int, error) {
+	w.watcher.touch()
+	return w.Writer.Write(p)
+}
@@ -0,0 +1,64 @@
+package api
+
+import (
+	node "github.com/ernoaapa/eliot/pkg/api/services/node/v1"
+	pods "github.com/ernoaapa/eliot/pkg/api/services/pods/v1"
+	"github.com/ernoaapa/eliot/pkg/image"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc/codes"
+)
+
+// PinDigest resolves the image tag to the immutable digest through the node registry client,
+// e.g. nginx:latest -> docker.io/library/nginx:latest@sha256:...
+// so that the image doesn't change between the pulls. Already pinned reference is returned in fully qualified form.
+// Returns ErrInvalidArgument if the reference is malformed.
+func (c *Client) PinDigest(ctx context.Context, ref string) (string, error) {
+	ref = expandImage(ref)
+	parsed, err := image.ParseRef(ref)
+	if err != nil {
+		return "", &Error{Code: codes.InvalidArgument, Message: err.Error()}
+	}
+	if parsed.IsPinned() {
+		return ref, nil
+	}
+
+	conn, err := c.getConnection()
+	if err != nil {
+		return "", err
+	}
+
+	client := node.NewNodeClient(conn)
+	resp, err := client.ResolveImage(ctx, &node.ResolveImageRequest{
+		Image: ref,
+	})
+	if err != nil {
+		return "", translateError(err)
+	}
+	return resp.GetImage(), nil
+}
+
+// WithPinnedImages rewrites every container image to the digest form with PinDigest when the pod gets created.
+// Use it to make sure that all devices run exactly the same images even if the tags get updated in the registry.
+func (c *Client) WithPinnedImages(ctx context.Context) PodOpts {
+	return func(pod *pods.Pod) error {
+		for _, container := range pod.GetSpec().GetContainers() {
+			pinned, err := c.PinDigest(ctx, container.Image)
+			if err != nil {
+				return err
+			}
+			if pinned != container.Image {
+				c.logger.Debugf("Pinned container [%s] image [%s] to [%s]", container.Name, container.Image, pinned)
+			}
+			container.Image = pinned
+		}
+		return nil
+	}
+}
+
+// resolvableImage return the image reference which the registry can resolve, the tag defaults to latest
+func resolvableImage(ref image.Ref) string {
+	if ref.Tag == "" {
+		ref.Tag = image.DefaultTag
+	}
+	return ref.String()
+}
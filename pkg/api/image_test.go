@@ -0,0 +1,87 @@
+package api
+
+import (
+	"errors"
+	"net"
+	"testing"
+
+	"github.com/ernoaapa/eliot/pkg/api/core"
+	containers "github.com/ernoaapa/eliot/pkg/api/services/containers/v1"
+	pods "github.com/ernoaapa/eliot/pkg/api/services/pods/v1"
+	"github.com/ernoaapa/eliot/pkg/config"
+	"github.com/ernoaapa/eliot/pkg/runtime"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/net/context"
+)
+
+const testImageDigest = "sha256:7d6a3c8f70470b6ba1d3d8b6b3c9c7cb3c0d4b3f7d8f4f0b1be0b5cbe0c5a1a2"
+
+// registryRuntime is runtime which resolves every image to the same digest
+type registryRuntime struct {
+	runtime.Client
+	resolved []string
+}
+
+func (r *registryRuntime) ResolveImage(ref string) (string, error) {
+	r.resolved = append(r.resolved, ref)
+	return testImageDigest, nil
+}
+
+func TestWithPinnedImages(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+
+	fake := &registryRuntime{}
+	server := NewServer(listener.Addr().String(), fake, nil)
+	go server.grpc.Serve(listener)
+	defer server.grpc.Stop()
+
+	client, err := NewClient("eliot", config.Endpoint{URL: listener.Addr().String()}, WithInsecure())
+	assert.NoError(t, err)
+	defer client.Close()
+
+	pod := &pods.Pod{
+		Metadata: &core.ResourceMetadata{Name: "foo", Namespace: "eliot"},
+		Spec: &pods.PodSpec{Containers: []*containers.Container{
+			{Name: "web", Image: "docker.io/library/nginx"},
+			{Name: "pinned", Image: "docker.io/library/alpine:3.7@" + testImageDigest},
+		}},
+	}
+	assert.NoError(t, client.WithPinnedImages(context.Background())(pod))
+	assert.Equal(t, "docker.io/library/nginx:latest@"+testImageDigest, pod.Spec.Containers[0].Image)
+	assert.Equal(t, "docker.io/library/alpine:3.7@"+testImageDigest, pod.Spec.Containers[1].Image)
+	assert.Equal(t, []string{"docker.io/library/nginx:latest"}, fake.resolved, "should resolve only unpinned images, with default tag")
+
+	_, err = client.PinDigest(context.Background(), "docker.io/library/nginx:-latest")
+	assert.True(t, errors.Is(err, ErrInvalidArgument), "expected invalid argument, but got %v", err)
+}
+
+func TestPinDigestExpandsShortName(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+
+	fake := &registryRuntime{}
+	server := NewServer(listener.Addr().String(), fake, nil)
+	go server.grpc.Serve(listener)
+	defer server.grpc.Stop()
+
+	client, err := NewClient("eliot", config.Endpoint{URL: listener.Addr().String()}, WithInsecure())
+	assert.NoError(t, err)
+	defer client.Close()
+
+	pinned, err := client.PinDigest(context.Background(), "nginx:latest")
+	assert.NoError(t, err)
+	assert.Equal(t, "docker.io/library/nginx:latest@"+testImageDigest, pinned)
+
+	pinned, err = client.PinDigest(context.Background(), "eaapa/hello-world@"+testImageDigest)
+	assert.NoError(t, err)
+	assert.Equal(t, "docker.io/eaapa/hello-world:latest@"+testImageDigest, pinned, "should not resolve already pinned image")
+	assert.Equal(t, []string{"docker.io/library/nginx:latest"}, fake.resolved)
+
+	pod := &pods.Pod{
+		Metadata: &core.ResourceMetadata{Name: "foo", Namespace: "eliot"},
+		Spec:     &pods.PodSpec{Containers: []*containers.Container{{Name: "web", Image: "nginx"}}},
+	}
+	assert.NoError(t, client.WithPinnedImages(context.Background())(pod))
+	assert.Equal(t, "docker.io/library/nginx:latest@"+testImageDigest, pod.Spec.Containers[0].Image)
+}
@@ -0,0 +1,169 @@
+package api
+
+import (
+	"encoding/json"
+	"time"
+
+	containers "github.com/ernoaapa/eliot/pkg/api/services/containers/v1"
+	"github.com/ernoaapa/eliot/pkg/runtime"
+	"github.com/pkg/errors"
+	"golang.org/x/net/context"
+)
+
+// ContainerInspect is everything the node runtime knows about the container, for troubleshooting.
+// It's JSON serializable, e.g. to print it with json.MarshalIndent.
+type ContainerInspect struct {
+	ContainerID string `json:"containerID"`
+	Image       string `json:"image"`
+	// ImageDigest is the digest of the image manifest, empty if the image has been deleted from the node
+	ImageDigest string `json:"imageDigest,omitempty"`
+	// Args is the command and arguments what the container process runs
+	Args        []string           `json:"args"`
+	Env         []string           `json:"env,omitempty"`
+	WorkingDir  string             `json:"workingDir,omitempty"`
+	Hostname    string             `json:"hostname,omitempty"`
+	Mounts      []InspectMount     `json:"mounts,omitempty"`
+	Namespaces  []InspectNamespace `json:"namespaces,omitempty"`
+	CgroupsPath string             `json:"cgroupsPath,omitempty"`
+	Labels      map[string]string  `json:"labels,omitempty"`
+	Annotations map[string]string  `json:"annotations,omitempty"`
+	Runtime     string             `json:"runtime"`
+	Snapshotter string             `json:"snapshotter"`
+	SnapshotKey string             `json:"snapshotKey"`
+	// Status is the task status, e.g. running or stopped, empty if the container has no task
+	Status string `json:"status,omitempty"`
+	// Pid is the container main process id in the node, zero if the container has no task
+	Pid       uint32    `json:"pid,omitempty"`
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
+	// Spec is the complete OCI runtime spec what the container runs with
+	Spec json.RawMessage `json:"spec,omitempty"`
+}
+
+// InspectMount is mount in the container
+type InspectMount struct {
+	Type        string   `json:"type"`
+	Source      string   `json:"source"`
+	Destination string   `json:"destination"`
+	Options     []string `json:"options,omitempty"`
+}
+
+// InspectNamespace is Linux namespace of the container, empty path means the container has own namespace
+type InspectNamespace struct {
+	Type string `json:"type"`
+	Path string `json:"path,omitempty"`
+}
+
+// Inspect return the container details from the node runtime: the command, mounts, namespaces,
+// cgroups path, annotations, resolved image digest and the OCI spec.
+// It's more detailed than GetContainerStatus, but also heavier, so don't use it in polling loops.
+// Returns ErrContainerNotFound if the container doesn't exist.
+func (c *Client) Inspect(ctx context.Context, containerID string) (*ContainerInspect, error) {
+	conn, err := c.getConnection()
+	if err != nil {
+		return nil, err
+	}
+
+	client := containers.NewContainersClient(conn)
+	resp, err := client.Inspect(ctx, &containers.InspectRequest{
+		Namespace:   c.Namespace,
+		ContainerID: containerID,
+	})
+	if err != nil {
+		return nil, translateContainerError(err)
+	}
+	return mapInspect(resp.GetInspect()), nil
+}
+
+func mapInspect(inspect *containers.ContainerInspect) *ContainerInspect {
+	result := &ContainerInspect{
+		ContainerID: inspect.GetContainerID(),
+		Image:       inspect.GetImage(),
+		ImageDigest: inspect.GetImageDigest(),
+		Args:        inspect.GetArgs(),
+		Env:         inspect.GetEnv(),
+		WorkingDir:  inspect.GetWorkingDir(),
+		Hostname:    inspect.GetHostname(),
+		CgroupsPath: inspect.GetCgroupsPath(),
+		Labels:      inspect.GetLabels(),
+		Annotations: inspect.GetAnnotations(),
+		Runtime:     inspect.GetRuntime(),
+		Snapshotter: inspect.GetSnapshotter(),
+		SnapshotKey: inspect.GetSnapshotKey(),
+		Status:      inspect.GetStatus(),
+		Pid:         inspect.GetPid(),
+		CreatedAt:   mapUnixNano(inspect.GetCreatedAt()),
+		UpdatedAt:   mapUnixNano(inspect.GetUpdatedAt()),
+	}
+	if len(inspect.GetSpec()) > 0 {
+		result.Spec = json.RawMessage(inspect.GetSpec())
+	}
+	for _, mount := range inspect.GetMounts() {
+		result.Mounts = append(result.Mounts, InspectMount{
+			Type:        mount.GetType(),
+			Source:      mount.GetSource(),
+			Destination: mount.GetDestination(),
+			Options:     mount.GetOptions(),
+		})
+	}
+	for _, namespace := range inspect.GetNamespaces() {
+		result.Namespaces = append(result.Namespaces, InspectNamespace{
+			Type: namespace.GetType(),
+			Path: namespace.GetPath(),
+		})
+	}
+	return result
+}
+
+// mapInspectToAPIModel maps the runtime inspect to API model, the details are picked from the OCI spec
+func mapInspectToAPIModel(inspect runtime.ContainerInspect) (*containers.ContainerInspect, error) {
+	result := &containers.ContainerInspect{
+		ContainerID: inspect.ID,
+		Image:       inspect.Image,
+		ImageDigest: inspect.ImageDigest,
+		Labels:      inspect.Labels,
+		Runtime:     inspect.Runtime,
+		Snapshotter: inspect.Snapshotter,
+		SnapshotKey: inspect.SnapshotKey,
+		Status:      inspect.Status,
+		Pid:         inspect.Pid,
+		CreatedAt:   mapTimeToUnixNano(inspect.CreatedAt),
+		UpdatedAt:   mapTimeToUnixNano(inspect.UpdatedAt),
+	}
+
+	spec := inspect.Spec
+	if spec == nil {
+		return result, nil
+	}
+
+	data, err := json.Marshal(spec)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Failed to serialize container [%s] spec", inspect.ID)
+	}
+	result.Spec = data
+	result.Hostname = spec.Hostname
+	result.Annotations = spec.Annotations
+	if spec.Process != nil {
+		result.Args = spec.Process.Args
+		result.Env = spec.Process.Env
+		result.WorkingDir = spec.Process.Cwd
+	}
+	for _, mount := range spec.Mounts {
+		result.Mounts = append(result.Mounts, &containers.Mount{
+			Type:        mount.Type,
+			Source:      mount.Source,
+			Destination: mount.Destination,
+			Options:     mount.Options,
+		})
+	}
+	if spec.Linux != nil {
+		result.CgroupsPath = spec.Linux.CgroupsPath
+		for _, namespace := range spec.Linux.Namespaces {
+			result.Namespaces = append(result.Namespaces, &containers.LinuxNamespace{
+				Type: string(namespace.Type),
+				Path: namespace.Path,
+			})
+		}
+	}
+	return result, nil
+}
@@ -0,0 +1,66 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	specs "github.com/opencontainers/runtime-spec/specs-go"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc/codes"
+	grpcstatus "google.golang.org/grpc/status"
+
+	containers "github.com/ernoaapa/eliot/pkg/api/services/containers/v1"
+	"github.com/ernoaapa/eliot/pkg/runtime"
+)
+
+func TestInspectMapsRuntimeSpec(t *testing.T) {
+	created := time.Unix(1523384743, 0)
+	inspect, err := mapInspectToAPIModel(runtime.ContainerInspect{
+		ID:          "foo",
+		Image:       "docker.io/library/alpine:latest",
+		ImageDigest: "sha256:abc",
+		CreatedAt:   created,
+		Pid:         123,
+		Spec: &specs.Spec{
+			Hostname:    "my-pod",
+			Annotations: map[string]string{"foo": "bar"},
+			Process:     &specs.Process{Args: []string{"/bin/sh", "-c", "sleep 10"}, Cwd: "/"},
+			Mounts:      []specs.Mount{{Type: "bind", Source: "/data", Destination: "/data", Options: []string{"rbind"}}},
+			Linux: &specs.Linux{
+				CgroupsPath: "/eliot/foo",
+				Namespaces:  []specs.LinuxNamespace{{Type: specs.NetworkNamespace, Path: "/proc/1/ns/net"}},
+			},
+		},
+	})
+	assert.NoError(t, err)
+
+	result := mapInspect(inspect)
+	assert.Equal(t, "sha256:abc", result.ImageDigest)
+	assert.Equal(t, []string{"/bin/sh", "-c", "sleep 10"}, result.Args)
+	assert.Equal(t, []InspectMount{{Type: "bind", Source: "/data", Destination: "/data", Options: []string{"rbind"}}}, result.Mounts)
+	assert.Equal(t, []InspectNamespace{{Type: "network", Path: "/proc/1/ns/net"}}, result.Namespaces)
+	assert.Equal(t, "/eliot/foo", result.CgroupsPath)
+	assert.Equal(t, map[string]string{"foo": "bar"}, result.Annotations)
+	assert.Equal(t, uint32(123), result.Pid)
+	assert.True(t, created.Equal(result.CreatedAt))
+	assert.True(t, result.UpdatedAt.IsZero())
+
+	data, err := json.Marshal(result)
+	assert.NoError(t, err)
+	decoded := map[string]interface{}{}
+	assert.NoError(t, json.Unmarshal(data, &decoded))
+	assert.Equal(t, "my-pod", decoded["spec"].(map[string]interface{})["hostname"], "should embed the spec as JSON object")
+}
+
+func TestInspectContainerNotFound(t *testing.T) {
+	client, stop := startFakeServer(t, nil, &fakeContainersServer{inspect: func(req *containers.InspectRequest) (*containers.InspectResponse, error) {
+		return nil, grpcstatus.Errorf(codes.NotFound, "Container [%s] not found", req.ContainerID)
+	}})
+	defer stop()
+
+	_, err := client.Inspect(context.Background(), "foo")
+	assert.True(t, errors.Is(err, ErrContainerNotFound))
+}
@@ -0,0 +1,30 @@
+package api
+
+import (
+	"io"
+	"os"
+
+	"golang.org/x/net/context"
+
+	"github.com/ernoaapa/eliot/pkg/term"
+)
+
+// AttachInteractive attaches the process stdin, stdout and stderr to the container main process, e.g. to an interactive shell.
+// If stdin is a terminal, it's set to raw mode for the time of the attach, so that keystrokes are sent right away and
+// e.g. Ctrl-C goes to the container, and the terminal size is sent to the container on every resize (SIGWINCH).
+// The terminal state gets restored when the attach returns, panics or the process gets terminated.
+// Returns ErrDetached when the default detach keys Ctrl-P Ctrl-Q are typed, the container keeps running.
+// For other attach options, use Attach with term.TTY, see AttachIO.
+func (c *Client) AttachInteractive(ctx context.Context, containerID string, hooks ...AttachHooks) error {
+	return c.attachInteractive(ctx, containerID, term.TTY{In: os.Stdin, Out: os.Stdout, Raw: true}, os.Stderr, hooks...)
+}
+
+func (c *Client) attachInteractive(ctx context.Context, containerID string, tty term.TTY, stderr io.Writer, hooks ...AttachHooks) error {
+	attachIO := NewAttachIO(tty.In, tty.Out, stderr)
+	if tty.IsTerminalIn() {
+		attachIO.Resize = tty.MonitorSize(tty.GetSize())
+	}
+	return tty.Safe(func() error {
+		return c.Attach(ctx, containerID, attachIO, hooks...)
+	})
+}
@@ -0,0 +1,29 @@
+package api
+
+import (
+	"bytes"
+	"errors"
+	"io/ioutil"
+	"testing"
+
+	containers "github.com/ernoaapa/eliot/pkg/api/services/containers/v1"
+	"github.com/ernoaapa/eliot/pkg/api/stream"
+	"github.com/ernoaapa/eliot/pkg/term"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/net/context"
+)
+
+func TestAttachInteractiveWithoutTerminal(t *testing.T) {
+	received := make(chan []byte, 1)
+	client, stop := startFakeContainersServer(t, func(server containers.Containers_AttachServer) error {
+		input, _ := ioutil.ReadAll(stream.NewReader(server))
+		received <- input
+		return nil
+	})
+	defer stop()
+
+	tty := term.TTY{In: bytes.NewReader([]byte("ls\n\x10\x11")), Out: ioutil.Discard, Raw: true}
+	err := client.attachInteractive(context.Background(), "foo", tty, ioutil.Discard)
+	assert.True(t, errors.Is(err, ErrDetached), "should detach with the default keys, got: %v", err)
+	assert.Equal(t, "ls\n", string(<-received))
+}
@@ -0,0 +1,50 @@
+package api
+
+import (
+	"golang.org/x/net/context"
+	"google.golang.org/grpc"
+)
+
+// WithUnaryInterceptors adds interceptors to every unary call, e.g. for tracing, auth token refresh or request logging.
+// The interceptors run in the given order, the first one outermost, and the ones from later WithUnaryInterceptors
+// options after the earlier ones. They run inside the client own handling: after the rate limit, with the
+// WithMetadata metadata and WithCallOptions options already added, so they see the call as it gets sent.
+// The cc argument is the connection the call is sent through. With WithServers the interceptors get called again
+// for each server the call is tried on, and with retries for each attempt.
+func WithUnaryInterceptors(interceptors ...grpc.UnaryClientInterceptor) ClientOpts {
+	return func(client *Client) error {
+		client.unaryInterceptors = append(client.unaryInterceptors, interceptors...)
+		return nil
+	}
+}
+
+// WithStreamInterceptors adds interceptors to every stream, like WithUnaryInterceptors for the unary calls.
+// The interceptors get called when the stream opens, to follow the messages wrap the returned grpc.ClientStream.
+func WithStreamInterceptors(interceptors ...grpc.StreamClientInterceptor) ClientOpts {
+	return func(client *Client) error {
+		client.streamInterceptors = append(client.streamInterceptors, interceptors...)
+		return nil
+	}
+}
+
+// chainUnaryInterceptors return invoker what calls the interceptors in order and last the invoker
+func chainUnaryInterceptors(interceptors []grpc.UnaryClientInterceptor, invoker grpc.UnaryInvoker) grpc.UnaryInvoker {
+	for i := len(interceptors) - 1; i >= 0; i-- {
+		interceptor, next := interceptors[i], invoker
+		invoker = func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+			return interceptor(ctx, method, req, reply, cc, next, opts...)
+		}
+	}
+	return invoker
+}
+
+// chainStreamInterceptors return streamer what calls the interceptors in order and last the streamer
+func chainStreamInterceptors(interceptors []grpc.StreamClientInterceptor, streamer grpc.Streamer) grpc.Streamer {
+	for i := len(interceptors) - 1; i >= 0; i-- {
+		interceptor, next := interceptors[i], streamer
+		streamer = func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+			return interceptor(ctx, desc, cc, method, next, opts...)
+		}
+	}
+	return streamer
+}
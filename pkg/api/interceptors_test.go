@@ -0,0 +1,78 @@
+package api
+
+import (
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+
+	containers "github.com/ernoaapa/eliot/pkg/api/services/containers/v1"
+	node "github.com/ernoaapa/eliot/pkg/api/services/node/v1"
+	"github.com/ernoaapa/eliot/pkg/config"
+)
+
+func TestWithUnaryInterceptors(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+	server := grpc.NewServer()
+	node.RegisterNodeServer(server, &fakeNodeServer{hostname: "test"})
+	go server.Serve(listener)
+	defer server.Stop()
+
+	calls := []string{}
+	record := func(name string) grpc.UnaryClientInterceptor {
+		return func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+			md, _ := metadata.FromOutgoingContext(ctx)
+			assert.Equal(t, []string{"foo"}, md["x-tenant-id"], "should see the client metadata")
+			assert.NotNil(t, cc, "should get the connection in use")
+			calls = append(calls, name+" "+method)
+			return invoker(ctx, method, req, reply, cc, opts...)
+		}
+	}
+
+	client, err := NewClient("eliot", config.Endpoint{URL: listener.Addr().String()},
+		WithInsecure(),
+		WithMetadata(metadata.Pairs("x-tenant-id", "foo")),
+		WithUnaryInterceptors(record("first"), record("second")),
+		WithUnaryInterceptors(record("third")),
+	)
+	assert.NoError(t, err)
+	defer client.Close()
+
+	_, err = client.GetInfo(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, []string{
+		"first /eliot.services.containers.v1.Node/Info",
+		"second /eliot.services.containers.v1.Node/Info",
+		"third /eliot.services.containers.v1.Node/Info",
+	}, calls)
+}
+
+func TestWithStreamInterceptors(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+	server := grpc.NewServer()
+	containers.RegisterContainersServer(server, &fakeContainersServer{logs: func(req *containers.LogsRequest, server containers.Containers_LogsServer) error {
+		return server.Send(&containers.LogsStreamResponse{Lines: []*containers.LogLine{{Line: []byte("hello\n")}}})
+	}})
+	go server.Serve(listener)
+	defer server.Stop()
+
+	opened := []string{}
+	client, err := NewClient("eliot", config.Endpoint{URL: listener.Addr().String()},
+		WithInsecure(),
+		WithStreamInterceptors(func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+			opened = append(opened, method)
+			return streamer(ctx, desc, cc, method, opts...)
+		}),
+	)
+	assert.NoError(t, err)
+	defer client.Close()
+
+	_, err = client.GetContainerLogs(context.Background(), "foo", LogFormatRaw)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"/eliot.services.containers.v1.Containers/Logs"}, opened)
+}
@@ -2,14 +2,76 @@ package api
 
 import (
 	"io"
+	"time"
 
+	containers "github.com/ernoaapa/eliot/pkg/api/services/containers/v1"
 	pods "github.com/ernoaapa/eliot/pkg/api/services/pods/v1"
 	"github.com/ernoaapa/eliot/pkg/config"
+	"github.com/ernoaapa/eliot/pkg/progress"
+	"github.com/ernoaapa/eliot/pkg/term"
 )
 
+// DefaultGracePeriod is how long containers have time to exit after SIGTERM before they get killed
+const DefaultGracePeriod = 10 * time.Second
+
+// ClientOpts configures the Client when it get created
+type ClientOpts func(client *Client) error
+
 // PodOpts adds more information to the Pod going to be created
 type PodOpts func(pod *pods.Pod) error
 
+// RunOpts changes the single container pod what RunContainer creates
+type RunOpts func(pod *pods.Pod, container *containers.Container) error
+
+// RunSpec is the one-shot container what RunAndCapture runs
+type RunSpec struct {
+	// Name is the pod and the container name
+	Name  string
+	Image string
+	// Command replaces the image default command, e.g. []string{"sh", "-c", "date"}
+	Command []string
+	Env     map[string]string
+	// Opts change the pod further, they get applied after the other fields
+	Opts []RunOpts
+}
+
+// ImageFetchProgress is the image pull progress of each pod container
+type ImageFetchProgress []*progress.ImageFetch
+
+// Layers return snapshot of the layer progress of all images, in the image order.
+// Progress handlers can use this instead of reading the ImageFetch values, which keep changing.
+func (p ImageFetchProgress) Layers() []progress.LayerProgress {
+	result := []progress.LayerProgress{}
+	for _, fetch := range p {
+		result = append(result, fetch.Snapshot()...)
+	}
+	return result
+}
+
+// PodImageFetch is the image pull progress of single container in CreatePods
+type PodImageFetch struct {
+	Pod string
+	*progress.ImageFetch
+}
+
+// PodsImageFetchProgress is the combined image pull progress of all pods in CreatePods
+type PodsImageFetchProgress []*PodImageFetch
+
+// DeleteOpts changes how the pod get deleted
+type DeleteOpts func(req *pods.DeletePodRequest) error
+
+// DiskUsageOpts changes how StreamDiskUsage samples the container disk usage
+type DiskUsageOpts func(req *containers.DiskUsageRequest) error
+
+// CheckpointOpts changes where Checkpoint stores the container checkpoint
+type CheckpointOpts func(req *containers.CheckpointRequest) error
+
+// RestoreOpts changes where Restore reads the container checkpoint from
+type RestoreOpts func(req *containers.RestoreRequest) error
+
+// GetOpts changes what GetPod fetches in addition to the pod
+type GetOpts func(options *getOptions)
+
 // AttachHooks is additional process what runs when is attached to container
 type AttachHooks func(endpoint config.Endpoint, done <-chan struct{})
 
@@ -18,9 +80,247 @@ type AttachIO struct {
 	Stdin  io.Reader
 	Stdout io.Writer
 	Stderr io.Writer
+	// Resize is optional queue of terminal size changes, sent to the container when stdin is terminal
+	Resize term.TerminalSizeQueue
+	// IdleTimeout closes the attach when no data is sent or received within the duration.
+	// Zero disables the timeout, so that e.g. shell waiting at prompt isn't closed.
+	IdleTimeout time.Duration
+	// LineBuffered writes the output to Stdout and Stderr only in complete lines, so that lines of the
+	// two streams don't get mixed when written to the same terminal. Output without newline, like
+	// shell prompt or binary data, is written when the stream ends, so use it only for line based output.
+	LineBuffered bool
+	// StdinBufferSize is the maximum amount of stdin sent in single message, zero means 32KiB.
+	// Each read from Stdin is sent right away, so it only limits how large pastes or piped input get split.
+	StdinBufferSize int
+	// Stats, if set, counts the bytes read from Stdin and received to Stdout and Stderr
+	Stats *AttachStats
+	// Streams selects which output streams the server sends, by default both stdout and stderr.
+	// The selection is independent of Stdin, nil Stdin only makes the attach read-only.
+	Streams AttachStreams
+	// DetachKeys is the Stdin key sequence which ends Attach with ErrDetached, leaving the container running
+	// with its stdin open. The sequence is not sent to the container. Nil means DefaultDetachKeys,
+	// empty slice disables the detaching, e.g. for binary input. See ParseDetachKeys.
+	DetachKeys []byte
 }
 
+// AttachStreams selects the container output streams to receive in Attach
+type AttachStreams string
+
+const (
+	// AttachAllStreams receives both stdout and stderr
+	AttachAllStreams AttachStreams = ""
+	// AttachStdoutOnly receives only stdout, the server doesn't send stderr at all
+	AttachStdoutOnly AttachStreams = "stdout"
+	// AttachStderrOnly receives only stderr, the server doesn't send stdout at all
+	AttachStderrOnly AttachStreams = "stderr"
+)
+
 // NewAttachIO is wrapper for stdin, stdout and stderr
 func NewAttachIO(stdin io.Reader, stdout, stderr io.Writer) AttachIO {
-	return AttachIO{stdin, stdout, stderr}
+	return AttachIO{
+		Stdin:  stdin,
+		Stdout: stdout,
+		Stderr: stderr,
+	}
+}
+
+// Stats is container resource usage at the moment
+type Stats struct {
+	Time time.Time
+	// CPUNanoseconds is total CPU time consumed by the container
+	CPUNanoseconds uint64
+	// MemoryUsageBytes is current memory usage, including page cache
+	MemoryUsageBytes uint64
+	// MemoryLimitBytes is the container memory limit
+	MemoryLimitBytes uint64
+	// BlkioReadBytes and BlkioWriteBytes are total bytes read and written to block devices
+	BlkioReadBytes  uint64
+	BlkioWriteBytes uint64
+}
+
+// PodMetrics is the resource usage of all pod containers at the moment
+type PodMetrics struct {
+	Pod string
+	// Total is the sum of the container stats, Time is the latest of the container stats times
+	Total Stats
+	// Containers has the init and main containers in the pod spec order, init containers first
+	Containers []ContainerMetrics
+}
+
+// ContainerMetrics is single container resource usage in PodMetrics
+type ContainerMetrics struct {
+	ID   string
+	Name string
+	Init bool
+	// Running is false if the container is not running, then the Stats are zero
+	Running bool
+	Stats   Stats
+}
+
+// ResourceLimits are the container cgroup memory and CPU limits.
+// In UpdateResources the zero fields are kept as is, in the result zero means no limit.
+type ResourceLimits struct {
+	MemoryLimitBytes int64
+	// CPUShares is the relative CPU weight compared to other containers
+	CPUShares uint64
+	// CPUQuota is the CPU time in microseconds the container can use in each CPUPeriod
+	CPUQuota  int64
+	CPUPeriod uint64
+}
+
+// DiskUsage is container filesystem usage at the moment
+type DiskUsage struct {
+	Time time.Time
+	// WritableBytes is the size of the container writable layer, zero if the root filesystem is read-only
+	WritableBytes int64
+	// Mounts is the disk space used in each bind mount of the container
+	Mounts []MountUsage
+}
+
+// MountUsage is the client side view of the runtime bind mount usage, read-only mounts have zero UsedBytes
+type MountUsage struct {
+	Source      string
+	Destination string
+	UsedBytes   int64
+}
+
+// BuildOptions defines how BuildImage builds the image from the Dockerfile in the build context root
+type BuildOptions struct {
+	// Tags are the image references for the built image, e.g. myapp:latest, at least one is required
+	Tags []string
+	// BuildArgs are the Dockerfile ARG values
+	BuildArgs map[string]string
+	// Target is the Dockerfile stage to build, empty builds the last stage
+	Target string
+}
+
+// BuildEvent is single line of the image build output.
+// The last event has the built image Digest, or Err if the build failed.
+type BuildEvent struct {
+	Line string
+	// Error is true for the lines which tell why the build failed
+	Error bool
+	// Digest is the built image manifest digest, set only in the last event
+	Digest string
+	// Err is the build failure, set only in the last event
+	Err error
+}
+
+// PushOptions defines the registry credentials for PushImage, empty Username means anonymous push
+type PushOptions struct {
+	Username string
+	Password string
+}
+
+// ImagePushProgress is the PushImage upload progress, the layers are the image blobs.
+// The last value has Err set if the push failed.
+type ImagePushProgress struct {
+	*progress.ImageFetch
+	// Err is the push failure, set only in the last value
+	Err error
+}
+
+// SyncOptions configures SyncDir
+type SyncOptions struct {
+	// Interval is how often the local directory is checked for changes, zero means DefaultSyncInterval
+	Interval time.Duration
+	// MaxFileSize is the largest file what gets synced, zero means DefaultSyncMaxFileSize and negative no limit.
+	// Larger files are skipped so that e.g. build artifacts don't flood the stream on every change.
+	MaxFileSize int64
+	// OnSync, if set, gets called after each push of changes, first time after the initial transfer
+	OnSync func(SyncResult)
+}
+
+// SyncResult tells what SyncDir pushed to the container, the paths are relative to the synced directory
+type SyncResult struct {
+	Copied  []string
+	Removed []string
+	// Skipped are the changed files which are larger than SyncOptions MaxFileSize
+	Skipped []string
+}
+
+// ListOptions selects the containers ListContainers returns
+type ListOptions struct {
+	// All includes also the containers which are not running, by default only the running containers are listed
+	All bool
+	// State lists only the containers in the state, e.g. "running" or "stopped", empty means all states
+	State string
+	// LabelSelector lists only the containers of the pods matching to the selector, e.g. "app=nginx,env!=dev".
+	// See model.ParseSelector for the syntax.
+	LabelSelector string
+}
+
+// ContainerSummary is single container in the ListContainers flat list
+type ContainerSummary struct {
+	ID    string
+	Name  string
+	Pod   string
+	Image string
+	State string
+	// Init is true for the pod init containers
+	Init         bool
+	RestartCount int
+	// StartedAt is when the container was last started, zero if never started
+	StartedAt time.Time
+	// Uptime is how long the container has been running, zero if it's not running
+	Uptime time.Duration
+}
+
+// Process is single process running inside the container
+type Process struct {
+	// PID is the process id inside the container
+	PID uint32
+	// PPID is the parent process id inside the container, zero if the parent is outside of the container
+	PPID uint32
+	// HostPID is the process id in the node
+	HostPID uint32
+	Command string
+	// CPUPercent is the CPU time used divided by the process running time, like in `ps`
+	CPUPercent float64
+	// RSSBytes is the process resident set size
+	RSSBytes uint64
+	// Init is true for the container main process, the PID 1 inside the container.
+	// If no process is marked as Init, the main process has exited.
+	Init bool
+}
+
+// LogOptions defines which container log lines to fetch
+type LogOptions struct {
+	// Follow keeps streaming new lines until the context get cancelled
+	Follow bool
+	// Tail limits the output to last N lines, zero means all lines
+	Tail int
+	// Since filters out lines written before the time
+	Since time.Time
+	// Prefix formats the container name prefix of the PodLogs lines, DefaultLogPrefix by default
+	Prefix func(containerName string) string
+}
+
+// LogFormat defines how GetContainerLogs returns the lines
+type LogFormat int
+
+const (
+	// LogFormatRaw return lines in the order they were written, message as is including the line ending
+	LogFormatRaw LogFormat = iota
+	// LogFormatJSON return lines in the order they were written, message without the line ending so it can be sent as JSON
+	LogFormatJSON
+	// LogFormatSplit return first all stdout lines and then all stderr lines, message without the line ending
+	LogFormatSplit
+)
+
+// LogStream tells which output stream the log line was written to
+type LogStream string
+
+const (
+	// LogStreamStdout is the container stdout
+	LogStreamStdout LogStream = "stdout"
+	// LogStreamStderr is the container stderr
+	LogStreamStderr LogStream = "stderr"
+)
+
+// LogLine is single line of container output
+type LogLine struct {
+	Time    time.Time `json:"time"`
+	Stream  LogStream `json:"stream"`
+	Message string    `json:"message"`
 }
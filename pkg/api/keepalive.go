@@ -0,0 +1,57 @@
+package api
+
+import (
+	"strings"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/keepalive"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	// keepaliveTime is how often the client pings the server while there are active streams,
+	// so that dead connection gets detected even when no data is moving, e.g. in Attach
+	keepaliveTime = 30 * time.Second
+
+	// keepaliveTimeout is how long the client waits ping response before closing the connection
+	keepaliveTimeout = 10 * time.Second
+
+	// keepaliveMinTime is the most frequent ping the server allows, must be less than keepaliveTime
+	keepaliveMinTime = 10 * time.Second
+)
+
+// connectionLostMessage is the prefix of the error message when established stream breaks
+const connectionLostMessage = "connection lost"
+
+func defaultKeepalive() keepalive.ClientParameters {
+	return keepalive.ClientParameters{
+		Time:    keepaliveTime,
+		Timeout: keepaliveTimeout,
+	}
+}
+
+func clientKeepalive(params keepalive.ClientParameters) grpc.DialOption {
+	return grpc.WithKeepaliveParams(params)
+}
+
+func serverKeepalive() grpc.ServerOption {
+	return grpc.KeepaliveEnforcementPolicy(keepalive.EnforcementPolicy{
+		MinTime: keepaliveMinTime,
+	})
+}
+
+// connectionLost converts the Unavailable error of already established stream to connection lost error,
+// e.g. when the server didn't respond to the keepalive ping, so that it's not mixed with unreachable server
+func connectionLost(err error) error {
+	s, ok := status.FromError(err)
+	if !ok || s.Code() != codes.Unavailable || isConnectionLost(s) {
+		return err
+	}
+	return status.Errorf(codes.Unavailable, "%s: %s", connectionLostMessage, s.Message())
+}
+
+func isConnectionLost(s *status.Status) bool {
+	return s.Code() == codes.Unavailable && strings.HasPrefix(s.Message(), connectionLostMessage)
+}
@@ -0,0 +1,66 @@
+package api
+
+import (
+	"sort"
+	"time"
+
+	"golang.org/x/net/context"
+
+	containers "github.com/ernoaapa/eliot/pkg/api/services/containers/v1"
+	pods "github.com/ernoaapa/eliot/pkg/api/services/pods/v1"
+)
+
+// ListContainers return the containers of all pods as flat list, like docker ps.
+// By default only the running containers are listed, see ListOptions to include the others and filter by state and labels.
+// The containers are sorted by the pod name, init containers first and otherwise in the pod spec order.
+func (c *Client) ListContainers(ctx context.Context, opts ListOptions) ([]ContainerSummary, error) {
+	all, err := c.GetPodsBySelector(ctx, opts.LabelSelector)
+	if err != nil {
+		return nil, err
+	}
+	sort.SliceStable(all, func(i, j int) bool {
+		return all[i].GetMetadata().GetName() < all[j].GetMetadata().GetName()
+	})
+
+	now := time.Now()
+	result := []ContainerSummary{}
+	for _, pod := range all {
+		for _, summary := range summarizeContainers(pod, now) {
+			if opts.State != "" && summary.State != opts.State {
+				continue
+			}
+			if opts.State == "" && !opts.All && summary.State != "running" {
+				continue
+			}
+			result = append(result, summary)
+		}
+	}
+	return result, nil
+}
+
+// summarizeContainers return the summary of the pod init and main containers
+func summarizeContainers(pod *pods.Pod, now time.Time) []ContainerSummary {
+	result := []ContainerSummary{}
+	add := func(statuses []*containers.ContainerStatus, init bool) {
+		for _, status := range statuses {
+			state := MapContainerState(status)
+			summary := ContainerSummary{
+				ID:           status.GetContainerID(),
+				Name:         status.GetName(),
+				Pod:          pod.GetMetadata().GetName(),
+				Image:        status.GetImage(),
+				State:        status.GetState(),
+				Init:         init,
+				RestartCount: int(status.GetRestartCount()),
+				StartedAt:    state.StartedAt,
+			}
+			if state.Running && !state.StartedAt.IsZero() {
+				summary.Uptime = now.Sub(state.StartedAt)
+			}
+			result = append(result, summary)
+		}
+	}
+	add(pod.GetStatus().GetInitContainerStatuses(), true)
+	add(pod.GetStatus().GetContainerStatuses(), false)
+	return result
+}
@@ -0,0 +1,70 @@
+package api
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/net/context"
+
+	core "github.com/ernoaapa/eliot/pkg/api/core"
+	containers "github.com/ernoaapa/eliot/pkg/api/services/containers/v1"
+	pods "github.com/ernoaapa/eliot/pkg/api/services/pods/v1"
+)
+
+func TestListContainers(t *testing.T) {
+	started := time.Now().Add(-time.Hour)
+	selectors := []string{}
+	podsServer := &fakePodsServer{list: func(req *pods.ListPodsRequest) (*pods.ListPodsResponse, error) {
+		selectors = append(selectors, req.LabelSelector)
+		return &pods.ListPodsResponse{Pods: []*pods.Pod{
+			{
+				Metadata: &core.ResourceMetadata{Name: "web"},
+				Status: &pods.PodStatus{
+					InitContainerStatuses: []*containers.ContainerStatus{
+						{ContainerID: "web-migrate", Name: "migrate", Image: "migrate:1", State: "stopped", Reason: "Completed"},
+					},
+					ContainerStatuses: []*containers.ContainerStatus{
+						{ContainerID: "web-nginx", Name: "nginx", Image: "nginx:1", State: "running", StartedAt: started.UnixNano(), RestartCount: 2},
+					},
+				},
+			},
+			{
+				Metadata: &core.ResourceMetadata{Name: "cron"},
+				Status: &pods.PodStatus{ContainerStatuses: []*containers.ContainerStatus{
+					{ContainerID: "cron-job", Name: "job", Image: "job:1", State: "stopped"},
+				}},
+			},
+		}}, nil
+	}}
+
+	client, stop := startFakeServer(t, podsServer, nil)
+	defer stop()
+
+	running, err := client.ListContainers(context.Background(), ListOptions{LabelSelector: "app=web"})
+	assert.NoError(t, err)
+	assert.Len(t, running, 1, "should list only the running containers by default")
+	nginx := running[0]
+	assert.Equal(t, "web-nginx", nginx.ID)
+	assert.Equal(t, "nginx", nginx.Name)
+	assert.Equal(t, "web", nginx.Pod)
+	assert.Equal(t, "nginx:1", nginx.Image)
+	assert.Equal(t, 2, nginx.RestartCount)
+	assert.Equal(t, started.UnixNano(), nginx.StartedAt.UnixNano())
+	assert.True(t, nginx.Uptime >= time.Hour, "should calculate the uptime, got %s", nginx.Uptime)
+	assert.Equal(t, []string{"app=web"}, selectors)
+
+	all, err := client.ListContainers(context.Background(), ListOptions{All: true})
+	assert.NoError(t, err)
+	ids := []string{}
+	for _, container := range all {
+		ids = append(ids, container.ID)
+	}
+	assert.Equal(t, []string{"cron-job", "web-migrate", "web-nginx"}, ids, "should sort by pod, init containers first")
+	assert.True(t, all[1].Init)
+	assert.Zero(t, all[0].Uptime, "stopped container should not have uptime")
+
+	stopped, err := client.ListContainers(context.Background(), ListOptions{State: "stopped"})
+	assert.NoError(t, err)
+	assert.Len(t, stopped, 2)
+}
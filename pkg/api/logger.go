@@ -0,0 +1,18 @@
+package api
+
+import (
+	"io/ioutil"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// discardLogger is the default client logger, which drops all messages so that
+// the package doesn't write into the logs of the application what embeds it
+var discardLogger = newDiscardLogger()
+
+func newDiscardLogger() *log.Entry {
+	logger := log.New()
+	logger.Out = ioutil.Discard
+	logger.Level = log.PanicLevel
+	return log.NewEntry(logger)
+}
@@ -20,11 +20,15 @@ func MapPodToInternalModel(pod *pods.Pod) model.Pod {
 		Metadata: model.Metadata{
 			Name:      pod.Metadata.Name,
 			Namespace: pod.Metadata.Namespace,
+			Labels:    pod.Metadata.Labels,
 		},
 		Spec: model.PodSpec{
-			Containers:  MapContainerToInternalModel(pod.Spec.Containers),
-			HostNetwork: pod.Spec.HostNetwork,
-			HostPID:     pod.Spec.HostPID,
+			Containers:    MapContainerToInternalModel(pod.Spec.Containers),
+			HostNetwork:   pod.Spec.HostNetwork,
+			HostPID:       pod.Spec.HostPID,
+			RestartPolicy: pod.Spec.RestartPolicy,
+
+			InitContainers: MapContainerToInternalModel(pod.Spec.InitContainers),
 		},
 	}
 }
@@ -41,11 +45,34 @@ func MapContainerToInternalModel(containers []*containers.Container) (result []m
 			WorkingDir: container.WorkingDir,
 			Mounts:     mapMountsToInternalModel(container.Mounts),
 			Pipe:       mapPipeToInternalModel(container.Pipe),
+
+			RestartPolicy:  container.RestartPolicy,
+			ReadinessProbe: mapProbeToInternalModel(container.ReadinessProbe),
 		})
 	}
 	return result
 }
 
+func mapProbeToInternalModel(probe *containers.Probe) *model.Probe {
+	if probe == nil {
+		return nil
+	}
+
+	result := &model.Probe{}
+	if probe.HttpGet != nil {
+		result.HTTPGet = &model.HTTPGetProbe{
+			Path: probe.HttpGet.Path,
+			Port: int(probe.HttpGet.Port),
+		}
+	}
+	if probe.Exec != nil {
+		result.Exec = &model.ExecProbe{
+			Command: probe.Exec.Command,
+		}
+	}
+	return result
+}
+
 func mapPipeToInternalModel(pipe *containers.PipeSet) *model.PipeSet {
 	if pipe == nil {
 		return nil
@@ -0,0 +1,19 @@
+package mapping
+
+import (
+	node "github.com/ernoaapa/eliot/pkg/api/services/node/v1"
+	"github.com/ernoaapa/eliot/pkg/runtime"
+)
+
+// MapEventToAPIModel maps runtime event to API model
+func MapEventToAPIModel(event runtime.Event) *node.Event {
+	return &node.Event{
+		Type:        string(event.Type),
+		Namespace:   event.Namespace,
+		Pod:         event.PodName,
+		ContainerID: event.ContainerID,
+		Image:       event.Image,
+		Time:        event.Time.UnixNano(),
+		Message:     event.Message,
+	}
+}
@@ -2,6 +2,7 @@ package mapping
 
 import (
 	"net"
+	"time"
 
 	core "github.com/ernoaapa/eliot/pkg/api/core"
 	containers "github.com/ernoaapa/eliot/pkg/api/services/containers/v1"
@@ -54,18 +55,25 @@ func MapPodsToAPIModel(pods []model.Pod) (result []*pods.Pod) {
 func MapPodToAPIModel(pod model.Pod) *pods.Pod {
 	return &pods.Pod{
 		Metadata: &core.ResourceMetadata{
-			Name:      pod.Metadata.Name,
-			Namespace: pod.Metadata.Namespace,
+			Name:            pod.Metadata.Name,
+			Namespace:       pod.Metadata.Namespace,
+			Labels:          pod.Metadata.Labels,
+			ResourceVersion: model.GetPodVersion(pod),
 		},
 		Spec: &pods.PodSpec{
 			Containers:    MapContainersToAPIModel(pod.Spec.Containers),
 			HostNetwork:   pod.Spec.HostNetwork,
 			HostPID:       pod.Spec.HostPID,
 			RestartPolicy: pod.Spec.RestartPolicy,
+
+			InitContainers: MapContainersToAPIModel(pod.Spec.InitContainers),
 		},
 		Status: &pods.PodStatus{
 			Hostname:          pod.Status.Hostname,
 			ContainerStatuses: MapContainerStatusesToAPIModel(pod.Status.ContainerStatuses),
+
+			InitContainerStatuses: MapContainerStatusesToAPIModel(pod.Status.InitContainerStatuses),
+			Cordoned:              pod.Status.Cordoned,
 		},
 	}
 }
@@ -81,11 +89,34 @@ func MapContainersToAPIModel(source []model.Container) (result []*containers.Con
 			Env:        container.Env,
 			Mounts:     mapMountsToAPIModel(container.Mounts),
 			Pipe:       mapPipeToAPIModel(container.Pipe),
+
+			RestartPolicy:  container.RestartPolicy,
+			ReadinessProbe: mapProbeToAPIModel(container.ReadinessProbe),
 		})
 	}
 	return result
 }
 
+func mapProbeToAPIModel(probe *model.Probe) *containers.Probe {
+	if probe == nil {
+		return nil
+	}
+
+	result := &containers.Probe{}
+	if probe.HTTPGet != nil {
+		result.HttpGet = &containers.HTTPGetProbe{
+			Path: probe.HTTPGet.Path,
+			Port: int32(probe.HTTPGet.Port),
+		}
+	}
+	if probe.Exec != nil {
+		result.Exec = &containers.ExecProbe{
+			Command: probe.Exec.Command,
+		}
+	}
+	return result
+}
+
 func mapMountsToAPIModel(mounts []model.Mount) (result []*containers.Mount) {
 	for _, mount := range mounts {
 		result = append(result, &containers.Mount{
@@ -120,11 +151,26 @@ func MapContainerStatusesToAPIModel(statuses []model.ContainerStatus) (result []
 			Image:        status.Image,
 			State:        status.State,
 			RestartCount: int32(status.RestartCount),
+			ExitCode:     int32(status.ExitCode),
+			Reason:       status.Reason,
+			StartedAt:    mapTimeToAPIModel(status.StartedAt),
+			FinishedAt:   mapTimeToAPIModel(status.FinishedAt),
+
+			Ready:            status.Ready,
+			ReadinessMessage: status.ReadinessMessage,
 		})
 	}
 	return result
 }
 
+// mapTimeToAPIModel return the time as Unix time in nanoseconds, zero time is zero
+func mapTimeToAPIModel(t time.Time) int64 {
+	if t.IsZero() {
+		return 0
+	}
+	return t.UnixNano()
+}
+
 func mapFilesystemsToAPIModel(disks []model.Filesystem) (result []*node.Filesystem) {
 	for _, disk := range disks {
 		result = append(result, &node.Filesystem{
@@ -0,0 +1,18 @@
+package mapping
+
+import (
+	containers "github.com/ernoaapa/eliot/pkg/api/services/containers/v1"
+	"github.com/ernoaapa/eliot/pkg/runtime"
+)
+
+// MapLogLinesToAPIModel maps container log lines to API model
+func MapLogLinesToAPIModel(lines []runtime.LogLine) (result []*containers.LogLine) {
+	for _, line := range lines {
+		result = append(result, &containers.LogLine{
+			Time:   line.Time.UnixNano(),
+			Stderr: line.Stderr,
+			Line:   line.Line,
+		})
+	}
+	return result
+}
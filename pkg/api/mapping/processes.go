@@ -0,0 +1,22 @@
+package mapping
+
+import (
+	containers "github.com/ernoaapa/eliot/pkg/api/services/containers/v1"
+	"github.com/ernoaapa/eliot/pkg/runtime"
+)
+
+// MapProcessesToAPIModel maps container processes to API model
+func MapProcessesToAPIModel(processes []runtime.Process) (result []*containers.Process) {
+	for _, process := range processes {
+		result = append(result, &containers.Process{
+			Pid:        process.PID,
+			Ppid:       process.PPID,
+			HostPid:    process.HostPID,
+			Command:    process.Command,
+			CpuPercent: process.CPUPercent,
+			RssBytes:   process.RSSBytes,
+			Init:       process.Init,
+		})
+	}
+	return result
+}
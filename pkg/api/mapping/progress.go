@@ -13,6 +13,7 @@ func MapImageFetchProgressToAPIModel(progresses []*progress.ImageFetch) (result
 			Layers = append(Layers, &pb.ImageLayerStatus{
 				Ref:    layer.Ref,
 				Digest: layer.Digest,
+				Status: layer.Status,
 				Offset: layer.Offset,
 				Total:  layer.Total,
 			})
@@ -28,24 +29,49 @@ func MapImageFetchProgressToAPIModel(progresses []*progress.ImageFetch) (result
 	return result
 }
 
-// MapAPIModelToImageFetchProgress maps image fetch progress information to API model
+// MapAPIModelToImageFetchProgress maps image fetch progress information from API model.
+// The result has single fetch for each image, if multiple containers use the same image,
+// their progress is merged and the fetch has the first container ID.
 func MapAPIModelToImageFetchProgress(progresses []*pb.ImageFetch) (result []*progress.ImageFetch) {
+	images := map[string]*progress.ImageFetch{}
 	for _, image := range progresses {
 		statuses := map[string]*progress.Status{}
 		for _, layer := range image.Layers {
 			statuses[layer.Ref] = &progress.Status{
 				Ref:    layer.Ref,
 				Digest: layer.Digest,
+				Status: mapLayerStatus(layer),
 				Offset: layer.Offset,
 				Total:  layer.Total,
 			}
 		}
-		result = append(result, progress.CreateImageFetch(
+		fetch := progress.CreateImageFetch(
 			image.ContainerID,
 			image.Image,
 			image.Resolved,
 			statuses,
-		))
+		)
+		fetch.Failed = image.Failed
+
+		if existing, ok := images[image.Image]; ok {
+			existing.Merge(fetch)
+			continue
+		}
+		images[image.Image] = fetch
+		result = append(result, fetch)
 	}
 	return result
 }
+
+// mapLayerStatus return the layer pull state, resolved from the offset when the server doesn't send it
+func mapLayerStatus(layer *pb.ImageLayerStatus) string {
+	switch {
+	case layer.Status != "":
+		return layer.Status
+	case layer.Total > 0 && layer.Offset >= layer.Total:
+		return progress.StatusDone
+	case layer.Offset > 0:
+		return progress.StatusDownloading
+	}
+	return progress.StatusWaiting
+}
@@ -0,0 +1,54 @@
+package mapping
+
+import (
+	containers "github.com/ernoaapa/eliot/pkg/api/services/containers/v1"
+	"github.com/ernoaapa/eliot/pkg/runtime"
+)
+
+// MapContainerStatsToAPIModel maps container resource usage to API model
+func MapContainerStatsToAPIModel(stats runtime.ContainerStats) *containers.ContainerStats {
+	return &containers.ContainerStats{
+		Time:             stats.Time.UnixNano(),
+		CpuNanoseconds:   stats.CPUNanoseconds,
+		MemoryUsageBytes: stats.MemoryUsageBytes,
+		MemoryLimitBytes: stats.MemoryLimitBytes,
+		BlkioReadBytes:   stats.BlkioReadBytes,
+		BlkioWriteBytes:  stats.BlkioWriteBytes,
+	}
+}
+
+// MapDiskUsageToAPIModel maps container filesystem usage to API model
+func MapDiskUsageToAPIModel(usage runtime.DiskUsage) *containers.DiskUsageResponse {
+	result := &containers.DiskUsageResponse{
+		Time:          usage.Time.UnixNano(),
+		WritableBytes: usage.WritableBytes,
+	}
+	for _, mount := range usage.Mounts {
+		result.Mounts = append(result.Mounts, &containers.MountUsage{
+			Source:      mount.Source,
+			Destination: mount.Destination,
+			UsedBytes:   mount.UsedBytes,
+		})
+	}
+	return result
+}
+
+// MapResourceLimitsToAPIModel maps container cgroup limits to API model
+func MapResourceLimitsToAPIModel(limits runtime.ResourceLimits) *containers.ResourceLimits {
+	return &containers.ResourceLimits{
+		MemoryLimitBytes: limits.MemoryLimitBytes,
+		CpuShares:        limits.CPUShares,
+		CpuQuota:         limits.CPUQuota,
+		CpuPeriod:        limits.CPUPeriod,
+	}
+}
+
+// MapAPIModelToResourceLimits maps container cgroup limits from API model
+func MapAPIModelToResourceLimits(limits *containers.ResourceLimits) runtime.ResourceLimits {
+	return runtime.ResourceLimits{
+		MemoryLimitBytes: limits.GetMemoryLimitBytes(),
+		CPUShares:        limits.GetCpuShares(),
+		CPUQuota:         limits.GetCpuQuota(),
+		CPUPeriod:        limits.GetCpuPeriod(),
+	}
+}
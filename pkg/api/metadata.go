@@ -0,0 +1,72 @@
+package api
+
+import (
+	"strings"
+	"time"
+
+	"golang.org/x/net/context"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// withMetadata adds the WithMetadata and WithPerCallMetadata metadata to the outgoing context.
+// The metadata set by the call itself, e.g. the container in Attach, is kept.
+func (c *Client) withMetadata(ctx context.Context) context.Context {
+	if c.metadata == nil && c.perCallMetadata == nil {
+		return ctx
+	}
+
+	md := normalizeMetadata(c.metadata)
+	if c.perCallMetadata != nil {
+		md = metadata.Join(md, normalizeMetadata(c.perCallMetadata(ctx)))
+	}
+	if existing, ok := metadata.FromOutgoingContext(ctx); ok {
+		md = metadata.Join(md, existing)
+	}
+	return metadata.NewOutgoingContext(ctx, md)
+}
+
+// unaryInterceptor waits the rate limit, adds the client metadata to each call, runs the WithUnaryInterceptors
+// interceptors and records the call metrics
+func (c *Client) unaryInterceptor(ctx context.Context, method string, req, reply interface{}, _ *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+	if err := c.waitRateLimit(ctx); err != nil {
+		return err
+	}
+	start := time.Now()
+	ctx = c.withMetadata(ctx)
+	invoker = chainUnaryInterceptors(c.unaryInterceptors, invoker)
+	err := c.invokeUnary(ctx, func(conn *grpc.ClientConn) error {
+		return invoker(ctx, method, req, reply, conn, c.compression.callOptions(method, c.callOptions.apply(method, opts))...)
+	})
+	c.observeRPC(method, start, err)
+	return err
+}
+
+// streamInterceptor waits the rate limit, adds the client metadata to each stream, runs the WithStreamInterceptors
+// interceptors and records the stream metrics
+func (c *Client) streamInterceptor(ctx context.Context, desc *grpc.StreamDesc, _ *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+	if err := c.waitRateLimit(ctx); err != nil {
+		return nil, err
+	}
+	start := time.Now()
+	ctx = c.withMetadata(ctx)
+	streamer = chainStreamInterceptors(c.streamInterceptors, streamer)
+	stream, err := c.openStream(ctx, func(conn *grpc.ClientConn) (grpc.ClientStream, error) {
+		return streamer(ctx, desc, conn, method, c.compression.callOptions(method, c.callOptions.apply(method, opts))...)
+	})
+	c.observeRPC(method, start, err)
+	if err != nil {
+		return nil, err
+	}
+	return c.observeStream(method, stream), nil
+}
+
+// normalizeMetadata return copy of the metadata with lowercase keys, as required by HTTP/2
+func normalizeMetadata(md metadata.MD) metadata.MD {
+	result := metadata.MD{}
+	for key, values := range md {
+		key = strings.ToLower(key)
+		result[key] = append(result[key], values...)
+	}
+	return result
+}
@@ -0,0 +1,117 @@
+package api
+
+import (
+	"net"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+
+	containers "github.com/ernoaapa/eliot/pkg/api/services/containers/v1"
+	node "github.com/ernoaapa/eliot/pkg/api/services/node/v1"
+	"github.com/ernoaapa/eliot/pkg/config"
+)
+
+func TestWithMetadata(t *testing.T) {
+	received := make(chan metadata.MD, 2)
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+
+	server := grpc.NewServer(grpc.UnaryInterceptor(func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		md, _ := metadata.FromIncomingContext(ctx)
+		received <- md
+		return handler(ctx, req)
+	}))
+	node.RegisterNodeServer(server, &fakeNodeServer{hostname: "test"})
+	go server.Serve(listener)
+	defer server.Stop()
+
+	tokens := []string{"first", "second"}
+	client, err := NewClient("eliot", config.Endpoint{URL: listener.Addr().String()},
+		WithInsecure(),
+		WithMetadata(metadata.MD{"X-Tenant-ID": []string{"foo"}}),
+		WithPerCallMetadata(func(ctx context.Context) metadata.MD {
+			token := tokens[0]
+			tokens = tokens[1:]
+			return metadata.Pairs("authorization", "Bearer "+token)
+		}),
+	)
+	assert.NoError(t, err)
+	defer client.Close()
+
+	for _, token := range []string{"first", "second"} {
+		_, err = client.GetInfo(context.Background())
+		assert.NoError(t, err)
+
+		md := <-received
+		assert.Equal(t, []string{"foo"}, md["x-tenant-id"])
+		assert.Equal(t, []string{"Bearer " + token}, md["authorization"], "should call the function on each call")
+	}
+}
+
+func TestWithMetadataKeepsCallMetadata(t *testing.T) {
+	received := make(chan metadata.MD, 1)
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+
+	server := grpc.NewServer()
+	containers.RegisterContainersServer(server, &fakeContainersServer{attach: func(server containers.Containers_AttachServer) error {
+		md, _ := metadata.FromIncomingContext(server.Context())
+		received <- md
+		return nil
+	}})
+	go server.Serve(listener)
+	defer server.Stop()
+
+	client, err := NewClient("eliot", config.Endpoint{URL: listener.Addr().String()},
+		WithInsecure(),
+		WithMetadata(metadata.Pairs("authorization", "Bearer token")),
+	)
+	assert.NoError(t, err)
+	defer client.Close()
+
+	err = client.Attach(context.Background(), "foo", NewAttachIO(nil, nil, nil))
+	assert.NoError(t, err)
+
+	md := <-received
+	assert.Equal(t, []string{"Bearer token"}, md["authorization"])
+	assert.Equal(t, []string{"foo"}, md["container"], "should keep the metadata set by Attach")
+}
+
+func TestWithUserAgent(t *testing.T) {
+	received := make(chan metadata.MD, 2)
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+
+	server := grpc.NewServer(grpc.UnaryInterceptor(func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		md, _ := metadata.FromIncomingContext(ctx)
+		received <- md
+		return handler(ctx, req)
+	}))
+	node.RegisterNodeServer(server, &fakeNodeServer{hostname: "test"})
+	go server.Serve(listener)
+	defer server.Stop()
+
+	for userAgent, opts := range map[string][]ClientOpts{
+		UserAgent("unknown"): {WithInsecure()},
+		"eli/v1.2.3":         {WithInsecure(), WithUserAgent("eli/v1.2.3")},
+	} {
+		client, err := NewClient("eliot", config.Endpoint{URL: listener.Addr().String()}, opts...)
+		assert.NoError(t, err)
+
+		_, err = client.GetInfo(context.Background())
+		assert.NoError(t, err)
+		client.Close()
+
+		md := <-received
+		if assert.Len(t, md["user-agent"], 1) {
+			assert.True(t, strings.HasPrefix(md["user-agent"][0], userAgent+" grpc-go/"), "unexpected user agent [%s]", md["user-agent"][0])
+		}
+	}
+
+	_, err = NewClient("eliot", config.Endpoint{URL: listener.Addr().String()}, WithInsecure(), WithUserAgent(" "))
+	assert.Error(t, err)
+}
@@ -0,0 +1,98 @@
+package api
+
+import (
+	"io"
+	"sync"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"google.golang.org/grpc"
+)
+
+// MetricsRecorder receives metrics of every call the client makes, e.g. to export them to Prometheus.
+// The method is the full gRPC method name, e.g. "/eliot.services.pods.v1.Pods/List".
+// The error is the gRPC status error, so you can resolve the status code with status.Code(err).
+type MetricsRecorder interface {
+	// ObserveRPC is called when unary call returns, or when the stream is opened.
+	// For streams, the duration is the stream setup latency.
+	ObserveRPC(method string, duration time.Duration, err error)
+}
+
+// StreamMetricsRecorder can be implemented by the MetricsRecorder to receive also metrics of the streams, e.g. Attach
+type StreamMetricsRecorder interface {
+	// ObserveStream is called when the stream ends with the time the stream was open and the message bytes sent and received.
+	// The error is nil if the stream ended normally.
+	ObserveStream(method string, duration time.Duration, sentBytes, receivedBytes int64, err error)
+}
+
+func (c *Client) observeRPC(method string, start time.Time, err error) {
+	if c.metrics != nil {
+		c.metrics.ObserveRPC(method, time.Since(start), err)
+	}
+}
+
+// observeStream wraps the stream to record the stream metrics, if the recorder supports it
+func (c *Client) observeStream(method string, stream grpc.ClientStream) grpc.ClientStream {
+	recorder, ok := c.metrics.(StreamMetricsRecorder)
+	if !ok {
+		return stream
+	}
+	return &metricsStream{
+		ClientStream: stream,
+		method:       method,
+		start:        time.Now(),
+		recorder:     recorder,
+	}
+}
+
+// metricsStream counts the bytes sent and received and records the metrics when the stream ends.
+// The stream ends when RecvMsg returns error, e.g. io.EOF or the context get cancelled.
+type metricsStream struct {
+	grpc.ClientStream
+	method   string
+	start    time.Time
+	recorder StreamMetricsRecorder
+
+	mu       sync.Mutex
+	sent     int64
+	received int64
+	done     bool
+}
+
+func (s *metricsStream) SendMsg(m interface{}) error {
+	err := s.ClientStream.SendMsg(m)
+	if err == nil {
+		s.mu.Lock()
+		s.sent += messageSize(m)
+		s.mu.Unlock()
+	}
+	return err
+}
+
+func (s *metricsStream) RecvMsg(m interface{}) error {
+	err := s.ClientStream.RecvMsg(m)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err == nil {
+		s.received += messageSize(m)
+		return nil
+	}
+
+	if !s.done {
+		s.done = true
+		streamErr := err
+		if err == io.EOF {
+			streamErr = nil
+		}
+		s.recorder.ObserveStream(s.method, time.Since(s.start), s.sent, s.received, streamErr)
+	}
+	return err
+}
+
+func messageSize(m interface{}) int64 {
+	if msg, ok := m.(proto.Message); ok {
+		return int64(proto.Size(msg))
+	}
+	return 0
+}
@@ -0,0 +1,99 @@
+package api
+
+import (
+	"io"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc"
+
+	containers "github.com/ernoaapa/eliot/pkg/api/services/containers/v1"
+	"github.com/ernoaapa/eliot/pkg/config"
+)
+
+type fakeMetricsRecorder struct {
+	mu      sync.Mutex
+	methods []string
+	errs    []error
+
+	streamSent     int64
+	streamReceived int64
+	streamErr      error
+	streams        int
+}
+
+func (r *fakeMetricsRecorder) ObserveRPC(method string, duration time.Duration, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.methods = append(r.methods, method)
+	r.errs = append(r.errs, err)
+}
+
+func (r *fakeMetricsRecorder) ObserveStream(method string, duration time.Duration, sentBytes, receivedBytes int64, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.streams++
+	r.streamSent = sentBytes
+	r.streamReceived = receivedBytes
+	r.streamErr = err
+}
+
+type fakeClientStream struct {
+	grpc.ClientStream
+	messages []*containers.StdoutStreamResponse
+}
+
+func (s *fakeClientStream) SendMsg(m interface{}) error {
+	return nil
+}
+
+func (s *fakeClientStream) RecvMsg(m interface{}) error {
+	if len(s.messages) == 0 {
+		return io.EOF
+	}
+	*m.(*containers.StdoutStreamResponse) = *s.messages[0]
+	s.messages = s.messages[1:]
+	return nil
+}
+
+func TestWithMetricsObservesUnaryCalls(t *testing.T) {
+	address, stop := startFakeNodeServer(t, "node")
+	defer stop()
+
+	recorder := &fakeMetricsRecorder{}
+	client, err := NewClient("eliot", config.Endpoint{Name: "node", URL: address}, WithInsecure(), WithMetrics(recorder))
+	assert.NoError(t, err)
+	defer client.Close()
+
+	_, err = client.GetInfo(context.Background())
+	assert.NoError(t, err)
+
+	assert.Equal(t, []string{"/eliot.services.containers.v1.Node/Info"}, recorder.methods)
+	assert.Equal(t, []error{nil}, recorder.errs)
+}
+
+func TestMetricsStreamObservesTransferredBytes(t *testing.T) {
+	recorder := &fakeMetricsRecorder{}
+	client := &Client{metrics: recorder}
+
+	stream := client.observeStream("/test", &fakeClientStream{
+		messages: []*containers.StdoutStreamResponse{{Output: []byte("hello")}, {Output: []byte("world")}},
+	})
+
+	assert.NoError(t, stream.SendMsg(&containers.StdinStreamRequest{Input: []byte("foo")}))
+	for {
+		if err := stream.RecvMsg(&containers.StdoutStreamResponse{}); err != nil {
+			assert.Equal(t, io.EOF, err)
+			break
+		}
+	}
+	stream.RecvMsg(&containers.StdoutStreamResponse{})
+
+	assert.Equal(t, 1, recorder.streams, "should observe the stream only once")
+	assert.Equal(t, int64(5), recorder.streamSent)
+	assert.Equal(t, int64(14), recorder.streamReceived)
+	assert.NoError(t, recorder.streamErr, "should not report EOF as error")
+}
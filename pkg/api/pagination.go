@@ -0,0 +1,54 @@
+package api
+
+import (
+	"encoding/base64"
+	"fmt"
+	"sort"
+
+	"github.com/ernoaapa/eliot/pkg/model"
+)
+
+// paginatePods sorts the pods by name and return the page after the page token.
+// The token is the last pod name of the previous page so pods don't get skipped
+// or duplicated even if pods get created or deleted between the pages.
+func paginatePods(pods []model.Pod, pageSize int, pageToken string) (page []model.Pod, nextPageToken string, err error) {
+	if pageSize < 0 {
+		return nil, "", fmt.Errorf("Invalid page size [%d], must be zero or greater", pageSize)
+	}
+
+	after, err := decodePageToken(pageToken)
+	if err != nil {
+		return nil, "", err
+	}
+
+	sorted := append([]model.Pod{}, pods...)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].Metadata.Name < sorted[j].Metadata.Name
+	})
+
+	start := 0
+	if pageToken != "" {
+		start = sort.Search(len(sorted), func(i int) bool {
+			return sorted[i].Metadata.Name > after
+		})
+	}
+	page = sorted[start:]
+
+	if pageSize > 0 && len(page) > pageSize {
+		page = page[:pageSize]
+		nextPageToken = encodePageToken(page[len(page)-1].Metadata.Name)
+	}
+	return page, nextPageToken, nil
+}
+
+func encodePageToken(name string) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(name))
+}
+
+func decodePageToken(token string) (string, error) {
+	name, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return "", fmt.Errorf("Invalid page token [%s]", token)
+	}
+	return string(name), nil
+}
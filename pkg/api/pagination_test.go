@@ -0,0 +1,65 @@
+package api
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ernoaapa/eliot/pkg/model"
+)
+
+func podNames(pods []model.Pod) (result []string) {
+	for _, pod := range pods {
+		result = append(result, pod.Metadata.Name)
+	}
+	return result
+}
+
+func TestPaginatePods(t *testing.T) {
+	all := []model.Pod{
+		{Metadata: model.Metadata{Name: "c"}},
+		{Metadata: model.Metadata{Name: "a"}},
+		{Metadata: model.Metadata{Name: "b"}},
+	}
+
+	page, next, err := paginatePods(all, 2, "")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"a", "b"}, podNames(page), "should sort by name")
+	assert.NotEmpty(t, next)
+
+	page, next, err = paginatePods(all, 2, next)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"c"}, podNames(page))
+	assert.Empty(t, next, "should not return token for the last page")
+
+	page, next, err = paginatePods(all, 0, "")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"a", "b", "c"}, podNames(page), "zero page size should return all")
+	assert.Empty(t, next)
+}
+
+func TestPaginatePodsWhenPodsChange(t *testing.T) {
+	_, next, err := paginatePods([]model.Pod{
+		{Metadata: model.Metadata{Name: "a"}},
+		{Metadata: model.Metadata{Name: "b"}},
+		{Metadata: model.Metadata{Name: "d"}},
+	}, 2, "")
+	assert.NoError(t, err)
+
+	// "a" deleted and "c" created between the pages
+	page, _, err := paginatePods([]model.Pod{
+		{Metadata: model.Metadata{Name: "b"}},
+		{Metadata: model.Metadata{Name: "c"}},
+		{Metadata: model.Metadata{Name: "d"}},
+	}, 2, next)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"c", "d"}, podNames(page), "should continue after the last pod of previous page")
+}
+
+func TestPaginatePodsInvalidInput(t *testing.T) {
+	_, _, err := paginatePods(nil, -1, "")
+	assert.Error(t, err)
+
+	_, _, err = paginatePods(nil, 1, "%%%")
+	assert.Error(t, err)
+}
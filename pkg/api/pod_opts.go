@@ -2,9 +2,15 @@ package api
 
 import (
 	"fmt"
+	"io/ioutil"
+	"os"
+	"sort"
+	"strings"
 
 	containers "github.com/ernoaapa/eliot/pkg/api/services/containers/v1"
 	pods "github.com/ernoaapa/eliot/pkg/api/services/pods/v1"
+	"github.com/pkg/errors"
+	"google.golang.org/grpc/codes"
 )
 
 // WithSharedMount adds mount point to each container
@@ -38,3 +44,165 @@ func WithContainer(container *containers.Container) PodOpts {
 		return nil
 	}
 }
+
+// WithInitContainer adds init container to the Pod spec. The init containers run one at a time in the order they
+// are added, each to completion, before the containers get started. If init container fails, the pod containers
+// don't get started, see WaitForPodReady.
+// The init container restart policy must be RestartOnFailure, which is the default, or RestartNever.
+func WithInitContainer(container *containers.Container) PodOpts {
+	return func(pod *pods.Pod) error {
+		pod.Spec.InitContainers = append(pod.Spec.InitContainers, container)
+		return nil
+	}
+}
+
+// WithEnv sets the environment variables of the container, existing variables with the same name are replaced.
+// Returns ErrContainerNotFound if the pod doesn't have the container.
+func WithEnv(containerName string, env map[string]string) PodOpts {
+	return func(pod *pods.Pod) error {
+		container, err := findSpecContainer(pod, containerName)
+		if err != nil {
+			return err
+		}
+
+		names := make([]string, 0, len(env))
+		for name := range env {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		for _, name := range names {
+			if err := setEnv(container, name, env[name]); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
+
+// WithSecretEnv sets the container environment variable to the secret value, so that the secret doesn't need to be
+// written to the pod definition. The secret is read when the option gets applied, the reference is either
+// "env:NAME" to read local environment variable or "file:PATH" to read the file content without the trailing newline.
+// Returns ErrContainerNotFound if the pod doesn't have the container.
+func WithSecretEnv(containerName, envVar, secretRef string) PodOpts {
+	return func(pod *pods.Pod) error {
+		container, err := findSpecContainer(pod, containerName)
+		if err != nil {
+			return err
+		}
+
+		value, err := resolveSecret(secretRef)
+		if err != nil {
+			return errors.Wrapf(err, "Cannot set container [%s] environment variable [%s]", containerName, envVar)
+		}
+		return setEnv(container, envVar, value)
+	}
+}
+
+// WithEnvFromFile sets the container environment variables from file which has line for each variable
+// in format NAME=VALUE. Empty lines and lines starting with # are ignored, the value can be quoted.
+// Returns ErrContainerNotFound if the pod doesn't have the container.
+func WithEnvFromFile(containerName, path string) PodOpts {
+	return func(pod *pods.Pod) error {
+		container, err := findSpecContainer(pod, containerName)
+		if err != nil {
+			return err
+		}
+
+		data, err := ioutil.ReadFile(path)
+		if err != nil {
+			return errors.Wrapf(err, "Failed to read environment file [%s]", path)
+		}
+
+		for i, line := range strings.Split(string(data), "\n") {
+			line = strings.TrimSpace(line)
+			if line == "" || strings.HasPrefix(line, "#") {
+				continue
+			}
+
+			parts := strings.SplitN(strings.TrimPrefix(line, "export "), "=", 2)
+			if len(parts) != 2 {
+				return fmt.Errorf("Invalid line %d in environment file [%s], must be in format NAME=VALUE", i+1, path)
+			}
+			if err := setEnv(container, strings.TrimSpace(parts[0]), unquote(strings.TrimSpace(parts[1]))); err != nil {
+				return errors.Wrapf(err, "Invalid line %d in environment file [%s]", i+1, path)
+			}
+		}
+		return nil
+	}
+}
+
+// findSpecContainer return the container with the name from the pod spec, including the init containers
+func findSpecContainer(pod *pods.Pod, containerName string) (*containers.Container, error) {
+	names := []string{}
+	for _, container := range append(append([]*containers.Container{}, pod.GetSpec().GetContainers()...), pod.GetSpec().GetInitContainers()...) {
+		if container.GetName() == containerName {
+			return container, nil
+		}
+		names = append(names, container.GetName())
+	}
+	return nil, &Error{
+		Code:    codes.NotFound,
+		Message: fmt.Sprintf("Container [%s] not found in pod [%s], available containers: [%s]", containerName, pod.GetMetadata().GetName(), strings.Join(names, ", ")),
+		cause:   ErrContainerNotFound,
+	}
+}
+
+// isInitContainer return true if the container is one of the pod init containers
+func isInitContainer(pod *pods.Pod, containerName string) bool {
+	for _, container := range pod.GetSpec().GetInitContainers() {
+		if container.GetName() == containerName {
+			return true
+		}
+	}
+	return false
+}
+
+// setEnv sets the environment variable, replacing the existing one with the same name
+func setEnv(container *containers.Container, name, value string) error {
+	if name == "" || strings.ContainsAny(name, "= ") {
+		return fmt.Errorf("Invalid environment variable name [%s]", name)
+	}
+
+	entry := fmt.Sprintf("%s=%s", name, value)
+	for i, existing := range container.Env {
+		if strings.SplitN(existing, "=", 2)[0] == name {
+			container.Env[i] = entry
+			return nil
+		}
+	}
+	container.Env = append(container.Env, entry)
+	return nil
+}
+
+// resolveSecret reads the secret value from the local environment ("env:NAME") or file ("file:PATH")
+func resolveSecret(ref string) (string, error) {
+	parts := strings.SplitN(ref, ":", 2)
+	if len(parts) != 2 || parts[1] == "" {
+		return "", fmt.Errorf("Invalid secret reference [%s], must be env:NAME or file:PATH", ref)
+	}
+
+	switch parts[0] {
+	case "env":
+		value, ok := os.LookupEnv(parts[1])
+		if !ok {
+			return "", fmt.Errorf("Secret environment variable [%s] is not set", parts[1])
+		}
+		return value, nil
+	case "file":
+		data, err := ioutil.ReadFile(parts[1])
+		if err != nil {
+			return "", errors.Wrapf(err, "Failed to read secret file [%s]", parts[1])
+		}
+		return strings.TrimRight(string(data), "\r\n"), nil
+	}
+	return "", fmt.Errorf("Invalid secret reference [%s], must be env:NAME or file:PATH", ref)
+}
+
+// unquote removes matching single or double quotes around the value
+func unquote(value string) string {
+	if len(value) >= 2 && (value[0] == '"' || value[0] == '\'') && value[len(value)-1] == value[0] {
+		return value[1 : len(value)-1]
+	}
+	return value
+}
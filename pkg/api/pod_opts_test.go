@@ -0,0 +1,73 @@
+package api
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ernoaapa/eliot/pkg/api/core"
+	containers "github.com/ernoaapa/eliot/pkg/api/services/containers/v1"
+	pods "github.com/ernoaapa/eliot/pkg/api/services/pods/v1"
+	"github.com/stretchr/testify/assert"
+)
+
+func newEnvTestPod() *pods.Pod {
+	return &pods.Pod{
+		Metadata: &core.ResourceMetadata{Name: "foo"},
+		Spec: &pods.PodSpec{Containers: []*containers.Container{
+			{Name: "app", Image: "alpine", Env: []string{"LEVEL=info"}},
+			{Name: "sidecar", Image: "alpine"},
+		}},
+	}
+}
+
+func TestWithEnv(t *testing.T) {
+	pod := newEnvTestPod()
+	assert.NoError(t, WithEnv("app", map[string]string{"LEVEL": "debug", "PORT": "8080"})(pod))
+	assert.Equal(t, []string{"LEVEL=debug", "PORT=8080"}, pod.Spec.Containers[0].Env)
+	assert.Empty(t, pod.Spec.Containers[1].Env, "should change only the given container")
+
+	err := WithEnv("web", map[string]string{"PORT": "8080"})(pod)
+	assert.True(t, errors.Is(err, ErrContainerNotFound))
+	assert.Contains(t, err.Error(), "app, sidecar", "should list available containers")
+
+	assert.Error(t, WithEnv("app", map[string]string{"IN VALID": "x"})(pod))
+}
+
+func TestWithSecretEnv(t *testing.T) {
+	dir, _ := ioutil.TempDir("", "secrets")
+	defer os.RemoveAll(dir)
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(dir, "token"), []byte("s3cr3t\n"), 0600))
+	os.Setenv("TEST_WITH_SECRET_ENV", "from-env")
+	defer os.Unsetenv("TEST_WITH_SECRET_ENV")
+
+	pod := newEnvTestPod()
+	assert.NoError(t, WithSecretEnv("app", "TOKEN", "file:"+filepath.Join(dir, "token"))(pod))
+	assert.NoError(t, WithSecretEnv("sidecar", "PASSWORD", "env:TEST_WITH_SECRET_ENV")(pod))
+	assert.Equal(t, []string{"LEVEL=info", "TOKEN=s3cr3t"}, pod.Spec.Containers[0].Env)
+	assert.Equal(t, []string{"PASSWORD=from-env"}, pod.Spec.Containers[1].Env)
+
+	assert.Error(t, WithSecretEnv("app", "TOKEN", "env:TEST_WITH_SECRET_ENV_MISSING")(pod))
+	assert.Error(t, WithSecretEnv("app", "TOKEN", "vault:token")(pod))
+	assert.True(t, errors.Is(WithSecretEnv("web", "TOKEN", "env:TEST_WITH_SECRET_ENV")(pod), ErrContainerNotFound))
+}
+
+func TestWithEnvFromFile(t *testing.T) {
+	dir, _ := ioutil.TempDir("", "env")
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "app.env")
+	assert.NoError(t, ioutil.WriteFile(path, []byte("# app settings\n\nLEVEL=warn\nexport NAME=\"my app\"\nURL=http://localhost?a=b\n"), 0644))
+
+	pod := newEnvTestPod()
+	assert.NoError(t, WithEnvFromFile("app", path)(pod))
+	assert.Equal(t, []string{"LEVEL=warn", "NAME=my app", "URL=http://localhost?a=b"}, pod.Spec.Containers[0].Env)
+
+	assert.NoError(t, ioutil.WriteFile(path, []byte("LEVEL=warn\nINVALID\n"), 0644))
+	err := WithEnvFromFile("app", path)(pod)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "line 2")
+
+	assert.Error(t, WithEnvFromFile("app", filepath.Join(dir, "missing.env"))(pod))
+}
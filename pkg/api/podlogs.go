@@ -0,0 +1,247 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	containers "github.com/ernoaapa/eliot/pkg/api/services/containers/v1"
+	pods "github.com/ernoaapa/eliot/pkg/api/services/pods/v1"
+	"golang.org/x/net/context"
+)
+
+// podLogsPollInterval is how often PodLogs checks the pod for started and restarted containers
+const podLogsPollInterval = time.Second
+
+// LogRestartMarker is written by FollowPodContainerLogs when the container restarted, so the lines
+// before and after it are from different runs and some lines in between might be missing
+const LogRestartMarker = "--- container restarted ---"
+
+// DefaultLogPrefix formats the PodLogs line prefix as "[container] "
+func DefaultLogPrefix(containerName string) string {
+	return fmt.Sprintf("[%s] ", containerName)
+}
+
+// PodLogs writes output lines of every container in the pod to the writer, each line prefixed with
+// the container name and timestamp, in the order the lines arrive. LogOptions Prefix formats the prefix.
+// With Follow option, the containers which get started or restarted later are followed too and
+// the stream continues until the context get cancelled, even if the containers stop.
+func (c *Client) PodLogs(ctx context.Context, podName string, w io.Writer, opts LogOptions) error {
+	pod, err := c.GetPod(ctx, podName)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	logs := &podLogs{
+		client:  c,
+		opts:    opts,
+		prefix:  opts.Prefix,
+		cancel:  cancel,
+		w:       w,
+		streams: map[string]*containerLogStream{},
+	}
+	if logs.prefix == nil {
+		logs.prefix = DefaultLogPrefix
+	}
+
+	logs.followAll(ctx, pod)
+	if !opts.Follow {
+		return logs.wait()
+	}
+
+	ticker := time.NewTicker(podLogsPollInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return logs.wait()
+		case <-ticker.C:
+		}
+
+		pod, err := c.GetPod(ctx, podName)
+		if err != nil {
+			if ctx.Err() == nil {
+				c.logger.Debugf("Failed to check pod [%s] containers for logs, check again in %s: %s", podName, podLogsPollInterval, err)
+			}
+			continue
+		}
+		logs.followAll(ctx, pod)
+	}
+}
+
+// podLogs merges the log streams of the pod containers
+type podLogs struct {
+	client *Client
+	opts   LogOptions
+	prefix func(containerName string) string
+	cancel func()
+	wg     sync.WaitGroup
+
+	// mu guards the writer, the streams and the err
+	mu      sync.Mutex
+	w       io.Writer
+	streams map[string]*containerLogStream
+	err     error
+}
+
+// containerLogStream is the log stream of single container, by the container name
+type containerLogStream struct {
+	containerID string
+	open        bool
+	// lastTime is the time of the last written line, the stream continues from it after the container restarts
+	lastTime int64
+}
+
+// followAll opens log stream for the containers which don't have it yet, or which got restarted after the stream ended
+func (l *podLogs) followAll(ctx context.Context, pod *pods.Pod) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	for _, status := range pod.GetStatus().GetContainerStatuses() {
+		stream, ok := l.streams[status.GetName()]
+		switch {
+		case !ok:
+			stream = &containerLogStream{containerID: status.GetContainerID()}
+			l.streams[status.GetName()] = stream
+		case stream.open || !MapContainerState(status).Running:
+			continue
+		case stream.containerID != status.GetContainerID():
+			// The container got recreated, all the lines of the new container are new
+			*stream = containerLogStream{containerID: status.GetContainerID()}
+		}
+
+		opts := l.opts
+		if stream.lastTime > 0 {
+			opts.Tail = 0
+			opts.Since = time.Unix(0, stream.lastTime+1)
+		}
+		stream.open = true
+
+		l.wg.Add(1)
+		go l.follow(ctx, status.GetName(), stream, opts)
+	}
+}
+
+func (l *podLogs) follow(ctx context.Context, containerName string, stream *containerLogStream, opts LogOptions) {
+	defer l.wg.Done()
+
+	err := l.client.streamLogs(ctx, stream.containerID, opts, func(line *containers.LogLine) error {
+		return l.write(containerName, stream, line)
+	})
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	stream.open = false
+	if err == nil || ctx.Err() != nil {
+		return
+	}
+	if l.opts.Follow {
+		l.client.logger.Warnf("Container [%s] log stream closed with error: %s", containerName, err)
+	} else if l.err == nil {
+		l.err = err
+	}
+}
+
+// write writes the line in single write so the lines of different containers don't get mixed
+func (l *podLogs) write(containerName string, stream *containerLogStream, line *containers.LogLine) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.err != nil {
+		return l.err
+	}
+
+	stream.lastTime = line.Time
+	if _, err := fmt.Fprintf(l.w, "%s%s %s", l.prefix(containerName), formatLogTime(line.Time), line.Line); err != nil {
+		l.err = err
+		l.cancel()
+		return err
+	}
+	return nil
+}
+
+// wait waits all the streams to end and returns the first error
+func (l *podLogs) wait() error {
+	l.wg.Wait()
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.err
+}
+
+// FollowPodContainerLogs writes the pod container output lines prefixed with timestamp to the writer,
+// like FollowLogs with Follow option, but keeps following the container across restarts.
+// When the container exits, the container gets resolved again by the name, possibly with new ID,
+// and when it's running again, LogRestartMarker line is written before the new lines.
+// The checks back off exponentially while the container is not running, or the node is unavailable.
+// containerName can be empty if there's only one container in the pod.
+// Returns nil when the context get cancelled or the pod gets deleted.
+func (c *Client) FollowPodContainerLogs(ctx context.Context, podName, containerName string, w io.Writer) error {
+	var (
+		followed *containers.ContainerStatus
+		lastTime int64
+	)
+	backoff := retryPolicy{backoff: podLogsPollInterval, logger: c.logger}
+
+	for attempt := 0; ; {
+		status, err := c.resolveContainerStatus(ctx, podName, containerName)
+		switch {
+		case ctx.Err() != nil:
+			return nil
+		case errors.Is(err, ErrPodNotFound):
+			return nil
+		case err != nil && !isRetryable(err):
+			return err
+		case err == nil && MapContainerState(status).Running:
+			opts := LogOptions{Follow: true}
+			if followed != nil {
+				if isContainerRestarted(followed, status) {
+					if _, err := fmt.Fprintf(w, "%s %s\n", formatLogTime(time.Now().UnixNano()), LogRestartMarker); err != nil {
+						return err
+					}
+				}
+				if followed.GetContainerID() == status.GetContainerID() && lastTime > 0 {
+					opts.Since = time.Unix(0, lastTime+1)
+				} else {
+					lastTime = 0
+				}
+			}
+			followed, attempt = status, 0
+
+			err := c.streamLogs(ctx, status.GetContainerID(), opts, func(line *containers.LogLine) error {
+				lastTime = line.Time
+				_, err := fmt.Fprintf(w, "%s %s", formatLogTime(line.Time), line.Line)
+				return err
+			})
+			if err != nil && !errors.Is(err, ErrContainerNotFound) && !isRetryable(err) {
+				return err
+			}
+		}
+
+		attempt++
+		wait := backoff.getBackoff(attempt)
+		c.logger.Debugf("Check container [%s] in pod [%s] logs again in %s", containerName, podName, wait)
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(wait):
+		}
+	}
+}
+
+// resolveContainerStatus return the current status of the pod container, containerName can be empty if there's only one container
+func (c *Client) resolveContainerStatus(ctx context.Context, podName, containerName string) (*containers.ContainerStatus, error) {
+	pod, err := c.GetPod(ctx, podName)
+	if err != nil {
+		return nil, err
+	}
+	return findContainerStatus(pod, containerName)
+}
+
+// isContainerRestarted return true if the container got recreated or restarted since the previous status
+func isContainerRestarted(previous, current *containers.ContainerStatus) bool {
+	return previous.GetContainerID() != current.GetContainerID() || previous.GetRestartCount() != current.GetRestartCount()
+}
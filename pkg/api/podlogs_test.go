@@ -0,0 +1,194 @@
+package api
+
+import (
+	"bytes"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/net/context"
+
+	"github.com/ernoaapa/eliot/pkg/api/core"
+	containers "github.com/ernoaapa/eliot/pkg/api/services/containers/v1"
+	pods "github.com/ernoaapa/eliot/pkg/api/services/pods/v1"
+)
+
+// syncBuffer is bytes.Buffer which can be read while PodLogs writes to it
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+func newLogsTestPod(statuses ...*containers.ContainerStatus) *pods.Pod {
+	return &pods.Pod{
+		Metadata: &core.ResourceMetadata{Name: "foo", Namespace: "eliot"},
+		Status:   &pods.PodStatus{ContainerStatuses: statuses},
+	}
+}
+
+func startFakeLogsServer(t *testing.T, pod func() *pods.Pod, logs func(req *containers.LogsRequest, server containers.Containers_LogsServer) error) (*Client, func()) {
+	return startFakeServer(t, &fakePodsServer{list: func(req *pods.ListPodsRequest) (*pods.ListPodsResponse, error) {
+		if current := pod(); current != nil {
+			return &pods.ListPodsResponse{Pods: []*pods.Pod{current}}, nil
+		}
+		return &pods.ListPodsResponse{}, nil
+	}}, &fakeContainersServer{logs: logs})
+}
+
+func sendLogLine(server containers.Containers_LogsServer, unixNano int64, line string) error {
+	return server.Send(&containers.LogsStreamResponse{Lines: []*containers.LogLine{{Time: unixNano, Line: []byte(line)}}})
+}
+
+func TestPodLogsPrefixesEachContainer(t *testing.T) {
+	pod := newLogsTestPod(
+		&containers.ContainerStatus{ContainerID: "1", Name: "app", State: "running"},
+		&containers.ContainerStatus{ContainerID: "2", Name: "sidecar", State: "running"},
+	)
+	client, stop := startFakeLogsServer(t, func() *pods.Pod { return pod }, func(req *containers.LogsRequest, server containers.Containers_LogsServer) error {
+		return sendLogLine(server, 1, "hello from "+req.ContainerID+"\n")
+	})
+	defer stop()
+
+	out := &bytes.Buffer{}
+	err := client.PodLogs(context.Background(), "foo", out, LogOptions{
+		Prefix: func(containerName string) string { return containerName + " | " },
+	})
+	assert.NoError(t, err)
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	assert.Len(t, lines, 2)
+	assert.Contains(t, lines, "app | "+formatLogTime(1)+" hello from 1")
+	assert.Contains(t, lines, "sidecar | "+formatLogTime(1)+" hello from 2")
+}
+
+func TestPodLogsKeepsFollowingWhenContainerStops(t *testing.T) {
+	var mu sync.Mutex
+	pod := newLogsTestPod(
+		&containers.ContainerStatus{ContainerID: "1", Name: "migrate", State: "stopped"},
+		&containers.ContainerStatus{ContainerID: "2", Name: "app", State: "running"},
+	)
+	getPod := func() *pods.Pod {
+		mu.Lock()
+		defer mu.Unlock()
+		return pod
+	}
+
+	requests := make(chan *containers.LogsRequest, 10)
+	client, stop := startFakeLogsServer(t, getPod, func(req *containers.LogsRequest, server containers.Containers_LogsServer) error {
+		requests <- req
+		if req.ContainerID == "1" {
+			// Migration ends right away, the app keeps running
+			return sendLogLine(server, 10, "migrated\n")
+		}
+		if err := sendLogLine(server, 20, "started\n"); err != nil {
+			return err
+		}
+		<-server.Context().Done()
+		return nil
+	})
+	defer stop()
+
+	out := &syncBuffer{}
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() {
+		done <- client.PodLogs(ctx, "foo", out, LogOptions{Follow: true})
+	}()
+
+	<-requests
+	<-requests
+
+	// The migration container gets started again, the stream should continue after the last line
+	mu.Lock()
+	pod = newLogsTestPod(
+		&containers.ContainerStatus{ContainerID: "1", Name: "migrate", State: "running"},
+		&containers.ContainerStatus{ContainerID: "2", Name: "app", State: "running"},
+	)
+	mu.Unlock()
+
+	select {
+	case req := <-requests:
+		assert.Equal(t, "1", req.ContainerID)
+		assert.Equal(t, int64(11), req.Since, "should continue after the last line")
+	case <-time.After(3 * podLogsPollInterval):
+		t.Fatal("PodLogs didn't open the restarted container logs")
+	}
+
+	select {
+	case err := <-done:
+		t.Fatalf("PodLogs returned before the context was cancelled: %v", err)
+	default:
+	}
+
+	cancel()
+	select {
+	case err := <-done:
+		assert.NoError(t, err)
+	case <-time.After(time.Second):
+		t.Fatal("PodLogs didn't return when the context was cancelled")
+	}
+	assert.Contains(t, out.String(), "[migrate] "+formatLogTime(10)+" migrated\n")
+	assert.Contains(t, out.String(), "[app] "+formatLogTime(20)+" started\n")
+}
+
+func TestFollowPodContainerLogsAcrossRestarts(t *testing.T) {
+	var mu sync.Mutex
+	pod := newLogsTestPod(&containers.ContainerStatus{ContainerID: "1", Name: "app", State: "running"})
+	getPod := func() *pods.Pod {
+		mu.Lock()
+		defer mu.Unlock()
+		return pod
+	}
+	setPod := func(next *pods.Pod) {
+		mu.Lock()
+		defer mu.Unlock()
+		pod = next
+	}
+
+	client, stop := startFakeLogsServer(t, getPod, func(req *containers.LogsRequest, server containers.Containers_LogsServer) error {
+		switch req.ContainerID {
+		case "1":
+			// The container crashes and gets recreated with new ID
+			setPod(newLogsTestPod(&containers.ContainerStatus{ContainerID: "2", Name: "app", State: "running", RestartCount: 1}))
+			return sendLogLine(server, 10, "first run\n")
+		default:
+			assert.Equal(t, int64(0), req.Since, "should read all lines of the new container")
+			setPod(nil)
+			return sendLogLine(server, 20, "second run\n")
+		}
+	})
+	defer stop()
+
+	out := &syncBuffer{}
+	done := make(chan error)
+	go func() {
+		done <- client.FollowPodContainerLogs(context.Background(), "foo", "app", out)
+	}()
+
+	select {
+	case err := <-done:
+		assert.NoError(t, err, "should return when the pod gets deleted")
+	case <-time.After(5 * podLogsPollInterval):
+		t.Fatal("FollowPodContainerLogs didn't return after the pod got deleted")
+	}
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	assert.Len(t, lines, 3)
+	assert.Equal(t, formatLogTime(10)+" first run", lines[0])
+	assert.True(t, strings.HasSuffix(lines[1], " "+LogRestartMarker), "should mark the restart")
+	assert.Equal(t, formatLogTime(20)+" second run", lines[2])
+}
@@ -0,0 +1,85 @@
+package api
+
+import (
+	"sync"
+
+	"golang.org/x/net/context"
+
+	containers "github.com/ernoaapa/eliot/pkg/api/services/containers/v1"
+)
+
+// GetPodMetrics return the current CPU, memory and block IO usage of the pod containers and the pod total.
+// The stats of the running containers are fetched concurrently. Containers which are not running,
+// or stop before their stats get fetched, are included with zero stats.
+// Returns ErrPodNotFound if the pod doesn't exist.
+func (c *Client) GetPodMetrics(ctx context.Context, podName string) (*PodMetrics, error) {
+	pod, err := c.GetPod(ctx, podName)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &PodMetrics{Pod: podName, Containers: []ContainerMetrics{}}
+	for _, status := range pod.GetStatus().GetInitContainerStatuses() {
+		result.Containers = append(result.Containers, newContainerMetrics(status, true))
+	}
+	for _, status := range pod.GetStatus().GetContainerStatuses() {
+		result.Containers = append(result.Containers, newContainerMetrics(status, false))
+	}
+
+	var (
+		mu       sync.Mutex
+		wg       sync.WaitGroup
+		statsErr error
+	)
+	for i := range result.Containers {
+		if !result.Containers[i].Running {
+			continue
+		}
+		wg.Add(1)
+		go func(container *ContainerMetrics) {
+			defer wg.Done()
+			stats, err := c.ContainerStats(ctx, container.ID)
+
+			mu.Lock()
+			defer mu.Unlock()
+			switch {
+			case err == nil:
+				container.Stats = *stats
+			case isContainerGone(err):
+				container.Running = false
+			default:
+				statsErr = err
+			}
+		}(&result.Containers[i])
+	}
+	wg.Wait()
+	if statsErr != nil {
+		return nil, statsErr
+	}
+
+	for _, container := range result.Containers {
+		result.Total.add(container.Stats)
+	}
+	return result, nil
+}
+
+func newContainerMetrics(status *containers.ContainerStatus, init bool) ContainerMetrics {
+	return ContainerMetrics{
+		ID:      status.GetContainerID(),
+		Name:    status.GetName(),
+		Init:    init,
+		Running: MapContainerState(status).Running,
+	}
+}
+
+// add sums the other stats to the stats and keeps the latest time
+func (s *Stats) add(other Stats) {
+	if other.Time.After(s.Time) {
+		s.Time = other.Time
+	}
+	s.CPUNanoseconds += other.CPUNanoseconds
+	s.MemoryUsageBytes += other.MemoryUsageBytes
+	s.MemoryLimitBytes += other.MemoryLimitBytes
+	s.BlkioReadBytes += other.BlkioReadBytes
+	s.BlkioWriteBytes += other.BlkioWriteBytes
+}
@@ -0,0 +1,76 @@
+package api
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	core "github.com/ernoaapa/eliot/pkg/api/core"
+	containers "github.com/ernoaapa/eliot/pkg/api/services/containers/v1"
+	pods "github.com/ernoaapa/eliot/pkg/api/services/pods/v1"
+)
+
+func TestGetPodMetrics(t *testing.T) {
+	podsServer := &fakePodsServer{list: func(req *pods.ListPodsRequest) (*pods.ListPodsResponse, error) {
+		return &pods.ListPodsResponse{Pods: []*pods.Pod{{
+			Metadata: &core.ResourceMetadata{Name: "web"},
+			Status: &pods.PodStatus{
+				InitContainerStatuses: []*containers.ContainerStatus{
+					{ContainerID: "web-migrate", Name: "migrate", State: "stopped"},
+				},
+				ContainerStatuses: []*containers.ContainerStatus{
+					{ContainerID: "web-nginx", Name: "nginx", State: "running"},
+					{ContainerID: "web-sidecar", Name: "sidecar", State: "running"},
+					{ContainerID: "web-exited", Name: "exited", State: "running"},
+				},
+			},
+		}}}, nil
+	}}
+	requested := make(chan string, 4)
+	containersServer := &fakeContainersServer{stats: func(req *containers.StatsRequest) (*containers.StatsResponse, error) {
+		requested <- req.ContainerID
+		switch req.ContainerID {
+		case "web-nginx":
+			return &containers.StatsResponse{Stats: &containers.ContainerStats{Time: 100, CpuNanoseconds: 10, MemoryUsageBytes: 1000, MemoryLimitBytes: 4000}}, nil
+		case "web-sidecar":
+			return &containers.StatsResponse{Stats: &containers.ContainerStats{Time: 200, CpuNanoseconds: 5, MemoryUsageBytes: 500, BlkioReadBytes: 7}}, nil
+		}
+		return nil, status.Error(codes.FailedPrecondition, "Container is not running")
+	}}
+
+	client, stop := startFakeServer(t, podsServer, containersServer)
+	defer stop()
+
+	metrics, err := client.GetPodMetrics(context.Background(), "web")
+	assert.NoError(t, err)
+	assert.Len(t, requested, 3, "should not fetch stats of stopped containers")
+
+	assert.Equal(t, "web", metrics.Pod)
+	assert.Equal(t, uint64(15), metrics.Total.CPUNanoseconds)
+	assert.Equal(t, uint64(1500), metrics.Total.MemoryUsageBytes)
+	assert.Equal(t, uint64(4000), metrics.Total.MemoryLimitBytes)
+	assert.Equal(t, uint64(7), metrics.Total.BlkioReadBytes)
+	assert.Equal(t, int64(200), metrics.Total.Time.UnixNano())
+
+	assert.Len(t, metrics.Containers, 4)
+	assert.Equal(t, ContainerMetrics{ID: "web-migrate", Name: "migrate", Init: true}, metrics.Containers[0])
+	assert.Equal(t, "nginx", metrics.Containers[1].Name)
+	assert.True(t, metrics.Containers[1].Running)
+	assert.Equal(t, uint64(1000), metrics.Containers[1].Stats.MemoryUsageBytes)
+	assert.Equal(t, ContainerMetrics{ID: "web-exited", Name: "exited"}, metrics.Containers[3], "container which exited meanwhile should have zero stats")
+}
+
+func TestGetPodMetricsReturnsPodNotFound(t *testing.T) {
+	podsServer := &fakePodsServer{list: func(req *pods.ListPodsRequest) (*pods.ListPodsResponse, error) {
+		return &pods.ListPodsResponse{Pods: []*pods.Pod{}}, nil
+	}}
+	client, stop := startFakeServer(t, podsServer, nil)
+	defer stop()
+
+	_, err := client.GetPodMetrics(context.Background(), "missing")
+	assert.True(t, errors.Is(err, ErrPodNotFound), "should return ErrPodNotFound, got: %v", err)
+}
@@ -0,0 +1,315 @@
+package api
+
+import (
+	"sync"
+	"time"
+
+	"golang.org/x/net/context"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/connectivity"
+
+	"github.com/ernoaapa/eliot/pkg/config"
+)
+
+// connectionPool holds the connections to the servers, keyed by the server address.
+// The pool is shared between the clients created with WithNamespace.
+type connectionPool struct {
+	mu      sync.Mutex
+	conns   map[string][]*pooledConn
+	maxSize int
+	// idleTimeout is how long connection can be unused before it's closed, zero means never
+	idleTimeout time.Duration
+	// active is the index of the server in use, when there's multiple servers
+	active int
+	// dialing is the number of dials in progress per server address, the dials are done without holding the lock
+	dialing map[string]int
+	// dialed gets signaled when dial completes
+	dialed *sync.Cond
+}
+
+// pooledConn is single connection in the pool
+type pooledConn struct {
+	conn      *grpc.ClientConn
+	address   string
+	inFlight  int
+	lastUsed  time.Time
+	discarded bool
+	closed    bool
+}
+
+func (pc *pooledConn) close() error {
+	if pc.closed {
+		return nil
+	}
+	pc.closed = true
+	return pc.conn.Close()
+}
+
+// PoolStats is the number of open connections in the client connection pool
+type PoolStats struct {
+	// Active is the number of connections which have calls in progress
+	Active int
+	// Idle is the number of connections without calls in progress
+	Idle int
+}
+
+func newConnectionPool() *connectionPool {
+	pool := &connectionPool{
+		conns:   map[string][]*pooledConn{},
+		maxSize: 1,
+		dialing: map[string]int{},
+	}
+	pool.dialed = sync.NewCond(&pool.mu)
+	return pool
+}
+
+// acquireConnection return connection to the active server for a call, and dials new one if all
+// connections are busy and the pool is not full. Call releaseConnection when the call is done.
+// The dial is done without holding the pool lock so that slow server doesn't block the other calls.
+func (c *Client) acquireConnection() (*pooledConn, error) {
+	c.pool.mu.Lock()
+	defer c.pool.mu.Unlock()
+
+	for {
+		c.pool.evict(time.Now())
+
+		address := c.servers[c.pool.active].GetAddress()
+		pc := c.pool.leastBusy(address)
+		if pc != nil && (pc.inFlight == 0 || len(c.pool.conns[address])+c.pool.dialing[address] >= c.pool.maxSize) {
+			return c.pool.use(pc), nil
+		}
+		if pc == nil && c.pool.dialing[address] > 0 {
+			// Wait the dial in progress rather than open many connections to the same server at once
+			c.pool.dialed.Wait()
+			continue
+		}
+
+		c.pool.dialing[address]++
+		c.pool.mu.Unlock()
+		conn, err := c.dial()
+		c.pool.mu.Lock()
+		c.pool.dialing[address]--
+		if c.pool.dialing[address] == 0 {
+			delete(c.pool.dialing, address)
+		}
+		c.pool.dialed.Broadcast()
+
+		if err == nil {
+			// with failover, the dial can connect to another than the active server
+			pc = &pooledConn{conn: conn, address: c.servers[c.pool.active].GetAddress()}
+			c.pool.conns[pc.address] = append(c.pool.conns[pc.address], pc)
+			if c.connStateHandler != nil {
+				go watchConnState(conn, c.connStateHandler)
+			}
+			return c.pool.use(pc), nil
+		}
+		if pc = c.pool.leastBusy(address); pc == nil {
+			return nil, err
+		}
+		c.logger.Debugf("Failed to open new connection to the pool, use existing connection: %s", err)
+		return c.pool.use(pc), nil
+	}
+}
+
+// use marks the connection to have one more call in progress.
+// Must be called while holding the pool lock.
+func (p *connectionPool) use(pc *pooledConn) *pooledConn {
+	pc.inFlight++
+	pc.lastUsed = time.Now()
+	return pc
+}
+
+// releaseConnection marks the call done, and closes the connection if it was discarded during the call
+func (c *Client) releaseConnection(pc *pooledConn) {
+	c.pool.mu.Lock()
+	defer c.pool.mu.Unlock()
+
+	pc.inFlight--
+	pc.lastUsed = time.Now()
+	if pc.discarded && pc.inFlight == 0 {
+		pc.close()
+	}
+}
+
+// ActiveEndpoint return the server which the client is connected to, or connects on the next call.
+// Without WithServers it's always the Endpoint given to NewClient.
+func (c *Client) ActiveEndpoint() config.Endpoint {
+	c.pool.mu.Lock()
+	defer c.pool.mu.Unlock()
+	return c.servers[c.pool.active]
+}
+
+// PoolStats return the number of active and idle connections in the pool
+func (c *Client) PoolStats() PoolStats {
+	c.pool.mu.Lock()
+	defer c.pool.mu.Unlock()
+
+	stats := PoolStats{}
+	for _, conns := range c.pool.conns {
+		for _, pc := range conns {
+			if pc.inFlight > 0 {
+				stats.Active++
+			} else {
+				stats.Idle++
+			}
+		}
+	}
+	return stats
+}
+
+// leastBusy return the connection to the address which has least calls in progress, nil if there's none.
+// Must be called while holding the pool lock.
+func (p *connectionPool) leastBusy(address string) (result *pooledConn) {
+	for _, pc := range p.conns[address] {
+		if result == nil || pc.inFlight < result.inFlight {
+			result = pc
+		}
+	}
+	return result
+}
+
+// evict discards the connections which have failed or have been idle longer than the idle timeout.
+// Must be called while holding the pool lock.
+func (p *connectionPool) evict(now time.Time) {
+	for address, conns := range p.conns {
+		remaining := []*pooledConn{}
+		for _, pc := range conns {
+			state := pc.conn.GetState()
+			switch {
+			case state == connectivity.TransientFailure || state == connectivity.Shutdown:
+				p.discard(pc)
+			case p.idleTimeout > 0 && pc.inFlight == 0 && now.Sub(pc.lastUsed) > p.idleTimeout:
+				p.discard(pc)
+			default:
+				remaining = append(remaining, pc)
+			}
+		}
+		p.setConns(address, remaining)
+	}
+}
+
+// discardAddress discards all connections to the address.
+// Must be called while holding the pool lock.
+func (p *connectionPool) discardAddress(address string) {
+	for _, pc := range p.conns[address] {
+		p.discard(pc)
+	}
+	delete(p.conns, address)
+}
+
+// discard closes the connection, or marks it to be closed when the calls in progress are done
+func (p *connectionPool) discard(pc *pooledConn) {
+	pc.discarded = true
+	if pc.inFlight == 0 {
+		pc.close()
+	}
+}
+
+func (p *connectionPool) setConns(address string, conns []*pooledConn) {
+	if len(conns) == 0 {
+		delete(p.conns, address)
+		return
+	}
+	p.conns[address] = conns
+}
+
+// closeAll closes all connections, the calls in progress get cancelled
+func (p *connectionPool) closeAll() (err error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	for _, conns := range p.conns {
+		for _, pc := range conns {
+			pc.discarded = true
+			if closeErr := pc.close(); closeErr != nil && err == nil {
+				err = closeErr
+			}
+		}
+	}
+	p.conns = map[string][]*pooledConn{}
+	return err
+}
+
+// invokeUnary runs the call through connection from the pool, and handles the failover if there's multiple servers
+func (c *Client) invokeUnary(ctx context.Context, invoke func(conn *grpc.ClientConn) error) error {
+	if len(c.servers) > 1 {
+		return c.failoverUnaryInterceptor(ctx, invoke)
+	}
+
+	pc, err := c.acquireConnection()
+	if err != nil {
+		return err
+	}
+	defer c.releaseConnection(pc)
+	return invoke(pc.conn)
+}
+
+// openStream opens the stream through connection from the pool, and handles the failover if there's multiple servers.
+// The connection is released when the stream ends or its context get cancelled.
+func (c *Client) openStream(ctx context.Context, open func(conn *grpc.ClientConn) (grpc.ClientStream, error)) (grpc.ClientStream, error) {
+	if len(c.servers) > 1 {
+		return c.failoverStreamInterceptor(ctx, open)
+	}
+
+	pc, err := c.acquireConnection()
+	if err != nil {
+		return nil, err
+	}
+	stream, err := open(pc.conn)
+	if err != nil {
+		c.releaseConnection(pc)
+		return nil, err
+	}
+	return newPooledStream(ctx, stream, func() { c.releaseConnection(pc) }), nil
+}
+
+// pooledStream releases the pooled connection when the stream ends or its context get cancelled
+type pooledStream struct {
+	grpc.ClientStream
+	once    sync.Once
+	done    chan struct{}
+	release func()
+}
+
+func newPooledStream(ctx context.Context, stream grpc.ClientStream, release func()) *pooledStream {
+	s := &pooledStream{
+		ClientStream: stream,
+		done:         make(chan struct{}),
+		release:      release,
+	}
+	go func() {
+		select {
+		case <-ctx.Done():
+			s.finish()
+		case <-s.done:
+		}
+	}()
+	return s
+}
+
+func (s *pooledStream) RecvMsg(m interface{}) error {
+	err := s.ClientStream.RecvMsg(m)
+	if err != nil {
+		s.finish()
+	}
+	return connectionLost(err)
+}
+
+func (s *pooledStream) finish() {
+	s.once.Do(func() {
+		close(s.done)
+		s.release()
+	})
+}
+
+// watchConnState calls the handler with the connection state and every change of it, until the connection get closed
+func watchConnState(conn *grpc.ClientConn, handler func(connectivity.State)) {
+	for {
+		state := conn.GetState()
+		handler(state)
+		if state == connectivity.Shutdown {
+			return
+		}
+		conn.WaitForStateChange(context.Background(), state)
+	}
+}
@@ -0,0 +1,221 @@
+package api
+
+import (
+	"errors"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/connectivity"
+	"google.golang.org/grpc/keepalive"
+	"google.golang.org/grpc/status"
+
+	"github.com/ernoaapa/eliot/pkg/config"
+)
+
+func TestConnectionPoolConcurrentCalls(t *testing.T) {
+	address, stop := startFakeNodeServer(t, "node")
+	defer stop()
+
+	client, err := NewClient("eliot", config.Endpoint{Name: "node", URL: address}, WithInsecure(), WithConnectionPool(3, 0))
+	assert.NoError(t, err)
+	defer client.Close()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := client.GetInfo(context.Background())
+			assert.NoError(t, err)
+		}()
+	}
+	wg.Wait()
+
+	stats := client.PoolStats()
+	assert.Equal(t, 0, stats.Active, "should release all connections")
+	assert.True(t, stats.Idle >= 1 && stats.Idle <= 3, "should open at most max size connections, but opened %d", stats.Idle)
+}
+
+func TestConnectionPoolEvictsIdleConnections(t *testing.T) {
+	address, stop := startFakeNodeServer(t, "node")
+	defer stop()
+
+	client, err := NewClient("eliot", config.Endpoint{Name: "node", URL: address}, WithInsecure(), WithConnectionPool(1, 10*time.Millisecond))
+	assert.NoError(t, err)
+	defer client.Close()
+
+	_, err = client.GetInfo(context.Background())
+	assert.NoError(t, err)
+	first := client.pool.conns[address][0]
+
+	time.Sleep(50 * time.Millisecond)
+
+	_, err = client.GetInfo(context.Background())
+	assert.NoError(t, err)
+	assert.True(t, first.closed, "should close the idle connection")
+	assert.Equal(t, PoolStats{Idle: 1}, client.PoolStats())
+}
+
+func TestConnectionPoolDiscardsFailedConnections(t *testing.T) {
+	conn, err := grpc.Dial(getUnusedAddress(t), grpc.WithInsecure())
+	assert.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	for state := conn.GetState(); state != connectivity.TransientFailure; state = conn.GetState() {
+		if !conn.WaitForStateChange(ctx, state) {
+			t.Fatal("Connection didn't go to TransientFailure state")
+		}
+	}
+
+	pool := newConnectionPool()
+	busy := &pooledConn{conn: conn, address: "foo", inFlight: 1}
+	pool.conns["foo"] = []*pooledConn{busy}
+
+	pool.evict(time.Now())
+	assert.Empty(t, pool.conns, "should remove failed connection from the pool")
+	assert.False(t, busy.closed, "should not close connection which has calls in progress")
+
+	busy.inFlight = 0
+	pool.discard(busy)
+	assert.True(t, busy.closed)
+}
+
+func TestConnectionPoolDialsWithoutHoldingLock(t *testing.T) {
+	address, stop := startFakeNodeServer(t, "node")
+	defer stop()
+
+	dialing := make(chan struct{})
+	release := make(chan struct{})
+	var once sync.Once
+	dialer := grpc.WithDialer(func(addr string, timeout time.Duration) (net.Conn, error) {
+		once.Do(func() { close(dialing) })
+		<-release
+		return net.DialTimeout("tcp", addr, timeout)
+	})
+
+	client, err := NewClient("eliot", config.Endpoint{Name: "node", URL: address}, WithInsecure(), WithDialTimeout(5*time.Second), WithDialOptions(dialer))
+	assert.NoError(t, err)
+	defer client.Close()
+
+	errc := make(chan error, 2)
+	for i := 0; i < 2; i++ {
+		go func() {
+			_, err := client.GetInfo(context.Background())
+			errc <- err
+		}()
+	}
+	<-dialing
+
+	stats := make(chan PoolStats)
+	go func() { stats <- client.PoolStats() }()
+	select {
+	case s := <-stats:
+		assert.Equal(t, PoolStats{}, s, "should not have connections while the dial is in progress")
+	case <-time.After(time.Second):
+		t.Fatal("PoolStats blocked while the connection was dialed")
+	}
+
+	close(release)
+	assert.NoError(t, <-errc)
+	assert.NoError(t, <-errc)
+	assert.Equal(t, PoolStats{Idle: 1}, client.PoolStats(), "should wait the dial in progress instead of dialing again")
+}
+
+func TestPooledStreamReleasesOnContextCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	released := make(chan struct{})
+	newPooledStream(ctx, nil, func() { close(released) })
+
+	cancel()
+	select {
+	case <-released:
+	case <-time.After(time.Second):
+		t.Fatal("Stream didn't release the connection when the context got cancelled")
+	}
+}
+
+func TestWithConnectionPoolValidates(t *testing.T) {
+	_, err := NewClient("eliot", config.Endpoint{Name: "node", URL: "localhost:5000"}, WithConnectionPool(0, 0))
+	assert.Error(t, err)
+
+	_, err = NewClient("eliot", config.Endpoint{Name: "node", URL: "localhost:5000"}, WithConnectionPool(1, -time.Second))
+	assert.Error(t, err)
+}
+
+func TestWithKeepaliveValidates(t *testing.T) {
+	endpoint := config.Endpoint{Name: "node", URL: "localhost:5000"}
+
+	client, err := NewClient("eliot", endpoint, WithKeepalive(keepalive.ClientParameters{Time: time.Minute, Timeout: 5 * time.Second}))
+	assert.NoError(t, err)
+	assert.Equal(t, time.Minute, client.keepalive.Time)
+
+	_, err = NewClient("eliot", endpoint, WithKeepalive(keepalive.ClientParameters{Time: time.Second, Timeout: time.Second}))
+	assert.Error(t, err, "server doesn't allow pings more often than keepaliveMinTime")
+
+	_, err = NewClient("eliot", endpoint, WithKeepalive(keepalive.ClientParameters{Time: time.Minute}))
+	assert.Error(t, err)
+}
+
+// brokenStream is stream whose connection has been closed
+type brokenStream struct {
+	grpc.ClientStream
+}
+
+func (s *brokenStream) RecvMsg(m interface{}) error {
+	return status.Error(codes.Unavailable, "transport is closing")
+}
+
+func TestPooledStreamReturnsConnectionLost(t *testing.T) {
+	s := newPooledStream(context.Background(), &brokenStream{}, func() {})
+
+	err := translateError(s.RecvMsg(nil))
+	assert.True(t, errors.Is(err, ErrConnectionLost), "expected connection lost, but got %v", err)
+	assert.True(t, errors.Is(err, ErrUnavailable))
+	assert.Equal(t, "connection lost: transport is closing", err.Error())
+}
+
+func TestConnStateHandlerReportsChanges(t *testing.T) {
+	address, stop := startFakeNodeServer(t, "node")
+
+	states := make(chan connectivity.State, 100)
+	client, err := NewClient("eliot", config.Endpoint{Name: "node", URL: address}, WithInsecure(), WithConnStateHandler(func(state connectivity.State) {
+		states <- state
+	}))
+	assert.NoError(t, err)
+
+	_, err = client.GetInfo(context.Background())
+	assert.NoError(t, err)
+	waitConnState(t, states, connectivity.Ready)
+
+	stop()
+	waitConnState(t, states, connectivity.TransientFailure)
+
+	client.Close()
+	waitConnState(t, states, connectivity.Shutdown)
+}
+
+func TestWithConnStateHandlerValidates(t *testing.T) {
+	_, err := NewClient("eliot", config.Endpoint{Name: "node", URL: "localhost:5000"}, WithConnStateHandler(nil))
+	assert.Error(t, err)
+}
+
+func waitConnState(t *testing.T, states <-chan connectivity.State, expected connectivity.State) {
+	timeout := time.After(5 * time.Second)
+	for {
+		select {
+		case state := <-states:
+			if state == expected {
+				return
+			}
+		case <-timeout:
+			t.Fatalf("Connection state handler wasn't called with %s", expected)
+		}
+	}
+}
@@ -0,0 +1,384 @@
+package api
+
+import (
+	"fmt"
+	"io"
+	"net"
+	"sync"
+
+	containers "github.com/ernoaapa/eliot/pkg/api/services/containers/v1"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc/codes"
+)
+
+// portForwardChunkSize is the max size of data sent in single PortForward message
+const portForwardChunkSize = 32 * 1024
+
+// PortForward listens the local address and forwards every accepted connection to the port in the container,
+// like `kubectl port-forward`. All connections are multiplexed over single stream to the server.
+// Returns when the context get cancelled or the stream to the server fails, all forwarded connections get closed.
+func (c *Client) PortForward(ctx context.Context, containerID string, localAddr string, remotePort int) error {
+	if err := validatePort(remotePort); err != nil {
+		return err
+	}
+
+	listener, err := net.Listen("tcp", localAddr)
+	if err != nil {
+		return errors.Wrapf(err, "Failed to listen local address [%s]", localAddr)
+	}
+	return c.PortForwardListener(ctx, containerID, listener, remotePort)
+}
+
+// PortForwardListener forwards connections accepted by the listener to the port in the container, see PortForward.
+// Use it e.g. to forward from random local port, the listener gets closed when the forwarding ends.
+func (c *Client) PortForwardListener(ctx context.Context, containerID string, listener net.Listener, remotePort int) error {
+	defer listener.Close()
+
+	if err := validatePort(remotePort); err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	conn, err := c.getConnection()
+	if err != nil {
+		return err
+	}
+
+	client := containers.NewContainersClient(conn)
+	s, err := client.PortForward(ctx)
+	if err != nil {
+		return translateError(err)
+	}
+
+	var sendMu sync.Mutex
+	send := func(req *containers.PortForwardRequest) error {
+		sendMu.Lock()
+		defer sendMu.Unlock()
+		return s.Send(req)
+	}
+
+	if err := send(&containers.PortForwardRequest{
+		Namespace:   c.Namespace,
+		ContainerID: containerID,
+		Port:        uint32(remotePort),
+	}); err != nil {
+		return translateError(err)
+	}
+
+	conns := newPortForwardConns()
+	defer conns.closeAll()
+
+	acceptc := make(chan error, 1)
+	go func() {
+		var next uint32
+		for {
+			local, err := listener.Accept()
+			if err != nil {
+				acceptc <- err
+				return
+			}
+			next++
+			id := next
+			c.logger.Debugf("Handling connection [%d] from [%s] for port %d", id, local.RemoteAddr(), remotePort)
+			conns.open(id)
+			conns.add(id, local)
+			if err := send(&containers.PortForwardRequest{Connection: id}); err != nil {
+				conns.close(id)
+				continue
+			}
+			go conns.pipe(id, func(data []byte, closed bool, _ error) error {
+				return send(&containers.PortForwardRequest{Connection: id, Data: data, Close: closed})
+			})
+		}
+	}()
+
+	recvc := make(chan error, 1)
+	go func() {
+		for {
+			resp, err := s.Recv()
+			if err != nil {
+				recvc <- err
+				return
+			}
+			if resp.Error != "" {
+				c.logger.Warnf("Connection [%d] to port %d in container [%s] failed: %s", resp.Connection, remotePort, containerID, resp.Error)
+				conns.close(resp.Connection)
+				continue
+			}
+			conns.write(resp.Connection, resp.Data, resp.Close)
+		}
+	}()
+
+	select {
+	case err := <-recvc:
+		if err == io.EOF {
+			return nil
+		}
+		return translateError(err)
+	case err := <-acceptc:
+		return errors.Wrapf(err, "Failed to accept connections in [%s]", listener.Addr())
+	case <-ctx.Done():
+		return translateError(ctx.Err())
+	}
+}
+
+// servePortForward reads the stream from the client and proxies the connections to the ports dialed with the dial function.
+// Returns when the client closes the stream, the connections to the ports get closed.
+func servePortForward(server containers.Containers_PortForwardServer, dial func() (net.Conn, error)) error {
+	var sendMu sync.Mutex
+	send := func(resp *containers.PortForwardResponse) error {
+		sendMu.Lock()
+		defer sendMu.Unlock()
+		return server.Send(resp)
+	}
+
+	conns := newPortForwardConns()
+	defer conns.closeAll()
+
+	for {
+		req, err := server.Recv()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		id := req.Connection
+		switch {
+		case id == 0:
+			// Message without connection is the stream header
+		case len(req.Data) == 0 && !req.Close:
+			// Dial in the background so that connection which takes time doesn't block the others
+			conns.open(id)
+			go func() {
+				conn, err := dial()
+				if err != nil {
+					log.Debugf("Failed to open port forward connection [%d]: %s", id, err)
+					conns.close(id)
+					send(&containers.PortForwardResponse{Connection: id, Close: true, Error: err.Error()})
+					return
+				}
+				if !conns.add(id, conn) {
+					return
+				}
+				conns.pipe(id, func(data []byte, closed bool, err error) error {
+					resp := &containers.PortForwardResponse{Connection: id, Data: data, Close: closed}
+					if err != nil {
+						resp.Error = err.Error()
+					}
+					return send(resp)
+				})
+			}()
+		default:
+			conns.write(id, req.Data, req.Close)
+		}
+	}
+}
+
+// portForwardConns tracks the connections multiplexed over single PortForward stream
+type portForwardConns struct {
+	mu    sync.Mutex
+	conns map[uint32]*portForwardConn
+}
+
+type portForwardConn struct {
+	// conn is nil while the connection is being opened
+	conn net.Conn
+	// queue holds the data received from the stream but not yet written to the connection
+	queue []pendingWrite
+	// writing is true while goroutine writes the queue to the connection
+	writing   bool
+	readDone  bool
+	writeDone bool
+}
+
+type pendingWrite struct {
+	data   []byte
+	closed bool
+}
+
+func newPortForwardConns() *portForwardConns {
+	return &portForwardConns{
+		conns: map[uint32]*portForwardConn{},
+	}
+}
+
+// open reserves the connection id so that the data received while the connection is being opened
+// gets written once the connection is added
+func (p *portForwardConns) open(id uint32) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.conns[id] = &portForwardConn{}
+}
+
+// add sets the opened connection and starts writing the data received while it was being opened.
+// Returns false and closes the connection if it got closed while opening.
+func (p *portForwardConns) add(id uint32, conn net.Conn) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	entry := p.conns[id]
+	if entry == nil {
+		conn.Close()
+		return false
+	}
+	entry.conn = conn
+	p.startWriting(id, entry)
+	return true
+}
+
+// write queues the data received from the stream to be written to the connection.
+// The write happens in the background so that slow connection doesn't block the stream, and the others with it.
+// If closed is true, the other end has no more data to send and the connection write side gets closed.
+// Data to unknown connection is ignored, the connection has been closed already.
+func (p *portForwardConns) write(id uint32, data []byte, closed bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	entry := p.conns[id]
+	if entry == nil {
+		return
+	}
+	entry.queue = append(entry.queue, pendingWrite{data: data, closed: closed})
+	p.startWriting(id, entry)
+}
+
+// startWriting starts goroutine to write the queue to the connection unless it's being opened or written already.
+// Must be called while holding the lock.
+func (p *portForwardConns) startWriting(id uint32, entry *portForwardConn) {
+	if entry.conn == nil || entry.writing || len(entry.queue) == 0 {
+		return
+	}
+	entry.writing = true
+	go p.drain(id, entry)
+}
+
+// drain writes the queue to the connection in order until the queue is empty or the connection is closed
+func (p *portForwardConns) drain(id uint32, entry *portForwardConn) {
+	for {
+		p.mu.Lock()
+		if len(entry.queue) == 0 || p.conns[id] != entry {
+			entry.writing = false
+			p.mu.Unlock()
+			return
+		}
+		w := entry.queue[0]
+		entry.queue = entry.queue[1:]
+		p.mu.Unlock()
+
+		if len(w.data) > 0 {
+			if _, err := entry.conn.Write(w.data); err != nil {
+				p.close(id)
+				continue
+			}
+		}
+		if w.closed {
+			p.finishWrite(id)
+		}
+	}
+}
+
+// pipe reads the connection and sends the data with the send function until the connection closes
+func (p *portForwardConns) pipe(id uint32, send func(data []byte, closed bool, err error) error) {
+	p.mu.Lock()
+	var conn net.Conn
+	if entry := p.conns[id]; entry != nil {
+		conn = entry.conn
+	}
+	p.mu.Unlock()
+	if conn == nil {
+		return
+	}
+
+	buf := make([]byte, portForwardChunkSize)
+	for {
+		n, err := conn.Read(buf)
+		if n > 0 {
+			data := make([]byte, n)
+			copy(data, buf[:n])
+			if sendErr := send(data, false, nil); sendErr != nil {
+				p.close(id)
+				return
+			}
+		}
+		if err != nil {
+			if err == io.EOF || !p.isOpen(id) {
+				err = nil
+			}
+			send(nil, true, err)
+			p.finishRead(id)
+			return
+		}
+	}
+}
+
+func (p *portForwardConns) isOpen(id uint32) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	_, ok := p.conns[id]
+	return ok
+}
+
+// finishWrite closes the connection write side, and the whole connection if the read side is done too
+func (p *portForwardConns) finishWrite(id uint32) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	entry := p.conns[id]
+	if entry == nil {
+		return
+	}
+	entry.writeDone = true
+	if entry.readDone {
+		p.remove(id)
+		return
+	}
+	if cw, ok := entry.conn.(interface{ CloseWrite() error }); ok {
+		cw.CloseWrite()
+	}
+}
+
+// finishRead marks the connection read side done, and closes the connection if the write side is done too
+func (p *portForwardConns) finishRead(id uint32) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	entry := p.conns[id]
+	if entry == nil {
+		return
+	}
+	entry.readDone = true
+	if entry.writeDone {
+		p.remove(id)
+	}
+}
+
+func (p *portForwardConns) close(id uint32) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.remove(id)
+}
+
+func (p *portForwardConns) closeAll() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for id := range p.conns {
+		p.remove(id)
+	}
+}
+
+// remove closes the connection, must be called while holding the lock
+func (p *portForwardConns) remove(id uint32) {
+	if entry := p.conns[id]; entry != nil && entry.conn != nil {
+		entry.conn.Close()
+	}
+	delete(p.conns, id)
+}
+
+func validatePort(port int) error {
+	if port < 1 || port > 65535 {
+		return &Error{Code: codes.InvalidArgument, Message: fmt.Sprintf("Invalid port [%d], must be between 1 and 65535", port)}
+	}
+	return nil
+}
@@ -0,0 +1,169 @@
+package api
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/ernoaapa/eliot/pkg/config"
+	"github.com/ernoaapa/eliot/pkg/runtime"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/net/context"
+)
+
+// portRuntime is runtime which connects the container ports to the given address
+type portRuntime struct {
+	runtime.Client
+	addr string
+}
+
+func (r *portRuntime) DialContainer(namespace, name string, port int) (net.Conn, error) {
+	if port != 8080 {
+		return nil, fmt.Errorf("connection refused")
+	}
+	return net.Dial("tcp", r.addr)
+}
+
+// startEchoServer starts server which writes back every line prefixed with "echo: "
+func startEchoServer(t *testing.T) net.Listener {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer conn.Close()
+				scanner := bufio.NewScanner(conn)
+				for scanner.Scan() {
+					fmt.Fprintf(conn, "echo: %s\n", scanner.Text())
+				}
+			}()
+		}
+	}()
+	return listener
+}
+
+func startPortForward(t *testing.T, remotePort int) (net.Addr, context.CancelFunc, <-chan error) {
+	echo := startEchoServer(t)
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+	server := NewServer(listener.Addr().String(), &portRuntime{addr: echo.Addr().String()}, nil)
+	go server.grpc.Serve(listener)
+
+	client, err := NewClient("eliot", config.Endpoint{URL: listener.Addr().String()}, WithInsecure())
+	assert.NoError(t, err)
+
+	local, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() {
+		done <- client.PortForwardListener(ctx, "foo", local, remotePort)
+		client.Close()
+		server.grpc.Stop()
+		echo.Close()
+	}()
+	return local.Addr(), cancel, done
+}
+
+func TestPortForwardMultipleConnections(t *testing.T) {
+	addr, cancel, done := startPortForward(t, 8080)
+
+	first, err := net.Dial("tcp", addr.String())
+	assert.NoError(t, err)
+	second, err := net.Dial("tcp", addr.String())
+	assert.NoError(t, err)
+
+	fmt.Fprintln(first, "first")
+	fmt.Fprintln(second, "second")
+	fmt.Fprintln(first, "again")
+
+	firstReader := bufio.NewReader(first)
+	line, err := firstReader.ReadString('\n')
+	assert.NoError(t, err)
+	assert.Equal(t, "echo: first\n", line)
+	line, err = firstReader.ReadString('\n')
+	assert.NoError(t, err)
+	assert.Equal(t, "echo: again\n", line)
+
+	line, err = bufio.NewReader(second).ReadString('\n')
+	assert.NoError(t, err)
+	assert.Equal(t, "echo: second\n", line)
+
+	cancel()
+	select {
+	case err := <-done:
+		assert.Error(t, err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("PortForward didn't return when the context got cancelled")
+	}
+
+	first.SetReadDeadline(time.Now().Add(5 * time.Second))
+	_, err = firstReader.ReadString('\n')
+	assert.Equal(t, io.EOF, err, "local connection should get closed")
+}
+
+func TestPortForwardClosesConnectionWhenPortIsNotListening(t *testing.T) {
+	addr, cancel, _ := startPortForward(t, 9090)
+	defer cancel()
+
+	conn, err := net.Dial("tcp", addr.String())
+	assert.NoError(t, err)
+	defer conn.Close()
+
+	conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	_, err = conn.Read(make([]byte, 1))
+	assert.Equal(t, io.EOF, err)
+}
+
+func TestPortForwardValidatesPort(t *testing.T) {
+	client, err := NewClient("eliot", config.Endpoint{URL: "localhost:5000"}, WithInsecure())
+	assert.NoError(t, err)
+
+	err = client.PortForward(context.Background(), "foo", "127.0.0.1:0", 0)
+	assert.True(t, errors.Is(err, ErrInvalidArgument), "expected invalid argument, but got %v", err)
+}
+
+func TestPortForwardSlowConnectionDoesNotBlockOthers(t *testing.T) {
+	conns := newPortForwardConns()
+	defer conns.closeAll()
+
+	// Nobody reads the slow connection, so the write blocks until the connection get closed
+	slow, slowPeer := net.Pipe()
+	defer slowPeer.Close()
+	fast, fastPeer := net.Pipe()
+	defer fastPeer.Close()
+
+	conns.open(1)
+	conns.add(1, slow)
+	conns.open(2)
+	conns.add(2, fast)
+
+	written := make(chan struct{})
+	go func() {
+		conns.write(1, []byte("slow"), false)
+		conns.write(2, []byte("fast"), false)
+		close(written)
+	}()
+
+	select {
+	case <-written:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Write to slow connection blocked the writes to other connections")
+	}
+
+	fastPeer.SetReadDeadline(time.Now().Add(5 * time.Second))
+	buf := make([]byte, 4)
+	_, err := io.ReadFull(fastPeer, buf)
+	assert.NoError(t, err)
+	assert.Equal(t, "fast", string(buf))
+}
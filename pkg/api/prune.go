@@ -0,0 +1,97 @@
+package api
+
+import (
+	"time"
+
+	node "github.com/ernoaapa/eliot/pkg/api/services/node/v1"
+	"github.com/ernoaapa/eliot/pkg/model"
+	"github.com/ernoaapa/eliot/pkg/runtime"
+	"golang.org/x/net/context"
+)
+
+// pruneImageGracePeriod is how long unused images are kept after the pull even if PruneOptions KeepFor is shorter,
+// because the pod which pulled the image might still be getting created
+const pruneImageGracePeriod = 10 * time.Minute
+
+// PruneOptions defines what Prune removes
+type PruneOptions struct {
+	// KeepFor keeps the pods which finished and the images which were pulled within the duration
+	KeepFor time.Duration
+	// Selector limits the pruned pods to the ones matching the label selector, e.g. "app=migrate".
+	// See model.ParseSelector for the syntax. With selector, only the images of the pruned pods get removed.
+	Selector string
+}
+
+// PruneResult tells what Prune removed
+type PruneResult struct {
+	// Containers are the IDs of the removed containers
+	Containers []string
+	// Images are the names of the removed images
+	Images []string
+	// ReclaimedBytes is the approximate disk space freed, image layers shared with other images are counted in each image
+	ReclaimedBytes uint64
+}
+
+// Prune removes the finished pods and the images which no container use, to free disk space in the node.
+// Pod is finished when all its containers have stopped and won't be restarted by the restart policy,
+// so pods with running containers, or stopped containers which get restarted, are never removed, nor their images.
+// The finished pods are removed as whole, stopped container is never removed from pod which is still running.
+func (c *Client) Prune(ctx context.Context, opts PruneOptions) (*PruneResult, error) {
+	conn, err := c.getConnection()
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := node.NewNodeClient(conn).Prune(ctx, &node.PruneRequest{
+		Namespace:     c.Namespace,
+		KeepFor:       int64(opts.KeepFor),
+		LabelSelector: opts.Selector,
+	})
+	if err != nil {
+		return nil, translateError(err)
+	}
+
+	return &PruneResult{
+		Containers:     resp.GetContainers(),
+		Images:         resp.GetImages(),
+		ReclaimedBytes: resp.GetReclaimedBytes(),
+	}, nil
+}
+
+// isPodFinished return true if all pod containers have stopped before the time
+// and none of them gets restarted by the restart policy
+func isPodFinished(pod model.Pod, before time.Time) bool {
+	if len(pod.Status.ContainerStatuses) == 0 {
+		return false
+	}
+
+	for _, status := range pod.Status.ContainerStatuses {
+		if status.State != "stopped" || model.ShouldRestart(model.GetRestartPolicy(pod, status.Name), status) {
+			return false
+		}
+		if status.FinishedAt.IsZero() || status.FinishedAt.After(before) {
+			return false
+		}
+	}
+	return true
+}
+
+// getUnusedImages return the images which no container use and which were pulled before the time.
+// If candidates is not nil, return only the images in it.
+func getUnusedImages(images []runtime.Image, pods []model.Pod, candidates map[string]bool, before time.Time) []runtime.Image {
+	used := map[string]bool{}
+	for _, pod := range pods {
+		for _, status := range append(append([]model.ContainerStatus{}, pod.Status.InitContainerStatuses...), pod.Status.ContainerStatuses...) {
+			used[status.Image] = true
+		}
+	}
+
+	result := []runtime.Image{}
+	for _, image := range images {
+		if used[image.Name] || image.UpdatedAt.After(before) || (candidates != nil && !candidates[image.Name]) {
+			continue
+		}
+		result = append(result, image)
+	}
+	return result
+}
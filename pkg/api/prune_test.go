@@ -0,0 +1,168 @@
+package api
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	node "github.com/ernoaapa/eliot/pkg/api/services/node/v1"
+	"github.com/ernoaapa/eliot/pkg/model"
+	"github.com/ernoaapa/eliot/pkg/runtime"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/net/context"
+)
+
+// pruneRuntime is runtime which keeps the pods and images in memory
+type pruneRuntime struct {
+	runtime.Client
+	mu     sync.Mutex
+	pods   []model.Pod
+	images []runtime.Image
+}
+
+func (r *pruneRuntime) GetPods(namespace string) ([]model.Pod, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	result := []model.Pod{}
+	for _, pod := range r.pods {
+		pod.Status.ContainerStatuses = append([]model.ContainerStatus{}, pod.Status.ContainerStatuses...)
+		result = append(result, pod)
+	}
+	return result, nil
+}
+
+func (r *pruneRuntime) GetPod(namespace, name string) (model.Pod, error) {
+	pods, _ := r.GetPods(namespace)
+	for _, pod := range pods {
+		if pod.Metadata.Name == name {
+			return pod, nil
+		}
+	}
+	return model.Pod{}, runtime.ErrWithMessagef(runtime.ErrNotFound, "Pod [%s] not found", name)
+}
+
+func (r *pruneRuntime) GetContainerDiskUsage(namespace, id string) (int64, error) {
+	return 10, nil
+}
+
+func (r *pruneRuntime) StopContainer(namespace, id string) (model.ContainerStatus, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	pods := []model.Pod{}
+	for _, pod := range r.pods {
+		statuses := []model.ContainerStatus{}
+		for _, status := range pod.Status.ContainerStatuses {
+			if status.ContainerID != id {
+				statuses = append(statuses, status)
+			}
+		}
+		pod.Status.ContainerStatuses = statuses
+		if len(statuses) > 0 {
+			pods = append(pods, pod)
+		}
+	}
+	r.pods = pods
+	return model.ContainerStatus{ContainerID: id}, nil
+}
+
+func (r *pruneRuntime) GetImages(namespace string) ([]runtime.Image, error) {
+	return r.images, nil
+}
+
+func (r *pruneRuntime) DeleteImage(namespace, name string) error {
+	images := []runtime.Image{}
+	for _, image := range r.images {
+		if image.Name != name {
+			images = append(images, image)
+		}
+	}
+	r.images = images
+	return nil
+}
+
+func newPrunePod(name, restartPolicy string, statuses ...model.ContainerStatus) model.Pod {
+	pod := model.Pod{
+		Metadata: model.NewMetadata("eliot", name),
+		Spec:     model.PodSpec{RestartPolicy: restartPolicy},
+	}
+	pod.Metadata.Labels = map[string]string{"app": name}
+	for _, status := range statuses {
+		pod.AppendContainer(model.Container{Name: status.Name, Image: status.Image}, status)
+	}
+	return pod
+}
+
+func newPruneRuntime() *pruneRuntime {
+	hourAgo := time.Now().Add(-time.Hour)
+	return &pruneRuntime{
+		pods: []model.Pod{
+			newPrunePod("migrate", "never",
+				model.ContainerStatus{ContainerID: "1", Name: "migrate", Image: "migrate:1", State: "stopped", FinishedAt: hourAgo}),
+			newPrunePod("web", "never",
+				model.ContainerStatus{ContainerID: "2", Name: "nginx", Image: "nginx:1", State: "running"},
+				model.ContainerStatus{ContainerID: "3", Name: "init", Image: "init:1", State: "stopped", FinishedAt: hourAgo}),
+			newPrunePod("worker", "onfailure",
+				model.ContainerStatus{ContainerID: "4", Name: "worker", Image: "worker:1", State: "stopped", ExitCode: 1, Reason: model.ReasonError, FinishedAt: hourAgo}),
+			newPrunePod("recent", "never",
+				model.ContainerStatus{ContainerID: "5", Name: "recent", Image: "recent:1", State: "stopped", FinishedAt: time.Now()}),
+		},
+		images: []runtime.Image{
+			{Name: "migrate:1", Size: 100, UpdatedAt: hourAgo},
+			{Name: "nginx:1", Size: 100, UpdatedAt: hourAgo},
+			{Name: "init:1", Size: 100, UpdatedAt: hourAgo},
+			{Name: "unused:1", Size: 100, UpdatedAt: hourAgo},
+			{Name: "pulling:1", Size: 100, UpdatedAt: time.Now()},
+		},
+	}
+}
+
+func TestServerPrune(t *testing.T) {
+	fake := newPruneRuntime()
+	server := NewServer("", fake, nil)
+
+	resp, err := server.Prune(context.Background(), &node.PruneRequest{Namespace: "eliot", KeepFor: int64(10 * time.Minute)})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"1"}, resp.Containers, "should prune only the finished pod, not stopped container of running pod")
+	assert.Equal(t, []string{"migrate:1", "unused:1"}, resp.Images, "should keep images in use and the ones just pulled")
+	assert.Equal(t, uint64(210), resp.ReclaimedBytes)
+	assert.Len(t, fake.pods, 3)
+}
+
+func TestServerPruneWithSelector(t *testing.T) {
+	fake := newPruneRuntime()
+	server := NewServer("", fake, nil)
+
+	resp, err := server.Prune(context.Background(), &node.PruneRequest{Namespace: "eliot", LabelSelector: "app=migrate"})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"1"}, resp.Containers)
+	assert.Equal(t, []string{"migrate:1"}, resp.Images, "should prune only the images of the pruned pods")
+
+	resp, err = server.Prune(context.Background(), &node.PruneRequest{Namespace: "eliot", LabelSelector: "app=web"})
+	assert.NoError(t, err)
+	assert.Empty(t, resp.Containers)
+	assert.Empty(t, resp.Images)
+}
+
+func TestServerPruneRechecksPodAfterLock(t *testing.T) {
+	fake := newPruneRuntime()
+	server := NewServer("", fake, nil)
+
+	unlock := server.locks.lock("eliot", "migrate")
+	done := make(chan *node.PruneResponse)
+	go func() {
+		resp, err := server.Prune(context.Background(), &node.PruneRequest{Namespace: "eliot", LabelSelector: "app=migrate"})
+		assert.NoError(t, err)
+		done <- resp
+	}()
+
+	// The pod gets started again while prune waits the lock
+	time.Sleep(50 * time.Millisecond)
+	fake.mu.Lock()
+	fake.pods[0].Status.ContainerStatuses[0].State = "running"
+	fake.mu.Unlock()
+	unlock()
+
+	resp := <-done
+	assert.Empty(t, resp.Containers, "should not prune pod which got started while waiting the lock")
+	assert.Empty(t, resp.Images)
+}
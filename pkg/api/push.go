@@ -0,0 +1,114 @@
+package api
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/ernoaapa/eliot/pkg/api/mapping"
+	node "github.com/ernoaapa/eliot/pkg/api/services/node/v1"
+	pods "github.com/ernoaapa/eliot/pkg/api/services/pods/v1"
+	"github.com/ernoaapa/eliot/pkg/image"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc/codes"
+)
+
+// TagImage gives the image in the node another name, e.g. to push image built with BuildImage to private registry.
+// The target gets replaced if it already exists. Returns ErrNotFound if the source image doesn't exist in the node
+// and ErrInvalidArgument if either of the references is malformed, or the target has digest.
+func (c *Client) TagImage(ctx context.Context, source, target string) error {
+	source, err := normalizeImageRef(source)
+	if err != nil {
+		return err
+	}
+	target, err = normalizeImageRef(target)
+	if err != nil {
+		return err
+	}
+	if ref, _ := image.ParseRef(target); ref.IsPinned() {
+		return &Error{Code: codes.InvalidArgument, Message: fmt.Sprintf("Invalid tag [%s], tag cannot have digest", target)}
+	}
+
+	conn, err := c.getConnection()
+	if err != nil {
+		return err
+	}
+
+	_, err = node.NewNodeClient(conn).TagImage(ctx, &node.TagImageRequest{
+		Namespace: c.Namespace,
+		Source:    source,
+		Target:    target,
+	})
+	return translateError(err)
+}
+
+// PushImage pushes the image from the node to the registry in the reference, e.g. registry.local/team/myapp:1.0.
+// The upload progress is sent to the channel in the same form as the CreatePod image pull progress,
+// the layers are the uploaded image blobs. The last value has Err set if the push failed, ErrRegistryUnauthorized
+// if the registry rejected the credentials. The channel get closed when the push completes.
+func (c *Client) PushImage(ctx context.Context, ref string, opts PushOptions) (<-chan ImagePushProgress, error) {
+	ref, err := normalizeImageRef(ref)
+	if err != nil {
+		return nil, err
+	}
+
+	conn, err := c.getConnection()
+	if err != nil {
+		return nil, err
+	}
+
+	stream, err := node.NewNodeClient(conn).PushImage(ctx, &node.PushImageRequest{
+		Namespace: c.Namespace,
+		Image:     ref,
+		Username:  opts.Username,
+		Password:  opts.Password,
+	})
+	if err != nil {
+		return nil, translatePushError(err)
+	}
+
+	result := make(chan ImagePushProgress)
+	go func() {
+		defer close(result)
+		for {
+			resp, err := stream.Recv()
+			if err == io.EOF {
+				return
+			}
+			if err != nil {
+				select {
+				case result <- ImagePushProgress{Err: translatePushError(err)}:
+				case <-ctx.Done():
+				}
+				return
+			}
+
+			fetches := mapping.MapAPIModelToImageFetchProgress([]*pods.ImageFetch{resp.Image})
+			if len(fetches) == 0 {
+				continue
+			}
+			select {
+			case result <- ImagePushProgress{ImageFetch: fetches[0]}:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return result, nil
+}
+
+// normalizeImageRef expands the image reference to fully qualified form, e.g. myapp -> docker.io/library/myapp:latest
+func normalizeImageRef(ref string) (string, error) {
+	parsed, err := image.ParseRef(expandImage(ref))
+	if err != nil {
+		return "", &Error{Code: codes.InvalidArgument, Message: err.Error()}
+	}
+	return resolvableImage(parsed), nil
+}
+
+func translatePushError(err error) error {
+	err = translateError(err)
+	if e, ok := err.(*Error); ok && e.Code == codes.Unauthenticated {
+		return &Error{Code: e.Code, Message: e.Message, cause: ErrRegistryUnauthorized}
+	}
+	return err
+}
@@ -0,0 +1,106 @@
+package api
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ernoaapa/eliot/pkg/progress"
+	"github.com/ernoaapa/eliot/pkg/runtime"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/net/context"
+)
+
+// pushRuntime records the tagged images and reports fake upload progress
+type pushRuntime struct {
+	runtime.Client
+	tags         map[string]string
+	auth         runtime.RegistryAuth
+	unauthorized bool
+}
+
+func (r *pushRuntime) TagImage(namespace, source, target string) error {
+	if source != "docker.io/library/myapp:latest" {
+		return runtime.ErrWithMessagef(runtime.ErrNotFound, "Image [%s] not found", source)
+	}
+	r.tags[target] = source
+	return nil
+}
+
+func (r *pushRuntime) PushImage(namespace, ref string, auth runtime.RegistryAuth, status *progress.ImageFetch, cancel <-chan struct{}) error {
+	r.auth = auth
+	if r.unauthorized {
+		return runtime.ErrWithMessagef(runtime.ErrUnauthorized, "Registry rejected the credentials to push image [%s]", ref)
+	}
+	status.Add("layer-sha256:abc", "sha256:abc")
+	status.SetToDownloading("layer-sha256:abc", 50, 100)
+	time.Sleep(150 * time.Millisecond)
+	status.AllDone()
+	return nil
+}
+
+func receivePushProgress(t *testing.T, progressc <-chan ImagePushProgress) (result []ImagePushProgress) {
+	timeout := time.After(5 * time.Second)
+	for {
+		select {
+		case p, ok := <-progressc:
+			if !ok {
+				return result
+			}
+			result = append(result, p)
+		case <-timeout:
+			t.Fatal("PushImage didn't close the channel")
+			return result
+		}
+	}
+}
+
+func TestTagImage(t *testing.T) {
+	fake := &pushRuntime{tags: map[string]string{}}
+	client, stop := startDiskUsageServer(t, fake)
+	defer stop()
+
+	assert.NoError(t, client.TagImage(context.Background(), "myapp", "registry.local/team/myapp:1.0"))
+	assert.Equal(t, map[string]string{"registry.local/team/myapp:1.0": "docker.io/library/myapp:latest"}, fake.tags)
+
+	err := client.TagImage(context.Background(), "missing", "registry.local/team/missing:1.0")
+	assert.True(t, errors.Is(err, ErrNotFound), "should return ErrNotFound, got: %v", err)
+
+	err = client.TagImage(context.Background(), "myapp", "myapp@sha256:abc")
+	assert.True(t, errors.Is(err, ErrInvalidArgument), "should reject digest in target")
+}
+
+func TestPushImageStreamsProgress(t *testing.T) {
+	fake := &pushRuntime{}
+	client, stop := startDiskUsageServer(t, fake)
+	defer stop()
+
+	progressc, err := client.PushImage(context.Background(), "registry.local/team/myapp:1.0", PushOptions{Username: "user", Password: "secret"})
+	assert.NoError(t, err)
+
+	received := receivePushProgress(t, progressc)
+	assert.True(t, len(received) >= 2, "should receive progress while pushing and the final progress")
+	assert.Equal(t, runtime.RegistryAuth{Username: "user", Password: "secret"}, fake.auth)
+
+	last := received[len(received)-1]
+	assert.NoError(t, last.Err)
+	assert.Equal(t, "registry.local/team/myapp:1.0", last.Image)
+	assert.True(t, last.IsDone())
+	assert.False(t, last.Failed)
+}
+
+func TestPushImageUnauthorized(t *testing.T) {
+	client, stop := startDiskUsageServer(t, &pushRuntime{unauthorized: true})
+	defer stop()
+
+	progressc, err := client.PushImage(context.Background(), "registry.local/team/myapp:1.0", PushOptions{})
+	assert.NoError(t, err)
+
+	received := receivePushProgress(t, progressc)
+	assert.Len(t, received, 2)
+	assert.True(t, received[0].Failed)
+
+	err = received[1].Err
+	assert.True(t, errors.Is(err, ErrRegistryUnauthorized), "should return ErrRegistryUnauthorized, got: %v", err)
+	assert.True(t, errors.Is(err, ErrUnauthenticated))
+}
@@ -0,0 +1,24 @@
+package api
+
+import (
+	"fmt"
+
+	"golang.org/x/net/context"
+	"google.golang.org/grpc/codes"
+)
+
+// waitRateLimit blocks until the WithRateLimit limiter allows new call or the context get cancelled
+func (c *Client) waitRateLimit(ctx context.Context) error {
+	if c.limiter == nil {
+		return nil
+	}
+
+	if err := c.limiter.Wait(ctx); err != nil {
+		if ctx.Err() != nil {
+			return translateError(ctx.Err())
+		}
+		// The limiter fails right away if the wait would exceed the context deadline
+		return &Error{Code: codes.DeadlineExceeded, Message: fmt.Sprintf("Rate limit doesn't allow the call before the deadline: %s", err)}
+	}
+	return nil
+}
@@ -0,0 +1,61 @@
+package api
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/net/context"
+	"golang.org/x/time/rate"
+
+	"github.com/ernoaapa/eliot/pkg/config"
+)
+
+func TestRateLimitWaitsForToken(t *testing.T) {
+	address, stop := startFakeNodeServer(t, "node")
+	defer stop()
+
+	client, err := NewClient("eliot", config.Endpoint{Name: "node", URL: address}, WithInsecure(), WithRateLimit(rate.Every(100*time.Millisecond), 1))
+	assert.NoError(t, err)
+	defer client.Close()
+
+	start := time.Now()
+	for i := 0; i < 3; i++ {
+		_, err := client.GetInfo(context.Background())
+		assert.NoError(t, err)
+	}
+	assert.True(t, time.Since(start) >= 200*time.Millisecond, "should wait token for the second and third call, took %s", time.Since(start))
+}
+
+func TestRateLimitRespectsContext(t *testing.T) {
+	address, stop := startFakeNodeServer(t, "node")
+	defer stop()
+
+	client, err := NewClient("eliot", config.Endpoint{Name: "node", URL: address}, WithInsecure(), WithRateLimit(rate.Every(time.Hour), 1))
+	assert.NoError(t, err)
+	defer client.Close()
+
+	_, err = client.GetInfo(context.Background())
+	assert.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	_, err = client.GetInfo(ctx)
+	assert.True(t, errors.Is(err, ErrDeadlineExceeded), "should fail because the token is not available before the deadline, got %v", err)
+	assert.True(t, time.Since(start) < time.Second, "should not wait the token beyond the deadline")
+
+	ctx, cancel = context.WithCancel(context.Background())
+	cancel()
+	_, err = client.GetInfo(ctx)
+	assert.True(t, errors.Is(err, ErrCanceled))
+}
+
+func TestWithRateLimitValidates(t *testing.T) {
+	_, err := NewClient("eliot", config.Endpoint{Name: "node", URL: "localhost:5000"}, WithRateLimit(0, 1))
+	assert.Error(t, err)
+
+	_, err = NewClient("eliot", config.Endpoint{Name: "node", URL: "localhost:5000"}, WithRateLimit(10, 0))
+	assert.Error(t, err)
+}
@@ -0,0 +1,70 @@
+package api
+
+import (
+	"fmt"
+
+	containers "github.com/ernoaapa/eliot/pkg/api/services/containers/v1"
+	pods "github.com/ernoaapa/eliot/pkg/api/services/pods/v1"
+)
+
+// WithHTTPReadinessProbe sets the container readiness probe to GET request to the path in the container port.
+// The container is ready when the response status code is 2xx or 3xx, so WaitForPodReady waits until it responds.
+// Init containers run to completion, so they cannot have readiness probe.
+// Returns ErrContainerNotFound if the pod doesn't have the container.
+func WithHTTPReadinessProbe(containerName, path string, port int) PodOpts {
+	return withReadinessProbe(containerName, &containers.Probe{
+		HttpGet: &containers.HTTPGetProbe{Path: path, Port: int32(port)},
+	})
+}
+
+// WithExecReadinessProbe sets the container readiness probe to command what the node runs in the container.
+// The container is ready when the command exits with zero exit code, so WaitForPodReady waits until it succeeds.
+// Init containers run to completion, so they cannot have readiness probe.
+// Returns ErrContainerNotFound if the pod doesn't have the container.
+func WithExecReadinessProbe(containerName string, cmd []string) PodOpts {
+	return withReadinessProbe(containerName, &containers.Probe{
+		Exec: &containers.ExecProbe{Command: cmd},
+	})
+}
+
+func withReadinessProbe(containerName string, probe *containers.Probe) PodOpts {
+	return func(pod *pods.Pod) error {
+		container, err := findSpecContainer(pod, containerName)
+		if err != nil {
+			return err
+		}
+		if isInitContainer(pod, containerName) {
+			return fmt.Errorf("Init container [%s] cannot have readiness probe, init containers run to completion", containerName)
+		}
+		if problem := validateProbe(probe); problem != "" {
+			return fmt.Errorf("Invalid container [%s] readiness probe, %s", containerName, problem)
+		}
+		container.ReadinessProbe = probe
+		return nil
+	}
+}
+
+// validateProbe return the problem in the probe, empty if the probe is valid
+func validateProbe(probe *containers.Probe) string {
+	httpGet, exec := probe.GetHttpGet(), probe.GetExec()
+	switch {
+	case httpGet == nil && exec == nil:
+		return "must have either httpGet or exec"
+	case httpGet != nil && exec != nil:
+		return "must have only one of httpGet or exec"
+	case httpGet != nil && (httpGet.GetPort() <= 0 || httpGet.GetPort() > 65535):
+		return fmt.Sprintf("httpGet port %d must be between 1 and 65535", httpGet.GetPort())
+	case exec != nil && (len(exec.GetCommand()) == 0 || exec.GetCommand()[0] == ""):
+		return "exec command must not be empty"
+	}
+	return ""
+}
+
+// isContainerReady return true if the container is running and, if the container has readiness probe,
+// the probe has succeeded
+func isContainerReady(container *containers.Container, status *containers.ContainerStatus) bool {
+	if !MapContainerState(status).Running {
+		return false
+	}
+	return container.GetReadinessProbe() == nil || status.GetReady()
+}
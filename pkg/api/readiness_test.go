@@ -0,0 +1,53 @@
+package api
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	containers "github.com/ernoaapa/eliot/pkg/api/services/containers/v1"
+)
+
+func TestWithReadinessProbe(t *testing.T) {
+	pod := newEnvTestPod()
+	assert.NoError(t, WithHTTPReadinessProbe("app", "/healthz", 8080)(pod))
+	assert.Equal(t, &containers.HTTPGetProbe{Path: "/healthz", Port: 8080}, pod.Spec.Containers[0].ReadinessProbe.HttpGet)
+	assert.Nil(t, pod.Spec.Containers[1].ReadinessProbe, "should change only the given container")
+
+	assert.NoError(t, WithExecReadinessProbe("sidecar", []string{"pg_isready"})(pod))
+	assert.Equal(t, []string{"pg_isready"}, pod.Spec.Containers[1].ReadinessProbe.Exec.Command)
+
+	assert.True(t, errors.Is(WithHTTPReadinessProbe("web", "/", 80)(pod), ErrContainerNotFound))
+	assert.EqualError(t, WithHTTPReadinessProbe("app", "/", 0)(pod), "Invalid container [app] readiness probe, httpGet port 0 must be between 1 and 65535")
+	assert.EqualError(t, WithExecReadinessProbe("app", nil)(pod), "Invalid container [app] readiness probe, exec command must not be empty")
+
+	pod.Spec.InitContainers = []*containers.Container{{Name: "migrate", Image: "alpine"}}
+	assert.Error(t, WithExecReadinessProbe("migrate", []string{"true"})(pod), "init container should not accept readiness probe")
+}
+
+func TestValidatePodReadinessProbe(t *testing.T) {
+	pod := newEnvTestPod()
+	pod.Spec.Containers[0].ReadinessProbe = &containers.Probe{}
+	pod.Spec.InitContainers = []*containers.Container{{Name: "migrate", Image: "alpine", ReadinessProbe: &containers.Probe{Exec: &containers.ExecProbe{Command: []string{"true"}}}}}
+
+	err := ValidatePod(pod)
+	assert.True(t, errors.Is(err, ErrInvalidArgument))
+	assert.Equal(t, []string{
+		"container #1 readiness probe must have either httpGet or exec",
+		"init container #1 must not have readiness probe, init containers run to completion",
+	}, err.(*ValidationError).Problems)
+}
+
+func TestIsPodReadyWaitsReadinessProbe(t *testing.T) {
+	pod := newWaitTestPod(map[string]string{"foo": "running", "bar": "running"})
+	pod.Spec.Containers[1].ReadinessProbe = &containers.Probe{HttpGet: &containers.HTTPGetProbe{Path: "/", Port: 80}}
+	pod.Status.ContainerStatuses[1].ReadinessMessage = "HTTP probe GET / to port 80 returned status 503"
+
+	assert.False(t, IsPodReady(pod), "should not be ready until the probe succeeds")
+	assert.EqualError(t, newPodNotReadyError("my-pod", pod, 10*time.Second), "Pod [my-pod] not ready within 10s, readiness probes not passed: [bar (HTTP probe GET / to port 80 returned status 503)]")
+
+	pod.Status.ContainerStatuses[1].Ready = true
+	assert.True(t, IsPodReady(pod))
+}
@@ -0,0 +1,196 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"golang.org/x/net/context"
+	"google.golang.org/grpc/codes"
+)
+
+// ReconnectPolicy defines how AttachWithReconnect attaches again after the connection breaks
+type ReconnectPolicy struct {
+	// MaxRetries is how many times in a row to try attaching again before giving up,
+	// the count resets after the container output flows again. Zero disables reconnecting.
+	MaxRetries int
+	// Backoff is the initial wait before attaching again, doubled on every failed attempt
+	Backoff time.Duration
+}
+
+// DefaultReconnectPolicy tries to reconnect five times, starting from one second wait
+var DefaultReconnectPolicy = ReconnectPolicy{
+	MaxRetries: 5,
+	Backoff:    time.Second,
+}
+
+func (p ReconnectPolicy) validate() error {
+	if p.MaxRetries < 0 {
+		return fmt.Errorf("Invalid reconnect max retries [%d], cannot be negative", p.MaxRetries)
+	}
+	if p.Backoff < 0 {
+		return fmt.Errorf("Invalid reconnect backoff [%s], cannot be negative", p.Backoff)
+	}
+	return nil
+}
+
+// AttachWithReconnect is like Attach, but when the connection breaks, e.g. because of flaky network link,
+// it attaches again to the same container and resumes the output.
+// The input typed while disconnected cannot be replayed, so a notice gets written to the AttachIO Stderr
+// (or Stdout if Stderr is nil) after every reconnect. The hooks get started again on every reconnect.
+// Stops retrying when the policy allows no more attempts, or returns ErrContainerNotRunning
+// if the container exited while the connection was broken.
+func (c *Client) AttachWithReconnect(ctx context.Context, containerID string, attachIO AttachIO, policy ReconnectPolicy, hooks ...AttachHooks) error {
+	if err := policy.validate(); err != nil {
+		return err
+	}
+
+	if attachIO.Stats == nil {
+		// Used to detect whether the output flows after reconnect
+		attachIO.Stats = &AttachStats{}
+	}
+
+	notice := attachIO.Stderr
+	if notice == nil {
+		notice = attachIO.Stdout
+	}
+
+	done := make(chan struct{})
+	defer close(done)
+	var stdin *sharedReader
+	if attachIO.Stdin != nil {
+		stdin = newSharedReader(attachIO.Stdin, done)
+	}
+
+	backoff := retryPolicy{backoff: policy.Backoff, logger: c.logger}
+	for attempt := 0; ; {
+		received := attachIO.Stats.StdoutBytes() + attachIO.Stats.StderrBytes()
+		err := c.attachOnce(ctx, containerID, attachIO, stdin, hooks...)
+		if err == nil || ctx.Err() != nil || !isRetryable(err) {
+			return err
+		}
+
+		if attachIO.Stats.StdoutBytes()+attachIO.Stats.StderrBytes() > received {
+			attempt = 0
+		}
+
+		for {
+			if attempt >= policy.MaxRetries {
+				return err
+			}
+			attempt++
+
+			wait := backoff.getBackoff(attempt)
+			c.logger.Debugf("Attach to container [%s] broken, attach again in %s (attempt %d/%d). Error: %s", containerID, wait, attempt, policy.MaxRetries, err)
+			select {
+			case <-ctx.Done():
+				return translateError(ctx.Err())
+			case <-time.After(wait):
+			}
+
+			// Attach to stopped container would return immediately as if the process just exited
+			if _, err = c.ContainerStats(ctx, containerID); err == nil {
+				break
+			}
+			if errors.Is(err, ErrContainerNotRunning) {
+				return &Error{
+					Code:    codes.FailedPrecondition,
+					Message: fmt.Sprintf("Container [%s] exited while the connection was broken", containerID),
+					cause:   ErrContainerNotRunning,
+				}
+			}
+			if !isRetryable(err) {
+				return err
+			}
+		}
+
+		if notice != nil {
+			fmt.Fprintf(notice, "\r\n--- Reconnected to container [%s], input typed while disconnected was lost ---\r\n", containerID)
+		}
+	}
+}
+
+// attachOnce attaches with stdin which can be shared across the attempts
+func (c *Client) attachOnce(ctx context.Context, containerID string, attachIO AttachIO, stdin *sharedReader, hooks ...AttachHooks) error {
+	if stdin == nil {
+		return c.Attach(ctx, containerID, attachIO, hooks...)
+	}
+
+	done := make(chan struct{})
+	defer close(done)
+	attachIO.Stdin = stdin.reader(done)
+	return c.Attach(ctx, containerID, attachIO, hooks...)
+}
+
+type readResult struct {
+	data []byte
+	err  error
+}
+
+// sharedReader reads the underlying reader in single goroutine, so that the reads can be handed over
+// to the next attach attempt. Otherwise the previous attempt stdin goroutine, still blocked in Read,
+// would consume the next input.
+type sharedReader struct {
+	reads chan readResult
+}
+
+func newSharedReader(r io.Reader, done <-chan struct{}) *sharedReader {
+	s := &sharedReader{
+		reads: make(chan readResult),
+	}
+	go func() {
+		buf := make([]byte, os.Getpagesize())
+		for {
+			n, err := r.Read(buf)
+			result := readResult{data: append([]byte(nil), buf[:n]...), err: err}
+			select {
+			case s.reads <- result:
+			case <-done:
+				return
+			}
+			if err != nil {
+				return
+			}
+		}
+	}()
+	return s
+}
+
+// reader returns reader which returns io.EOF after the done channel closes, so that it doesn't consume the input
+// meant for the next reader
+func (s *sharedReader) reader(done <-chan struct{}) io.Reader {
+	return &sharedReaderAttempt{shared: s, done: done}
+}
+
+type sharedReaderAttempt struct {
+	shared  *sharedReader
+	done    <-chan struct{}
+	pending []byte
+	err     error
+}
+
+func (r *sharedReaderAttempt) Read(p []byte) (int, error) {
+	if len(r.pending) == 0 && r.err == nil {
+		select {
+		case <-r.done:
+			return 0, io.EOF
+		default:
+		}
+
+		select {
+		case <-r.done:
+			return 0, io.EOF
+		case result := <-r.shared.reads:
+			r.pending, r.err = result.data, result.err
+		}
+	}
+
+	n := copy(p, r.pending)
+	r.pending = r.pending[n:]
+	if len(r.pending) == 0 && r.err != nil {
+		return n, r.err
+	}
+	return n, nil
+}
@@ -0,0 +1,93 @@
+package api
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	containers "github.com/ernoaapa/eliot/pkg/api/services/containers/v1"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+var testReconnectPolicy = ReconnectPolicy{MaxRetries: 3, Backoff: 10 * time.Millisecond}
+
+func TestAttachWithReconnectResumesOutput(t *testing.T) {
+	var (
+		attempts    = 0
+		reattaching = make(chan struct{})
+		inputs      = []string{}
+	)
+	client, stop := startFakeServer(t, nil, &fakeContainersServer{
+		attach: func(server containers.Containers_AttachServer) error {
+			attempts++
+			req, err := server.Recv()
+			if err != nil {
+				return err
+			}
+			inputs = append(inputs, string(req.Input))
+
+			if attempts == 1 {
+				server.Send(&containers.StdoutStreamResponse{Output: []byte("first\n")})
+				return status.Error(codes.Unavailable, "transport is closing")
+			}
+			return server.Send(&containers.StdoutStreamResponse{Output: []byte("second\n")})
+		},
+		// Called between the attempts, after the first attach has returned
+		stats: func(req *containers.StatsRequest) (*containers.StatsResponse, error) {
+			close(reattaching)
+			return &containers.StatsResponse{Stats: &containers.ContainerStats{}}, nil
+		},
+	})
+	defer stop()
+
+	stdin, stdinWriter := io.Pipe()
+	go func() {
+		stdinWriter.Write([]byte("a"))
+		<-reattaching
+		stdinWriter.Write([]byte("b"))
+	}()
+
+	stdout, stderr := &bytes.Buffer{}, &bytes.Buffer{}
+	err := client.AttachWithReconnect(context.Background(), "foo", NewAttachIO(stdin, stdout, stderr), testReconnectPolicy)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, attempts)
+	assert.Equal(t, []string{"a", "b"}, inputs, "should hand over the stdin to the new attach")
+	assert.Equal(t, "first\nsecond\n", stdout.String())
+	assert.Contains(t, stderr.String(), "Reconnected to container [foo]")
+}
+
+func TestAttachWithReconnectStopsWhenContainerExited(t *testing.T) {
+	attempts := 0
+	client, stop := startFakeServer(t, nil, &fakeContainersServer{
+		attach: func(server containers.Containers_AttachServer) error {
+			attempts++
+			return status.Error(codes.Unavailable, "transport is closing")
+		},
+		stats: func(req *containers.StatsRequest) (*containers.StatsResponse, error) {
+			return nil, status.Error(codes.FailedPrecondition, "container not running")
+		},
+	})
+	defer stop()
+
+	err := client.AttachWithReconnect(context.Background(), "foo", NewAttachIO(nil, &bytes.Buffer{}, &bytes.Buffer{}), testReconnectPolicy)
+	assert.True(t, errors.Is(err, ErrContainerNotRunning), "should return ErrContainerNotRunning but got %v", err)
+	assert.Equal(t, 1, attempts)
+}
+
+func TestAttachWithReconnectGivesUpAfterMaxRetries(t *testing.T) {
+	attempts := 0
+	client, stop := startFakeContainersServer(t, func(server containers.Containers_AttachServer) error {
+		attempts++
+		return status.Error(codes.Unavailable, "transport is closing")
+	})
+	defer stop()
+
+	err := client.AttachWithReconnect(context.Background(), "foo", NewAttachIO(nil, &bytes.Buffer{}, &bytes.Buffer{}), testReconnectPolicy)
+	assert.True(t, errors.Is(err, ErrUnavailable), "should return ErrUnavailable but got %v", err)
+	assert.Equal(t, 1+testReconnectPolicy.MaxRetries, attempts)
+}
@@ -0,0 +1,50 @@
+package api
+
+import (
+	"golang.org/x/net/context"
+	"google.golang.org/grpc/codes"
+
+	containers "github.com/ernoaapa/eliot/pkg/api/services/containers/v1"
+)
+
+// UpdateResources changes the memory and CPU limits of the running container without restarting it,
+// e.g. to raise the memory limit of a container which is about to get OOM killed. Only the non-zero limits
+// are changed and the limits are kept when the container gets restarted. Return all limits after the update.
+// Memory limit below the container current memory usage, including page cache, is rejected with ErrInvalidArgument,
+// because the container would get OOM killed right away.
+// Returns ErrContainerNotRunning if the container is not running.
+func (c *Client) UpdateResources(ctx context.Context, containerID string, res ResourceLimits) (*ResourceLimits, error) {
+	if res.MemoryLimitBytes < 0 || res.CPUQuota < 0 {
+		return nil, &Error{Code: codes.InvalidArgument, Message: "Resource limits must not be negative"}
+	}
+	if res == (ResourceLimits{}) {
+		return nil, &Error{Code: codes.InvalidArgument, Message: "No resource limits to update"}
+	}
+
+	conn, err := c.getConnection()
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := containers.NewContainersClient(conn).UpdateResources(ctx, &containers.UpdateResourcesRequest{
+		Namespace:   c.Namespace,
+		ContainerID: containerID,
+		Limits: &containers.ResourceLimits{
+			MemoryLimitBytes: res.MemoryLimitBytes,
+			CpuShares:        res.CPUShares,
+			CpuQuota:         res.CPUQuota,
+			CpuPeriod:        res.CPUPeriod,
+		},
+	})
+	if err != nil {
+		return nil, translateStatsError(translateContainerError(err))
+	}
+
+	limits := resp.GetLimits()
+	return &ResourceLimits{
+		MemoryLimitBytes: limits.GetMemoryLimitBytes(),
+		CPUShares:        limits.GetCpuShares(),
+		CPUQuota:         limits.GetCpuQuota(),
+		CPUPeriod:        limits.GetCpuPeriod(),
+	}, nil
+}
@@ -0,0 +1,77 @@
+package api
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/ernoaapa/eliot/pkg/runtime"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/net/context"
+)
+
+// resourcesRuntime has single running container "foo" which uses 100 bytes of memory
+type resourcesRuntime struct {
+	runtime.Client
+	limits  runtime.ResourceLimits
+	updates int
+}
+
+func (r *resourcesRuntime) GetContainerStats(namespace, name string) (runtime.ContainerStats, error) {
+	if name != "foo" {
+		return runtime.ContainerStats{}, runtime.ErrWithMessagef(runtime.ErrNotRunning, "Container [%s] is not running", name)
+	}
+	return runtime.ContainerStats{MemoryUsageBytes: 100, MemoryLimitBytes: uint64(r.limits.MemoryLimitBytes)}, nil
+}
+
+func (r *resourcesRuntime) UpdateContainerResources(namespace, name string, limits runtime.ResourceLimits) (runtime.ResourceLimits, error) {
+	if name != "foo" {
+		return runtime.ResourceLimits{}, runtime.ErrWithMessagef(runtime.ErrNotRunning, "Container [%s] is not running", name)
+	}
+	r.updates++
+	if limits.MemoryLimitBytes != 0 {
+		r.limits.MemoryLimitBytes = limits.MemoryLimitBytes
+	}
+	if limits.CPUShares != 0 {
+		r.limits.CPUShares = limits.CPUShares
+	}
+	return r.limits, nil
+}
+
+func TestUpdateResources(t *testing.T) {
+	fake := &resourcesRuntime{limits: runtime.ResourceLimits{MemoryLimitBytes: 150, CPUShares: 1024}}
+	client, stop := startDiskUsageServer(t, fake)
+	defer stop()
+
+	result, err := client.UpdateResources(context.Background(), "foo", ResourceLimits{MemoryLimitBytes: 200})
+	assert.NoError(t, err)
+	assert.Equal(t, &ResourceLimits{MemoryLimitBytes: 200, CPUShares: 1024}, result, "should return all applied limits")
+
+	result, err = client.UpdateResources(context.Background(), "foo", ResourceLimits{CPUShares: 512})
+	assert.NoError(t, err)
+	assert.Equal(t, &ResourceLimits{MemoryLimitBytes: 200, CPUShares: 512}, result)
+}
+
+func TestUpdateResourcesRejectsMemoryBelowUsage(t *testing.T) {
+	fake := &resourcesRuntime{limits: runtime.ResourceLimits{MemoryLimitBytes: 150}}
+	client, stop := startDiskUsageServer(t, fake)
+	defer stop()
+
+	_, err := client.UpdateResources(context.Background(), "foo", ResourceLimits{MemoryLimitBytes: 50})
+	assert.True(t, errors.Is(err, ErrInvalidArgument), "should return ErrInvalidArgument, got: %v", err)
+	assert.Contains(t, err.Error(), "below container [foo] current memory usage 100 bytes")
+	assert.Equal(t, 0, fake.updates, "should not update the limits")
+}
+
+func TestUpdateResourcesErrors(t *testing.T) {
+	client, stop := startDiskUsageServer(t, &resourcesRuntime{})
+	defer stop()
+
+	_, err := client.UpdateResources(context.Background(), "stopped", ResourceLimits{MemoryLimitBytes: 200})
+	assert.True(t, errors.Is(err, ErrContainerNotRunning), "should return ErrContainerNotRunning, got: %v", err)
+
+	_, err = client.UpdateResources(context.Background(), "foo", ResourceLimits{})
+	assert.True(t, errors.Is(err, ErrInvalidArgument))
+
+	_, err = client.UpdateResources(context.Background(), "foo", ResourceLimits{CPUQuota: -1})
+	assert.True(t, errors.Is(err, ErrInvalidArgument))
+}
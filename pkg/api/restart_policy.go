@@ -0,0 +1,82 @@
+package api
+
+import (
+	"fmt"
+
+	pods "github.com/ernoaapa/eliot/pkg/api/services/pods/v1"
+)
+
+// RestartPolicy tells when the node restarts the container after it stops
+type RestartPolicy string
+
+// Restart policies what the node supports
+const (
+	RestartAlways    RestartPolicy = "always"
+	RestartOnFailure RestartPolicy = "onfailure"
+	RestartNever     RestartPolicy = "never"
+)
+
+var restartPolicies = []RestartPolicy{
+	RestartAlways,
+	RestartOnFailure,
+	RestartNever,
+}
+
+// initRestartPolicies are the restart policies of init containers, which must run to completion
+var initRestartPolicies = []RestartPolicy{
+	RestartOnFailure,
+	RestartNever,
+}
+
+// WithRestartPolicy sets the container restart policy, which overrides the pod restart policy.
+// Init containers run to completion, so they cannot have RestartAlways.
+// Returns ErrContainerNotFound if the pod doesn't have the container.
+func WithRestartPolicy(containerName string, policy RestartPolicy) PodOpts {
+	return func(pod *pods.Pod) error {
+		container, err := findSpecContainer(pod, containerName)
+		if err != nil {
+			return err
+		}
+		if !isRestartPolicy(string(policy)) {
+			return fmt.Errorf("Invalid restart policy [%s], must be one of %v", policy, restartPolicies)
+		}
+		if policy == RestartAlways && isInitContainer(pod, containerName) {
+			return fmt.Errorf("Invalid init container restart policy [%s], must be one of %v", policy, initRestartPolicies)
+		}
+		container.RestartPolicy = string(policy)
+		return nil
+	}
+}
+
+// GetContainerRestartPolicy returns the restart policy what the node applies to the container:
+// the container own policy, the pod policy or RestartAlways if neither is set.
+// Init containers run to completion, so they get RestartOnFailure unless the pod policy is RestartNever.
+// Returns ErrContainerNotFound if the pod doesn't have the container.
+func GetContainerRestartPolicy(pod *pods.Pod, containerName string) (RestartPolicy, error) {
+	container, err := findSpecContainer(pod, containerName)
+	if err != nil {
+		return "", err
+	}
+	if container.GetRestartPolicy() != "" {
+		return RestartPolicy(container.GetRestartPolicy()), nil
+	}
+	if isInitContainer(pod, containerName) {
+		if pod.GetSpec().GetRestartPolicy() == string(RestartNever) {
+			return RestartNever, nil
+		}
+		return RestartOnFailure, nil
+	}
+	if pod.GetSpec().GetRestartPolicy() != "" {
+		return RestartPolicy(pod.GetSpec().GetRestartPolicy()), nil
+	}
+	return RestartAlways, nil
+}
+
+func isRestartPolicy(policy string) bool {
+	for _, known := range restartPolicies {
+		if RestartPolicy(policy) == known {
+			return true
+		}
+	}
+	return false
+}
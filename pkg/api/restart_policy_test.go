@@ -0,0 +1,55 @@
+package api
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	containers "github.com/ernoaapa/eliot/pkg/api/services/containers/v1"
+)
+
+func TestWithRestartPolicy(t *testing.T) {
+	pod := newEnvTestPod()
+	assert.NoError(t, WithRestartPolicy("app", RestartNever)(pod))
+	assert.Equal(t, "never", pod.Spec.Containers[0].RestartPolicy)
+	assert.Empty(t, pod.Spec.Containers[1].RestartPolicy, "should change only the given container")
+
+	assert.True(t, errors.Is(WithRestartPolicy("web", RestartNever)(pod), ErrContainerNotFound))
+	assert.EqualError(t, WithRestartPolicy("app", "sometimes")(pod), "Invalid restart policy [sometimes], must be one of [always onfailure never]")
+}
+
+func TestGetContainerRestartPolicy(t *testing.T) {
+	pod := newEnvTestPod()
+
+	policy, err := GetContainerRestartPolicy(pod, "app")
+	assert.NoError(t, err)
+	assert.Equal(t, RestartAlways, policy, "should default to always")
+
+	pod.Spec.RestartPolicy = "onfailure"
+	policy, _ = GetContainerRestartPolicy(pod, "app")
+	assert.Equal(t, RestartOnFailure, policy, "should fall back to the pod restart policy")
+
+	pod.Spec.Containers[0].RestartPolicy = "never"
+	policy, _ = GetContainerRestartPolicy(pod, "app")
+	assert.Equal(t, RestartNever, policy, "container policy should override the pod policy")
+
+	_, err = GetContainerRestartPolicy(pod, "web")
+	assert.True(t, errors.Is(err, ErrContainerNotFound))
+}
+
+func TestInitContainerRestartPolicy(t *testing.T) {
+	pod := newEnvTestPod()
+	assert.NoError(t, WithInitContainer(&containers.Container{Name: "migrate", Image: "docker.io/library/migrate:latest"})(pod))
+
+	policy, err := GetContainerRestartPolicy(pod, "migrate")
+	assert.NoError(t, err)
+	assert.Equal(t, RestartOnFailure, policy, "init container should run to completion")
+
+	pod.Spec.RestartPolicy = "never"
+	policy, _ = GetContainerRestartPolicy(pod, "migrate")
+	assert.Equal(t, RestartNever, policy)
+
+	assert.NoError(t, WithRestartPolicy("migrate", RestartNever)(pod))
+	assert.EqualError(t, WithRestartPolicy("migrate", RestartAlways)(pod), "Invalid init container restart policy [always], must be one of [onfailure never]")
+}
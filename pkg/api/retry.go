@@ -0,0 +1,67 @@
+package api
+
+import (
+	"errors"
+	"math/rand"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"golang.org/x/net/context"
+)
+
+// maxRetryBackoff limits the wait between retry attempts
+const maxRetryBackoff = 30 * time.Second
+
+// retryPolicy defines how idempotent calls get retried, zero value means no retries
+type retryPolicy struct {
+	maxAttempts int
+	backoff     time.Duration
+	logger      *log.Entry
+}
+
+// do calls the fn until it succeeds, returns non retryable error or attempts run out
+func (p retryPolicy) do(ctx context.Context, fn func() error) error {
+	for attempt := 1; ; attempt++ {
+		err := fn()
+		if err == nil || attempt >= p.maxAttempts || !isRetryable(err) {
+			return err
+		}
+
+		wait := p.getBackoff(attempt)
+		p.getLogger().Debugf("Call failed (attempt %d/%d), will retry in %s. Error: %s", attempt, p.maxAttempts, wait, err)
+
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(wait):
+		}
+	}
+}
+
+func (p retryPolicy) getLogger() *log.Entry {
+	if p.logger == nil {
+		return discardLogger
+	}
+	return p.logger
+}
+
+// getBackoff return the wait before next attempt, random duration between half and full of the exponential backoff
+func (p retryPolicy) getBackoff(attempt int) time.Duration {
+	backoff := p.backoff
+	for i := 1; i < attempt && backoff < maxRetryBackoff; i++ {
+		backoff *= 2
+	}
+	if backoff > maxRetryBackoff {
+		backoff = maxRetryBackoff
+	}
+	if backoff <= 0 {
+		return 0
+	}
+
+	half := backoff / 2
+	return half + time.Duration(rand.Int63n(int64(backoff-half)+1))
+}
+
+func isRetryable(err error) bool {
+	return errors.Is(err, ErrUnavailable)
+}
@@ -0,0 +1,65 @@
+package api
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/net/context"
+)
+
+func TestRetryUntilSuccess(t *testing.T) {
+	calls := 0
+	err := retryPolicy{maxAttempts: 3, backoff: time.Millisecond}.do(context.Background(), func() error {
+		calls++
+		if calls < 3 {
+			return ErrUnavailable
+		}
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, 3, calls)
+}
+
+func TestRetryFailFastOnNonRetryableError(t *testing.T) {
+	calls := 0
+	err := retryPolicy{maxAttempts: 3, backoff: time.Millisecond}.do(context.Background(), func() error {
+		calls++
+		return ErrNotFound
+	})
+	assert.Equal(t, ErrNotFound, err)
+	assert.Equal(t, 1, calls)
+}
+
+func TestRetryStopsToMaxAttempts(t *testing.T) {
+	calls := 0
+	err := retryPolicy{maxAttempts: 2, backoff: time.Millisecond}.do(context.Background(), func() error {
+		calls++
+		return ErrUnavailable
+	})
+	assert.Equal(t, ErrUnavailable, err)
+	assert.Equal(t, 2, calls)
+}
+
+func TestNoRetryByDefault(t *testing.T) {
+	calls := 0
+	retryPolicy{}.do(context.Background(), func() error {
+		calls++
+		return ErrUnavailable
+	})
+	assert.Equal(t, 1, calls)
+}
+
+func TestGetBackoff(t *testing.T) {
+	policy := retryPolicy{maxAttempts: 10, backoff: 100 * time.Millisecond}
+	for i := 0; i < 100; i++ {
+		first := policy.getBackoff(1)
+		assert.True(t, first >= 50*time.Millisecond && first <= 100*time.Millisecond, "first backoff %s out of range", first)
+
+		third := policy.getBackoff(3)
+		assert.True(t, third >= 200*time.Millisecond && third <= 400*time.Millisecond, "third backoff %s out of range", third)
+
+		capped := policy.getBackoff(20)
+		assert.True(t, capped <= maxRetryBackoff, "backoff %s should not exceed max", capped)
+	}
+}
@@ -0,0 +1,69 @@
+package api
+
+import "sync"
+
+// RingBuffer is io.Writer which keeps only the last written bytes, e.g. the tail of crashing container output.
+// Safe for concurrent use, so stdout and stderr can write to the same buffer.
+type RingBuffer struct {
+	mu   sync.Mutex
+	data []byte
+	// next is the index where the next byte gets written, and the oldest byte when the buffer is full
+	next int
+	full bool
+}
+
+// NewRingBuffer creates RingBuffer which keeps the last size bytes, panics if the size is not positive
+func NewRingBuffer(size int) *RingBuffer {
+	if size <= 0 {
+		panic("RingBuffer size must be positive")
+	}
+	return &RingBuffer{data: make([]byte, size)}
+}
+
+// NewRingBufferIO returns AttachIO without stdin, which writes both stdout and stderr to ring buffer of the size.
+// Read the output tail with Bytes after the attach returns.
+func NewRingBufferIO(size int) (AttachIO, *RingBuffer) {
+	buffer := NewRingBuffer(size)
+	return NewAttachIO(nil, buffer, buffer), buffer
+}
+
+// Write writes the bytes to the buffer, overwriting the oldest bytes when the buffer is full. Never fails.
+func (b *RingBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	written := len(p)
+	size := len(b.data)
+	if len(p) >= size {
+		copy(b.data, p[len(p)-size:])
+		b.next = 0
+		b.full = true
+		return written, nil
+	}
+
+	n := copy(b.data[b.next:], p)
+	if n < len(p) {
+		copy(b.data, p[n:])
+	}
+	if b.next+len(p) >= size {
+		b.full = true
+	}
+	b.next = (b.next + len(p)) % size
+	return written, nil
+}
+
+// Bytes returns copy of the buffer content, the oldest byte first
+func (b *RingBuffer) Bytes() []byte {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if !b.full {
+		return append([]byte{}, b.data[:b.next]...)
+	}
+	return append(append([]byte{}, b.data[b.next:]...), b.data[:b.next]...)
+}
+
+// String returns the buffer content as string
+func (b *RingBuffer) String() string {
+	return string(b.Bytes())
+}
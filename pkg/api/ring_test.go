@@ -0,0 +1,60 @@
+package api
+
+import (
+	"strings"
+	"sync"
+	"testing"
+
+	containers "github.com/ernoaapa/eliot/pkg/api/services/containers/v1"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/net/context"
+)
+
+func TestRingBuffer(t *testing.T) {
+	buffer := NewRingBuffer(5)
+	assert.Equal(t, "", buffer.String())
+
+	buffer.Write([]byte("abc"))
+	assert.Equal(t, "abc", buffer.String())
+
+	buffer.Write([]byte("de"))
+	assert.Equal(t, "abcde", buffer.String())
+
+	buffer.Write([]byte("fg"))
+	assert.Equal(t, "cdefg", buffer.String(), "should drop the oldest bytes")
+
+	n, err := buffer.Write([]byte("123456789"))
+	assert.NoError(t, err)
+	assert.Equal(t, 9, n, "should report all bytes written")
+	assert.Equal(t, "56789", buffer.String())
+}
+
+func TestRingBufferConcurrentWrites(t *testing.T) {
+	buffer := NewRingBuffer(64)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				buffer.Write([]byte("x"))
+			}
+		}()
+	}
+	wg.Wait()
+	assert.Equal(t, strings.Repeat("x", 64), buffer.String())
+}
+
+func TestAttachToRingBuffer(t *testing.T) {
+	client, stop := startFakeContainersServer(t, func(server containers.Containers_AttachServer) error {
+		server.Send(&containers.StdoutStreamResponse{Output: []byte("starting\n")})
+		server.Send(&containers.StdoutStreamResponse{Output: []byte("panic: oops\n"), Stderr: true})
+		return nil
+	})
+	defer stop()
+
+	attachIO, output := NewRingBufferIO(16)
+	assert.NoError(t, client.Attach(context.Background(), "foo", attachIO))
+	assert.Equal(t, "ing\npanic: oops\n", output.String())
+}
@@ -0,0 +1,102 @@
+package api
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/ernoaapa/eliot/pkg/api/core"
+	containers "github.com/ernoaapa/eliot/pkg/api/services/containers/v1"
+	pods "github.com/ernoaapa/eliot/pkg/api/services/pods/v1"
+	"golang.org/x/net/context"
+)
+
+// RunContainer creates and starts pod with single container from the image, like `eli run`, and returns
+// the started pod so that the container can be attached right away.
+// The container gets the pod name and the image can be given in short form, e.g. nginx:latest.
+// The pod uses the host network, so the ports the container listens are reachable in the node address.
+func (c *Client) RunContainer(ctx context.Context, name, image string, opts ...RunOpts) (*pods.Pod, error) {
+	pod, err := c.newRunPod(name, image, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := c.CreatePod(ctx, nil, pod); err != nil {
+		return nil, err
+	}
+	return c.StartPod(ctx, name)
+}
+
+// newRunPod return the single container pod what RunContainer creates
+func (c *Client) newRunPod(name, image string, opts ...RunOpts) (*pods.Pod, error) {
+	container := &containers.Container{
+		Name:  name,
+		Image: expandImage(image),
+	}
+	pod := &pods.Pod{
+		Metadata: &core.ResourceMetadata{
+			Name:      name,
+			Namespace: c.Namespace,
+		},
+		Spec: &pods.PodSpec{
+			HostNetwork: true,
+			Containers:  []*containers.Container{container},
+		},
+	}
+
+	for _, o := range opts {
+		if err := o(pod, container); err != nil {
+			return nil, err
+		}
+	}
+	return pod, nil
+}
+
+// WithRunCommand sets the command to run in the container instead of the image default command
+func WithRunCommand(command ...string) RunOpts {
+	return func(pod *pods.Pod, container *containers.Container) error {
+		if len(command) == 0 || command[0] == "" {
+			return fmt.Errorf("Invalid command, command cannot be empty")
+		}
+		container.Args = append(append([]string{}, command...), container.Args...)
+		return nil
+	}
+}
+
+// WithRunArgs adds arguments after the command given with WithRunCommand.
+// The arguments replace the whole image default command, so give the command too unless the args start with it.
+func WithRunArgs(args ...string) RunOpts {
+	return func(pod *pods.Pod, container *containers.Container) error {
+		container.Args = append(container.Args, args...)
+		return nil
+	}
+}
+
+// WithRunEnv sets the container environment variables, existing variables with the same name are replaced
+func WithRunEnv(env map[string]string) RunOpts {
+	return func(pod *pods.Pod, container *containers.Container) error {
+		names := make([]string, 0, len(env))
+		for name := range env {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		for _, name := range names {
+			if err := setEnv(container, name, env[name]); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
+
+// WithRunRestartPolicy sets the pod restart policy.
+// By default the node restarts the container always when it stops.
+func WithRunRestartPolicy(policy RestartPolicy) RunOpts {
+	return func(pod *pods.Pod, container *containers.Container) error {
+		if !isRestartPolicy(string(policy)) {
+			return fmt.Errorf("Invalid restart policy [%s], must be one of %v", policy, restartPolicies)
+		}
+		pod.Spec.RestartPolicy = string(policy)
+		return nil
+	}
+}
@@ -0,0 +1,49 @@
+package api
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/net/context"
+)
+
+func TestRunContainer(t *testing.T) {
+	fake := &applyPodsServer{}
+	client, stop := newApplyTestClient(t, fake)
+	defer stop()
+
+	pod, err := client.RunContainer(context.Background(), "web", "nginx:1.13",
+		WithRunCommand("nginx", "-g"),
+		WithRunArgs("daemon off;"),
+		WithRunEnv(map[string]string{"FOO": "bar"}),
+		WithRunRestartPolicy(RestartOnFailure),
+	)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"create", "start"}, fake.calls)
+	assert.Equal(t, "web", pod.Metadata.Name)
+	assert.Equal(t, "eliot", pod.Metadata.Namespace)
+	assert.True(t, pod.Spec.HostNetwork)
+	assert.Equal(t, "onfailure", pod.Spec.RestartPolicy)
+
+	container := pod.Spec.Containers[0]
+	assert.Equal(t, "web", container.Name)
+	assert.Equal(t, "docker.io/library/nginx:1.13", container.Image)
+	assert.Equal(t, []string{"nginx", "-g", "daemon off;"}, container.Args)
+	assert.Equal(t, []string{"FOO=bar"}, container.Env)
+}
+
+func TestRunContainerValidatesOptions(t *testing.T) {
+	fake := &applyPodsServer{}
+	client, stop := newApplyTestClient(t, fake)
+	defer stop()
+
+	_, err := client.RunContainer(context.Background(), "web", "nginx", WithRunRestartPolicy("sometimes"))
+	assert.EqualError(t, err, "Invalid restart policy [sometimes], must be one of [always onfailure never]")
+
+	_, err = client.RunContainer(context.Background(), "web", "nginx", WithRunCommand())
+	assert.Error(t, err)
+
+	_, err = client.RunContainer(context.Background(), "Invalid_Name", "nginx")
+	assert.Error(t, err)
+	assert.Empty(t, fake.calls, "should not create invalid pod")
+}
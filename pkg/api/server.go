@@ -1,10 +1,16 @@
 package api
 
 import (
+	"bufio"
+	"bytes"
 	"fmt"
+	"io"
+	"io/ioutil"
 	"net"
+	"os"
 	"strconv"
 	"strings"
+	"sync"
 	"syscall"
 	"time"
 
@@ -17,13 +23,36 @@ import (
 	node "github.com/ernoaapa/eliot/pkg/api/services/node/v1"
 	pods "github.com/ernoaapa/eliot/pkg/api/services/pods/v1"
 	"github.com/ernoaapa/eliot/pkg/api/stream"
+	"github.com/ernoaapa/eliot/pkg/image"
 	resolver "github.com/ernoaapa/eliot/pkg/node"
 	"github.com/ernoaapa/eliot/pkg/progress"
 	"github.com/ernoaapa/eliot/pkg/runtime"
+	digest "github.com/opencontainers/go-digest"
 	"github.com/pkg/errors"
 	log "github.com/sirupsen/logrus"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/health/grpc_health_v1"
 	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	// watchInterval is how often Watch checks the pods for changes
+	watchInterval = 1 * time.Second
+
+	// statsInterval is how often StreamStats sends the container stats
+	statsInterval = 1 * time.Second
+
+	// diskUsageInterval is how often StreamDiskUsage sends the container disk usage by default.
+	// Usage of the bind mounts is resolved by walking the directories, so the interval is longer than for stats.
+	diskUsageInterval = 10 * time.Second
+
+	// stopPollInterval is how often the container state is checked while waiting it to stop
+	stopPollInterval = 100 * time.Millisecond
+
+	// copyChunkSize is the maximum size of single archive chunk message in CopyTo and CopyFrom
+	copyChunkSize = 32 * 1024
 )
 
 // Server implements the GRPC API for the eli
@@ -32,26 +61,350 @@ type Server struct {
 	client   runtime.Client
 	grpc     *grpc.Server
 	listen   string
+	creates  createRequests
+	locks    podLocks
+	// volumesDir is the node directory of the pod volumes, which Delete with cascade removes
+	volumesDir string
 }
 
 // Info is Node service Info implementation
 func (s *Server) Info(context context.Context, req *node.InfoRequest) (*node.InfoResponse, error) {
+	info := mapping.MapInfoToAPIModel(s.resolver.GetInfo())
+	info.ApiVersion = APIVersion
+
+	runtimeVersion, err := s.client.GetVersion()
+	if err != nil {
+		log.Warnf("Failed to resolve container runtime version: %s", err)
+	}
+	info.RuntimeVersion = runtimeVersion
+
 	return &node.InfoResponse{
-		Info: mapping.MapInfoToAPIModel(s.resolver.GetInfo()),
+		Info: info,
+	}, nil
+}
+
+// ResolveImage is 'node' service ResolveImage implementation
+func (s *Server) ResolveImage(context context.Context, req *node.ResolveImageRequest) (*node.ResolveImageResponse, error) {
+	ref, err := image.ParseRef(req.Image)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	if ref.IsPinned() {
+		return &node.ResolveImageResponse{Image: req.Image, Digest: ref.Digest.String()}, nil
+	}
+
+	dgst, err := s.client.ResolveImage(resolvableImage(ref))
+	if err != nil {
+		return nil, err
+	}
+	parsed, err := digest.Parse(dgst)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Registry returned invalid digest [%s] for image [%s]", dgst, req.Image)
+	}
+	return &node.ResolveImageResponse{
+		Image:  ref.WithDigest(parsed).String(),
+		Digest: dgst,
 	}, nil
 }
 
+// Events is 'node' service Events implementation
+// Streams the runtime events until the client cancels.
+func (s *Server) Events(req *node.EventsRequest, server node.Node_EventsServer) error {
+	types := map[runtime.EventType]bool{}
+	for _, t := range req.Types {
+		if !isRuntimeEventType(runtime.EventType(t)) {
+			return status.Errorf(codes.InvalidArgument, "Invalid event type [%s], must be one of %v", t, runtime.EventTypes)
+		}
+		types[runtime.EventType(t)] = true
+	}
+
+	log.Debugf("Stream events in namespace [%s]", req.Namespace)
+	return s.client.Events(req.Namespace, server.Context().Done(), func(event runtime.Event) error {
+		if len(types) > 0 && !types[event.Type] {
+			return nil
+		}
+		return server.Send(mapping.MapEventToAPIModel(event))
+	})
+}
+
+// Prune is 'node' service Prune implementation
+// Removes the finished pods and then the images which no container use anymore.
+func (s *Server) Prune(context context.Context, req *node.PruneRequest) (*node.PruneResponse, error) {
+	selector, err := model.ParseSelector(req.LabelSelector)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	before := time.Now().Add(-time.Duration(req.KeepFor))
+
+	all, err := s.client.GetPods(req.Namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &node.PruneResponse{}
+	// With selector, only the images of the pruned pods are removed
+	var candidates map[string]bool
+	if len(selector) > 0 {
+		candidates = map[string]bool{}
+	}
+	for _, pod := range all {
+		if !selector.Matches(pod.Metadata.Labels) || !isPodFinished(pod, before) {
+			continue
+		}
+		removed, err := s.prunePod(req.Namespace, pod.Metadata.Name, before, resp)
+		if err != nil {
+			return nil, err
+		}
+		if candidates != nil {
+			for _, status := range removed {
+				candidates[status.Image] = true
+			}
+		}
+	}
+
+	remaining, err := s.client.GetPods(req.Namespace)
+	if err != nil {
+		return nil, err
+	}
+	images, err := s.client.GetImages(req.Namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	imagesBefore := before
+	if grace := time.Now().Add(-pruneImageGracePeriod); grace.Before(imagesBefore) {
+		imagesBefore = grace
+	}
+	for _, image := range getUnusedImages(images, remaining, candidates, imagesBefore) {
+		if err := s.client.DeleteImage(req.Namespace, image.Name); err != nil {
+			if runtime.IsNotFound(err) {
+				continue
+			}
+			return nil, err
+		}
+		log.Debugf("Pruned image [%s] in namespace [%s]", image.Name, req.Namespace)
+		resp.Images = append(resp.Images, image.Name)
+		resp.ReclaimedBytes += uint64(image.Size)
+	}
+	return resp, nil
+}
+
+// Build is 'node' service Build implementation
+// Receives the build context tar archive, builds the image and streams the build output back to the client.
+// The last message carries the built image digest.
+func (s *Server) Build(server node.Node_BuildServer) error {
+	req, err := server.Recv()
+	if err != nil {
+		return err
+	}
+
+	if req.Namespace == "" {
+		return status.Errorf(codes.InvalidArgument, "You must define namespace in the first build message")
+	}
+	if len(req.Tags) == 0 {
+		return status.Errorf(codes.InvalidArgument, "You must define at least one image tag in the first build message")
+	}
+
+	opts := runtime.BuildOptions{
+		Tags:      req.Tags,
+		BuildArgs: req.BuildArgs,
+		Target:    req.Target,
+	}
+	log.Debugf("Build image %v to namespace [%s]", req.Tags, req.Namespace)
+	dgst, err := s.client.BuildImage(req.Namespace, newBuildContextReader(server, req.Data), opts, server.Context().Done(), func(line runtime.BuildLog) error {
+		return server.Send(&node.BuildResponse{Line: line.Line, Error: line.Error})
+	})
+	if err != nil {
+		return err
+	}
+	return server.Send(&node.BuildResponse{Digest: dgst})
+}
+
+// buildContextReader is io.Reader implementation what reads build context archive chunks from RPC stream
+type buildContextReader struct {
+	buffer bytes.Buffer
+	stream node.Node_BuildServer
+}
+
+// newBuildContextReader creates new buildContextReader, first is the data of already received first message
+func newBuildContextReader(stream node.Node_BuildServer, first []byte) *buildContextReader {
+	reader := &buildContextReader{stream: stream}
+	reader.buffer.Write(first)
+	return reader
+}
+
+func (r *buildContextReader) Read(p []byte) (int, error) {
+	for r.buffer.Len() == 0 {
+		req, err := r.stream.Recv()
+		if err != nil {
+			return 0, err
+		}
+		r.buffer.Write(req.GetData())
+	}
+	return r.buffer.Read(p)
+}
+
+// TagImage is 'node' service TagImage implementation
+func (s *Server) TagImage(context context.Context, req *node.TagImageRequest) (*node.TagImageResponse, error) {
+	if req.Source == "" || req.Target == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "You must define source and target image")
+	}
+	if err := s.client.TagImage(req.Namespace, req.Source, req.Target); err != nil {
+		return nil, err
+	}
+	return &node.TagImageResponse{}, nil
+}
+
+// PushImage is 'node' service PushImage implementation
+// Pushes the image to the registry and streams the upload progress back to the client until the push completes.
+func (s *Server) PushImage(req *node.PushImageRequest, server node.Node_PushImageServer) error {
+	if req.Image == "" {
+		return status.Errorf(codes.InvalidArgument, "You must define image to push")
+	}
+
+	fetch := progress.NewImageFetch("", req.Image)
+	auth := runtime.RegistryAuth{Username: req.Username, Password: req.Password}
+	result := make(chan error, 1)
+	go func() {
+		result <- s.client.PushImage(req.Namespace, req.Image, auth, fetch, server.Context().Done())
+	}()
+
+	for {
+		select {
+		case err := <-result:
+			if err != nil {
+				fetch.SetToFailed()
+			}
+			images := mapping.MapImageFetchProgressToAPIModel([]*progress.ImageFetch{fetch})
+			if sendErr := server.Send(&node.PushImageResponse{Image: images[0]}); sendErr != nil && err == nil {
+				return sendErr
+			}
+			return err
+		case <-time.After(100 * time.Millisecond):
+			images := mapping.MapImageFetchProgressToAPIModel([]*progress.ImageFetch{fetch})
+			if err := server.Send(&node.PushImageResponse{Image: images[0]}); err != nil {
+				log.Warnf("Error while sending push image status back to client: %s", err)
+			}
+		}
+	}
+}
+
+// prunePod removes the pod containers if the pod is still finished, it might have been restarted while waiting the lock
+func (s *Server) prunePod(namespace, name string, before time.Time, resp *node.PruneResponse) ([]model.ContainerStatus, error) {
+	unlock := s.locks.lock(namespace, name)
+	defer unlock()
+
+	pod, err := s.client.GetPod(namespace, name)
+	if runtime.IsNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if !isPodFinished(pod, before) {
+		return nil, nil
+	}
+
+	removed := []model.ContainerStatus{}
+	for _, status := range append(append([]model.ContainerStatus{}, pod.Status.InitContainerStatuses...), pod.Status.ContainerStatuses...) {
+		size, err := s.client.GetContainerDiskUsage(namespace, status.ContainerID)
+		if err != nil {
+			log.Warnf("Failed to resolve container [%s] disk usage: %s", status.ContainerID, err)
+		}
+		if _, err := s.client.StopContainer(namespace, status.ContainerID); err != nil {
+			return removed, errors.Wrapf(err, "Failed to remove container [%s] of pod [%s]", status.ContainerID, name)
+		}
+		log.Debugf("Pruned container [%s] of pod [%s] in namespace [%s]", status.ContainerID, name, namespace)
+		resp.Containers = append(resp.Containers, status.ContainerID)
+		resp.ReclaimedBytes += uint64(size)
+		removed = append(removed, status)
+	}
+	return removed, nil
+}
+
+func isRuntimeEventType(eventType runtime.EventType) bool {
+	for _, t := range runtime.EventTypes {
+		if t == eventType {
+			return true
+		}
+	}
+	return false
+}
+
 // Create is 'pods' service Create implementation
+// With dry run, only validates the pod and sends back the pod what would be created.
+// If the request has idempotency key, retried create with the same key and pod waits the original create
+// and sends back its result instead of failing because the pod already exist.
 func (s *Server) Create(req *pods.CreatePodRequest, server pods.Pods_CreateServer) error {
+	key := getIdempotencyKey(server.Context())
+	if key == "" || req.DryRun {
+		_, err := s.create(req, server)
+		return err
+	}
+
+	var (
+		namespace = req.GetPod().GetMetadata().GetNamespace()
+		name      = req.GetPod().GetMetadata().GetName()
+		entryKey  = fmt.Sprintf("%s/%s/%s", namespace, name, key)
+	)
+	for {
+		entry, isNew := s.creates.begin(entryKey)
+		if isNew {
+			images, err := s.create(req, server)
+			s.creates.finish(entryKey, entry, images, err)
+			return err
+		}
+
+		log.Debugf("Pod [%s] create with idempotency key [%s] already received, wait the result", name, key)
+		select {
+		case <-entry.done:
+		case <-server.Context().Done():
+			return server.Context().Err()
+		}
+		if entry.err != nil {
+			// The original create failed, try again
+			continue
+		}
+		if _, err := s.client.GetPod(namespace, name); runtime.IsNotFound(err) {
+			// The pod has been deleted since, create it again
+			s.creates.forget(entryKey, entry)
+			continue
+		}
+		return server.Send(&pods.CreatePodStreamResponse{Images: entry.images})
+	}
+}
+
+// create pulls the images and creates the pod containers, return the final image pull progress
+func (s *Server) create(req *pods.CreatePodRequest, server pods.Pods_CreateServer) ([]*pods.ImageFetch, error) {
 	pod := mapping.MapPodToInternalModel(req.Pod)
 	var (
 		done       = make(chan struct{})
 		progresses = []*progress.ImageFetch{}
+		// mu guards the progresses, the update loop sends them while the images get pulled
+		mu sync.Mutex
 	)
 	defer close(done)
+	getProgresses := func() []*pods.ImageFetch {
+		mu.Lock()
+		defer mu.Unlock()
+		return mapping.MapImageFetchProgressToAPIModel(progresses)
+	}
+
+	if !req.DryRun {
+		unlock := s.locks.lock(pod.Metadata.Namespace, pod.Metadata.Name)
+		defer unlock()
+	}
 
 	if err := s.ensurePodNotExist(pod.Metadata.Namespace, pod.Metadata.Name); err != nil {
-		return errors.Wrapf(err, "Cannot create pod [%s]", pod.Metadata.Name)
+		return nil, errors.Wrapf(err, "Cannot create pod [%s]", pod.Metadata.Name)
+	}
+
+	if req.DryRun {
+		if err := model.Validate([]model.Pod{pod}); err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "Invalid pod [%s]: %s", pod.Metadata.Name, err)
+		}
+		log.Debugf("Dry run, pod [%s] not created", pod.Metadata.Name)
+		return nil, server.Send(&pods.CreatePodStreamResponse{Pod: mapping.MapPodToAPIModel(pod)})
 	}
 
 	go func() {
@@ -59,14 +412,14 @@ func (s *Server) Create(req *pods.CreatePodRequest, server pods.Pods_CreateServe
 			select {
 			case <-done:
 				// Send last update
-				images := mapping.MapImageFetchProgressToAPIModel(progresses)
+				images := getProgresses()
 
 				if err := server.Send(&pods.CreatePodStreamResponse{Images: images}); err != nil {
 					log.Warnf("Error while sending last create pod status back to client: %s", err)
 				}
 				return // End update loop
 			case <-time.After(100 * time.Millisecond):
-				images := mapping.MapImageFetchProgressToAPIModel(progresses)
+				images := getProgresses()
 
 				if err := server.Send(&pods.CreatePodStreamResponse{Images: images}); err != nil {
 					log.Warnf("Error while sending create pod status back to client: %s", err)
@@ -75,24 +428,26 @@ func (s *Server) Create(req *pods.CreatePodRequest, server pods.Pods_CreateServe
 		}
 	}()
 
-	for _, container := range pod.Spec.Containers {
+	for _, container := range append(append([]model.Container{}, pod.Spec.InitContainers...), pod.Spec.Containers...) {
 		progress := progress.NewImageFetch(container.Name, container.Image)
+		mu.Lock()
 		progresses = append(progresses, progress)
+		mu.Unlock()
 
-		if err := s.client.PullImage(pod.Metadata.Namespace, container.Image, progress); err != nil {
+		if err := s.client.PullImage(pod.Metadata.Namespace, container.Image, progress, server.Context().Done()); err != nil {
 			progress.SetToFailed()
-			return errors.Wrapf(err, "Failed to pull image [%s]", container.Image)
+			return nil, errors.Wrapf(err, "Failed to pull image [%s]", container.Image)
 		}
 		progress.AllDone()
 
 		_, err := s.client.CreateContainer(pod, container)
 		if err != nil {
-			return errors.Wrapf(err, "Failed to create container [%s]", container.Name)
+			return nil, errors.Wrapf(err, "Failed to create container [%s]", container.Name)
 		}
 		log.Debugf("Container [%s] created", container.Name)
 	}
 
-	return nil
+	return getProgresses(), nil
 }
 
 func (s *Server) ensurePodNotExist(namespace, name string) error {
@@ -103,7 +458,7 @@ func (s *Server) ensurePodNotExist(namespace, name string) error {
 		}
 		return err
 	}
-	return fmt.Errorf("Pod [%s] in namespace [%s] already exist", name, namespace)
+	return runtime.ErrWithMessagef(runtime.ErrAlreadyExists, "Pod [%s] in namespace [%s] already exist", name, namespace)
 }
 
 // Start is 'pods' service Start implementation
@@ -113,11 +468,27 @@ func (s *Server) Start(context context.Context, req *pods.StartPodRequest) (*pod
 		return nil, errors.Wrapf(err, "Failed to find containers to start for pod [%s] in namespace [%s]", req.Name, req.Namespace)
 	}
 
-	iosets, err := buildContainerIOSets(pod.Metadata.Name, pod.Spec.Containers)
+	iosets, err := buildContainerIOSets(pod.Metadata.Name, append(append([]model.Container{}, pod.Spec.InitContainers...), pod.Spec.Containers...))
 	if err != nil {
 		return nil, errors.Wrapf(err, "Cannot start pod [%s], error while building IO sets for containers", req.Name)
 	}
 
+	// Only the first pending init container gets started, the lifecycle controller starts the next ones
+	// and the containers when the init containers complete
+	if pending := model.GetPendingInitContainer(pod); pending >= 0 {
+		status := pod.Status.InitContainerStatuses[pending]
+		started, err := s.client.StartContainer(pod.Metadata.Namespace, status.ContainerID, *iosets[status.Name])
+		if err != nil {
+			return nil, errors.Wrapf(err, "Failed to start init container [%s]", status.Name)
+		}
+		log.Debugf("Init container [%s] started", status.Name)
+		pod.Status.InitContainerStatuses[pending] = started
+
+		return &pods.StartPodResponse{
+			Pod: mapping.MapPodToAPIModel(pod),
+		}, nil
+	}
+
 	statuses := []model.ContainerStatus{}
 	for _, status := range pod.Status.ContainerStatuses {
 		status, err := s.client.StartContainer(pod.Metadata.Namespace, status.ContainerID, *iosets[status.Name])
@@ -151,7 +522,7 @@ func buildContainerIOSets(podName string, containers []model.Container) (map[str
 		if container.Pipe != nil {
 			target := iosets[container.Pipe.Stdout.Stdin.Name]
 			if target == nil {
-				return nil, fmt.Errorf("Invalid pipe definition, target container with name [%s] not found", container.Pipe.Stdout.Stdin.Name)
+				return nil, status.Errorf(codes.InvalidArgument, "Invalid pipe definition, target container with name [%s] not found", container.Pipe.Stdout.Stdin.Name)
 			}
 			ioset.PipeStdoutTo(target)
 		}
@@ -159,121 +530,1030 @@ func buildContainerIOSets(podName string, containers []model.Container) (map[str
 	return iosets, nil
 }
 
-// Delete is 'pods' service Delete implementation
-func (s *Server) Delete(context context.Context, req *pods.DeletePodRequest) (*pods.DeletePodResponse, error) {
+// Restart is 'pods' service Restart implementation
+// Stops all pod containers gracefully and creates them again, the pod metadata stay the same
+func (s *Server) Restart(context context.Context, req *pods.RestartPodRequest) (*pods.RestartPodResponse, error) {
+	unlock := s.locks.lock(req.Namespace, req.Name)
+	defer unlock()
+
 	pod, err := s.client.GetPod(req.Namespace, req.Name)
 	if err != nil {
-		return nil, errors.Wrapf(err, "Cannot fetch pod containers, cannot delete pod [%s]", req.Name)
+		return nil, errors.Wrapf(err, "Cannot fetch pod containers, cannot restart pod [%s]", req.Name)
 	}
 
-	statuses := []model.ContainerStatus{}
 	for _, containerStatus := range pod.Status.ContainerStatuses {
-		status, err := s.client.StopContainer(req.Namespace, containerStatus.ContainerID)
+		if _, err := s.stopContainer(req.Namespace, containerStatus.ContainerID, time.Duration(req.GracePeriod)); err != nil {
+			return nil, errors.Wrapf(err, "Error while stopping container [%s]", containerStatus.Name)
+		}
+		log.Debugf("Container [%s] stopped", containerStatus.Name)
+	}
+
+	iosets, err := buildContainerIOSets(pod.Metadata.Name, pod.Spec.Containers)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Cannot restart pod [%s], error while building IO sets for containers", req.Name)
+	}
+
+	for _, container := range pod.Spec.Containers {
+		status, err := s.client.CreateContainer(pod, container)
 		if err != nil {
-			return nil, errors.Wrapf(err, "Error while stopping container [%s]", containerStatus.ContainerID)
+			return nil, errors.Wrapf(err, "Failed to create container [%s]", container.Name)
 		}
-		statuses = append(statuses, status)
+		if _, err := s.client.StartContainer(req.Namespace, status.ContainerID, *iosets[container.Name]); err != nil {
+			return nil, errors.Wrapf(err, "Failed to start container [%s]", container.Name)
+		}
+		log.Debugf("Container [%s] created and started", container.Name)
 	}
 
-	pod.Status.ContainerStatuses = statuses
+	restarted, err := s.client.GetPod(req.Namespace, req.Name)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Failed to fetch restarted pod [%s]", req.Name)
+	}
 
-	return &pods.DeletePodResponse{
-		Pod: mapping.MapPodToAPIModel(pod),
+	return &pods.RestartPodResponse{
+		Pod: mapping.MapPodToAPIModel(restarted),
 	}, nil
 }
 
-// List is 'pods' service List implementation
-func (s *Server) List(context context.Context, req *pods.ListPodsRequest) (*pods.ListPodsResponse, error) {
-	p, err := s.client.GetPods(req.Namespace)
+// UpdateLabels is 'pods' service UpdateLabels implementation
+// Updates the labels of all pod containers without recreating them, the pod keeps running.
+func (s *Server) UpdateLabels(context context.Context, req *pods.UpdatePodLabelsRequest) (*pods.UpdatePodLabelsResponse, error) {
+	unlock := s.locks.lock(req.Namespace, req.Name)
+	defer unlock()
+
+	pod, err := s.client.GetPod(req.Namespace, req.Name)
 	if err != nil {
-		return nil, err
+		return nil, errors.Wrapf(err, "Cannot update pod [%s] labels", req.Name)
 	}
-	return &pods.ListPodsResponse{
-		Pods: mapping.MapPodsToAPIModel(p),
+
+	labels := req.Labels
+	if req.Merge {
+		labels = map[string]string{}
+		for key, value := range pod.Metadata.Labels {
+			labels[key] = value
+		}
+		for key, value := range req.Labels {
+			labels[key] = value
+		}
+		for _, key := range req.Remove {
+			delete(labels, key)
+		}
+	}
+
+	if err := s.client.SetPodLabels(req.Namespace, req.Name, labels); err != nil {
+		return nil, errors.Wrapf(err, "Failed to update pod [%s] labels", req.Name)
+	}
+	log.Debugf("Pod [%s] labels updated to %v", req.Name, labels)
+
+	updated, err := s.client.GetPod(req.Namespace, req.Name)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Failed to fetch updated pod [%s]", req.Name)
+	}
+	return &pods.UpdatePodLabelsResponse{
+		Pod: mapping.MapPodToAPIModel(updated),
 	}, nil
 }
 
-// Exec connects to process in container and streams stdout and stderr outputs to client
-func (s *Server) Exec(server containers.Containers_ExecServer) error {
-	md, ok := metadata.FromIncomingContext(server.Context())
-	if !ok {
-		return fmt.Errorf("Incoming Exec request don't have metadata. You must provide 'namespace', 'container', 'execid' and 'command' through metadata")
+// Rename is 'pods' service Rename implementation
+// Moves the pod containers to the new name without recreating them, fails if pod with the new name already exist.
+func (s *Server) Rename(context context.Context, req *pods.RenamePodRequest) (*pods.RenamePodResponse, error) {
+	if problem := validatePodName(req.NewName); problem != "" {
+		return nil, status.Errorf(codes.InvalidArgument, "Cannot rename pod [%s]: %s", req.Name, problem)
 	}
-	log.Debugf("Received metadata: %s", md)
-	var (
-		execID      = getMetadataValue(md, "execid")
-		namespace   = getMetadataValue(md, "namespace")
-		containerID = getMetadataValue(md, "container")
-		args        = strings.Split(getMetadataValue(md, "args"), " ")
-		tty         = false
-	)
-	tty, _ = strconv.ParseBool(getMetadataValue(md, "tty"))
 
-	if namespace == "" {
-		return fmt.Errorf("You must define 'namespace' metadata")
+	// Lock in name order so that two renames in opposite directions cannot deadlock
+	first, second := req.Name, req.NewName
+	if second < first {
+		first, second = second, first
+	}
+	unlockFirst := s.locks.lock(req.Namespace, first)
+	defer unlockFirst()
+	if first != second {
+		unlockSecond := s.locks.lock(req.Namespace, second)
+		defer unlockSecond()
 	}
 
-	if containerID == "" {
-		return fmt.Errorf("You must define 'container' metadata")
+	if _, err := s.client.GetPod(req.Namespace, req.Name); err != nil {
+		return nil, errors.Wrapf(err, "Cannot rename pod [%s]", req.Name)
+	}
+	if req.NewName == req.Name {
+		return nil, status.Errorf(codes.InvalidArgument, "Cannot rename pod [%s], the new name is the same", req.Name)
+	}
+	if err := s.ensurePodNotExist(req.Namespace, req.NewName); err != nil {
+		return nil, errors.Wrapf(err, "Cannot rename pod [%s]", req.Name)
 	}
 
-	if len(args) == 0 {
-		return fmt.Errorf("You must define 'args' metadata")
+	if err := s.client.RenamePod(req.Namespace, req.Name, req.NewName); err != nil {
+		return nil, errors.Wrapf(err, "Failed to rename pod [%s] to [%s]", req.Name, req.NewName)
 	}
+	log.Debugf("Pod [%s] renamed to [%s]", req.Name, req.NewName)
 
-	log.Debugf("Execute command [%s](tty: %s) in container [%s] in namespace [%s]", strings.Join(args, " "), tty, containerID, namespace)
-	return s.client.Exec(
-		namespace,
-		containerID,
-		execID,
-		args,
-		tty,
-		runtime.AttachIO{
-			Stdin:  stream.NewReader(server),
-			Stdout: stream.NewWriter(server, false),
-			Stderr: stream.NewWriter(server, true),
-		},
-	)
+	renamed, err := s.client.GetPod(req.Namespace, req.NewName)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Failed to fetch renamed pod [%s]", req.NewName)
+	}
+	return &pods.RenamePodResponse{
+		Pod: mapping.MapPodToAPIModel(renamed),
+	}, nil
 }
 
-// Attach connects to process in container and streams stdout and stderr outputs to client
-func (s *Server) Attach(server containers.Containers_AttachServer) error {
-	md, ok := metadata.FromIncomingContext(server.Context())
-	if !ok {
-		return fmt.Errorf("Incoming attach request don't have metadata. You must provide 'Namespace' and 'ContainerID' through metadata")
+// Cordon is 'pods' service Cordon implementation
+// Marks the pod containers so that the lifecycle controller doesn't restart them, the containers keep running
+// or stay stopped as they are.
+func (s *Server) Cordon(context context.Context, req *pods.CordonPodRequest) (*pods.CordonPodResponse, error) {
+	unlock := s.locks.lock(req.Namespace, req.Name)
+	defer unlock()
+
+	if err := s.client.SetPodCordoned(req.Namespace, req.Name, req.Cordoned); err != nil {
+		return nil, errors.Wrapf(err, "Failed to update pod [%s] cordon", req.Name)
 	}
-	log.Debugf("Received metadata: %s", md)
-	var (
-		namespace   = getMetadataValue(md, "namespace")
-		containerID = getMetadataValue(md, "container")
-	)
+	log.Debugf("Pod [%s] cordoned: %t", req.Name, req.Cordoned)
 
-	if namespace == "" {
-		return fmt.Errorf("You must define 'namespace' metadata")
+	pod, err := s.client.GetPod(req.Namespace, req.Name)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Failed to fetch pod [%s]", req.Name)
 	}
+	return &pods.CordonPodResponse{
+		Pod: mapping.MapPodToAPIModel(pod),
+	}, nil
+}
 
-	if containerID == "" {
-		return fmt.Errorf("You must define 'container' metadata")
+// stopContainer sends SIGTERM to the container and waits the grace period for it to exit
+// before killing it with SIGKILL and removing the container. Zero grace period kills immediately.
+func (s *Server) stopContainer(namespace, id string, gracePeriod time.Duration) (model.ContainerStatus, error) {
+	if gracePeriod > 0 {
+		if err := s.waitContainerStop(namespace, id, gracePeriod); err != nil {
+			log.Warnf("Failed to stop container [%s] gracefully, will force kill. Error: %s", id, err)
+		}
 	}
+	return s.client.StopContainer(namespace, id)
+}
 
-	log.Debugf("Attach to container [%s] in namespace [%s]", containerID, namespace)
-	return s.client.Attach(
-		namespace, containerID,
-		runtime.AttachIO{
-			Stdin:  stream.NewReader(server),
-			Stdout: stream.NewWriter(server, false),
-			Stderr: stream.NewWriter(server, true),
-		},
-	)
+func (s *Server) waitContainerStop(namespace, id string, gracePeriod time.Duration) error {
+	running, err := s.client.IsContainerRunning(namespace, id)
+	if err != nil || !running {
+		return err
+	}
+
+	log.Debugf("Send SIGTERM to container [%s] and wait %s for it to exit", id, gracePeriod)
+	if err := s.client.Signal(namespace, id, syscall.SIGTERM); err != nil {
+		return err
+	}
+
+	deadline := time.Now().Add(gracePeriod)
+	for time.Now().Before(deadline) {
+		time.Sleep(stopPollInterval)
+		running, err := s.client.IsContainerRunning(namespace, id)
+		if err != nil || !running {
+			return err
+		}
+	}
+
+	log.Debugf("Container [%s] didn't exit in %s, send SIGKILL", id, gracePeriod)
+	return s.client.Signal(namespace, id, syscall.SIGKILL)
 }
 
-// Signal connects to process in container and send signal to the process
-func (s *Server) Signal(cxt context.Context, req *containers.SignalRequest) (*containers.SignalResponse, error) {
-	err := s.client.Signal(req.Namespace, req.ContainerID, syscall.Signal(req.Signal))
+// Delete is 'pods' service Delete implementation
+// Containers get the request grace period time to exit after SIGTERM before they get killed
+// With dry run, only returns the pod what would be deleted.
+func (s *Server) Delete(context context.Context, req *pods.DeletePodRequest) (*pods.DeletePodResponse, error) {
+	if !req.DryRun {
+		unlock := s.locks.lock(req.Namespace, req.Name)
+		defer unlock()
+	}
+
+	pod, err := s.client.GetPod(req.Namespace, req.Name)
 	if err != nil {
-		return nil, err
+		return nil, errors.Wrapf(err, "Cannot fetch pod containers, cannot delete pod [%s]", req.Name)
 	}
-	return &containers.SignalResponse{}, nil
+
+	if req.DryRun {
+		log.Debugf("Dry run, pod [%s] not deleted", req.Name)
+		resp := &pods.DeletePodResponse{
+			Pod: mapping.MapPodToAPIModel(pod),
+		}
+		if req.Cascade {
+			if resp.RemovedVolumes, resp.RetainedVolumes, err = s.removePodVolumes(pod, true); err != nil {
+				return nil, err
+			}
+		}
+		return resp, nil
+	}
+
+	initStatuses := []model.ContainerStatus{}
+	for _, containerStatus := range pod.Status.InitContainerStatuses {
+		status, err := s.stopContainer(req.Namespace, containerStatus.ContainerID, time.Duration(req.GracePeriod))
+		if err != nil {
+			return nil, errors.Wrapf(err, "Error while stopping init container [%s]", containerStatus.ContainerID)
+		}
+		initStatuses = append(initStatuses, status)
+	}
+
+	statuses := []model.ContainerStatus{}
+	for _, containerStatus := range pod.Status.ContainerStatuses {
+		status, err := s.stopContainer(req.Namespace, containerStatus.ContainerID, time.Duration(req.GracePeriod))
+		if err != nil {
+			return nil, errors.Wrapf(err, "Error while stopping container [%s]", containerStatus.ContainerID)
+		}
+		statuses = append(statuses, status)
+	}
+
+	pod.Status.InitContainerStatuses = initStatuses
+	pod.Status.ContainerStatuses = statuses
+
+	resp := &pods.DeletePodResponse{
+		Pod: mapping.MapPodToAPIModel(pod),
+	}
+	if req.Cascade {
+		// The containers are already removed, so the volumes are removed last and only if no other pod use them
+		if resp.RemovedVolumes, resp.RetainedVolumes, err = s.removePodVolumes(pod, false); err != nil {
+			return nil, err
+		}
+	}
+	return resp, nil
+}
+
+// Update is 'pods' service Update implementation
+// Recreates only the containers which spec have changed, other containers keep running.
+// If the pod metadata has resource version, fails with Aborted when the pod has changed since that version.
+func (s *Server) Update(context context.Context, req *pods.UpdatePodRequest) (*pods.UpdatePodResponse, error) {
+	desired := mapping.MapPodToInternalModel(req.Pod)
+	namespace := desired.Metadata.Namespace
+
+	unlock := s.locks.lock(namespace, desired.Metadata.Name)
+	defer unlock()
+
+	current, err := s.client.GetPod(namespace, desired.Metadata.Name)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Cannot update pod [%s]", desired.Metadata.Name)
+	}
+
+	expected := req.GetPod().GetMetadata().GetResourceVersion()
+	if version := model.GetPodVersion(current); expected != "" && expected != version {
+		return nil, status.Errorf(codes.Aborted, "Cannot update pod [%s], it has been changed since version [%s], current version is [%s]", desired.Metadata.Name, expected, version)
+	}
+
+	// The recreated containers stay cordoned
+	desired.Status.Cordoned = current.Status.Cordoned
+
+	update := planPodUpdate(current, desired)
+	create := append(append([]string{}, update.added...), update.recreated...)
+
+	containers := map[string]model.Container{}
+	for _, container := range desired.Spec.Containers {
+		containers[container.Name] = container
+	}
+
+	// Pull images before stopping anything to keep the downtime short
+	for _, name := range create {
+		image := containers[name].Image
+		if err := s.client.PullImage(namespace, image, progress.NewImageFetch(name, image), context.Done()); err != nil {
+			return nil, errors.Wrapf(err, "Failed to pull image [%s]", image)
+		}
+	}
+
+	containerIDs := map[string]string{}
+	for _, status := range current.Status.ContainerStatuses {
+		containerIDs[status.Name] = status.ContainerID
+	}
+
+	for _, name := range append(append([]string{}, update.removed...), update.recreated...) {
+		if _, err := s.client.StopContainer(namespace, containerIDs[name]); err != nil {
+			return nil, errors.Wrapf(err, "Error while stopping container [%s]", name)
+		}
+		log.Debugf("Container [%s] stopped", name)
+	}
+
+	iosets, err := buildContainerIOSets(desired.Metadata.Name, desired.Spec.Containers)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Cannot update pod [%s], error while building IO sets for containers", desired.Metadata.Name)
+	}
+
+	for _, name := range create {
+		status, err := s.client.CreateContainer(desired, containers[name])
+		if err != nil {
+			return nil, errors.Wrapf(err, "Failed to create container [%s]", name)
+		}
+		if _, err := s.client.StartContainer(namespace, status.ContainerID, *iosets[name]); err != nil {
+			return nil, errors.Wrapf(err, "Failed to start container [%s]", name)
+		}
+		log.Debugf("Container [%s] created and started", name)
+	}
+
+	// Labels are not part of the container spec hash, so the kept containers still have the old labels
+	if !labelsEqual(current.Metadata.Labels, desired.Metadata.Labels) {
+		if err := s.client.SetPodLabels(namespace, desired.Metadata.Name, desired.Metadata.Labels); err != nil {
+			return nil, errors.Wrapf(err, "Failed to update pod [%s] labels", desired.Metadata.Name)
+		}
+		log.Debugf("Pod [%s] labels updated to %v", desired.Metadata.Name, desired.Metadata.Labels)
+	}
+
+	updated, err := s.client.GetPod(namespace, desired.Metadata.Name)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Failed to fetch updated pod [%s]", desired.Metadata.Name)
+	}
+
+	return &pods.UpdatePodResponse{
+		Pod:       mapping.MapPodToAPIModel(updated),
+		Added:     update.added,
+		Removed:   update.removed,
+		Restarted: update.recreated,
+	}, nil
+}
+
+// List is 'pods' service List implementation
+// The pods are sorted by name, and if the page size is given, returned in pages
+func (s *Server) List(context context.Context, req *pods.ListPodsRequest) (*pods.ListPodsResponse, error) {
+	selector, err := model.ParseSelector(req.LabelSelector)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	all, err := s.client.GetPods(req.Namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	selected := []model.Pod{}
+	for _, pod := range all {
+		if selector.Matches(pod.Metadata.Labels) {
+			selected = append(selected, pod)
+		}
+	}
+
+	page, nextPageToken, err := paginatePods(selected, int(req.PageSize), req.PageToken)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	return &pods.ListPodsResponse{
+		Pods:          mapping.MapPodsToAPIModel(page),
+		NextPageToken: nextPageToken,
+	}, nil
+}
+
+// ListNamespaces is 'pods' service ListNamespaces implementation
+func (s *Server) ListNamespaces(context context.Context, req *pods.ListNamespacesRequest) (*pods.ListNamespacesResponse, error) {
+	namespaces, err := s.client.GetNamespaces()
+	if err != nil {
+		return nil, err
+	}
+
+	return &pods.ListNamespacesResponse{
+		Namespaces: namespaces,
+	}, nil
+}
+
+// CreateNamespace is 'pods' service CreateNamespace implementation
+func (s *Server) CreateNamespace(context context.Context, req *pods.CreateNamespaceRequest) (*pods.CreateNamespaceResponse, error) {
+	if !model.IsValidNamespace(req.Name) {
+		return nil, status.Errorf(codes.InvalidArgument, "Invalid namespace name [%s], must be alphanumeric and can contain dashes", req.Name)
+	}
+
+	if err := s.client.CreateNamespace(req.Name); err != nil {
+		return nil, err
+	}
+	return &pods.CreateNamespaceResponse{}, nil
+}
+
+// Watch is 'pods' service Watch implementation
+// Sends Added event for each existing pod and then checks the pods periodically and sends the changes
+func (s *Server) Watch(req *pods.WatchPodsRequest, server pods.Pods_WatchServer) error {
+	ticker := time.NewTicker(watchInterval)
+	defer ticker.Stop()
+
+	previous := map[string]*pods.Pod{}
+	for {
+		list, err := s.client.GetPods(req.Namespace)
+		if err != nil {
+			return errors.Wrapf(err, "Failed to list pods in namespace [%s] for watching", req.Namespace)
+		}
+
+		current := map[string]*pods.Pod{}
+		for _, pod := range list {
+			current[pod.Metadata.Name] = mapping.MapPodToAPIModel(pod)
+		}
+
+		for _, event := range diffPods(previous, current) {
+			if err := server.Send(&pods.WatchPodsStreamResponse{Type: string(event.Type), Pod: event.Pod}); err != nil {
+				return err
+			}
+		}
+		previous = current
+
+		select {
+		case <-server.Context().Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// Exec connects to process in container and streams stdout and stderr outputs to client
+func (s *Server) Exec(server containers.Containers_ExecServer) error {
+	md, ok := metadata.FromIncomingContext(server.Context())
+	if !ok {
+		return status.Errorf(codes.InvalidArgument, "Incoming Exec request don't have metadata. You must provide 'namespace', 'container', 'execid' and 'command' through metadata")
+	}
+	log.Debugf("Received metadata: %s", md)
+	var (
+		execID      = getMetadataValue(md, "execid")
+		namespace   = getMetadataValue(md, "namespace")
+		containerID = getMetadataValue(md, "container")
+		args        = strings.Split(getMetadataValue(md, "args"), " ")
+		tty         = false
+	)
+	tty, _ = strconv.ParseBool(getMetadataValue(md, "tty"))
+
+	if namespace == "" {
+		return status.Errorf(codes.InvalidArgument, "You must define 'namespace' metadata")
+	}
+
+	if containerID == "" {
+		return status.Errorf(codes.InvalidArgument, "You must define 'container' metadata")
+	}
+
+	if len(args) == 0 {
+		return status.Errorf(codes.InvalidArgument, "You must define 'args' metadata")
+	}
+
+	log.Debugf("Execute command [%s](tty: %t) in container [%s] in namespace [%s]", strings.Join(args, " "), tty, containerID, namespace)
+	exitCode, err := s.client.Exec(
+		namespace,
+		containerID,
+		execID,
+		args,
+		tty,
+		newStreamAttachIO(server),
+	)
+	if err != nil {
+		return err
+	}
+
+	server.SetTrailer(metadata.Pairs("exitcode", strconv.Itoa(exitCode)))
+	return nil
+}
+
+// Attach connects to process in container and streams stdout and stderr outputs to client
+func (s *Server) Attach(server containers.Containers_AttachServer) error {
+	md, ok := metadata.FromIncomingContext(server.Context())
+	if !ok {
+		return status.Errorf(codes.InvalidArgument, "Incoming attach request don't have metadata. You must provide 'Namespace' and 'ContainerID' through metadata")
+	}
+	log.Debugf("Received metadata: %s", md)
+	var (
+		namespace   = getMetadataValue(md, "namespace")
+		containerID = getMetadataValue(md, "container")
+	)
+
+	if namespace == "" {
+		return status.Errorf(codes.InvalidArgument, "You must define 'namespace' metadata")
+	}
+
+	if containerID == "" {
+		return status.Errorf(codes.InvalidArgument, "You must define 'container' metadata")
+	}
+
+	attachIO := newStreamAttachIO(server)
+	// Older clients don't tell, so stdin is attached unless the client explicitly don't want it
+	if getMetadataValue(md, "stdin") == "false" {
+		attachIO.Stdin = nil
+		attachIO.Resize = nil
+	}
+
+	// The output still needs to be read from the container, just not sent to the client
+	switch streams := getMetadataValue(md, "streams"); streams {
+	case "":
+	case "stdout":
+		attachIO.Stderr = ioutil.Discard
+	case "stderr":
+		attachIO.Stdout = ioutil.Discard
+	default:
+		return status.Errorf(codes.InvalidArgument, "Invalid 'streams' metadata [%s], must be stdout, stderr or empty", streams)
+	}
+
+	log.Debugf("Attach to container [%s] in namespace [%s]", containerID, namespace)
+	detach := newDetachableAttach(&attachIO)
+	errc := make(chan error, 1)
+	go func() {
+		errc <- s.client.Attach(namespace, containerID, attachIO)
+	}()
+
+	select {
+	case err := <-errc:
+		return err
+	case <-detach.detached:
+		log.Debugf("Client detached from container [%s] in namespace [%s]", containerID, namespace)
+		return nil
+	}
+}
+
+// detachableAttach ends the attach when the client detaches. The runtime keeps following the container
+// output until the next write, so the writes fail after the detach instead of sending to the finished stream.
+type detachableAttach struct {
+	detached chan struct{}
+	once     sync.Once
+	mu       sync.Mutex
+}
+
+// newDetachableAttach wraps the AttachIO stdin and output writers
+func newDetachableAttach(attachIO *runtime.AttachIO) *detachableAttach {
+	d := &detachableAttach{detached: make(chan struct{})}
+	if attachIO.Stdin != nil {
+		attachIO.Stdin = &detachReader{attachIO.Stdin, d}
+	}
+	attachIO.Stdout = &detachWriter{attachIO.Stdout, d}
+	attachIO.Stderr = &detachWriter{attachIO.Stderr, d}
+	return d
+}
+
+func (d *detachableAttach) detach() {
+	d.once.Do(func() {
+		d.mu.Lock()
+		defer d.mu.Unlock()
+		close(d.detached)
+	})
+}
+
+type detachReader struct {
+	io.Reader
+	attach *detachableAttach
+}
+
+func (r *detachReader) Read(p []byte) (int, error) {
+	n, err := r.Reader.Read(p)
+	if err == stream.ErrDetached {
+		r.attach.detach()
+	}
+	return n, err
+}
+
+type detachWriter struct {
+	io.Writer
+	attach *detachableAttach
+}
+
+func (w *detachWriter) Write(p []byte) (int, error) {
+	w.attach.mu.Lock()
+	defer w.attach.mu.Unlock()
+	select {
+	case <-w.attach.detached:
+		return 0, stream.ErrDetached
+	default:
+	}
+	return w.Writer.Write(p)
+}
+
+// streamServer is the bidirectional stdin/stdout stream of Attach and Exec
+type streamServer interface {
+	stream.StdinStreamServer
+	stream.StdoutStreamServer
+}
+
+// newStreamAttachIO creates AttachIO which reads stdin and terminal size changes from the stream and writes output to it
+func newStreamAttachIO(server streamServer) runtime.AttachIO {
+	resize := make(chan runtime.TerminalSize, 1)
+	return runtime.AttachIO{
+		Stdin: stream.NewResizeReader(server, func(size *containers.TerminalSize) {
+			// Only the latest size matters, replace the pending one if not yet handled
+			select {
+			case <-resize:
+			default:
+			}
+			resize <- runtime.TerminalSize{Width: size.Width, Height: size.Height}
+		}),
+		Stdout: stream.NewWriter(server, false),
+		Stderr: stream.NewWriter(server, true),
+		Resize: resize,
+	}
+}
+
+// Signal connects to process in container and send signal to the process
+func (s *Server) Signal(cxt context.Context, req *containers.SignalRequest) (*containers.SignalResponse, error) {
+	err := s.client.Signal(req.Namespace, req.ContainerID, syscall.Signal(req.Signal))
+	if err != nil {
+		return nil, err
+	}
+	return &containers.SignalResponse{}, nil
+}
+
+// SetEnv updates the container environment variables, which take effect when the container gets started next time.
+// With signal, the signal is sent to the container process after the update.
+func (s *Server) SetEnv(context context.Context, req *containers.SetEnvRequest) (*containers.SetEnvResponse, error) {
+	if err := s.client.SetContainerEnv(req.Namespace, req.ContainerID, req.Env); err != nil {
+		return nil, err
+	}
+	log.Debugf("Container [%s] environment updated: %d variables", req.ContainerID, len(req.Env))
+
+	if req.Signal != 0 {
+		if err := s.client.Signal(req.Namespace, req.ContainerID, syscall.Signal(req.Signal)); err != nil {
+			return nil, errors.Wrapf(err, "Container [%s] environment updated, but failed to send signal", req.ContainerID)
+		}
+	}
+	return &containers.SetEnvResponse{}, nil
+}
+
+// Logs streams container output lines to client
+func (s *Server) Logs(req *containers.LogsRequest, server containers.Containers_LogsServer) error {
+	opts := runtime.LogOptions{
+		Follow: req.Follow,
+		Tail:   int(req.Tail),
+	}
+	if req.Since > 0 {
+		opts.Since = time.Unix(0, req.Since)
+	}
+
+	log.Debugf("Stream logs of container [%s] in namespace [%s]", req.ContainerID, req.Namespace)
+	return s.client.Logs(req.Namespace, req.ContainerID, opts, server.Context().Done(), func(line runtime.LogLine) error {
+		return server.Send(&containers.LogsStreamResponse{
+			Lines: mapping.MapLogLinesToAPIModel([]runtime.LogLine{line}),
+		})
+	})
+}
+
+// Stats returns container current resource usage
+func (s *Server) Stats(context context.Context, req *containers.StatsRequest) (*containers.StatsResponse, error) {
+	stats, err := s.client.GetContainerStats(req.Namespace, req.ContainerID)
+	if err != nil {
+		return nil, err
+	}
+	return &containers.StatsResponse{
+		Stats: mapping.MapContainerStatsToAPIModel(stats),
+	}, nil
+}
+
+// Status return the status of single container
+func (s *Server) Status(context context.Context, req *containers.StatusRequest) (*containers.StatusResponse, error) {
+	status, err := s.client.GetContainerStatus(req.Namespace, req.ContainerID)
+	if err != nil {
+		return nil, err
+	}
+	return &containers.StatusResponse{
+		Status: mapping.MapContainerStatusesToAPIModel([]model.ContainerStatus{status})[0],
+	}, nil
+}
+
+// Wait blocks until the container exits and return the exit code, or the recorded exit code if already exited
+func (s *Server) Wait(context context.Context, req *containers.WaitRequest) (*containers.WaitResponse, error) {
+	exitCode, err := s.client.WaitContainer(req.Namespace, req.ContainerID, context.Done())
+	if err != nil {
+		return nil, err
+	}
+	return &containers.WaitResponse{ExitCode: int32(exitCode)}, nil
+}
+
+// UpdateResources changes the running container cgroup limits without restarting it.
+// Memory limit below the current usage is rejected, because the container would get OOM killed right away.
+func (s *Server) UpdateResources(context context.Context, req *containers.UpdateResourcesRequest) (*containers.UpdateResourcesResponse, error) {
+	limits := mapping.MapAPIModelToResourceLimits(req.Limits)
+	if limits.MemoryLimitBytes < 0 || limits.CPUQuota < 0 {
+		return nil, status.Error(codes.InvalidArgument, "Resource limits must not be negative")
+	}
+
+	if limits.MemoryLimitBytes > 0 {
+		stats, err := s.client.GetContainerStats(req.Namespace, req.ContainerID)
+		if err != nil {
+			return nil, err
+		}
+		if uint64(limits.MemoryLimitBytes) < stats.MemoryUsageBytes {
+			return nil, status.Errorf(codes.InvalidArgument,
+				"Memory limit %d bytes is below container [%s] current memory usage %d bytes, the container would get OOM killed",
+				limits.MemoryLimitBytes, req.ContainerID, stats.MemoryUsageBytes)
+		}
+	}
+
+	updated, err := s.client.UpdateContainerResources(req.Namespace, req.ContainerID, limits)
+	if err != nil {
+		return nil, err
+	}
+	log.Debugf("Container [%s] resources updated: %+v", req.ContainerID, updated)
+	return &containers.UpdateResourcesResponse{
+		Limits: mapping.MapResourceLimitsToAPIModel(updated),
+	}, nil
+}
+
+// Inspect return the container details from the runtime, including the OCI spec
+func (s *Server) Inspect(context context.Context, req *containers.InspectRequest) (*containers.InspectResponse, error) {
+	inspect, err := s.client.Inspect(req.Namespace, req.ContainerID)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := mapInspectToAPIModel(inspect)
+	if err != nil {
+		return nil, err
+	}
+	return &containers.InspectResponse{Inspect: result}, nil
+}
+
+// StreamStats sends container resource usage periodically until client cancels or the container stops
+func (s *Server) StreamStats(req *containers.StatsRequest, server containers.Containers_StreamStatsServer) error {
+	ticker := time.NewTicker(statsInterval)
+	defer ticker.Stop()
+
+	for sent := 0; ; sent++ {
+		stats, err := s.client.GetContainerStats(req.Namespace, req.ContainerID)
+		if err != nil {
+			if sent > 0 && errors.Cause(err) == runtime.ErrNotRunning {
+				log.Debugf("Container [%s] stopped, end stats stream", req.ContainerID)
+				return nil
+			}
+			return err
+		}
+
+		if err := server.Send(&containers.StatsResponse{Stats: mapping.MapContainerStatsToAPIModel(stats)}); err != nil {
+			return err
+		}
+
+		select {
+		case <-server.Context().Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// StreamDiskUsage sends container filesystem usage periodically until client cancels or the container stops
+func (s *Server) StreamDiskUsage(req *containers.DiskUsageRequest, server containers.Containers_StreamDiskUsageServer) error {
+	interval := time.Duration(req.Interval)
+	if interval == 0 {
+		interval = diskUsageInterval
+	}
+	if interval < MinDiskUsageInterval {
+		return status.Errorf(codes.InvalidArgument, "Disk usage interval %s must be at least %s", interval, MinDiskUsageInterval)
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for sent := 0; ; sent++ {
+		usage, err := s.client.GetContainerFilesystemUsage(req.Namespace, req.ContainerID)
+		if err != nil {
+			if sent > 0 && (errors.Cause(err) == runtime.ErrNotRunning || errors.Cause(err) == runtime.ErrNotFound) {
+				log.Debugf("Container [%s] stopped, end disk usage stream", req.ContainerID)
+				return nil
+			}
+			return err
+		}
+
+		if err := server.Send(mapping.MapDiskUsageToAPIModel(usage)); err != nil {
+			return err
+		}
+
+		select {
+		case <-server.Context().Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// Top returns the processes running in the container
+func (s *Server) Top(context context.Context, req *containers.TopRequest) (*containers.TopResponse, error) {
+	processes, err := s.client.Top(req.Namespace, req.ContainerID)
+	if err != nil {
+		return nil, err
+	}
+	return &containers.TopResponse{
+		Processes: mapping.MapProcessesToAPIModel(processes),
+	}, nil
+}
+
+// PortForward proxies the connections multiplexed in the stream to the port in the container
+func (s *Server) PortForward(server containers.Containers_PortForwardServer) error {
+	req, err := server.Recv()
+	if err != nil {
+		return err
+	}
+
+	if req.Namespace == "" {
+		return status.Errorf(codes.InvalidArgument, "You must define namespace in the first port forward message")
+	}
+	if req.ContainerID == "" {
+		return status.Errorf(codes.InvalidArgument, "You must define containerID in the first port forward message")
+	}
+	if err := validatePort(int(req.Port)); err != nil {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	log.Debugf("Forward connections to port %d in container [%s] in namespace [%s]", req.Port, req.ContainerID, req.Namespace)
+	return servePortForward(server, func() (net.Conn, error) {
+		return s.client.DialContainer(req.Namespace, req.ContainerID, int(req.Port))
+	})
+}
+
+// CopyTo receives tar archive from the client and extracts it to the container
+func (s *Server) CopyTo(server containers.Containers_CopyToServer) error {
+	req, err := server.Recv()
+	if err != nil {
+		return err
+	}
+
+	if err := validatePathRequest(req.Namespace, req.ContainerID, req.Path); err != nil {
+		return err
+	}
+
+	if len(req.Remove) > 0 {
+		log.Debugf("Remove %d paths from [%s] in container [%s] in namespace [%s]", len(req.Remove), req.Path, req.ContainerID, req.Namespace)
+		if err := s.client.RemoveFrom(req.Namespace, req.ContainerID, req.Path, req.Remove); err != nil {
+			return err
+		}
+	}
+
+	log.Debugf("Copy archive to [%s] in container [%s] in namespace [%s]", req.Path, req.ContainerID, req.Namespace)
+	if err := s.client.CopyTo(req.Namespace, req.ContainerID, req.Path, stream.NewCopyToReader(server, req.Data)); err != nil {
+		return err
+	}
+	return server.SendAndClose(&containers.CopyToResponse{})
+}
+
+// CopyFrom sends tar archive of the path in the container to the client
+func (s *Server) CopyFrom(req *containers.CopyFromRequest, server containers.Containers_CopyFromServer) error {
+	if err := validatePathRequest(req.Namespace, req.ContainerID, req.Path); err != nil {
+		return err
+	}
+
+	log.Debugf("Copy archive of [%s] from container [%s] in namespace [%s]", req.Path, req.ContainerID, req.Namespace)
+	writer := bufio.NewWriterSize(stream.NewCopyFromWriter(server), copyChunkSize)
+	if err := s.client.CopyFrom(req.Namespace, req.ContainerID, req.Path, writer); err != nil {
+		return err
+	}
+	return writer.Flush()
+}
+
+// Checkpoint checkpoints the running container to the node path, or streams the checkpoint to the client
+// as tar archive if the client doesn't want to store it in the node
+func (s *Server) Checkpoint(req *containers.CheckpointRequest, server containers.Containers_CheckpointServer) error {
+	if req.Namespace == "" {
+		return status.Errorf(codes.InvalidArgument, "You must define namespace")
+	}
+	if req.ContainerID == "" {
+		return status.Errorf(codes.InvalidArgument, "You must define containerID")
+	}
+	if req.StoreOnNode && req.Path == "" {
+		return status.Errorf(codes.InvalidArgument, "You must define path where to store the checkpoint in the node")
+	}
+
+	dir := req.Path
+	if !req.StoreOnNode {
+		tmp, err := ioutil.TempDir("", "eliot-checkpoint")
+		if err != nil {
+			return errors.Wrapf(err, "Failed to create temporary directory for container [%s] checkpoint", req.ContainerID)
+		}
+		defer os.RemoveAll(tmp)
+		dir = tmp
+	}
+
+	log.Debugf("Checkpoint container [%s] in namespace [%s] to [%s]", req.ContainerID, req.Namespace, dir)
+	if err := s.client.CheckpointContainer(req.Namespace, req.ContainerID, dir); err != nil {
+		return toCheckpointError(err)
+	}
+	if req.StoreOnNode {
+		return nil
+	}
+
+	writer := bufio.NewWriterSize(&checkpointWriter{server}, copyChunkSize)
+	if err := runtime.CreateArchive(dir, "/", writer); err != nil {
+		return errors.Wrapf(err, "Failed to send container [%s] checkpoint", req.ContainerID)
+	}
+	return writer.Flush()
+}
+
+// Restore restores the pod container from the checkpoint in the node path, or from the tar archive
+// what the client streams
+func (s *Server) Restore(server containers.Containers_RestoreServer) error {
+	req, err := server.Recv()
+	if err != nil {
+		return err
+	}
+
+	if req.Namespace == "" {
+		return status.Errorf(codes.InvalidArgument, "You must define namespace in the first restore message")
+	}
+	if req.Pod == "" || req.Container == "" {
+		return status.Errorf(codes.InvalidArgument, "You must define pod and container in the first restore message")
+	}
+	if req.FromNode && req.Path == "" {
+		return status.Errorf(codes.InvalidArgument, "You must define path of the checkpoint in the node")
+	}
+
+	dir := req.Path
+	if !req.FromNode {
+		tmp, err := ioutil.TempDir("", "eliot-restore")
+		if err != nil {
+			return errors.Wrapf(err, "Failed to create temporary directory for container [%s] checkpoint", req.Container)
+		}
+		defer os.RemoveAll(tmp)
+		dir = tmp
+
+		if err := runtime.ExtractArchive(dir, "/", newRestoreReader(server, req.Data)); err != nil {
+			return errors.Wrapf(err, "Failed to receive container [%s] checkpoint", req.Container)
+		}
+	}
+
+	unlock := s.locks.lock(req.Namespace, req.Pod)
+	defer unlock()
+
+	pod, err := s.client.GetPod(req.Namespace, req.Pod)
+	if err != nil {
+		return errors.Wrapf(err, "Cannot fetch pod [%s], cannot restore container [%s]", req.Pod, req.Container)
+	}
+	containerID := ""
+	for _, containerStatus := range pod.Status.ContainerStatuses {
+		if containerStatus.Name == req.Container {
+			containerID = containerStatus.ContainerID
+		}
+	}
+	if containerID == "" {
+		return status.Errorf(codes.NotFound, "Pod [%s] doesn't have container [%s]", req.Pod, req.Container)
+	}
+
+	ioset, err := runtime.NewIOSet(fmt.Sprintf("%s.%s", req.Pod, req.Container))
+	if err != nil {
+		return errors.Wrapf(err, "Failed to create IO set for container [%s]", req.Container)
+	}
+
+	log.Debugf("Restore container [%s] in pod [%s] in namespace [%s] from [%s]", req.Container, req.Pod, req.Namespace, dir)
+	restored, err := s.client.RestoreContainer(req.Namespace, containerID, dir, *ioset)
+	if err != nil {
+		return toCheckpointError(err)
+	}
+	return server.SendAndClose(&containers.RestoreResponse{
+		Status: mapping.MapContainerStatusesToAPIModel([]model.ContainerStatus{restored})[0],
+	})
+}
+
+// toCheckpointError converts the runtime ErrNotSupported to Unimplemented status,
+// so the client can tell the missing CRIU support apart from other failed preconditions
+func toCheckpointError(err error) error {
+	if errors.Cause(err) == runtime.ErrNotSupported {
+		return status.Error(codes.Unimplemented, err.Error())
+	}
+	return err
+}
+
+// checkpointWriter is io.Writer implementation what writes checkpoint archive chunks to RPC stream
+type checkpointWriter struct {
+	stream containers.Containers_CheckpointServer
+}
+
+func (w *checkpointWriter) Write(p []byte) (int, error) {
+	if err := w.stream.Send(&containers.CheckpointResponse{Data: p}); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// restoreReader is io.Reader implementation what reads checkpoint archive chunks from RPC stream
+type restoreReader struct {
+	buffer bytes.Buffer
+	stream containers.Containers_RestoreServer
+}
+
+// newRestoreReader creates new restoreReader, first is the data of already received first message
+func newRestoreReader(stream containers.Containers_RestoreServer, first []byte) *restoreReader {
+	reader := &restoreReader{stream: stream}
+	reader.buffer.Write(first)
+	return reader
+}
+
+func (r *restoreReader) Read(p []byte) (int, error) {
+	for r.buffer.Len() == 0 {
+		req, err := r.stream.Recv()
+		if err != nil {
+			return 0, err
+		}
+		r.buffer.Write(req.GetData())
+	}
+	return r.buffer.Read(p)
+}
+
+// WatchFile streams the changes of the file in the container until the client closes the stream
+func (s *Server) WatchFile(req *containers.WatchFileRequest, server containers.Containers_WatchFileServer) error {
+	if err := validatePathRequest(req.Namespace, req.ContainerID, req.Path); err != nil {
+		return err
+	}
+
+	log.Debugf("Watch file [%s] in container [%s] in namespace [%s]", req.Path, req.ContainerID, req.Namespace)
+	return s.client.WatchFile(req.Namespace, req.ContainerID, req.Path, server.Context().Done(), func(event runtime.FileEvent) error {
+		return server.Send(&containers.FileEvent{
+			Op:   string(event.Op),
+			Path: event.Path,
+			Time: event.Time.UnixNano(),
+		})
+	})
+}
+
+func validatePathRequest(namespace, containerID, path string) error {
+	if namespace == "" {
+		return status.Errorf(codes.InvalidArgument, "You must define namespace")
+	}
+	if containerID == "" {
+		return status.Errorf(codes.InvalidArgument, "You must define containerID")
+	}
+	if path == "" {
+		return status.Errorf(codes.InvalidArgument, "You must define path")
+	}
+	return nil
 }
 
 func getMetadataValue(md metadata.MD, key string) string {
@@ -284,17 +1564,25 @@ func getMetadataValue(md metadata.MD, key string) string {
 }
 
 // NewServer creates new API server
-func NewServer(listen string, client runtime.Client, resolver *resolver.Resolver) *Server {
+// The server installs interceptors which convert errors to gRPC status codes,
+// so the opts must not contain unary or stream interceptor.
+func NewServer(listen string, client runtime.Client, resolver *resolver.Resolver, opts ...grpc.ServerOption) *Server {
 	apiserver := &Server{
-		resolver: resolver,
-		client:   client,
-		listen:   listen,
+		resolver:   resolver,
+		client:     client,
+		listen:     listen,
+		volumesDir: model.PodVolumesDir,
 	}
 
-	apiserver.grpc = grpc.NewServer()
+	apiserver.grpc = grpc.NewServer(append([]grpc.ServerOption{
+		grpc.UnaryInterceptor(unaryErrorInterceptor),
+		grpc.StreamInterceptor(streamErrorInterceptor),
+		serverKeepalive(),
+	}, opts...)...)
 	pods.RegisterPodsServer(apiserver.grpc, apiserver)
 	containers.RegisterContainersServer(apiserver.grpc, apiserver)
 	node.RegisterNodeServer(apiserver.grpc, apiserver)
+	grpc_health_v1.RegisterHealthServer(apiserver.grpc, apiserver)
 	return apiserver
 }
 
@@ -1,9 +1,18 @@
 package api
 
 import (
+	"syscall"
 	"testing"
+	"time"
 
+	containers "github.com/ernoaapa/eliot/pkg/api/services/containers/v1"
+	pods "github.com/ernoaapa/eliot/pkg/api/services/pods/v1"
+	"github.com/ernoaapa/eliot/pkg/model"
+	"github.com/ernoaapa/eliot/pkg/runtime"
 	"github.com/stretchr/testify/assert"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 func TestGetMetadataValue(t *testing.T) {
@@ -21,3 +30,220 @@ func TestGetMetadataValue(t *testing.T) {
 	assert.Equal(t, "first", getMetadataValue(md, "crazy"))
 	assert.Equal(t, "", getMetadataValue(md, "dontexist"))
 }
+
+// labelsRuntime is runtime which keeps the pod labels in memory
+type labelsRuntime struct {
+	runtime.Client
+	pods map[string]map[string]string
+}
+
+func (r *labelsRuntime) GetPod(namespace, name string) (model.Pod, error) {
+	labels, ok := r.pods[name]
+	if !ok {
+		return model.Pod{}, runtime.ErrWithMessagef(runtime.ErrNotFound, "Pod [%s] not found", name)
+	}
+	return model.Pod{Metadata: model.Metadata{Name: name, Namespace: namespace, Labels: labels}}, nil
+}
+
+func (r *labelsRuntime) SetPodLabels(namespace, name string, labels map[string]string) error {
+	r.pods[name] = labels
+	return nil
+}
+
+func (r *labelsRuntime) RenamePod(namespace, name, newName string) error {
+	r.pods[newName] = r.pods[name]
+	delete(r.pods, name)
+	return nil
+}
+
+func TestServerUpdateLabels(t *testing.T) {
+	fake := &labelsRuntime{pods: map[string]map[string]string{
+		"foo": {"app": "foo", "tier": "backend"},
+	}}
+	server := NewServer("", fake, nil)
+
+	resp, err := server.UpdateLabels(context.Background(), &pods.UpdatePodLabelsRequest{
+		Namespace: "eliot",
+		Name:      "foo",
+		Labels:    map[string]string{"version": "2"},
+		Merge:     true,
+		Remove:    []string{"tier"},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{"app": "foo", "version": "2"}, resp.Pod.Metadata.Labels)
+
+	resp, err = server.UpdateLabels(context.Background(), &pods.UpdatePodLabelsRequest{
+		Namespace: "eliot",
+		Name:      "foo",
+		Labels:    map[string]string{"app": "bar"},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{"app": "bar"}, resp.Pod.Metadata.Labels, "should replace all labels without merge")
+}
+
+func TestServerRename(t *testing.T) {
+	fake := &labelsRuntime{pods: map[string]map[string]string{
+		"foo": {},
+		"bar": {},
+	}}
+	server := NewServer("", fake, nil)
+
+	_, err := server.Rename(context.Background(), &pods.RenamePodRequest{Namespace: "eliot", Name: "foo", NewName: "bar"})
+	assert.Equal(t, codes.AlreadyExists, status.Code(toStatusError(err)))
+
+	_, err = server.Rename(context.Background(), &pods.RenamePodRequest{Namespace: "eliot", Name: "foo", NewName: "Invalid_Name"})
+	assert.Equal(t, codes.InvalidArgument, status.Code(toStatusError(err)))
+
+	resp, err := server.Rename(context.Background(), &pods.RenamePodRequest{Namespace: "eliot", Name: "foo", NewName: "baz"})
+	assert.NoError(t, err)
+	assert.Equal(t, "baz", resp.Pod.Metadata.Name)
+	assert.NotContains(t, fake.pods, "foo")
+}
+
+// cordonRuntime is runtime which keeps the pod cordon state in memory
+type cordonRuntime struct {
+	runtime.Client
+	cordoned map[string]bool
+}
+
+func (r *cordonRuntime) GetPod(namespace, name string) (model.Pod, error) {
+	cordoned, ok := r.cordoned[name]
+	if !ok {
+		return model.Pod{}, runtime.ErrWithMessagef(runtime.ErrNotFound, "Pod [%s] not found", name)
+	}
+	return model.Pod{Metadata: model.Metadata{Name: name, Namespace: namespace}, Status: model.PodStatus{Cordoned: cordoned}}, nil
+}
+
+func (r *cordonRuntime) SetPodCordoned(namespace, name string, cordoned bool) error {
+	if _, ok := r.cordoned[name]; !ok {
+		return runtime.ErrWithMessagef(runtime.ErrNotFound, "Pod [%s] not found", name)
+	}
+	r.cordoned[name] = cordoned
+	return nil
+}
+
+func TestServerCordon(t *testing.T) {
+	fake := &cordonRuntime{cordoned: map[string]bool{"foo": false}}
+	server := NewServer("", fake, nil)
+
+	resp, err := server.Cordon(context.Background(), &pods.CordonPodRequest{Namespace: "eliot", Name: "foo", Cordoned: true})
+	assert.NoError(t, err)
+	assert.True(t, resp.Pod.Status.Cordoned)
+	assert.True(t, fake.cordoned["foo"])
+
+	resp, err = server.Cordon(context.Background(), &pods.CordonPodRequest{Namespace: "eliot", Name: "foo", Cordoned: false})
+	assert.NoError(t, err)
+	assert.False(t, resp.Pod.Status.Cordoned)
+
+	_, err = server.Cordon(context.Background(), &pods.CordonPodRequest{Namespace: "eliot", Name: "bar", Cordoned: true})
+	assert.Equal(t, codes.NotFound, status.Code(toStatusError(err)))
+}
+
+// envRuntime is runtime which records the container environment updates and signals
+type envRuntime struct {
+	runtime.Client
+	env    map[string]string
+	signal syscall.Signal
+}
+
+func (r *envRuntime) SetContainerEnv(namespace, name string, env map[string]string) error {
+	for key, value := range env {
+		r.env[key] = value
+	}
+	return nil
+}
+
+func (r *envRuntime) Signal(namespace, name string, signal syscall.Signal) error {
+	r.signal = signal
+	return nil
+}
+
+func TestServerSetEnv(t *testing.T) {
+	fake := &envRuntime{env: map[string]string{"LEVEL": "info"}}
+	server := NewServer("", fake, nil)
+
+	_, err := server.SetEnv(context.Background(), &containers.SetEnvRequest{Namespace: "eliot", ContainerID: "foo", Env: map[string]string{"LEVEL": "debug"}})
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{"LEVEL": "debug"}, fake.env)
+	assert.Equal(t, syscall.Signal(0), fake.signal, "should not send signal by default")
+
+	_, err = server.SetEnv(context.Background(), &containers.SetEnvRequest{Namespace: "eliot", ContainerID: "foo", Env: map[string]string{"NEW": "1"}, Signal: 15})
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{"LEVEL": "debug", "NEW": "1"}, fake.env)
+	assert.Equal(t, syscall.SIGTERM, fake.signal)
+}
+
+// assertWaitsPodLock checks that the call doesn't complete while someone else holds the pod lock
+func assertWaitsPodLock(t *testing.T, server *Server, namespace, name string, call func() error) {
+	unlock := server.locks.lock(namespace, name)
+	done := make(chan error, 1)
+	go func() { done <- call() }()
+
+	select {
+	case <-done:
+		t.Fatal("Call completed while the pod was locked")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	unlock()
+	select {
+	case err := <-done:
+		assert.NoError(t, err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("Call didn't complete after the pod got unlocked")
+	}
+}
+
+func TestServerRestartLocksPod(t *testing.T) {
+	fake := &labelsRuntime{pods: map[string]map[string]string{"foo": {}}}
+	server := NewServer("", fake, nil)
+
+	assertWaitsPodLock(t, server, "eliot", "foo", func() error {
+		_, err := server.Restart(context.Background(), &pods.RestartPodRequest{Namespace: "eliot", Name: "foo"})
+		return err
+	})
+}
+
+func TestServerDeleteLocksPod(t *testing.T) {
+	fake := &labelsRuntime{pods: map[string]map[string]string{"foo": {}}}
+	server := NewServer("", fake, nil)
+
+	assertWaitsPodLock(t, server, "eliot", "foo", func() error {
+		_, err := server.Delete(context.Background(), &pods.DeletePodRequest{Namespace: "eliot", Name: "foo"})
+		return err
+	})
+}
+
+// statusRuntime is runtime which has single container
+type statusRuntime struct {
+	runtime.Client
+	status model.ContainerStatus
+}
+
+func (r *statusRuntime) GetContainerStatus(namespace, name string) (model.ContainerStatus, error) {
+	if name != r.status.ContainerID {
+		return model.ContainerStatus{}, runtime.ErrWithMessagef(runtime.ErrNotFound, "Container [%s] not found", name)
+	}
+	return r.status, nil
+}
+
+func TestServerStatus(t *testing.T) {
+	finished := time.Now()
+	server := NewServer("", &statusRuntime{status: model.ContainerStatus{
+		ContainerID:  "foo",
+		State:        "stopped",
+		RestartCount: 1,
+		ExitCode:     137,
+		Reason:       model.ReasonOOMKilled,
+		FinishedAt:   finished,
+	}}, nil)
+
+	resp, err := server.Status(context.Background(), &containers.StatusRequest{Namespace: "eliot", ContainerID: "foo"})
+	assert.NoError(t, err)
+	assert.Equal(t, int32(137), resp.Status.ExitCode)
+	assert.Equal(t, model.ReasonOOMKilled, resp.Status.Reason)
+	assert.Equal(t, finished.UnixNano(), resp.Status.FinishedAt)
+
+	_, err = server.Status(context.Background(), &containers.StatusRequest{Namespace: "eliot", ContainerID: "bar"})
+	assert.True(t, runtime.IsNotFound(err))
+}
@@ -19,6 +19,47 @@ It has these top-level messages:
 	PipeToStdin
 	Mount
 	ContainerStatus
+	LogsRequest
+	LogLine
+	LogsStreamResponse
+	StatsRequest
+	ContainerStats
+	StatsResponse
+	TerminalSize
+	CopyToRequest
+	CopyToResponse
+	CopyFromRequest
+	CopyFromResponse
+	TopRequest
+	Process
+	TopResponse
+	PortForwardRequest
+	PortForwardResponse
+	WatchFileRequest
+	FileEvent
+	StatusRequest
+	StatusResponse
+	InspectRequest
+	LinuxNamespace
+	ContainerInspect
+	InspectResponse
+	SetEnvRequest
+	SetEnvResponse
+	Probe
+	HTTPGetProbe
+	ExecProbe
+	DiskUsageRequest
+	DiskUsageResponse
+	MountUsage
+	CheckpointRequest
+	CheckpointResponse
+	RestoreRequest
+	RestoreResponse
+	WaitRequest
+	WaitResponse
+	ResourceLimits
+	UpdateResourcesRequest
+	UpdateResourcesResponse
 */
 package containers
 
@@ -44,6 +85,10 @@ const _ = proto.ProtoPackageIsVersion2 // please upgrade the proto package
 
 type StdinStreamRequest struct {
 	Input []byte `protobuf:"bytes,1,opt,name=input,proto3" json:"input,omitempty"`
+	// Terminal size changed, sent instead of input when the client terminal get resized
+	Resize *TerminalSize `protobuf:"bytes,2,opt,name=resize" json:"resize,omitempty"`
+	// Client detached, the server stops reading stdin but leaves the container stdin open
+	Detach bool `protobuf:"varint,3,opt,name=detach" json:"detach,omitempty"`
 }
 
 func (m *StdinStreamRequest) Reset()                    { *m = StdinStreamRequest{} }
@@ -58,6 +103,20 @@ func (m *StdinStreamRequest) GetInput() []byte {
 	return nil
 }
 
+func (m *StdinStreamRequest) GetResize() *TerminalSize {
+	if m != nil {
+		return m.Resize
+	}
+	return nil
+}
+
+func (m *StdinStreamRequest) GetDetach() bool {
+	if m != nil {
+		return m.Detach
+	}
+	return false
+}
+
 type StdoutStreamResponse struct {
 	Output []byte `protobuf:"bytes,1,opt,name=output,proto3" json:"output,omitempty"`
 	// Is this stderr(=true) or stdout(=false)
@@ -132,6 +191,10 @@ type Container struct {
 	Env        []string `protobuf:"bytes,6,rep,name=env" json:"env,omitempty"`
 	Mounts     []*Mount `protobuf:"bytes,7,rep,name=mounts" json:"mounts,omitempty"`
 	Pipe       *PipeSet `protobuf:"bytes,8,opt,name=pipe" json:"pipe,omitempty"`
+	// Restart policy of the container, one of always, onfailure or never. Empty means the pod restart policy.
+	RestartPolicy string `protobuf:"bytes,9,opt,name=restartPolicy" json:"restartPolicy,omitempty"`
+	// Probe which tells when the container is ready, the container is ready when running if not set
+	ReadinessProbe *Probe `protobuf:"bytes,10,opt,name=readinessProbe" json:"readinessProbe,omitempty"`
 }
 
 func (m *Container) Reset()                    { *m = Container{} }
@@ -195,6 +258,20 @@ func (m *Container) GetPipe() *PipeSet {
 	return nil
 }
 
+func (m *Container) GetRestartPolicy() string {
+	if m != nil {
+		return m.RestartPolicy
+	}
+	return ""
+}
+
+func (m *Container) GetReadinessProbe() *Probe {
+	if m != nil {
+		return m.ReadinessProbe
+	}
+	return nil
+}
+
 type PipeSet struct {
 	Stdout *PipeFromStdout `protobuf:"bytes,1,opt,name=stdout" json:"stdout,omitempty"`
 }
@@ -289,6 +366,20 @@ type ContainerStatus struct {
 	Image        string `protobuf:"bytes,3,opt,name=image" json:"image,omitempty"`
 	State        string `protobuf:"bytes,4,opt,name=state" json:"state,omitempty"`
 	RestartCount int32  `protobuf:"varint,5,opt,name=restartCount" json:"restartCount,omitempty"`
+	// Exit code of the last run, only set when the container has exited
+	ExitCode int32 `protobuf:"varint,6,opt,name=exitCode" json:"exitCode,omitempty"`
+	// Reason why the container exited, e.g. Completed, Error or OOMKilled
+	Reason string `protobuf:"bytes,7,opt,name=reason" json:"reason,omitempty"`
+	// Unix time in nanoseconds when the container was last started
+	StartedAt int64 `protobuf:"varint,8,opt,name=startedAt" json:"startedAt,omitempty"`
+	// Unix time in nanoseconds when the container exited
+	FinishedAt int64 `protobuf:"varint,9,opt,name=finishedAt" json:"finishedAt,omitempty"`
+	// True when the container is running and its readiness probe, if any, has succeeded
+	Ready bool `protobuf:"varint,10,opt,name=ready" json:"ready,omitempty"`
+	// Why the readiness probe failed, empty when the probe succeeded or has not run yet
+	ReadinessMessage string `protobuf:"bytes,11,opt,name=readinessMessage" json:"readinessMessage,omitempty"`
+	// Why the client couldn't fetch the live status, set only by GetPod WithContainerStatus option
+	StatusError string `protobuf:"bytes,12,opt,name=statusError" json:"statusError,omitempty"`
 }
 
 func (m *ContainerStatus) Reset()                    { *m = ContainerStatus{} }
@@ -331,204 +422,2406 @@ func (m *ContainerStatus) GetRestartCount() int32 {
 	return 0
 }
 
-func init() {
-	proto.RegisterType((*StdinStreamRequest)(nil), "eliot.services.containers.v1.StdinStreamRequest")
-	proto.RegisterType((*StdoutStreamResponse)(nil), "eliot.services.containers.v1.StdoutStreamResponse")
-	proto.RegisterType((*SignalRequest)(nil), "eliot.services.containers.v1.SignalRequest")
-	proto.RegisterType((*SignalResponse)(nil), "eliot.services.containers.v1.SignalResponse")
-	proto.RegisterType((*Container)(nil), "eliot.services.containers.v1.Container")
-	proto.RegisterType((*PipeSet)(nil), "eliot.services.containers.v1.PipeSet")
-	proto.RegisterType((*PipeFromStdout)(nil), "eliot.services.containers.v1.PipeFromStdout")
-	proto.RegisterType((*PipeToStdin)(nil), "eliot.services.containers.v1.PipeToStdin")
-	proto.RegisterType((*Mount)(nil), "eliot.services.containers.v1.Mount")
-	proto.RegisterType((*ContainerStatus)(nil), "eliot.services.containers.v1.ContainerStatus")
+func (m *ContainerStatus) GetExitCode() int32 {
+	if m != nil {
+		return m.ExitCode
+	}
+	return 0
 }
 
-// Reference imports to suppress errors if they are not otherwise used.
-var _ context.Context
-var _ grpc.ClientConn
-
-// This is a compile-time assertion to ensure that this generated file
-// is compatible with the grpc package it is being compiled against.
-const _ = grpc.SupportPackageIsVersion4
+func (m *ContainerStatus) GetReason() string {
+	if m != nil {
+		return m.Reason
+	}
+	return ""
+}
 
-// Client API for Containers service
+func (m *ContainerStatus) GetStartedAt() int64 {
+	if m != nil {
+		return m.StartedAt
+	}
+	return 0
+}
 
-type ContainersClient interface {
-	Attach(ctx context.Context, opts ...grpc.CallOption) (Containers_AttachClient, error)
-	Exec(ctx context.Context, opts ...grpc.CallOption) (Containers_ExecClient, error)
-	Signal(ctx context.Context, in *SignalRequest, opts ...grpc.CallOption) (*SignalResponse, error)
+func (m *ContainerStatus) GetFinishedAt() int64 {
+	if m != nil {
+		return m.FinishedAt
+	}
+	return 0
 }
 
-type containersClient struct {
-	cc *grpc.ClientConn
+func (m *ContainerStatus) GetReady() bool {
+	if m != nil {
+		return m.Ready
+	}
+	return false
 }
 
-func NewContainersClient(cc *grpc.ClientConn) ContainersClient {
-	return &containersClient{cc}
+func (m *ContainerStatus) GetReadinessMessage() string {
+	if m != nil {
+		return m.ReadinessMessage
+	}
+	return ""
 }
 
-func (c *containersClient) Attach(ctx context.Context, opts ...grpc.CallOption) (Containers_AttachClient, error) {
-	stream, err := grpc.NewClientStream(ctx, &_Containers_serviceDesc.Streams[0], c.cc, "/eliot.services.containers.v1.Containers/Attach", opts...)
-	if err != nil {
-		return nil, err
+func (m *ContainerStatus) GetStatusError() string {
+	if m != nil {
+		return m.StatusError
 	}
-	x := &containersAttachClient{stream}
-	return x, nil
+	return ""
 }
 
-type Containers_AttachClient interface {
-	Send(*StdinStreamRequest) error
-	Recv() (*StdoutStreamResponse, error)
-	grpc.ClientStream
+type LogsRequest struct {
+	Namespace   string `protobuf:"bytes,1,opt,name=namespace" json:"namespace,omitempty"`
+	ContainerID string `protobuf:"bytes,2,opt,name=containerID" json:"containerID,omitempty"`
+	// Keep streaming new lines until client cancels
+	Follow bool `protobuf:"varint,3,opt,name=follow" json:"follow,omitempty"`
+	// Return only last N lines, zero means all
+	Tail int32 `protobuf:"varint,4,opt,name=tail" json:"tail,omitempty"`
+	// Return only lines written after the time, as Unix time in nanoseconds
+	Since int64 `protobuf:"varint,5,opt,name=since" json:"since,omitempty"`
 }
 
-type containersAttachClient struct {
-	grpc.ClientStream
+func (m *LogsRequest) Reset()                    { *m = LogsRequest{} }
+func (m *LogsRequest) String() string            { return proto.CompactTextString(m) }
+func (*LogsRequest) ProtoMessage()               {}
+func (*LogsRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{10} }
+
+func (m *LogsRequest) GetNamespace() string {
+	if m != nil {
+		return m.Namespace
+	}
+	return ""
 }
 
-func (x *containersAttachClient) Send(m *StdinStreamRequest) error {
-	return x.ClientStream.SendMsg(m)
+func (m *LogsRequest) GetContainerID() string {
+	if m != nil {
+		return m.ContainerID
+	}
+	return ""
 }
 
-func (x *containersAttachClient) Recv() (*StdoutStreamResponse, error) {
-	m := new(StdoutStreamResponse)
-	if err := x.ClientStream.RecvMsg(m); err != nil {
-		return nil, err
+func (m *LogsRequest) GetFollow() bool {
+	if m != nil {
+		return m.Follow
 	}
-	return m, nil
+	return false
 }
 
-func (c *containersClient) Exec(ctx context.Context, opts ...grpc.CallOption) (Containers_ExecClient, error) {
-	stream, err := grpc.NewClientStream(ctx, &_Containers_serviceDesc.Streams[1], c.cc, "/eliot.services.containers.v1.Containers/Exec", opts...)
-	if err != nil {
-		return nil, err
+func (m *LogsRequest) GetTail() int32 {
+	if m != nil {
+		return m.Tail
 	}
-	x := &containersExecClient{stream}
-	return x, nil
+	return 0
 }
 
-type Containers_ExecClient interface {
-	Send(*StdinStreamRequest) error
-	Recv() (*StdoutStreamResponse, error)
-	grpc.ClientStream
+func (m *LogsRequest) GetSince() int64 {
+	if m != nil {
+		return m.Since
+	}
+	return 0
 }
 
-type containersExecClient struct {
-	grpc.ClientStream
+type LogLine struct {
+	// Unix time in nanoseconds when the line was written
+	Time int64 `protobuf:"varint,1,opt,name=time" json:"time,omitempty"`
+	// Is this stderr(=true) or stdout(=false)
+	Stderr bool   `protobuf:"varint,2,opt,name=stderr" json:"stderr,omitempty"`
+	Line   []byte `protobuf:"bytes,3,opt,name=line,proto3" json:"line,omitempty"`
 }
 
-func (x *containersExecClient) Send(m *StdinStreamRequest) error {
-	return x.ClientStream.SendMsg(m)
+func (m *LogLine) Reset()                    { *m = LogLine{} }
+func (m *LogLine) String() string            { return proto.CompactTextString(m) }
+func (*LogLine) ProtoMessage()               {}
+func (*LogLine) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{11} }
+
+func (m *LogLine) GetTime() int64 {
+	if m != nil {
+		return m.Time
+	}
+	return 0
 }
 
-func (x *containersExecClient) Recv() (*StdoutStreamResponse, error) {
-	m := new(StdoutStreamResponse)
-	if err := x.ClientStream.RecvMsg(m); err != nil {
-		return nil, err
+func (m *LogLine) GetStderr() bool {
+	if m != nil {
+		return m.Stderr
 	}
-	return m, nil
+	return false
 }
 
-func (c *containersClient) Signal(ctx context.Context, in *SignalRequest, opts ...grpc.CallOption) (*SignalResponse, error) {
-	out := new(SignalResponse)
-	err := grpc.Invoke(ctx, "/eliot.services.containers.v1.Containers/Signal", in, out, c.cc, opts...)
-	if err != nil {
-		return nil, err
+func (m *LogLine) GetLine() []byte {
+	if m != nil {
+		return m.Line
 	}
-	return out, nil
+	return nil
 }
 
-// Server API for Containers service
+type LogsStreamResponse struct {
+	Lines []*LogLine `protobuf:"bytes,1,rep,name=lines" json:"lines,omitempty"`
+}
 
-type ContainersServer interface {
-	Attach(Containers_AttachServer) error
-	Exec(Containers_ExecServer) error
-	Signal(context.Context, *SignalRequest) (*SignalResponse, error)
+func (m *LogsStreamResponse) Reset()                    { *m = LogsStreamResponse{} }
+func (m *LogsStreamResponse) String() string            { return proto.CompactTextString(m) }
+func (*LogsStreamResponse) ProtoMessage()               {}
+func (*LogsStreamResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{12} }
+
+func (m *LogsStreamResponse) GetLines() []*LogLine {
+	if m != nil {
+		return m.Lines
+	}
+	return nil
 }
 
-func RegisterContainersServer(s *grpc.Server, srv ContainersServer) {
-	s.RegisterService(&_Containers_serviceDesc, srv)
+type StatsRequest struct {
+	Namespace   string `protobuf:"bytes,1,opt,name=namespace" json:"namespace,omitempty"`
+	ContainerID string `protobuf:"bytes,2,opt,name=containerID" json:"containerID,omitempty"`
 }
 
-func _Containers_Attach_Handler(srv interface{}, stream grpc.ServerStream) error {
-	return srv.(ContainersServer).Attach(&containersAttachServer{stream})
+func (m *StatsRequest) Reset()                    { *m = StatsRequest{} }
+func (m *StatsRequest) String() string            { return proto.CompactTextString(m) }
+func (*StatsRequest) ProtoMessage()               {}
+func (*StatsRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{13} }
+
+func (m *StatsRequest) GetNamespace() string {
+	if m != nil {
+		return m.Namespace
+	}
+	return ""
 }
 
-type Containers_AttachServer interface {
-	Send(*StdoutStreamResponse) error
-	Recv() (*StdinStreamRequest, error)
-	grpc.ServerStream
+func (m *StatsRequest) GetContainerID() string {
+	if m != nil {
+		return m.ContainerID
+	}
+	return ""
 }
 
-type containersAttachServer struct {
-	grpc.ServerStream
+type ContainerStats struct {
+	// Unix time in nanoseconds when the stats were collected
+	Time             int64  `protobuf:"varint,1,opt,name=time" json:"time,omitempty"`
+	CpuNanoseconds   uint64 `protobuf:"varint,2,opt,name=cpuNanoseconds" json:"cpuNanoseconds,omitempty"`
+	MemoryUsageBytes uint64 `protobuf:"varint,3,opt,name=memoryUsageBytes" json:"memoryUsageBytes,omitempty"`
+	MemoryLimitBytes uint64 `protobuf:"varint,4,opt,name=memoryLimitBytes" json:"memoryLimitBytes,omitempty"`
+	BlkioReadBytes   uint64 `protobuf:"varint,5,opt,name=blkioReadBytes" json:"blkioReadBytes,omitempty"`
+	BlkioWriteBytes  uint64 `protobuf:"varint,6,opt,name=blkioWriteBytes" json:"blkioWriteBytes,omitempty"`
 }
 
-func (x *containersAttachServer) Send(m *StdoutStreamResponse) error {
-	return x.ServerStream.SendMsg(m)
+func (m *ContainerStats) Reset()                    { *m = ContainerStats{} }
+func (m *ContainerStats) String() string            { return proto.CompactTextString(m) }
+func (*ContainerStats) ProtoMessage()               {}
+func (*ContainerStats) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{14} }
+
+func (m *ContainerStats) GetTime() int64 {
+	if m != nil {
+		return m.Time
+	}
+	return 0
 }
 
-func (x *containersAttachServer) Recv() (*StdinStreamRequest, error) {
-	m := new(StdinStreamRequest)
-	if err := x.ServerStream.RecvMsg(m); err != nil {
-		return nil, err
+func (m *ContainerStats) GetCpuNanoseconds() uint64 {
+	if m != nil {
+		return m.CpuNanoseconds
 	}
-	return m, nil
+	return 0
 }
 
-func _Containers_Exec_Handler(srv interface{}, stream grpc.ServerStream) error {
-	return srv.(ContainersServer).Exec(&containersExecServer{stream})
+func (m *ContainerStats) GetMemoryUsageBytes() uint64 {
+	if m != nil {
+		return m.MemoryUsageBytes
+	}
+	return 0
 }
 
-type Containers_ExecServer interface {
-	Send(*StdoutStreamResponse) error
-	Recv() (*StdinStreamRequest, error)
-	grpc.ServerStream
+func (m *ContainerStats) GetMemoryLimitBytes() uint64 {
+	if m != nil {
+		return m.MemoryLimitBytes
+	}
+	return 0
 }
 
-type containersExecServer struct {
-	grpc.ServerStream
+func (m *ContainerStats) GetBlkioReadBytes() uint64 {
+	if m != nil {
+		return m.BlkioReadBytes
+	}
+	return 0
 }
 
-func (x *containersExecServer) Send(m *StdoutStreamResponse) error {
-	return x.ServerStream.SendMsg(m)
+func (m *ContainerStats) GetBlkioWriteBytes() uint64 {
+	if m != nil {
+		return m.BlkioWriteBytes
+	}
+	return 0
 }
 
-func (x *containersExecServer) Recv() (*StdinStreamRequest, error) {
-	m := new(StdinStreamRequest)
-	if err := x.ServerStream.RecvMsg(m); err != nil {
-		return nil, err
+type StatsResponse struct {
+	Stats *ContainerStats `protobuf:"bytes,1,opt,name=stats" json:"stats,omitempty"`
+}
+
+func (m *StatsResponse) Reset()                    { *m = StatsResponse{} }
+func (m *StatsResponse) String() string            { return proto.CompactTextString(m) }
+func (*StatsResponse) ProtoMessage()               {}
+func (*StatsResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{15} }
+
+func (m *StatsResponse) GetStats() *ContainerStats {
+	if m != nil {
+		return m.Stats
 	}
-	return m, nil
+	return nil
 }
 
-func _Containers_Signal_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(SignalRequest)
-	if err := dec(in); err != nil {
-		return nil, err
+type TerminalSize struct {
+	Width  uint32 `protobuf:"varint,1,opt,name=width" json:"width,omitempty"`
+	Height uint32 `protobuf:"varint,2,opt,name=height" json:"height,omitempty"`
+}
+
+func (m *TerminalSize) Reset()                    { *m = TerminalSize{} }
+func (m *TerminalSize) String() string            { return proto.CompactTextString(m) }
+func (*TerminalSize) ProtoMessage()               {}
+func (*TerminalSize) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{16} }
+
+func (m *TerminalSize) GetWidth() uint32 {
+	if m != nil {
+		return m.Width
 	}
-	if interceptor == nil {
-		return srv.(ContainersServer).Signal(ctx, in)
+	return 0
+}
+
+func (m *TerminalSize) GetHeight() uint32 {
+	if m != nil {
+		return m.Height
 	}
-	info := &grpc.UnaryServerInfo{
-		Server:     srv,
-		FullMethod: "/eliot.services.containers.v1.Containers/Signal",
+	return 0
+}
+
+type CopyToRequest struct {
+	// Namespace, containerID and path are given in the first message
+	Namespace   string `protobuf:"bytes,1,opt,name=namespace" json:"namespace,omitempty"`
+	ContainerID string `protobuf:"bytes,2,opt,name=containerID" json:"containerID,omitempty"`
+	Path        string `protobuf:"bytes,3,opt,name=path" json:"path,omitempty"`
+	// Chunk of tar archive
+	Data []byte `protobuf:"bytes,4,opt,name=data,proto3" json:"data,omitempty"`
+	// Paths relative to the path to remove before extracting the archive, given in the first message
+	Remove []string `protobuf:"bytes,5,rep,name=remove" json:"remove,omitempty"`
+}
+
+func (m *CopyToRequest) Reset()                    { *m = CopyToRequest{} }
+func (m *CopyToRequest) String() string            { return proto.CompactTextString(m) }
+func (*CopyToRequest) ProtoMessage()               {}
+func (*CopyToRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{17} }
+
+func (m *CopyToRequest) GetNamespace() string {
+	if m != nil {
+		return m.Namespace
 	}
-	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(ContainersServer).Signal(ctx, req.(*SignalRequest))
+	return ""
+}
+
+func (m *CopyToRequest) GetContainerID() string {
+	if m != nil {
+		return m.ContainerID
 	}
-	return interceptor(ctx, in, info, handler)
+	return ""
 }
 
-var _Containers_serviceDesc = grpc.ServiceDesc{
-	ServiceName: "eliot.services.containers.v1.Containers",
-	HandlerType: (*ContainersServer)(nil),
+func (m *CopyToRequest) GetPath() string {
+	if m != nil {
+		return m.Path
+	}
+	return ""
+}
+
+func (m *CopyToRequest) GetData() []byte {
+	if m != nil {
+		return m.Data
+	}
+	return nil
+}
+
+func (m *CopyToRequest) GetRemove() []string {
+	if m != nil {
+		return m.Remove
+	}
+	return nil
+}
+
+type CopyToResponse struct {
+}
+
+func (m *CopyToResponse) Reset()                    { *m = CopyToResponse{} }
+func (m *CopyToResponse) String() string            { return proto.CompactTextString(m) }
+func (*CopyToResponse) ProtoMessage()               {}
+func (*CopyToResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{18} }
+
+type CopyFromRequest struct {
+	Namespace   string `protobuf:"bytes,1,opt,name=namespace" json:"namespace,omitempty"`
+	ContainerID string `protobuf:"bytes,2,opt,name=containerID" json:"containerID,omitempty"`
+	Path        string `protobuf:"bytes,3,opt,name=path" json:"path,omitempty"`
+}
+
+func (m *CopyFromRequest) Reset()                    { *m = CopyFromRequest{} }
+func (m *CopyFromRequest) String() string            { return proto.CompactTextString(m) }
+func (*CopyFromRequest) ProtoMessage()               {}
+func (*CopyFromRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{19} }
+
+func (m *CopyFromRequest) GetNamespace() string {
+	if m != nil {
+		return m.Namespace
+	}
+	return ""
+}
+
+func (m *CopyFromRequest) GetContainerID() string {
+	if m != nil {
+		return m.ContainerID
+	}
+	return ""
+}
+
+func (m *CopyFromRequest) GetPath() string {
+	if m != nil {
+		return m.Path
+	}
+	return ""
+}
+
+type CopyFromResponse struct {
+	// Chunk of tar archive
+	Data []byte `protobuf:"bytes,1,opt,name=data,proto3" json:"data,omitempty"`
+}
+
+func (m *CopyFromResponse) Reset()                    { *m = CopyFromResponse{} }
+func (m *CopyFromResponse) String() string            { return proto.CompactTextString(m) }
+func (*CopyFromResponse) ProtoMessage()               {}
+func (*CopyFromResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{20} }
+
+func (m *CopyFromResponse) GetData() []byte {
+	if m != nil {
+		return m.Data
+	}
+	return nil
+}
+
+type TopRequest struct {
+	Namespace   string `protobuf:"bytes,1,opt,name=namespace" json:"namespace,omitempty"`
+	ContainerID string `protobuf:"bytes,2,opt,name=containerID" json:"containerID,omitempty"`
+}
+
+func (m *TopRequest) Reset()                    { *m = TopRequest{} }
+func (m *TopRequest) String() string            { return proto.CompactTextString(m) }
+func (*TopRequest) ProtoMessage()               {}
+func (*TopRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{21} }
+
+func (m *TopRequest) GetNamespace() string {
+	if m != nil {
+		return m.Namespace
+	}
+	return ""
+}
+
+func (m *TopRequest) GetContainerID() string {
+	if m != nil {
+		return m.ContainerID
+	}
+	return ""
+}
+
+type Process struct {
+	// Process id inside the container pid namespace
+	Pid uint32 `protobuf:"varint,1,opt,name=pid" json:"pid,omitempty"`
+	// Parent process id inside the container pid namespace, zero if the parent is outside of the container
+	Ppid uint32 `protobuf:"varint,2,opt,name=ppid" json:"ppid,omitempty"`
+	// Process id in the host pid namespace
+	HostPid uint32 `protobuf:"varint,3,opt,name=hostPid" json:"hostPid,omitempty"`
+	Command string `protobuf:"bytes,4,opt,name=command" json:"command,omitempty"`
+	// CPU time used divided by the process running time, like in `ps`
+	CpuPercent float64 `protobuf:"fixed64,5,opt,name=cpuPercent" json:"cpuPercent,omitempty"`
+	// Resident set size
+	RssBytes uint64 `protobuf:"varint,6,opt,name=rssBytes" json:"rssBytes,omitempty"`
+	// Is this the container main process (PID 1 inside the container)
+	Init bool `protobuf:"varint,7,opt,name=init" json:"init,omitempty"`
+}
+
+func (m *Process) Reset()                    { *m = Process{} }
+func (m *Process) String() string            { return proto.CompactTextString(m) }
+func (*Process) ProtoMessage()               {}
+func (*Process) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{22} }
+
+func (m *Process) GetPid() uint32 {
+	if m != nil {
+		return m.Pid
+	}
+	return 0
+}
+
+func (m *Process) GetPpid() uint32 {
+	if m != nil {
+		return m.Ppid
+	}
+	return 0
+}
+
+func (m *Process) GetHostPid() uint32 {
+	if m != nil {
+		return m.HostPid
+	}
+	return 0
+}
+
+func (m *Process) GetCommand() string {
+	if m != nil {
+		return m.Command
+	}
+	return ""
+}
+
+func (m *Process) GetCpuPercent() float64 {
+	if m != nil {
+		return m.CpuPercent
+	}
+	return 0
+}
+
+func (m *Process) GetRssBytes() uint64 {
+	if m != nil {
+		return m.RssBytes
+	}
+	return 0
+}
+
+func (m *Process) GetInit() bool {
+	if m != nil {
+		return m.Init
+	}
+	return false
+}
+
+type TopResponse struct {
+	Processes []*Process `protobuf:"bytes,1,rep,name=processes" json:"processes,omitempty"`
+}
+
+func (m *TopResponse) Reset()                    { *m = TopResponse{} }
+func (m *TopResponse) String() string            { return proto.CompactTextString(m) }
+func (*TopResponse) ProtoMessage()               {}
+func (*TopResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{23} }
+
+func (m *TopResponse) GetProcesses() []*Process {
+	if m != nil {
+		return m.Processes
+	}
+	return nil
+}
+
+type PortForwardRequest struct {
+	// Namespace, containerID and port are given in the first message
+	Namespace   string `protobuf:"bytes,1,opt,name=namespace" json:"namespace,omitempty"`
+	ContainerID string `protobuf:"bytes,2,opt,name=containerID" json:"containerID,omitempty"`
+	Port        uint32 `protobuf:"varint,3,opt,name=port" json:"port,omitempty"`
+	// Client side connection the message belongs to, starting from one.
+	// Message with only the connection opens new connection to the port
+	Connection uint32 `protobuf:"varint,4,opt,name=connection" json:"connection,omitempty"`
+	// Chunk of data sent to the connection
+	Data []byte `protobuf:"bytes,5,opt,name=data,proto3" json:"data,omitempty"`
+	// Client side connection closed, no more data is sent to the connection
+	Close bool `protobuf:"varint,6,opt,name=close" json:"close,omitempty"`
+}
+
+func (m *PortForwardRequest) Reset()                    { *m = PortForwardRequest{} }
+func (m *PortForwardRequest) String() string            { return proto.CompactTextString(m) }
+func (*PortForwardRequest) ProtoMessage()               {}
+func (*PortForwardRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{24} }
+
+func (m *PortForwardRequest) GetNamespace() string {
+	if m != nil {
+		return m.Namespace
+	}
+	return ""
+}
+
+func (m *PortForwardRequest) GetContainerID() string {
+	if m != nil {
+		return m.ContainerID
+	}
+	return ""
+}
+
+func (m *PortForwardRequest) GetPort() uint32 {
+	if m != nil {
+		return m.Port
+	}
+	return 0
+}
+
+func (m *PortForwardRequest) GetConnection() uint32 {
+	if m != nil {
+		return m.Connection
+	}
+	return 0
+}
+
+func (m *PortForwardRequest) GetData() []byte {
+	if m != nil {
+		return m.Data
+	}
+	return nil
+}
+
+func (m *PortForwardRequest) GetClose() bool {
+	if m != nil {
+		return m.Close
+	}
+	return false
+}
+
+type PortForwardResponse struct {
+	Connection uint32 `protobuf:"varint,1,opt,name=connection" json:"connection,omitempty"`
+	// Chunk of data received from the connection
+	Data []byte `protobuf:"bytes,2,opt,name=data,proto3" json:"data,omitempty"`
+	// Connection to the port closed, no more data is received from the connection
+	Close bool `protobuf:"varint,3,opt,name=close" json:"close,omitempty"`
+	// Reason why the connection to the port failed or closed, empty if closed normally
+	Error string `protobuf:"bytes,4,opt,name=error" json:"error,omitempty"`
+}
+
+func (m *PortForwardResponse) Reset()                    { *m = PortForwardResponse{} }
+func (m *PortForwardResponse) String() string            { return proto.CompactTextString(m) }
+func (*PortForwardResponse) ProtoMessage()               {}
+func (*PortForwardResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{25} }
+
+func (m *PortForwardResponse) GetConnection() uint32 {
+	if m != nil {
+		return m.Connection
+	}
+	return 0
+}
+
+func (m *PortForwardResponse) GetData() []byte {
+	if m != nil {
+		return m.Data
+	}
+	return nil
+}
+
+func (m *PortForwardResponse) GetClose() bool {
+	if m != nil {
+		return m.Close
+	}
+	return false
+}
+
+func (m *PortForwardResponse) GetError() string {
+	if m != nil {
+		return m.Error
+	}
+	return ""
+}
+
+type WatchFileRequest struct {
+	Namespace   string `protobuf:"bytes,1,opt,name=namespace" json:"namespace,omitempty"`
+	ContainerID string `protobuf:"bytes,2,opt,name=containerID" json:"containerID,omitempty"`
+	// Path of the file in the container, doesn't need to exist yet
+	Path string `protobuf:"bytes,3,opt,name=path" json:"path,omitempty"`
+}
+
+func (m *WatchFileRequest) Reset()                    { *m = WatchFileRequest{} }
+func (m *WatchFileRequest) String() string            { return proto.CompactTextString(m) }
+func (*WatchFileRequest) ProtoMessage()               {}
+func (*WatchFileRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{26} }
+
+func (m *WatchFileRequest) GetNamespace() string {
+	if m != nil {
+		return m.Namespace
+	}
+	return ""
+}
+
+func (m *WatchFileRequest) GetContainerID() string {
+	if m != nil {
+		return m.ContainerID
+	}
+	return ""
+}
+
+func (m *WatchFileRequest) GetPath() string {
+	if m != nil {
+		return m.Path
+	}
+	return ""
+}
+
+type FileEvent struct {
+	// What happened to the file, one of create, write or remove
+	Op   string `protobuf:"bytes,1,opt,name=op" json:"op,omitempty"`
+	Path string `protobuf:"bytes,2,opt,name=path" json:"path,omitempty"`
+	// Unix time in nanoseconds when the change was noticed
+	Time int64 `protobuf:"varint,3,opt,name=time" json:"time,omitempty"`
+}
+
+func (m *FileEvent) Reset()                    { *m = FileEvent{} }
+func (m *FileEvent) String() string            { return proto.CompactTextString(m) }
+func (*FileEvent) ProtoMessage()               {}
+func (*FileEvent) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{27} }
+
+func (m *FileEvent) GetOp() string {
+	if m != nil {
+		return m.Op
+	}
+	return ""
+}
+
+func (m *FileEvent) GetPath() string {
+	if m != nil {
+		return m.Path
+	}
+	return ""
+}
+
+func (m *FileEvent) GetTime() int64 {
+	if m != nil {
+		return m.Time
+	}
+	return 0
+}
+
+type StatusRequest struct {
+	Namespace   string `protobuf:"bytes,1,opt,name=namespace" json:"namespace,omitempty"`
+	ContainerID string `protobuf:"bytes,2,opt,name=containerID" json:"containerID,omitempty"`
+}
+
+func (m *StatusRequest) Reset()                    { *m = StatusRequest{} }
+func (m *StatusRequest) String() string            { return proto.CompactTextString(m) }
+func (*StatusRequest) ProtoMessage()               {}
+func (*StatusRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{28} }
+
+func (m *StatusRequest) GetNamespace() string {
+	if m != nil {
+		return m.Namespace
+	}
+	return ""
+}
+
+func (m *StatusRequest) GetContainerID() string {
+	if m != nil {
+		return m.ContainerID
+	}
+	return ""
+}
+
+type StatusResponse struct {
+	Status *ContainerStatus `protobuf:"bytes,1,opt,name=status" json:"status,omitempty"`
+}
+
+func (m *StatusResponse) Reset()                    { *m = StatusResponse{} }
+func (m *StatusResponse) String() string            { return proto.CompactTextString(m) }
+func (*StatusResponse) ProtoMessage()               {}
+func (*StatusResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{29} }
+
+func (m *StatusResponse) GetStatus() *ContainerStatus {
+	if m != nil {
+		return m.Status
+	}
+	return nil
+}
+
+type InspectRequest struct {
+	Namespace   string `protobuf:"bytes,1,opt,name=namespace" json:"namespace,omitempty"`
+	ContainerID string `protobuf:"bytes,2,opt,name=containerID" json:"containerID,omitempty"`
+}
+
+func (m *InspectRequest) Reset()                    { *m = InspectRequest{} }
+func (m *InspectRequest) String() string            { return proto.CompactTextString(m) }
+func (*InspectRequest) ProtoMessage()               {}
+func (*InspectRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{30} }
+
+func (m *InspectRequest) GetNamespace() string {
+	if m != nil {
+		return m.Namespace
+	}
+	return ""
+}
+
+func (m *InspectRequest) GetContainerID() string {
+	if m != nil {
+		return m.ContainerID
+	}
+	return ""
+}
+
+type LinuxNamespace struct {
+	Type string `protobuf:"bytes,1,opt,name=type" json:"type,omitempty"`
+	Path string `protobuf:"bytes,2,opt,name=path" json:"path,omitempty"`
+}
+
+func (m *LinuxNamespace) Reset()                    { *m = LinuxNamespace{} }
+func (m *LinuxNamespace) String() string            { return proto.CompactTextString(m) }
+func (*LinuxNamespace) ProtoMessage()               {}
+func (*LinuxNamespace) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{31} }
+
+func (m *LinuxNamespace) GetType() string {
+	if m != nil {
+		return m.Type
+	}
+	return ""
+}
+
+func (m *LinuxNamespace) GetPath() string {
+	if m != nil {
+		return m.Path
+	}
+	return ""
+}
+
+type ContainerInspect struct {
+	ContainerID string `protobuf:"bytes,1,opt,name=containerID" json:"containerID,omitempty"`
+	Image       string `protobuf:"bytes,2,opt,name=image" json:"image,omitempty"`
+	// Digest of the image manifest the container was created from
+	ImageDigest string `protobuf:"bytes,3,opt,name=imageDigest" json:"imageDigest,omitempty"`
+	// Command and arguments of the container process
+	Args        []string          `protobuf:"bytes,4,rep,name=args" json:"args,omitempty"`
+	Env         []string          `protobuf:"bytes,5,rep,name=env" json:"env,omitempty"`
+	WorkingDir  string            `protobuf:"bytes,6,opt,name=workingDir" json:"workingDir,omitempty"`
+	Hostname    string            `protobuf:"bytes,7,opt,name=hostname" json:"hostname,omitempty"`
+	Mounts      []*Mount          `protobuf:"bytes,8,rep,name=mounts" json:"mounts,omitempty"`
+	Namespaces  []*LinuxNamespace `protobuf:"bytes,9,rep,name=namespaces" json:"namespaces,omitempty"`
+	CgroupsPath string            `protobuf:"bytes,10,opt,name=cgroupsPath" json:"cgroupsPath,omitempty"`
+	Labels      map[string]string `protobuf:"bytes,11,rep,name=labels" json:"labels,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	Annotations map[string]string `protobuf:"bytes,12,rep,name=annotations" json:"annotations,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	Runtime     string            `protobuf:"bytes,13,opt,name=runtime" json:"runtime,omitempty"`
+	Snapshotter string            `protobuf:"bytes,14,opt,name=snapshotter" json:"snapshotter,omitempty"`
+	SnapshotKey string            `protobuf:"bytes,15,opt,name=snapshotKey" json:"snapshotKey,omitempty"`
+	// Task status, e.g. running or stopped
+	Status string `protobuf:"bytes,16,opt,name=status" json:"status,omitempty"`
+	// Process id of the container main process in the host, zero if not running
+	Pid uint32 `protobuf:"varint,17,opt,name=pid" json:"pid,omitempty"`
+	// Unix time in nanoseconds
+	CreatedAt int64 `protobuf:"varint,18,opt,name=createdAt" json:"createdAt,omitempty"`
+	UpdatedAt int64 `protobuf:"varint,19,opt,name=updatedAt" json:"updatedAt,omitempty"`
+	// Complete OCI runtime spec as JSON
+	Spec []byte `protobuf:"bytes,20,opt,name=spec,proto3" json:"spec,omitempty"`
+}
+
+func (m *ContainerInspect) Reset()                    { *m = ContainerInspect{} }
+func (m *ContainerInspect) String() string            { return proto.CompactTextString(m) }
+func (*ContainerInspect) ProtoMessage()               {}
+func (*ContainerInspect) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{32} }
+
+func (m *ContainerInspect) GetContainerID() string {
+	if m != nil {
+		return m.ContainerID
+	}
+	return ""
+}
+
+func (m *ContainerInspect) GetImage() string {
+	if m != nil {
+		return m.Image
+	}
+	return ""
+}
+
+func (m *ContainerInspect) GetImageDigest() string {
+	if m != nil {
+		return m.ImageDigest
+	}
+	return ""
+}
+
+func (m *ContainerInspect) GetArgs() []string {
+	if m != nil {
+		return m.Args
+	}
+	return nil
+}
+
+func (m *ContainerInspect) GetEnv() []string {
+	if m != nil {
+		return m.Env
+	}
+	return nil
+}
+
+func (m *ContainerInspect) GetWorkingDir() string {
+	if m != nil {
+		return m.WorkingDir
+	}
+	return ""
+}
+
+func (m *ContainerInspect) GetHostname() string {
+	if m != nil {
+		return m.Hostname
+	}
+	return ""
+}
+
+func (m *ContainerInspect) GetMounts() []*Mount {
+	if m != nil {
+		return m.Mounts
+	}
+	return nil
+}
+
+func (m *ContainerInspect) GetNamespaces() []*LinuxNamespace {
+	if m != nil {
+		return m.Namespaces
+	}
+	return nil
+}
+
+func (m *ContainerInspect) GetCgroupsPath() string {
+	if m != nil {
+		return m.CgroupsPath
+	}
+	return ""
+}
+
+func (m *ContainerInspect) GetLabels() map[string]string {
+	if m != nil {
+		return m.Labels
+	}
+	return nil
+}
+
+func (m *ContainerInspect) GetAnnotations() map[string]string {
+	if m != nil {
+		return m.Annotations
+	}
+	return nil
+}
+
+func (m *ContainerInspect) GetRuntime() string {
+	if m != nil {
+		return m.Runtime
+	}
+	return ""
+}
+
+func (m *ContainerInspect) GetSnapshotter() string {
+	if m != nil {
+		return m.Snapshotter
+	}
+	return ""
+}
+
+func (m *ContainerInspect) GetSnapshotKey() string {
+	if m != nil {
+		return m.SnapshotKey
+	}
+	return ""
+}
+
+func (m *ContainerInspect) GetStatus() string {
+	if m != nil {
+		return m.Status
+	}
+	return ""
+}
+
+func (m *ContainerInspect) GetPid() uint32 {
+	if m != nil {
+		return m.Pid
+	}
+	return 0
+}
+
+func (m *ContainerInspect) GetCreatedAt() int64 {
+	if m != nil {
+		return m.CreatedAt
+	}
+	return 0
+}
+
+func (m *ContainerInspect) GetUpdatedAt() int64 {
+	if m != nil {
+		return m.UpdatedAt
+	}
+	return 0
+}
+
+func (m *ContainerInspect) GetSpec() []byte {
+	if m != nil {
+		return m.Spec
+	}
+	return nil
+}
+
+type InspectResponse struct {
+	Inspect *ContainerInspect `protobuf:"bytes,1,opt,name=inspect" json:"inspect,omitempty"`
+}
+
+func (m *InspectResponse) Reset()                    { *m = InspectResponse{} }
+func (m *InspectResponse) String() string            { return proto.CompactTextString(m) }
+func (*InspectResponse) ProtoMessage()               {}
+func (*InspectResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{33} }
+
+func (m *InspectResponse) GetInspect() *ContainerInspect {
+	if m != nil {
+		return m.Inspect
+	}
+	return nil
+}
+
+type SetEnvRequest struct {
+	Namespace   string `protobuf:"bytes,1,opt,name=namespace" json:"namespace,omitempty"`
+	ContainerID string `protobuf:"bytes,2,opt,name=containerID" json:"containerID,omitempty"`
+	// Environment variables to set, other variables are kept as is
+	Env map[string]string `protobuf:"bytes,3,rep,name=env" json:"env,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	// Signal sent to the container process after the update, zero means no signal
+	Signal int32 `protobuf:"varint,4,opt,name=signal" json:"signal,omitempty"`
+}
+
+func (m *SetEnvRequest) Reset()                    { *m = SetEnvRequest{} }
+func (m *SetEnvRequest) String() string            { return proto.CompactTextString(m) }
+func (*SetEnvRequest) ProtoMessage()               {}
+func (*SetEnvRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{34} }
+
+func (m *SetEnvRequest) GetNamespace() string {
+	if m != nil {
+		return m.Namespace
+	}
+	return ""
+}
+
+func (m *SetEnvRequest) GetContainerID() string {
+	if m != nil {
+		return m.ContainerID
+	}
+	return ""
+}
+
+func (m *SetEnvRequest) GetEnv() map[string]string {
+	if m != nil {
+		return m.Env
+	}
+	return nil
+}
+
+func (m *SetEnvRequest) GetSignal() int32 {
+	if m != nil {
+		return m.Signal
+	}
+	return 0
+}
+
+type SetEnvResponse struct {
+}
+
+func (m *SetEnvResponse) Reset()                    { *m = SetEnvResponse{} }
+func (m *SetEnvResponse) String() string            { return proto.CompactTextString(m) }
+func (*SetEnvResponse) ProtoMessage()               {}
+func (*SetEnvResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{35} }
+
+type Probe struct {
+	HttpGet *HTTPGetProbe `protobuf:"bytes,1,opt,name=httpGet" json:"httpGet,omitempty"`
+	Exec    *ExecProbe    `protobuf:"bytes,2,opt,name=exec" json:"exec,omitempty"`
+}
+
+func (m *Probe) Reset()                    { *m = Probe{} }
+func (m *Probe) String() string            { return proto.CompactTextString(m) }
+func (*Probe) ProtoMessage()               {}
+func (*Probe) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{36} }
+
+func (m *Probe) GetHttpGet() *HTTPGetProbe {
+	if m != nil {
+		return m.HttpGet
+	}
+	return nil
+}
+
+func (m *Probe) GetExec() *ExecProbe {
+	if m != nil {
+		return m.Exec
+	}
+	return nil
+}
+
+type HTTPGetProbe struct {
+	Path string `protobuf:"bytes,1,opt,name=path" json:"path,omitempty"`
+	Port int32  `protobuf:"varint,2,opt,name=port" json:"port,omitempty"`
+}
+
+func (m *HTTPGetProbe) Reset()                    { *m = HTTPGetProbe{} }
+func (m *HTTPGetProbe) String() string            { return proto.CompactTextString(m) }
+func (*HTTPGetProbe) ProtoMessage()               {}
+func (*HTTPGetProbe) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{37} }
+
+func (m *HTTPGetProbe) GetPath() string {
+	if m != nil {
+		return m.Path
+	}
+	return ""
+}
+
+func (m *HTTPGetProbe) GetPort() int32 {
+	if m != nil {
+		return m.Port
+	}
+	return 0
+}
+
+type ExecProbe struct {
+	Command []string `protobuf:"bytes,1,rep,name=command" json:"command,omitempty"`
+}
+
+func (m *ExecProbe) Reset()                    { *m = ExecProbe{} }
+func (m *ExecProbe) String() string            { return proto.CompactTextString(m) }
+func (*ExecProbe) ProtoMessage()               {}
+func (*ExecProbe) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{38} }
+
+func (m *ExecProbe) GetCommand() []string {
+	if m != nil {
+		return m.Command
+	}
+	return nil
+}
+
+type DiskUsageRequest struct {
+	Namespace   string `protobuf:"bytes,1,opt,name=namespace" json:"namespace,omitempty"`
+	ContainerID string `protobuf:"bytes,2,opt,name=containerID" json:"containerID,omitempty"`
+	// How often the usage is sent in nanoseconds, zero means the node default
+	Interval int64 `protobuf:"varint,3,opt,name=interval" json:"interval,omitempty"`
+}
+
+func (m *DiskUsageRequest) Reset()                    { *m = DiskUsageRequest{} }
+func (m *DiskUsageRequest) String() string            { return proto.CompactTextString(m) }
+func (*DiskUsageRequest) ProtoMessage()               {}
+func (*DiskUsageRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{39} }
+
+func (m *DiskUsageRequest) GetNamespace() string {
+	if m != nil {
+		return m.Namespace
+	}
+	return ""
+}
+
+func (m *DiskUsageRequest) GetContainerID() string {
+	if m != nil {
+		return m.ContainerID
+	}
+	return ""
+}
+
+func (m *DiskUsageRequest) GetInterval() int64 {
+	if m != nil {
+		return m.Interval
+	}
+	return 0
+}
+
+type DiskUsageResponse struct {
+	// Unix time in nanoseconds when the usage was collected
+	Time int64 `protobuf:"varint,1,opt,name=time" json:"time,omitempty"`
+	// Size of the container writable layer, zero if the root filesystem is read-only
+	WritableBytes int64         `protobuf:"varint,2,opt,name=writableBytes" json:"writableBytes,omitempty"`
+	Mounts        []*MountUsage `protobuf:"bytes,3,rep,name=mounts" json:"mounts,omitempty"`
+}
+
+func (m *DiskUsageResponse) Reset()                    { *m = DiskUsageResponse{} }
+func (m *DiskUsageResponse) String() string            { return proto.CompactTextString(m) }
+func (*DiskUsageResponse) ProtoMessage()               {}
+func (*DiskUsageResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{40} }
+
+func (m *DiskUsageResponse) GetTime() int64 {
+	if m != nil {
+		return m.Time
+	}
+	return 0
+}
+
+func (m *DiskUsageResponse) GetWritableBytes() int64 {
+	if m != nil {
+		return m.WritableBytes
+	}
+	return 0
+}
+
+func (m *DiskUsageResponse) GetMounts() []*MountUsage {
+	if m != nil {
+		return m.Mounts
+	}
+	return nil
+}
+
+type MountUsage struct {
+	Source      string `protobuf:"bytes,1,opt,name=source" json:"source,omitempty"`
+	Destination string `protobuf:"bytes,2,opt,name=destination" json:"destination,omitempty"`
+	UsedBytes   int64  `protobuf:"varint,3,opt,name=usedBytes" json:"usedBytes,omitempty"`
+}
+
+func (m *MountUsage) Reset()                    { *m = MountUsage{} }
+func (m *MountUsage) String() string            { return proto.CompactTextString(m) }
+func (*MountUsage) ProtoMessage()               {}
+func (*MountUsage) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{41} }
+
+func (m *MountUsage) GetSource() string {
+	if m != nil {
+		return m.Source
+	}
+	return ""
+}
+
+func (m *MountUsage) GetDestination() string {
+	if m != nil {
+		return m.Destination
+	}
+	return ""
+}
+
+func (m *MountUsage) GetUsedBytes() int64 {
+	if m != nil {
+		return m.UsedBytes
+	}
+	return 0
+}
+
+type CheckpointRequest struct {
+	Namespace   string `protobuf:"bytes,1,opt,name=namespace" json:"namespace,omitempty"`
+	ContainerID string `protobuf:"bytes,2,opt,name=containerID" json:"containerID,omitempty"`
+	// Directory in the node where to store the checkpoint, if storeOnNode is true
+	Path string `protobuf:"bytes,3,opt,name=path" json:"path,omitempty"`
+	// Store the checkpoint in the node path instead of streaming it back as tar archive
+	StoreOnNode bool `protobuf:"varint,4,opt,name=storeOnNode" json:"storeOnNode,omitempty"`
+}
+
+func (m *CheckpointRequest) Reset()                    { *m = CheckpointRequest{} }
+func (m *CheckpointRequest) String() string            { return proto.CompactTextString(m) }
+func (*CheckpointRequest) ProtoMessage()               {}
+func (*CheckpointRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{42} }
+
+func (m *CheckpointRequest) GetNamespace() string {
+	if m != nil {
+		return m.Namespace
+	}
+	return ""
+}
+
+func (m *CheckpointRequest) GetContainerID() string {
+	if m != nil {
+		return m.ContainerID
+	}
+	return ""
+}
+
+func (m *CheckpointRequest) GetPath() string {
+	if m != nil {
+		return m.Path
+	}
+	return ""
+}
+
+func (m *CheckpointRequest) GetStoreOnNode() bool {
+	if m != nil {
+		return m.StoreOnNode
+	}
+	return false
+}
+
+type CheckpointResponse struct {
+	// Chunk of the checkpoint tar archive
+	Data []byte `protobuf:"bytes,1,opt,name=data,proto3" json:"data,omitempty"`
+}
+
+func (m *CheckpointResponse) Reset()                    { *m = CheckpointResponse{} }
+func (m *CheckpointResponse) String() string            { return proto.CompactTextString(m) }
+func (*CheckpointResponse) ProtoMessage()               {}
+func (*CheckpointResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{43} }
+
+func (m *CheckpointResponse) GetData() []byte {
+	if m != nil {
+		return m.Data
+	}
+	return nil
+}
+
+type RestoreRequest struct {
+	// Namespace, pod, container, path and fromNode are given in the first message
+	Namespace string `protobuf:"bytes,1,opt,name=namespace" json:"namespace,omitempty"`
+	Pod       string `protobuf:"bytes,2,opt,name=pod" json:"pod,omitempty"`
+	Container string `protobuf:"bytes,3,opt,name=container" json:"container,omitempty"`
+	// Directory in the node where the checkpoint is stored, if fromNode is true
+	Path string `protobuf:"bytes,4,opt,name=path" json:"path,omitempty"`
+	// Restore from the checkpoint in the node path instead of the streamed tar archive
+	FromNode bool `protobuf:"varint,5,opt,name=fromNode" json:"fromNode,omitempty"`
+	// Chunk of the checkpoint tar archive
+	Data []byte `protobuf:"bytes,6,opt,name=data,proto3" json:"data,omitempty"`
+}
+
+func (m *RestoreRequest) Reset()                    { *m = RestoreRequest{} }
+func (m *RestoreRequest) String() string            { return proto.CompactTextString(m) }
+func (*RestoreRequest) ProtoMessage()               {}
+func (*RestoreRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{44} }
+
+func (m *RestoreRequest) GetNamespace() string {
+	if m != nil {
+		return m.Namespace
+	}
+	return ""
+}
+
+func (m *RestoreRequest) GetPod() string {
+	if m != nil {
+		return m.Pod
+	}
+	return ""
+}
+
+func (m *RestoreRequest) GetContainer() string {
+	if m != nil {
+		return m.Container
+	}
+	return ""
+}
+
+func (m *RestoreRequest) GetPath() string {
+	if m != nil {
+		return m.Path
+	}
+	return ""
+}
+
+func (m *RestoreRequest) GetFromNode() bool {
+	if m != nil {
+		return m.FromNode
+	}
+	return false
+}
+
+func (m *RestoreRequest) GetData() []byte {
+	if m != nil {
+		return m.Data
+	}
+	return nil
+}
+
+type RestoreResponse struct {
+	Status *ContainerStatus `protobuf:"bytes,1,opt,name=status" json:"status,omitempty"`
+}
+
+func (m *RestoreResponse) Reset()                    { *m = RestoreResponse{} }
+func (m *RestoreResponse) String() string            { return proto.CompactTextString(m) }
+func (*RestoreResponse) ProtoMessage()               {}
+func (*RestoreResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{45} }
+
+func (m *RestoreResponse) GetStatus() *ContainerStatus {
+	if m != nil {
+		return m.Status
+	}
+	return nil
+}
+
+type WaitRequest struct {
+	Namespace   string `protobuf:"bytes,1,opt,name=namespace" json:"namespace,omitempty"`
+	ContainerID string `protobuf:"bytes,2,opt,name=containerID" json:"containerID,omitempty"`
+}
+
+func (m *WaitRequest) Reset()                    { *m = WaitRequest{} }
+func (m *WaitRequest) String() string            { return proto.CompactTextString(m) }
+func (*WaitRequest) ProtoMessage()               {}
+func (*WaitRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{46} }
+
+func (m *WaitRequest) GetNamespace() string {
+	if m != nil {
+		return m.Namespace
+	}
+	return ""
+}
+
+func (m *WaitRequest) GetContainerID() string {
+	if m != nil {
+		return m.ContainerID
+	}
+	return ""
+}
+
+type WaitResponse struct {
+	// Exit code of the container main process
+	ExitCode int32 `protobuf:"varint,1,opt,name=exitCode" json:"exitCode,omitempty"`
+}
+
+func (m *WaitResponse) Reset()                    { *m = WaitResponse{} }
+func (m *WaitResponse) String() string            { return proto.CompactTextString(m) }
+func (*WaitResponse) ProtoMessage()               {}
+func (*WaitResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{47} }
+
+func (m *WaitResponse) GetExitCode() int32 {
+	if m != nil {
+		return m.ExitCode
+	}
+	return 0
+}
+
+type ResourceLimits struct {
+	MemoryLimitBytes int64  `protobuf:"varint,1,opt,name=memoryLimitBytes" json:"memoryLimitBytes,omitempty"`
+	CpuShares        uint64 `protobuf:"varint,2,opt,name=cpuShares" json:"cpuShares,omitempty"`
+	CpuQuota         int64  `protobuf:"varint,3,opt,name=cpuQuota" json:"cpuQuota,omitempty"`
+	CpuPeriod        uint64 `protobuf:"varint,4,opt,name=cpuPeriod" json:"cpuPeriod,omitempty"`
+}
+
+func (m *ResourceLimits) Reset()                    { *m = ResourceLimits{} }
+func (m *ResourceLimits) String() string            { return proto.CompactTextString(m) }
+func (*ResourceLimits) ProtoMessage()               {}
+func (*ResourceLimits) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{48} }
+
+func (m *ResourceLimits) GetMemoryLimitBytes() int64 {
+	if m != nil {
+		return m.MemoryLimitBytes
+	}
+	return 0
+}
+
+func (m *ResourceLimits) GetCpuShares() uint64 {
+	if m != nil {
+		return m.CpuShares
+	}
+	return 0
+}
+
+func (m *ResourceLimits) GetCpuQuota() int64 {
+	if m != nil {
+		return m.CpuQuota
+	}
+	return 0
+}
+
+func (m *ResourceLimits) GetCpuPeriod() uint64 {
+	if m != nil {
+		return m.CpuPeriod
+	}
+	return 0
+}
+
+type UpdateResourcesRequest struct {
+	Namespace   string `protobuf:"bytes,1,opt,name=namespace" json:"namespace,omitempty"`
+	ContainerID string `protobuf:"bytes,2,opt,name=containerID" json:"containerID,omitempty"`
+	// Limits to change, zero fields are kept as is
+	Limits *ResourceLimits `protobuf:"bytes,3,opt,name=limits" json:"limits,omitempty"`
+}
+
+func (m *UpdateResourcesRequest) Reset()                    { *m = UpdateResourcesRequest{} }
+func (m *UpdateResourcesRequest) String() string            { return proto.CompactTextString(m) }
+func (*UpdateResourcesRequest) ProtoMessage()               {}
+func (*UpdateResourcesRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{49} }
+
+func (m *UpdateResourcesRequest) GetNamespace() string {
+	if m != nil {
+		return m.Namespace
+	}
+	return ""
+}
+
+func (m *UpdateResourcesRequest) GetContainerID() string {
+	if m != nil {
+		return m.ContainerID
+	}
+	return ""
+}
+
+func (m *UpdateResourcesRequest) GetLimits() *ResourceLimits {
+	if m != nil {
+		return m.Limits
+	}
+	return nil
+}
+
+type UpdateResourcesResponse struct {
+	// All limits of the container after the update
+	Limits *ResourceLimits `protobuf:"bytes,1,opt,name=limits" json:"limits,omitempty"`
+}
+
+func (m *UpdateResourcesResponse) Reset()                    { *m = UpdateResourcesResponse{} }
+func (m *UpdateResourcesResponse) String() string            { return proto.CompactTextString(m) }
+func (*UpdateResourcesResponse) ProtoMessage()               {}
+func (*UpdateResourcesResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{50} }
+
+func (m *UpdateResourcesResponse) GetLimits() *ResourceLimits {
+	if m != nil {
+		return m.Limits
+	}
+	return nil
+}
+
+func init() {
+	proto.RegisterType((*StdinStreamRequest)(nil), "eliot.services.containers.v1.StdinStreamRequest")
+	proto.RegisterType((*StdoutStreamResponse)(nil), "eliot.services.containers.v1.StdoutStreamResponse")
+	proto.RegisterType((*SignalRequest)(nil), "eliot.services.containers.v1.SignalRequest")
+	proto.RegisterType((*SignalResponse)(nil), "eliot.services.containers.v1.SignalResponse")
+	proto.RegisterType((*Container)(nil), "eliot.services.containers.v1.Container")
+	proto.RegisterType((*PipeSet)(nil), "eliot.services.containers.v1.PipeSet")
+	proto.RegisterType((*PipeFromStdout)(nil), "eliot.services.containers.v1.PipeFromStdout")
+	proto.RegisterType((*PipeToStdin)(nil), "eliot.services.containers.v1.PipeToStdin")
+	proto.RegisterType((*Mount)(nil), "eliot.services.containers.v1.Mount")
+	proto.RegisterType((*ContainerStatus)(nil), "eliot.services.containers.v1.ContainerStatus")
+	proto.RegisterType((*LogsRequest)(nil), "eliot.services.containers.v1.LogsRequest")
+	proto.RegisterType((*LogLine)(nil), "eliot.services.containers.v1.LogLine")
+	proto.RegisterType((*LogsStreamResponse)(nil), "eliot.services.containers.v1.LogsStreamResponse")
+	proto.RegisterType((*StatsRequest)(nil), "eliot.services.containers.v1.StatsRequest")
+	proto.RegisterType((*ContainerStats)(nil), "eliot.services.containers.v1.ContainerStats")
+	proto.RegisterType((*StatsResponse)(nil), "eliot.services.containers.v1.StatsResponse")
+	proto.RegisterType((*TerminalSize)(nil), "eliot.services.containers.v1.TerminalSize")
+	proto.RegisterType((*CopyToRequest)(nil), "eliot.services.containers.v1.CopyToRequest")
+	proto.RegisterType((*CopyToResponse)(nil), "eliot.services.containers.v1.CopyToResponse")
+	proto.RegisterType((*CopyFromRequest)(nil), "eliot.services.containers.v1.CopyFromRequest")
+	proto.RegisterType((*CopyFromResponse)(nil), "eliot.services.containers.v1.CopyFromResponse")
+	proto.RegisterType((*TopRequest)(nil), "eliot.services.containers.v1.TopRequest")
+	proto.RegisterType((*Process)(nil), "eliot.services.containers.v1.Process")
+	proto.RegisterType((*TopResponse)(nil), "eliot.services.containers.v1.TopResponse")
+	proto.RegisterType((*PortForwardRequest)(nil), "eliot.services.containers.v1.PortForwardRequest")
+	proto.RegisterType((*PortForwardResponse)(nil), "eliot.services.containers.v1.PortForwardResponse")
+	proto.RegisterType((*WatchFileRequest)(nil), "eliot.services.containers.v1.WatchFileRequest")
+	proto.RegisterType((*FileEvent)(nil), "eliot.services.containers.v1.FileEvent")
+	proto.RegisterType((*StatusRequest)(nil), "eliot.services.containers.v1.StatusRequest")
+	proto.RegisterType((*StatusResponse)(nil), "eliot.services.containers.v1.StatusResponse")
+	proto.RegisterType((*InspectRequest)(nil), "eliot.services.containers.v1.InspectRequest")
+	proto.RegisterType((*LinuxNamespace)(nil), "eliot.services.containers.v1.LinuxNamespace")
+	proto.RegisterType((*ContainerInspect)(nil), "eliot.services.containers.v1.ContainerInspect")
+	proto.RegisterType((*InspectResponse)(nil), "eliot.services.containers.v1.InspectResponse")
+	proto.RegisterType((*SetEnvRequest)(nil), "eliot.services.containers.v1.SetEnvRequest")
+	proto.RegisterType((*SetEnvResponse)(nil), "eliot.services.containers.v1.SetEnvResponse")
+	proto.RegisterType((*Probe)(nil), "eliot.services.containers.v1.Probe")
+	proto.RegisterType((*HTTPGetProbe)(nil), "eliot.services.containers.v1.HTTPGetProbe")
+	proto.RegisterType((*ExecProbe)(nil), "eliot.services.containers.v1.ExecProbe")
+	proto.RegisterType((*DiskUsageRequest)(nil), "eliot.services.containers.v1.DiskUsageRequest")
+	proto.RegisterType((*DiskUsageResponse)(nil), "eliot.services.containers.v1.DiskUsageResponse")
+	proto.RegisterType((*MountUsage)(nil), "eliot.services.containers.v1.MountUsage")
+	proto.RegisterType((*CheckpointRequest)(nil), "eliot.services.containers.v1.CheckpointRequest")
+	proto.RegisterType((*CheckpointResponse)(nil), "eliot.services.containers.v1.CheckpointResponse")
+	proto.RegisterType((*RestoreRequest)(nil), "eliot.services.containers.v1.RestoreRequest")
+	proto.RegisterType((*RestoreResponse)(nil), "eliot.services.containers.v1.RestoreResponse")
+	proto.RegisterType((*WaitRequest)(nil), "eliot.services.containers.v1.WaitRequest")
+	proto.RegisterType((*WaitResponse)(nil), "eliot.services.containers.v1.WaitResponse")
+	proto.RegisterType((*ResourceLimits)(nil), "eliot.services.containers.v1.ResourceLimits")
+	proto.RegisterType((*UpdateResourcesRequest)(nil), "eliot.services.containers.v1.UpdateResourcesRequest")
+	proto.RegisterType((*UpdateResourcesResponse)(nil), "eliot.services.containers.v1.UpdateResourcesResponse")
+}
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ context.Context
+var _ grpc.ClientConn
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+const _ = grpc.SupportPackageIsVersion4
+
+// Client API for Containers service
+
+type ContainersClient interface {
+	Attach(ctx context.Context, opts ...grpc.CallOption) (Containers_AttachClient, error)
+	Exec(ctx context.Context, opts ...grpc.CallOption) (Containers_ExecClient, error)
+	Signal(ctx context.Context, in *SignalRequest, opts ...grpc.CallOption) (*SignalResponse, error)
+	Logs(ctx context.Context, in *LogsRequest, opts ...grpc.CallOption) (Containers_LogsClient, error)
+	Stats(ctx context.Context, in *StatsRequest, opts ...grpc.CallOption) (*StatsResponse, error)
+	StreamStats(ctx context.Context, in *StatsRequest, opts ...grpc.CallOption) (Containers_StreamStatsClient, error)
+	CopyTo(ctx context.Context, opts ...grpc.CallOption) (Containers_CopyToClient, error)
+	CopyFrom(ctx context.Context, in *CopyFromRequest, opts ...grpc.CallOption) (Containers_CopyFromClient, error)
+	Top(ctx context.Context, in *TopRequest, opts ...grpc.CallOption) (*TopResponse, error)
+	PortForward(ctx context.Context, opts ...grpc.CallOption) (Containers_PortForwardClient, error)
+	WatchFile(ctx context.Context, in *WatchFileRequest, opts ...grpc.CallOption) (Containers_WatchFileClient, error)
+	Status(ctx context.Context, in *StatusRequest, opts ...grpc.CallOption) (*StatusResponse, error)
+	Inspect(ctx context.Context, in *InspectRequest, opts ...grpc.CallOption) (*InspectResponse, error)
+	SetEnv(ctx context.Context, in *SetEnvRequest, opts ...grpc.CallOption) (*SetEnvResponse, error)
+	StreamDiskUsage(ctx context.Context, in *DiskUsageRequest, opts ...grpc.CallOption) (Containers_StreamDiskUsageClient, error)
+	Checkpoint(ctx context.Context, in *CheckpointRequest, opts ...grpc.CallOption) (Containers_CheckpointClient, error)
+	Restore(ctx context.Context, opts ...grpc.CallOption) (Containers_RestoreClient, error)
+	Wait(ctx context.Context, in *WaitRequest, opts ...grpc.CallOption) (*WaitResponse, error)
+	UpdateResources(ctx context.Context, in *UpdateResourcesRequest, opts ...grpc.CallOption) (*UpdateResourcesResponse, error)
+}
+
+type containersClient struct {
+	cc *grpc.ClientConn
+}
+
+func NewContainersClient(cc *grpc.ClientConn) ContainersClient {
+	return &containersClient{cc}
+}
+
+func (c *containersClient) Attach(ctx context.Context, opts ...grpc.CallOption) (Containers_AttachClient, error) {
+	stream, err := grpc.NewClientStream(ctx, &_Containers_serviceDesc.Streams[0], c.cc, "/eliot.services.containers.v1.Containers/Attach", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &containersAttachClient{stream}
+	return x, nil
+}
+
+type Containers_AttachClient interface {
+	Send(*StdinStreamRequest) error
+	Recv() (*StdoutStreamResponse, error)
+	grpc.ClientStream
+}
+
+type containersAttachClient struct {
+	grpc.ClientStream
+}
+
+func (x *containersAttachClient) Send(m *StdinStreamRequest) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *containersAttachClient) Recv() (*StdoutStreamResponse, error) {
+	m := new(StdoutStreamResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *containersClient) Exec(ctx context.Context, opts ...grpc.CallOption) (Containers_ExecClient, error) {
+	stream, err := grpc.NewClientStream(ctx, &_Containers_serviceDesc.Streams[1], c.cc, "/eliot.services.containers.v1.Containers/Exec", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &containersExecClient{stream}
+	return x, nil
+}
+
+type Containers_ExecClient interface {
+	Send(*StdinStreamRequest) error
+	Recv() (*StdoutStreamResponse, error)
+	grpc.ClientStream
+}
+
+type containersExecClient struct {
+	grpc.ClientStream
+}
+
+func (x *containersExecClient) Send(m *StdinStreamRequest) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *containersExecClient) Recv() (*StdoutStreamResponse, error) {
+	m := new(StdoutStreamResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *containersClient) Signal(ctx context.Context, in *SignalRequest, opts ...grpc.CallOption) (*SignalResponse, error) {
+	out := new(SignalResponse)
+	err := grpc.Invoke(ctx, "/eliot.services.containers.v1.Containers/Signal", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *containersClient) Logs(ctx context.Context, in *LogsRequest, opts ...grpc.CallOption) (Containers_LogsClient, error) {
+	stream, err := grpc.NewClientStream(ctx, &_Containers_serviceDesc.Streams[2], c.cc, "/eliot.services.containers.v1.Containers/Logs", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &containersLogsClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Containers_LogsClient interface {
+	Recv() (*LogsStreamResponse, error)
+	grpc.ClientStream
+}
+
+type containersLogsClient struct {
+	grpc.ClientStream
+}
+
+func (x *containersLogsClient) Recv() (*LogsStreamResponse, error) {
+	m := new(LogsStreamResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *containersClient) Stats(ctx context.Context, in *StatsRequest, opts ...grpc.CallOption) (*StatsResponse, error) {
+	out := new(StatsResponse)
+	err := grpc.Invoke(ctx, "/eliot.services.containers.v1.Containers/Stats", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *containersClient) StreamStats(ctx context.Context, in *StatsRequest, opts ...grpc.CallOption) (Containers_StreamStatsClient, error) {
+	stream, err := grpc.NewClientStream(ctx, &_Containers_serviceDesc.Streams[3], c.cc, "/eliot.services.containers.v1.Containers/StreamStats", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &containersStreamStatsClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Containers_StreamStatsClient interface {
+	Recv() (*StatsResponse, error)
+	grpc.ClientStream
+}
+
+type containersStreamStatsClient struct {
+	grpc.ClientStream
+}
+
+func (x *containersStreamStatsClient) Recv() (*StatsResponse, error) {
+	m := new(StatsResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *containersClient) CopyTo(ctx context.Context, opts ...grpc.CallOption) (Containers_CopyToClient, error) {
+	stream, err := grpc.NewClientStream(ctx, &_Containers_serviceDesc.Streams[4], c.cc, "/eliot.services.containers.v1.Containers/CopyTo", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &containersCopyToClient{stream}
+	return x, nil
+}
+
+type Containers_CopyToClient interface {
+	Send(*CopyToRequest) error
+	CloseAndRecv() (*CopyToResponse, error)
+	grpc.ClientStream
+}
+
+type containersCopyToClient struct {
+	grpc.ClientStream
+}
+
+func (x *containersCopyToClient) Send(m *CopyToRequest) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *containersCopyToClient) CloseAndRecv() (*CopyToResponse, error) {
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	m := new(CopyToResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *containersClient) CopyFrom(ctx context.Context, in *CopyFromRequest, opts ...grpc.CallOption) (Containers_CopyFromClient, error) {
+	stream, err := grpc.NewClientStream(ctx, &_Containers_serviceDesc.Streams[5], c.cc, "/eliot.services.containers.v1.Containers/CopyFrom", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &containersCopyFromClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Containers_CopyFromClient interface {
+	Recv() (*CopyFromResponse, error)
+	grpc.ClientStream
+}
+
+type containersCopyFromClient struct {
+	grpc.ClientStream
+}
+
+func (x *containersCopyFromClient) Recv() (*CopyFromResponse, error) {
+	m := new(CopyFromResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *containersClient) Top(ctx context.Context, in *TopRequest, opts ...grpc.CallOption) (*TopResponse, error) {
+	out := new(TopResponse)
+	err := grpc.Invoke(ctx, "/eliot.services.containers.v1.Containers/Top", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *containersClient) PortForward(ctx context.Context, opts ...grpc.CallOption) (Containers_PortForwardClient, error) {
+	stream, err := grpc.NewClientStream(ctx, &_Containers_serviceDesc.Streams[6], c.cc, "/eliot.services.containers.v1.Containers/PortForward", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &containersPortForwardClient{stream}
+	return x, nil
+}
+
+type Containers_PortForwardClient interface {
+	Send(*PortForwardRequest) error
+	Recv() (*PortForwardResponse, error)
+	grpc.ClientStream
+}
+
+type containersPortForwardClient struct {
+	grpc.ClientStream
+}
+
+func (x *containersPortForwardClient) Send(m *PortForwardRequest) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *containersPortForwardClient) Recv() (*PortForwardResponse, error) {
+	m := new(PortForwardResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *containersClient) WatchFile(ctx context.Context, in *WatchFileRequest, opts ...grpc.CallOption) (Containers_WatchFileClient, error) {
+	stream, err := grpc.NewClientStream(ctx, &_Containers_serviceDesc.Streams[7], c.cc, "/eliot.services.containers.v1.Containers/WatchFile", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &containersWatchFileClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Containers_WatchFileClient interface {
+	Recv() (*FileEvent, error)
+	grpc.ClientStream
+}
+
+type containersWatchFileClient struct {
+	grpc.ClientStream
+}
+
+func (x *containersWatchFileClient) Recv() (*FileEvent, error) {
+	m := new(FileEvent)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *containersClient) Status(ctx context.Context, in *StatusRequest, opts ...grpc.CallOption) (*StatusResponse, error) {
+	out := new(StatusResponse)
+	err := grpc.Invoke(ctx, "/eliot.services.containers.v1.Containers/Status", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *containersClient) Inspect(ctx context.Context, in *InspectRequest, opts ...grpc.CallOption) (*InspectResponse, error) {
+	out := new(InspectResponse)
+	err := grpc.Invoke(ctx, "/eliot.services.containers.v1.Containers/Inspect", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *containersClient) SetEnv(ctx context.Context, in *SetEnvRequest, opts ...grpc.CallOption) (*SetEnvResponse, error) {
+	out := new(SetEnvResponse)
+	err := grpc.Invoke(ctx, "/eliot.services.containers.v1.Containers/SetEnv", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *containersClient) StreamDiskUsage(ctx context.Context, in *DiskUsageRequest, opts ...grpc.CallOption) (Containers_StreamDiskUsageClient, error) {
+	stream, err := grpc.NewClientStream(ctx, &_Containers_serviceDesc.Streams[8], c.cc, "/eliot.services.containers.v1.Containers/StreamDiskUsage", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &containersStreamDiskUsageClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Containers_StreamDiskUsageClient interface {
+	Recv() (*DiskUsageResponse, error)
+	grpc.ClientStream
+}
+
+type containersStreamDiskUsageClient struct {
+	grpc.ClientStream
+}
+
+func (x *containersStreamDiskUsageClient) Recv() (*DiskUsageResponse, error) {
+	m := new(DiskUsageResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *containersClient) Checkpoint(ctx context.Context, in *CheckpointRequest, opts ...grpc.CallOption) (Containers_CheckpointClient, error) {
+	stream, err := grpc.NewClientStream(ctx, &_Containers_serviceDesc.Streams[9], c.cc, "/eliot.services.containers.v1.Containers/Checkpoint", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &containersCheckpointClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Containers_CheckpointClient interface {
+	Recv() (*CheckpointResponse, error)
+	grpc.ClientStream
+}
+
+type containersCheckpointClient struct {
+	grpc.ClientStream
+}
+
+func (x *containersCheckpointClient) Recv() (*CheckpointResponse, error) {
+	m := new(CheckpointResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *containersClient) Restore(ctx context.Context, opts ...grpc.CallOption) (Containers_RestoreClient, error) {
+	stream, err := grpc.NewClientStream(ctx, &_Containers_serviceDesc.Streams[10], c.cc, "/eliot.services.containers.v1.Containers/Restore", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &containersRestoreClient{stream}
+	return x, nil
+}
+
+type Containers_RestoreClient interface {
+	Send(*RestoreRequest) error
+	CloseAndRecv() (*RestoreResponse, error)
+	grpc.ClientStream
+}
+
+type containersRestoreClient struct {
+	grpc.ClientStream
+}
+
+func (x *containersRestoreClient) Send(m *RestoreRequest) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *containersRestoreClient) CloseAndRecv() (*RestoreResponse, error) {
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	m := new(RestoreResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *containersClient) Wait(ctx context.Context, in *WaitRequest, opts ...grpc.CallOption) (*WaitResponse, error) {
+	out := new(WaitResponse)
+	err := grpc.Invoke(ctx, "/eliot.services.containers.v1.Containers/Wait", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *containersClient) UpdateResources(ctx context.Context, in *UpdateResourcesRequest, opts ...grpc.CallOption) (*UpdateResourcesResponse, error) {
+	out := new(UpdateResourcesResponse)
+	err := grpc.Invoke(ctx, "/eliot.services.containers.v1.Containers/UpdateResources", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// Server API for Containers service
+
+type ContainersServer interface {
+	Attach(Containers_AttachServer) error
+	Exec(Containers_ExecServer) error
+	Signal(context.Context, *SignalRequest) (*SignalResponse, error)
+	Logs(*LogsRequest, Containers_LogsServer) error
+	Stats(context.Context, *StatsRequest) (*StatsResponse, error)
+	StreamStats(*StatsRequest, Containers_StreamStatsServer) error
+	CopyTo(Containers_CopyToServer) error
+	CopyFrom(*CopyFromRequest, Containers_CopyFromServer) error
+	Top(context.Context, *TopRequest) (*TopResponse, error)
+	PortForward(Containers_PortForwardServer) error
+	WatchFile(*WatchFileRequest, Containers_WatchFileServer) error
+	Status(context.Context, *StatusRequest) (*StatusResponse, error)
+	Inspect(context.Context, *InspectRequest) (*InspectResponse, error)
+	SetEnv(context.Context, *SetEnvRequest) (*SetEnvResponse, error)
+	StreamDiskUsage(*DiskUsageRequest, Containers_StreamDiskUsageServer) error
+	Checkpoint(*CheckpointRequest, Containers_CheckpointServer) error
+	Restore(Containers_RestoreServer) error
+	Wait(context.Context, *WaitRequest) (*WaitResponse, error)
+	UpdateResources(context.Context, *UpdateResourcesRequest) (*UpdateResourcesResponse, error)
+}
+
+func RegisterContainersServer(s *grpc.Server, srv ContainersServer) {
+	s.RegisterService(&_Containers_serviceDesc, srv)
+}
+
+func _Containers_Attach_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(ContainersServer).Attach(&containersAttachServer{stream})
+}
+
+type Containers_AttachServer interface {
+	Send(*StdoutStreamResponse) error
+	Recv() (*StdinStreamRequest, error)
+	grpc.ServerStream
+}
+
+type containersAttachServer struct {
+	grpc.ServerStream
+}
+
+func (x *containersAttachServer) Send(m *StdoutStreamResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *containersAttachServer) Recv() (*StdinStreamRequest, error) {
+	m := new(StdinStreamRequest)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func _Containers_Exec_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(ContainersServer).Exec(&containersExecServer{stream})
+}
+
+type Containers_ExecServer interface {
+	Send(*StdoutStreamResponse) error
+	Recv() (*StdinStreamRequest, error)
+	grpc.ServerStream
+}
+
+type containersExecServer struct {
+	grpc.ServerStream
+}
+
+func (x *containersExecServer) Send(m *StdoutStreamResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *containersExecServer) Recv() (*StdinStreamRequest, error) {
+	m := new(StdinStreamRequest)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func _Containers_Signal_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SignalRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ContainersServer).Signal(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/eliot.services.containers.v1.Containers/Signal",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ContainersServer).Signal(ctx, req.(*SignalRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Containers_Logs_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(LogsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(ContainersServer).Logs(m, &containersLogsServer{stream})
+}
+
+type Containers_LogsServer interface {
+	Send(*LogsStreamResponse) error
+	grpc.ServerStream
+}
+
+type containersLogsServer struct {
+	grpc.ServerStream
+}
+
+func (x *containersLogsServer) Send(m *LogsStreamResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _Containers_Stats_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(StatsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ContainersServer).Stats(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/eliot.services.containers.v1.Containers/Stats",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ContainersServer).Stats(ctx, req.(*StatsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Containers_StreamStats_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(StatsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(ContainersServer).StreamStats(m, &containersStreamStatsServer{stream})
+}
+
+type Containers_StreamStatsServer interface {
+	Send(*StatsResponse) error
+	grpc.ServerStream
+}
+
+type containersStreamStatsServer struct {
+	grpc.ServerStream
+}
+
+func (x *containersStreamStatsServer) Send(m *StatsResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _Containers_CopyTo_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(ContainersServer).CopyTo(&containersCopyToServer{stream})
+}
+
+type Containers_CopyToServer interface {
+	SendAndClose(*CopyToResponse) error
+	Recv() (*CopyToRequest, error)
+	grpc.ServerStream
+}
+
+type containersCopyToServer struct {
+	grpc.ServerStream
+}
+
+func (x *containersCopyToServer) SendAndClose(m *CopyToResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *containersCopyToServer) Recv() (*CopyToRequest, error) {
+	m := new(CopyToRequest)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func _Containers_CopyFrom_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(CopyFromRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(ContainersServer).CopyFrom(m, &containersCopyFromServer{stream})
+}
+
+type Containers_CopyFromServer interface {
+	Send(*CopyFromResponse) error
+	grpc.ServerStream
+}
+
+type containersCopyFromServer struct {
+	grpc.ServerStream
+}
+
+func (x *containersCopyFromServer) Send(m *CopyFromResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _Containers_Top_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TopRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ContainersServer).Top(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/eliot.services.containers.v1.Containers/Top",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ContainersServer).Top(ctx, req.(*TopRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Containers_PortForward_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(ContainersServer).PortForward(&containersPortForwardServer{stream})
+}
+
+type Containers_PortForwardServer interface {
+	Send(*PortForwardResponse) error
+	Recv() (*PortForwardRequest, error)
+	grpc.ServerStream
+}
+
+type containersPortForwardServer struct {
+	grpc.ServerStream
+}
+
+func (x *containersPortForwardServer) Send(m *PortForwardResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *containersPortForwardServer) Recv() (*PortForwardRequest, error) {
+	m := new(PortForwardRequest)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func _Containers_WatchFile_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchFileRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(ContainersServer).WatchFile(m, &containersWatchFileServer{stream})
+}
+
+type Containers_WatchFileServer interface {
+	Send(*FileEvent) error
+	grpc.ServerStream
+}
+
+type containersWatchFileServer struct {
+	grpc.ServerStream
+}
+
+func (x *containersWatchFileServer) Send(m *FileEvent) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _Containers_Status_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(StatusRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ContainersServer).Status(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/eliot.services.containers.v1.Containers/Status",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ContainersServer).Status(ctx, req.(*StatusRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Containers_Inspect_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(InspectRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ContainersServer).Inspect(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/eliot.services.containers.v1.Containers/Inspect",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ContainersServer).Inspect(ctx, req.(*InspectRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Containers_SetEnv_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetEnvRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ContainersServer).SetEnv(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/eliot.services.containers.v1.Containers/SetEnv",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ContainersServer).SetEnv(ctx, req.(*SetEnvRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Containers_StreamDiskUsage_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(DiskUsageRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(ContainersServer).StreamDiskUsage(m, &containersStreamDiskUsageServer{stream})
+}
+
+type Containers_StreamDiskUsageServer interface {
+	Send(*DiskUsageResponse) error
+	grpc.ServerStream
+}
+
+type containersStreamDiskUsageServer struct {
+	grpc.ServerStream
+}
+
+func (x *containersStreamDiskUsageServer) Send(m *DiskUsageResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _Containers_Checkpoint_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(CheckpointRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(ContainersServer).Checkpoint(m, &containersCheckpointServer{stream})
+}
+
+type Containers_CheckpointServer interface {
+	Send(*CheckpointResponse) error
+	grpc.ServerStream
+}
+
+type containersCheckpointServer struct {
+	grpc.ServerStream
+}
+
+func (x *containersCheckpointServer) Send(m *CheckpointResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _Containers_Restore_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(ContainersServer).Restore(&containersRestoreServer{stream})
+}
+
+type Containers_RestoreServer interface {
+	SendAndClose(*RestoreResponse) error
+	Recv() (*RestoreRequest, error)
+	grpc.ServerStream
+}
+
+type containersRestoreServer struct {
+	grpc.ServerStream
+}
+
+func (x *containersRestoreServer) SendAndClose(m *RestoreResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *containersRestoreServer) Recv() (*RestoreRequest, error) {
+	m := new(RestoreRequest)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func _Containers_Wait_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(WaitRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ContainersServer).Wait(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/eliot.services.containers.v1.Containers/Wait",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ContainersServer).Wait(ctx, req.(*WaitRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Containers_UpdateResources_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateResourcesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ContainersServer).UpdateResources(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/eliot.services.containers.v1.Containers/UpdateResources",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ContainersServer).UpdateResources(ctx, req.(*UpdateResourcesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+var _Containers_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "eliot.services.containers.v1.Containers",
+	HandlerType: (*ContainersServer)(nil),
 	Methods: []grpc.MethodDesc{
 		{
 			MethodName: "Signal",
 			Handler:    _Containers_Signal_Handler,
 		},
+		{
+			MethodName: "Stats",
+			Handler:    _Containers_Stats_Handler,
+		},
+		{
+			MethodName: "Top",
+			Handler:    _Containers_Top_Handler,
+		},
+		{
+			MethodName: "Status",
+			Handler:    _Containers_Status_Handler,
+		},
+		{
+			MethodName: "Inspect",
+			Handler:    _Containers_Inspect_Handler,
+		},
+		{
+			MethodName: "SetEnv",
+			Handler:    _Containers_SetEnv_Handler,
+		},
+		{
+			MethodName: "Wait",
+			Handler:    _Containers_Wait_Handler,
+		},
+		{
+			MethodName: "UpdateResources",
+			Handler:    _Containers_UpdateResources_Handler,
+		},
 	},
 	Streams: []grpc.StreamDesc{
 		{
@@ -543,6 +2836,52 @@ var _Containers_serviceDesc = grpc.ServiceDesc{
 			ServerStreams: true,
 			ClientStreams: true,
 		},
+		{
+			StreamName:    "Logs",
+			Handler:       _Containers_Logs_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "StreamStats",
+			Handler:       _Containers_StreamStats_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "CopyTo",
+			Handler:       _Containers_CopyTo_Handler,
+			ClientStreams: true,
+		},
+		{
+			StreamName:    "CopyFrom",
+			Handler:       _Containers_CopyFrom_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "PortForward",
+			Handler:       _Containers_PortForward_Handler,
+			ServerStreams: true,
+			ClientStreams: true,
+		},
+		{
+			StreamName:    "WatchFile",
+			Handler:       _Containers_WatchFile_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "StreamDiskUsage",
+			Handler:       _Containers_StreamDiskUsage_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "Checkpoint",
+			Handler:       _Containers_Checkpoint_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "Restore",
+			Handler:       _Containers_Restore_Handler,
+			ClientStreams: true,
+		},
 	},
 	Metadata: "services/containers/v1/containers.proto",
 }
@@ -14,12 +14,25 @@ It has these top-level messages:
 	Info
 	Label
 	Filesystem
+	ResolveImageRequest
+	ResolveImageResponse
+	EventsRequest
+	Event
+	PruneRequest
+	PruneResponse
+	BuildRequest
+	BuildResponse
+	TagImageRequest
+	TagImageResponse
+	PushImageRequest
+	PushImageResponse
 */
 package node
 
 import proto "github.com/golang/protobuf/proto"
 import fmt "fmt"
 import math "math"
+import eliot_services_pods_v1 "github.com/ernoaapa/eliot/pkg/api/services/pods/v1"
 
 import (
 	context "golang.org/x/net/context"
@@ -88,6 +101,10 @@ type Info struct {
 	Filesystems []*Filesystem `protobuf:"bytes,11,rep,name=filesystems" json:"filesystems,omitempty"`
 	// Seconds since node boot up
 	Uptime uint64 `protobuf:"varint,12,opt,name=uptime" json:"uptime,omitempty"`
+	// API version what the server implements
+	ApiVersion string `protobuf:"bytes,13,opt,name=apiVersion" json:"apiVersion,omitempty"`
+	// Container runtime name and version, e.g. "containerd v1.1.0"
+	RuntimeVersion string `protobuf:"bytes,14,opt,name=runtimeVersion" json:"runtimeVersion,omitempty"`
 }
 
 func (m *Info) Reset()                    { *m = Info{} }
@@ -179,6 +196,20 @@ func (m *Info) GetUptime() uint64 {
 	return 0
 }
 
+func (m *Info) GetApiVersion() string {
+	if m != nil {
+		return m.ApiVersion
+	}
+	return ""
+}
+
+func (m *Info) GetRuntimeVersion() string {
+	if m != nil {
+		return m.RuntimeVersion
+	}
+	return ""
+}
+
 type Label struct {
 	Key   string `protobuf:"bytes,1,opt,name=key" json:"key,omitempty"`
 	Value string `protobuf:"bytes,2,opt,name=value" json:"value,omitempty"`
@@ -265,12 +296,420 @@ func (m *Filesystem) GetAvailable() uint64 {
 	return 0
 }
 
+type ResolveImageRequest struct {
+	// Fully qualified image reference, e.g. docker.io/library/nginx:latest
+	Image string `protobuf:"bytes,1,opt,name=image" json:"image,omitempty"`
+}
+
+func (m *ResolveImageRequest) Reset()                    { *m = ResolveImageRequest{} }
+func (m *ResolveImageRequest) String() string            { return proto.CompactTextString(m) }
+func (*ResolveImageRequest) ProtoMessage()               {}
+func (*ResolveImageRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{5} }
+
+func (m *ResolveImageRequest) GetImage() string {
+	if m != nil {
+		return m.Image
+	}
+	return ""
+}
+
+type ResolveImageResponse struct {
+	// The image reference pinned to the digest, e.g. docker.io/library/nginx:latest@sha256:...
+	Image string `protobuf:"bytes,1,opt,name=image" json:"image,omitempty"`
+	// The image manifest digest in the registry
+	Digest string `protobuf:"bytes,2,opt,name=digest" json:"digest,omitempty"`
+}
+
+func (m *ResolveImageResponse) Reset()                    { *m = ResolveImageResponse{} }
+func (m *ResolveImageResponse) String() string            { return proto.CompactTextString(m) }
+func (*ResolveImageResponse) ProtoMessage()               {}
+func (*ResolveImageResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{6} }
+
+func (m *ResolveImageResponse) GetImage() string {
+	if m != nil {
+		return m.Image
+	}
+	return ""
+}
+
+func (m *ResolveImageResponse) GetDigest() string {
+	if m != nil {
+		return m.Digest
+	}
+	return ""
+}
+
+type EventsRequest struct {
+	// Namespace to watch, empty means all namespaces
+	Namespace string `protobuf:"bytes,1,opt,name=namespace" json:"namespace,omitempty"`
+	// Event types to return, empty means all types
+	Types []string `protobuf:"bytes,2,rep,name=types" json:"types,omitempty"`
+}
+
+func (m *EventsRequest) Reset()                    { *m = EventsRequest{} }
+func (m *EventsRequest) String() string            { return proto.CompactTextString(m) }
+func (*EventsRequest) ProtoMessage()               {}
+func (*EventsRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{7} }
+
+func (m *EventsRequest) GetNamespace() string {
+	if m != nil {
+		return m.Namespace
+	}
+	return ""
+}
+
+func (m *EventsRequest) GetTypes() []string {
+	if m != nil {
+		return m.Types
+	}
+	return nil
+}
+
+type Event struct {
+	// Type of the event, e.g. ContainerExited
+	Type      string `protobuf:"bytes,1,opt,name=type" json:"type,omitempty"`
+	Namespace string `protobuf:"bytes,2,opt,name=namespace" json:"namespace,omitempty"`
+	// Name of the pod the event is about, empty if not related to any pod
+	Pod string `protobuf:"bytes,3,opt,name=pod" json:"pod,omitempty"`
+	// ID of the container the event is about, empty if not related to any container
+	ContainerID string `protobuf:"bytes,4,opt,name=containerID" json:"containerID,omitempty"`
+	// Image reference in image events
+	Image string `protobuf:"bytes,5,opt,name=image" json:"image,omitempty"`
+	// Time of the event in nanoseconds since unix epoch
+	Time    int64  `protobuf:"varint,6,opt,name=time" json:"time,omitempty"`
+	Message string `protobuf:"bytes,7,opt,name=message" json:"message,omitempty"`
+}
+
+func (m *Event) Reset()                    { *m = Event{} }
+func (m *Event) String() string            { return proto.CompactTextString(m) }
+func (*Event) ProtoMessage()               {}
+func (*Event) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{8} }
+
+func (m *Event) GetType() string {
+	if m != nil {
+		return m.Type
+	}
+	return ""
+}
+
+func (m *Event) GetNamespace() string {
+	if m != nil {
+		return m.Namespace
+	}
+	return ""
+}
+
+func (m *Event) GetPod() string {
+	if m != nil {
+		return m.Pod
+	}
+	return ""
+}
+
+func (m *Event) GetContainerID() string {
+	if m != nil {
+		return m.ContainerID
+	}
+	return ""
+}
+
+func (m *Event) GetImage() string {
+	if m != nil {
+		return m.Image
+	}
+	return ""
+}
+
+func (m *Event) GetTime() int64 {
+	if m != nil {
+		return m.Time
+	}
+	return 0
+}
+
+func (m *Event) GetMessage() string {
+	if m != nil {
+		return m.Message
+	}
+	return ""
+}
+
+type PruneRequest struct {
+	Namespace string `protobuf:"bytes,1,opt,name=namespace" json:"namespace,omitempty"`
+	// Keep the pods which finished and the images which were pulled within the duration, in nanoseconds
+	KeepFor int64 `protobuf:"varint,2,opt,name=keepFor" json:"keepFor,omitempty"`
+	// Prune only the pods matching the label selector, and only their images
+	LabelSelector string `protobuf:"bytes,3,opt,name=labelSelector" json:"labelSelector,omitempty"`
+}
+
+func (m *PruneRequest) Reset()                    { *m = PruneRequest{} }
+func (m *PruneRequest) String() string            { return proto.CompactTextString(m) }
+func (*PruneRequest) ProtoMessage()               {}
+func (*PruneRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{9} }
+
+func (m *PruneRequest) GetNamespace() string {
+	if m != nil {
+		return m.Namespace
+	}
+	return ""
+}
+
+func (m *PruneRequest) GetKeepFor() int64 {
+	if m != nil {
+		return m.KeepFor
+	}
+	return 0
+}
+
+func (m *PruneRequest) GetLabelSelector() string {
+	if m != nil {
+		return m.LabelSelector
+	}
+	return ""
+}
+
+type PruneResponse struct {
+	// IDs of the removed containers
+	Containers []string `protobuf:"bytes,1,rep,name=containers" json:"containers,omitempty"`
+	// Names of the removed images
+	Images []string `protobuf:"bytes,2,rep,name=images" json:"images,omitempty"`
+	// Approximate disk space freed, image layers shared with other images are counted in each image
+	ReclaimedBytes uint64 `protobuf:"varint,3,opt,name=reclaimedBytes" json:"reclaimedBytes,omitempty"`
+}
+
+func (m *PruneResponse) Reset()                    { *m = PruneResponse{} }
+func (m *PruneResponse) String() string            { return proto.CompactTextString(m) }
+func (*PruneResponse) ProtoMessage()               {}
+func (*PruneResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{10} }
+
+func (m *PruneResponse) GetContainers() []string {
+	if m != nil {
+		return m.Containers
+	}
+	return nil
+}
+
+func (m *PruneResponse) GetImages() []string {
+	if m != nil {
+		return m.Images
+	}
+	return nil
+}
+
+func (m *PruneResponse) GetReclaimedBytes() uint64 {
+	if m != nil {
+		return m.ReclaimedBytes
+	}
+	return 0
+}
+
+type BuildRequest struct {
+	// Namespace, tags, buildArgs and target are given in the first message
+	Namespace string `protobuf:"bytes,1,opt,name=namespace" json:"namespace,omitempty"`
+	// Image references what to tag the built image with
+	Tags []string `protobuf:"bytes,2,rep,name=tags" json:"tags,omitempty"`
+	// Dockerfile ARG values
+	BuildArgs map[string]string `protobuf:"bytes,3,rep,name=buildArgs" json:"buildArgs,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	// Dockerfile stage to build, empty builds the last stage
+	Target string `protobuf:"bytes,4,opt,name=target" json:"target,omitempty"`
+	// Chunk of the build context tar archive
+	Data []byte `protobuf:"bytes,5,opt,name=data,proto3" json:"data,omitempty"`
+}
+
+func (m *BuildRequest) Reset()                    { *m = BuildRequest{} }
+func (m *BuildRequest) String() string            { return proto.CompactTextString(m) }
+func (*BuildRequest) ProtoMessage()               {}
+func (*BuildRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{11} }
+
+func (m *BuildRequest) GetNamespace() string {
+	if m != nil {
+		return m.Namespace
+	}
+	return ""
+}
+
+func (m *BuildRequest) GetTags() []string {
+	if m != nil {
+		return m.Tags
+	}
+	return nil
+}
+
+func (m *BuildRequest) GetBuildArgs() map[string]string {
+	if m != nil {
+		return m.BuildArgs
+	}
+	return nil
+}
+
+func (m *BuildRequest) GetTarget() string {
+	if m != nil {
+		return m.Target
+	}
+	return ""
+}
+
+func (m *BuildRequest) GetData() []byte {
+	if m != nil {
+		return m.Data
+	}
+	return nil
+}
+
+type BuildResponse struct {
+	// Line of the build output
+	Line string `protobuf:"bytes,1,opt,name=line" json:"line,omitempty"`
+	// True if the line tells why the build failed
+	Error bool `protobuf:"varint,2,opt,name=error" json:"error,omitempty"`
+	// Digest of the built image manifest, set only in the last message
+	Digest string `protobuf:"bytes,3,opt,name=digest" json:"digest,omitempty"`
+}
+
+func (m *BuildResponse) Reset()                    { *m = BuildResponse{} }
+func (m *BuildResponse) String() string            { return proto.CompactTextString(m) }
+func (*BuildResponse) ProtoMessage()               {}
+func (*BuildResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{12} }
+
+func (m *BuildResponse) GetLine() string {
+	if m != nil {
+		return m.Line
+	}
+	return ""
+}
+
+func (m *BuildResponse) GetError() bool {
+	if m != nil {
+		return m.Error
+	}
+	return false
+}
+
+func (m *BuildResponse) GetDigest() string {
+	if m != nil {
+		return m.Digest
+	}
+	return ""
+}
+
+type TagImageRequest struct {
+	Namespace string `protobuf:"bytes,1,opt,name=namespace" json:"namespace,omitempty"`
+	// Image reference to tag, e.g. docker.io/library/myapp:latest
+	Source string `protobuf:"bytes,2,opt,name=source" json:"source,omitempty"`
+	// New image reference, e.g. registry.local/team/myapp:1.0
+	Target string `protobuf:"bytes,3,opt,name=target" json:"target,omitempty"`
+}
+
+func (m *TagImageRequest) Reset()                    { *m = TagImageRequest{} }
+func (m *TagImageRequest) String() string            { return proto.CompactTextString(m) }
+func (*TagImageRequest) ProtoMessage()               {}
+func (*TagImageRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{13} }
+
+func (m *TagImageRequest) GetNamespace() string {
+	if m != nil {
+		return m.Namespace
+	}
+	return ""
+}
+
+func (m *TagImageRequest) GetSource() string {
+	if m != nil {
+		return m.Source
+	}
+	return ""
+}
+
+func (m *TagImageRequest) GetTarget() string {
+	if m != nil {
+		return m.Target
+	}
+	return ""
+}
+
+type TagImageResponse struct {
+}
+
+func (m *TagImageResponse) Reset()                    { *m = TagImageResponse{} }
+func (m *TagImageResponse) String() string            { return proto.CompactTextString(m) }
+func (*TagImageResponse) ProtoMessage()               {}
+func (*TagImageResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{14} }
+
+type PushImageRequest struct {
+	Namespace string `protobuf:"bytes,1,opt,name=namespace" json:"namespace,omitempty"`
+	// Image reference to push, the registry is resolved from the reference
+	Image string `protobuf:"bytes,2,opt,name=image" json:"image,omitempty"`
+	// Registry credentials, empty username means anonymous push
+	Username string `protobuf:"bytes,3,opt,name=username" json:"username,omitempty"`
+	Password string `protobuf:"bytes,4,opt,name=password" json:"password,omitempty"`
+}
+
+func (m *PushImageRequest) Reset()                    { *m = PushImageRequest{} }
+func (m *PushImageRequest) String() string            { return proto.CompactTextString(m) }
+func (*PushImageRequest) ProtoMessage()               {}
+func (*PushImageRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{15} }
+
+func (m *PushImageRequest) GetNamespace() string {
+	if m != nil {
+		return m.Namespace
+	}
+	return ""
+}
+
+func (m *PushImageRequest) GetImage() string {
+	if m != nil {
+		return m.Image
+	}
+	return ""
+}
+
+func (m *PushImageRequest) GetUsername() string {
+	if m != nil {
+		return m.Username
+	}
+	return ""
+}
+
+func (m *PushImageRequest) GetPassword() string {
+	if m != nil {
+		return m.Password
+	}
+	return ""
+}
+
+type PushImageResponse struct {
+	// Upload progress of the image blobs
+	Image *eliot_services_pods_v1.ImageFetch `protobuf:"bytes,1,opt,name=image" json:"image,omitempty"`
+}
+
+func (m *PushImageResponse) Reset()                    { *m = PushImageResponse{} }
+func (m *PushImageResponse) String() string            { return proto.CompactTextString(m) }
+func (*PushImageResponse) ProtoMessage()               {}
+func (*PushImageResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{16} }
+
+func (m *PushImageResponse) GetImage() *eliot_services_pods_v1.ImageFetch {
+	if m != nil {
+		return m.Image
+	}
+	return nil
+}
+
 func init() {
 	proto.RegisterType((*InfoRequest)(nil), "eliot.services.containers.v1.InfoRequest")
 	proto.RegisterType((*InfoResponse)(nil), "eliot.services.containers.v1.InfoResponse")
 	proto.RegisterType((*Info)(nil), "eliot.services.containers.v1.Info")
 	proto.RegisterType((*Label)(nil), "eliot.services.containers.v1.Label")
 	proto.RegisterType((*Filesystem)(nil), "eliot.services.containers.v1.Filesystem")
+	proto.RegisterType((*ResolveImageRequest)(nil), "eliot.services.containers.v1.ResolveImageRequest")
+	proto.RegisterType((*ResolveImageResponse)(nil), "eliot.services.containers.v1.ResolveImageResponse")
+	proto.RegisterType((*EventsRequest)(nil), "eliot.services.containers.v1.EventsRequest")
+	proto.RegisterType((*Event)(nil), "eliot.services.containers.v1.Event")
+	proto.RegisterType((*PruneRequest)(nil), "eliot.services.containers.v1.PruneRequest")
+	proto.RegisterType((*PruneResponse)(nil), "eliot.services.containers.v1.PruneResponse")
+	proto.RegisterType((*BuildRequest)(nil), "eliot.services.containers.v1.BuildRequest")
+	proto.RegisterType((*BuildResponse)(nil), "eliot.services.containers.v1.BuildResponse")
+	proto.RegisterType((*TagImageRequest)(nil), "eliot.services.containers.v1.TagImageRequest")
+	proto.RegisterType((*TagImageResponse)(nil), "eliot.services.containers.v1.TagImageResponse")
+	proto.RegisterType((*PushImageRequest)(nil), "eliot.services.containers.v1.PushImageRequest")
+	proto.RegisterType((*PushImageResponse)(nil), "eliot.services.containers.v1.PushImageResponse")
 }
 
 // Reference imports to suppress errors if they are not otherwise used.
@@ -285,6 +724,12 @@ const _ = grpc.SupportPackageIsVersion4
 
 type NodeClient interface {
 	Info(ctx context.Context, in *InfoRequest, opts ...grpc.CallOption) (*InfoResponse, error)
+	ResolveImage(ctx context.Context, in *ResolveImageRequest, opts ...grpc.CallOption) (*ResolveImageResponse, error)
+	Events(ctx context.Context, in *EventsRequest, opts ...grpc.CallOption) (Node_EventsClient, error)
+	Prune(ctx context.Context, in *PruneRequest, opts ...grpc.CallOption) (*PruneResponse, error)
+	Build(ctx context.Context, opts ...grpc.CallOption) (Node_BuildClient, error)
+	TagImage(ctx context.Context, in *TagImageRequest, opts ...grpc.CallOption) (*TagImageResponse, error)
+	PushImage(ctx context.Context, in *PushImageRequest, opts ...grpc.CallOption) (Node_PushImageClient, error)
 }
 
 type nodeClient struct {
@@ -304,10 +749,138 @@ func (c *nodeClient) Info(ctx context.Context, in *InfoRequest, opts ...grpc.Cal
 	return out, nil
 }
 
+func (c *nodeClient) ResolveImage(ctx context.Context, in *ResolveImageRequest, opts ...grpc.CallOption) (*ResolveImageResponse, error) {
+	out := new(ResolveImageResponse)
+	err := grpc.Invoke(ctx, "/eliot.services.containers.v1.Node/ResolveImage", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *nodeClient) Events(ctx context.Context, in *EventsRequest, opts ...grpc.CallOption) (Node_EventsClient, error) {
+	stream, err := grpc.NewClientStream(ctx, &_Node_serviceDesc.Streams[0], c.cc, "/eliot.services.containers.v1.Node/Events", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &nodeEventsClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Node_EventsClient interface {
+	Recv() (*Event, error)
+	grpc.ClientStream
+}
+
+type nodeEventsClient struct {
+	grpc.ClientStream
+}
+
+func (x *nodeEventsClient) Recv() (*Event, error) {
+	m := new(Event)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *nodeClient) Prune(ctx context.Context, in *PruneRequest, opts ...grpc.CallOption) (*PruneResponse, error) {
+	out := new(PruneResponse)
+	err := grpc.Invoke(ctx, "/eliot.services.containers.v1.Node/Prune", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *nodeClient) Build(ctx context.Context, opts ...grpc.CallOption) (Node_BuildClient, error) {
+	stream, err := grpc.NewClientStream(ctx, &_Node_serviceDesc.Streams[1], c.cc, "/eliot.services.containers.v1.Node/Build", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &nodeBuildClient{stream}
+	return x, nil
+}
+
+type Node_BuildClient interface {
+	Send(*BuildRequest) error
+	Recv() (*BuildResponse, error)
+	grpc.ClientStream
+}
+
+type nodeBuildClient struct {
+	grpc.ClientStream
+}
+
+func (x *nodeBuildClient) Send(m *BuildRequest) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *nodeBuildClient) Recv() (*BuildResponse, error) {
+	m := new(BuildResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *nodeClient) TagImage(ctx context.Context, in *TagImageRequest, opts ...grpc.CallOption) (*TagImageResponse, error) {
+	out := new(TagImageResponse)
+	err := grpc.Invoke(ctx, "/eliot.services.containers.v1.Node/TagImage", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *nodeClient) PushImage(ctx context.Context, in *PushImageRequest, opts ...grpc.CallOption) (Node_PushImageClient, error) {
+	stream, err := grpc.NewClientStream(ctx, &_Node_serviceDesc.Streams[2], c.cc, "/eliot.services.containers.v1.Node/PushImage", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &nodePushImageClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Node_PushImageClient interface {
+	Recv() (*PushImageResponse, error)
+	grpc.ClientStream
+}
+
+type nodePushImageClient struct {
+	grpc.ClientStream
+}
+
+func (x *nodePushImageClient) Recv() (*PushImageResponse, error) {
+	m := new(PushImageResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
 // Server API for Node service
 
 type NodeServer interface {
 	Info(context.Context, *InfoRequest) (*InfoResponse, error)
+	ResolveImage(context.Context, *ResolveImageRequest) (*ResolveImageResponse, error)
+	Events(*EventsRequest, Node_EventsServer) error
+	Prune(context.Context, *PruneRequest) (*PruneResponse, error)
+	Build(Node_BuildServer) error
+	TagImage(context.Context, *TagImageRequest) (*TagImageResponse, error)
+	PushImage(*PushImageRequest, Node_PushImageServer) error
 }
 
 func RegisterNodeServer(s *grpc.Server, srv NodeServer) {
@@ -332,6 +905,128 @@ func _Node_Info_Handler(srv interface{}, ctx context.Context, dec func(interface
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Node_ResolveImage_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ResolveImageRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(NodeServer).ResolveImage(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/eliot.services.containers.v1.Node/ResolveImage",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(NodeServer).ResolveImage(ctx, req.(*ResolveImageRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Node_Events_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(EventsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(NodeServer).Events(m, &nodeEventsServer{stream})
+}
+
+type Node_EventsServer interface {
+	Send(*Event) error
+	grpc.ServerStream
+}
+
+type nodeEventsServer struct {
+	grpc.ServerStream
+}
+
+func (x *nodeEventsServer) Send(m *Event) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _Node_Prune_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PruneRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(NodeServer).Prune(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/eliot.services.containers.v1.Node/Prune",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(NodeServer).Prune(ctx, req.(*PruneRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Node_Build_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(NodeServer).Build(&nodeBuildServer{stream})
+}
+
+type Node_BuildServer interface {
+	Send(*BuildResponse) error
+	Recv() (*BuildRequest, error)
+	grpc.ServerStream
+}
+
+type nodeBuildServer struct {
+	grpc.ServerStream
+}
+
+func (x *nodeBuildServer) Send(m *BuildResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *nodeBuildServer) Recv() (*BuildRequest, error) {
+	m := new(BuildRequest)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func _Node_TagImage_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TagImageRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(NodeServer).TagImage(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/eliot.services.containers.v1.Node/TagImage",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(NodeServer).TagImage(ctx, req.(*TagImageRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Node_PushImage_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(PushImageRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(NodeServer).PushImage(m, &nodePushImageServer{stream})
+}
+
+type Node_PushImageServer interface {
+	Send(*PushImageResponse) error
+	grpc.ServerStream
+}
+
+type nodePushImageServer struct {
+	grpc.ServerStream
+}
+
+func (x *nodePushImageServer) Send(m *PushImageResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
 var _Node_serviceDesc = grpc.ServiceDesc{
 	ServiceName: "eliot.services.containers.v1.Node",
 	HandlerType: (*NodeServer)(nil),
@@ -340,8 +1035,37 @@ var _Node_serviceDesc = grpc.ServiceDesc{
 			MethodName: "Info",
 			Handler:    _Node_Info_Handler,
 		},
+		{
+			MethodName: "ResolveImage",
+			Handler:    _Node_ResolveImage_Handler,
+		},
+		{
+			MethodName: "Prune",
+			Handler:    _Node_Prune_Handler,
+		},
+		{
+			MethodName: "TagImage",
+			Handler:    _Node_TagImage_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "Events",
+			Handler:       _Node_Events_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "Build",
+			Handler:       _Node_Build_Handler,
+			ServerStreams: true,
+			ClientStreams: true,
+		},
+		{
+			StreamName:    "PushImage",
+			Handler:       _Node_PushImage_Handler,
+			ServerStreams: true,
+		},
 	},
-	Streams:  []grpc.StreamDesc{},
 	Metadata: "services/node/v1/node.proto",
 }
 
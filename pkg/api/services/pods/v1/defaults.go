@@ -20,5 +20,6 @@ func Default(pod *Pod) *Pod {
 	}
 
 	pod.Spec.Containers = containers.Defaults(pod.Spec.Containers)
+	pod.Spec.InitContainers = containers.Defaults(pod.Spec.InitContainers)
 	return pod
 }
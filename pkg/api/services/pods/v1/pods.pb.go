@@ -22,6 +22,23 @@ It has these top-level messages:
 	Pod
 	PodSpec
 	PodStatus
+	UpdatePodRequest
+	UpdatePodResponse
+	WatchPodsRequest
+	WatchPodsStreamResponse
+	RestartPodRequest
+	RestartPodResponse
+	ListNamespacesRequest
+	ListNamespacesResponse
+	CreateNamespaceRequest
+	CreateNamespaceResponse
+	UpdatePodLabelsRequest
+	UpdatePodLabelsResponse
+	RenamePodRequest
+	RenamePodResponse
+	CordonPodRequest
+	CordonPodResponse
+	RetainedVolume
 */
 package pods
 
@@ -50,6 +67,8 @@ const _ = proto.ProtoPackageIsVersion2 // please upgrade the proto package
 type CreatePodRequest struct {
 	Pod *Pod `protobuf:"bytes,1,opt,name=pod" json:"pod,omitempty"`
 	Tty bool `protobuf:"varint,2,opt,name=tty" json:"tty,omitempty"`
+	// Only validate the pod and return the pod what would be created, without creating anything
+	DryRun bool `protobuf:"varint,3,opt,name=dryRun" json:"dryRun,omitempty"`
 }
 
 func (m *CreatePodRequest) Reset()                    { *m = CreatePodRequest{} }
@@ -71,8 +90,17 @@ func (m *CreatePodRequest) GetTty() bool {
 	return false
 }
 
+func (m *CreatePodRequest) GetDryRun() bool {
+	if m != nil {
+		return m.DryRun
+	}
+	return false
+}
+
 type CreatePodStreamResponse struct {
 	Images []*ImageFetch `protobuf:"bytes,1,rep,name=images" json:"images,omitempty"`
+	// The pod what would be created, set only in dry run
+	Pod *Pod `protobuf:"bytes,2,opt,name=pod" json:"pod,omitempty"`
 }
 
 func (m *CreatePodStreamResponse) Reset()                    { *m = CreatePodStreamResponse{} }
@@ -87,6 +115,13 @@ func (m *CreatePodStreamResponse) GetImages() []*ImageFetch {
 	return nil
 }
 
+func (m *CreatePodStreamResponse) GetPod() *Pod {
+	if m != nil {
+		return m.Pod
+	}
+	return nil
+}
+
 type ImageFetch struct {
 	ContainerID string              `protobuf:"bytes,1,opt,name=containerID" json:"containerID,omitempty"`
 	Image       string              `protobuf:"bytes,2,opt,name=image" json:"image,omitempty"`
@@ -226,6 +261,13 @@ func (m *StartPodResponse) GetPod() *Pod {
 type DeletePodRequest struct {
 	Namespace string `protobuf:"bytes,1,opt,name=namespace" json:"namespace,omitempty"`
 	Name      string `protobuf:"bytes,2,opt,name=name" json:"name,omitempty"`
+	// How long to wait containers to exit after SIGTERM before SIGKILL, in nanoseconds.
+	// Zero means immediate kill.
+	GracePeriod int64 `protobuf:"varint,3,opt,name=gracePeriod" json:"gracePeriod,omitempty"`
+	// Only return the pod what would be deleted, without deleting anything
+	DryRun bool `protobuf:"varint,4,opt,name=dryRun" json:"dryRun,omitempty"`
+	// Remove also the pod volumes, which are not mounted by other pods
+	Cascade bool `protobuf:"varint,5,opt,name=cascade" json:"cascade,omitempty"`
 }
 
 func (m *DeletePodRequest) Reset()                    { *m = DeletePodRequest{} }
@@ -247,8 +289,33 @@ func (m *DeletePodRequest) GetName() string {
 	return ""
 }
 
+func (m *DeletePodRequest) GetGracePeriod() int64 {
+	if m != nil {
+		return m.GracePeriod
+	}
+	return 0
+}
+
+func (m *DeletePodRequest) GetDryRun() bool {
+	if m != nil {
+		return m.DryRun
+	}
+	return false
+}
+
+func (m *DeletePodRequest) GetCascade() bool {
+	if m != nil {
+		return m.Cascade
+	}
+	return false
+}
+
 type DeletePodResponse struct {
 	Pod *Pod `protobuf:"bytes,1,opt,name=pod" json:"pod,omitempty"`
+	// Paths of the removed pod volumes, with cascade
+	RemovedVolumes []string `protobuf:"bytes,2,rep,name=removedVolumes" json:"removedVolumes,omitempty"`
+	// The pod volumes which were not removed because other pods still mount them, with cascade
+	RetainedVolumes []*RetainedVolume `protobuf:"bytes,3,rep,name=retainedVolumes" json:"retainedVolumes,omitempty"`
 }
 
 func (m *DeletePodResponse) Reset()                    { *m = DeletePodResponse{} }
@@ -263,8 +330,28 @@ func (m *DeletePodResponse) GetPod() *Pod {
 	return nil
 }
 
+func (m *DeletePodResponse) GetRemovedVolumes() []string {
+	if m != nil {
+		return m.RemovedVolumes
+	}
+	return nil
+}
+
+func (m *DeletePodResponse) GetRetainedVolumes() []*RetainedVolume {
+	if m != nil {
+		return m.RetainedVolumes
+	}
+	return nil
+}
+
 type ListPodsRequest struct {
 	Namespace string `protobuf:"bytes,1,opt,name=namespace" json:"namespace,omitempty"`
+	// Label selector, e.g. "app=nginx,env!=dev,tier". Empty selects all pods.
+	LabelSelector string `protobuf:"bytes,2,opt,name=labelSelector" json:"labelSelector,omitempty"`
+	// Maximum number of pods to return, zero means all pods
+	PageSize int32 `protobuf:"varint,3,opt,name=pageSize" json:"pageSize,omitempty"`
+	// Token from previous response nextPageToken to continue the listing, empty starts from the beginning
+	PageToken string `protobuf:"bytes,4,opt,name=pageToken" json:"pageToken,omitempty"`
 }
 
 func (m *ListPodsRequest) Reset()                    { *m = ListPodsRequest{} }
@@ -279,8 +366,31 @@ func (m *ListPodsRequest) GetNamespace() string {
 	return ""
 }
 
+func (m *ListPodsRequest) GetLabelSelector() string {
+	if m != nil {
+		return m.LabelSelector
+	}
+	return ""
+}
+
+func (m *ListPodsRequest) GetPageSize() int32 {
+	if m != nil {
+		return m.PageSize
+	}
+	return 0
+}
+
+func (m *ListPodsRequest) GetPageToken() string {
+	if m != nil {
+		return m.PageToken
+	}
+	return ""
+}
+
 type ListPodsResponse struct {
 	Pods []*Pod `protobuf:"bytes,1,rep,name=pods" json:"pods,omitempty"`
+	// Token to get the next page, empty if this is the last page
+	NextPageToken string `protobuf:"bytes,2,opt,name=nextPageToken" json:"nextPageToken,omitempty"`
 }
 
 func (m *ListPodsResponse) Reset()                    { *m = ListPodsResponse{} }
@@ -295,6 +405,13 @@ func (m *ListPodsResponse) GetPods() []*Pod {
 	return nil
 }
 
+func (m *ListPodsResponse) GetNextPageToken() string {
+	if m != nil {
+		return m.NextPageToken
+	}
+	return ""
+}
+
 type Pod struct {
 	Metadata *cand_core.ResourceMetadata `protobuf:"bytes,1,opt,name=metadata" json:"metadata,omitempty"`
 	Spec     *PodSpec                    `protobuf:"bytes,2,opt,name=spec" json:"spec,omitempty"`
@@ -332,6 +449,8 @@ type PodSpec struct {
 	HostNetwork   bool                                     `protobuf:"varint,2,opt,name=hostNetwork" json:"hostNetwork,omitempty"`
 	HostPID       bool                                     `protobuf:"varint,3,opt,name=hostPID" json:"hostPID,omitempty"`
 	RestartPolicy string                                   `protobuf:"bytes,4,opt,name=restartPolicy" json:"restartPolicy,omitempty"`
+	// Init containers run one at a time to completion before the containers get started
+	InitContainers []*cand_services_containers_v1.Container `protobuf:"bytes,5,rep,name=initContainers" json:"initContainers,omitempty"`
 }
 
 func (m *PodSpec) Reset()                    { *m = PodSpec{} }
@@ -367,9 +486,20 @@ func (m *PodSpec) GetRestartPolicy() string {
 	return ""
 }
 
+func (m *PodSpec) GetInitContainers() []*cand_services_containers_v1.Container {
+	if m != nil {
+		return m.InitContainers
+	}
+	return nil
+}
+
 type PodStatus struct {
 	ContainerStatuses []*cand_services_containers_v1.ContainerStatus `protobuf:"bytes,1,rep,name=containerStatuses" json:"containerStatuses,omitempty"`
 	Hostname          string                                         `protobuf:"bytes,2,opt,name=hostname" json:"hostname,omitempty"`
+	// Statuses of the init containers
+	InitContainerStatuses []*cand_services_containers_v1.ContainerStatus `protobuf:"bytes,3,rep,name=initContainerStatuses" json:"initContainerStatuses,omitempty"`
+	// Cordoned pod containers are not restarted by the restart policy
+	Cordoned bool `protobuf:"varint,4,opt,name=cordoned" json:"cordoned,omitempty"`
 }
 
 func (m *PodStatus) Reset()                    { *m = PodStatus{} }
@@ -391,6 +521,404 @@ func (m *PodStatus) GetHostname() string {
 	return ""
 }
 
+func (m *PodStatus) GetInitContainerStatuses() []*cand_services_containers_v1.ContainerStatus {
+	if m != nil {
+		return m.InitContainerStatuses
+	}
+	return nil
+}
+
+func (m *PodStatus) GetCordoned() bool {
+	if m != nil {
+		return m.Cordoned
+	}
+	return false
+}
+
+type UpdatePodRequest struct {
+	Pod *Pod `protobuf:"bytes,1,opt,name=pod" json:"pod,omitempty"`
+}
+
+func (m *UpdatePodRequest) Reset()                    { *m = UpdatePodRequest{} }
+func (m *UpdatePodRequest) String() string            { return proto.CompactTextString(m) }
+func (*UpdatePodRequest) ProtoMessage()               {}
+func (*UpdatePodRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{13} }
+
+func (m *UpdatePodRequest) GetPod() *Pod {
+	if m != nil {
+		return m.Pod
+	}
+	return nil
+}
+
+type UpdatePodResponse struct {
+	Pod *Pod `protobuf:"bytes,1,opt,name=pod" json:"pod,omitempty"`
+	// Names of the containers created, removed and recreated by the update
+	Added     []string `protobuf:"bytes,2,rep,name=added" json:"added,omitempty"`
+	Removed   []string `protobuf:"bytes,3,rep,name=removed" json:"removed,omitempty"`
+	Restarted []string `protobuf:"bytes,4,rep,name=restarted" json:"restarted,omitempty"`
+}
+
+func (m *UpdatePodResponse) Reset()                    { *m = UpdatePodResponse{} }
+func (m *UpdatePodResponse) String() string            { return proto.CompactTextString(m) }
+func (*UpdatePodResponse) ProtoMessage()               {}
+func (*UpdatePodResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{14} }
+
+func (m *UpdatePodResponse) GetPod() *Pod {
+	if m != nil {
+		return m.Pod
+	}
+	return nil
+}
+
+func (m *UpdatePodResponse) GetAdded() []string {
+	if m != nil {
+		return m.Added
+	}
+	return nil
+}
+
+func (m *UpdatePodResponse) GetRemoved() []string {
+	if m != nil {
+		return m.Removed
+	}
+	return nil
+}
+
+func (m *UpdatePodResponse) GetRestarted() []string {
+	if m != nil {
+		return m.Restarted
+	}
+	return nil
+}
+
+type WatchPodsRequest struct {
+	Namespace string `protobuf:"bytes,1,opt,name=namespace" json:"namespace,omitempty"`
+}
+
+func (m *WatchPodsRequest) Reset()                    { *m = WatchPodsRequest{} }
+func (m *WatchPodsRequest) String() string            { return proto.CompactTextString(m) }
+func (*WatchPodsRequest) ProtoMessage()               {}
+func (*WatchPodsRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{15} }
+
+func (m *WatchPodsRequest) GetNamespace() string {
+	if m != nil {
+		return m.Namespace
+	}
+	return ""
+}
+
+type WatchPodsStreamResponse struct {
+	// Event type, one of Added, Modified or Deleted
+	Type string `protobuf:"bytes,1,opt,name=type" json:"type,omitempty"`
+	Pod  *Pod   `protobuf:"bytes,2,opt,name=pod" json:"pod,omitempty"`
+}
+
+func (m *WatchPodsStreamResponse) Reset()                    { *m = WatchPodsStreamResponse{} }
+func (m *WatchPodsStreamResponse) String() string            { return proto.CompactTextString(m) }
+func (*WatchPodsStreamResponse) ProtoMessage()               {}
+func (*WatchPodsStreamResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{16} }
+
+func (m *WatchPodsStreamResponse) GetType() string {
+	if m != nil {
+		return m.Type
+	}
+	return ""
+}
+
+func (m *WatchPodsStreamResponse) GetPod() *Pod {
+	if m != nil {
+		return m.Pod
+	}
+	return nil
+}
+
+type RestartPodRequest struct {
+	Namespace string `protobuf:"bytes,1,opt,name=namespace" json:"namespace,omitempty"`
+	Name      string `protobuf:"bytes,2,opt,name=name" json:"name,omitempty"`
+	// How long to wait containers to exit after SIGTERM before SIGKILL, in nanoseconds.
+	// Zero means immediate kill.
+	GracePeriod int64 `protobuf:"varint,3,opt,name=gracePeriod" json:"gracePeriod,omitempty"`
+}
+
+func (m *RestartPodRequest) Reset()                    { *m = RestartPodRequest{} }
+func (m *RestartPodRequest) String() string            { return proto.CompactTextString(m) }
+func (*RestartPodRequest) ProtoMessage()               {}
+func (*RestartPodRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{17} }
+
+func (m *RestartPodRequest) GetNamespace() string {
+	if m != nil {
+		return m.Namespace
+	}
+	return ""
+}
+
+func (m *RestartPodRequest) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *RestartPodRequest) GetGracePeriod() int64 {
+	if m != nil {
+		return m.GracePeriod
+	}
+	return 0
+}
+
+type RestartPodResponse struct {
+	Pod *Pod `protobuf:"bytes,1,opt,name=pod" json:"pod,omitempty"`
+}
+
+func (m *RestartPodResponse) Reset()                    { *m = RestartPodResponse{} }
+func (m *RestartPodResponse) String() string            { return proto.CompactTextString(m) }
+func (*RestartPodResponse) ProtoMessage()               {}
+func (*RestartPodResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{18} }
+
+func (m *RestartPodResponse) GetPod() *Pod {
+	if m != nil {
+		return m.Pod
+	}
+	return nil
+}
+
+type ListNamespacesRequest struct {
+}
+
+func (m *ListNamespacesRequest) Reset()                    { *m = ListNamespacesRequest{} }
+func (m *ListNamespacesRequest) String() string            { return proto.CompactTextString(m) }
+func (*ListNamespacesRequest) ProtoMessage()               {}
+func (*ListNamespacesRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{19} }
+
+type ListNamespacesResponse struct {
+	Namespaces []string `protobuf:"bytes,1,rep,name=namespaces" json:"namespaces,omitempty"`
+}
+
+func (m *ListNamespacesResponse) Reset()                    { *m = ListNamespacesResponse{} }
+func (m *ListNamespacesResponse) String() string            { return proto.CompactTextString(m) }
+func (*ListNamespacesResponse) ProtoMessage()               {}
+func (*ListNamespacesResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{20} }
+
+func (m *ListNamespacesResponse) GetNamespaces() []string {
+	if m != nil {
+		return m.Namespaces
+	}
+	return nil
+}
+
+type CreateNamespaceRequest struct {
+	Name string `protobuf:"bytes,1,opt,name=name" json:"name,omitempty"`
+}
+
+func (m *CreateNamespaceRequest) Reset()                    { *m = CreateNamespaceRequest{} }
+func (m *CreateNamespaceRequest) String() string            { return proto.CompactTextString(m) }
+func (*CreateNamespaceRequest) ProtoMessage()               {}
+func (*CreateNamespaceRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{21} }
+
+func (m *CreateNamespaceRequest) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+type CreateNamespaceResponse struct {
+}
+
+func (m *CreateNamespaceResponse) Reset()                    { *m = CreateNamespaceResponse{} }
+func (m *CreateNamespaceResponse) String() string            { return proto.CompactTextString(m) }
+func (*CreateNamespaceResponse) ProtoMessage()               {}
+func (*CreateNamespaceResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{22} }
+
+type UpdatePodLabelsRequest struct {
+	Namespace string            `protobuf:"bytes,1,opt,name=namespace" json:"namespace,omitempty"`
+	Name      string            `protobuf:"bytes,2,opt,name=name" json:"name,omitempty"`
+	Labels    map[string]string `protobuf:"bytes,3,rep,name=labels" json:"labels,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	// Merge the labels to the existing labels instead of replacing all of them
+	Merge bool `protobuf:"varint,4,opt,name=merge" json:"merge,omitempty"`
+	// Label keys to remove in merge mode
+	Remove []string `protobuf:"bytes,5,rep,name=remove" json:"remove,omitempty"`
+}
+
+func (m *UpdatePodLabelsRequest) Reset()                    { *m = UpdatePodLabelsRequest{} }
+func (m *UpdatePodLabelsRequest) String() string            { return proto.CompactTextString(m) }
+func (*UpdatePodLabelsRequest) ProtoMessage()               {}
+func (*UpdatePodLabelsRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{23} }
+
+func (m *UpdatePodLabelsRequest) GetNamespace() string {
+	if m != nil {
+		return m.Namespace
+	}
+	return ""
+}
+
+func (m *UpdatePodLabelsRequest) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *UpdatePodLabelsRequest) GetLabels() map[string]string {
+	if m != nil {
+		return m.Labels
+	}
+	return nil
+}
+
+func (m *UpdatePodLabelsRequest) GetMerge() bool {
+	if m != nil {
+		return m.Merge
+	}
+	return false
+}
+
+func (m *UpdatePodLabelsRequest) GetRemove() []string {
+	if m != nil {
+		return m.Remove
+	}
+	return nil
+}
+
+type UpdatePodLabelsResponse struct {
+	Pod *Pod `protobuf:"bytes,1,opt,name=pod" json:"pod,omitempty"`
+}
+
+func (m *UpdatePodLabelsResponse) Reset()                    { *m = UpdatePodLabelsResponse{} }
+func (m *UpdatePodLabelsResponse) String() string            { return proto.CompactTextString(m) }
+func (*UpdatePodLabelsResponse) ProtoMessage()               {}
+func (*UpdatePodLabelsResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{24} }
+
+func (m *UpdatePodLabelsResponse) GetPod() *Pod {
+	if m != nil {
+		return m.Pod
+	}
+	return nil
+}
+
+type RenamePodRequest struct {
+	Namespace string `protobuf:"bytes,1,opt,name=namespace" json:"namespace,omitempty"`
+	Name      string `protobuf:"bytes,2,opt,name=name" json:"name,omitempty"`
+	NewName   string `protobuf:"bytes,3,opt,name=newName" json:"newName,omitempty"`
+}
+
+func (m *RenamePodRequest) Reset()                    { *m = RenamePodRequest{} }
+func (m *RenamePodRequest) String() string            { return proto.CompactTextString(m) }
+func (*RenamePodRequest) ProtoMessage()               {}
+func (*RenamePodRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{25} }
+
+func (m *RenamePodRequest) GetNamespace() string {
+	if m != nil {
+		return m.Namespace
+	}
+	return ""
+}
+
+func (m *RenamePodRequest) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *RenamePodRequest) GetNewName() string {
+	if m != nil {
+		return m.NewName
+	}
+	return ""
+}
+
+type RenamePodResponse struct {
+	Pod *Pod `protobuf:"bytes,1,opt,name=pod" json:"pod,omitempty"`
+}
+
+func (m *RenamePodResponse) Reset()                    { *m = RenamePodResponse{} }
+func (m *RenamePodResponse) String() string            { return proto.CompactTextString(m) }
+func (*RenamePodResponse) ProtoMessage()               {}
+func (*RenamePodResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{26} }
+
+func (m *RenamePodResponse) GetPod() *Pod {
+	if m != nil {
+		return m.Pod
+	}
+	return nil
+}
+
+type CordonPodRequest struct {
+	Namespace string `protobuf:"bytes,1,opt,name=namespace" json:"namespace,omitempty"`
+	Name      string `protobuf:"bytes,2,opt,name=name" json:"name,omitempty"`
+	// True cordons the pod, false resumes the restarts
+	Cordoned bool `protobuf:"varint,3,opt,name=cordoned" json:"cordoned,omitempty"`
+}
+
+func (m *CordonPodRequest) Reset()                    { *m = CordonPodRequest{} }
+func (m *CordonPodRequest) String() string            { return proto.CompactTextString(m) }
+func (*CordonPodRequest) ProtoMessage()               {}
+func (*CordonPodRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{27} }
+
+func (m *CordonPodRequest) GetNamespace() string {
+	if m != nil {
+		return m.Namespace
+	}
+	return ""
+}
+
+func (m *CordonPodRequest) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *CordonPodRequest) GetCordoned() bool {
+	if m != nil {
+		return m.Cordoned
+	}
+	return false
+}
+
+type CordonPodResponse struct {
+	Pod *Pod `protobuf:"bytes,1,opt,name=pod" json:"pod,omitempty"`
+}
+
+func (m *CordonPodResponse) Reset()                    { *m = CordonPodResponse{} }
+func (m *CordonPodResponse) String() string            { return proto.CompactTextString(m) }
+func (*CordonPodResponse) ProtoMessage()               {}
+func (*CordonPodResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{28} }
+
+func (m *CordonPodResponse) GetPod() *Pod {
+	if m != nil {
+		return m.Pod
+	}
+	return nil
+}
+
+type RetainedVolume struct {
+	Path string `protobuf:"bytes,1,opt,name=path" json:"path,omitempty"`
+	// The other pods which still mount the volume, as namespace/name
+	Pods []string `protobuf:"bytes,2,rep,name=pods" json:"pods,omitempty"`
+}
+
+func (m *RetainedVolume) Reset()                    { *m = RetainedVolume{} }
+func (m *RetainedVolume) String() string            { return proto.CompactTextString(m) }
+func (*RetainedVolume) ProtoMessage()               {}
+func (*RetainedVolume) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{29} }
+
+func (m *RetainedVolume) GetPath() string {
+	if m != nil {
+		return m.Path
+	}
+	return ""
+}
+
+func (m *RetainedVolume) GetPods() []string {
+	if m != nil {
+		return m.Pods
+	}
+	return nil
+}
+
 func init() {
 	proto.RegisterType((*CreatePodRequest)(nil), "cand.services.pods.v1.CreatePodRequest")
 	proto.RegisterType((*CreatePodStreamResponse)(nil), "cand.services.pods.v1.CreatePodStreamResponse")
@@ -405,6 +933,23 @@ func init() {
 	proto.RegisterType((*Pod)(nil), "cand.services.pods.v1.Pod")
 	proto.RegisterType((*PodSpec)(nil), "cand.services.pods.v1.PodSpec")
 	proto.RegisterType((*PodStatus)(nil), "cand.services.pods.v1.PodStatus")
+	proto.RegisterType((*UpdatePodRequest)(nil), "cand.services.pods.v1.UpdatePodRequest")
+	proto.RegisterType((*UpdatePodResponse)(nil), "cand.services.pods.v1.UpdatePodResponse")
+	proto.RegisterType((*WatchPodsRequest)(nil), "cand.services.pods.v1.WatchPodsRequest")
+	proto.RegisterType((*WatchPodsStreamResponse)(nil), "cand.services.pods.v1.WatchPodsStreamResponse")
+	proto.RegisterType((*RestartPodRequest)(nil), "cand.services.pods.v1.RestartPodRequest")
+	proto.RegisterType((*RestartPodResponse)(nil), "cand.services.pods.v1.RestartPodResponse")
+	proto.RegisterType((*ListNamespacesRequest)(nil), "cand.services.pods.v1.ListNamespacesRequest")
+	proto.RegisterType((*ListNamespacesResponse)(nil), "cand.services.pods.v1.ListNamespacesResponse")
+	proto.RegisterType((*CreateNamespaceRequest)(nil), "cand.services.pods.v1.CreateNamespaceRequest")
+	proto.RegisterType((*CreateNamespaceResponse)(nil), "cand.services.pods.v1.CreateNamespaceResponse")
+	proto.RegisterType((*UpdatePodLabelsRequest)(nil), "cand.services.pods.v1.UpdatePodLabelsRequest")
+	proto.RegisterType((*UpdatePodLabelsResponse)(nil), "cand.services.pods.v1.UpdatePodLabelsResponse")
+	proto.RegisterType((*RenamePodRequest)(nil), "cand.services.pods.v1.RenamePodRequest")
+	proto.RegisterType((*RenamePodResponse)(nil), "cand.services.pods.v1.RenamePodResponse")
+	proto.RegisterType((*CordonPodRequest)(nil), "cand.services.pods.v1.CordonPodRequest")
+	proto.RegisterType((*CordonPodResponse)(nil), "cand.services.pods.v1.CordonPodResponse")
+	proto.RegisterType((*RetainedVolume)(nil), "cand.services.pods.v1.RetainedVolume")
 }
 
 // Reference imports to suppress errors if they are not otherwise used.
@@ -422,6 +967,14 @@ type PodsClient interface {
 	Start(ctx context.Context, in *StartPodRequest, opts ...grpc.CallOption) (*StartPodResponse, error)
 	Delete(ctx context.Context, in *DeletePodRequest, opts ...grpc.CallOption) (*DeletePodResponse, error)
 	List(ctx context.Context, in *ListPodsRequest, opts ...grpc.CallOption) (*ListPodsResponse, error)
+	Update(ctx context.Context, in *UpdatePodRequest, opts ...grpc.CallOption) (*UpdatePodResponse, error)
+	Watch(ctx context.Context, in *WatchPodsRequest, opts ...grpc.CallOption) (Pods_WatchClient, error)
+	Restart(ctx context.Context, in *RestartPodRequest, opts ...grpc.CallOption) (*RestartPodResponse, error)
+	ListNamespaces(ctx context.Context, in *ListNamespacesRequest, opts ...grpc.CallOption) (*ListNamespacesResponse, error)
+	CreateNamespace(ctx context.Context, in *CreateNamespaceRequest, opts ...grpc.CallOption) (*CreateNamespaceResponse, error)
+	UpdateLabels(ctx context.Context, in *UpdatePodLabelsRequest, opts ...grpc.CallOption) (*UpdatePodLabelsResponse, error)
+	Rename(ctx context.Context, in *RenamePodRequest, opts ...grpc.CallOption) (*RenamePodResponse, error)
+	Cordon(ctx context.Context, in *CordonPodRequest, opts ...grpc.CallOption) (*CordonPodResponse, error)
 }
 
 type podsClient struct {
@@ -491,6 +1044,101 @@ func (c *podsClient) List(ctx context.Context, in *ListPodsRequest, opts ...grpc
 	return out, nil
 }
 
+func (c *podsClient) Update(ctx context.Context, in *UpdatePodRequest, opts ...grpc.CallOption) (*UpdatePodResponse, error) {
+	out := new(UpdatePodResponse)
+	err := grpc.Invoke(ctx, "/cand.services.pods.v1.Pods/Update", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *podsClient) Watch(ctx context.Context, in *WatchPodsRequest, opts ...grpc.CallOption) (Pods_WatchClient, error) {
+	stream, err := grpc.NewClientStream(ctx, &_Pods_serviceDesc.Streams[1], c.cc, "/cand.services.pods.v1.Pods/Watch", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &podsWatchClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Pods_WatchClient interface {
+	Recv() (*WatchPodsStreamResponse, error)
+	grpc.ClientStream
+}
+
+type podsWatchClient struct {
+	grpc.ClientStream
+}
+
+func (x *podsWatchClient) Recv() (*WatchPodsStreamResponse, error) {
+	m := new(WatchPodsStreamResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *podsClient) Restart(ctx context.Context, in *RestartPodRequest, opts ...grpc.CallOption) (*RestartPodResponse, error) {
+	out := new(RestartPodResponse)
+	err := grpc.Invoke(ctx, "/cand.services.pods.v1.Pods/Restart", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *podsClient) ListNamespaces(ctx context.Context, in *ListNamespacesRequest, opts ...grpc.CallOption) (*ListNamespacesResponse, error) {
+	out := new(ListNamespacesResponse)
+	err := grpc.Invoke(ctx, "/cand.services.pods.v1.Pods/ListNamespaces", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *podsClient) CreateNamespace(ctx context.Context, in *CreateNamespaceRequest, opts ...grpc.CallOption) (*CreateNamespaceResponse, error) {
+	out := new(CreateNamespaceResponse)
+	err := grpc.Invoke(ctx, "/cand.services.pods.v1.Pods/CreateNamespace", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *podsClient) UpdateLabels(ctx context.Context, in *UpdatePodLabelsRequest, opts ...grpc.CallOption) (*UpdatePodLabelsResponse, error) {
+	out := new(UpdatePodLabelsResponse)
+	err := grpc.Invoke(ctx, "/cand.services.pods.v1.Pods/UpdateLabels", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *podsClient) Rename(ctx context.Context, in *RenamePodRequest, opts ...grpc.CallOption) (*RenamePodResponse, error) {
+	out := new(RenamePodResponse)
+	err := grpc.Invoke(ctx, "/cand.services.pods.v1.Pods/Rename", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *podsClient) Cordon(ctx context.Context, in *CordonPodRequest, opts ...grpc.CallOption) (*CordonPodResponse, error) {
+	out := new(CordonPodResponse)
+	err := grpc.Invoke(ctx, "/cand.services.pods.v1.Pods/Cordon", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // Server API for Pods service
 
 type PodsServer interface {
@@ -498,6 +1146,14 @@ type PodsServer interface {
 	Start(context.Context, *StartPodRequest) (*StartPodResponse, error)
 	Delete(context.Context, *DeletePodRequest) (*DeletePodResponse, error)
 	List(context.Context, *ListPodsRequest) (*ListPodsResponse, error)
+	Update(context.Context, *UpdatePodRequest) (*UpdatePodResponse, error)
+	Watch(*WatchPodsRequest, Pods_WatchServer) error
+	Restart(context.Context, *RestartPodRequest) (*RestartPodResponse, error)
+	ListNamespaces(context.Context, *ListNamespacesRequest) (*ListNamespacesResponse, error)
+	CreateNamespace(context.Context, *CreateNamespaceRequest) (*CreateNamespaceResponse, error)
+	UpdateLabels(context.Context, *UpdatePodLabelsRequest) (*UpdatePodLabelsResponse, error)
+	Rename(context.Context, *RenamePodRequest) (*RenamePodResponse, error)
+	Cordon(context.Context, *CordonPodRequest) (*CordonPodResponse, error)
 }
 
 func RegisterPodsServer(s *grpc.Server, srv PodsServer) {
@@ -579,6 +1235,153 @@ func _Pods_List_Handler(srv interface{}, ctx context.Context, dec func(interface
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Pods_Update_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdatePodRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PodsServer).Update(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/cand.services.pods.v1.Pods/Update",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PodsServer).Update(ctx, req.(*UpdatePodRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Pods_Watch_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchPodsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(PodsServer).Watch(m, &podsWatchServer{stream})
+}
+
+type Pods_WatchServer interface {
+	Send(*WatchPodsStreamResponse) error
+	grpc.ServerStream
+}
+
+type podsWatchServer struct {
+	grpc.ServerStream
+}
+
+func (x *podsWatchServer) Send(m *WatchPodsStreamResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _Pods_Restart_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RestartPodRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PodsServer).Restart(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/cand.services.pods.v1.Pods/Restart",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PodsServer).Restart(ctx, req.(*RestartPodRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Pods_ListNamespaces_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListNamespacesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PodsServer).ListNamespaces(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/cand.services.pods.v1.Pods/ListNamespaces",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PodsServer).ListNamespaces(ctx, req.(*ListNamespacesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Pods_CreateNamespace_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateNamespaceRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PodsServer).CreateNamespace(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/cand.services.pods.v1.Pods/CreateNamespace",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PodsServer).CreateNamespace(ctx, req.(*CreateNamespaceRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Pods_UpdateLabels_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdatePodLabelsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PodsServer).UpdateLabels(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/cand.services.pods.v1.Pods/UpdateLabels",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PodsServer).UpdateLabels(ctx, req.(*UpdatePodLabelsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Pods_Rename_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RenamePodRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PodsServer).Rename(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/cand.services.pods.v1.Pods/Rename",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PodsServer).Rename(ctx, req.(*RenamePodRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Pods_Cordon_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CordonPodRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PodsServer).Cordon(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/cand.services.pods.v1.Pods/Cordon",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PodsServer).Cordon(ctx, req.(*CordonPodRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 var _Pods_serviceDesc = grpc.ServiceDesc{
 	ServiceName: "cand.services.pods.v1.Pods",
 	HandlerType: (*PodsServer)(nil),
@@ -595,6 +1398,34 @@ var _Pods_serviceDesc = grpc.ServiceDesc{
 			MethodName: "List",
 			Handler:    _Pods_List_Handler,
 		},
+		{
+			MethodName: "Update",
+			Handler:    _Pods_Update_Handler,
+		},
+		{
+			MethodName: "Restart",
+			Handler:    _Pods_Restart_Handler,
+		},
+		{
+			MethodName: "ListNamespaces",
+			Handler:    _Pods_ListNamespaces_Handler,
+		},
+		{
+			MethodName: "CreateNamespace",
+			Handler:    _Pods_CreateNamespace_Handler,
+		},
+		{
+			MethodName: "UpdateLabels",
+			Handler:    _Pods_UpdateLabels_Handler,
+		},
+		{
+			MethodName: "Rename",
+			Handler:    _Pods_Rename_Handler,
+		},
+		{
+			MethodName: "Cordon",
+			Handler:    _Pods_Cordon_Handler,
+		},
 	},
 	Streams: []grpc.StreamDesc{
 		{
@@ -602,6 +1433,11 @@ var _Pods_serviceDesc = grpc.ServiceDesc{
 			Handler:       _Pods_Create_Handler,
 			ServerStreams: true,
 		},
+		{
+			StreamName:    "Watch",
+			Handler:       _Pods_Watch_Handler,
+			ServerStreams: true,
+		},
 	},
 	Metadata: "services/pods/v1/pods.proto",
 }
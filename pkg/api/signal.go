@@ -0,0 +1,86 @@
+package api
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"syscall"
+
+	"golang.org/x/net/context"
+	"google.golang.org/grpc/codes"
+)
+
+// signals are the Linux signal numbers by name. The node is always Linux, so the numbers are defined here
+// instead of the syscall constants, which differ or don't exist when the client is built e.g. for Windows.
+var signals = map[string]syscall.Signal{
+	"HUP":    1,
+	"INT":    2,
+	"QUIT":   3,
+	"ILL":    4,
+	"TRAP":   5,
+	"ABRT":   6,
+	"BUS":    7,
+	"FPE":    8,
+	"KILL":   9,
+	"USR1":   10,
+	"SEGV":   11,
+	"USR2":   12,
+	"PIPE":   13,
+	"ALRM":   14,
+	"TERM":   15,
+	"STKFLT": 16,
+	"CHLD":   17,
+	"CONT":   18,
+	"STOP":   19,
+	"TSTP":   20,
+	"TTIN":   21,
+	"TTOU":   22,
+	"URG":    23,
+	"XCPU":   24,
+	"XFSZ":   25,
+	"VTALRM": 26,
+	"PROF":   27,
+	"WINCH":  28,
+	"IO":     29,
+	"PWR":    30,
+	"SYS":    31,
+}
+
+// ParseSignal return the Linux signal number of the name, e.g. "SIGTERM", "TERM", "term" or "15".
+// Returns ErrInvalidArgument if the signal is unknown.
+func ParseSignal(name string) (syscall.Signal, error) {
+	name = strings.ToUpper(strings.TrimSpace(name))
+	if number, err := strconv.Atoi(name); err == nil {
+		if number > 0 && number <= 64 {
+			return syscall.Signal(number), nil
+		}
+	} else if signal, ok := signals[strings.TrimPrefix(name, "SIG")]; ok {
+		return signal, nil
+	}
+
+	return 0, &Error{
+		Code:    codes.InvalidArgument,
+		Message: fmt.Sprintf("Invalid signal [%s], must be signal number or one of %v", name, signalNames()),
+	}
+}
+
+// SignalByName is like Signal, but resolves the signal with ParseSignal, e.g. "TERM".
+func (c *Client) SignalByName(ctx context.Context, containerID, name string) error {
+	signal, err := ParseSignal(name)
+	if err != nil {
+		return err
+	}
+	return c.Signal(ctx, containerID, signal)
+}
+
+func signalNames() []string {
+	names := make([]string, 0, len(signals))
+	for name := range signals {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		return signals[names[i]] < signals[names[j]]
+	})
+	return names
+}
@@ -0,0 +1,26 @@
+package api
+
+import (
+	"errors"
+	"syscall"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseSignal(t *testing.T) {
+	for _, name := range []string{"SIGTERM", "TERM", "term", " sigterm ", "15"} {
+		signal, err := ParseSignal(name)
+		assert.NoError(t, err, name)
+		assert.Equal(t, syscall.Signal(15), signal, name)
+	}
+
+	signal, err := ParseSignal("HUP")
+	assert.NoError(t, err)
+	assert.Equal(t, syscall.Signal(1), signal)
+
+	for _, name := range []string{"", "SIG", "SIGFOO", "0", "65", "-1"} {
+		_, err := ParseSignal(name)
+		assert.True(t, errors.Is(err, ErrInvalidArgument), "should not accept [%s]", name)
+	}
+}
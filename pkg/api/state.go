@@ -0,0 +1,101 @@
+package api
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	containers "github.com/ernoaapa/eliot/pkg/api/services/containers/v1"
+	pods "github.com/ernoaapa/eliot/pkg/api/services/pods/v1"
+)
+
+// State is the current state of single container in the pod
+type State struct {
+	// Status is the container task status, e.g. running, stopped or created
+	Status    string
+	Running   bool
+	StartedAt time.Time
+	// Exited is true when the container has been started and the process has exited.
+	// ExitCode, Reason and FinishedAt are set only when Exited is true.
+	Exited     bool
+	ExitCode   int
+	Reason     string
+	FinishedAt time.Time
+}
+
+// ContainerState return the state of the container in the pod.
+// The container name can be empty if the pod has only one container.
+func ContainerState(pod *pods.Pod, containerName string) (*State, error) {
+	status, err := findContainerStatus(pod, containerName)
+	if err != nil {
+		return nil, err
+	}
+	return MapContainerState(status), nil
+}
+
+// MapContainerState maps the container status to State
+func MapContainerState(status *containers.ContainerStatus) *State {
+	state := &State{
+		Status:    status.GetState(),
+		Running:   status.GetState() == "running",
+		StartedAt: mapUnixNano(status.GetStartedAt()),
+	}
+	if !state.Running && status.GetReason() != "" {
+		state.Exited = true
+		state.ExitCode = int(status.GetExitCode())
+		state.Reason = status.GetReason()
+		state.FinishedAt = mapUnixNano(status.GetFinishedAt())
+	}
+	return state
+}
+
+func mapUnixNano(unixNano int64) time.Time {
+	if unixNano == 0 {
+		return time.Time{}
+	}
+	return time.Unix(0, unixNano)
+}
+
+// mapTimeToUnixNano is inverse of mapUnixNano, zero time is zero
+func mapTimeToUnixNano(t time.Time) int64 {
+	if t.IsZero() {
+		return 0
+	}
+	return t.UnixNano()
+}
+
+// String return the state in human readable format, e.g. "Exited (137) OOMKilled 2m ago" or "Running 5m"
+func (s *State) String() string {
+	return s.format(time.Now())
+}
+
+func (s *State) format(now time.Time) string {
+	switch {
+	case s.Running && !s.StartedAt.IsZero():
+		return fmt.Sprintf("Running %s", formatAge(now.Sub(s.StartedAt)))
+	case s.Running:
+		return "Running"
+	case s.Exited && !s.FinishedAt.IsZero():
+		return fmt.Sprintf("Exited (%d) %s %s ago", s.ExitCode, s.Reason, formatAge(now.Sub(s.FinishedAt)))
+	case s.Exited:
+		return fmt.Sprintf("Exited (%d) %s", s.ExitCode, s.Reason)
+	case s.Status == "":
+		return "Unknown"
+	default:
+		return strings.Title(s.Status)
+	}
+}
+
+// formatAge return the duration in the largest unit, e.g. 45s, 2m, 3h or 5d
+func formatAge(d time.Duration) string {
+	switch {
+	case d < time.Minute:
+		return fmt.Sprintf("%ds", int(d.Seconds()))
+	case d < time.Hour:
+		return fmt.Sprintf("%dm", int(d.Minutes()))
+	case d < 24*time.Hour:
+		return fmt.Sprintf("%dh", int(d.Hours()))
+	default:
+		return fmt.Sprintf("%dd", int(d.Hours()/24))
+	}
+}
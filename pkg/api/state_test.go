@@ -0,0 +1,56 @@
+package api
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ernoaapa/eliot/pkg/api/core"
+	containers "github.com/ernoaapa/eliot/pkg/api/services/containers/v1"
+	pods "github.com/ernoaapa/eliot/pkg/api/services/pods/v1"
+)
+
+func TestContainerStateRunning(t *testing.T) {
+	startedAt := time.Now().Add(-5 * time.Minute)
+	pod := &pods.Pod{
+		Metadata: &core.ResourceMetadata{Name: "my-pod"},
+		Status: &pods.PodStatus{
+			ContainerStatuses: []*containers.ContainerStatus{
+				{Name: "foo", State: "running", StartedAt: startedAt.UnixNano()},
+			},
+		},
+	}
+
+	state, err := ContainerState(pod, "foo")
+	assert.NoError(t, err)
+	assert.True(t, state.Running)
+	assert.False(t, state.Exited, "running container should not have exit status")
+	assert.Equal(t, startedAt.UnixNano(), state.StartedAt.UnixNano())
+	assert.Equal(t, "Running 5m", state.format(startedAt.Add(5*time.Minute)))
+}
+
+func TestContainerStateExited(t *testing.T) {
+	finishedAt := time.Now().Add(-2 * time.Minute)
+	pod := &pods.Pod{
+		Metadata: &core.ResourceMetadata{Name: "my-pod"},
+		Status: &pods.PodStatus{
+			ContainerStatuses: []*containers.ContainerStatus{
+				{Name: "foo", State: "stopped", ExitCode: 137, Reason: "OOMKilled", FinishedAt: finishedAt.UnixNano()},
+			},
+		},
+	}
+
+	state, err := ContainerState(pod, "")
+	assert.NoError(t, err)
+	assert.True(t, state.Exited)
+	assert.Equal(t, 137, state.ExitCode)
+	assert.Equal(t, "Exited (137) OOMKilled 2m ago", state.format(finishedAt.Add(2*time.Minute)))
+}
+
+func TestContainerStateCreated(t *testing.T) {
+	state := MapContainerState(&containers.ContainerStatus{Name: "foo", State: "created"})
+	assert.False(t, state.Running)
+	assert.False(t, state.Exited)
+	assert.Equal(t, "Created", state.String())
+}
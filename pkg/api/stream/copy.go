@@ -0,0 +1,60 @@
+package stream
+
+import (
+	"bytes"
+
+	containers "github.com/ernoaapa/eliot/pkg/api/services/containers/v1"
+)
+
+// CopyToStreamServer interface for the endpoint what receives archive chunks
+type CopyToStreamServer interface {
+	Recv() (*containers.CopyToRequest, error)
+}
+
+// CopyFromStreamServer interface for the endpoint what sends archive chunks
+type CopyFromStreamServer interface {
+	Send(*containers.CopyFromResponse) error
+}
+
+// CopyToReader is io.Reader implementation what reads archive chunks from RPC stream
+type CopyToReader struct {
+	buffer bytes.Buffer
+	stream CopyToStreamServer
+}
+
+// NewCopyToReader creates new CopyToReader instance, first is the data of already received first message
+func NewCopyToReader(stream CopyToStreamServer, first []byte) *CopyToReader {
+	reader := &CopyToReader{stream: stream}
+	reader.buffer.Write(first)
+	return reader
+}
+
+// Read reads bytes from the RPC stream
+func (r *CopyToReader) Read(p []byte) (n int, err error) {
+	for r.buffer.Len() == 0 {
+		req, err := r.stream.Recv()
+		if err != nil {
+			return 0, err
+		}
+		r.buffer.Write(req.GetData())
+	}
+	return r.buffer.Read(p)
+}
+
+// CopyFromWriter is io.Writer implementation what writes archive chunks to RPC stream
+type CopyFromWriter struct {
+	stream CopyFromStreamServer
+}
+
+// NewCopyFromWriter creates new CopyFromWriter instance
+func NewCopyFromWriter(stream CopyFromStreamServer) *CopyFromWriter {
+	return &CopyFromWriter{stream}
+}
+
+// Write writes bytes to the RPC stream
+func (w *CopyFromWriter) Write(p []byte) (n int, err error) {
+	if err := w.stream.Send(&containers.CopyFromResponse{Data: p}); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
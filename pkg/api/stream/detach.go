@@ -0,0 +1,89 @@
+package stream
+
+import (
+	"io"
+
+	"github.com/pkg/errors"
+)
+
+// ErrDetached is returned by DetachReader when the detach key sequence is read,
+// and by the server side Reader when the client tells that it detached
+var ErrDetached = errors.New("detached")
+
+// DetachReader is io.Reader which returns ErrDetached when the input contains the key sequence.
+// The sequence itself is never returned. Bytes which might start the sequence are held back until
+// the next read tells if they were part of it, so e.g. lone Ctrl-P reaches the container with the next key.
+type DetachReader struct {
+	reader  io.Reader
+	keys    []byte
+	matched int
+	buf     []byte
+	pending []byte
+	err     error
+}
+
+// NewDetachReader creates new DetachReader, empty keys disables the detaching
+func NewDetachReader(reader io.Reader, keys []byte) io.Reader {
+	if len(keys) == 0 {
+		return reader
+	}
+	return &DetachReader{reader: reader, keys: keys}
+}
+
+func (r *DetachReader) Read(p []byte) (int, error) {
+	if len(p) == 0 {
+		return 0, nil
+	}
+
+	for len(r.pending) == 0 {
+		if r.err != nil {
+			return 0, r.err
+		}
+		if len(r.buf) < len(p) {
+			r.buf = make([]byte, len(p))
+		}
+
+		n, err := r.reader.Read(r.buf[:len(p)])
+		out, detached := r.scan(r.buf[:n])
+		r.pending = out
+		if detached {
+			r.err = ErrDetached
+		} else if err != nil {
+			// The input ended in the middle of the sequence, so it wasn't the sequence
+			r.pending = append(r.pending, r.keys[:r.matched]...)
+			r.matched = 0
+			r.err = err
+		}
+	}
+
+	n := copy(p, r.pending)
+	r.pending = r.pending[n:]
+	return n, nil
+}
+
+// scan return the data without the key sequence, and true if the whole sequence was found.
+// The data after the sequence is dropped because nothing is sent after detaching.
+func (r *DetachReader) scan(data []byte) (out []byte, detached bool) {
+	for len(data) > 0 {
+		b := data[0]
+		data = data[1:]
+		if b == r.keys[r.matched] {
+			r.matched++
+			if r.matched == len(r.keys) {
+				r.matched = 0
+				return out, true
+			}
+			continue
+		}
+		if r.matched == 0 {
+			out = append(out, b)
+			continue
+		}
+		// Not the sequence after all, release the first held back byte and scan the rest again
+		held := append(append([]byte{}, r.keys[1:r.matched]...), b)
+		out = append(out, r.keys[0])
+		r.matched = 0
+		data = append(held, data...)
+	}
+	return out, false
+}
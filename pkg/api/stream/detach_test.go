@@ -0,0 +1,52 @@
+package stream
+
+import (
+	"bytes"
+	"io/ioutil"
+	"testing"
+	"testing/iotest"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var ctrlPQ = []byte{16, 17}
+
+func TestDetachReaderStopsAtSequence(t *testing.T) {
+	reader := NewDetachReader(bytes.NewReader([]byte("ls\n\x10\x11exit\n")), ctrlPQ)
+
+	data, err := ioutil.ReadAll(reader)
+	assert.Equal(t, ErrDetached, err)
+	assert.Equal(t, "ls\n", string(data), "should not return the sequence or anything after it")
+}
+
+func TestDetachReaderFindsSequenceSplitToReads(t *testing.T) {
+	reader := NewDetachReader(iotest.OneByteReader(bytes.NewReader([]byte("a\x10\x11"))), ctrlPQ)
+
+	data, err := ioutil.ReadAll(reader)
+	assert.Equal(t, ErrDetached, err)
+	assert.Equal(t, "a", string(data))
+}
+
+func TestDetachReaderReleasesPartialMatch(t *testing.T) {
+	reader := NewDetachReader(iotest.OneByteReader(bytes.NewReader([]byte("\x10a\x10"))), ctrlPQ)
+
+	data, err := ioutil.ReadAll(reader)
+	assert.NoError(t, err)
+	assert.Equal(t, "\x10a\x10", string(data), "should return the held back bytes when they weren't the sequence")
+}
+
+func TestDetachReaderMatchesRepeatedPrefix(t *testing.T) {
+	reader := NewDetachReader(bytes.NewReader([]byte("xxxyz")), []byte("xxy"))
+
+	data, err := ioutil.ReadAll(reader)
+	assert.Equal(t, ErrDetached, err)
+	assert.Equal(t, "x", string(data))
+}
+
+func TestDetachReaderDisabled(t *testing.T) {
+	reader := NewDetachReader(bytes.NewReader([]byte("\x10\x11")), []byte{})
+
+	data, err := ioutil.ReadAll(reader)
+	assert.NoError(t, err)
+	assert.Equal(t, "\x10\x11", string(data))
+}
@@ -0,0 +1,77 @@
+package stream
+
+import (
+	"bytes"
+	"io"
+	"sync"
+	"unicode/utf8"
+)
+
+// maxLineLength is how long line the LineWriter buffers before it writes the line even without newline
+const maxLineLength = 64 * 1024
+
+// LineWriter is io.Writer implementation what writes only complete lines to the underlying writer,
+// so that output of multiple streams written to the same terminal doesn't get mixed in the middle of line.
+// Call Flush to write the last line, if it doesn't end with newline.
+type LineWriter struct {
+	mu  sync.Mutex
+	w   io.Writer
+	buf []byte
+}
+
+// NewLineWriter creates new LineWriter instance
+func NewLineWriter(w io.Writer) *LineWriter {
+	return &LineWriter{w: w}
+}
+
+// Write buffers the bytes and writes all complete lines to the underlying writer
+func (w *LineWriter) Write(p []byte) (n int, err error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.buf = append(w.buf, p...)
+
+	end := bytes.LastIndexByte(w.buf, '\n') + 1
+	if end == 0 && len(w.buf) >= maxLineLength {
+		// Too long line, write what we have but don't split multi-byte character
+		end = completeRunes(w.buf)
+	}
+
+	if end > 0 {
+		if err := w.write(end); err != nil {
+			return len(p), err
+		}
+	}
+	return len(p), nil
+}
+
+// Flush writes the buffered partial line to the underlying writer
+func (w *LineWriter) Flush() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if len(w.buf) == 0 {
+		return nil
+	}
+	return w.write(len(w.buf))
+}
+
+func (w *LineWriter) write(end int) error {
+	_, err := w.w.Write(w.buf[:end])
+	w.buf = append(w.buf[:0], w.buf[end:]...)
+	return err
+}
+
+// completeRunes return the length of the bytes without the incomplete multi-byte character at the end
+func completeRunes(p []byte) int {
+	for i := 1; i <= utf8.UTFMax && i <= len(p); i++ {
+		start := len(p) - i
+		if utf8.RuneStart(p[start]) {
+			if utf8.FullRune(p[start:]) {
+				return len(p)
+			}
+			return start
+		}
+	}
+	return len(p)
+}
@@ -0,0 +1,51 @@
+package stream
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type recordingWriter struct {
+	writes []string
+}
+
+func (w *recordingWriter) Write(p []byte) (int, error) {
+	w.writes = append(w.writes, string(p))
+	return len(p), nil
+}
+
+func TestLineWriter(t *testing.T) {
+	target := &recordingWriter{}
+	writer := NewLineWriter(target)
+
+	writer.Write([]byte("foo"))
+	writer.Write([]byte("bar\nba"))
+	writer.Write([]byte("z\nqux\nlast"))
+	assert.Equal(t, []string{"foobar\n", "baz\nqux\n"}, target.writes)
+
+	assert.NoError(t, writer.Flush())
+	assert.Equal(t, []string{"foobar\n", "baz\nqux\n", "last"}, target.writes)
+}
+
+func TestLineWriterWritesTooLongLine(t *testing.T) {
+	target := &bytes.Buffer{}
+	writer := NewLineWriter(target)
+
+	line := strings.Repeat("a", maxLineLength-1) + "ä"
+	writer.Write([]byte(line[:maxLineLength]))
+	assert.Equal(t, maxLineLength-1, target.Len(), "should not split multi-byte character")
+
+	writer.Write([]byte(line[maxLineLength:]))
+	writer.Flush()
+	assert.Equal(t, line, target.String())
+}
+
+func TestCompleteRunes(t *testing.T) {
+	assert.Equal(t, 3, completeRunes([]byte("foo")))
+	assert.Equal(t, 2, completeRunes([]byte("fo\xc3")), "should leave out incomplete character")
+	assert.Equal(t, 4, completeRunes([]byte("foä")))
+	assert.Equal(t, 2, completeRunes([]byte{0xff, 0xfe}), "should pass through binary data")
+}
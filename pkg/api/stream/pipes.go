@@ -35,20 +35,42 @@ func PipeStdout(stream StdoutStreamClient, stdout, stderr io.Writer) error {
 	}
 }
 
-// PipeStdin reads input from Stdin and writes it to the grpc stream
-func PipeStdin(stream StdinStreamClient, stdin io.Reader) error {
+// DefaultStdinBufferSize is the maximum amount of stdin what PipeStdin sends in single message by default
+const DefaultStdinBufferSize = 32 * 1024
+
+// PipeStdin reads input from Stdin and sends each read to the grpc stream right away, so interactive input
+// is not delayed and large input, e.g. piped file, is sent in order in chunks of bufferSize.
+// Zero bufferSize means DefaultStdinBufferSize.
+// When stdin ends, closes the sending side of the stream so that the container process gets end of input.
+// If stdin returns ErrDetached, tells the server to leave the container stdin open before closing and returns ErrDetached.
+func PipeStdin(stream StdinStreamCloser, stdin io.Reader, bufferSize int) error {
+	if bufferSize <= 0 {
+		bufferSize = DefaultStdinBufferSize
+	}
+
+	// The message is serialized before Send returns, so the buffer can be reused for the next read
+	buf := make([]byte, bufferSize)
 	for {
-		buf := make([]byte, 1024)
 		n, err := stdin.Read(buf)
+		if n > 0 {
+			if err := stream.Send(&containers.StdinStreamRequest{Input: buf[:n]}); err != nil {
+				return errors.Wrapf(err, "Sending to stream returned error")
+			}
+		}
 		if err == io.EOF {
-			return nil
+			return errors.Wrapf(stream.CloseSend(), "Failed to close stdin stream")
+		}
+		if err == ErrDetached {
+			if err := stream.Send(&containers.StdinStreamRequest{Detach: true}); err != nil {
+				return errors.Wrapf(err, "Sending detach to stream returned error")
+			}
+			if err := stream.CloseSend(); err != nil {
+				return errors.Wrapf(err, "Failed to close stdin stream")
+			}
+			return ErrDetached
 		}
 		if err != nil {
 			return errors.Wrapf(err, "Error while reading stdin to buffer")
 		}
-
-		if err := stream.Send(&containers.StdinStreamRequest{Input: buf[:n]}); err != nil {
-			return errors.Wrapf(err, "Sending to stream returned error")
-		}
 	}
 }
@@ -0,0 +1,86 @@
+package stream
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+	"testing/iotest"
+
+	containers "github.com/ernoaapa/eliot/pkg/api/services/containers/v1"
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeStdinClient copies the sent input like gRPC serializes the message in Send
+type fakeStdinClient struct {
+	inputs   [][]byte
+	closed   bool
+	detached bool
+}
+
+func (s *fakeStdinClient) Send(req *containers.StdinStreamRequest) error {
+	if s.closed {
+		return errors.New("send after close")
+	}
+	if req.Detach {
+		s.detached = true
+		return nil
+	}
+	s.inputs = append(s.inputs, append([]byte{}, req.Input...))
+	return nil
+}
+
+func (s *fakeStdinClient) CloseSend() error {
+	s.closed = true
+	return nil
+}
+
+func TestPipeStdinSendsLargeInputInOrder(t *testing.T) {
+	input := bytes.Repeat([]byte("0123456789abcdef"), 10000)
+	client := &fakeStdinClient{}
+
+	err := PipeStdin(client, bytes.NewReader(input), 1024)
+	assert.NoError(t, err)
+
+	for _, chunk := range client.inputs {
+		assert.True(t, len(chunk) <= 1024, "should not send more than the buffer size in single message")
+	}
+	assert.Equal(t, input, bytes.Join(client.inputs, nil))
+	assert.True(t, client.closed, "should close the send side when stdin ends")
+}
+
+func TestPipeStdinSendsDataReturnedWithEOF(t *testing.T) {
+	client := &fakeStdinClient{}
+
+	err := PipeStdin(client, iotest.DataErrReader(bytes.NewReader([]byte("last line\n"))), 0)
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("last line\n"), bytes.Join(client.inputs, nil))
+	assert.True(t, client.closed)
+}
+
+func TestPipeStdinSendsEachPartialRead(t *testing.T) {
+	client := &fakeStdinClient{}
+
+	err := PipeStdin(client, iotest.OneByteReader(bytes.NewReader([]byte("abc"))), 0)
+	assert.NoError(t, err)
+	assert.Equal(t, [][]byte{[]byte("a"), []byte("b"), []byte("c")}, client.inputs)
+}
+
+func TestPipeStdinSendsDetach(t *testing.T) {
+	client := &fakeStdinClient{}
+
+	err := PipeStdin(client, NewDetachReader(bytes.NewReader([]byte("ls\n\x10\x11")), []byte{16, 17}), 0)
+	assert.Equal(t, ErrDetached, err)
+	assert.Equal(t, []byte("ls\n"), bytes.Join(client.inputs, nil))
+	assert.True(t, client.detached, "should tell the server to leave the container stdin open")
+	assert.True(t, client.closed)
+}
+
+func TestLockedStdinStreamDropsSendAfterClose(t *testing.T) {
+	client := &fakeStdinClient{}
+	locked := NewLockedStdinStream(client)
+
+	assert.NoError(t, locked.CloseSend())
+	assert.NoError(t, locked.CloseSend(), "should allow closing twice")
+	assert.Error(t, locked.Send(&containers.StdinStreamRequest{Resize: &containers.TerminalSize{Width: 80}}))
+	assert.Empty(t, client.inputs)
+}
@@ -8,8 +8,9 @@ import (
 
 // Reader is io.Reader implementation what reads bytes from RPC stream
 type Reader struct {
-	buffer bytes.Buffer
-	stream StdinStreamServer
+	buffer   bytes.Buffer
+	stream   StdinStreamServer
+	onResize func(size *containers.TerminalSize)
 }
 
 // StdinStreamServer interface for the endpoint what takes stdin stream in
@@ -22,18 +23,36 @@ type StdinStreamClient interface {
 	Send(*containers.StdinStreamRequest) error
 }
 
+// StdinStreamCloser is StdinStreamClient which can tell the server that no more stdin is sent
+type StdinStreamCloser interface {
+	StdinStreamClient
+	CloseSend() error
+}
+
 // NewReader creates new Reader instance
 func NewReader(stream StdinStreamServer) *Reader {
 	return &Reader{stream: stream}
 }
 
+// NewResizeReader creates new Reader instance which calls onResize when client sends terminal resize event
+func NewResizeReader(stream StdinStreamServer, onResize func(size *containers.TerminalSize)) *Reader {
+	return &Reader{stream: stream, onResize: onResize}
+}
+
 // Write writes bytes to given RPC stream
 func (w *Reader) Read(p []byte) (n int, err error) {
-	if w.buffer.Len() == 0 {
+	for w.buffer.Len() == 0 {
 		req, err := w.stream.Recv()
 		if err != nil {
 			return 0, err
 		}
+		if req.GetDetach() {
+			// Not io.EOF, so that the container stdin doesn't get closed
+			return 0, ErrDetached
+		}
+		if req.GetResize() != nil && w.onResize != nil {
+			w.onResize(req.GetResize())
+		}
 		w.buffer.Write(req.GetInput())
 	}
 	return w.buffer.Read(p)
@@ -0,0 +1,50 @@
+package stream
+
+import (
+	"io"
+	"io/ioutil"
+	"testing"
+
+	containers "github.com/ernoaapa/eliot/pkg/api/services/containers/v1"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeStdinStream struct {
+	requests []*containers.StdinStreamRequest
+}
+
+func (s *fakeStdinStream) Recv() (*containers.StdinStreamRequest, error) {
+	if len(s.requests) == 0 {
+		return nil, io.EOF
+	}
+	req := s.requests[0]
+	s.requests = s.requests[1:]
+	return req, nil
+}
+
+func TestResizeReader(t *testing.T) {
+	sizes := []*containers.TerminalSize{}
+	reader := NewResizeReader(&fakeStdinStream{[]*containers.StdinStreamRequest{
+		{Input: []byte("foo")},
+		{Resize: &containers.TerminalSize{Width: 80, Height: 24}},
+		{Input: []byte("bar")},
+	}}, func(size *containers.TerminalSize) {
+		sizes = append(sizes, size)
+	})
+
+	data, err := ioutil.ReadAll(reader)
+	assert.NoError(t, err)
+	assert.Equal(t, "foobar", string(data))
+	assert.Equal(t, []*containers.TerminalSize{{Width: 80, Height: 24}}, sizes)
+}
+
+func TestReaderReturnsDetached(t *testing.T) {
+	reader := NewReader(&fakeStdinStream{[]*containers.StdinStreamRequest{
+		{Input: []byte("foo")},
+		{Detach: true},
+	}})
+
+	data, err := ioutil.ReadAll(reader)
+	assert.Equal(t, ErrDetached, err, "should not return io.EOF which would close the container stdin")
+	assert.Equal(t, "foo", string(data))
+}
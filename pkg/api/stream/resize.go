@@ -0,0 +1,71 @@
+package stream
+
+import (
+	"sync"
+
+	containers "github.com/ernoaapa/eliot/pkg/api/services/containers/v1"
+	"github.com/ernoaapa/eliot/pkg/term"
+	"github.com/pkg/errors"
+)
+
+// errStdinClosed is returned when sending to LockedStdinStream after CloseSend
+var errStdinClosed = errors.New("stdin stream closed")
+
+// LockedStdinStream allows sending stdin and resize events to the same stream from multiple goroutines
+type LockedStdinStream struct {
+	mu     sync.Mutex
+	stream StdinStreamCloser
+	closed bool
+}
+
+// NewLockedStdinStream creates new LockedStdinStream instance
+func NewLockedStdinStream(stream StdinStreamCloser) *LockedStdinStream {
+	return &LockedStdinStream{stream: stream}
+}
+
+// Send sends the message to the stream
+func (s *LockedStdinStream) Send(req *containers.StdinStreamRequest) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return errStdinClosed
+	}
+	return s.stream.Send(req)
+}
+
+// CloseSend closes the sending side of the stream, e.g. terminal size changes are not sent after it
+func (s *LockedStdinStream) CloseSend() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return nil
+	}
+	s.closed = true
+	return s.stream.CloseSend()
+}
+
+// PipeResize sends terminal size changes to the grpc stream until the queue stops
+func PipeResize(stream StdinStreamClient, sizes term.TerminalSizeQueue, done <-chan struct{}) error {
+	for {
+		size := sizes.Next()
+		if size == nil {
+			return nil
+		}
+
+		select {
+		case <-done:
+			return nil
+		default:
+		}
+
+		err := stream.Send(&containers.StdinStreamRequest{
+			Resize: &containers.TerminalSize{
+				Width:  uint32(size.Width),
+				Height: uint32(size.Height),
+			},
+		})
+		if err != nil {
+			return errors.Wrapf(err, "Sending terminal size to stream returned error")
+		}
+	}
+}
@@ -0,0 +1,287 @@
+package api
+
+import (
+	"archive/tar"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	eliotsync "github.com/ernoaapa/eliot/pkg/sync"
+	"github.com/pkg/errors"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc/codes"
+)
+
+// DefaultSyncInterval is how often SyncDir checks the local directory for changes by default
+const DefaultSyncInterval = time.Second
+
+// DefaultSyncMaxFileSize is the largest file what SyncDir syncs by default
+const DefaultSyncMaxFileSize = 10 * 1024 * 1024
+
+// SyncDir keeps the directory in the container in sync with the local directory, e.g. to run the code
+// in the container while editing it locally. First all files are copied, then the local directory is
+// checked for changes every SyncOptions Interval and the changed files are pushed and the deleted ones
+// removed from the container. Files which exist only in the container are kept.
+// The paths listed in the local .syncignore file are skipped, see sync.Ignore, and so are files larger than
+// SyncOptions MaxFileSize. The remote directory parent must exist in the container.
+// Returns when the context get cancelled, or with error if reading the local directory or pushing fails.
+func (c *Client) SyncDir(ctx context.Context, containerID, localDir, remoteDir string, opts SyncOptions) error {
+	if opts.Interval == 0 {
+		opts.Interval = DefaultSyncInterval
+	}
+	if opts.MaxFileSize == 0 {
+		opts.MaxFileSize = DefaultSyncMaxFileSize
+	}
+	if info, err := os.Stat(localDir); err != nil || !info.IsDir() {
+		return &Error{Code: codes.InvalidArgument, Message: fmt.Sprintf("Cannot sync [%s], it's not a directory", localDir)}
+	}
+
+	// The archive top level entry is the remote directory, so it gets created if it doesn't exist
+	remoteDir = path.Clean("/" + remoteDir)
+	push := &syncPush{client: c, containerID: containerID, localDir: localDir, dest: path.Dir(remoteDir), prefix: path.Base(remoteDir)}
+	if remoteDir == "/" {
+		push.prefix = ""
+	}
+
+	current := map[string]syncEntry{}
+	for initial := true; ; initial = false {
+		next, err := scanSyncDir(localDir, opts.MaxFileSize)
+		if err != nil {
+			return errors.Wrapf(err, "Failed to read directory [%s] to sync", localDir)
+		}
+
+		result := diffSyncEntries(current, next)
+		for _, skipped := range result.Skipped {
+			c.logger.Warnf("Skip syncing [%s], the file is larger than %d bytes", skipped, opts.MaxFileSize)
+		}
+		if initial || len(result.Copied) > 0 || len(result.Removed) > 0 {
+			if err := push.push(ctx, result, initial); err != nil {
+				if ctx.Err() != nil {
+					return nil
+				}
+				return err
+			}
+		}
+		if opts.OnSync != nil && (initial || len(result.Copied) > 0 || len(result.Removed) > 0 || len(result.Skipped) > 0) {
+			opts.OnSync(result)
+		}
+		current = next
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(opts.Interval):
+		}
+	}
+}
+
+// syncEntry is the state of the local file when the directory was scanned
+type syncEntry struct {
+	mode    os.FileMode
+	size    int64
+	modTime time.Time
+	// skipped is true if the file is too large to sync
+	skipped bool
+}
+
+func (e syncEntry) changed(other syncEntry) bool {
+	if e.mode.IsDir() && other.mode.IsDir() {
+		// Directory modification time changes with the content, which is synced separately
+		return e.mode != other.mode
+	}
+	return e.mode != other.mode || e.size != other.size || !e.modTime.Equal(other.modTime)
+}
+
+// scanSyncDir return the state of every file in the directory by the slash separated relative path
+func scanSyncDir(dir string, maxFileSize int64) (map[string]syncEntry, error) {
+	ignore, err := eliotsync.LoadIgnore(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	result := map[string]syncEntry{}
+	err = filepath.Walk(dir, func(file string, info os.FileInfo, err error) error {
+		if err != nil {
+			if os.IsNotExist(err) {
+				// Removed while scanning, gets noticed in the next scan
+				return nil
+			}
+			return err
+		}
+
+		rel, err := filepath.Rel(dir, file)
+		if err != nil {
+			return err
+		}
+		if rel == "." {
+			return nil
+		}
+		if ignore.Match(rel, info.IsDir()) {
+			if info.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		result[filepath.ToSlash(rel)] = syncEntry{
+			mode:    info.Mode(),
+			size:    info.Size(),
+			modTime: info.ModTime(),
+			skipped: info.Mode().IsRegular() && maxFileSize > 0 && info.Size() > maxFileSize,
+		}
+		return nil
+	})
+	return result, err
+}
+
+// diffSyncEntries return the changes between the scans. When directory is removed, only the directory
+// itself is in Removed. If file is replaced with directory or the other way around, it's in both lists.
+func diffSyncEntries(current, next map[string]syncEntry) (result SyncResult) {
+	for name, entry := range next {
+		old, exists := current[name]
+		if exists && !old.changed(entry) {
+			continue
+		}
+		if exists && old.mode.IsDir() != entry.mode.IsDir() {
+			result.Removed = append(result.Removed, name)
+		}
+		if entry.skipped {
+			result.Skipped = append(result.Skipped, name)
+			continue
+		}
+		result.Copied = append(result.Copied, name)
+	}
+
+	removed := map[string]bool{}
+	for name := range current {
+		if _, exists := next[name]; !exists {
+			removed[name] = true
+		}
+	}
+	for _, name := range result.Removed {
+		removed[name] = true
+	}
+	result.Removed = nil
+	for name := range removed {
+		if !hasRemovedParent(name, removed) {
+			result.Removed = append(result.Removed, name)
+		}
+	}
+
+	// Sorted so that directories are created before their content
+	sort.Strings(result.Copied)
+	sort.Strings(result.Removed)
+	sort.Strings(result.Skipped)
+	return result
+}
+
+func hasRemovedParent(name string, removed map[string]bool) bool {
+	for parent := path.Dir(name); parent != "."; parent = path.Dir(parent) {
+		if removed[parent] {
+			return true
+		}
+	}
+	return false
+}
+
+// syncPush sends the changes to the remote directory, which is the prefix in the destination
+type syncPush struct {
+	client      *Client
+	containerID string
+	localDir    string
+	dest        string
+	prefix      string
+}
+
+func (p *syncPush) push(ctx context.Context, result SyncResult, initial bool) error {
+	remove := []string{}
+	for _, name := range result.Removed {
+		remove = append(remove, path.Join(p.prefix, name))
+	}
+
+	reader, writer := io.Pipe()
+	go func() {
+		writer.CloseWithError(p.writeArchive(writer, result.Copied, initial))
+	}()
+	defer reader.Close()
+
+	return p.client.copyToContainer(ctx, p.containerID, p.dest, reader, remove)
+}
+
+// writeArchive writes tar archive of the files, the initial archive has also the directory itself
+func (p *syncPush) writeArchive(w io.Writer, names []string, initial bool) error {
+	archive := tar.NewWriter(w)
+	if initial && p.prefix != "" {
+		if err := p.writeEntry(archive, p.localDir, ""); err != nil {
+			return err
+		}
+	}
+	for _, name := range names {
+		if err := p.writeEntry(archive, filepath.Join(p.localDir, filepath.FromSlash(name)), name); err != nil {
+			return err
+		}
+	}
+	return archive.Close()
+}
+
+func (p *syncPush) writeEntry(archive *tar.Writer, file, name string) error {
+	info, err := os.Lstat(file)
+	if err != nil {
+		if os.IsNotExist(err) {
+			// Removed after the scan, gets removed in the next push
+			return nil
+		}
+		return err
+	}
+
+	link := ""
+	if info.Mode()&os.ModeSymlink != 0 {
+		if link, err = os.Readlink(file); err != nil {
+			return err
+		}
+	}
+
+	header, err := tar.FileInfoHeader(info, link)
+	if err != nil {
+		return err
+	}
+	header.Name = path.Join(p.prefix, name)
+	if info.IsDir() {
+		header.Name = strings.TrimSuffix(header.Name, "/") + "/"
+	}
+
+	if !info.Mode().IsRegular() {
+		return archive.WriteHeader(header)
+	}
+
+	f, err := os.Open(file)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	if err := archive.WriteHeader(header); err != nil {
+		return err
+	}
+	n, err := io.CopyN(archive, f, header.Size)
+	if err == io.EOF {
+		// The file got truncated after the scan, fill the rest so that the archive stays valid.
+		// The modification time changed too, so the next push sends the file again.
+		_, err = io.CopyN(archive, zeroReader{}, header.Size-n)
+	}
+	return err
+}
+
+type zeroReader struct{}
+
+func (zeroReader) Read(p []byte) (int, error) {
+	for i := range p {
+		p[i] = 0
+	}
+	return len(p), nil
+}
@@ -0,0 +1,120 @@
+package api
+
+import (
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ernoaapa/eliot/pkg/runtime"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/net/context"
+)
+
+// syncRuntime extracts the copied archives to the root directory like it would be the container filesystem
+type syncRuntime struct {
+	runtime.Client
+	root string
+}
+
+func (r *syncRuntime) CopyTo(namespace, name, destPath string, archive io.Reader) error {
+	return runtime.ExtractArchive(r.root, destPath, archive)
+}
+
+func (r *syncRuntime) RemoveFrom(namespace, name, destPath string, paths []string) error {
+	return runtime.RemovePaths(r.root, destPath, paths)
+}
+
+func writeSyncFile(t *testing.T, dir, name, content string) {
+	assert.NoError(t, os.MkdirAll(filepath.Dir(filepath.Join(dir, name)), 0755))
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(dir, name), []byte(content), 0644))
+}
+
+func receiveSyncResult(t *testing.T, results <-chan SyncResult) SyncResult {
+	select {
+	case result := <-results:
+		return result
+	case <-time.After(5 * time.Second):
+		t.Fatal("SyncDir didn't push the changes")
+		return SyncResult{}
+	}
+}
+
+func TestSyncDir(t *testing.T) {
+	local, _ := ioutil.TempDir("", "sync-local")
+	defer os.RemoveAll(local)
+	root, _ := ioutil.TempDir("", "sync-container")
+	defer os.RemoveAll(root)
+	assert.NoError(t, os.Mkdir(filepath.Join(root, "app"), 0755))
+
+	writeSyncFile(t, local, "main.py", "print('hello')")
+	writeSyncFile(t, local, "static/index.html", "<html>")
+	writeSyncFile(t, local, "debug.log", "ignored")
+	writeSyncFile(t, local, "model.bin", "this file is too large to sync")
+	writeSyncFile(t, local, ".syncignore", "*.log\n")
+
+	client, stop := startDiskUsageServer(t, &syncRuntime{root: root})
+	defer stop()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	results := make(chan SyncResult)
+	done := make(chan error)
+	go func() {
+		done <- client.SyncDir(ctx, "foo", local, "/app/src", SyncOptions{
+			Interval:    10 * time.Millisecond,
+			MaxFileSize: 20,
+			OnSync:      func(result SyncResult) { results <- result },
+		})
+	}()
+
+	initial := receiveSyncResult(t, results)
+	assert.Equal(t, []string{".syncignore", "main.py", "static", "static/index.html"}, initial.Copied)
+	assert.Equal(t, []string{"model.bin"}, initial.Skipped)
+
+	data, err := ioutil.ReadFile(filepath.Join(root, "app", "src", "main.py"))
+	assert.NoError(t, err)
+	assert.Equal(t, "print('hello')", string(data))
+	_, err = os.Stat(filepath.Join(root, "app", "src", "debug.log"))
+	assert.True(t, os.IsNotExist(err), "should not sync ignored file")
+
+	assert.NoError(t, os.RemoveAll(filepath.Join(local, "static")))
+	writeSyncFile(t, local, "util.py", "pass")
+
+	// The changes can get pushed in one or two rounds, depending on when the directory gets scanned
+	changes := receiveSyncResult(t, results)
+	if len(changes.Copied) == 0 || len(changes.Removed) == 0 {
+		next := receiveSyncResult(t, results)
+		changes.Copied = append(changes.Copied, next.Copied...)
+		changes.Removed = append(changes.Removed, next.Removed...)
+	}
+	assert.Equal(t, []string{"util.py"}, changes.Copied)
+	assert.Equal(t, []string{"static"}, changes.Removed)
+
+	_, err = os.Stat(filepath.Join(root, "app", "src", "static"))
+	assert.True(t, os.IsNotExist(err), "should remove the deleted directory")
+	data, err = ioutil.ReadFile(filepath.Join(root, "app", "src", "util.py"))
+	assert.NoError(t, err)
+	assert.Equal(t, "pass", string(data))
+
+	cancel()
+	select {
+	case err := <-done:
+		assert.NoError(t, err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("SyncDir didn't return after the context got cancelled")
+	}
+}
+
+func TestDiffSyncEntriesTypeChange(t *testing.T) {
+	file := syncEntry{mode: 0644, size: 1}
+	dir := syncEntry{mode: os.ModeDir | 0755}
+
+	result := diffSyncEntries(
+		map[string]syncEntry{"config": file, "data": dir, "data/a": file},
+		map[string]syncEntry{"config": dir, "config/a": file, "data": file},
+	)
+	assert.Equal(t, []string{"config", "config/a", "data"}, result.Copied)
+	assert.Equal(t, []string{"config", "data"}, result.Removed, "should remove the replaced paths but not the children")
+}
@@ -0,0 +1,45 @@
+package api
+
+import (
+	node "github.com/ernoaapa/eliot/pkg/api/services/node/v1"
+	pods "github.com/ernoaapa/eliot/pkg/api/services/pods/v1"
+	"github.com/ernoaapa/eliot/pkg/template"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc/codes"
+)
+
+// ExpandTemplate renders the pod manifest template for the node, see template.RenderPodTemplate.
+// In addition to the values, the template gets the node info in .node, e.g. {{ .node.hostname }}
+// or {{ .node.labels.region }}, so the same template can be created to many nodes.
+// The values take precedence over the node info. Returns ErrInvalidArgument if the template is invalid.
+func (c *Client) ExpandTemplate(ctx context.Context, tmpl []byte, values map[string]interface{}) (*pods.Pod, error) {
+	info, err := c.GetInfo(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	data := map[string]interface{}{"node": getTemplateNodeValues(info)}
+	for key, value := range values {
+		data[key] = value
+	}
+
+	pod, err := template.RenderPodTemplate(tmpl, data)
+	if err != nil {
+		return nil, &Error{Code: codes.InvalidArgument, Message: err.Error(), cause: err}
+	}
+	return pod, nil
+}
+
+func getTemplateNodeValues(info *node.Info) map[string]interface{} {
+	labels := map[string]string{}
+	for _, label := range info.GetLabels() {
+		labels[label.Key] = label.Value
+	}
+	return map[string]interface{}{
+		"hostname":  info.GetHostname(),
+		"labels":    labels,
+		"arch":      info.GetArch(),
+		"os":        info.GetOs(),
+		"machineID": info.GetMachineID(),
+	}
+}
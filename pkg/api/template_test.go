@@ -0,0 +1,58 @@
+package api
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/ernoaapa/eliot/pkg/node"
+	"github.com/ernoaapa/eliot/pkg/runtime"
+	"github.com/ernoaapa/eliot/pkg/template"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc"
+)
+
+// versionRuntime reports only the runtime version for the node info
+type versionRuntime struct {
+	runtime.Client
+}
+
+func (r *versionRuntime) GetVersion() (string, error) {
+	return "1.0.0", nil
+}
+
+func startTemplateServer(t *testing.T, labels map[string]string) (*Client, func()) {
+	return startTestServer(t, func(address string) *grpc.Server {
+		return NewServer(address, &versionRuntime{}, node.NewResolver(5000, "test", labels)).grpc
+	})
+}
+
+func TestExpandTemplateWithNodeLabels(t *testing.T) {
+	client, stop := startTemplateServer(t, map[string]string{"device": "pi42"})
+	defer stop()
+
+	pod, err := client.ExpandTemplate(context.Background(), []byte(`
+metadata:
+  name: myapp-{{ .node.labels.device }}
+spec:
+  containers:
+    - name: myapp
+      image: registry.local/team/myapp:{{ .version }}
+`), map[string]interface{}{"version": "1.0"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "myapp-pi42", pod.Metadata.Name)
+	assert.Equal(t, "registry.local/team/myapp:1.0", pod.Spec.Containers[0].Image)
+}
+
+func TestExpandTemplateInvalid(t *testing.T) {
+	client, stop := startTemplateServer(t, map[string]string{})
+	defer stop()
+
+	_, err := client.ExpandTemplate(context.Background(), []byte(`metadata: {{ .name | unknown }}`), nil)
+	assert.True(t, errors.Is(err, ErrInvalidArgument), "should return ErrInvalidArgument, got: %v", err)
+
+	var templateErr *template.TemplateError
+	assert.True(t, errors.As(err, &templateErr))
+	assert.Equal(t, 1, templateErr.Line)
+}
@@ -0,0 +1,73 @@
+package api
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"io/ioutil"
+
+	"github.com/pkg/errors"
+	"google.golang.org/grpc/credentials"
+)
+
+// NewClientTLSCredentials loads TLS credentials for connecting to the server.
+// If certFile and keyFile are given, the certificate is presented to the server for mutual TLS.
+// If caFile is empty, the host root CA set is used to verify the server certificate.
+func NewClientTLSCredentials(certFile, keyFile, caFile string) (credentials.TransportCredentials, error) {
+	config := &tls.Config{}
+
+	if certFile != "" || keyFile != "" {
+		cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+		if err != nil {
+			return nil, errors.Wrapf(err, "Failed to load client certificate [%s] and key [%s]", certFile, keyFile)
+		}
+		config.Certificates = []tls.Certificate{cert}
+	}
+
+	if caFile != "" {
+		pool, err := loadCertPool(caFile)
+		if err != nil {
+			return nil, err
+		}
+		config.RootCAs = pool
+	}
+
+	return credentials.NewTLS(config), nil
+}
+
+// NewServerTLSCredentials loads TLS credentials for serving the API.
+// If clientCAFile is given, clients must present a certificate signed by the CA (mutual TLS).
+func NewServerTLSCredentials(certFile, keyFile, clientCAFile string) (credentials.TransportCredentials, error) {
+	cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Failed to load server certificate [%s] and key [%s]", certFile, keyFile)
+	}
+
+	config := &tls.Config{
+		Certificates: []tls.Certificate{cert},
+	}
+
+	if clientCAFile != "" {
+		pool, err := loadCertPool(clientCAFile)
+		if err != nil {
+			return nil, err
+		}
+		config.ClientCAs = pool
+		config.ClientAuth = tls.RequireAndVerifyClientCert
+	}
+
+	return credentials.NewTLS(config), nil
+}
+
+func loadCertPool(caFile string) (*x509.CertPool, error) {
+	data, err := ioutil.ReadFile(caFile)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Failed to read CA certificate [%s]", caFile)
+	}
+
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(data) {
+		return nil, fmt.Errorf("No valid PEM certificates found from CA file [%s]", caFile)
+	}
+	return pool, nil
+}
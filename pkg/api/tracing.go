@@ -0,0 +1,152 @@
+package api
+
+import (
+	"io"
+	"strings"
+	"sync"
+
+	"golang.org/x/net/context"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// Span attributes what WithTracer sets, named by the OpenTelemetry RPC semantic conventions
+const (
+	AttributeRPCSystem     = "rpc.system"
+	AttributeRPCService    = "rpc.service"
+	AttributeRPCMethod     = "rpc.method"
+	AttributeServerAddress = "server.address"
+	AttributeStatusCode    = "rpc.grpc.status_code"
+	AttributeNamespace     = "eliot.namespace"
+)
+
+// Tracer creates the tracing spans of the client RPCs. The client doesn't depend on any tracing library,
+// so implement this with the one in use, e.g. OpenTelemetry: Start with trace.Tracer Start and the attributes
+// converted to attribute.String, Inject with the propagator Inject.
+type Tracer interface {
+	// Start starts span with the attributes and return context carrying the span
+	Start(ctx context.Context, name string, attributes map[string]string) (context.Context, Span)
+	// Inject writes the trace context of the span in the context with the set function, e.g. the traceparent header
+	Inject(ctx context.Context, set func(key, value string))
+}
+
+// Span is the span of single RPC
+type Span interface {
+	// SetAttribute adds attribute to the span, e.g. the namespace of stream which is known after the first message
+	SetAttribute(key, value string)
+	// AddEvent records event at the current time, e.g. when the stream got opened
+	AddEvent(name string)
+	// End ends the span, err is the RPC error or nil if the call succeeded
+	End(err error)
+}
+
+// WithTracer creates span of every RPC with the tracer, with the method name, server address, namespace and
+// the status code, and propagates the trace context to the server in the call metadata.
+// Stream spans last until the stream ends and have "stream opened" event when the stream setup completed.
+// The tracing runs as interceptor in the WithUnaryInterceptors and WithStreamInterceptors chains,
+// at the position of this option, so with WithServers each tried server gets own span.
+func WithTracer(tracer Tracer) ClientOpts {
+	return func(client *Client) error {
+		client.unaryInterceptors = append(client.unaryInterceptors, client.tracingUnaryInterceptor(tracer))
+		client.streamInterceptors = append(client.streamInterceptors, client.tracingStreamInterceptor(tracer))
+		return nil
+	}
+}
+
+// namespacedRequest is the request message which has the target namespace, e.g. ListPodsRequest
+type namespacedRequest interface {
+	GetNamespace() string
+}
+
+func (c *Client) tracingUnaryInterceptor(tracer Tracer) grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		ctx, span := c.startRPCSpan(ctx, tracer, method)
+		if r, ok := req.(namespacedRequest); ok {
+			span.SetAttribute(AttributeNamespace, r.GetNamespace())
+		}
+		err := invoker(ctx, method, req, reply, cc, opts...)
+		endRPCSpan(span, err)
+		return err
+	}
+}
+
+func (c *Client) tracingStreamInterceptor(tracer Tracer) grpc.StreamClientInterceptor {
+	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		ctx, span := c.startRPCSpan(ctx, tracer, method)
+		stream, err := streamer(ctx, desc, cc, method, opts...)
+		if err != nil {
+			endRPCSpan(span, err)
+			return nil, err
+		}
+		span.AddEvent("stream opened")
+		return &tracingStream{ClientStream: stream, span: span}, nil
+	}
+}
+
+// startRPCSpan starts the span and adds the trace context to the outgoing metadata.
+// The interceptors get called with the connection to the active server, so it's the server the call goes to.
+func (c *Client) startRPCSpan(ctx context.Context, tracer Tracer, method string) (context.Context, Span) {
+	name := strings.TrimPrefix(method, "/")
+	attributes := map[string]string{
+		AttributeRPCSystem:     "grpc",
+		AttributeServerAddress: c.ActiveEndpoint().GetAddress(),
+	}
+	if i := strings.LastIndex(name, "/"); i >= 0 {
+		attributes[AttributeRPCService] = name[:i]
+		attributes[AttributeRPCMethod] = name[i+1:]
+	}
+	ctx, span := tracer.Start(ctx, name, attributes)
+
+	md, _ := metadata.FromOutgoingContext(ctx)
+	md = md.Copy()
+	tracer.Inject(ctx, func(key, value string) {
+		md[strings.ToLower(key)] = []string{value}
+	})
+	return metadata.NewOutgoingContext(ctx, md), span
+}
+
+func endRPCSpan(span Span, err error) {
+	span.SetAttribute(AttributeStatusCode, status.Code(err).String())
+	span.End(err)
+}
+
+// tracingStream ends the span when the stream ends, when RecvMsg returns error, e.g. io.EOF or the context get cancelled.
+// The namespace attribute is set from the first message which has it.
+type tracingStream struct {
+	grpc.ClientStream
+	span Span
+
+	mu        sync.Mutex
+	namespace bool
+	done      bool
+}
+
+func (s *tracingStream) SendMsg(m interface{}) error {
+	s.mu.Lock()
+	if r, ok := m.(namespacedRequest); ok && !s.namespace && r.GetNamespace() != "" {
+		s.namespace = true
+		s.span.SetAttribute(AttributeNamespace, r.GetNamespace())
+	}
+	s.mu.Unlock()
+	return s.ClientStream.SendMsg(m)
+}
+
+func (s *tracingStream) RecvMsg(m interface{}) error {
+	err := s.ClientStream.RecvMsg(m)
+	if err == nil {
+		return nil
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !s.done {
+		s.done = true
+		if err == io.EOF {
+			endRPCSpan(s.span, nil)
+		} else {
+			endRPCSpan(s.span, err)
+		}
+	}
+	return err
+}
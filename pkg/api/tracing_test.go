@@ -0,0 +1,114 @@
+package api
+
+import (
+	"net"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	grpcstatus "google.golang.org/grpc/status"
+
+	containers "github.com/ernoaapa/eliot/pkg/api/services/containers/v1"
+	pods "github.com/ernoaapa/eliot/pkg/api/services/pods/v1"
+	"github.com/ernoaapa/eliot/pkg/config"
+)
+
+type fakeTracer struct {
+	mu    sync.Mutex
+	spans []*fakeSpan
+}
+
+type fakeSpan struct {
+	name       string
+	attributes map[string]string
+	events     []string
+	ended      bool
+	err        error
+}
+
+func (t *fakeTracer) Start(ctx context.Context, name string, attributes map[string]string) (context.Context, Span) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	span := &fakeSpan{name: name, attributes: attributes}
+	t.spans = append(t.spans, span)
+	return ctx, span
+}
+
+func (t *fakeTracer) Inject(ctx context.Context, set func(key, value string)) {
+	set("Traceparent", "00-trace-span-01")
+}
+
+func (s *fakeSpan) SetAttribute(key, value string) { s.attributes[key] = value }
+func (s *fakeSpan) AddEvent(name string)           { s.events = append(s.events, name) }
+func (s *fakeSpan) End(err error)                  { s.ended, s.err = true, err }
+
+func TestWithTracer(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+
+	received := make(chan metadata.MD, 2)
+	server := grpc.NewServer(
+		grpc.UnaryInterceptor(func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+			md, _ := metadata.FromIncomingContext(ctx)
+			received <- md
+			return handler(ctx, req)
+		}),
+	)
+	pods.RegisterPodsServer(server, &fakePodsServer{list: func(req *pods.ListPodsRequest) (*pods.ListPodsResponse, error) {
+		if req.LabelSelector != "" {
+			return nil, grpcstatus.Error(codes.Unavailable, "node is restarting")
+		}
+		return &pods.ListPodsResponse{}, nil
+	}})
+	containers.RegisterContainersServer(server, &fakeContainersServer{logs: func(req *containers.LogsRequest, server containers.Containers_LogsServer) error {
+		return server.Send(&containers.LogsStreamResponse{Lines: []*containers.LogLine{{Line: []byte("hello\n")}}})
+	}})
+	go server.Serve(listener)
+	defer server.Stop()
+
+	tracer := &fakeTracer{}
+	client, err := NewClient("apps", config.Endpoint{URL: listener.Addr().String()}, WithInsecure(), WithTracer(tracer))
+	assert.NoError(t, err)
+	defer client.Close()
+
+	_, err = client.GetPods(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"00-trace-span-01"}, (<-received)["traceparent"], "should propagate the trace context")
+
+	_, err = client.GetPodsBySelector(context.Background(), "app=foo")
+	assert.Error(t, err)
+	<-received
+
+	_, err = client.GetContainerLogs(context.Background(), "foo", LogFormatRaw)
+	assert.NoError(t, err)
+
+	assert.Len(t, tracer.spans, 3)
+	list := tracer.spans[0]
+	assert.Equal(t, "cand.services.pods.v1.Pods/List", list.name)
+	assert.Equal(t, map[string]string{
+		AttributeRPCSystem:     "grpc",
+		AttributeRPCService:    "cand.services.pods.v1.Pods",
+		AttributeRPCMethod:     "List",
+		AttributeServerAddress: listener.Addr().String(),
+		AttributeNamespace:     "apps",
+		AttributeStatusCode:    "OK",
+	}, list.attributes)
+	assert.True(t, list.ended)
+	assert.NoError(t, list.err)
+
+	failed := tracer.spans[1]
+	assert.True(t, failed.ended)
+	assert.Error(t, failed.err)
+	assert.Equal(t, "Unavailable", failed.attributes[AttributeStatusCode])
+
+	logs := tracer.spans[2]
+	assert.Equal(t, "Logs", logs.attributes[AttributeRPCMethod])
+	assert.Equal(t, "apps", logs.attributes[AttributeNamespace], "should take the namespace from the stream request")
+	assert.Equal(t, []string{"stream opened"}, logs.events)
+	assert.True(t, logs.ended, "should end the span when the stream ends")
+	assert.NoError(t, logs.err)
+}
@@ -0,0 +1,130 @@
+package api
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/ernoaapa/eliot/pkg/model"
+)
+
+// podUpdate lists container names which must change to get from the current pod to the desired pod
+type podUpdate struct {
+	added     []string
+	removed   []string
+	recreated []string
+}
+
+// planPodUpdate compares the current pod to the desired pod and resolves which containers must be
+// created, removed or recreated. Containers which spec hash haven't changed are left untouched.
+// Containers connected with a pipe get recreated together, because the pipe is set up when they start.
+func planPodUpdate(current, desired model.Pod) (update podUpdate) {
+	currentHashes := map[string]string{}
+	for _, status := range current.Status.ContainerStatuses {
+		currentHashes[status.Name] = status.SpecHash
+	}
+
+	desiredNames := map[string]bool{}
+	changed := map[string]bool{}
+	for _, container := range desired.Spec.Containers {
+		desiredNames[container.Name] = true
+
+		hash, exist := currentHashes[container.Name]
+		if !exist {
+			update.added = append(update.added, container.Name)
+			changed[container.Name] = true
+		} else if hash != model.GetContainerSpecHash(desired.Spec, container) {
+			changed[container.Name] = true
+		}
+	}
+
+	for _, status := range current.Status.ContainerStatuses {
+		if !desiredNames[status.Name] {
+			update.removed = append(update.removed, status.Name)
+		}
+	}
+
+	peers := getPipePeers(desired.Spec.Containers)
+	for expanded := true; expanded; {
+		expanded = false
+		for name := range changed {
+			for _, peer := range peers[name] {
+				if !changed[peer] {
+					changed[peer] = true
+					expanded = true
+				}
+			}
+		}
+	}
+
+	for _, container := range desired.Spec.Containers {
+		if _, exist := currentHashes[container.Name]; exist && changed[container.Name] {
+			update.recreated = append(update.recreated, container.Name)
+		}
+	}
+	return update
+}
+
+func getPipePeers(containers []model.Container) map[string][]string {
+	peers := map[string][]string{}
+	for _, container := range containers {
+		if container.Pipe != nil && container.Pipe.Stdout != nil && container.Pipe.Stdout.Stdin != nil {
+			target := container.Pipe.Stdout.Stdin.Name
+			peers[container.Name] = append(peers[container.Name], target)
+			peers[target] = append(peers[target], container.Name)
+		}
+	}
+	return peers
+}
+
+// podLocks serializes the changes to the same pod, so that concurrent creates and updates don't interleave
+type podLocks struct {
+	mu    sync.Mutex
+	locks map[string]*podLock
+}
+
+type podLock struct {
+	sync.Mutex
+	refs int
+}
+
+// lock waits until no one else is changing the pod and return function which releases the lock
+func (l *podLocks) lock(namespace, name string) func() {
+	key := fmt.Sprintf("%s/%s", namespace, name)
+
+	l.mu.Lock()
+	if l.locks == nil {
+		l.locks = map[string]*podLock{}
+	}
+	lock, ok := l.locks[key]
+	if !ok {
+		lock = &podLock{}
+		l.locks[key] = lock
+	}
+	lock.refs++
+	l.mu.Unlock()
+
+	lock.Lock()
+	return func() {
+		lock.Unlock()
+
+		l.mu.Lock()
+		defer l.mu.Unlock()
+		lock.refs--
+		if lock.refs == 0 {
+			delete(l.locks, key)
+		}
+	}
+}
+
+// labelsEqual return true if both have the same labels, nil and empty are equal
+func labelsEqual(a, b map[string]string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for key, value := range a {
+		if other, ok := b[key]; !ok || other != value {
+			return false
+		}
+	}
+	return true
+}
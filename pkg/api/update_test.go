@@ -0,0 +1,82 @@
+package api
+
+import (
+	"testing"
+
+	"github.com/ernoaapa/eliot/pkg/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func newRunningPod(spec model.PodSpec) model.Pod {
+	pod := model.Pod{Spec: spec}
+	for _, container := range spec.Containers {
+		pod.Status.ContainerStatuses = append(pod.Status.ContainerStatuses, model.ContainerStatus{
+			Name:     container.Name,
+			SpecHash: model.GetContainerSpecHash(spec, container),
+		})
+	}
+	return pod
+}
+
+func TestPlanPodUpdate(t *testing.T) {
+	current := newRunningPod(model.PodSpec{
+		Containers: []model.Container{
+			{Name: "unchanged", Image: "docker.io/library/foo:1"},
+			{Name: "image-changed", Image: "docker.io/library/bar:1"},
+			{Name: "env-changed", Image: "docker.io/library/bar:1", Env: []string{"FOO=1"}},
+			{Name: "removed", Image: "docker.io/library/bar:1"},
+		},
+	})
+	desired := model.Pod{
+		Spec: model.PodSpec{
+			Containers: []model.Container{
+				{Name: "unchanged", Image: "docker.io/library/foo:1"},
+				{Name: "image-changed", Image: "docker.io/library/bar:2"},
+				{Name: "env-changed", Image: "docker.io/library/bar:1", Env: []string{"FOO=2"}},
+				{Name: "added", Image: "docker.io/library/baz:1"},
+			},
+		},
+	}
+
+	update := planPodUpdate(current, desired)
+	assert.Equal(t, []string{"added"}, update.added)
+	assert.Equal(t, []string{"removed"}, update.removed)
+	assert.Equal(t, []string{"image-changed", "env-changed"}, update.recreated)
+}
+
+func TestPlanPodUpdateRecreatesPipePeers(t *testing.T) {
+	pipe := &model.PipeSet{Stdout: &model.PipeFromStdout{Stdin: &model.PipeToStdin{Name: "target"}}}
+	current := newRunningPod(model.PodSpec{
+		Containers: []model.Container{
+			{Name: "source", Image: "docker.io/library/foo:1", Pipe: pipe},
+			{Name: "target", Image: "docker.io/library/bar:1"},
+			{Name: "other", Image: "docker.io/library/baz:1"},
+		},
+	})
+	desired := model.Pod{
+		Spec: model.PodSpec{
+			Containers: []model.Container{
+				{Name: "source", Image: "docker.io/library/foo:1", Pipe: pipe},
+				{Name: "target", Image: "docker.io/library/bar:2"},
+				{Name: "other", Image: "docker.io/library/baz:1"},
+			},
+		},
+	}
+
+	update := planPodUpdate(current, desired)
+	assert.Empty(t, update.added)
+	assert.Empty(t, update.removed)
+	assert.Equal(t, []string{"source", "target"}, update.recreated)
+}
+
+func TestPlanPodUpdateNoChanges(t *testing.T) {
+	spec := model.PodSpec{
+		Containers: []model.Container{
+			{Name: "foo", Image: "docker.io/library/foo:1"},
+		},
+	}
+	update := planPodUpdate(newRunningPod(spec), model.Pod{Spec: spec})
+	assert.Empty(t, update.added)
+	assert.Empty(t, update.removed)
+	assert.Empty(t, update.recreated)
+}
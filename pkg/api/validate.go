@@ -0,0 +1,113 @@
+package api
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	containers "github.com/ernoaapa/eliot/pkg/api/services/containers/v1"
+	pods "github.com/ernoaapa/eliot/pkg/api/services/pods/v1"
+	"github.com/ernoaapa/eliot/pkg/model"
+)
+
+// podNamePattern is DNS label (RFC 1123), so that the pod name can be used as hostname
+var podNamePattern = regexp.MustCompile("^[a-z0-9]([-a-z0-9]*[a-z0-9])?$")
+
+// maxPodNameLength is the maximum length of DNS label
+const maxPodNameLength = 63
+
+// ValidationError lists all problems found in the pod definition
+// The error matches also to ErrInvalidArgument.
+type ValidationError struct {
+	Problems []string
+}
+
+func (e *ValidationError) Error() string {
+	return fmt.Sprintf("Invalid pod definition: %s", strings.Join(e.Problems, ", "))
+}
+
+// Is makes errors.Is(err, ErrInvalidArgument) to match the validation error
+func (e *ValidationError) Is(target error) bool {
+	return target == ErrInvalidArgument
+}
+
+// ValidatePod checks that the pod has all required fields before it's sent to the server.
+// Returns ValidationError which lists all found problems at once.
+func ValidatePod(pod *pods.Pod) error {
+	problems := []string{}
+
+	if problem := validatePodName(pod.GetMetadata().GetName()); problem != "" {
+		problems = append(problems, problem)
+	}
+
+	if policy := pod.GetSpec().GetRestartPolicy(); policy != "" && !isRestartPolicy(policy) {
+		problems = append(problems, fmt.Sprintf("pod restart policy [%s] must be one of %v", policy, restartPolicies))
+	}
+
+	containers := pod.GetSpec().GetContainers()
+	if len(containers) == 0 {
+		problems = append(problems, "pod must have at least one container")
+	}
+
+	names := map[string]bool{}
+	for i, container := range containers {
+		problems = append(problems, validateContainer(fmt.Sprintf("container #%d", i+1), container, names)...)
+	}
+
+	for i, container := range pod.GetSpec().GetInitContainers() {
+		kind := fmt.Sprintf("init container #%d", i+1)
+		problems = append(problems, validateContainer(kind, container, names)...)
+		if container.GetRestartPolicy() == string(RestartAlways) {
+			problems = append(problems, fmt.Sprintf("%s restart policy [%s] must be one of %v, init containers run to completion", kind, container.GetRestartPolicy(), initRestartPolicies))
+		}
+		if container.GetReadinessProbe() != nil {
+			problems = append(problems, fmt.Sprintf("%s must not have readiness probe, init containers run to completion", kind))
+		}
+	}
+
+	if len(problems) > 0 {
+		return &ValidationError{Problems: problems}
+	}
+	return nil
+}
+
+// validateContainer return the problems in the container, names are the container names seen so far in the pod
+func validateContainer(kind string, container *containers.Container, names map[string]bool) (problems []string) {
+	if container.GetName() == "" {
+		problems = append(problems, fmt.Sprintf("%s name must not be empty", kind))
+	} else if names[container.GetName()] {
+		problems = append(problems, fmt.Sprintf("container name [%s] is defined more than once", container.GetName()))
+	}
+	names[container.GetName()] = true
+
+	switch {
+	case container.GetImage() == "":
+		problems = append(problems, fmt.Sprintf("%s image must not be empty", kind))
+	case !model.IsValidImageReference(container.GetImage()):
+		problems = append(problems, fmt.Sprintf("%s image [%s] is not valid image reference", kind, container.GetImage()))
+	}
+
+	if policy := container.GetRestartPolicy(); policy != "" && !isRestartPolicy(policy) {
+		problems = append(problems, fmt.Sprintf("%s restart policy [%s] must be one of %v", kind, policy, restartPolicies))
+	}
+
+	if probe := container.GetReadinessProbe(); probe != nil {
+		if problem := validateProbe(probe); problem != "" {
+			problems = append(problems, fmt.Sprintf("%s readiness probe %s", kind, problem))
+		}
+	}
+	return problems
+}
+
+// validatePodName return the problem in the pod name, empty if the name is valid
+func validatePodName(name string) string {
+	switch {
+	case name == "":
+		return "pod name must not be empty"
+	case len(name) > maxPodNameLength:
+		return fmt.Sprintf("pod name [%s] must be at most %d characters", name, maxPodNameLength)
+	case !podNamePattern.MatchString(name):
+		return fmt.Sprintf("pod name [%s] must contain only lowercase alphanumeric characters or '-', and start and end with alphanumeric character", name)
+	}
+	return ""
+}
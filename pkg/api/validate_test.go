@@ -0,0 +1,95 @@
+package api
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ernoaapa/eliot/pkg/api/core"
+	containers "github.com/ernoaapa/eliot/pkg/api/services/containers/v1"
+	pods "github.com/ernoaapa/eliot/pkg/api/services/pods/v1"
+)
+
+func TestValidatePod(t *testing.T) {
+	err := ValidatePod(&pods.Pod{
+		Metadata: &core.ResourceMetadata{Name: "my-pod"},
+		Spec: &pods.PodSpec{
+			Containers: []*containers.Container{
+				{Name: "foo", Image: "docker.io/library/foo:latest"},
+				{Name: "bar", Image: "docker.io/library/bar:latest"},
+			},
+		},
+	})
+	assert.NoError(t, err)
+}
+
+func TestValidatePodListsAllProblems(t *testing.T) {
+	err := ValidatePod(&pods.Pod{
+		Metadata: &core.ResourceMetadata{Name: "My_Pod"},
+		Spec: &pods.PodSpec{
+			Containers: []*containers.Container{
+				{Name: "foo", Image: "docker.io/library/foo:latest"},
+				{Name: "foo", Image: "docker.io/library/foo:latest"},
+				{Name: "", Image: ""},
+			},
+		},
+	})
+	assert.Error(t, err)
+	assert.True(t, errors.Is(err, ErrInvalidArgument))
+
+	validationErr, ok := err.(*ValidationError)
+	assert.True(t, ok, "should return ValidationError")
+	assert.Len(t, validationErr.Problems, 4)
+}
+
+func TestValidatePodWithoutContainers(t *testing.T) {
+	err := ValidatePod(&pods.Pod{})
+
+	validationErr, ok := err.(*ValidationError)
+	assert.True(t, ok, "should return ValidationError")
+	assert.Equal(t, []string{"pod name must not be empty", "pod must have at least one container"}, validationErr.Problems)
+}
+
+func TestValidatePodRestartPolicy(t *testing.T) {
+	err := ValidatePod(&pods.Pod{
+		Metadata: &core.ResourceMetadata{Name: "my-pod"},
+		Spec: &pods.PodSpec{
+			RestartPolicy: "sometimes",
+			Containers: []*containers.Container{
+				{Name: "foo", Image: "docker.io/library/foo:latest", RestartPolicy: "never"},
+				{Name: "bar", Image: "docker.io/library/bar:latest", RestartPolicy: "Always"},
+			},
+		},
+	})
+
+	validationErr, ok := err.(*ValidationError)
+	assert.True(t, ok, "should return ValidationError")
+	assert.Equal(t, []string{
+		"pod restart policy [sometimes] must be one of [always onfailure never]",
+		"container #2 restart policy [Always] must be one of [always onfailure never]",
+	}, validationErr.Problems)
+}
+
+func TestValidatePodInitContainers(t *testing.T) {
+	err := ValidatePod(&pods.Pod{
+		Metadata: &core.ResourceMetadata{Name: "my-pod"},
+		Spec: &pods.PodSpec{
+			Containers: []*containers.Container{
+				{Name: "app", Image: "docker.io/library/app:latest"},
+			},
+			InitContainers: []*containers.Container{
+				{Name: "migrate", Image: "docker.io/library/app:latest", RestartPolicy: "always"},
+				{Name: "app", Image: ""},
+			},
+		},
+	})
+
+	validationErr, ok := err.(*ValidationError)
+	assert.True(t, ok, "should return ValidationError")
+	assert.Equal(t, []string{
+		"init container #1 restart policy [always] must be one of [onfailure never], init containers run to completion",
+		"container name [app] is defined more than once",
+		"init container #2 image must not be empty",
+	}, validationErr.Problems)
+}
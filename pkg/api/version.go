@@ -0,0 +1,43 @@
+package api
+
+import (
+	"strings"
+
+	node "github.com/ernoaapa/eliot/pkg/api/services/node/v1"
+)
+
+// APIVersion is the API version this client and server are compiled with.
+// Minor version grows when new fields or methods get added,
+// major version changes only when the API breaks backward compatibility.
+const APIVersion = "1.1"
+
+// VersionInfo is the server and container runtime version information
+type VersionInfo struct {
+	ServerVersion string
+	// APIVersion is the API version what the server implements.
+	// Empty if the server is older than the version reporting.
+	APIVersion     string
+	RuntimeVersion string
+}
+
+// IsCompatible return true if the server API has the same major version as the client.
+// Servers which don't report API version are assumed to be compatible.
+func (v *VersionInfo) IsCompatible() bool {
+	if v.APIVersion == "" {
+		return true
+	}
+	return majorVersion(v.APIVersion) == majorVersion(APIVersion)
+}
+
+func majorVersion(version string) string {
+	return strings.SplitN(strings.TrimPrefix(version, "v"), ".", 2)[0]
+}
+
+// MapVersionInfo maps the version fields from the node info
+func MapVersionInfo(info *node.Info) *VersionInfo {
+	return &VersionInfo{
+		ServerVersion:  info.GetVersion(),
+		APIVersion:     info.GetApiVersion(),
+		RuntimeVersion: info.GetRuntimeVersion(),
+	}
+}
@@ -0,0 +1,15 @@
+package api
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVersionInfoIsCompatible(t *testing.T) {
+	assert.True(t, (&VersionInfo{APIVersion: APIVersion}).IsCompatible())
+	assert.True(t, (&VersionInfo{APIVersion: "1.0"}).IsCompatible())
+	assert.True(t, (&VersionInfo{APIVersion: "v1.5"}).IsCompatible())
+	assert.True(t, (&VersionInfo{}).IsCompatible(), "old servers don't report API version")
+	assert.False(t, (&VersionInfo{APIVersion: "2.0"}).IsCompatible())
+}
@@ -0,0 +1,125 @@
+package api
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	pods "github.com/ernoaapa/eliot/pkg/api/services/pods/v1"
+	"github.com/ernoaapa/eliot/pkg/model"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+)
+
+// removePodVolumes removes the pod volumes, which are the pod bind mount sources in the volumes directory.
+// The volumes which other pods mount, or which contain or are inside a path other pods mount, are retained.
+// In dry run, only return what would be removed.
+func (s *Server) removePodVolumes(pod model.Pod, dryRun bool) (removed []string, retained []*pods.RetainedVolume, err error) {
+	volumes := getPodVolumes(pod, s.volumesDir)
+	if len(volumes) == 0 {
+		return nil, nil, nil
+	}
+
+	others, err := s.getOtherPods(pod)
+	if err != nil {
+		return nil, nil, errors.Wrapf(err, "Cannot resolve pod [%s] volume references", pod.Metadata.Name)
+	}
+
+	for _, volume := range volumes {
+		if refs := getVolumeReferences(volume, others); len(refs) > 0 {
+			log.Debugf("Retain volume [%s] of pod [%s], still mounted by %v", volume, pod.Metadata.Name, refs)
+			retained = append(retained, &pods.RetainedVolume{Path: volume, Pods: refs})
+			continue
+		}
+		if !dryRun {
+			if err := os.RemoveAll(volume); err != nil {
+				return removed, retained, errors.Wrapf(err, "Failed to remove pod [%s] volume [%s]", pod.Metadata.Name, volume)
+			}
+			removeEmptyParents(volume, s.volumesDir)
+		}
+		removed = append(removed, volume)
+	}
+	return removed, retained, nil
+}
+
+// getOtherPods return the pods in all namespaces, except the pod
+func (s *Server) getOtherPods(pod model.Pod) ([]model.Pod, error) {
+	namespaces, err := s.client.GetNamespaces()
+	if err != nil {
+		return nil, err
+	}
+
+	result := []model.Pod{}
+	for _, namespace := range namespaces {
+		list, err := s.client.GetPods(namespace)
+		if err != nil {
+			return nil, err
+		}
+		for _, other := range list {
+			if other.Metadata.Namespace == pod.Metadata.Namespace && other.Metadata.Name == pod.Metadata.Name {
+				continue
+			}
+			result = append(result, other)
+		}
+	}
+	return result, nil
+}
+
+// getPodVolumes return the bind mount sources of the pod containers which are inside the volumes directory
+func getPodVolumes(pod model.Pod, volumesDir string) []string {
+	found := map[string]bool{}
+	for _, source := range getBindSources(pod) {
+		if isSubPath(source, volumesDir) && filepath.Clean(source) != filepath.Clean(volumesDir) {
+			found[filepath.Clean(source)] = true
+		}
+	}
+
+	result := make([]string, 0, len(found))
+	for volume := range found {
+		result = append(result, volume)
+	}
+	sort.Strings(result)
+	return result
+}
+
+// getVolumeReferences return the pods, as namespace/name, which mount the volume, a path inside it or its parent directory
+func getVolumeReferences(volume string, others []model.Pod) []string {
+	refs := []string{}
+	for _, other := range others {
+		for _, source := range getBindSources(other) {
+			if isSubPath(source, volume) || isSubPath(volume, source) {
+				refs = append(refs, fmt.Sprintf("%s/%s", other.Metadata.Namespace, other.Metadata.Name))
+				break
+			}
+		}
+	}
+	return refs
+}
+
+func getBindSources(pod model.Pod) (result []string) {
+	for _, container := range append(append([]model.Container{}, pod.Spec.InitContainers...), pod.Spec.Containers...) {
+		for _, mount := range container.Mounts {
+			if mount.Type == "bind" && filepath.IsAbs(mount.Source) {
+				result = append(result, filepath.Clean(mount.Source))
+			}
+		}
+	}
+	return result
+}
+
+// isSubPath return true if the path is the dir or inside it
+func isSubPath(path, dir string) bool {
+	path, dir = filepath.Clean(path), filepath.Clean(dir)
+	return path == dir || strings.HasPrefix(path, strings.TrimSuffix(dir, "/")+"/")
+}
+
+// removeEmptyParents removes the empty parent directories of the path, up to the root which is kept
+func removeEmptyParents(path, root string) {
+	for dir := filepath.Dir(path); dir != filepath.Clean(root) && isSubPath(dir, root); dir = filepath.Dir(dir) {
+		if err := os.Remove(dir); err != nil {
+			return
+		}
+	}
+}
@@ -0,0 +1,104 @@
+package api
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	pods "github.com/ernoaapa/eliot/pkg/api/services/pods/v1"
+	"github.com/ernoaapa/eliot/pkg/fs"
+	"github.com/ernoaapa/eliot/pkg/model"
+	"github.com/ernoaapa/eliot/pkg/runtime"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/net/context"
+)
+
+// volumesRuntime is runtime which has fixed pods without containers running
+type volumesRuntime struct {
+	runtime.Client
+	pods []model.Pod
+}
+
+func (r *volumesRuntime) GetNamespaces() ([]string, error) {
+	return []string{"eliot", "other"}, nil
+}
+
+func (r *volumesRuntime) GetPods(namespace string) (result []model.Pod, err error) {
+	for _, pod := range r.pods {
+		if pod.Metadata.Namespace == namespace {
+			result = append(result, pod)
+		}
+	}
+	return result, nil
+}
+
+func (r *volumesRuntime) GetPod(namespace, name string) (model.Pod, error) {
+	for _, pod := range r.pods {
+		if pod.Metadata.Namespace == namespace && pod.Metadata.Name == name {
+			return pod, nil
+		}
+	}
+	return model.Pod{}, runtime.ErrWithMessagef(runtime.ErrNotFound, "Pod [%s] not found", name)
+}
+
+func newVolumesTestPod(namespace, name string, sources ...string) model.Pod {
+	mounts := []model.Mount{{Type: "proc", Source: "proc", Destination: "/proc"}}
+	for _, source := range sources {
+		mounts = append(mounts, model.Mount{Type: "bind", Source: source, Destination: "/data"})
+	}
+	return model.Pod{
+		Metadata: model.Metadata{Namespace: namespace, Name: name},
+		Spec:     model.PodSpec{Containers: []model.Container{{Name: name, Mounts: mounts}}},
+	}
+}
+
+func TestServerDeleteCascade(t *testing.T) {
+	dir, err := ioutil.TempDir("", "volumes")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	own := filepath.Join(dir, "foo", "app")
+	shared := filepath.Join(dir, "shared")
+	for _, path := range []string{own, shared} {
+		assert.NoError(t, os.MkdirAll(path, 0755))
+	}
+
+	server := NewServer("", &volumesRuntime{pods: []model.Pod{
+		newVolumesTestPod("eliot", "foo", own, shared, "/etc/config"),
+		newVolumesTestPod("other", "bar", filepath.Join(shared, "data")),
+	}}, nil)
+	server.volumesDir = dir
+
+	resp, err := server.Delete(context.Background(), &pods.DeletePodRequest{Namespace: "eliot", Name: "foo", DryRun: true, Cascade: true})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{own}, resp.RemovedVolumes)
+	assert.True(t, fs.DirExist(own), "should not remove anything in dry run")
+
+	resp, err = server.Delete(context.Background(), &pods.DeletePodRequest{Namespace: "eliot", Name: "foo", Cascade: true})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{own}, resp.RemovedVolumes)
+	assert.Equal(t, []*pods.RetainedVolume{{Path: shared, Pods: []string{"other/bar"}}}, resp.RetainedVolumes)
+
+	_, err = os.Stat(filepath.Join(dir, "foo"))
+	assert.True(t, os.IsNotExist(err), "should remove the empty pod volume directory")
+	assert.True(t, fs.DirExist(shared), "should keep the volume other pod mounts")
+	assert.True(t, fs.DirExist(dir))
+}
+
+func TestServerDeleteKeepsVolumesByDefault(t *testing.T) {
+	dir, err := ioutil.TempDir("", "volumes")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	own := filepath.Join(dir, "foo")
+	assert.NoError(t, os.MkdirAll(own, 0755))
+
+	server := NewServer("", &volumesRuntime{pods: []model.Pod{newVolumesTestPod("eliot", "foo", own)}}, nil)
+	server.volumesDir = dir
+
+	resp, err := server.Delete(context.Background(), &pods.DeletePodRequest{Namespace: "eliot", Name: "foo"})
+	assert.NoError(t, err)
+	assert.Empty(t, resp.RemovedVolumes)
+	assert.True(t, fs.DirExist(own))
+}
@@ -0,0 +1,311 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	containers "github.com/ernoaapa/eliot/pkg/api/services/containers/v1"
+	pods "github.com/ernoaapa/eliot/pkg/api/services/pods/v1"
+	"github.com/ernoaapa/eliot/pkg/model"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc/codes"
+)
+
+// waitPollInterval is how often WaitForPodReady polls the pod when watching is not available
+const waitPollInterval = time.Second
+
+// IsPodReady return true if every init container in the pod spec has completed and every container is running.
+// The containers which have readiness probe must also have passed the probe.
+func IsPodReady(pod *pods.Pod) bool {
+	return pod != nil && len(pod.GetSpec().GetContainers()) > 0 && len(getNotCompletedInitContainers(pod)) == 0 && len(getNotReadyContainers(pod)) == 0
+}
+
+// getNotCompletedInitContainers return names of the pod init containers which have not exited successfully
+func getNotCompletedInitContainers(pod *pods.Pod) (result []string) {
+	completed := map[string]bool{}
+	for _, status := range pod.GetStatus().GetInitContainerStatuses() {
+		state := MapContainerState(status)
+		completed[status.GetName()] = state.Exited && state.Reason == model.ReasonCompleted
+	}
+	for _, container := range pod.GetSpec().GetInitContainers() {
+		if !completed[container.GetName()] {
+			result = append(result, container.GetName())
+		}
+	}
+	return result
+}
+
+// getFailedInitContainer return status of the init container which exited with error, nil if none has failed
+func getFailedInitContainer(pod *pods.Pod) *containers.ContainerStatus {
+	for _, status := range pod.GetStatus().GetInitContainerStatuses() {
+		state := MapContainerState(status)
+		if state.Exited && state.Reason != model.ReasonCompleted {
+			return status
+		}
+	}
+	return nil
+}
+
+// checkInitContainers return ErrInitContainerFailed if init container has failed and won't be restarted
+func checkInitContainers(name string, pod *pods.Pod) error {
+	failed := getFailedInitContainer(pod)
+	if failed == nil {
+		return nil
+	}
+	if policy, err := GetContainerRestartPolicy(pod, failed.GetName()); err != nil || policy != RestartNever {
+		return nil
+	}
+	return &Error{
+		Code:    codes.FailedPrecondition,
+		Message: fmt.Sprintf("Pod [%s] init container [%s] failed with exit code %d", name, failed.GetName(), failed.GetExitCode()),
+		cause:   ErrInitContainerFailed,
+	}
+}
+
+// getNotReadyContainers return names of the pod containers which are not running or which readiness probe
+// has not succeeded
+func getNotReadyContainers(pod *pods.Pod) (result []string) {
+	for _, container := range pod.GetSpec().GetContainers() {
+		if !isContainerReady(container, getContainerStatusByName(pod, container.GetName())) {
+			result = append(result, container.GetName())
+		}
+	}
+	return result
+}
+
+// getNotRunningContainers return names of the pod containers which are not running
+func getNotRunningContainers(pod *pods.Pod) (result []string) {
+	for _, container := range pod.GetSpec().GetContainers() {
+		if !MapContainerState(getContainerStatusByName(pod, container.GetName())).Running {
+			result = append(result, container.GetName())
+		}
+	}
+	return result
+}
+
+// getFailedReadinessProbes return the running pod containers which readiness probe has not succeeded, with the reason
+func getFailedReadinessProbes(pod *pods.Pod) (result []string) {
+	for _, container := range pod.GetSpec().GetContainers() {
+		status := getContainerStatusByName(pod, container.GetName())
+		if !MapContainerState(status).Running || isContainerReady(container, status) {
+			continue
+		}
+		if message := status.GetReadinessMessage(); message != "" {
+			result = append(result, fmt.Sprintf("%s (%s)", container.GetName(), message))
+		} else {
+			result = append(result, container.GetName())
+		}
+	}
+	return result
+}
+
+// getContainerStatusByName return the status of the pod container, nil if the container doesn't have status
+func getContainerStatusByName(pod *pods.Pod, containerName string) *containers.ContainerStatus {
+	for _, status := range pod.GetStatus().GetContainerStatuses() {
+		if status.GetName() == containerName {
+			return status
+		}
+	}
+	return nil
+}
+
+// WaitForPodReady waits until all init containers of the pod have completed and all containers are running.
+// The containers which have readiness probe, see WithHTTPReadinessProbe, must also have passed the probe.
+// It watches the pod changes and falls back to polling if the server doesn't support watching.
+// Zero timeout means wait until the context get cancelled.
+// On timeout returns the last observed pod with ErrPodNotReady, so you can see which containers are not running.
+// If init container fails and its restart policy is RestartNever, returns right away with ErrInitContainerFailed
+// which tells the init container and its exit code.
+func (c *Client) WaitForPodReady(ctx context.Context, name string, timeout time.Duration) (*pods.Pod, error) {
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	pod, err := c.watchPodReady(ctx, name)
+	if err == nil && !IsPodReady(pod) && ctx.Err() == nil {
+		c.logger.Debugf("Pod watch stream closed before pod [%s] got ready, fall back to polling", name)
+		var last *pods.Pod
+		last, err = c.pollPodReady(ctx, name)
+		if last != nil {
+			pod = last
+		}
+	}
+	if err != nil {
+		return pod, err
+	}
+
+	if IsPodReady(pod) {
+		return pod, nil
+	}
+	if ctx.Err() == context.DeadlineExceeded {
+		return pod, newPodNotReadyError(name, pod, timeout)
+	}
+	return pod, translateError(ctx.Err())
+}
+
+// watchPodReady return the pod when it's ready, or the last observed pod when the context get cancelled
+// or the watch stream closes
+func (c *Client) watchPodReady(ctx context.Context, name string) (*pods.Pod, error) {
+	events, err := c.WatchPods(ctx, "")
+	if err != nil {
+		if errors.Is(err, ErrUnimplemented) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var last *pods.Pod
+	for event := range events {
+		if event.Pod.GetMetadata().GetName() != name {
+			continue
+		}
+		if event.Type == PodDeleted {
+			last = nil
+			continue
+		}
+		last = event.Pod
+		if IsPodReady(last) {
+			return last, nil
+		}
+		if err := checkInitContainers(name, last); err != nil {
+			return last, err
+		}
+	}
+	return last, nil
+}
+
+// pollPodReady fetches the pod until it's ready, return the last observed pod when the context get cancelled
+func (c *Client) pollPodReady(ctx context.Context, name string) (*pods.Pod, error) {
+	var last *pods.Pod
+	for {
+		pod, err := c.GetPod(ctx, name)
+		switch {
+		case err == nil:
+			last = pod
+			if IsPodReady(pod) {
+				return pod, nil
+			}
+			if err := checkInitContainers(name, pod); err != nil {
+				return pod, err
+			}
+		case errors.Is(err, ErrNotFound) || ctx.Err() != nil:
+			// pod may not exist yet, or the context is already done which is handled below
+		default:
+			return last, err
+		}
+
+		select {
+		case <-ctx.Done():
+			return last, nil
+		case <-time.After(waitPollInterval):
+		}
+	}
+}
+
+// WaitForContainerRunning waits until the container in the pod is running and return its status.
+// The container name can be empty if the pod has only one container.
+// Container which is not yet created or started is waited, but if the pod doesn't exist, or the pod spec doesn't
+// have the container, returns ErrPodNotFound or ErrContainerNotFound right away.
+// Zero timeout means wait until the context get cancelled.
+func (c *Client) WaitForContainerRunning(ctx context.Context, podName, containerName string, timeout time.Duration) (*containers.ContainerStatus, error) {
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	for {
+		pod, err := c.GetPod(ctx, podName)
+		if err != nil {
+			if ctx.Err() == context.DeadlineExceeded {
+				return nil, newContainerNotRunningError(podName, containerName, timeout)
+			}
+			return nil, err
+		}
+
+		status, err := findContainerStatus(pod, containerName)
+		switch {
+		case err == nil && MapContainerState(status).Running:
+			return status, nil
+		case err != nil && !hasSpecContainer(pod, containerName):
+			return nil, err
+		}
+
+		select {
+		case <-ctx.Done():
+			if ctx.Err() == context.DeadlineExceeded {
+				return nil, newContainerNotRunningError(podName, containerName, timeout)
+			}
+			return nil, translateError(ctx.Err())
+		case <-time.After(waitPollInterval):
+		}
+	}
+}
+
+// WaitContainer blocks until the container exits and return its exit code, like docker wait.
+// The server waits for the runtime exit event, so there's no polling. If the container has already exited,
+// the recorded exit code is returned right away. Use context deadline to limit how long to wait.
+// Returns ErrContainerNotFound if the container doesn't exist, or ErrContainerNotRunning if it has never been started.
+func (c *Client) WaitContainer(ctx context.Context, containerID string) (exitCode int, err error) {
+	conn, err := c.getConnection()
+	if err != nil {
+		return -1, err
+	}
+
+	resp, err := containers.NewContainersClient(conn).Wait(ctx, &containers.WaitRequest{
+		Namespace:   c.Namespace,
+		ContainerID: containerID,
+	})
+	if err != nil {
+		return -1, translateStatsError(translateContainerError(err))
+	}
+	return int(resp.GetExitCode()), nil
+}
+
+// hasSpecContainer return true if the pod spec has the container, or with empty name, only one container
+func hasSpecContainer(pod *pods.Pod, containerName string) bool {
+	specs := pod.GetSpec().GetContainers()
+	if containerName == "" {
+		return len(specs) == 1
+	}
+	for _, container := range specs {
+		if container.GetName() == containerName {
+			return true
+		}
+	}
+	return false
+}
+
+func newContainerNotRunningError(podName, containerName string, timeout time.Duration) error {
+	message := fmt.Sprintf("Container [%s] in pod [%s] not running within %s", containerName, podName, timeout)
+	if containerName == "" {
+		message = fmt.Sprintf("Container in pod [%s] not running within %s", podName, timeout)
+	}
+	return &Error{
+		Code:    codes.DeadlineExceeded,
+		Message: message,
+	}
+}
+
+func newPodNotReadyError(name string, pod *pods.Pod, timeout time.Duration) error {
+	message := fmt.Sprintf("Pod [%s] not found within %s", name, timeout)
+	if pod != nil {
+		message = fmt.Sprintf("Pod [%s] not ready within %s, containers not running: [%s]", name, timeout, strings.Join(getNotRunningContainers(pod), ", "))
+		if failed := getFailedInitContainer(pod); failed != nil {
+			message = fmt.Sprintf("Pod [%s] not ready within %s, init container [%s] failed with exit code %d", name, timeout, failed.GetName(), failed.GetExitCode())
+		} else if pending := getNotCompletedInitContainers(pod); len(pending) > 0 {
+			message = fmt.Sprintf("Pod [%s] not ready within %s, init containers not completed: [%s]", name, timeout, strings.Join(pending, ", "))
+		} else if len(getNotRunningContainers(pod)) == 0 {
+			message = fmt.Sprintf("Pod [%s] not ready within %s, readiness probes not passed: [%s]", name, timeout, strings.Join(getFailedReadinessProbes(pod), ", "))
+		}
+	}
+	return &Error{
+		Code:    codes.DeadlineExceeded,
+		Message: message,
+		cause:   ErrPodNotReady,
+	}
+}
@@ -0,0 +1,230 @@
+package api
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ernoaapa/eliot/pkg/api/core"
+	containers "github.com/ernoaapa/eliot/pkg/api/services/containers/v1"
+	pods "github.com/ernoaapa/eliot/pkg/api/services/pods/v1"
+	"github.com/ernoaapa/eliot/pkg/runtime"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/net/context"
+)
+
+func newWaitTestPod(states map[string]string) *pods.Pod {
+	pod := &pods.Pod{
+		Metadata: &core.ResourceMetadata{Name: "my-pod"},
+		Spec: &pods.PodSpec{
+			Containers: []*containers.Container{{Name: "foo"}, {Name: "bar"}},
+		},
+		Status: &pods.PodStatus{},
+	}
+	for _, name := range []string{"foo", "bar"} {
+		if state, ok := states[name]; ok {
+			pod.Status.ContainerStatuses = append(pod.Status.ContainerStatuses, &containers.ContainerStatus{Name: name, State: state})
+		}
+	}
+	return pod
+}
+
+func TestIsPodReady(t *testing.T) {
+	assert.True(t, IsPodReady(newWaitTestPod(map[string]string{"foo": "running", "bar": "running"})))
+	assert.False(t, IsPodReady(newWaitTestPod(map[string]string{"foo": "running", "bar": "stopped"})))
+	assert.False(t, IsPodReady(newWaitTestPod(map[string]string{"foo": "running"})), "should not be ready until all containers have status")
+	assert.False(t, IsPodReady(&pods.Pod{}), "should not be ready without containers")
+	assert.False(t, IsPodReady(nil))
+}
+
+func TestPodNotReadyError(t *testing.T) {
+	err := newPodNotReadyError("my-pod", newWaitTestPod(map[string]string{"foo": "running", "bar": "created"}), 10*time.Second)
+
+	assert.True(t, errors.Is(err, ErrPodNotReady))
+	assert.True(t, errors.Is(err, ErrDeadlineExceeded))
+	assert.Equal(t, "Pod [my-pod] not ready within 10s, containers not running: [bar]", err.Error())
+
+	err = newPodNotReadyError("my-pod", nil, 10*time.Second)
+	assert.Equal(t, "Pod [my-pod] not found within 10s", err.Error())
+}
+
+func startFakeWaitServer(t *testing.T, list func() []*pods.Pod) (*Client, func()) {
+	return startFakeServer(t, &fakePodsServer{list: func(req *pods.ListPodsRequest) (*pods.ListPodsResponse, error) {
+		return &pods.ListPodsResponse{Pods: list()}, nil
+	}}, nil)
+}
+
+func TestWaitForContainerRunningWaitsContainerToStart(t *testing.T) {
+	var mu sync.Mutex
+	calls := 0
+	client, stop := startFakeWaitServer(t, func() []*pods.Pod {
+		mu.Lock()
+		defer mu.Unlock()
+		calls++
+		if calls == 1 {
+			// The bar container is not created yet
+			return []*pods.Pod{newWaitTestPod(map[string]string{"foo": "running"})}
+		}
+		return []*pods.Pod{newWaitTestPod(map[string]string{"foo": "running", "bar": "running"})}
+	})
+	defer stop()
+
+	status, err := client.WaitForContainerRunning(context.Background(), "my-pod", "bar", 5*time.Second)
+	assert.NoError(t, err)
+	assert.Equal(t, "bar", status.Name)
+}
+
+func TestWaitForContainerRunningFailsFast(t *testing.T) {
+	client, stop := startFakeWaitServer(t, func() []*pods.Pod {
+		return []*pods.Pod{newWaitTestPod(map[string]string{"foo": "created"})}
+	})
+	defer stop()
+
+	start := time.Now()
+	_, err := client.WaitForContainerRunning(context.Background(), "other-pod", "foo", 5*time.Second)
+	assert.True(t, errors.Is(err, ErrPodNotFound), "should fail if the pod doesn't exist")
+
+	_, err = client.WaitForContainerRunning(context.Background(), "my-pod", "baz", 5*time.Second)
+	assert.True(t, errors.Is(err, ErrContainerNotFound), "should fail if the pod spec doesn't have the container")
+
+	assert.True(t, time.Since(start) < waitPollInterval, "should not wait, but took %s", time.Since(start))
+}
+
+func TestWaitForContainerRunningTimeout(t *testing.T) {
+	client, stop := startFakeWaitServer(t, func() []*pods.Pod {
+		return []*pods.Pod{newWaitTestPod(map[string]string{"foo": "created", "bar": "running"})}
+	})
+	defer stop()
+
+	_, err := client.WaitForContainerRunning(context.Background(), "my-pod", "foo", 100*time.Millisecond)
+	assert.True(t, errors.Is(err, ErrDeadlineExceeded))
+	assert.Equal(t, "Container [foo] in pod [my-pod] not running within 100ms", err.Error())
+}
+
+func newInitTestPod(initStatus *containers.ContainerStatus, restartPolicy string) *pods.Pod {
+	pod := newWaitTestPod(map[string]string{"foo": "unknown", "bar": "unknown"})
+	pod.Spec.RestartPolicy = restartPolicy
+	pod.Spec.InitContainers = []*containers.Container{{Name: "migrate"}}
+	pod.Status.InitContainerStatuses = []*containers.ContainerStatus{initStatus}
+	return pod
+}
+
+func TestIsPodReadyWaitsInitContainers(t *testing.T) {
+	pod := newWaitTestPod(map[string]string{"foo": "running", "bar": "running"})
+	pod.Spec.InitContainers = []*containers.Container{{Name: "migrate"}}
+	assert.False(t, IsPodReady(pod), "should not be ready until init container has status")
+
+	pod.Status.InitContainerStatuses = []*containers.ContainerStatus{{Name: "migrate", State: "running"}}
+	assert.False(t, IsPodReady(pod))
+
+	pod.Status.InitContainerStatuses[0] = &containers.ContainerStatus{Name: "migrate", State: "stopped", Reason: "Completed"}
+	assert.True(t, IsPodReady(pod))
+}
+
+func TestWaitForPodReadyFailsWhenInitContainerFails(t *testing.T) {
+	client, stop := startFakeWaitServer(t, func() []*pods.Pod {
+		return []*pods.Pod{newInitTestPod(&containers.ContainerStatus{Name: "migrate", State: "stopped", ExitCode: 3, Reason: "Error"}, "never")}
+	})
+	defer stop()
+
+	start := time.Now()
+	_, err := client.WaitForPodReady(context.Background(), "my-pod", 5*time.Second)
+	assert.True(t, errors.Is(err, ErrInitContainerFailed))
+	assert.True(t, errors.Is(err, ErrFailedPrecondition))
+	assert.Equal(t, "Pod [my-pod] init container [migrate] failed with exit code 3", err.Error())
+	assert.True(t, time.Since(start) < waitPollInterval, "should not wait, but took %s", time.Since(start))
+}
+
+func TestPodNotReadyErrorTellsInitContainers(t *testing.T) {
+	// With onfailure policy the init container gets restarted, so the wait continues until the timeout
+	pod := newInitTestPod(&containers.ContainerStatus{Name: "migrate", State: "stopped", ExitCode: 1, Reason: "Error"}, "")
+	assert.NoError(t, checkInitContainers("my-pod", pod))
+	assert.Equal(t, "Pod [my-pod] not ready within 10s, init container [migrate] failed with exit code 1", newPodNotReadyError("my-pod", pod, 10*time.Second).Error())
+
+	pod = newInitTestPod(&containers.ContainerStatus{Name: "migrate", State: "running"}, "")
+	assert.Equal(t, "Pod [my-pod] not ready within 10s, init containers not completed: [migrate]", newPodNotReadyError("my-pod", pod, 10*time.Second).Error())
+}
+
+// waitRuntime exits the container when the exit channel closes, if exited is set the container has already exited
+type waitRuntime struct {
+	runtime.Client
+	exit     chan struct{}
+	exited   bool
+	exitCode int
+}
+
+func (r *waitRuntime) WaitContainer(namespace, name string, done <-chan struct{}) (int, error) {
+	switch name {
+	case "missing":
+		return -1, runtime.ErrWithMessagef(runtime.ErrNotFound, "Container [%s] not found", name)
+	case "created":
+		return -1, runtime.ErrWithMessagef(runtime.ErrNotRunning, "Container [%s] has not been started", name)
+	}
+	if r.exited {
+		return r.exitCode, nil
+	}
+	select {
+	case <-r.exit:
+		return r.exitCode, nil
+	case <-done:
+		return -1, runtime.ErrWithMessagef(context.Canceled, "Wait of container [%s] cancelled", name)
+	}
+}
+
+func TestWaitContainerBlocksUntilExit(t *testing.T) {
+	fake := &waitRuntime{exit: make(chan struct{}), exitCode: 3}
+	client, stop := startDiskUsageServer(t, fake)
+	defer stop()
+
+	result := make(chan int)
+	go func() {
+		exitCode, err := client.WaitContainer(context.Background(), "foo")
+		assert.NoError(t, err)
+		result <- exitCode
+	}()
+
+	select {
+	case <-result:
+		t.Fatal("WaitContainer returned before the container exited")
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	close(fake.exit)
+	select {
+	case exitCode := <-result:
+		assert.Equal(t, 3, exitCode)
+	case <-time.After(5 * time.Second):
+		t.Fatal("WaitContainer didn't return after the container exited")
+	}
+}
+
+func TestWaitContainerReturnsRecordedExitCode(t *testing.T) {
+	client, stop := startDiskUsageServer(t, &waitRuntime{exited: true, exitCode: 1})
+	defer stop()
+
+	exitCode, err := client.WaitContainer(context.Background(), "foo")
+	assert.NoError(t, err)
+	assert.Equal(t, 1, exitCode)
+}
+
+func TestWaitContainerTimeout(t *testing.T) {
+	client, stop := startDiskUsageServer(t, &waitRuntime{exit: make(chan struct{})})
+	defer stop()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	_, err := client.WaitContainer(ctx, "foo")
+	assert.True(t, errors.Is(err, ErrDeadlineExceeded), "should return ErrDeadlineExceeded, got: %v", err)
+}
+
+func TestWaitContainerErrors(t *testing.T) {
+	client, stop := startDiskUsageServer(t, &waitRuntime{})
+	defer stop()
+
+	_, err := client.WaitContainer(context.Background(), "missing")
+	assert.True(t, errors.Is(err, ErrContainerNotFound), "should return ErrContainerNotFound, got: %v", err)
+
+	_, err = client.WaitContainer(context.Background(), "created")
+	assert.True(t, errors.Is(err, ErrContainerNotRunning), "should return ErrContainerNotRunning, got: %v", err)
+}
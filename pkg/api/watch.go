@@ -0,0 +1,54 @@
+package api
+
+import (
+	"sort"
+
+	"github.com/golang/protobuf/proto"
+
+	pods "github.com/ernoaapa/eliot/pkg/api/services/pods/v1"
+)
+
+// PodEventType tells what happened to the pod
+type PodEventType string
+
+// Pod event types
+const (
+	PodAdded    PodEventType = "Added"
+	PodModified PodEventType = "Modified"
+	PodDeleted  PodEventType = "Deleted"
+)
+
+// PodEvent is single change in pod state
+type PodEvent struct {
+	Type PodEventType
+	Pod  *pods.Pod
+}
+
+// diffPods resolves the events between previous and current pods, keyed by pod name
+func diffPods(previous, current map[string]*pods.Pod) (events []PodEvent) {
+	for _, name := range sortedPodNames(current) {
+		pod := current[name]
+		old, exist := previous[name]
+		if !exist {
+			events = append(events, PodEvent{Type: PodAdded, Pod: pod})
+		} else if !proto.Equal(old, pod) {
+			events = append(events, PodEvent{Type: PodModified, Pod: pod})
+		}
+	}
+
+	for _, name := range sortedPodNames(previous) {
+		if _, exist := current[name]; !exist {
+			events = append(events, PodEvent{Type: PodDeleted, Pod: previous[name]})
+		}
+	}
+	return events
+}
+
+func sortedPodNames(pods map[string]*pods.Pod) []string {
+	names := []string{}
+	for name := range pods {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
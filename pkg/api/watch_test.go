@@ -0,0 +1,49 @@
+package api
+
+import (
+	"testing"
+
+	core "github.com/ernoaapa/eliot/pkg/api/core"
+	containers "github.com/ernoaapa/eliot/pkg/api/services/containers/v1"
+	pods "github.com/ernoaapa/eliot/pkg/api/services/pods/v1"
+	"github.com/stretchr/testify/assert"
+)
+
+func newPodWithState(name, state string) *pods.Pod {
+	return &pods.Pod{
+		Metadata: &core.ResourceMetadata{Name: name},
+		Status: &pods.PodStatus{
+			ContainerStatuses: []*containers.ContainerStatus{{Name: "foo", State: state}},
+		},
+	}
+}
+
+func TestDiffPods(t *testing.T) {
+	previous := map[string]*pods.Pod{
+		"unchanged": newPodWithState("unchanged", "running"),
+		"modified":  newPodWithState("modified", "created"),
+		"deleted":   newPodWithState("deleted", "running"),
+	}
+	current := map[string]*pods.Pod{
+		"unchanged": newPodWithState("unchanged", "running"),
+		"modified":  newPodWithState("modified", "running"),
+		"added":     newPodWithState("added", "created"),
+	}
+
+	events := diffPods(previous, current)
+	assert.Len(t, events, 3)
+	assert.Equal(t, PodAdded, events[0].Type)
+	assert.Equal(t, "added", events[0].Pod.Metadata.Name)
+	assert.Equal(t, PodModified, events[1].Type)
+	assert.Equal(t, "modified", events[1].Pod.Metadata.Name)
+	assert.Equal(t, PodDeleted, events[2].Type)
+	assert.Equal(t, "deleted", events[2].Pod.Metadata.Name)
+}
+
+func TestDiffPodsInitialListIsAdded(t *testing.T) {
+	events := diffPods(map[string]*pods.Pod{}, map[string]*pods.Pod{
+		"foo": newPodWithState("foo", "running"),
+	})
+	assert.Len(t, events, 1)
+	assert.Equal(t, PodAdded, events[0].Type)
+}
@@ -22,6 +22,9 @@ func (t *Debug) Stop() {
 	t.running = false
 }
 
+// Update is log Output implementation, the lines are printed when they change
+func (t *Debug) Update() {}
+
 // NewLine creates new terminal output line what you can change afterward
 func (t *Debug) NewLine() Line {
 	return &DebugLine{}
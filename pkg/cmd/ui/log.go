@@ -4,6 +4,7 @@ package ui
 type Output interface {
 	Start()
 	Stop()
+	Update()
 	NewLine() Line
 }
 
@@ -26,6 +27,11 @@ func Stop() {
 	output.Stop()
 }
 
+// Update renders the output lines right away, e.g. the final state before the command exits
+func Update() {
+	output.Update()
+}
+
 // NewLine creates new updateable output Line
 func NewLine() Line {
 	return output.NewLine()
@@ -31,14 +31,14 @@ func NewTerminal() *Terminal {
 
 // Start starts updating the terminal lines
 func (t *Terminal) Start() {
-	t.running = true
+	t.setRunning(true)
 	go func() {
 		for {
 			select {
 			case <-time.After(100 * time.Millisecond):
 				t.Update()
 			}
-			if !t.running {
+			if !t.isRunning() {
 				return
 			}
 		}
@@ -47,11 +47,25 @@ func (t *Terminal) Start() {
 
 // Stop updating the terminal lines
 func (t *Terminal) Stop() {
-	t.running = false
+	t.setRunning(false)
 	t.Update()
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
 	t.rows = []*TerminalLine{}
 }
 
+func (t *Terminal) setRunning(running bool) {
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+	t.running = running
+}
+
+func (t *Terminal) isRunning() bool {
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+	return t.running
+}
+
 // Update will re-render the output
 func (t *Terminal) Update() {
 	t.mtx.Lock()
@@ -42,16 +42,28 @@ var (
 
 // WithProgress display progress bar when line is in loading state
 func (r *TerminalLine) WithProgress(current, total int64) Line {
-	r.showProgress = true
-	r.current = current
-	r.total = total
-	r.Update()
+	r.set(func() {
+		r.showProgress = true
+		r.current = current
+		r.total = total
+	})
 	return r
 }
 
-// setText updates the text in the line
-func (r *TerminalLine) setText(a ...interface{}) {
-	r.Text = fmt.Sprint(a...)
+// setState updates the state and the text in the line
+func (r *TerminalLine) setState(state State, a ...interface{}) {
+	r.set(func() {
+		r.state = state
+		r.Text = fmt.Sprint(a...)
+	})
+}
+
+// set updates the line while holding the terminal lock, so that the lines can be updated
+// from multiple goroutines without the terminal rendering half updated line, and re-renders
+func (r *TerminalLine) set(update func()) {
+	r.terminal.mtx.Lock()
+	update()
+	r.terminal.mtx.Unlock()
 	r.Update()
 }
 
@@ -62,8 +74,7 @@ func (r *TerminalLine) Infof(format string, args ...interface{}) Line {
 
 // Info mark this line to be just blank info line
 func (r *TerminalLine) Info(a ...interface{}) Line {
-	r.state = BLANK
-	r.setText(a...)
+	r.setState(BLANK, a...)
 	return r
 }
 
@@ -74,8 +85,7 @@ func (r *TerminalLine) Loadingf(format string, args ...interface{}) Line {
 
 // Loading mark this line to be loading (displays loading indicator)
 func (r *TerminalLine) Loading(a ...interface{}) Line {
-	r.state = LOADING
-	r.setText(a...)
+	r.setState(LOADING, a...)
 	return r
 }
 
@@ -86,8 +96,7 @@ func (r *TerminalLine) Donef(format string, args ...interface{}) Line {
 
 // Done marks this line to be done and updates the text
 func (r *TerminalLine) Done(a ...interface{}) Line {
-	r.state = DONE
-	r.setText(a...)
+	r.setState(DONE, a...)
 	return r
 }
 
@@ -98,8 +107,7 @@ func (r *TerminalLine) Warnf(format string, args ...interface{}) Line {
 
 // Warn mark this line to be in warning with given message
 func (r *TerminalLine) Warn(a ...interface{}) Line {
-	r.state = WARN
-	r.setText(a...)
+	r.setState(WARN, a...)
 	return r
 }
 
@@ -110,8 +118,7 @@ func (r *TerminalLine) Errorf(format string, args ...interface{}) Line {
 
 // Error mark this line to be in error with given message
 func (r *TerminalLine) Error(a ...interface{}) Line {
-	r.state = ERROR
-	r.setText(a...)
+	r.setState(ERROR, a...)
 	return r
 }
 
@@ -124,16 +131,15 @@ func (r *TerminalLine) Fatalf(format string, args ...interface{}) {
 // Fatal mark this line to be in error with given message
 // Will exit(1) after rerendering the lines
 func (r *TerminalLine) Fatal(a ...interface{}) {
-	r.state = ERROR
-	r.setText(a...)
+	r.setState(ERROR, a...)
 	os.Exit(1)
 }
 
 func (r *TerminalLine) render() string {
 	switch r.state {
 	case LOADING:
-		if r.showProgress {
-			return pad.Left(spinner.Rotate(), 5, " ") + " " + r.Text + " " + string(progressBar.Render(70, r.current, r.total))
+		if r.showProgress && r.total > 0 {
+			return pad.Left(spinner.Rotate(), 5, " ") + " " + r.Text + " " + string(progressBar.Render(70, r.current, r.total)) + fmt.Sprintf(" %d%%", r.current*100/r.total)
 		}
 		return pad.Left(spinner.Rotate(), 5, " ") + " " + r.Text
 	case DONE:
@@ -24,9 +24,18 @@ type Endpoint struct {
 	URL  string `yaml:"url"`
 }
 
+// GetAddress return host:port part of endpoint URL
+// Only host:port is used for connecting, so possible scheme prefix (e.g. 'https://') is dropped
+func (e Endpoint) GetAddress() string {
+	if index := strings.Index(e.URL, "://"); index >= 0 {
+		return e.URL[index+len("://"):]
+	}
+	return e.URL
+}
+
 // GetHost return just hostname/ip of endpoint URL
 func (e Endpoint) GetHost() string {
-	parts := strings.SplitN(e.URL, ":", 2)
+	parts := strings.SplitN(e.GetAddress(), ":", 2)
 	return parts[0]
 }
 
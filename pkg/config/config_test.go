@@ -33,3 +33,14 @@ func TestSetValue(t *testing.T) {
 
 	assert.Equal(t, "foobar", config.Namespace)
 }
+
+func TestEndpointGetAddress(t *testing.T) {
+	assert.Equal(t, "localhost:5000", Endpoint{URL: "localhost:5000"}.GetAddress())
+	assert.Equal(t, "localhost:5000", Endpoint{URL: "https://localhost:5000"}.GetAddress())
+	assert.Equal(t, "192.168.1.2:5000", Endpoint{URL: "http://192.168.1.2:5000"}.GetAddress())
+}
+
+func TestEndpointGetHost(t *testing.T) {
+	assert.Equal(t, "localhost", Endpoint{URL: "localhost:5000"}.GetHost())
+	assert.Equal(t, "localhost", Endpoint{URL: "https://localhost:5000"}.GetHost())
+}
@@ -6,6 +6,7 @@ import (
 
 	"github.com/pkg/errors"
 
+	"github.com/ernoaapa/eliot/pkg/model"
 	"github.com/ernoaapa/eliot/pkg/runtime"
 	log "github.com/sirupsen/logrus"
 )
@@ -68,22 +69,59 @@ func (l *Lifecycle) checkAll() error {
 		}
 
 		for _, pod := range pods {
-			for _, status := range pod.Status.ContainerStatuses {
-				if status.State == "stopped" || status.State == "unknown" && pod.Spec.RestartPolicy == "always" {
-					log.Debugf("Detected [%s] container [%s] in namespace [%s] with 'always' restart policy", status.State, status.ContainerID, pod.Metadata.Name)
-					ioset, err := runtime.NewIOSet(fmt.Sprintf("%s.%s", pod.Metadata.Name, status.Name))
-					if err != nil {
-						return errors.Wrapf(err, "Error while creating container ioset, cannot run lifecycle controller")
-					}
-					status, err := l.client.StartContainer(namespace, status.ContainerID, *ioset)
-					if err != nil {
-						log.Warnf("Lifecycle controller failed to start container: %s", err)
-						continue
-					}
-					log.Debugf("Restarted container [%s] in namespace [%s]", status.ContainerID, pod.Metadata.Name)
-				}
+			if err := l.check(namespace, pod); err != nil {
+				return err
 			}
 		}
 	}
 	return nil
 }
+
+// check starts the pod containers which should be restarted based on the restart policy, unless the pod is cordoned.
+// The init containers run one at a time, the next one gets started when the previous completes,
+// and the containers get started only after all init containers have completed.
+func (l *Lifecycle) check(namespace string, pod model.Pod) error {
+	if pod.Status.Cordoned {
+		return nil
+	}
+
+	if pending := model.GetPendingInitContainer(pod); pending >= 0 {
+		status := pod.Status.InitContainerStatuses[pending]
+		policy := model.GetRestartPolicy(pod, status.Name)
+		// The first init container gets started when the pod is started
+		if (pending > 0 && isNeverStarted(status)) || model.ShouldRestart(policy, status) {
+			return l.start(namespace, pod, status, policy)
+		}
+		return nil
+	}
+
+	for _, status := range pod.Status.ContainerStatuses {
+		policy := model.GetRestartPolicy(pod, status.Name)
+		if (len(pod.Status.InitContainerStatuses) > 0 && isNeverStarted(status)) || model.ShouldRestart(policy, status) {
+			if err := l.start(namespace, pod, status, policy); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (l *Lifecycle) start(namespace string, pod model.Pod, status model.ContainerStatus, policy string) error {
+	log.Debugf("Detected [%s] container [%s] in namespace [%s] with '%s' restart policy", status.State, status.ContainerID, pod.Metadata.Name, policy)
+	ioset, err := runtime.NewIOSet(fmt.Sprintf("%s.%s", pod.Metadata.Name, status.Name))
+	if err != nil {
+		return errors.Wrapf(err, "Error while creating container ioset, cannot run lifecycle controller")
+	}
+	status, err = l.client.StartContainer(namespace, status.ContainerID, *ioset)
+	if err != nil {
+		log.Warnf("Lifecycle controller failed to start container: %s", err)
+		return nil
+	}
+	log.Debugf("Restarted container [%s] in namespace [%s]", status.ContainerID, pod.Metadata.Name)
+	return nil
+}
+
+// isNeverStarted return true if the container has been created but not started yet
+func isNeverStarted(status model.ContainerStatus) bool {
+	return status.StartedAt.IsZero() && status.State != "running"
+}
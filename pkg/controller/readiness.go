@@ -0,0 +1,177 @@
+package controller
+
+import (
+	"bytes"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ernoaapa/eliot/pkg/model"
+	"github.com/ernoaapa/eliot/pkg/runtime"
+	"github.com/rs/xid"
+	log "github.com/sirupsen/logrus"
+)
+
+// maxProbeOutput is how much of the failed exec probe output is included in the readiness message
+const maxProbeOutput = 256
+
+// Readiness is controller which runs the container readiness probes and stores the results to the runtime,
+// so that the container status tells when the container is actually ready
+type Readiness struct {
+	client   runtime.Client
+	interval time.Duration
+	timeout  time.Duration
+	serving  bool
+}
+
+// NewReadiness creates new Readiness controller instance
+func NewReadiness(client runtime.Client) *Readiness {
+	return &Readiness{
+		client:   client,
+		interval: 2 * time.Second,
+		timeout:  time.Second,
+	}
+}
+
+// Serve starts the controller to probe the running containers
+func (r *Readiness) Serve() {
+	log.Infof("Start readiness controller...")
+	r.serving = true
+
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		if !r.serving {
+			return
+		}
+		r.probeAll()
+	}
+}
+
+// Stop the readiness probing
+func (r *Readiness) Stop() {
+	log.Infof("Stop readiness controller...")
+	r.serving = false
+}
+
+// probeAll runs the readiness probes of all running containers in parallel and waits them to complete
+func (r *Readiness) probeAll() {
+	namespaces, err := r.client.GetNamespaces()
+	if err != nil {
+		log.Warnf("Readiness controller cannot probe containers, error while fetching namespaces: %s", err)
+		return
+	}
+
+	var wg sync.WaitGroup
+	for _, namespace := range namespaces {
+		pods, err := r.client.GetPods(namespace)
+		if err != nil {
+			log.Warnf("Readiness controller cannot probe containers, error while fetching pods: %s", err)
+			continue
+		}
+
+		for _, pod := range pods {
+			for i, container := range pod.Spec.Containers {
+				if container.ReadinessProbe == nil || i >= len(pod.Status.ContainerStatuses) {
+					continue
+				}
+				status := pod.Status.ContainerStatuses[i]
+				if status.State != "running" {
+					continue
+				}
+
+				wg.Add(1)
+				go func(namespace string, probe model.Probe, status model.ContainerStatus) {
+					defer wg.Done()
+					r.check(namespace, probe, status)
+				}(namespace, *container.ReadinessProbe, status)
+			}
+		}
+	}
+	wg.Wait()
+}
+
+// check runs the probe and stores the result for the current container run
+func (r *Readiness) check(namespace string, probe model.Probe, status model.ContainerStatus) {
+	result := r.probe(namespace, status.ContainerID, probe)
+	result.StartedAt = status.StartedAt
+
+	if result.Ready != status.Ready {
+		if result.Ready {
+			log.Debugf("Container [%s] in namespace [%s] is ready", status.ContainerID, namespace)
+		} else {
+			log.Debugf("Container [%s] in namespace [%s] is not ready: %s", status.ContainerID, namespace, result.Message)
+		}
+	}
+	r.client.SetContainerReadiness(namespace, status.ContainerID, result)
+}
+
+func (r *Readiness) probe(namespace, id string, probe model.Probe) runtime.ProbeResult {
+	switch {
+	case probe.HTTPGet != nil:
+		return r.probeHTTP(namespace, id, *probe.HTTPGet)
+	case probe.Exec != nil:
+		return r.probeExec(namespace, id, *probe.Exec)
+	}
+	return runtime.ProbeResult{Message: "Readiness probe has neither HTTP GET nor command defined"}
+}
+
+// probeHTTP sends GET request to the container port, 2xx and 3xx status codes mean the container is ready
+func (r *Readiness) probeHTTP(namespace, id string, probe model.HTTPGetProbe) runtime.ProbeResult {
+	client := &http.Client{
+		Timeout: r.timeout,
+		Transport: &http.Transport{
+			DisableKeepAlives: true,
+			Dial: func(network, addr string) (net.Conn, error) {
+				return r.client.DialContainer(namespace, id, probe.Port)
+			},
+		},
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+
+	path := probe.Path
+	if !strings.HasPrefix(path, "/") {
+		path = "/" + path
+	}
+	resp, err := client.Get(fmt.Sprintf("http://localhost:%d%s", probe.Port, path))
+	if err != nil {
+		return runtime.ProbeResult{Message: fmt.Sprintf("HTTP probe GET %s to port %d failed: %s", path, probe.Port, err)}
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 400 {
+		return runtime.ProbeResult{Message: fmt.Sprintf("HTTP probe GET %s to port %d returned status %d", path, probe.Port, resp.StatusCode)}
+	}
+	return runtime.ProbeResult{Ready: true}
+}
+
+// probeExec runs the command in the container, zero exit code means the container is ready
+func (r *Readiness) probeExec(namespace, id string, probe model.ExecProbe) runtime.ProbeResult {
+	stdout, stderr := &bytes.Buffer{}, &bytes.Buffer{}
+	exitCode, err := r.client.Exec(namespace, id, "readiness-"+xid.New().String(), probe.Command, false, runtime.AttachIO{
+		Stdin:  &bytes.Buffer{},
+		Stdout: stdout,
+		Stderr: stderr,
+	})
+	if err != nil {
+		return runtime.ProbeResult{Message: fmt.Sprintf("Exec probe [%s] failed: %s", strings.Join(probe.Command, " "), err)}
+	}
+
+	if exitCode != 0 {
+		message := fmt.Sprintf("Exec probe [%s] exited with code %d", strings.Join(probe.Command, " "), exitCode)
+		if out := strings.TrimSpace(stdout.String() + stderr.String()); out != "" {
+			if len(out) > maxProbeOutput {
+				out = out[:maxProbeOutput] + "..."
+			}
+			message = fmt.Sprintf("%s: %s", message, out)
+		}
+		return runtime.ProbeResult{Message: message}
+	}
+	return runtime.ProbeResult{Ready: true}
+}
@@ -0,0 +1,64 @@
+package controller
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/ernoaapa/eliot/pkg/model"
+	"github.com/ernoaapa/eliot/pkg/runtime"
+	"github.com/stretchr/testify/assert"
+)
+
+// probeRuntime dials the test server instead of the container port and returns the exit code for the exec
+type probeRuntime struct {
+	runtime.Client
+	addr     string
+	exitCode int
+	output   string
+	results  map[string]runtime.ProbeResult
+}
+
+func (r *probeRuntime) DialContainer(namespace, name string, port int) (net.Conn, error) {
+	return net.Dial("tcp", r.addr)
+}
+
+func (r *probeRuntime) Exec(namespace, name, id string, args []string, tty bool, io runtime.AttachIO) (int, error) {
+	fmt.Fprint(io.Stderr, r.output)
+	return r.exitCode, nil
+}
+
+func (r *probeRuntime) SetContainerReadiness(namespace, name string, result runtime.ProbeResult) {
+	r.results[name] = result
+}
+
+func TestProbeHTTP(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.URL.Path != "/healthz" {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	defer server.Close()
+
+	readiness := NewReadiness(&probeRuntime{addr: strings.TrimPrefix(server.URL, "http://")})
+
+	assert.True(t, readiness.probeHTTP("eliot", "1", model.HTTPGetProbe{Path: "/healthz", Port: 8080}).Ready)
+
+	result := readiness.probeHTTP("eliot", "1", model.HTTPGetProbe{Path: "starting", Port: 8080})
+	assert.False(t, result.Ready)
+	assert.Equal(t, "HTTP probe GET /starting to port 8080 returned status 503", result.Message)
+}
+
+func TestCheckStoresExecProbeFailure(t *testing.T) {
+	client := &probeRuntime{exitCode: 1, output: "database not reachable\n", results: map[string]runtime.ProbeResult{}}
+	status := model.ContainerStatus{ContainerID: "1", State: "running"}
+
+	NewReadiness(client).check("eliot", model.Probe{Exec: &model.ExecProbe{Command: []string{"pg_isready"}}}, status)
+
+	assert.False(t, client.results["1"].Ready)
+	assert.Equal(t, "Exec probe [pg_isready] exited with code 1: database not reachable", client.results["1"].Message)
+	assert.Equal(t, status.StartedAt, client.results["1"].StartedAt)
+}
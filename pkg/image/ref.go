@@ -0,0 +1,131 @@
+package image
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	digest "github.com/opencontainers/go-digest"
+)
+
+// DefaultTag is the tag used when the reference doesn't have tag nor digest
+const DefaultTag = "latest"
+
+var (
+	hostComponentPattern = regexp.MustCompile(`^[a-zA-Z0-9]([a-zA-Z0-9-]*[a-zA-Z0-9])?$`)
+	pathComponentPattern = regexp.MustCompile(`^[a-z0-9]+((\.|_|__|-+)[a-z0-9]+)*$`)
+	tagPattern           = regexp.MustCompile(`^[\w][\w.-]{0,127}$`)
+	portPattern          = regexp.MustCompile(`^[0-9]+$`)
+)
+
+// Ref is parsed image reference, e.g. docker.io/library/nginx:1.13@sha256:...
+type Ref struct {
+	// Registry is the registry host and optional port, e.g. docker.io or localhost:5000
+	Registry string
+	// Repository is the image path in the registry, e.g. library/nginx
+	Repository string
+	// Tag is empty if not given in the reference
+	Tag string
+	// Digest is empty if not given in the reference
+	Digest digest.Digest
+}
+
+// ParseRef parses and validates fully qualified image reference in format REGISTRY/REPOSITORY[:TAG][@DIGEST].
+// The registry is required, use utils.ExpandToFQIN to expand short names like nginx:latest.
+func ParseRef(s string) (Ref, error) {
+	if s == "" {
+		return Ref{}, fmt.Errorf("Invalid image reference, reference cannot be empty")
+	}
+	if strings.ContainsAny(s, " \t\n") {
+		return Ref{}, fmt.Errorf("Invalid image reference [%s], reference cannot contain whitespace", s)
+	}
+
+	ref := Ref{}
+	name := s
+	if i := strings.Index(name, "@"); i >= 0 {
+		dgst, err := digest.Parse(name[i+1:])
+		if err != nil {
+			return Ref{}, fmt.Errorf("Invalid image reference [%s], digest [%s] is invalid: %s", s, name[i+1:], err)
+		}
+		ref.Digest = dgst
+		name = name[:i]
+	}
+
+	// Tag is after the last colon, unless the colon is in the registry port
+	if i := strings.LastIndex(name, ":"); i > strings.LastIndex(name, "/") {
+		ref.Tag = name[i+1:]
+		name = name[:i]
+		if !tagPattern.MatchString(ref.Tag) {
+			return Ref{}, fmt.Errorf("Invalid image reference [%s], tag [%s] must contain only letters, digits, '_', '.' or '-', start with letter, digit or '_' and be at most 128 characters", s, ref.Tag)
+		}
+	}
+
+	parts := strings.SplitN(name, "/", 2)
+	if len(parts) < 2 || !isRegistry(parts[0]) {
+		return Ref{}, fmt.Errorf("Invalid image reference [%s], must start with registry host, e.g. docker.io/library/nginx:latest", s)
+	}
+	ref.Registry = parts[0]
+	if err := validateRegistry(ref.Registry); err != nil {
+		return Ref{}, fmt.Errorf("Invalid image reference [%s], %s", s, err)
+	}
+
+	ref.Repository = parts[1]
+	for _, component := range strings.Split(ref.Repository, "/") {
+		if !pathComponentPattern.MatchString(component) {
+			return Ref{}, fmt.Errorf("Invalid image reference [%s], repository path component [%s] must contain only lowercase letters, digits and separators '.', '_', '__' or '-' between them", s, component)
+		}
+	}
+	if len(ref.Registry)+1+len(ref.Repository) > 255 {
+		return Ref{}, fmt.Errorf("Invalid image reference [%s], name must be at most 255 characters", s)
+	}
+	return ref, nil
+}
+
+// isRegistry return true if the first reference component looks like registry host, like docker does
+func isRegistry(component string) bool {
+	return strings.ContainsAny(component, ".:") || component == "localhost"
+}
+
+func validateRegistry(registry string) error {
+	host := registry
+	if i := strings.LastIndex(registry, ":"); i >= 0 {
+		host = registry[:i]
+		if port := registry[i+1:]; !portPattern.MatchString(port) {
+			return fmt.Errorf("registry [%s] port [%s] must be number", registry, port)
+		}
+	}
+	for _, component := range strings.Split(host, ".") {
+		if !hostComponentPattern.MatchString(component) {
+			return fmt.Errorf("registry [%s] must be valid hostname", registry)
+		}
+	}
+	return nil
+}
+
+// Name returns the registry and repository, e.g. docker.io/library/nginx
+func (r Ref) Name() string {
+	return r.Registry + "/" + r.Repository
+}
+
+// IsPinned return true if the reference has digest, so it always points to the same image
+func (r Ref) IsPinned() bool {
+	return r.Digest != ""
+}
+
+// WithDigest returns copy of the reference pinned to the digest, the tag is kept for readability
+func (r Ref) WithDigest(dgst digest.Digest) Ref {
+	r.Digest = dgst
+	return r
+}
+
+// String returns the reference in format REGISTRY/REPOSITORY[:TAG][@DIGEST]
+func (r Ref) String() string {
+	s := r.Name()
+	if r.Tag != "" {
+		s += ":" + r.Tag
+	}
+	if r.Digest != "" {
+		s += "@" + r.Digest.String()
+	}
+	return s
+}
@@ -0,0 +1,57 @@
+package image
+
+import (
+	"testing"
+
+	digest "github.com/opencontainers/go-digest"
+	"github.com/stretchr/testify/assert"
+)
+
+const testDigest = "sha256:7d6a3c8f70470b6ba1d3d8b6b3c9c7cb3c0d4b3f7d8f4f0b1be0b5cbe0c5a1a2"
+
+func TestParseRef(t *testing.T) {
+	ref, err := ParseRef("docker.io/library/nginx:1.13")
+	assert.NoError(t, err)
+	assert.Equal(t, Ref{Registry: "docker.io", Repository: "library/nginx", Tag: "1.13"}, ref)
+	assert.False(t, ref.IsPinned())
+
+	ref, err = ParseRef("localhost:5000/my-app@" + testDigest)
+	assert.NoError(t, err)
+	assert.Equal(t, "localhost:5000", ref.Registry)
+	assert.Equal(t, "my-app", ref.Repository)
+	assert.Equal(t, "", ref.Tag)
+	assert.Equal(t, digest.Digest(testDigest), ref.Digest)
+	assert.True(t, ref.IsPinned())
+
+	ref, err = ParseRef("docker.io/library/nginx:latest@" + testDigest)
+	assert.NoError(t, err)
+	assert.Equal(t, "latest", ref.Tag)
+	assert.Equal(t, "docker.io/library/nginx:latest@"+testDigest, ref.String())
+}
+
+func TestParseRefRejectsMalformed(t *testing.T) {
+	for input, message := range map[string]string{
+		"":                                "cannot be empty",
+		"nginx:latest":                    "must start with registry host",
+		"docker.io/library/Nginx":         "repository path component [Nginx]",
+		"docker.io/library/nginx:-bad":    "tag [-bad]",
+		"docker.io/library/nginx@sha256:": "digest [sha256:] is invalid",
+		"docker io/library/nginx":         "cannot contain whitespace",
+		"my_registry.io/nginx":            "must be valid hostname",
+		"localhost:port/nginx":            "port [port] must be number",
+	} {
+		_, err := ParseRef(input)
+		if assert.Error(t, err, input) {
+			assert.Contains(t, err.Error(), message)
+		}
+	}
+}
+
+func TestWithDigest(t *testing.T) {
+	ref, err := ParseRef("docker.io/library/nginx:1.13")
+	assert.NoError(t, err)
+
+	pinned := ref.WithDigest(testDigest)
+	assert.Equal(t, "docker.io/library/nginx:1.13@"+testDigest, pinned.String())
+	assert.Equal(t, "docker.io/library/nginx:1.13", ref.String(), "should not modify the original")
+}
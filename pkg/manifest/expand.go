@@ -0,0 +1,113 @@
+package manifest
+
+import (
+	"fmt"
+	"os"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+
+	pods "github.com/ernoaapa/eliot/pkg/api/services/pods/v1"
+)
+
+// variablePattern matches ${VAR} and ${VAR:-default} references, and the escaped $${VAR} which is kept as ${VAR}
+var variablePattern = regexp.MustCompile(`\$(\$?)\{([A-Za-z_][A-Za-z0-9_]*)(:-[^}]*)?\}`)
+
+// ExpandPod substitutes the ${VAR} references in the pod metadata and spec string fields, e.g. image tags,
+// env values and labels, with the values from the vars. If the variable is not defined or is empty,
+// ${VAR:-default} gets the default value. Returns error if some variable is not defined and has no default.
+// Use $${VAR} to write literal ${VAR}, other $ characters, e.g. $VAR in the container args, are kept as is.
+func ExpandPod(pod *pods.Pod, vars map[string]string) error {
+	if err := expandValue(reflect.ValueOf(pod.Metadata), "metadata", vars); err != nil {
+		return err
+	}
+	return expandValue(reflect.ValueOf(pod.Spec), "spec", vars)
+}
+
+// EnvironmentVars returns the host environment variables
+func EnvironmentVars() map[string]string {
+	result := map[string]string{}
+	for _, env := range os.Environ() {
+		if parts := strings.SplitN(env, "=", 2); len(parts) == 2 {
+			result[parts[0]] = parts[1]
+		}
+	}
+	return result
+}
+
+func expandValue(v reflect.Value, path string, vars map[string]string) error {
+	switch v.Kind() {
+	case reflect.Ptr:
+		if v.IsNil() {
+			return nil
+		}
+		return expandValue(v.Elem(), path, vars)
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			field := v.Type().Field(i)
+			if field.PkgPath != "" || strings.HasPrefix(field.Name, "XXX_") {
+				continue
+			}
+			if err := expandValue(v.Field(i), path+"."+getFieldName(field), vars); err != nil {
+				return err
+			}
+		}
+	case reflect.Slice:
+		for i := 0; i < v.Len(); i++ {
+			if err := expandValue(v.Index(i), fmt.Sprintf("%s[%d]", path, i), vars); err != nil {
+				return err
+			}
+		}
+	case reflect.Map:
+		if v.Type().Elem().Kind() != reflect.String {
+			return nil
+		}
+		keys := v.MapKeys()
+		sort.Slice(keys, func(i, j int) bool { return keys[i].String() < keys[j].String() })
+		for _, key := range keys {
+			expanded, err := expandString(v.MapIndex(key).String(), fmt.Sprintf("%s[%s]", path, key), vars)
+			if err != nil {
+				return err
+			}
+			v.SetMapIndex(key, reflect.ValueOf(expanded))
+		}
+	case reflect.String:
+		expanded, err := expandString(v.String(), path, vars)
+		if err != nil {
+			return err
+		}
+		v.SetString(expanded)
+	}
+	return nil
+}
+
+func expandString(value, path string, vars map[string]string) (string, error) {
+	var err error
+	result := variablePattern.ReplaceAllStringFunc(value, func(reference string) string {
+		match := variablePattern.FindStringSubmatch(reference)
+		escaped, name, defaultValue := match[1] != "", match[2], match[3]
+		if escaped {
+			return reference[1:]
+		}
+		if value := vars[name]; value != "" {
+			return value
+		}
+		if defaultValue != "" {
+			return strings.TrimPrefix(defaultValue, ":-")
+		}
+		if _, ok := vars[name]; !ok && err == nil {
+			err = fmt.Errorf("Undefined variable [%s] in [%s], define it or give default value with ${%s:-default}", name, path, name)
+		}
+		return ""
+	})
+	return result, err
+}
+
+// getFieldName return the field name in the manifest format, e.g. restartPolicy
+func getFieldName(field reflect.StructField) string {
+	if name := strings.Split(field.Tag.Get("json"), ",")[0]; name != "" && name != "-" {
+		return name
+	}
+	return field.Name
+}
@@ -0,0 +1,72 @@
+package manifest
+
+import (
+	"strings"
+	"testing"
+
+	core "github.com/ernoaapa/eliot/pkg/api/core"
+	containers "github.com/ernoaapa/eliot/pkg/api/services/containers/v1"
+	pods "github.com/ernoaapa/eliot/pkg/api/services/pods/v1"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExpandPod(t *testing.T) {
+	pod := &pods.Pod{
+		Metadata: &core.ResourceMetadata{
+			Name:   "app-${DEVICE_ID}",
+			Labels: map[string]string{"device": "${DEVICE_ID}"},
+		},
+		Spec: &pods.PodSpec{
+			Containers: []*containers.Container{
+				{
+					Name:  "app",
+					Image: "docker.io/eaapa/app:${VERSION:-latest}",
+					Args:  []string{"sh", "-c", "echo $HOME $${LITERAL}"},
+					Env:   []string{"HOST=${HOSTNAME}", "EMPTY=${EMPTY}"},
+				},
+			},
+		},
+	}
+
+	err := ExpandPod(pod, map[string]string{"DEVICE_ID": "rpi-1", "HOSTNAME": "node-1", "EMPTY": ""})
+	assert.NoError(t, err)
+	assert.Equal(t, "app-rpi-1", pod.Metadata.Name)
+	assert.Equal(t, map[string]string{"device": "rpi-1"}, pod.Metadata.Labels)
+	assert.Equal(t, "docker.io/eaapa/app:latest", pod.Spec.Containers[0].Image, "should use the default value")
+	assert.Equal(t, []string{"sh", "-c", "echo $HOME ${LITERAL}"}, pod.Spec.Containers[0].Args, "should keep $VAR and unescape $${VAR}")
+	assert.Equal(t, []string{"HOST=node-1", "EMPTY="}, pod.Spec.Containers[0].Env)
+}
+
+func TestExpandPodUndefinedVariable(t *testing.T) {
+	pod := &pods.Pod{
+		Metadata: &core.ResourceMetadata{Name: "foo"},
+		Spec: &pods.PodSpec{
+			Containers: []*containers.Container{{Name: "foo", Image: "app:${VERSION}"}},
+		},
+	}
+
+	err := ExpandPod(pod, map[string]string{})
+	assert.EqualError(t, err, "Undefined variable [VERSION] in [spec.containers[0].image], define it or give default value with ${VERSION:-default}")
+}
+
+func TestLoadPodsWithVars(t *testing.T) {
+	manifest := `
+metadata:
+  name: foo
+spec:
+  containers:
+    - name: foo
+      image: app:${VERSION}
+`
+	pod, err := LoadPod(strings.NewReader(manifest))
+	assert.NoError(t, err)
+	assert.Equal(t, "app:${VERSION}", pod.Spec.Containers[0].Image, "should not expand by default")
+
+	pod, err = LoadPod(strings.NewReader(manifest), WithVars(map[string]string{"VERSION": "1.2.3"}))
+	assert.NoError(t, err)
+	assert.Equal(t, "app:1.2.3", pod.Spec.Containers[0].Image)
+
+	pod, err = LoadPod(strings.NewReader(manifest), WithVars(map[string]string{"VERSION": "1.2.3"}), WithEnvironmentVars())
+	assert.NoError(t, err)
+	assert.Equal(t, "app:1.2.3", pod.Spec.Containers[0].Image, "vars should take precedence over the environment")
+}
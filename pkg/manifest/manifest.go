@@ -0,0 +1,347 @@
+// Package manifest decodes Pod manifest files written in YAML or JSON to the API model
+package manifest
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"regexp"
+	"strconv"
+	"strings"
+
+	core "github.com/ernoaapa/eliot/pkg/api/core"
+	pods "github.com/ernoaapa/eliot/pkg/api/services/pods/v1"
+	"github.com/ghodss/yaml"
+	"github.com/pkg/errors"
+)
+
+// DefaultRestartPolicy is the restart policy what each pod get if there is no spec.restartPolicy
+const DefaultRestartPolicy = "always"
+
+// LoadOpts is option for loading the manifest
+type LoadOpts func(options *loadOptions)
+
+type loadOptions struct {
+	// vars are the variables for the ${VAR} references, nil disables the expansion
+	vars map[string]string
+}
+
+// WithVars substitutes the ${VAR} references in the pods with the vars, see ExpandPod.
+// Can be combined with WithEnvironmentVars, the vars take precedence over the host environment.
+func WithVars(vars map[string]string) LoadOpts {
+	return func(options *loadOptions) {
+		if options.vars == nil {
+			options.vars = map[string]string{}
+		}
+		for name, value := range vars {
+			options.vars[name] = value
+		}
+	}
+}
+
+// WithEnvironmentVars substitutes the ${VAR} references in the pods with the host environment variables, see ExpandPod
+func WithEnvironmentVars() LoadOpts {
+	return func(options *loadOptions) {
+		vars := EnvironmentVars()
+		for name, value := range options.vars {
+			vars[name] = value
+		}
+		options.vars = vars
+	}
+}
+
+// LoadPod reads manifest which must contain exactly one pod
+func LoadPod(r io.Reader, opts ...LoadOpts) (*pods.Pod, error) {
+	result, err := LoadPods(r, opts...)
+	if err != nil {
+		return nil, err
+	}
+	if len(result) != 1 {
+		return nil, fmt.Errorf("Manifest must contain exactly one pod, but found %d", len(result))
+	}
+	return result[0], nil
+}
+
+// LoadPods reads all pods from the manifest.
+// The manifest can be YAML with multiple documents separated with '---', JSON, or list of pods in either format.
+// Field names can be given in the API format (restartPolicy) or in snake or kebab case (restart_policy, restart-policy).
+// Returns ParseError with the line number if the manifest is invalid.
+// By default the ${VAR} references are kept as is, use WithVars or WithEnvironmentVars to substitute them.
+func LoadPods(r io.Reader, opts ...LoadOpts) ([]*pods.Pod, error) {
+	options := &loadOptions{}
+	for _, opt := range opts {
+		opt(options)
+	}
+
+	data, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Failed to read manifest")
+	}
+
+	result := []*pods.Pod{}
+	for _, doc := range splitDocuments(data) {
+		decoded, err := decodeDocument(doc.data)
+		if err != nil {
+			return nil, newParseError(doc, err)
+		}
+		result = append(result, decoded...)
+	}
+
+	for _, pod := range result {
+		if options.vars != nil {
+			if err := ExpandPod(pod, options.vars); err != nil {
+				return nil, errors.Wrapf(err, "Failed to expand pod [%s] variables", pod.GetMetadata().GetName())
+			}
+		}
+		Default(pod)
+	}
+	return result, nil
+}
+
+// Default set default values to the pod, e.g. the namespace and restart policy
+func Default(pod *pods.Pod) *pods.Pod {
+	if pod.Metadata == nil {
+		pod.Metadata = &core.ResourceMetadata{}
+	}
+	if pod.Spec == nil {
+		pod.Spec = &pods.PodSpec{}
+	}
+	if pod.Spec.RestartPolicy == "" {
+		pod.Spec.RestartPolicy = DefaultRestartPolicy
+	}
+	return pods.Default(pod)
+}
+
+// document is single YAML document in the manifest
+type document struct {
+	// line is the manifest line number where the document starts
+	line int
+	data []byte
+}
+
+var documentSeparator = regexp.MustCompile(`^---\s*$`)
+
+func splitDocuments(data []byte) (result []document) {
+	current := document{line: 1}
+	for i, line := range bytes.SplitAfter(data, []byte("\n")) {
+		if documentSeparator.Match(bytes.TrimRight(line, "\r\n")) {
+			result = appendDocument(result, current)
+			current = document{line: i + 2}
+			continue
+		}
+		current.data = append(current.data, line...)
+	}
+	return appendDocument(result, current)
+}
+
+func appendDocument(documents []document, doc document) []document {
+	for _, line := range strings.Split(string(doc.data), "\n") {
+		line = strings.TrimSpace(line)
+		if line != "" && !strings.HasPrefix(line, "#") {
+			return append(documents, doc)
+		}
+	}
+	return documents
+}
+
+func decodeDocument(data []byte) ([]*pods.Pod, error) {
+	jsonData, err := yaml.YAMLToJSON(data)
+	if err != nil {
+		return nil, err
+	}
+
+	var raw interface{}
+	if err := json.Unmarshal(jsonData, &raw); err != nil {
+		return nil, err
+	}
+
+	items, ok := raw.([]interface{})
+	if !ok {
+		items = []interface{}{raw}
+	}
+
+	result := []*pods.Pod{}
+	for _, item := range items {
+		if _, ok := item.(map[string]interface{}); !ok {
+			return nil, fmt.Errorf("Pod must be an object, but found %s", describeValue(item))
+		}
+
+		normalized, err := json.Marshal(normalizeKeys(item))
+		if err != nil {
+			return nil, err
+		}
+
+		pod := &pods.Pod{}
+		decoder := json.NewDecoder(bytes.NewReader(normalized))
+		decoder.DisallowUnknownFields()
+		if err := decoder.Decode(pod); err != nil {
+			return nil, err
+		}
+		result = append(result, pod)
+	}
+	return result, nil
+}
+
+// mapFields are the fields which keys are user data and must not be normalized
+var mapFields = map[string]bool{
+	"labels": true,
+}
+
+// aliases maps commonly used field names to the API field names
+var aliases = map[string]string{
+	"workdir":     "workingDir",
+	"command":     "args",
+	"environment": "env",
+}
+
+func normalizeKeys(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		result := make(map[string]interface{}, len(v))
+		for key, child := range v {
+			name := normalizeKey(key)
+			if mapFields[name] {
+				result[name] = child
+			} else {
+				result[name] = normalizeKeys(child)
+			}
+		}
+		return result
+	case []interface{}:
+		result := make([]interface{}, len(v))
+		for i, child := range v {
+			result[i] = normalizeKeys(child)
+		}
+		return result
+	default:
+		return value
+	}
+}
+
+// normalizeKey converts snake_case and kebab-case field name to the API camelCase format
+func normalizeKey(key string) string {
+	if alias, ok := aliases[strings.ToLower(key)]; ok {
+		return alias
+	}
+
+	parts := strings.FieldsFunc(key, func(r rune) bool {
+		return r == '_' || r == '-'
+	})
+	if len(parts) == 0 {
+		return key
+	}
+
+	result := strings.ToLower(parts[0][:1]) + parts[0][1:]
+	for _, part := range parts[1:] {
+		result += strings.ToUpper(part[:1]) + part[1:]
+	}
+	return result
+}
+
+func describeValue(value interface{}) string {
+	switch value.(type) {
+	case nil:
+		return "empty value"
+	case []interface{}:
+		return "a list"
+	case string:
+		return "a string"
+	default:
+		return fmt.Sprintf("%T", value)
+	}
+}
+
+// ParseError is returned when the manifest cannot be parsed
+type ParseError struct {
+	// Line is the manifest line number where the problem is, zero if unknown
+	Line int
+	Err  error
+}
+
+func (e *ParseError) Error() string {
+	if e.Line == 0 {
+		return fmt.Sprintf("Failed to parse manifest: %s", e.Err)
+	}
+	return fmt.Sprintf("Failed to parse manifest at line %d: %s", e.Line, e.Err)
+}
+
+var (
+	yamlLinePattern     = regexp.MustCompile(`^(?:error converting YAML to JSON: )?yaml: line (\d+): `)
+	unknownFieldPattern = regexp.MustCompile(`unknown field "([^"]+)"`)
+)
+
+// newParseError resolves the manifest line number where the parsing failed.
+// YAML syntax errors have the line number in the message; for unknown fields and invalid
+// values, it's the first line in the document which defines the field.
+func newParseError(doc document, err error) *ParseError {
+	message := err.Error()
+	if match := yamlLinePattern.FindStringSubmatch(message); match != nil {
+		line, _ := strconv.Atoi(match[1])
+		return &ParseError{
+			Line: doc.line + line - 1,
+			Err:  errors.New(yamlLinePattern.ReplaceAllString(message, "")),
+		}
+	}
+
+	field := ""
+	switch e := err.(type) {
+	case *json.UnmarshalTypeError:
+		if e.Field != "" {
+			parts := strings.Split(e.Field, ".")
+			field = parts[len(parts)-1]
+		}
+		err = fmt.Errorf("Invalid value %s for field [%s], must be %s", e.Value, e.Field, e.Type)
+	default:
+		if match := unknownFieldPattern.FindStringSubmatch(message); match != nil {
+			field = match[1]
+			err = fmt.Errorf("Unknown field [%s]", field)
+		}
+	}
+
+	if line := findFieldLine(doc.data, field); line > 0 {
+		return &ParseError{Line: doc.line + line - 1, Err: err}
+	}
+	return &ParseError{Err: err}
+}
+
+// findFieldLine return the first line in the document which defines the field, or zero if not found
+func findFieldLine(data []byte, field string) int {
+	if field == "" {
+		return 0
+	}
+
+	candidates := []string{field}
+	for alias, name := range aliases {
+		if name == field {
+			candidates = append(candidates, alias)
+		}
+	}
+
+	patterns := []*regexp.Regexp{}
+	for _, candidate := range candidates {
+		patterns = append(patterns, regexp.MustCompile(`(?i)(^|[\s{,-])"?`+fieldPattern(candidate)+`"?\s*:`))
+	}
+
+	for i, line := range strings.Split(string(data), "\n") {
+		for _, pattern := range patterns {
+			if pattern.MatchString(line) {
+				return i + 1
+			}
+		}
+	}
+	return 0
+}
+
+// fieldPattern matches the field name in any of the supported formats, e.g. restartPolicy or restart_policy
+func fieldPattern(field string) string {
+	pattern := ""
+	for i, r := range field {
+		if i > 0 && r >= 'A' && r <= 'Z' {
+			pattern += "[_-]?"
+		}
+		pattern += regexp.QuoteMeta(string(r))
+	}
+	return pattern
+}
@@ -0,0 +1,126 @@
+package manifest
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoadPod(t *testing.T) {
+	pod, err := LoadPod(strings.NewReader(`
+metadata:
+  name: foo
+  labels:
+    app_name: foo
+spec:
+  host_network: true
+  containers:
+    - name: foo-1
+      image: docker.io/library/hello-world:latest
+      working-dir: /app
+      command: ["echo", "hello"]
+`))
+
+	assert.NoError(t, err)
+	assert.Equal(t, "foo", pod.Metadata.Name)
+	assert.Equal(t, "eliot", pod.Metadata.Namespace, "should default the namespace")
+	assert.Equal(t, map[string]string{"app_name": "foo"}, pod.Metadata.Labels, "should not normalize label keys")
+	assert.Equal(t, "always", pod.Spec.RestartPolicy, "should default the restart policy")
+	assert.True(t, pod.Spec.HostNetwork)
+	assert.Equal(t, "/app", pod.Spec.Containers[0].WorkingDir)
+	assert.Equal(t, []string{"echo", "hello"}, pod.Spec.Containers[0].Args)
+}
+
+func TestLoadPodRequiresSinglePod(t *testing.T) {
+	_, err := LoadPod(strings.NewReader(`
+metadata:
+  name: foo
+---
+metadata:
+  name: bar
+`))
+	assert.Error(t, err)
+}
+
+func TestLoadPods(t *testing.T) {
+	result, err := LoadPods(strings.NewReader(`# first pod
+metadata:
+  name: foo
+spec:
+  restartPolicy: onfailure
+---
+- metadata:
+    name: bar
+- metadata:
+    name: baz
+---
+`))
+
+	assert.NoError(t, err)
+	assert.Len(t, result, 3)
+	assert.Equal(t, "foo", result[0].Metadata.Name)
+	assert.Equal(t, "onfailure", result[0].Spec.RestartPolicy)
+	assert.Equal(t, "baz", result[2].Metadata.Name)
+}
+
+func TestLoadPodsJSON(t *testing.T) {
+	result, err := LoadPods(strings.NewReader(`[
+  {"metadata": {"name": "foo"}, "spec": {"containers": [{"name": "foo", "image": "alpine"}]}},
+  {"metadata": {"name": "bar"}}
+]`))
+
+	assert.NoError(t, err)
+	assert.Len(t, result, 2)
+	assert.Equal(t, "alpine", result[0].Spec.Containers[0].Image)
+}
+
+func TestLoadPodsSyntaxErrorLine(t *testing.T) {
+	_, err := LoadPods(strings.NewReader(`metadata:
+  name: foo
+---
+metadata:
+  name: bar
+spec:
+  containers: [foo
+`))
+
+	parseErr, ok := err.(*ParseError)
+	assert.True(t, ok, "should return ParseError")
+	assert.Equal(t, 7, parseErr.Line)
+}
+
+func TestLoadPodsUnknownFieldLine(t *testing.T) {
+	_, err := LoadPods(strings.NewReader(`metadata:
+  name: foo
+spec:
+  containers:
+    - name: foo
+      imag: alpine
+`))
+
+	parseErr, ok := err.(*ParseError)
+	assert.True(t, ok, "should return ParseError")
+	assert.Equal(t, 6, parseErr.Line)
+	assert.Equal(t, "Failed to parse manifest at line 6: Unknown field [imag]", err.Error())
+}
+
+func TestLoadPodsInvalidValueLine(t *testing.T) {
+	_, err := LoadPods(strings.NewReader(`metadata:
+  name: foo
+spec:
+  host-network: "yes"
+`))
+
+	parseErr, ok := err.(*ParseError)
+	assert.True(t, ok, "should return ParseError")
+	assert.Equal(t, 4, parseErr.Line)
+}
+
+func TestNormalizeKey(t *testing.T) {
+	assert.Equal(t, "restartPolicy", normalizeKey("restartPolicy"))
+	assert.Equal(t, "restartPolicy", normalizeKey("restart_policy"))
+	assert.Equal(t, "restartPolicy", normalizeKey("restart-policy"))
+	assert.Equal(t, "hostNetwork", normalizeKey("HostNetwork"))
+	assert.Equal(t, "workingDir", normalizeKey("workdir"))
+}
@@ -1,5 +1,12 @@
 package model
 
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"time"
+)
+
 // Container defines what image should be running
 type Container struct {
 	Name       string `validate:"required,gt=0,alphanumOrDash"`
@@ -10,6 +17,27 @@ type Container struct {
 	WorkingDir string   `validate:"omitempty,gt=0"`
 	Mounts     []Mount  `validate:"dive"`
 	Pipe       *PipeSet
+	// RestartPolicy overrides the pod restart policy, omitted from the spec hash when empty
+	RestartPolicy string `json:",omitempty"`
+	// ReadinessProbe tells when the container is ready, omitted from the spec hash when not set
+	ReadinessProbe *Probe `json:",omitempty"`
+}
+
+// Probe checks the container readiness with HTTP GET request or command, only one of them is set
+type Probe struct {
+	HTTPGet *HTTPGetProbe `json:",omitempty"`
+	Exec    *ExecProbe    `json:",omitempty"`
+}
+
+// HTTPGetProbe succeeds when GET request to the container port returns 2xx or 3xx status code
+type HTTPGetProbe struct {
+	Path string
+	Port int
+}
+
+// ExecProbe succeeds when the command exits with zero exit code in the container
+type ExecProbe struct {
+	Command []string
 }
 
 // PipeSet allows defining pipe from some source(s) to another container
@@ -27,6 +55,10 @@ type PipeToStdin struct {
 	Name string
 }
 
+// PodVolumesDir is the node directory for the pod volumes, e.g. eli up syncs the project to /var/lib/volumes/<pod name>/.
+// The bind mounts from the directory get removed when the pod is deleted with cascade, unless other pods mount them.
+const PodVolumesDir = "/var/lib/volumes"
+
 // Mount defines directory mount from host to the container
 type Mount struct {
 	Type        string   `validate:"omitempty,gt=0"`
@@ -35,6 +67,13 @@ type Mount struct {
 	Options     []string `validate:"dive,gt=0"`
 }
 
+// Reasons why the container exited
+const (
+	ReasonCompleted = "Completed"
+	ReasonError     = "Error"
+	ReasonOOMKilled = "OOMKilled"
+)
+
 // ContainerStatus represents one container status
 type ContainerStatus struct {
 	ContainerID  string `validate:"required,gt=0"`
@@ -42,4 +81,32 @@ type ContainerStatus struct {
 	Image        string `validate:"required,gt=0,imageRef"`
 	State        string `validate:"required,gt=0"`
 	RestartCount int    `validate:"required,gte=0"`
+	// SpecHash is the GetContainerSpecHash value of the spec the container was created from
+	SpecHash string
+	// StartedAt is when the container was last started, zero if never started
+	StartedAt time.Time
+	// ExitCode, Reason and FinishedAt are set only when the container has exited
+	ExitCode   int
+	Reason     string
+	FinishedAt time.Time
+	// Ready is true when the container is running and its readiness probe, if any, has succeeded
+	Ready bool
+	// ReadinessMessage tells why the readiness probe failed
+	ReadinessMessage string
+}
+
+// GetContainerSpecHash return hash of the container spec, including the pod options what affect to the container.
+// If the hash changes, the container must be recreated to apply the change.
+func GetContainerSpecHash(spec PodSpec, container Container) string {
+	data, err := json.Marshal(struct {
+		HostNetwork bool
+		HostPID     bool
+		Container   Container
+	}{spec.HostNetwork, spec.HostPID, container})
+	if err != nil {
+		// Container contains only plain values, so this should never happen
+		panic(err)
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
 }
@@ -46,3 +46,19 @@ func TestValidationRequiresContainerFields(t *testing.T) {
 		Image: "/foo",
 	}), "should return error if container image reference is invalid")
 }
+
+func TestGetContainerSpecHash(t *testing.T) {
+	container := Container{
+		Name:  "foo",
+		Image: "docker.io/library/foobar:latest",
+		Env:   []string{"FOO=bar"},
+	}
+	hash := GetContainerSpecHash(PodSpec{}, container)
+	assert.Equal(t, hash, GetContainerSpecHash(PodSpec{}, container), "should be stable")
+
+	changed := container
+	changed.Env = []string{"FOO=baz"}
+	assert.NotEqual(t, hash, GetContainerSpecHash(PodSpec{}, changed), "should change when env changes")
+
+	assert.NotEqual(t, hash, GetContainerSpecHash(PodSpec{HostNetwork: true}, container), "should change when pod host network changes")
+}
@@ -4,6 +4,7 @@ package model
 type Metadata struct {
 	Name      string `validate:"required,gt=0,alphanumOrDash"`
 	Namespace string `validate:"omitempty,gt=0,alphanumOrDash"`
+	Labels    map[string]string
 }
 
 // NewMetadata creates new metadata with name and metadata fields
@@ -1,5 +1,12 @@
 package model
 
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"sort"
+	"strings"
+)
+
 // DefaultNamespace is namespace what each pod get if there is no metadata.namespace
 var DefaultNamespace = "eliot"
 
@@ -16,12 +23,18 @@ type PodSpec struct {
 	HostPID       bool
 	Containers    []Container `validate:"required,gt=0,dive"`
 	RestartPolicy string
+	// InitContainers run one at a time to completion before the containers get started
+	InitContainers []Container `validate:"dive"`
 }
 
 // PodStatus represents latest known state of pod
 type PodStatus struct {
 	Hostname          string
 	ContainerStatuses []ContainerStatus `validate:"dive"`
+	// InitContainerStatuses are in the order the init containers run
+	InitContainerStatuses []ContainerStatus `validate:"dive"`
+	// Cordoned pod containers are not restarted by the restart policy
+	Cordoned bool
 }
 
 // AppendContainer adds container to the pod information
@@ -29,3 +42,87 @@ func (p *Pod) AppendContainer(container Container, status ContainerStatus) {
 	p.Spec.Containers = append(p.Spec.Containers, container)
 	p.Status.ContainerStatuses = append(p.Status.ContainerStatuses, status)
 }
+
+// AppendInitContainer adds init container to the pod information
+func (p *Pod) AppendInitContainer(container Container, status ContainerStatus) {
+	p.Spec.InitContainers = append(p.Spec.InitContainers, container)
+	p.Status.InitContainerStatuses = append(p.Status.InitContainerStatuses, status)
+}
+
+// GetRestartPolicy return the container restart policy, falling back to the pod restart policy and "always".
+// Init containers run to completion, so they fall back to "onfailure" unless the pod restart policy is "never".
+func GetRestartPolicy(pod Pod, containerName string) string {
+	for _, container := range pod.Spec.Containers {
+		if container.Name == containerName && container.RestartPolicy != "" {
+			return container.RestartPolicy
+		}
+	}
+	for _, container := range pod.Spec.InitContainers {
+		if container.Name != containerName {
+			continue
+		}
+		if container.RestartPolicy != "" {
+			return container.RestartPolicy
+		}
+		if pod.Spec.RestartPolicy == "never" {
+			return "never"
+		}
+		return "onfailure"
+	}
+	if pod.Spec.RestartPolicy != "" {
+		return pod.Spec.RestartPolicy
+	}
+	return "always"
+}
+
+// IsInitContainerCompleted return true if the init container has exited successfully
+func IsInitContainerCompleted(status ContainerStatus) bool {
+	return status.State == "stopped" && status.Reason == ReasonCompleted
+}
+
+// GetPendingInitContainer return index of the first init container which hasn't completed yet,
+// or -1 if all init containers have completed and the containers can be started
+func GetPendingInitContainer(pod Pod) int {
+	for i, status := range pod.Status.InitContainerStatuses {
+		if !IsInitContainerCompleted(status) {
+			return i
+		}
+	}
+	return -1
+}
+
+// ShouldRestart return true if the stopped container should be started again based on the restart policy
+func ShouldRestart(policy string, status ContainerStatus) bool {
+	if status.State != "stopped" && status.State != "unknown" {
+		return false
+	}
+
+	switch policy {
+	case "never":
+		return false
+	case "onfailure":
+		return status.ExitCode != 0 || status.Reason == ReasonOOMKilled
+	}
+	return true
+}
+
+// GetPodVersion return version of the pod, which changes every time container is created, recreated or removed,
+// or the pod labels change. Used to detect that the pod has been changed since it was read.
+// Return empty string if the pod doesn't have any containers created.
+func GetPodVersion(pod Pod) string {
+	if len(pod.Status.ContainerStatuses) == 0 {
+		return ""
+	}
+
+	entries := []string{}
+	for _, status := range append(append([]ContainerStatus{}, pod.Status.InitContainerStatuses...), pod.Status.ContainerStatuses...) {
+		entries = append(entries, strings.Join([]string{status.Name, status.ContainerID, status.SpecHash}, ":"))
+	}
+	for key, value := range pod.Metadata.Labels {
+		entries = append(entries, "label:"+key+"="+value)
+	}
+	sort.Strings(entries)
+
+	sum := sha256.Sum256([]byte(strings.Join(entries, "\n")))
+	return hex.EncodeToString(sum[:8])
+}
@@ -101,3 +101,74 @@ func TestValidationNamespaceMetadata(t *testing.T) {
 		},
 	}), "should return error if not alphanumeric namespace")
 }
+
+func TestGetPodVersion(t *testing.T) {
+	pod := Pod{Status: PodStatus{ContainerStatuses: []ContainerStatus{
+		{Name: "foo", ContainerID: "foo-1", SpecHash: "a"},
+		{Name: "bar", ContainerID: "bar-1", SpecHash: "b"},
+	}}}
+	reordered := Pod{Status: PodStatus{ContainerStatuses: []ContainerStatus{
+		pod.Status.ContainerStatuses[1],
+		pod.Status.ContainerStatuses[0],
+	}}}
+	recreated := Pod{Status: PodStatus{ContainerStatuses: []ContainerStatus{
+		{Name: "foo", ContainerID: "foo-2", SpecHash: "a"},
+		{Name: "bar", ContainerID: "bar-1", SpecHash: "b"},
+	}}}
+
+	assert.Equal(t, GetPodVersion(pod), GetPodVersion(reordered), "should not depend on the container order")
+	assert.NotEqual(t, GetPodVersion(pod), GetPodVersion(recreated), "should change when container is recreated")
+
+	relabeled := pod
+	relabeled.Metadata.Labels = map[string]string{"app": "foo"}
+	assert.NotEqual(t, GetPodVersion(pod), GetPodVersion(relabeled), "should change when labels change")
+}
+
+func TestShouldRestart(t *testing.T) {
+	stopped := ContainerStatus{State: "stopped", Reason: ReasonCompleted}
+	failed := ContainerStatus{State: "stopped", ExitCode: 1, Reason: ReasonError}
+	oomKilled := ContainerStatus{State: "stopped", ExitCode: 137, Reason: ReasonOOMKilled}
+
+	assert.True(t, ShouldRestart("always", stopped))
+	assert.False(t, ShouldRestart("always", ContainerStatus{State: "running"}))
+	assert.False(t, ShouldRestart("onfailure", stopped))
+	assert.True(t, ShouldRestart("onfailure", failed))
+	assert.True(t, ShouldRestart("onfailure", oomKilled))
+	assert.False(t, ShouldRestart("never", failed))
+}
+
+func TestGetRestartPolicy(t *testing.T) {
+	pod := Pod{Spec: PodSpec{Containers: []Container{{Name: "app"}, {Name: "migrate", RestartPolicy: "never"}}}}
+	assert.Equal(t, "always", GetRestartPolicy(pod, "app"))
+	assert.Equal(t, "never", GetRestartPolicy(pod, "migrate"))
+
+	pod.Spec.RestartPolicy = "onfailure"
+	assert.Equal(t, "onfailure", GetRestartPolicy(pod, "app"))
+}
+
+func TestGetRestartPolicyOfInitContainer(t *testing.T) {
+	pod := Pod{Spec: PodSpec{
+		Containers:     []Container{{Name: "app"}},
+		InitContainers: []Container{{Name: "migrate"}, {Name: "seed", RestartPolicy: "never"}},
+	}}
+	assert.Equal(t, "onfailure", GetRestartPolicy(pod, "migrate"), "should not restart completed init container")
+	assert.Equal(t, "never", GetRestartPolicy(pod, "seed"))
+
+	pod.Spec.RestartPolicy = "never"
+	assert.Equal(t, "never", GetRestartPolicy(pod, "migrate"))
+}
+
+func TestGetPendingInitContainer(t *testing.T) {
+	pod := Pod{Status: PodStatus{InitContainerStatuses: []ContainerStatus{
+		{Name: "migrate", State: "stopped", Reason: ReasonCompleted},
+		{Name: "seed", State: "created"},
+	}}}
+	assert.Equal(t, 1, GetPendingInitContainer(pod))
+
+	pod.Status.InitContainerStatuses[1] = ContainerStatus{Name: "seed", State: "stopped", ExitCode: 1, Reason: ReasonError}
+	assert.Equal(t, 1, GetPendingInitContainer(pod), "failed init container is still pending")
+
+	pod.Status.InitContainerStatuses[1] = ContainerStatus{Name: "seed", State: "stopped", Reason: ReasonCompleted}
+	assert.Equal(t, -1, GetPendingInitContainer(pod))
+	assert.Equal(t, -1, GetPendingInitContainer(Pod{}), "pod without init containers can start right away")
+}
@@ -0,0 +1,110 @@
+package model
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// selectorOperator defines how the label value get compared
+type selectorOperator string
+
+const (
+	selectorEquals    selectorOperator = "="
+	selectorNotEquals selectorOperator = "!="
+	selectorExists    selectorOperator = ""
+)
+
+// selectorRequirement is single "key=value", "key!=value" or "key" term in Selector
+type selectorRequirement struct {
+	key      string
+	operator selectorOperator
+	value    string
+}
+
+func (r selectorRequirement) matches(labels map[string]string) bool {
+	value, exist := labels[r.key]
+	switch r.operator {
+	case selectorEquals:
+		return exist && value == r.value
+	case selectorNotEquals:
+		return !exist || value != r.value
+	default:
+		return exist
+	}
+}
+
+func (r selectorRequirement) String() string {
+	if r.operator == selectorExists {
+		return r.key
+	}
+	return r.key + string(r.operator) + r.value
+}
+
+// Selector selects resources by labels. All requirements must match.
+type Selector []selectorRequirement
+
+// ParseSelector parses comma separated label selector, e.g. "app=nginx,env!=dev,tier"
+// where "key=value" requires the label value, "key!=value" requires missing label or different value
+// and plain "key" requires the label to exist. Empty string selects everything.
+func ParseSelector(selector string) (Selector, error) {
+	result := Selector{}
+	for _, term := range strings.Split(selector, ",") {
+		term = strings.TrimSpace(term)
+		if term == "" {
+			continue
+		}
+
+		requirement := selectorRequirement{key: term, operator: selectorExists}
+		if i := strings.Index(term, "!="); i >= 0 {
+			requirement = selectorRequirement{key: term[:i], operator: selectorNotEquals, value: term[i+2:]}
+		} else if i := strings.Index(term, "="); i >= 0 {
+			requirement = selectorRequirement{key: term[:i], operator: selectorEquals, value: term[i+1:]}
+		}
+
+		requirement.key = strings.TrimSpace(requirement.key)
+		requirement.value = strings.TrimSpace(requirement.value)
+		if requirement.key == "" {
+			return nil, fmt.Errorf("Invalid label selector [%s], term [%s] is missing the key", selector, term)
+		}
+		if strings.ContainsAny(requirement.key, "=! ") || strings.ContainsAny(requirement.value, "=! ") {
+			return nil, fmt.Errorf("Invalid label selector [%s], cannot parse term [%s]", selector, term)
+		}
+		result = append(result, requirement)
+	}
+	return result, nil
+}
+
+// SelectorFromMap creates Selector which requires all the labels to have the given values
+func SelectorFromMap(labels map[string]string) Selector {
+	keys := []string{}
+	for key := range labels {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	result := Selector{}
+	for _, key := range keys {
+		result = append(result, selectorRequirement{key: key, operator: selectorEquals, value: labels[key]})
+	}
+	return result
+}
+
+// Matches return true if the labels match all selector requirements
+func (s Selector) Matches(labels map[string]string) bool {
+	for _, requirement := range s {
+		if !requirement.matches(labels) {
+			return false
+		}
+	}
+	return true
+}
+
+// String formats the selector in the format what ParseSelector accepts
+func (s Selector) String() string {
+	terms := []string{}
+	for _, requirement := range s {
+		terms = append(terms, requirement.String())
+	}
+	return strings.Join(terms, ",")
+}
@@ -0,0 +1,40 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseSelector(t *testing.T) {
+	selector, err := ParseSelector("app=nginx, env!=dev,tier")
+	assert.NoError(t, err)
+	assert.Equal(t, "app=nginx,env!=dev,tier", selector.String())
+
+	assert.True(t, selector.Matches(map[string]string{"app": "nginx", "env": "prod", "tier": "web"}))
+	assert.True(t, selector.Matches(map[string]string{"app": "nginx", "tier": "web"}), "missing label should match !=")
+	assert.False(t, selector.Matches(map[string]string{"app": "nginx", "env": "dev", "tier": "web"}))
+	assert.False(t, selector.Matches(map[string]string{"app": "nginx", "env": "prod"}), "should require tier label to exist")
+	assert.False(t, selector.Matches(map[string]string{"app": "apache", "tier": "web"}))
+}
+
+func TestParseEmptySelectorMatchesAll(t *testing.T) {
+	selector, err := ParseSelector("")
+	assert.NoError(t, err)
+	assert.True(t, selector.Matches(nil))
+}
+
+func TestParseInvalidSelector(t *testing.T) {
+	_, err := ParseSelector("=foo")
+	assert.Error(t, err)
+
+	_, err = ParseSelector("app=foo=bar")
+	assert.Error(t, err)
+}
+
+func TestSelectorFromMap(t *testing.T) {
+	selector := SelectorFromMap(map[string]string{"env": "prod", "app": "nginx"})
+	assert.Equal(t, "app=nginx,env=prod", selector.String())
+	assert.True(t, selector.Matches(map[string]string{"app": "nginx", "env": "prod", "extra": "yes"}), "should match superset")
+	assert.False(t, selector.Matches(map[string]string{"app": "nginx"}))
+}
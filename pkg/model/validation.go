@@ -23,7 +23,7 @@ func getValidator() *validator.Validate {
 			return hasValidName(fl.Field().Interface().(Metadata))
 		})
 		validate.RegisterValidation("imageRef", func(fl validator.FieldLevel) bool {
-			return isValidImageReference(fl.Field().Interface().(string))
+			return IsValidImageReference(fl.Field().Interface().(string))
 		})
 		validate.RegisterValidation("alphanumOrDash", func(fl validator.FieldLevel) bool {
 			return isAlphanumericOrDash(fl.Field().Interface().(string))
@@ -48,7 +48,8 @@ func hasValidName(metadata Metadata) bool {
 	return true
 }
 
-func isValidImageReference(ref string) bool {
+// IsValidImageReference return true if ref is valid image reference, e.g. docker.io/library/nginx:latest
+func IsValidImageReference(ref string) bool {
 	_, err := imageref.Parse(ref)
 	return err == nil
 }
@@ -108,3 +109,8 @@ func Validate(pods []Pod) error {
 
 	return nil
 }
+
+// IsValidNamespace return true if the namespace name is alphanumeric and can contain dashes
+func IsValidNamespace(namespace string) bool {
+	return isAlphanumericOrDash(namespace)
+}
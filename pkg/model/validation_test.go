@@ -31,9 +31,9 @@ func TestValidationIsValid(t *testing.T) {
 }
 
 func TestImageReferenceValidation(t *testing.T) {
-	assert.True(t, isValidImageReference("docker.io/library/hello-world:latest"), "should be valid full image reference")
-	assert.True(t, isValidImageReference("docker.io/library/hello-world"), "should be valid image reference without tag")
-	assert.False(t, isValidImageReference("/hello-world"), "should be invalid reference if no hostname")
+	assert.True(t, IsValidImageReference("docker.io/library/hello-world:latest"), "should be valid full image reference")
+	assert.True(t, IsValidImageReference("docker.io/library/hello-world"), "should be valid image reference without tag")
+	assert.False(t, IsValidImageReference("/hello-world"), "should be invalid reference if no hostname")
 }
 
 func TestEnvKeyValuePairs(t *testing.T) {
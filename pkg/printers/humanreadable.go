@@ -10,6 +10,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/ernoaapa/eliot/pkg/api"
 	containers "github.com/ernoaapa/eliot/pkg/api/services/containers/v1"
 	node "github.com/ernoaapa/eliot/pkg/api/services/node/v1"
 	pods "github.com/ernoaapa/eliot/pkg/api/services/pods/v1"
@@ -51,14 +52,25 @@ func (p *HumanReadablePrinter) PrintPods(pods []*pods.Pod, writer io.Writer) err
 	return nil
 }
 
-// getStatus constructs a string representation of all containers statuses
+// getStatus constructs a string representation of all containers statuses, with " Cordoned" suffix if the pod
+// is cordoned. If there's only one container, return its state in detail, e.g. "Exited (137) OOMKilled 2m ago"
 func getStatus(pod *pods.Pod) string {
+	if pod.GetStatus().GetCordoned() {
+		return getContainersStatus(pod) + " Cordoned"
+	}
+	return getContainersStatus(pod)
+}
+
+func getContainersStatus(pod *pods.Pod) string {
 	counts := map[string]int{}
 
 	statuses := []*containers.ContainerStatus{}
 	if pod.Status != nil {
 		statuses = pod.Status.ContainerStatuses
 	}
+	if len(statuses) == 1 {
+		return api.MapContainerState(statuses[0]).String()
+	}
 	for _, status := range statuses {
 		if _, ok := counts[status.State]; !ok {
 			counts[status.State] = 0
@@ -156,7 +168,7 @@ func (p *HumanReadablePrinter) PrintPod(pod *pods.Pod, writer io.Writer) error {
 			if pod.Status == nil {
 				return nil
 			}
-			for _, status := range pod.Status.ContainerStatuses {
+			for _, status := range append(append([]*containers.ContainerStatus{}, pod.Status.InitContainerStatuses...), pod.Status.ContainerStatuses...) {
 				if status.Name == name {
 					return status
 				}
@@ -164,6 +176,9 @@ func (p *HumanReadablePrinter) PrintPod(pod *pods.Pod, writer io.Writer) error {
 			return nil
 		},
 		"StringsJoin": strings.Join,
+		"FormatState": func(status *containers.ContainerStatus) string { return api.MapContainerState(status).String() },
+		"FormatReady": formatReady,
+		"FormatProbe": formatProbe,
 	})
 	t, err := t.Parse(humanreadable.PodDetailsTemplate)
 	if err != nil {
@@ -176,6 +191,25 @@ func (p *HumanReadablePrinter) PrintPod(pod *pods.Pod, writer io.Writer) error {
 	return t.Execute(writer, data)
 }
 
+// formatReady return is the container ready, with the reason if the readiness probe failed
+func formatReady(status *containers.ContainerStatus) string {
+	if !status.GetReady() && status.GetReadinessMessage() != "" {
+		return fmt.Sprintf("false (%s)", status.GetReadinessMessage())
+	}
+	return strconv.FormatBool(status.GetReady())
+}
+
+// formatProbe return description of the probe, e.g. "http-get :8080/healthz" or "exec [pg_isready -q]"
+func formatProbe(probe *containers.Probe) string {
+	switch {
+	case probe.GetHttpGet() != nil:
+		return fmt.Sprintf("http-get :%d%s", probe.GetHttpGet().GetPort(), probe.GetHttpGet().GetPath())
+	case probe.GetExec() != nil:
+		return fmt.Sprintf("exec [%s]", strings.Join(probe.GetExec().GetCommand(), " "))
+	}
+	return "none"
+}
+
 // PrintConfig writes list of pods in human readable detailed format to the writer
 func (p *HumanReadablePrinter) PrintConfig(config *config.Config, writer io.Writer) error {
 	t := template.New("config")
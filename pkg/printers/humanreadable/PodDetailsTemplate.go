@@ -4,19 +4,38 @@ package humanreadable
 const PodDetailsTemplate = `{{$pod := .Pod -}}
 Name:	{{.Pod.Metadata.Name}}
 Namespace:	{{.Pod.Metadata.Namespace}}
+Labels:{{range $key, $value := .Pod.Metadata.Labels}}
+	{{$key}}={{$value}}
+{{- end}}
 Node:	{{.Pod.Status.Hostname}}
 State:	{{.Status}}
 Restart Policy:	{{.Pod.Spec.RestartPolicy}}
 Host Network:	{{.Pod.Spec.HostNetwork}}
 Host PID:	{{.Pod.Spec.HostPID}}
+{{- if .Pod.Spec.InitContainers}}
+Init Containers:{{range .Pod.Spec.InitContainers}}
+  {{- $status := GetStatus $pod .Name}}
+	{{.Name}}:
+		Image:	{{.Image}}
+    {{- if $status }}
+		ContainerID:	{{$status.ContainerID}}
+		State:	{{FormatState $status}}
+		Restart Count:	{{$status.RestartCount}}
+		{{- end}}
+		Args:{{range .Args}}
+			- {{.}}
+		{{- end}}
+	{{- end}}
+{{- end}}
 Containers:{{range .Pod.Spec.Containers}}
   {{- $status := GetStatus $pod .Name}}
 	{{.Name}}:
 		Image:	{{.Image}}
     {{- if $status }}
 		ContainerID:	{{$status.ContainerID}}
-		State:	{{$status.State}}
+		State:	{{FormatState $status}}
 		Restart Count:	{{$status.RestartCount}}
+		Ready:	{{FormatReady $status}}
 		Working Dir:	{{.WorkingDir}}
 		{{- end}}
 		Args:{{range .Args}}
@@ -32,5 +51,8 @@ Containers:{{range .Pod.Spec.Containers}}
 		Pipe:
 			stdout -> stdin: {{.Pipe.Stdout.Stdin.Name}}
 		{{- end}}
+    {{- if .ReadinessProbe}}
+		Readiness Probe:	{{FormatProbe .ReadinessProbe}}
+		{{- end}}
 	{{end}}
 `
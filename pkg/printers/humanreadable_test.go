@@ -3,6 +3,8 @@ package printers
 import (
 	"testing"
 
+	containers "github.com/ernoaapa/eliot/pkg/api/services/containers/v1"
+	pods "github.com/ernoaapa/eliot/pkg/api/services/pods/v1"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -15,3 +17,28 @@ func TestFormatDuration(t *testing.T) {
 	assert.Equal(t, "292 years 24 weeks 3 days 23 hours 47 minutes 16 seconds", formatUptime(9223372036), "should format large value (maximum Nanosecond duration in seconds)")
 	assert.Equal(t, "18446744073709551615 seconds", formatUptime(18446744073709551615), "Should not break if goes above int64 (e.g. if maximum uint64)")
 }
+
+func TestGetStatus(t *testing.T) {
+	single := &pods.Pod{Status: &pods.PodStatus{ContainerStatuses: []*containers.ContainerStatus{
+		{Name: "foo", State: "stopped", ExitCode: 1, Reason: "Error"},
+	}}}
+	assert.Equal(t, "Exited (1) Error", getStatus(single), "should show the state in detail if only one container")
+
+	multi := &pods.Pod{Status: &pods.PodStatus{ContainerStatuses: []*containers.ContainerStatus{
+		{Name: "foo", State: "running"},
+		{Name: "bar", State: "running"},
+		{Name: "baz", State: "stopped"},
+	}}}
+	assert.Equal(t, "running(2),stopped(1)", getStatus(multi))
+
+	single.Status.Cordoned = true
+	assert.Equal(t, "Exited (1) Error Cordoned", getStatus(single), "should show that the containers don't get restarted")
+}
+
+func TestFormatReadiness(t *testing.T) {
+	assert.Equal(t, "true", formatReady(&containers.ContainerStatus{State: "running", Ready: true}))
+	assert.Equal(t, "false (Exec probe [pg_isready] exited with code 2)", formatReady(&containers.ContainerStatus{State: "running", ReadinessMessage: "Exec probe [pg_isready] exited with code 2"}))
+
+	assert.Equal(t, "http-get :8080/healthz", formatProbe(&containers.Probe{HttpGet: &containers.HTTPGetProbe{Path: "/healthz", Port: 8080}}))
+	assert.Equal(t, "exec [pg_isready -q]", formatProbe(&containers.Probe{Exec: &containers.ExecProbe{Command: []string{"pg_isready", "-q"}}}))
+}
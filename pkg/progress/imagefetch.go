@@ -1,9 +1,22 @@
 package progress
 
 import (
+	"sort"
 	"sync"
 )
 
+// Layer pull states of Status and LayerProgress
+const (
+	// StatusWaiting layer is not yet downloading
+	StatusWaiting = "waiting"
+	// StatusDownloading layer is being downloaded
+	StatusDownloading = "downloading"
+	// StatusExtracting layer is downloaded and being unpacked to the snapshotter
+	StatusExtracting = "extracting"
+	// StatusDone layer is ready
+	StatusDone = "done"
+)
+
 // ImageFetch stores container pull status
 type ImageFetch struct {
 	ContainerID string
@@ -28,26 +41,32 @@ func NewStatus(ref, digest string) *Status {
 	return &Status{
 		Ref:    ref,
 		Digest: digest,
-		Status: "waiting",
+		Status: StatusWaiting,
 	}
 }
 
 // Waiting marks status to be in waiting state
 func (s *Status) Waiting() {
-	s.Status = "waiting"
+	s.Status = StatusWaiting
 }
 
 // Downloading updates Status to downloading
 func (s *Status) Downloading(offset, total int64) {
-	s.Status = "downloading"
+	s.Status = StatusDownloading
 	s.Offset = offset
 	s.Total = total
 }
 
+// Extracting marks Status downloaded and being extracted
+func (s *Status) Extracting() {
+	s.Offset = s.Total
+	s.Status = StatusExtracting
+}
+
 // Done marks Status to done state
 func (s *Status) Done() {
 	s.Offset = s.Total
-	s.Status = "done"
+	s.Status = StatusDone
 }
 
 // NewImageFetch creates new ImageFetch for given name
@@ -73,6 +92,9 @@ func (s *ImageFetch) IsDone() bool {
 
 // GetProgress calculates current and total bytes of all layers
 func (s *ImageFetch) GetProgress() (current, total int64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
 	for _, layer := range s.layers {
 		current += layer.Offset
 		total += layer.Total
@@ -137,6 +159,16 @@ func (s *ImageFetch) SetToFailed() {
 	s.Failed = true
 }
 
+// AllExtracting marks all layers downloaded and being extracted
+func (s *ImageFetch) AllExtracting() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, layer := range s.layers {
+		layer.Extracting()
+	}
+}
+
 // AllDone marks all layers downloaded
 func (s *ImageFetch) AllDone() {
 	s.mu.Lock()
@@ -147,10 +179,71 @@ func (s *ImageFetch) AllDone() {
 	}
 }
 
+// Merge adds the layers of other fetch of the same image, e.g. when multiple containers use the image.
+// The layer progress is the furthest of the two, and the fetch is failed if either of them failed.
+func (s *ImageFetch) Merge(other *ImageFetch) {
+	other.mu.Lock()
+	resolved, failed := other.Resolved, other.Failed
+	other.mu.Unlock()
+	layers := other.GetLayers()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.Resolved = s.Resolved || resolved
+	s.Failed = s.Failed || failed
+
+	for _, layer := range layers {
+		if current, ok := s.layers[layer.Ref]; ok && current.Offset >= layer.Offset {
+			continue
+		}
+		merged := layer
+		s.layers[layer.Ref] = &merged
+	}
+}
+
 // GetLayers return list of layers
 func (s *ImageFetch) GetLayers() (result []Status) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
 	for _, status := range s.layers {
 		result = append(result, *status)
 	}
 	return result
 }
+
+// LayerProgress is snapshot of single layer pull progress. Unlike Status, it is not updated
+// after it is taken, so progress handlers can keep and compare the values.
+type LayerProgress struct {
+	// Image is the image reference the layer belongs to
+	Image  string
+	Ref    string
+	Digest string
+	// Total and Downloaded are the layer size and the downloaded bytes, Total is zero until the download starts
+	Total      int64
+	Downloaded int64
+	// Status is StatusWaiting, StatusDownloading, StatusExtracting or StatusDone
+	Status string
+}
+
+// Snapshot return the current progress of the layers, sorted by the ref
+func (s *ImageFetch) Snapshot() []LayerProgress {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	result := []LayerProgress{}
+	for _, layer := range s.layers {
+		result = append(result, LayerProgress{
+			Image:      s.Image,
+			Ref:        layer.Ref,
+			Digest:     layer.Digest,
+			Total:      layer.Total,
+			Downloaded: layer.Offset,
+			Status:     layer.Status,
+		})
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Ref < result[j].Ref
+	})
+	return result
+}
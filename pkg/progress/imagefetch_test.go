@@ -1,6 +1,8 @@
 package progress
 
 import (
+	"fmt"
+	"sync"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -24,3 +26,59 @@ func TestIsDone(t *testing.T) {
 
 	assert.True(t, fetch.IsDone())
 }
+
+func TestMerge(t *testing.T) {
+	fetch := CreateImageFetch("first", "imageref", true, map[string]*Status{
+		"1": {Ref: "1", Offset: 100, Total: 100},
+		"2": {Ref: "2", Offset: 20, Total: 200},
+	})
+	fetch.Merge(CreateImageFetch("second", "imageref", false, map[string]*Status{
+		"1": {Ref: "1", Offset: 50, Total: 100},
+		"2": {Ref: "2", Offset: 200, Total: 200},
+	}))
+
+	assert.Equal(t, "first", fetch.ContainerID)
+	assert.True(t, fetch.Resolved)
+	assert.True(t, fetch.IsDone(), "should keep the furthest progress of each layer")
+}
+
+func TestSnapshot(t *testing.T) {
+	fetch := NewImageFetch("containerID", "imageref")
+	fetch.Add("2", "sha256:2")
+	fetch.Add("1", "sha256:1")
+	fetch.SetToDownloading("1", 50, 100)
+
+	snapshot := fetch.Snapshot()
+	fetch.AllExtracting()
+
+	assert.Equal(t, []LayerProgress{
+		{Image: "imageref", Ref: "1", Digest: "sha256:1", Total: 100, Downloaded: 50, Status: StatusDownloading},
+		{Image: "imageref", Ref: "2", Digest: "sha256:2", Status: StatusWaiting},
+	}, snapshot, "should not change after taken")
+	assert.Equal(t, StatusExtracting, fetch.Snapshot()[0].Status)
+	assert.Equal(t, int64(100), fetch.Snapshot()[0].Downloaded)
+}
+
+func TestImageFetchConcurrentUpdates(t *testing.T) {
+	fetch := NewImageFetch("containerID", "imageref")
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 100; i++ {
+			ref := fmt.Sprintf("layer-%d", i)
+			fetch.Add(ref, "digest")
+			fetch.SetToDownloading(ref, 50, 100)
+			fetch.SetToDone(ref)
+		}
+	}()
+	for i := 0; i < 100; i++ {
+		fetch.GetProgress()
+		fetch.GetLayers()
+	}
+	wg.Wait()
+
+	assert.True(t, fetch.IsDone())
+	assert.Len(t, fetch.GetLayers(), 100)
+}
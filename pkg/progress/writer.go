@@ -0,0 +1,86 @@
+package progress
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"sync"
+)
+
+// writerStep is how often, in percents, Writer prints the download progress
+const writerStep = 10
+
+// Writer prints the image pull progress as plain lines, without terminal control codes,
+// so that the output can be written to a file or CI log.
+// Each download percentage step and the final line of each image are printed only once.
+type Writer struct {
+	out     io.Writer
+	mu      sync.Mutex
+	percent map[string]int
+	done    map[string]bool
+	images  map[string]string
+}
+
+// NewWriter creates new Writer which prints the progress lines to the out
+func NewWriter(out io.Writer) *Writer {
+	return &Writer{
+		out:     out,
+		percent: map[string]int{},
+		done:    map[string]bool{},
+		images:  map[string]string{},
+	}
+}
+
+// Update prints lines for the fetches which progressed since the last update
+func (w *Writer) Update(fetches []*ImageFetch) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	for _, fetch := range fetches {
+		key := fmt.Sprintf("%s %s", fetch.ContainerID, fetch.Image)
+		if w.done[key] {
+			continue
+		}
+		w.images[key] = fetch.Image
+
+		switch {
+		case fetch.Failed:
+			w.done[key] = true
+			fmt.Fprintf(w.out, "Failed to pull %s\n", fetch.Image)
+		case fetch.IsDone():
+			w.done[key] = true
+			fmt.Fprintf(w.out, "Pulled %s\n", fetch.Image)
+		default:
+			current, total := fetch.GetProgress()
+			if total == 0 {
+				continue
+			}
+			// Total grows when new layers get resolved, so print only when the progress goes forward
+			percent := int(current*100/total) / writerStep * writerStep
+			if last, ok := w.percent[key]; ok && percent <= last {
+				continue
+			}
+			w.percent[key] = percent
+			fmt.Fprintf(w.out, "Downloading %s %d%%\n", fetch.Image, percent)
+		}
+	}
+}
+
+// Cancel prints the final line for the images which are not pulled yet, e.g. when the pull gets cancelled.
+// The later updates of the images are not printed.
+func (w *Writer) Cancel() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	keys := []string{}
+	for key := range w.images {
+		if !w.done[key] {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		w.done[key] = true
+		fmt.Fprintf(w.out, "Cancelled pulling %s\n", w.images[key])
+	}
+}
@@ -0,0 +1,73 @@
+package progress
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWriterPrintsEachLineOnce(t *testing.T) {
+	out := &bytes.Buffer{}
+	writer := NewWriter(out)
+
+	layer := &Status{Offset: 0, Total: 100}
+	fetch := CreateImageFetch("app", "docker.io/library/alpine:latest", true, map[string]*Status{"1": layer})
+
+	writer.Update([]*ImageFetch{fetch})
+	layer.Offset = 5
+	writer.Update([]*ImageFetch{fetch})
+	layer.Offset = 45
+	writer.Update([]*ImageFetch{fetch})
+	writer.Update([]*ImageFetch{fetch})
+	layer.Offset = 100
+	writer.Update([]*ImageFetch{fetch})
+	writer.Update([]*ImageFetch{fetch})
+
+	assert.Equal(t, "Downloading docker.io/library/alpine:latest 0%\n"+
+		"Downloading docker.io/library/alpine:latest 40%\n"+
+		"Pulled docker.io/library/alpine:latest\n", out.String())
+}
+
+func TestWriterDontGoBackwardWhenLayersAdded(t *testing.T) {
+	out := &bytes.Buffer{}
+	writer := NewWriter(out)
+
+	layers := map[string]*Status{"1": {Offset: 50, Total: 100}}
+	fetch := CreateImageFetch("app", "alpine", true, layers)
+	writer.Update([]*ImageFetch{fetch})
+
+	layers["2"] = &Status{Offset: 0, Total: 100}
+	writer.Update([]*ImageFetch{fetch})
+
+	assert.Equal(t, "Downloading alpine 50%\n", out.String())
+}
+
+func TestWriterPrintsFailure(t *testing.T) {
+	out := &bytes.Buffer{}
+	writer := NewWriter(out)
+
+	fetch := NewImageFetch("app", "alpine")
+	fetch.SetToFailed()
+	writer.Update([]*ImageFetch{fetch})
+	writer.Update([]*ImageFetch{fetch})
+
+	assert.Equal(t, "Failed to pull alpine\n", out.String())
+}
+
+func TestWriterCancel(t *testing.T) {
+	out := &bytes.Buffer{}
+	writer := NewWriter(out)
+
+	layer := &Status{Offset: 50, Total: 100}
+	pulling := CreateImageFetch("app", "alpine", true, map[string]*Status{"1": layer})
+	pulled := CreateImageFetch("app", "busybox", true, map[string]*Status{"1": {Offset: 100, Total: 100}})
+	writer.Update([]*ImageFetch{pulling, pulled})
+	writer.Cancel()
+	layer.Offset = 100
+	writer.Update([]*ImageFetch{pulling, pulled})
+
+	assert.Equal(t, "Downloading alpine 50%\n"+
+		"Pulled busybox\n"+
+		"Cancelled pulling alpine\n", out.String())
+}
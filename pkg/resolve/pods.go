@@ -1,6 +1,7 @@
 package resolve
 
 import (
+	"bytes"
 	"fmt"
 	"io/ioutil"
 	"net/http"
@@ -11,6 +12,7 @@ import (
 	containers "github.com/ernoaapa/eliot/pkg/api/services/containers/v1"
 	pods "github.com/ernoaapa/eliot/pkg/api/services/pods/v1"
 	"github.com/ernoaapa/eliot/pkg/fs"
+	"github.com/ernoaapa/eliot/pkg/manifest"
 	"github.com/ernoaapa/eliot/pkg/utils"
 	"github.com/pkg/errors"
 )
@@ -20,14 +22,39 @@ import (
 // - directory of yaml specs
 // - yaml spec file
 // - url to download yaml spec
-func Pods(sources []string) (result []*pods.Pod, err error) {
+// The opts are passed to the manifest loader, e.g. to substitute variables
+func Pods(sources []string, opts ...manifest.LoadOpts) (result []*pods.Pod, err error) {
+	manifests, err := Manifests(sources)
+	if err != nil {
+		return result, err
+	}
+	for _, m := range manifests {
+		resources, err := manifest.LoadPods(bytes.NewReader(m.Data), opts...)
+		if err != nil {
+			return result, errors.Wrapf(err, "Failed to read pod spec %s", m.Source)
+		}
+		result = append(result, resources...)
+	}
+	return result, nil
+}
+
+// Manifest is the raw content of single manifest file or url
+type Manifest struct {
+	// Source is the file path or url where the manifest was read
+	Source string
+	Data   []byte
+}
+
+// Manifests resolve the manifests from the same sources as Pods without decoding them,
+// e.g. to render the manifest templates before decoding
+func Manifests(sources []string) (result []Manifest, err error) {
 	for _, source := range sources {
 		if fs.FileExist(source) {
-			resources, err := readFileSource(source)
+			data, err := ioutil.ReadFile(source)
 			if err != nil {
 				return result, errors.Wrapf(err, "Failed to read pod spec file %s", source)
 			}
-			result = append(result, resources...)
+			result = append(result, Manifest{Source: source, Data: data})
 		} else if fs.DirExist(source) {
 			files, err := ioutil.ReadDir(source)
 			if err != nil {
@@ -35,30 +62,20 @@ func Pods(sources []string) (result []*pods.Pod, err error) {
 			}
 			for _, file := range files {
 				if !file.IsDir() {
-					resources, err := readFileSource(filepath.Join(source, file.Name()))
+					path := filepath.Join(source, file.Name())
+					data, err := ioutil.ReadFile(path)
 					if err != nil {
-						return result, errors.Wrapf(err, "Failed to read pod spec file %s", source)
+						return result, errors.Wrapf(err, "Failed to read pod spec file %s", path)
 					}
-					result = append(result, resources...)
+					result = append(result, Manifest{Source: path, Data: data})
 				}
 			}
 		} else if validURL(source) {
-			response, err := http.Get(source)
+			data, err := readURLSource(source)
 			if err != nil {
-				return result, errors.Wrapf(err, "Failed to load spec from url: %s", source)
+				return result, err
 			}
-			defer response.Body.Close()
-
-			data, err := ioutil.ReadAll(response.Body)
-			if err != nil {
-				return result, errors.Wrapf(err, "Failed to get spec response from url: %s", source)
-			}
-			resources, err := pods.UnmarshalYaml(data)
-			if err != nil {
-				return result, errors.Wrapf(err, "Failed to read pod spec response from url: %s", source)
-			}
-
-			result = append(result, resources...)
+			result = append(result, Manifest{Source: source, Data: data})
 		} else {
 			return result, fmt.Errorf("Unknown source %s. Must be file, directory or url", source)
 		}
@@ -66,13 +83,18 @@ func Pods(sources []string) (result []*pods.Pod, err error) {
 	return result, nil
 }
 
-func readFileSource(path string) ([]*pods.Pod, error) {
-	data, err := ioutil.ReadFile(path)
+func readURLSource(source string) ([]byte, error) {
+	response, err := http.Get(source)
 	if err != nil {
-		return []*pods.Pod{}, errors.Wrapf(err, "Failed to read pod spec file %s", path)
+		return nil, errors.Wrapf(err, "Failed to load spec from url: %s", source)
 	}
+	defer response.Body.Close()
 
-	return pods.UnmarshalYaml(data)
+	data, err := ioutil.ReadAll(response.Body)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Failed to read pod spec response from url: %s", source)
+	}
+	return data, nil
 }
 
 func validURL(u string) bool {
@@ -0,0 +1,265 @@
+package runtime
+
+import (
+	"archive/tar"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+)
+
+// CreateArchive writes tar archive of the path inside the root directory.
+// Like with `docker cp`, the path itself is the top level entry in the archive.
+func CreateArchive(root, path string, w io.Writer) error {
+	source, err := resolveInRoot(root, path)
+	if err != nil {
+		return err
+	}
+
+	if _, err := os.Lstat(source); err != nil {
+		if os.IsNotExist(err) {
+			return ErrWithMessagef(ErrNotFound, "Path [%s] does not exist", path)
+		}
+		return errors.Wrapf(err, "Failed to read path [%s]", path)
+	}
+
+	base := filepath.Dir(source)
+	if source == root {
+		base = root
+	}
+
+	archive := tar.NewWriter(w)
+	err = filepath.Walk(source, func(file string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		name, err := filepath.Rel(base, file)
+		if err != nil {
+			return err
+		}
+		if name == "." {
+			return nil
+		}
+
+		link := ""
+		if info.Mode()&os.ModeSymlink != 0 {
+			if link, err = os.Readlink(file); err != nil {
+				return err
+			}
+		}
+
+		header, err := tar.FileInfoHeader(info, link)
+		if err != nil {
+			return err
+		}
+		header.Name = filepath.ToSlash(name)
+		if info.IsDir() {
+			header.Name += "/"
+		}
+
+		if err := archive.WriteHeader(header); err != nil {
+			return err
+		}
+
+		if !info.Mode().IsRegular() {
+			return nil
+		}
+
+		f, err := os.Open(file)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+
+		_, err = io.Copy(archive, f)
+		return err
+	})
+	if err != nil {
+		return errors.Wrapf(err, "Failed to archive path [%s]", path)
+	}
+	return archive.Close()
+}
+
+// ExtractArchive extracts tar archive to the destination path inside the root directory.
+// Like with `docker cp`, if the destination is existing directory, the archive is extracted into it.
+// Otherwise the archive top level entry is extracted with the destination name.
+// The destination parent directory must exist.
+// File mode is preserved, ownership is preserved when running as root.
+func ExtractArchive(root, dest string, r io.Reader) error {
+	target, err := resolveInRoot(root, dest)
+	if err != nil {
+		return err
+	}
+
+	rename := ""
+	info, err := os.Lstat(target)
+	switch {
+	case err == nil && info.IsDir():
+		// extract into the directory
+	case err == nil || os.IsNotExist(err):
+		parent := filepath.Dir(target)
+		parentInfo, err := os.Stat(parent)
+		if err != nil {
+			if os.IsNotExist(err) {
+				return ErrWithMessagef(ErrNotFound, "Cannot copy to [%s], parent directory [%s] does not exist", dest, filepath.Dir(filepath.Clean("/"+dest)))
+			}
+			return errors.Wrapf(err, "Failed to read destination [%s] parent directory", dest)
+		}
+		if !parentInfo.IsDir() {
+			return ErrWithMessagef(ErrNotFound, "Cannot copy to [%s], parent [%s] is not a directory", dest, filepath.Dir(filepath.Clean("/"+dest)))
+		}
+		rename = filepath.Base(target)
+		target = parent
+	default:
+		return errors.Wrapf(err, "Failed to read destination [%s]", dest)
+	}
+
+	archive := tar.NewReader(r)
+	for {
+		header, err := archive.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return errors.Wrapf(err, "Failed to read archive")
+		}
+
+		name := strings.TrimPrefix(filepath.Clean("/"+header.Name), "/")
+		if name == "" {
+			continue
+		}
+		if rename != "" {
+			parts := strings.SplitN(name, "/", 2)
+			parts[0] = rename
+			name = strings.Join(parts, "/")
+		}
+
+		path, err := resolveInRoot(target, name)
+		if err != nil {
+			return err
+		}
+
+		if err := extractEntry(path, header, archive); err != nil {
+			return errors.Wrapf(err, "Failed to extract [%s]", header.Name)
+		}
+	}
+}
+
+// RemovePaths removes the paths relative to the destination path inside the root directory,
+// e.g. the files what got deleted from the synced directory. Paths which don't exist are skipped.
+func RemovePaths(root, dest string, paths []string) error {
+	for _, p := range paths {
+		name := strings.TrimPrefix(filepath.Clean("/"+p), "/")
+		if name == "" {
+			return ErrWithMessagef(ErrNotSupported, "Cannot remove the destination [%s] itself", dest)
+		}
+
+		target, err := resolveInRoot(root, filepath.Join(dest, name))
+		if err != nil {
+			return err
+		}
+		if err := os.RemoveAll(target); err != nil {
+			return errors.Wrapf(err, "Failed to remove [%s]", p)
+		}
+	}
+	return nil
+}
+
+func extractEntry(path string, header *tar.Header, r io.Reader) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+
+	mode := header.FileInfo().Mode()
+	switch header.Typeflag {
+	case tar.TypeDir:
+		info, err := os.Lstat(path)
+		if err == nil && !info.IsDir() {
+			return errors.Errorf("Cannot overwrite file [%s] with directory", path)
+		}
+		if os.IsNotExist(err) {
+			if err := os.Mkdir(path, mode.Perm()); err != nil {
+				return err
+			}
+		}
+	case tar.TypeReg, tar.TypeRegA:
+		if err := removeNonDir(path); err != nil {
+			return err
+		}
+		f, err := os.OpenFile(path, os.O_CREATE|os.O_EXCL|os.O_WRONLY, mode.Perm())
+		if err != nil {
+			return err
+		}
+		_, err = io.Copy(f, r)
+		f.Close()
+		if err != nil {
+			return err
+		}
+	case tar.TypeSymlink:
+		if err := removeNonDir(path); err != nil {
+			return err
+		}
+		if err := os.Symlink(header.Linkname, path); err != nil {
+			return err
+		}
+	default:
+		log.Debugf("Skip unsupported archive entry [%s] type [%c]", header.Name, header.Typeflag)
+		return nil
+	}
+
+	if err := os.Lchown(path, header.Uid, header.Gid); err != nil && !os.IsPermission(err) {
+		return err
+	}
+	if header.Typeflag != tar.TypeSymlink {
+		return os.Chmod(path, mode.Perm())
+	}
+	return nil
+}
+
+// removeNonDir removes existing file or symlink so that it can be replaced, and never write through a symlink
+func removeNonDir(path string) error {
+	info, err := os.Lstat(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+	if info.IsDir() {
+		return errors.Errorf("Cannot overwrite directory [%s] with file", path)
+	}
+	return os.Remove(path)
+}
+
+// resolveInRoot joins the path to the root so that it cannot point outside of the root.
+// The path cannot go through symlinks, because in container root they would resolve to the host filesystem.
+func resolveInRoot(root, path string) (string, error) {
+	parts := strings.Split(strings.TrimPrefix(filepath.Clean("/"+path), "/"), "/")
+
+	current := root
+	for i, part := range parts {
+		if part == "" {
+			continue
+		}
+		current = filepath.Join(current, part)
+		if i == len(parts)-1 {
+			break
+		}
+
+		info, err := os.Lstat(current)
+		if err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+			return "", errors.Wrapf(err, "Failed to resolve path [%s]", path)
+		}
+		if info.Mode()&os.ModeSymlink != 0 {
+			return "", ErrWithMessagef(ErrNotSupported, "Path [%s] goes through symlink, which is not supported", path)
+		}
+	}
+	return current, nil
+}
@@ -0,0 +1,108 @@
+package runtime
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCopyDirectoryBetweenRoots(t *testing.T) {
+	source, _ := ioutil.TempDir("", "source")
+	defer os.RemoveAll(source)
+	target, _ := ioutil.TempDir("", "target")
+	defer os.RemoveAll(target)
+
+	assert.NoError(t, os.MkdirAll(filepath.Join(source, "etc", "app"), 0755))
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(source, "etc", "app", "config.yml"), []byte("foo: bar"), 0600))
+	assert.NoError(t, os.Mkdir(filepath.Join(target, "etc"), 0755))
+
+	archive := &bytes.Buffer{}
+	assert.NoError(t, CreateArchive(source, "/etc/app", archive))
+	assert.NoError(t, ExtractArchive(target, "/etc", archive))
+
+	data, err := ioutil.ReadFile(filepath.Join(target, "etc", "app", "config.yml"))
+	assert.NoError(t, err)
+	assert.Equal(t, "foo: bar", string(data))
+
+	info, err := os.Stat(filepath.Join(target, "etc", "app", "config.yml"))
+	assert.NoError(t, err)
+	assert.Equal(t, os.FileMode(0600), info.Mode().Perm(), "should preserve file mode")
+}
+
+func TestCopyFileWithNewName(t *testing.T) {
+	source, _ := ioutil.TempDir("", "source")
+	defer os.RemoveAll(source)
+	target, _ := ioutil.TempDir("", "target")
+	defer os.RemoveAll(target)
+
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(source, "file.txt"), []byte("hello"), 0644))
+
+	archive := &bytes.Buffer{}
+	assert.NoError(t, CreateArchive(source, "file.txt", archive))
+	assert.NoError(t, ExtractArchive(target, "/renamed.txt", archive))
+
+	data, err := ioutil.ReadFile(filepath.Join(target, "renamed.txt"))
+	assert.NoError(t, err)
+	assert.Equal(t, "hello", string(data))
+}
+
+func TestExtractArchiveParentMissing(t *testing.T) {
+	source, _ := ioutil.TempDir("", "source")
+	defer os.RemoveAll(source)
+	target, _ := ioutil.TempDir("", "target")
+	defer os.RemoveAll(target)
+
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(source, "file.txt"), []byte("hello"), 0644))
+	archive := &bytes.Buffer{}
+	assert.NoError(t, CreateArchive(source, "file.txt", archive))
+
+	err := ExtractArchive(target, "/not/exist/file.txt", archive)
+	assert.Error(t, err)
+	assert.True(t, IsNotFound(err))
+	assert.Contains(t, err.Error(), "parent directory [/not/exist] does not exist")
+
+	_, err = os.Stat(filepath.Join(target, "not"))
+	assert.True(t, os.IsNotExist(err), "should not create the missing parent directory")
+}
+
+func TestCreateArchiveNotFound(t *testing.T) {
+	source, _ := ioutil.TempDir("", "source")
+	defer os.RemoveAll(source)
+
+	err := CreateArchive(source, "/not-exist", &bytes.Buffer{})
+	assert.True(t, IsNotFound(err))
+}
+
+func TestResolveInRootDontFollowSymlinks(t *testing.T) {
+	root, _ := ioutil.TempDir("", "root")
+	defer os.RemoveAll(root)
+	assert.NoError(t, os.Symlink("/etc", filepath.Join(root, "link")))
+
+	_, err := resolveInRoot(root, "/link/passwd")
+	assert.Error(t, err)
+
+	path, err := resolveInRoot(root, "../../foo")
+	assert.NoError(t, err)
+	assert.Equal(t, filepath.Join(root, "foo"), path)
+}
+
+func TestRemovePaths(t *testing.T) {
+	root, _ := ioutil.TempDir("", "root")
+	defer os.RemoveAll(root)
+	assert.NoError(t, os.MkdirAll(filepath.Join(root, "app", "static"), 0755))
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(root, "app", "main.py"), []byte("print()"), 0644))
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(root, "app", "static", "index.html"), []byte("<html>"), 0644))
+	assert.NoError(t, os.Symlink("/etc", filepath.Join(root, "app", "link")))
+
+	assert.NoError(t, RemovePaths(root, "/app", []string{"static", "main.py", "link", "not-exist"}))
+
+	files, err := ioutil.ReadDir(filepath.Join(root, "app"))
+	assert.NoError(t, err)
+	assert.Empty(t, files)
+
+	assert.Error(t, RemovePaths(root, "/app", []string{"../"}), "should not remove the destination itself")
+}
@@ -0,0 +1,305 @@
+package runtime
+
+import (
+	"archive/tar"
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/containerd/containerd"
+	"github.com/containerd/containerd/content"
+	"github.com/containerd/containerd/errdefs"
+	"github.com/containerd/containerd/images"
+	digest "github.com/opencontainers/go-digest"
+	imagespecs "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+)
+
+// BuildImageLabel is the containerd image label of the images built in the node.
+// The built images don't exist in any registry, so they are not pulled when pod uses them.
+const BuildImageLabel = "eliot.build"
+
+// buildctl is the BuildKit client which runs the builds in the node,
+// set BUILDKIT_HOST environment variable if buildkitd doesn't listen the default address
+const buildctl = "buildctl"
+
+// buildErrorPattern matches the BuildKit plain progress lines which tell why the build failed
+var buildErrorPattern = regexp.MustCompile(`^(#\d+ )?ERROR|^error:`)
+
+// BuildOptions defines how the image get built from the Dockerfile in the build context root
+type BuildOptions struct {
+	// Tags are the image references, e.g. docker.io/library/myapp:latest, at least one is required
+	Tags []string
+	// BuildArgs are the Dockerfile ARG values
+	BuildArgs map[string]string
+	// Target is the Dockerfile stage to build, empty builds the last stage
+	Target string
+}
+
+// BuildLog is single line of the build output
+type BuildLog struct {
+	Line string
+	// Error is true for the lines which tell why the build failed
+	Error bool
+}
+
+// BuildImage builds image from the build context tar archive with BuildKit and stores it with the tags
+// to the namespace. The build output lines are passed to the handler while the build runs.
+// Returns the image manifest digest, or ErrNotSupported if the node doesn't have BuildKit installed.
+func (c *ContainerdClient) BuildImage(namespace string, buildContext io.Reader, opts BuildOptions, done <-chan struct{}, handler func(BuildLog) error) (string, error) {
+	if len(opts.Tags) == 0 {
+		return "", errors.New("Image build requires at least one tag")
+	}
+	if _, err := exec.LookPath(buildctl); err != nil {
+		return "", ErrWithMessagef(ErrNotSupported, "Cannot build image, the node doesn't have BuildKit [%s] installed", buildctl)
+	}
+
+	dir, err := ioutil.TempDir("", "eliot-build")
+	if err != nil {
+		return "", errors.Wrap(err, "Failed to create temporary build directory")
+	}
+	defer os.RemoveAll(dir)
+
+	contextDir := filepath.Join(dir, "context")
+	if err := os.Mkdir(contextDir, 0755); err != nil {
+		return "", errors.Wrap(err, "Failed to create build context directory")
+	}
+	if err := ExtractArchive(contextDir, "/", buildContext); err != nil {
+		return "", errors.Wrap(err, "Failed to extract build context")
+	}
+	if _, err := os.Stat(filepath.Join(contextDir, "Dockerfile")); err != nil {
+		return "", ErrWithMessagef(ErrNotFound, "Build context doesn't contain Dockerfile in the root")
+	}
+
+	ctx, cancel := context.WithCancel(c.context)
+	defer cancel()
+	go func() {
+		select {
+		case <-done:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	archive := filepath.Join(dir, "image.tar")
+	if err := runBuildctl(ctx, getBuildctlArgs(contextDir, archive, opts), handler); err != nil {
+		return "", err
+	}
+
+	client, err := c.getConnection(namespace)
+	if err != nil {
+		return "", err
+	}
+
+	f, err := os.Open(archive)
+	if err != nil {
+		return "", errors.Wrap(err, "Failed to open built image archive")
+	}
+	defer f.Close()
+
+	ctx, leaseDone, err := client.WithLease(ctx)
+	if err != nil {
+		return "", errors.Wrap(err, "Failed to create lease for image import")
+	}
+	defer leaseDone(ctx)
+
+	target, err := importOCIArchive(ctx, client.ContentStore(), f)
+	if err != nil {
+		return "", errors.Wrap(err, "Failed to import built image")
+	}
+
+	stored := []images.Image{}
+	for _, name := range getBuildImageNames(opts.Tags, target.Digest) {
+		img, err := storeImage(ctx, client, images.Image{
+			Name:   name,
+			Target: target,
+			Labels: map[string]string{BuildImageLabel: "true"},
+		})
+		if err != nil {
+			return "", errors.Wrapf(err, "Failed to store built image [%s]", name)
+		}
+		stored = append(stored, img)
+	}
+
+	// All names point to the same content, so unpacking one is enough
+	if err := containerd.NewImage(client, stored[0]).Unpack(ctx, c.snapshotter); err != nil {
+		return "", errors.Wrapf(err, "Error while unpacking image [%s] to namespace [%s]", stored[0].Name, namespace)
+	}
+
+	log.Debugf("Image %v built to namespace [%s]: %s", opts.Tags, namespace, target.Digest)
+	return target.Digest.String(), nil
+}
+
+// isBuiltImage return true if the image was built in the node
+func isBuiltImage(ctx context.Context, client *containerd.Client, ref string) bool {
+	img, err := client.ImageService().Get(ctx, ref)
+	return err == nil && img.Labels[BuildImageLabel] == "true"
+}
+
+func getBuildctlArgs(contextDir, archive string, opts BuildOptions) []string {
+	args := []string{
+		"build",
+		"--progress=plain",
+		"--frontend=dockerfile.v0",
+		"--local", "context=" + contextDir,
+		"--local", "dockerfile=" + contextDir,
+		"--output", "type=oci,dest=" + archive,
+	}
+	if opts.Target != "" {
+		args = append(args, "--opt", "target="+opts.Target)
+	}
+
+	names := make([]string, 0, len(opts.BuildArgs))
+	for name := range opts.BuildArgs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		args = append(args, "--opt", fmt.Sprintf("build-arg:%s=%s", name, opts.BuildArgs[name]))
+	}
+	return args
+}
+
+// runBuildctl runs the build and passes the output lines to the handler, the lines after the first error
+// line are marked as errors
+func runBuildctl(ctx context.Context, args []string, handler func(BuildLog) error) error {
+	cmd := exec.CommandContext(ctx, buildctl, args...)
+	r, w := io.Pipe()
+	cmd.Stdout = w
+	cmd.Stderr = w
+
+	log.Debugf("Run %s %s", buildctl, strings.Join(args, " "))
+	if err := cmd.Start(); err != nil {
+		return errors.Wrapf(err, "Failed to start image build")
+	}
+
+	waitErr := make(chan error, 1)
+	go func() {
+		err := cmd.Wait()
+		w.Close()
+		waitErr <- err
+	}()
+
+	failure, handlerErr := readBuildLogs(r, handler)
+	if handlerErr != nil {
+		if cmd.Process != nil {
+			cmd.Process.Kill()
+		}
+		r.Close()
+		<-waitErr
+		return handlerErr
+	}
+
+	if err := <-waitErr; err != nil {
+		if ctx.Err() != nil {
+			return ErrWithMessagef(context.Canceled, "Image build cancelled")
+		}
+		if failure == "" {
+			failure = err.Error()
+		}
+		return errors.Errorf("Image build failed: %s", failure)
+	}
+	return nil
+}
+
+// readBuildLogs passes the output lines to the handler and return the first error line
+func readBuildLogs(r io.Reader, handler func(BuildLog) error) (failure string, err error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	failed := false
+	for scanner.Scan() {
+		line := scanner.Text()
+		isError := failed || buildErrorPattern.MatchString(line)
+		if strings.HasPrefix(line, "error:") {
+			// The final error is the last thing BuildKit prints
+			failed = true
+		}
+		if isError && failure == "" {
+			failure = strings.TrimSpace(line)
+		}
+		if err := handler(BuildLog{Line: line, Error: isError}); err != nil {
+			return failure, err
+		}
+	}
+	return failure, scanner.Err()
+}
+
+// importOCIArchive writes the blobs in OCI image layout tar archive to the content store
+// and return the image manifest descriptor
+func importOCIArchive(ctx context.Context, store content.Store, r io.Reader) (imagespecs.Descriptor, error) {
+	var index *imagespecs.Index
+	archive := tar.NewReader(r)
+	for {
+		header, err := archive.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return imagespecs.Descriptor{}, errors.Wrap(err, "Failed to read image archive")
+		}
+		if header.Typeflag != tar.TypeReg && header.Typeflag != tar.TypeRegA {
+			continue
+		}
+
+		name := strings.TrimPrefix(filepath.ToSlash(filepath.Clean(header.Name)), "./")
+		switch {
+		case name == "index.json":
+			index = &imagespecs.Index{}
+			if err := json.NewDecoder(archive).Decode(index); err != nil {
+				return imagespecs.Descriptor{}, errors.Wrap(err, "Invalid image archive index.json")
+			}
+		case strings.HasPrefix(name, "blobs/"):
+			parts := strings.Split(name, "/")
+			if len(parts) != 3 {
+				continue
+			}
+			dgst := digest.NewDigestFromHex(parts[1], parts[2])
+			if err := dgst.Validate(); err != nil {
+				return imagespecs.Descriptor{}, errors.Wrapf(err, "Invalid blob [%s] in image archive", name)
+			}
+			if err := content.WriteBlob(ctx, store, "build-"+dgst.String(), archive, header.Size, dgst); err != nil {
+				return imagespecs.Descriptor{}, errors.Wrapf(err, "Failed to write blob [%s]", dgst)
+			}
+		}
+	}
+
+	if index == nil {
+		return imagespecs.Descriptor{}, errors.New("Image archive doesn't contain index.json")
+	}
+	for _, desc := range index.Manifests {
+		switch desc.MediaType {
+		case imagespecs.MediaTypeImageManifest, imagespecs.MediaTypeImageIndex, images.MediaTypeDockerSchema2Manifest, images.MediaTypeDockerSchema2ManifestList:
+			return desc, nil
+		}
+	}
+	return imagespecs.Descriptor{}, errors.New("Image archive doesn't contain image manifest")
+}
+
+// getBuildImageNames return the image names for the tags, and the tags pinned to the digest, e.g.
+// docker.io/library/myapp:latest@sha256:..., so that the pod can use the exact built image
+func getBuildImageNames(tags []string, dgst digest.Digest) []string {
+	result := append([]string{}, tags...)
+	for _, tag := range tags {
+		result = append(result, tag+"@"+dgst.String())
+	}
+	return result
+}
+
+// storeImage creates the image, or updates the existing image to point to the new target
+func storeImage(ctx context.Context, client *containerd.Client, img images.Image) (images.Image, error) {
+	created, err := client.ImageService().Create(ctx, img)
+	if err == nil || !errdefs.IsAlreadyExists(err) {
+		return created, err
+	}
+	return client.ImageService().Update(ctx, img, "target", "labels")
+}
@@ -0,0 +1,63 @@
+package runtime
+
+import (
+	"strings"
+	"testing"
+
+	digest "github.com/opencontainers/go-digest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReadBuildLogsMarksErrorLines(t *testing.T) {
+	output := strings.Join([]string{
+		"#1 [internal] load build definition from Dockerfile",
+		"#5 [2/2] RUN make",
+		"#5 0.215 make: *** No targets specified and no makefile found.  Stop.",
+		"#5 ERROR: executor failed running [/bin/sh -c make]: exit code: 2",
+		"error: failed to solve: rpc error: code = Unknown desc = executor failed running [/bin/sh -c make]: exit code: 2",
+		"details of the failure",
+	}, "\n")
+
+	logs := []BuildLog{}
+	failure, err := readBuildLogs(strings.NewReader(output), func(log BuildLog) error {
+		logs = append(logs, log)
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "#5 ERROR: executor failed running [/bin/sh -c make]: exit code: 2", failure)
+
+	assert.Len(t, logs, 6)
+	assert.False(t, logs[0].Error)
+	assert.False(t, logs[2].Error, "command output is not error")
+	assert.True(t, logs[3].Error)
+	assert.True(t, logs[4].Error)
+	assert.True(t, logs[5].Error, "lines after the final error belong to the error")
+}
+
+func TestGetBuildctlArgs(t *testing.T) {
+	args := getBuildctlArgs("/tmp/context", "/tmp/image.tar", BuildOptions{
+		Target:    "release",
+		BuildArgs: map[string]string{"VERSION": "1.0", "ARCH": "arm64"},
+	})
+	assert.Equal(t, []string{
+		"build",
+		"--progress=plain",
+		"--frontend=dockerfile.v0",
+		"--local", "context=/tmp/context",
+		"--local", "dockerfile=/tmp/context",
+		"--output", "type=oci,dest=/tmp/image.tar",
+		"--opt", "target=release",
+		"--opt", "build-arg:ARCH=arm64",
+		"--opt", "build-arg:VERSION=1.0",
+	}, args)
+}
+
+func TestGetBuildImageNames(t *testing.T) {
+	dgst := digest.FromString("image")
+	assert.Equal(t, []string{
+		"docker.io/library/myapp:latest",
+		"localhost:5000/myapp:1.0",
+		"docker.io/library/myapp:latest@" + dgst.String(),
+		"localhost:5000/myapp:1.0@" + dgst.String(),
+	}, getBuildImageNames([]string{"docker.io/library/myapp:latest", "localhost:5000/myapp:1.0"}, dgst))
+}
@@ -0,0 +1,274 @@
+package runtime
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/containerd/containerd"
+	"github.com/containerd/containerd/content"
+	"github.com/containerd/containerd/errdefs"
+	"github.com/containerd/containerd/images"
+	"github.com/ernoaapa/eliot/pkg/model"
+	opts "github.com/ernoaapa/eliot/pkg/runtime/containerd"
+	"github.com/ernoaapa/eliot/pkg/runtime/containerd/mapping"
+	imagespecs "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+)
+
+// CheckpointManifestFile is the file in the checkpoint directory which describes the checkpoint
+const CheckpointManifestFile = "checkpoint.json"
+
+// CheckpointManifest describes the container checkpoint and the blobs, stored in blobs/<algorithm>/<hex>
+// in the checkpoint directory
+type CheckpointManifest struct {
+	ContainerID string
+	Image       string
+	CreatedAt   time.Time
+	Blobs       []imagespecs.Descriptor
+}
+
+// CheckpointContainer checkpoints the running container memory state and writable layer with CRIU to the directory.
+// The container keeps running after the checkpoint.
+// Returns ErrNotRunning if the container is not running and ErrNotSupported if the node doesn't support CRIU.
+func (c *ContainerdClient) CheckpointContainer(namespace, name, dir string) error {
+	ctx, cancel := c.getContext()
+	defer cancel()
+
+	client, err := c.getConnection(namespace)
+	if err != nil {
+		return err
+	}
+
+	container, err := client.LoadContainer(ctx, name)
+	if err != nil {
+		if errdefs.IsNotFound(err) {
+			return ErrWithMessagef(ErrNotFound, "Container [%s] not found", name)
+		}
+		return errors.Wrapf(err, "Failed to load container [%s], cannot checkpoint it", name)
+	}
+
+	task, err := container.Task(ctx, nil)
+	if err != nil {
+		if errdefs.IsNotFound(err) {
+			return ErrWithMessagef(ErrNotRunning, "Container [%s] is not running", name)
+		}
+		return errors.Wrapf(err, "Unable to get task in container [%s], cannot checkpoint it", name)
+	}
+
+	ctx, done, err := client.WithLease(ctx)
+	if err != nil {
+		return errors.Wrapf(err, "Failed to create lease for container [%s] checkpoint", name)
+	}
+	defer done(ctx)
+
+	checkpoint, err := task.Checkpoint(ctx)
+	if err != nil {
+		if isCheckpointUnsupported(err) {
+			return ErrWithMessagef(ErrNotSupported, "Cannot checkpoint container [%s], the node doesn't support CRIU: %s", name, err)
+		}
+		return errors.Wrapf(err, "Failed to checkpoint container [%s]", name)
+	}
+	defer func() {
+		if err := client.ImageService().Delete(ctx, checkpoint.Name()); err != nil {
+			log.Warnf("Failed to delete container [%s] checkpoint image [%s]: %s", name, checkpoint.Name(), err)
+		}
+	}()
+
+	data, err := content.ReadBlob(ctx, client.ContentStore(), checkpoint.Target().Digest)
+	if err != nil {
+		return errors.Wrapf(err, "Failed to read container [%s] checkpoint index", name)
+	}
+	var index imagespecs.Index
+	if err := json.Unmarshal(data, &index); err != nil {
+		return errors.Wrapf(err, "Invalid container [%s] checkpoint index", name)
+	}
+
+	info, err := container.Info(ctx)
+	if err != nil {
+		return errors.Wrap(err, "Error while fetching container info")
+	}
+
+	manifest := CheckpointManifest{
+		ContainerID: name,
+		Image:       info.Image,
+		CreatedAt:   time.Now(),
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return errors.Wrapf(err, "Failed to create checkpoint directory [%s]", dir)
+	}
+	for _, desc := range index.Manifests {
+		switch desc.MediaType {
+		case images.MediaTypeContainerd1Checkpoint, images.MediaTypeContainerd1RW:
+		default:
+			// The image and runtime config are restored from the existing container
+			continue
+		}
+		if err := writeCheckpointBlob(ctx, client, dir, desc); err != nil {
+			return errors.Wrapf(err, "Failed to write container [%s] checkpoint to [%s]", name, dir)
+		}
+		manifest.Blobs = append(manifest.Blobs, desc)
+	}
+
+	data, err = json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return errors.Wrapf(err, "Failed to serialize container [%s] checkpoint manifest", name)
+	}
+	return ioutil.WriteFile(filepath.Join(dir, CheckpointManifestFile), data, 0644)
+}
+
+// RestoreContainer replaces the container task with the process restored from the checkpoint directory,
+// the checkpoint writable layer changes get applied to the container filesystem first.
+// Returns ErrNotSupported if the node doesn't support CRIU.
+func (c *ContainerdClient) RestoreContainer(namespace, name, dir string, ioSet IOSet) (result model.ContainerStatus, err error) {
+	manifest, err := ReadCheckpointManifest(dir)
+	if err != nil {
+		return result, err
+	}
+
+	ctx, cancel := c.getContext()
+	defer cancel()
+
+	client, err := c.getConnection(namespace)
+	if err != nil {
+		return result, err
+	}
+
+	container, err := client.LoadContainer(ctx, name)
+	if err != nil {
+		if errdefs.IsNotFound(err) {
+			return result, ErrWithMessagef(ErrNotFound, "Container [%s] not found", name)
+		}
+		return result, errors.Wrapf(err, "Failed to load container [%s], cannot restore it", name)
+	}
+
+	info, err := container.Info(ctx)
+	if err != nil {
+		return result, errors.Wrap(err, "Error while fetching container info")
+	}
+
+	ctx, done, err := client.WithLease(ctx)
+	if err != nil {
+		return result, errors.Wrapf(err, "Failed to create lease for container [%s] restore", name)
+	}
+	defer done(ctx)
+
+	var checkpoint *imagespecs.Descriptor
+	for i, desc := range manifest.Blobs {
+		if err := readCheckpointBlob(ctx, client, dir, desc); err != nil {
+			return result, errors.Wrapf(err, "Failed to read container [%s] checkpoint from [%s]", name, dir)
+		}
+		if desc.MediaType == images.MediaTypeContainerd1Checkpoint {
+			checkpoint = &manifest.Blobs[i]
+		}
+	}
+	if checkpoint == nil {
+		return result, errors.Errorf("Checkpoint in [%s] doesn't contain the container memory state", dir)
+	}
+
+	// The old process must be gone before the filesystem changes get applied
+	if task, err := container.Task(ctx, nil); err == nil {
+		if _, err := task.Delete(ctx, containerd.WithProcessKill); err != nil && !errdefs.IsNotFound(err) {
+			return result, errors.Wrapf(err, "Failed to delete container [%s] task before restore", name)
+		}
+	} else if !errdefs.IsNotFound(err) {
+		return result, errors.Wrapf(err, "Error while resolving container task status")
+	}
+
+	for _, desc := range manifest.Blobs {
+		if desc.MediaType != images.MediaTypeContainerd1RW {
+			continue
+		}
+		mounts, err := client.SnapshotService(info.Snapshotter).Mounts(ctx, info.SnapshotKey)
+		if err != nil {
+			return result, errors.Wrapf(err, "Failed to resolve container [%s] snapshot mounts", name)
+		}
+		if _, err := client.DiffService().Apply(ctx, desc, mounts); err != nil {
+			return result, errors.Wrapf(err, "Failed to apply checkpoint writable layer to container [%s]", name)
+		}
+	}
+
+	if err := c.startTask(ctx, namespace, container, info, ioSet, opts.WithTaskCheckpoint(*checkpoint)); err != nil {
+		if isCheckpointUnsupported(errors.Cause(err)) {
+			return result, ErrWithMessagef(ErrNotSupported, "Cannot restore container [%s], the node doesn't support CRIU: %s", name, err)
+		}
+		return result, err
+	}
+
+	return mapping.MapContainerStatusToInternalModel(info, resolveContainerStatus(ctx, container)), nil
+}
+
+// ReadCheckpointManifest reads the checkpoint manifest in the checkpoint directory
+func ReadCheckpointManifest(dir string) (manifest CheckpointManifest, err error) {
+	data, err := ioutil.ReadFile(filepath.Join(dir, CheckpointManifestFile))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return manifest, ErrWithMessagef(ErrNotFound, "Directory [%s] doesn't contain checkpoint", dir)
+		}
+		return manifest, errors.Wrapf(err, "Failed to read checkpoint manifest in [%s]", dir)
+	}
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return manifest, errors.Wrapf(err, "Invalid checkpoint manifest in [%s]", dir)
+	}
+	return manifest, nil
+}
+
+// getCheckpointBlobPath return the blob file path in the checkpoint directory
+func getCheckpointBlobPath(dir string, desc imagespecs.Descriptor) string {
+	return filepath.Join(dir, "blobs", desc.Digest.Algorithm().String(), desc.Digest.Hex())
+}
+
+func writeCheckpointBlob(ctx context.Context, client *containerd.Client, dir string, desc imagespecs.Descriptor) error {
+	ra, err := client.ContentStore().ReaderAt(ctx, desc.Digest)
+	if err != nil {
+		return err
+	}
+	defer ra.Close()
+
+	path := getCheckpointBlobPath(dir, desc)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	_, err = io.Copy(f, content.NewReader(ra))
+	return err
+}
+
+func readCheckpointBlob(ctx context.Context, client *containerd.Client, dir string, desc imagespecs.Descriptor) error {
+	f, err := os.Open(getCheckpointBlobPath(dir, desc))
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	return content.WriteBlob(ctx, client.ContentStore(), "checkpoint-"+desc.Digest.String(), f, desc.Size, desc.Digest)
+}
+
+// isCheckpointUnsupported return true if the error tells that the runtime cannot checkpoint or restore
+// because CRIU is missing or too old
+func isCheckpointUnsupported(err error) bool {
+	if err == nil {
+		return false
+	}
+	if errdefs.IsNotImplemented(err) {
+		return true
+	}
+	message := strings.ToLower(err.Error())
+	if !strings.Contains(message, "criu") {
+		return false
+	}
+	return strings.Contains(message, "not found") ||
+		strings.Contains(message, "no such file") ||
+		strings.Contains(message, "version")
+}
@@ -0,0 +1,36 @@
+package runtime
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/containerd/containerd/errdefs"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReadCheckpointManifest(t *testing.T) {
+	dir, err := ioutil.TempDir("", "checkpoint-test")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	_, err = ReadCheckpointManifest(dir)
+	assert.True(t, IsNotFound(err), "should return ErrNotFound if the directory doesn't contain checkpoint")
+
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(dir, CheckpointManifestFile), []byte(`{"ContainerID":"foo","Image":"docker.io/library/redis:latest"}`), 0644))
+	manifest, err := ReadCheckpointManifest(dir)
+	assert.NoError(t, err)
+	assert.Equal(t, "foo", manifest.ContainerID)
+	assert.Equal(t, "docker.io/library/redis:latest", manifest.Image)
+}
+
+func TestIsCheckpointUnsupported(t *testing.T) {
+	assert.True(t, isCheckpointUnsupported(errors.Wrap(errdefs.ErrNotImplemented, "checkpoint")))
+	assert.True(t, isCheckpointUnsupported(errors.New(`exec: "criu": executable file not found in $PATH`)))
+	assert.True(t, isCheckpointUnsupported(errors.New("CRIU version check failed")))
+	assert.False(t, isCheckpointUnsupported(errors.New("criu failed: type NOTIFY errno 0")))
+	assert.False(t, isCheckpointUnsupported(errors.New("container not found")))
+	assert.False(t, isCheckpointUnsupported(nil))
+}
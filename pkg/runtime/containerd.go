@@ -3,14 +3,20 @@ package runtime
 import (
 	"context"
 	"fmt"
+	"io"
+	"net"
+	"net/http"
 	"runtime"
+	"sort"
 	"strings"
 	"syscall"
 	"time"
 
 	"github.com/containerd/containerd"
 	tasks "github.com/containerd/containerd/api/services/tasks/v1"
+	tasktypes "github.com/containerd/containerd/api/types/task"
 	"github.com/containerd/containerd/cio"
+	"github.com/containerd/containerd/containers"
 	"github.com/containerd/containerd/errdefs"
 	"github.com/containerd/containerd/images"
 	"github.com/containerd/containerd/namespaces"
@@ -19,6 +25,7 @@ import (
 	"github.com/containerd/containerd/platforms"
 	"github.com/containerd/containerd/plugin"
 	"github.com/containerd/containerd/remotes"
+	"github.com/containerd/containerd/remotes/docker"
 	"github.com/ernoaapa/eliot/pkg/model"
 	"github.com/ernoaapa/eliot/pkg/progress"
 	opts "github.com/ernoaapa/eliot/pkg/runtime/containerd"
@@ -38,16 +45,75 @@ type ContainerdClient struct {
 	snapshotter string
 	address     string
 	hostname    string
+	logs        *LogStore
+	oom         *OOMStore
+	readiness   *ReadinessStore
+	events      *EventBroker
 }
 
+// eventsReconnectInterval is how long to wait before subscribing again to containerd events after failure
+const eventsReconnectInterval = 5 * time.Second
+
 // NewContainerdClient creates new containerd client with given timeout
 func NewContainerdClient(context context.Context, timeout time.Duration, snapshotter, address, hostname string) *ContainerdClient {
-	return &ContainerdClient{
+	client := &ContainerdClient{
 		context:     context,
 		timeout:     timeout,
 		address:     address,
 		snapshotter: snapshotter,
 		hostname:    hostname,
+		logs:        NewLogStore(DefaultLogBufferLines),
+		oom:         NewOOMStore(),
+		readiness:   NewReadinessStore(),
+		events:      NewEventBroker(),
+	}
+	go client.watchOOMEvents()
+	return client
+}
+
+// watchOOMEvents records the containers killed because out of memory until the client context get cancelled
+func (c *ContainerdClient) watchOOMEvents() {
+	for {
+		err := c.subscribeOOMEvents()
+		select {
+		case <-c.context.Done():
+			return
+		case <-time.After(eventsReconnectInterval):
+			log.Debugf("Subscription to containerd OOM events failed, subscribe again: %s", err)
+		}
+	}
+}
+
+func (c *ContainerdClient) subscribeOOMEvents() error {
+	client, err := c.getConnection(model.DefaultNamespace)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	ctx, cancel := context.WithCancel(c.context)
+	defer cancel()
+
+	envelopes, errs := client.Subscribe(ctx, fmt.Sprintf(`topic=="%s"`, opts.TaskOOMTopic))
+	for {
+		select {
+		case envelope, ok := <-envelopes:
+			if !ok {
+				return errors.New("Containerd closed the events stream")
+			}
+			if envelope.Event == nil {
+				continue
+			}
+			event, err := opts.UnmarshalTaskOOM(envelope.Event.Value)
+			if err != nil {
+				log.Warnf("Invalid OOM event from containerd: %s", err)
+				continue
+			}
+			log.Debugf("Container [%s] in namespace [%s] run out of memory", event.ContainerID, envelope.Namespace)
+			c.oom.Add(envelope.Namespace, event.ContainerID)
+		case err := <-errs:
+			return err
+		}
 	}
 }
 
@@ -90,6 +156,7 @@ func (c *ContainerdClient) GetPods(namespace string) ([]model.Pod, error) {
 		return nil, errors.Wrap(err, "Error while getting list of containers")
 	}
 
+	initContainers := map[string][]initContainer{}
 	for _, container := range containers {
 		info, err := container.Info(ctx)
 		if err != nil {
@@ -101,15 +168,157 @@ func (c *ContainerdClient) GetPods(namespace string) ([]model.Pod, error) {
 			pods[pod.Metadata.Name] = &pod
 		}
 
-		pods[podName].AppendContainer(
-			mapping.MapContainerToInternalModel(info),
-			mapping.MapContainerStatusToInternalModel(info, resolveContainerStatus(ctx, container)),
-		)
+		spec, status := mapping.MapContainerToInternalModel(info), c.getContainerStatus(ctx, namespace, container, info)
+		if index, ok := mapping.GetInitContainerIndex(info); ok {
+			initContainers[podName] = append(initContainers[podName], initContainer{index, spec, status})
+			continue
+		}
+		if len(pods[podName].Spec.Containers) == 0 {
+			// The pod might have been initialised from init container, which restart policy can differ
+			pods[podName].Spec.RestartPolicy = spec.RestartPolicy
+		}
+		pods[podName].AppendContainer(spec, status)
+	}
+
+	// The init containers run in the order of the pod spec, so keep them in that order
+	for podName, list := range initContainers {
+		sort.Slice(list, func(i, j int) bool { return list[i].index < list[j].index })
+		for _, init := range list {
+			pods[podName].AppendInitContainer(init.spec, init.status)
+		}
 	}
 
 	return getValues(pods), nil
 }
 
+// initContainer is init container by its position in the pod spec
+type initContainer struct {
+	index  int
+	spec   model.Container
+	status model.ContainerStatus
+}
+
+// GetContainerStatus return status of single container, without loading the other containers in the pod
+func (c *ContainerdClient) GetContainerStatus(namespace, name string) (model.ContainerStatus, error) {
+	ctx, cancel := c.getContext()
+	defer cancel()
+
+	client, err := c.getConnection(namespace)
+	if err != nil {
+		return model.ContainerStatus{}, err
+	}
+
+	container, err := client.LoadContainer(ctx, name)
+	if err != nil {
+		if errdefs.IsNotFound(err) {
+			return model.ContainerStatus{}, ErrWithMessagef(ErrNotFound, "Container [%s] not found", name)
+		}
+		return model.ContainerStatus{}, errors.Wrapf(err, "Failed to load container [%s]", name)
+	}
+
+	info, err := container.Info(ctx)
+	if err != nil {
+		return model.ContainerStatus{}, errors.Wrapf(err, "Error while fetching container [%s] info", name)
+	}
+	return c.getContainerStatus(ctx, namespace, container, info), nil
+}
+
+func (c *ContainerdClient) getContainerStatus(ctx context.Context, namespace string, container containerd.Container, info containers.Container) model.ContainerStatus {
+	status := mapping.MapContainerStatusToInternalModel(info, resolveContainerStatus(ctx, container))
+	if status.Reason != "" && c.oom.IsKilled(namespace, container.ID()) {
+		status.Reason = model.ReasonOOMKilled
+	}
+	status.Ready, status.ReadinessMessage = c.getReadiness(namespace, info, status)
+	return status
+}
+
+// getReadiness return true if the container is running and it doesn't have readiness probe,
+// or the probe has succeeded after the container was last started
+func (c *ContainerdClient) getReadiness(namespace string, info containers.Container, status model.ContainerStatus) (bool, string) {
+	if status.State != string(containerd.Running) {
+		return false, ""
+	}
+	if !mapping.HasReadinessProbe(info) {
+		return true, ""
+	}
+	result, ok := c.readiness.Get(namespace, info.ID, status.StartedAt)
+	if !ok {
+		return false, ""
+	}
+	return result.Ready, result.Message
+}
+
+// SetContainerReadiness stores the container readiness probe result, which the container status tells until
+// the next result or until the container restarts
+func (c *ContainerdClient) SetContainerReadiness(namespace, name string, result ProbeResult) {
+	c.readiness.Set(namespace, name, result)
+}
+
+// SetPodLabels replaces the labels of all pod containers, without recreating the containers
+func (c *ContainerdClient) SetPodLabels(namespace, podName string, labels map[string]string) error {
+	return c.patchPodContainers(namespace, podName, func(info containers.Container) (map[string]string, []string) {
+		return mapping.PodLabelsPatch(info.Labels, labels)
+	})
+}
+
+// SetPodCordoned marks all pod containers cordoned or not, without recreating the containers
+func (c *ContainerdClient) SetPodCordoned(namespace, podName string, cordoned bool) error {
+	return c.patchPodContainers(namespace, podName, func(info containers.Container) (map[string]string, []string) {
+		return mapping.PodCordonPatch(cordoned)
+	})
+}
+
+// RenamePod moves all pod containers to the pod with the new name, without recreating the containers
+func (c *ContainerdClient) RenamePod(namespace, podName, newName string) error {
+	return c.patchPodContainers(namespace, podName, func(info containers.Container) (map[string]string, []string) {
+		return mapping.PodNamePatch(newName)
+	})
+}
+
+// patchPodContainers updates the labels of each pod container. The patch return the label values and
+// the keys to update, keys without value get removed.
+func (c *ContainerdClient) patchPodContainers(namespace, podName string, patch func(info containers.Container) (map[string]string, []string)) error {
+	ctx, cancel := c.getContext()
+	defer cancel()
+
+	client, err := c.getConnection(namespace)
+	if err != nil {
+		return err
+	}
+
+	list, err := client.Containers(ctx)
+	if err != nil {
+		return errors.Wrap(err, "Error while getting list of containers")
+	}
+
+	found := false
+	for _, container := range list {
+		info, err := container.Info(ctx)
+		if err != nil {
+			return errors.Wrap(err, "Error while fetching container info")
+		}
+		if mapping.GetPodName(info) != podName {
+			continue
+		}
+		found = true
+
+		labels, keys := patch(info)
+		paths := make([]string, len(keys))
+		for i, key := range keys {
+			paths[i] = "labels." + key
+		}
+		_, err = client.ContainerService().Update(ctx, containers.Container{ID: info.ID, Labels: labels}, paths...)
+		if err != nil {
+			return errors.Wrapf(err, "Failed to update container [%s] labels", info.ID)
+		}
+	}
+
+	if !found {
+		return ErrWithMessagef(ErrNotFound, "Pod in namespace [%s] with name [%s] not found", namespace, podName)
+	}
+	return nil
+}
+
 func resolveContainerStatus(ctx context.Context, container containerd.Container) containerd.Status {
 	status := containerd.Status{}
 	task, err := container.Task(ctx, nil)
@@ -196,6 +405,8 @@ func (c *ContainerdClient) CreateContainer(pod model.Pod, container model.Contai
 		specOpts = append(specOpts, oci.WithHostNamespace(specs.PIDNamespace))
 	}
 
+	restartPolicy := model.GetRestartPolicy(pod, container.Name)
+
 	id := xid.New()
 	containerOpts := []containerd.NewContainerOpts{
 		containerd.WithContainerLabels(mapping.NewLabels(pod, container)),
@@ -203,7 +414,7 @@ func (c *ContainerdClient) CreateContainer(pod model.Pod, container model.Contai
 		containerd.WithSnapshotter(c.snapshotter),
 		containerd.WithNewSnapshot(id.String(), image),
 		containerd.WithRuntime(fmt.Sprintf("%s.%s", plugin.RuntimePlugin, "linux"), nil),
-		extensions.WithLifecycleExtension,
+		extensions.WithRestartPolicy(extensions.ParseRestartPolicy(restartPolicy)),
 	}
 
 	if container.Pipe != nil {
@@ -212,6 +423,12 @@ func (c *ContainerdClient) CreateContainer(pod model.Pod, container model.Contai
 		))
 	}
 
+	if container.ReadinessProbe != nil {
+		containerOpts = append(containerOpts, extensions.WithReadinessProbeExtension(
+			mapping.MapReadinessProbeToContainerdModel(*container.ReadinessProbe),
+		))
+	}
+
 	log.Debugf("Create new container from image %s...", image.Name())
 	created, err := client.NewContainer(
 		namespaceutils.WithNamespace(ctx, pod.Metadata.Namespace),
@@ -234,6 +451,17 @@ func (c *ContainerdClient) CreateContainer(pod model.Pod, container model.Contai
 func (c *ContainerdClient) StartContainer(namespace, id string, ioSet IOSet) (result model.ContainerStatus, err error) {
 	ctx, cancel := c.getContext()
 	defer cancel()
+	defer func() {
+		if err != nil {
+			c.events.Publish(Event{
+				Type:        EventContainerStartFailed,
+				Namespace:   namespace,
+				ContainerID: id,
+				Time:        time.Now(),
+				Message:     err.Error(),
+			})
+		}
+	}()
 
 	client, connectionErr := c.getConnection(namespace)
 	if connectionErr != nil {
@@ -250,42 +478,63 @@ func (c *ContainerdClient) StartContainer(namespace, id string, ioSet IOSet) (re
 		return result, errors.Wrap(err, "Error while fetching container info")
 	}
 
+	if err := c.startTask(ctx, namespace, container, info, ioSet); err != nil {
+		return result, err
+	}
+
+	return mapping.MapContainerStatusToInternalModel(info, resolveContainerStatus(ctx, container)), nil
+}
+
+// startTask replaces the container task with new one and starts it, the old task gets stopped first
+func (c *ContainerdClient) startTask(ctx context.Context, namespace string, container containerd.Container, info containers.Container, ioSet IOSet, taskOpts ...containerd.NewTaskOpts) error {
 	log.Debugf("Create task in container: %s", container.ID())
 	io, err := opts.NewDirectIO(ctx, ioSet.Stdin, ioSet.Stdout, ioSet.Stderr, mapping.RequireTty(info))
 	if err != nil {
-		return result, errors.Wrapf(err, "Error while creating container task IO")
+		return errors.Wrapf(err, "Error while creating container task IO")
 	}
 
 	if task, err := container.Task(ctx, nil); err != nil {
 		if !errdefs.IsNotFound(err) {
-			return result, errors.Wrapf(err, "Error while resolving container task status")
+			return errors.Wrapf(err, "Error while resolving container task status")
 		}
 	} else {
 		if err := ensureTaskStopped(ctx, task); err != nil {
-			return result, errors.Wrapf(err, "Failed to ensure task is stopped")
+			return errors.Wrapf(err, "Failed to ensure task is stopped")
 		}
 		if _, err := task.Delete(ctx); err != nil {
-			return result, errors.Wrapf(err, "Error while cleaning up old container task")
+			return errors.Wrapf(err, "Error while cleaning up old container task")
 		}
 	}
 
-	task, err := container.NewTask(ctx, io.IOCreate)
+	c.oom.Remove(namespace, container.ID())
+	task, err := container.NewTask(ctx, io.IOCreate, taskOpts...)
 	if err != nil {
-		return result, errors.Wrapf(err, "Error while creating task for container [%s]", container.ID())
+		return errors.Wrapf(err, "Error while creating task for container [%s]", container.ID())
 	}
 
 	log.Debugln("Starting task...")
 	err = task.Start(ctx)
 	if err != nil {
-		return result, errors.Wrapf(err, "Failed to start task in container [%s]", container.ID())
+		return errors.Wrapf(err, "Failed to start task in container [%s]", container.ID())
 	}
 	log.Debugf("Task started (pid %d)", task.Pid())
 
+	pipe, err := extensions.GetPipeExtension(info)
+	if err != nil {
+		return errors.Wrapf(err, "Failed to resolve container [%s] pipe configuration", container.ID())
+	}
+	if pipe != nil {
+		// Stdout is piped to another container stdin so it can't be read to logs
+		c.logs.Get(namespace, container.ID()).Capture(nil, io.Stderr)
+	} else {
+		c.logs.Get(namespace, container.ID()).Capture(io.Stdout, io.Stderr)
+	}
+
 	if err := container.Update(ctx, extensions.IncrementRestart); err != nil {
-		return result, errors.Wrapf(err, "Failed to increment container [%s] start counter", container.ID())
+		return errors.Wrapf(err, "Failed to increment container [%s] start counter", container.ID())
 	}
 
-	return mapping.MapContainerStatusToInternalModel(info, resolveContainerStatus(ctx, container)), nil
+	return nil
 }
 
 func ensureTaskStopped(ctx context.Context, task containerd.Task) error {
@@ -344,6 +593,9 @@ func (c *ContainerdClient) StopContainer(namespace, name string) (result model.C
 			return result, errors.Wrapf(err, "Failed to delete container [%s]", container.ID())
 		}
 	}
+	c.logs.Remove(namespace, name)
+	c.oom.Remove(namespace, name)
+	c.readiness.Remove(namespace, name)
 
 	return model.ContainerStatus{
 		ContainerID: info.ID,
@@ -376,8 +628,237 @@ func (c *ContainerdClient) Signal(namespace, name string, signal syscall.Signal)
 	return task.Kill(ctx, signal, containerd.WithKillAll)
 }
 
-// PullImage ensures that given container image is pulled to the namespace
-func (c *ContainerdClient) PullImage(namespace, ref string, progress *progress.ImageFetch) error {
+// SetContainerEnv sets the environment variables in the container spec, other variables are kept as is.
+// The running task keeps the environment it was started with, the new environment is used when the
+// container gets started next time, e.g. restarted by the restart policy.
+func (c *ContainerdClient) SetContainerEnv(namespace, name string, env map[string]string) error {
+	ctx, cancel := c.getContext()
+	defer cancel()
+
+	client, err := c.getConnection(namespace)
+	if err != nil {
+		return err
+	}
+
+	container, err := client.LoadContainer(ctx, name)
+	if err != nil {
+		if errdefs.IsNotFound(err) {
+			return ErrWithMessagef(ErrNotFound, "Container [%s] in namespace [%s] not found", name, namespace)
+		}
+		return errors.Wrapf(err, "Failed to load container [%s], cannot update environment", name)
+	}
+
+	if err := container.Update(ctx, opts.WithUpdatedEnv(env)); err != nil {
+		return errors.Wrapf(err, "Failed to update container [%s] environment", name)
+	}
+	return nil
+}
+
+// GetContainerStats returns the container task cgroup metrics.
+// Returns ErrNotRunning if the container task is not running, so exited container is not reported as idle.
+func (c *ContainerdClient) GetContainerStats(namespace, name string) (ContainerStats, error) {
+	ctx, cancel := c.getContext()
+	defer cancel()
+
+	client, err := c.getConnection(namespace)
+	if err != nil {
+		return ContainerStats{}, err
+	}
+
+	container, err := client.LoadContainer(ctx, name)
+	if err != nil {
+		if errdefs.IsNotFound(err) {
+			return ContainerStats{}, ErrWithMessagef(ErrNotFound, "Container [%s] not found", name)
+		}
+		return ContainerStats{}, errors.Wrapf(err, "Failed to load container [%s], cannot get stats", name)
+	}
+
+	task, err := container.Task(ctx, nil)
+	if err != nil {
+		if errdefs.IsNotFound(err) {
+			return ContainerStats{}, ErrWithMessagef(ErrNotRunning, "Container [%s] is not running", name)
+		}
+		return ContainerStats{}, errors.Wrapf(err, "Unable to get task in container [%s], cannot get stats", name)
+	}
+
+	status, err := task.Status(ctx)
+	if err != nil {
+		return ContainerStats{}, errors.Wrapf(err, "Failed to resolve container [%s] task status", name)
+	}
+	if status.Status != containerd.Running {
+		return ContainerStats{}, ErrWithMessagef(ErrNotRunning, "Container [%s] is not running (%s)", name, status.Status)
+	}
+
+	metric, err := task.Metrics(ctx)
+	if err != nil {
+		return ContainerStats{}, errors.Wrapf(err, "Failed to get container [%s] metrics", name)
+	}
+	if metric.Data == nil {
+		return ContainerStats{}, fmt.Errorf("Runtime didn't return metrics for container [%s]", name)
+	}
+
+	metrics, err := opts.UnmarshalCgroupMetrics(metric.Data.Value)
+	if err != nil {
+		return ContainerStats{}, err
+	}
+
+	stats := ContainerStats{Time: metric.Timestamp}
+	if metrics.CPU != nil && metrics.CPU.Usage != nil {
+		stats.CPUNanoseconds = metrics.CPU.Usage.Total
+	}
+	if metrics.Memory != nil && metrics.Memory.Usage != nil {
+		stats.MemoryUsageBytes = metrics.Memory.Usage.Usage
+		stats.MemoryLimitBytes = metrics.Memory.Usage.Limit
+	}
+	stats.BlkioReadBytes, stats.BlkioWriteBytes = metrics.GetBlkioBytes()
+	return stats, nil
+}
+
+// Top returns the processes running in the container task.
+// The container main process is marked as Init.
+func (c *ContainerdClient) Top(namespace, name string) ([]Process, error) {
+	ctx, cancel := c.getContext()
+	defer cancel()
+
+	client, err := c.getConnection(namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	container, err := client.LoadContainer(ctx, name)
+	if err != nil {
+		if errdefs.IsNotFound(err) {
+			return nil, ErrWithMessagef(ErrNotFound, "Container [%s] not found", name)
+		}
+		return nil, errors.Wrapf(err, "Failed to load container [%s], cannot list processes", name)
+	}
+
+	task, err := container.Task(ctx, nil)
+	if err != nil {
+		if errdefs.IsNotFound(err) {
+			return nil, ErrWithMessagef(ErrNotRunning, "Container [%s] is not running", name)
+		}
+		return nil, errors.Wrapf(err, "Unable to get task in container [%s], cannot list processes", name)
+	}
+
+	infos, err := task.Pids(ctx)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Failed to list container [%s] processes", name)
+	}
+
+	pids := make([]uint32, len(infos))
+	for i, info := range infos {
+		pids[i] = info.Pid
+	}
+	return readProcesses("/proc", pids, task.Pid())
+}
+
+// DialContainer connects to the TCP port listening in the container localhost
+func (c *ContainerdClient) DialContainer(namespace, name string, port int) (net.Conn, error) {
+	ctx, cancel := c.getContext()
+	defer cancel()
+
+	client, err := c.getConnection(namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	container, err := client.LoadContainer(ctx, name)
+	if err != nil {
+		if errdefs.IsNotFound(err) {
+			return nil, ErrWithMessagef(ErrNotFound, "Container [%s] not found", name)
+		}
+		return nil, errors.Wrapf(err, "Failed to load container [%s], cannot connect to port %d", name, port)
+	}
+
+	task, err := container.Task(ctx, nil)
+	if err != nil {
+		if errdefs.IsNotFound(err) {
+			return nil, ErrWithMessagef(ErrNotRunning, "Container [%s] is not running", name)
+		}
+		return nil, errors.Wrapf(err, "Unable to get task in container [%s], cannot connect to port %d", name, port)
+	}
+
+	conn, err := dialInNetworkNamespace(task.Pid(), port)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Failed to connect to port %d in container [%s]", port, name)
+	}
+	return conn, nil
+}
+
+// CopyTo extracts tar archive to the destination path in the running container filesystem
+func (c *ContainerdClient) CopyTo(namespace, name, destPath string, archive io.Reader) error {
+	root, err := c.getContainerRoot(namespace, name)
+	if err != nil {
+		return err
+	}
+	return ExtractArchive(root, destPath, archive)
+}
+
+// CopyFrom writes tar archive of the source path in the running container filesystem
+func (c *ContainerdClient) CopyFrom(namespace, name, srcPath string, archive io.Writer) error {
+	root, err := c.getContainerRoot(namespace, name)
+	if err != nil {
+		return err
+	}
+	return CreateArchive(root, srcPath, archive)
+}
+
+// RemoveFrom removes the paths relative to the destination path in the running container filesystem
+func (c *ContainerdClient) RemoveFrom(namespace, name, destPath string, paths []string) error {
+	root, err := c.getContainerRoot(namespace, name)
+	if err != nil {
+		return err
+	}
+	return RemovePaths(root, destPath, paths)
+}
+
+// getContainerRoot return path to the running container root filesystem through the task process
+func (c *ContainerdClient) getContainerRoot(namespace, name string) (string, error) {
+	ctx, cancel := c.getContext()
+	defer cancel()
+
+	client, err := c.getConnection(namespace)
+	if err != nil {
+		return "", err
+	}
+
+	container, err := client.LoadContainer(ctx, name)
+	if err != nil {
+		if errdefs.IsNotFound(err) {
+			return "", ErrWithMessagef(ErrNotFound, "Container [%s] not found", name)
+		}
+		return "", errors.Wrapf(err, "Failed to load container [%s]", name)
+	}
+
+	task, err := container.Task(ctx, nil)
+	if err != nil {
+		if errdefs.IsNotFound(err) {
+			return "", ErrWithMessagef(ErrNotRunning, "Container [%s] is not running", name)
+		}
+		return "", errors.Wrapf(err, "Unable to get task in container [%s]", name)
+	}
+
+	return fmt.Sprintf("/proc/%d/root", task.Pid()), nil
+}
+
+// PullImage ensures that given container image is pulled to the namespace.
+// ResolveImage resolves the image reference to the image manifest digest in the registry, without pulling the image
+func (c *ContainerdClient) ResolveImage(ref string) (string, error) {
+	ctx, cancel := c.getContext()
+	defer cancel()
+
+	// Same resolver what containerd uses by default when pulling the image
+	resolver := docker.NewResolver(docker.ResolverOptions{Client: http.DefaultClient})
+	_, desc, err := resolver.Resolve(ctx, ref)
+	if err != nil {
+		return "", errors.Wrapf(err, "Failed to resolve image [%s] digest", ref)
+	}
+	return desc.Digest.String(), nil
+}
+
+// Closing the cancel channel aborts the pull and removes the partially downloaded layers.
+func (c *ContainerdClient) PullImage(namespace, ref string, progress *progress.ImageFetch, cancelPull <-chan struct{}) error {
 	ctx, cancel := c.getContext()
 	defer cancel()
 
@@ -386,9 +867,22 @@ func (c *ContainerdClient) PullImage(namespace, ref string, progress *progress.I
 		return err
 	}
 
+	if isBuiltImage(ctx, client, ref) {
+		log.Debugf("Image [%s] is built in the node, skip pull", ref)
+		progress.AllDone()
+		return nil
+	}
+
 	done := make(chan struct{})
 	defer close(done)
 	go opts.UpdateFetchProgress(done, client, progress)
+	go func() {
+		select {
+		case <-cancelPull:
+			cancel()
+		case <-done:
+		}
+	}()
 
 	handler := func(ctx context.Context, desc imagespecs.Descriptor) ([]imagespecs.Descriptor, error) {
 		if desc.MediaType != images.MediaTypeDockerSchema1Manifest {
@@ -403,7 +897,18 @@ func (c *ContainerdClient) PullImage(namespace, ref string, progress *progress.I
 		containerd.WithSchema1Conversion,
 		containerd.WithImageHandler(images.HandlerFunc(handler)),
 	)
+	if isClosed(cancelPull) {
+		c.abortIngests(client, progress)
+		return ErrWithMessagef(context.Canceled, "Pull of image [%s] to namespace [%s] cancelled", ref, namespace)
+	}
 	if err != nil {
+		c.events.Publish(Event{
+			Type:      EventImagePullFailed,
+			Namespace: namespace,
+			Image:     ref,
+			Time:      time.Now(),
+			Message:   err.Error(),
+		})
 		return errors.Wrapf(err, "Error while pulling image [%s] to namespace [%s]", ref, namespace)
 	}
 
@@ -429,6 +934,7 @@ func (c *ContainerdClient) PullImage(namespace, ref string, progress *progress.I
 		return ErrWithMessagef(ErrNotSupported, "Image [%s] does not available for [%s/%s]", ref, runtime.GOOS, runtime.GOARCH)
 	}
 
+	progress.AllExtracting()
 	if err := img.Unpack(ctx, c.snapshotter); err != nil {
 		return errors.Wrapf(err, "Error while unpacking image [%s] to namespace [%s]", ref, namespace)
 	}
@@ -448,6 +954,49 @@ func platformExist(platform imagespecs.Platform, supported []imagespecs.Platform
 	return false
 }
 
+// abortIngests removes the partially downloaded layers of the image
+func (c *ContainerdClient) abortIngests(client *containerd.Client, progress *progress.ImageFetch) {
+	ctx, cancel := c.getContext()
+	defer cancel()
+
+	active, err := client.ContentStore().ListStatuses(ctx, "")
+	if err != nil {
+		log.Warnf("Failed to list active downloads to clean up cancelled image [%s] pull: %s", progress.Image, err)
+		return
+	}
+
+	refs := map[string]bool{}
+	for _, layer := range progress.GetLayers() {
+		refs[layer.Ref] = true
+	}
+
+	for _, status := range active {
+		if !refs[status.Ref] {
+			continue
+		}
+		if err := client.ContentStore().Abort(ctx, status.Ref); err != nil && !errdefs.IsNotFound(err) {
+			log.Warnf("Failed to remove partially downloaded layer [%s] of image [%s]: %s", status.Ref, progress.Image, err)
+		}
+	}
+}
+
+// GetVersion return containerd name and version, e.g. "containerd v1.1.0"
+func (c *ContainerdClient) GetVersion() (string, error) {
+	ctx, cancel := c.getContext()
+	defer cancel()
+
+	client, connErr := c.getConnection(model.DefaultNamespace)
+	if connErr != nil {
+		return "", connErr
+	}
+
+	version, err := client.Version(ctx)
+	if err != nil {
+		return "", errors.Wrapf(err, "Failed to get containerd version")
+	}
+	return fmt.Sprintf("containerd %s", version.Version), nil
+}
+
 // GetNamespaces return all namespaces
 func (c *ContainerdClient) GetNamespaces() ([]string, error) {
 	ctx, cancel := c.getContext()
@@ -466,6 +1015,25 @@ func (c *ContainerdClient) GetNamespaces() ([]string, error) {
 	return getNamespaces(resp), nil
 }
 
+// CreateNamespace creates new namespace
+func (c *ContainerdClient) CreateNamespace(namespace string) error {
+	ctx, cancel := c.getContext()
+	defer cancel()
+
+	client, err := c.getConnection(model.DefaultNamespace)
+	if err != nil {
+		return err
+	}
+
+	if err := client.NamespaceService().Create(ctx, namespace, map[string]string{}); err != nil {
+		if errdefs.IsAlreadyExists(err) {
+			return ErrWithMessagef(ErrAlreadyExists, "Namespace [%s] already exist", namespace)
+		}
+		return errors.Wrapf(err, "Failed to create namespace [%s]", namespace)
+	}
+	return nil
+}
+
 func getNamespaces(namespaces []string) (result []string) {
 	for _, namespace := range namespaces {
 		if namespace != "default" {
@@ -523,59 +1091,118 @@ func (c *ContainerdClient) GetContainerTaskStatus(namespace, name string) string
 }
 
 // Exec run command in container and hook IO to the new process
-func (c *ContainerdClient) Exec(namespace, name, id string, args []string, tty bool, io AttachIO) error {
+// Returns the process exit code once the process exits
+func (c *ContainerdClient) Exec(namespace, name, id string, args []string, tty bool, io AttachIO) (int, error) {
 	ctx, cancel := c.getContext()
 	defer cancel()
 	ctx = namespaces.WithNamespace(ctx, namespace)
 
 	client, err := c.getConnection(namespace)
 	if err != nil {
-		return errors.Wrapf(err, "Unable to get connection to execute command")
+		return -1, errors.Wrapf(err, "Unable to get connection to execute command")
 	}
 
 	container, err := client.LoadContainer(ctx, name)
 	if err != nil {
-		return errors.Wrapf(err, "Cannot execute command in container [%s] in namespace [%s]", name, namespace)
+		return -1, errors.Wrapf(err, "Cannot execute command in container [%s] in namespace [%s]", name, namespace)
 	}
 
 	spec, err := container.Spec(ctx)
 	if err != nil {
-		return err
+		return -1, err
 	}
 
 	task, taskErr := container.Task(ctx, nil)
 	if taskErr != nil {
-		return taskErr
+		return -1, taskErr
 	}
 
 	pspec := spec.Process
 	pspec.Terminal = tty
 	pspec.Args = args
 
-	process, err := task.Exec(ctx, id, pspec, cio.NewCreator(
-		cio.WithStreams(io.Stdin, io.Stdout, io.Stderr),
-		cio.WithTerminal,
-	))
+	ioOpts := []cio.Opt{cio.WithStreams(io.Stdin, io.Stdout, io.Stderr)}
+	if tty {
+		ioOpts = append(ioOpts, cio.WithTerminal)
+	}
+
+	process, err := task.Exec(ctx, id, pspec, cio.NewCreator(ioOpts...))
 	if err != nil {
-		return err
+		return -1, err
 	}
 	defer process.Delete(ctx)
 
 	status, err := process.Wait(ctx)
 	if err != nil {
-		return err
+		return -1, err
 	}
 
 	if err := process.Start(ctx); err != nil {
-		return err
+		return -1, err
+	}
+
+	if tty {
+		go resizeOnChange(ctx, process, io.Resize)
 	}
 
 	exitStatus := <-status
-	return exitStatus.Error()
+	return int(exitStatus.ExitCode()), exitStatus.Error()
+}
+
+// WaitContainer blocks until the container main process exits and return the exit code.
+// If the process has already exited, the recorded exit code is returned right away.
+// Closing the done channel stops the wait. Returns ErrNotRunning if the container has never been started.
+func (c *ContainerdClient) WaitContainer(namespace, name string, done <-chan struct{}) (int, error) {
+	ctx, cancel := context.WithCancel(c.context)
+	defer cancel()
+	go func() {
+		select {
+		case <-done:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	client, err := c.getConnection(namespace)
+	if err != nil {
+		return -1, err
+	}
+
+	container, err := client.LoadContainer(ctx, name)
+	if err != nil {
+		if errdefs.IsNotFound(err) {
+			return -1, ErrWithMessagef(ErrNotFound, "Container [%s] not found", name)
+		}
+		return -1, errors.Wrapf(err, "Failed to load container [%s]", name)
+	}
+
+	task, err := container.Task(ctx, nil)
+	if err != nil {
+		if errdefs.IsNotFound(err) {
+			return -1, ErrWithMessagef(ErrNotRunning, "Container [%s] has not been started", name)
+		}
+		return -1, errors.Wrapf(err, "Unable to get task in container [%s]", name)
+	}
+
+	// Wait of already stopped task returns the recorded exit status
+	status, err := task.Wait(ctx)
+	if err != nil {
+		return -1, errors.Wrapf(err, "Failed to wait container [%s]", name)
+	}
+
+	exitStatus := <-status
+	if isClosed(done) {
+		return -1, ErrWithMessagef(context.Canceled, "Wait of container [%s] cancelled", name)
+	}
+	if err := exitStatus.Error(); err != nil {
+		return -1, errors.Wrapf(err, "Failed to wait container [%s]", name)
+	}
+	return int(exitStatus.ExitCode()), nil
 }
 
 // Attach hook IO to container main process
-func (c *ContainerdClient) Attach(namespace, name string, io AttachIO) error {
+// Output is read from the container logs, starting from the output which is not yet delivered to anyone
+func (c *ContainerdClient) Attach(namespace, name string, attachIO AttachIO) error {
 	ctx, cancel := c.getContext()
 	defer cancel()
 
@@ -589,18 +1216,239 @@ func (c *ContainerdClient) Attach(namespace, name string, io AttachIO) error {
 		return errors.Wrapf(err, "Cannot attach to container [%s] in namespace [%s]", name, namespace)
 	}
 
-	task, taskErr := container.Task(ctx, cio.NewAttach(
-		cio.WithStreams(io.Stdin, io.Stdout, io.Stderr),
-	))
+	task, taskErr := container.Task(ctx, nil)
 	if taskErr != nil {
 		return taskErr
 	}
 
+	process, err := c.getTaskProcess(ctx, client, name)
+	if err != nil {
+		return err
+	}
+
+	buffer, err := c.ensureLogCapture(ctx, client, namespace, name, process)
+	if err != nil {
+		return err
+	}
+
+	if attachIO.Stdin != nil && process.Stdin != "" {
+		stdin, err := opts.OpenInputFifo(ctx, process.Stdin)
+		if err != nil {
+			return errors.Wrapf(err, "Cannot attach to container [%s] stdin", name)
+		}
+		defer stdin.Close()
+		go func() {
+			if _, err := io.Copy(stdin, attachIO.Stdin); err != nil {
+				return
+			}
+			// The client closed stdin, e.g. piped file ended, so the process gets end of input once all
+			// writers are closed. The container stdin cannot be opened again, so it's closed only when the client
+			// explicitly ends it.
+			stdin.Close()
+			if err := task.CloseIO(ctx, containerd.WithStdinCloser); err != nil {
+				log.Warnf("Failed to close container [%s] stdin: %s", name, err)
+			}
+		}()
+		go resizeOnChange(ctx, task, attachIO.Resize)
+	}
+
 	status, err := task.Wait(ctx)
 	if err != nil {
 		return err
 	}
 
-	exitStatus := <-status
+	exited := make(chan struct{})
+	var exitStatus containerd.ExitStatus
+	go func() {
+		exitStatus = <-status
+		<-buffer.CaptureDone()
+		close(exited)
+	}()
+
+	err = buffer.FollowAttached(exited, func(line LogLine) error {
+		writer := attachIO.Stdout
+		if line.Stderr {
+			writer = attachIO.Stderr
+		}
+		_, err := writer.Write(line.Line)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
 	return exitStatus.Error()
 }
+
+// resizer is containerd task or process which terminal can be resized
+type resizer interface {
+	Resize(ctx context.Context, w, h uint32) error
+}
+
+// resizeOnChange resizes the process terminal when the client terminal size changes, until the context is done
+func resizeOnChange(ctx context.Context, process resizer, sizes <-chan TerminalSize) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case size := <-sizes:
+			if err := process.Resize(ctx, size.Width, size.Height); err != nil {
+				log.Debugf("Failed to resize terminal to %dx%d: %s", size.Width, size.Height, err)
+			}
+		}
+	}
+}
+
+// Events calls the handler for every runtime event in the namespace, or in all namespaces if the namespace is empty,
+// until the done channel closes or the handler returns an error.
+// Returns error if the subscription to containerd events fails.
+func (c *ContainerdClient) Events(namespace string, done <-chan struct{}, handler func(Event) error) error {
+	client, err := c.getConnection(namespace)
+	if err != nil {
+		return errors.Wrapf(err, "Unable to get connection to subscribe events")
+	}
+	defer client.Close()
+
+	ctx, cancel := context.WithCancel(c.context)
+	defer cancel()
+
+	local, unsubscribe := c.events.Subscribe(namespace)
+	defer unsubscribe()
+
+	// Containerd events contain only the container id, cache the pod names so they can be resolved after delete
+	podNames := map[string]string{}
+	resolvePodName := func(event Event) string {
+		if event.ContainerID == "" {
+			return ""
+		}
+		key := logStoreKey(event.Namespace, event.ContainerID)
+		if name, ok := podNames[key]; ok {
+			return name
+		}
+		container, err := client.ContainerService().Get(namespaceutils.WithNamespace(ctx, event.Namespace), event.ContainerID)
+		if err != nil {
+			return ""
+		}
+		podNames[key] = mapping.GetPodName(container)
+		return podNames[key]
+	}
+	emit := func(event Event) error {
+		if event.PodName == "" {
+			event.PodName = resolvePodName(event)
+		}
+		if event.Type == EventContainerDeleted {
+			delete(podNames, logStoreKey(event.Namespace, event.ContainerID))
+		}
+		return handler(event)
+	}
+
+	envelopes, errs := client.Subscribe(ctx, eventFilters(namespace)...)
+	for {
+		select {
+		case envelope, ok := <-envelopes:
+			if !ok {
+				return errors.New("Containerd closed the events stream")
+			}
+			event, ok, err := mapEnvelope(envelope)
+			if err != nil {
+				log.Warnf("Invalid event from containerd: %s", err)
+				continue
+			}
+			if !ok {
+				continue
+			}
+			if err := emit(event); err != nil {
+				return err
+			}
+		case event := <-local:
+			if err := emit(event); err != nil {
+				return err
+			}
+		case err := <-errs:
+			return errors.Wrapf(err, "Subscription to containerd events failed")
+		case <-done:
+			return nil
+		}
+	}
+}
+
+// Logs calls handler for each container output line matching the options.
+// If follow is set, keeps calling handler with new lines until done channel closes.
+func (c *ContainerdClient) Logs(namespace, name string, logOpts LogOptions, done <-chan struct{}, handler func(LogLine) error) error {
+	ctx, cancel := c.getContext()
+	defer cancel()
+
+	client, err := c.getConnection(namespace)
+	if err != nil {
+		return errors.Wrapf(err, "Unable to get connection to read container logs")
+	}
+
+	if _, err := client.LoadContainer(ctx, name); err != nil {
+		if errdefs.IsNotFound(err) {
+			return ErrWithMessagef(ErrNotFound, "Container [%s] in namespace [%s] not found", name, namespace)
+		}
+		return errors.Wrapf(err, "Cannot read logs of container [%s] in namespace [%s]", name, namespace)
+	}
+
+	buffer := c.logs.Get(namespace, name)
+	if process, err := c.getTaskProcess(ctx, client, name); err == nil {
+		if buffer, err = c.ensureLogCapture(ctx, client, namespace, name, process); err != nil {
+			return err
+		}
+	} else if !errdefs.IsNotFound(errors.Cause(err)) {
+		return err
+	}
+
+	lines, next := buffer.Lines(logOpts)
+	for _, line := range lines {
+		if err := handler(line); err != nil {
+			return err
+		}
+	}
+
+	if !logOpts.Follow {
+		return nil
+	}
+	return buffer.Follow(next, done, handler)
+}
+
+func (c *ContainerdClient) getTaskProcess(ctx context.Context, client *containerd.Client, name string) (*tasktypes.Process, error) {
+	resp, err := client.TaskService().Get(ctx, &tasks.GetRequest{
+		ContainerID: name,
+	})
+	if err != nil {
+		return nil, errors.Wrapf(errdefs.FromGRPC(err), "Unable to get task of container [%s]", name)
+	}
+	return resp.Process, nil
+}
+
+// ensureLogCapture starts reading the running task output to the logs if not already reading.
+// For example, eliotd restart loses the readers opened when the task started.
+func (c *ContainerdClient) ensureLogCapture(ctx context.Context, client *containerd.Client, namespace, name string, process *tasktypes.Process) (*LogBuffer, error) {
+	buffer := c.logs.Get(namespace, name)
+	if buffer.IsCapturing() || process.Status != tasktypes.StatusRunning {
+		return buffer, nil
+	}
+
+	container, err := client.ContainerService().Get(ctx, name)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Failed to load container [%s]", name)
+	}
+
+	pipe, err := extensions.GetPipeExtension(container)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Failed to resolve container [%s] pipe configuration", name)
+	}
+
+	stdoutPath := process.Stdout
+	if pipe != nil {
+		stdoutPath = ""
+	}
+
+	stdout, stderr, err := opts.OpenOutputFifos(ctx, stdoutPath, process.Stderr)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Cannot read container [%s] output", name)
+	}
+	buffer.Capture(stdout, stderr)
+	return buffer, nil
+}
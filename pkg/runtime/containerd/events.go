@@ -0,0 +1,113 @@
+package containerd
+
+import (
+	"github.com/golang/protobuf/proto"
+	"github.com/pkg/errors"
+)
+
+// TaskOOMTopic is the containerd event topic where the out of memory events get published
+const TaskOOMTopic = "/tasks/oom"
+
+// TaskOOM mirrors the containerd events.TaskOOM message what gets published
+// when the kernel kills the container process because it run out of memory
+type TaskOOM struct {
+	ContainerID string `protobuf:"bytes,1,opt,name=container_id" json:"container_id,omitempty"`
+}
+
+func (m *TaskOOM) Reset()         { *m = TaskOOM{} }
+func (m *TaskOOM) String() string { return proto.CompactTextString(m) }
+func (*TaskOOM) ProtoMessage()    {}
+
+// UnmarshalTaskOOM decodes TaskOOM event payload
+func UnmarshalTaskOOM(data []byte) (*TaskOOM, error) {
+	event := &TaskOOM{}
+	if err := proto.Unmarshal(data, event); err != nil {
+		return nil, errors.Wrap(err, "Failed to unmarshal task OOM event")
+	}
+	return event, nil
+}
+
+// Containerd event topics which get mapped to the runtime events
+const (
+	TaskStartTopic       = "/tasks/start"
+	TaskExitTopic        = "/tasks/exit"
+	ContainerCreateTopic = "/containers/create"
+	ContainerDeleteTopic = "/containers/delete"
+	ImageCreateTopic     = "/images/create"
+)
+
+// TaskStart mirrors the containerd events.TaskStart message
+type TaskStart struct {
+	ContainerID string `protobuf:"bytes,1,opt,name=container_id" json:"container_id,omitempty"`
+	Pid         uint32 `protobuf:"varint,2,opt,name=pid" json:"pid,omitempty"`
+}
+
+func (m *TaskStart) Reset()         { *m = TaskStart{} }
+func (m *TaskStart) String() string { return proto.CompactTextString(m) }
+func (*TaskStart) ProtoMessage()    {}
+
+// TaskExit mirrors the containerd events.TaskExit message, the exit time is left out
+type TaskExit struct {
+	ContainerID string `protobuf:"bytes,1,opt,name=container_id" json:"container_id,omitempty"`
+	// ID is the process id, same as ContainerID for the container main process
+	ID         string `protobuf:"bytes,2,opt,name=id" json:"id,omitempty"`
+	Pid        uint32 `protobuf:"varint,3,opt,name=pid" json:"pid,omitempty"`
+	ExitStatus uint32 `protobuf:"varint,4,opt,name=exit_status" json:"exit_status,omitempty"`
+}
+
+func (m *TaskExit) Reset()         { *m = TaskExit{} }
+func (m *TaskExit) String() string { return proto.CompactTextString(m) }
+func (*TaskExit) ProtoMessage()    {}
+
+// ContainerCreate mirrors the containerd events.ContainerCreate message, the runtime is left out
+type ContainerCreate struct {
+	ID    string `protobuf:"bytes,1,opt,name=id" json:"id,omitempty"`
+	Image string `protobuf:"bytes,2,opt,name=image" json:"image,omitempty"`
+}
+
+func (m *ContainerCreate) Reset()         { *m = ContainerCreate{} }
+func (m *ContainerCreate) String() string { return proto.CompactTextString(m) }
+func (*ContainerCreate) ProtoMessage()    {}
+
+// ContainerDelete mirrors the containerd events.ContainerDelete message
+type ContainerDelete struct {
+	ID string `protobuf:"bytes,1,opt,name=id" json:"id,omitempty"`
+}
+
+func (m *ContainerDelete) Reset()         { *m = ContainerDelete{} }
+func (m *ContainerDelete) String() string { return proto.CompactTextString(m) }
+func (*ContainerDelete) ProtoMessage()    {}
+
+// ImageCreate mirrors the containerd events.ImageCreate message, the labels are left out
+type ImageCreate struct {
+	Name string `protobuf:"bytes,1,opt,name=name" json:"name,omitempty"`
+}
+
+func (m *ImageCreate) Reset()         { *m = ImageCreate{} }
+func (m *ImageCreate) String() string { return proto.CompactTextString(m) }
+func (*ImageCreate) ProtoMessage()    {}
+
+// UnmarshalEvent decodes the event payload of the topic, returns nil if the topic is not known
+func UnmarshalEvent(topic string, data []byte) (proto.Message, error) {
+	var event proto.Message
+	switch topic {
+	case TaskOOMTopic:
+		event = &TaskOOM{}
+	case TaskStartTopic:
+		event = &TaskStart{}
+	case TaskExitTopic:
+		event = &TaskExit{}
+	case ContainerCreateTopic:
+		event = &ContainerCreate{}
+	case ContainerDeleteTopic:
+		event = &ContainerDelete{}
+	case ImageCreateTopic:
+		event = &ImageCreate{}
+	default:
+		return nil, nil
+	}
+	if err := proto.Unmarshal(data, event); err != nil {
+		return nil, errors.Wrapf(err, "Failed to unmarshal %s event", topic)
+	}
+	return event, nil
+}
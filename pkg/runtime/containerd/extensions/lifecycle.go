@@ -3,6 +3,7 @@ package extensions
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/containerd/containerd"
 	"github.com/containerd/containerd/containers"
@@ -21,6 +22,8 @@ const (
 	Always = iota
 	// OnFailure means that only if process fails (non zero exit code) the container should be restarted
 	OnFailure
+	// Never means that the container is never restarted, e.g. one time migration
+	Never
 )
 
 func (p RestartPolicy) String() string {
@@ -29,6 +32,8 @@ func (p RestartPolicy) String() string {
 		return "always"
 	case OnFailure:
 		return "onfailure"
+	case Never:
+		return "never"
 	default:
 		return "unknown"
 	}
@@ -40,6 +45,18 @@ type ContainerLifecycle struct {
 	// If value is zero, assumed that it's not yet created
 	StartCount    int
 	RestartPolicy RestartPolicy
+	// StartedAt is the time when the container was last started
+	StartedAt time.Time
+}
+
+// ParseRestartPolicy parses the restart policy name, empty and unknown names default to Always
+func ParseRestartPolicy(name string) RestartPolicy {
+	for _, policy := range []RestartPolicy{OnFailure, Never} {
+		if name == policy.String() {
+			return policy
+		}
+	}
+	return Always
 }
 
 // WithLifecycleExtension is containerd.NewContainerOpts implementation what add lifecycle extension data to the container object.
@@ -47,6 +64,13 @@ func WithLifecycleExtension(ctx context.Context, client *containerd.Client, c *c
 	return updateLifecycleExtension(c, ContainerLifecycle{})
 }
 
+// WithRestartPolicy is like WithLifecycleExtension, but sets the container restart policy
+func WithRestartPolicy(policy RestartPolicy) containerd.NewContainerOpts {
+	return func(ctx context.Context, client *containerd.Client, c *containers.Container) error {
+		return updateLifecycleExtension(c, ContainerLifecycle{RestartPolicy: policy})
+	}
+}
+
 func updateLifecycleExtension(c *containers.Container, lifecycle ContainerLifecycle) error {
 	any, err := typeurl.MarshalAny(&lifecycle)
 	if err != nil {
@@ -63,12 +87,14 @@ func updateLifecycleExtension(c *containers.Container, lifecycle ContainerLifecy
 }
 
 // IncrementRestart is containerd.UpdateContainerOpts implementation what increments restart counter
+// and updates the start time
 func IncrementRestart(ctx context.Context, client *containerd.Client, c *containers.Container) error {
 	lifecycle, err := GetLifecycleExtension(*c)
 	if err != nil {
 		return errors.Wrapf(err, "Cannot increment container restart counter")
 	}
 	lifecycle.StartCount++
+	lifecycle.StartedAt = time.Now()
 
 	return updateLifecycleExtension(c, lifecycle)
 }
@@ -28,3 +28,10 @@ func TestGetLifecycleExtensionReturnNotFoundErr(t *testing.T) {
 	_, err := GetLifecycleExtension(containers.Container{})
 	assert.True(t, IsNotFound(err))
 }
+
+func TestParseRestartPolicy(t *testing.T) {
+	assert.Equal(t, RestartPolicy(OnFailure), ParseRestartPolicy("onfailure"))
+	assert.Equal(t, RestartPolicy(Always), ParseRestartPolicy("always"))
+	assert.Equal(t, RestartPolicy(Never), ParseRestartPolicy("never"))
+	assert.Equal(t, RestartPolicy(Always), ParseRestartPolicy(""), "should default to always")
+}
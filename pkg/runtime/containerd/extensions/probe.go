@@ -0,0 +1,56 @@
+package extensions
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/containerd/containerd"
+	"github.com/containerd/containerd/containers"
+	"github.com/containerd/typeurl"
+	"github.com/gogo/protobuf/types"
+)
+
+var readinessProbeExtensionName = "eliot.io.readinessprobe"
+
+// ReadinessProbe defines how to check the container readiness, either with HTTP GET request or command
+type ReadinessProbe struct {
+	HTTPPath string
+	HTTPPort int
+	Command  []string
+}
+
+// WithReadinessProbeExtension appends readiness probe extension data to the container object.
+func WithReadinessProbeExtension(probe ReadinessProbe) containerd.NewContainerOpts {
+	return func(ctx context.Context, client *containerd.Client, c *containers.Container) error {
+		any, err := typeurl.MarshalAny(&probe)
+		if err != nil {
+			return err
+		}
+
+		if c.Extensions == nil {
+			c.Extensions = make(map[string]types.Any)
+		}
+		c.Extensions[readinessProbeExtensionName] = *any
+		return nil
+	}
+}
+
+// GetReadinessProbeExtension returns ReadinessProbe from container extensions or nil if not defined
+func GetReadinessProbeExtension(container containers.Container) (*ReadinessProbe, error) {
+	extension, ok := container.Extensions[readinessProbeExtensionName]
+	if !ok {
+		return nil, nil
+	}
+
+	decoded, err := typeurl.UnmarshalAny(&extension)
+	if err != nil {
+		return nil, err
+	}
+
+	probe, ok := decoded.(*ReadinessProbe)
+	if !ok {
+		return nil, fmt.Errorf("Failed to decode ReadinessProbe from container [%s] extensions", container.ID)
+	}
+
+	return probe, nil
+}
@@ -14,4 +14,5 @@ func init() {
 	major := strconv.Itoa(versionMajor)
 	typeurl.Register(&PipeSet{}, prefix, "containerd/extensions", major, "PipeSet")
 	typeurl.Register(&ContainerLifecycle{}, prefix, "containerd/extensions", major, "ContainerLifecycle")
+	typeurl.Register(&ReadinessProbe{}, prefix, "containerd/extensions", major, "ReadinessProbe")
 }
@@ -107,3 +107,31 @@ func (f *DirectIO) Close() error {
 func (f *DirectIO) Delete() error {
 	return nil
 }
+
+// OpenOutputFifos opens existing stdout and stderr FIFOs for reading.
+// Empty path is skipped and returns nil reader.
+func OpenOutputFifos(ctx context.Context, stdout, stderr string) (stdoutReader, stderrReader io.ReadCloser, err error) {
+	if stdout != "" {
+		if stdoutReader, err = fifo.OpenFifo(ctx, stdout, syscall.O_RDONLY|syscall.O_NONBLOCK, 0700); err != nil {
+			return nil, nil, errors.Wrapf(err, "Failed to open out FIFO [%s]", stdout)
+		}
+	}
+	if stderr != "" {
+		if stderrReader, err = fifo.OpenFifo(ctx, stderr, syscall.O_RDONLY|syscall.O_NONBLOCK, 0700); err != nil {
+			if stdoutReader != nil {
+				stdoutReader.Close()
+			}
+			return nil, nil, errors.Wrapf(err, "Failed to open err FIFO [%s]", stderr)
+		}
+	}
+	return stdoutReader, stderrReader, nil
+}
+
+// OpenInputFifo opens existing stdin FIFO for writing
+func OpenInputFifo(ctx context.Context, stdin string) (io.WriteCloser, error) {
+	writer, err := fifo.OpenFifo(ctx, stdin, syscall.O_WRONLY|syscall.O_NONBLOCK, 0700)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Failed to open in FIFO [%s]", stdin)
+	}
+	return writer, nil
+}
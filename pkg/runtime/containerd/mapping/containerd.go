@@ -2,6 +2,7 @@ package mapping
 
 import (
 	"encoding/json"
+	"time"
 
 	specs "github.com/opencontainers/runtime-spec/specs-go"
 	log "github.com/sirupsen/logrus"
@@ -23,10 +24,18 @@ func GetPodName(container containers.Container) string {
 	return podName
 }
 
+// GetInitContainerIndex return the position of the init container in the pod spec, ok false if the container is
+// not init container
+func GetInitContainerIndex(container containers.Container) (index int, ok bool) {
+	return ContainerLabels(container.Labels).getInitIndex()
+}
+
 // InitialisePodModel creates new Pod struct with name and namespace metadata
 func InitialisePodModel(container containers.Container, namespace, name, hostname string) model.Pod {
+	metadata := model.NewMetadata(namespace, name)
+	metadata.Labels = ContainerLabels(container.Labels).getPodLabels()
 	return model.Pod{
-		Metadata: model.NewMetadata(namespace, name),
+		Metadata: metadata,
 		Spec: model.PodSpec{
 			Containers:    []model.Container{},
 			HostNetwork:   !haveNamespace(container, specs.NetworkNamespace),
@@ -36,6 +45,7 @@ func InitialisePodModel(container containers.Container, namespace, name, hostnam
 		Status: model.PodStatus{
 			Hostname:          hostname,
 			ContainerStatuses: []model.ContainerStatus{},
+			Cordoned:          ContainerLabels(container.Labels).isCordoned(),
 		},
 	}
 }
@@ -60,6 +70,9 @@ func MapContainerToInternalModel(container containers.Container) model.Container
 		WorkingDir: processWorkingDir(container),
 		Pipe:       mapPipeToInternalModel(container),
 		Mounts:     mapMountsToInternalModel(container),
+
+		RestartPolicy:  getRestartPolicy(container),
+		ReadinessProbe: mapReadinessProbeToInternalModel(container),
 	}
 }
 
@@ -100,6 +113,26 @@ func mapPipeToInternalModel(container containers.Container) *model.PipeSet {
 	}
 }
 
+func mapReadinessProbeToInternalModel(container containers.Container) *model.Probe {
+	probe, err := extensions.GetReadinessProbeExtension(container)
+	if err != nil {
+		log.Errorf("Failed to read ReadinessProbe extension from container [%s]: %s", container.ID, err)
+	}
+	if probe == nil {
+		return nil
+	}
+
+	if len(probe.Command) > 0 {
+		return &model.Probe{Exec: &model.ExecProbe{Command: probe.Command}}
+	}
+	return &model.Probe{HTTPGet: &model.HTTPGetProbe{Path: probe.HTTPPath, Port: probe.HTTPPort}}
+}
+
+// HasReadinessProbe return true if the container has readiness probe
+func HasReadinessProbe(container containers.Container) bool {
+	return mapReadinessProbeToInternalModel(container) != nil
+}
+
 func processArgs(container containers.Container) []string {
 	spec, err := getSpec(container)
 	if err != nil {
@@ -151,13 +184,40 @@ func mapMountsToInternalModel(container containers.Container) (result []model.Mo
 // MapContainerStatusToInternalModel maps containerd model to internal container status model
 func MapContainerStatusToInternalModel(container containers.Container, status containerd.Status) model.ContainerStatus {
 	labels := ContainerLabels(container.Labels)
-	return model.ContainerStatus{
+	result := model.ContainerStatus{
 		ContainerID:  container.ID,
 		Name:         labels.getContainerName(),
 		Image:        container.Image,
 		State:        mapContainerStatus(status),
 		RestartCount: getRestartCount(container),
+		SpecHash:     labels.getSpecHash(),
+		StartedAt:    getStartedAt(container),
+	}
+
+	// Created but not yet started task is also stopped, but it doesn't have exit status
+	if status.Status == containerd.Stopped && !result.StartedAt.IsZero() {
+		result.ExitCode = int(status.ExitStatus)
+		result.FinishedAt = status.ExitTime
+		result.Reason = getExitReason(result.ExitCode)
+	}
+	return result
+}
+
+func getStartedAt(container containers.Container) time.Time {
+	lifecycle, err := extensions.GetLifecycleExtension(container)
+	if err != nil && !extensions.IsNotFound(err) {
+		log.Warnf("Error while resolving container start time: %s", err)
+	}
+	return lifecycle.StartedAt
+}
+
+// getExitReason return reason based on the exit code.
+// The exit code doesn't tell if the process was killed because out of memory, so runtime sets ReasonOOMKilled separately.
+func getExitReason(exitCode int) string {
+	if exitCode == 0 {
+		return model.ReasonCompleted
 	}
+	return model.ReasonError
 }
 
 func getRestartCount(container containers.Container) int {
@@ -26,3 +26,16 @@ func MapPipeToContainerdModel(pipe model.PipeSet) extensions.PipeSet {
 		},
 	}
 }
+
+// MapReadinessProbeToContainerdModel maps internal readiness probe model to containerd extension model
+func MapReadinessProbeToContainerdModel(probe model.Probe) extensions.ReadinessProbe {
+	result := extensions.ReadinessProbe{}
+	if probe.HTTPGet != nil {
+		result.HTTPPath = probe.HTTPGet.Path
+		result.HTTPPort = probe.HTTPGet.Port
+	}
+	if probe.Exec != nil {
+		result.Command = probe.Exec.Command
+	}
+	return result
+}
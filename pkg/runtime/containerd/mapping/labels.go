@@ -2,6 +2,8 @@ package mapping
 
 import (
 	"fmt"
+	"strconv"
+	"strings"
 
 	"github.com/ernoaapa/eliot/pkg/model"
 )
@@ -10,6 +12,10 @@ var (
 	labelPrefix        = "io.eliot"
 	podNameLabel       = "pod.name"
 	containerNameLabel = "container.name"
+	specHashLabel      = "container.spec-hash"
+	initIndexLabel     = "container.init-index"
+	podCordonedLabel   = "pod.cordoned"
+	podLabelPrefix     = "pod.label."
 )
 
 // ContainerLabels is helper type for managing container labels
@@ -23,6 +29,38 @@ func (l ContainerLabels) getContainerName() string {
 	return l.getValue(containerNameLabel)
 }
 
+func (l ContainerLabels) getSpecHash() string {
+	return l.getValue(specHashLabel)
+}
+
+func (l ContainerLabels) isCordoned() bool {
+	return l.getValue(podCordonedLabel) == "true"
+}
+
+// getInitIndex return the position of the init container in the pod spec, ok false if the container is not init container
+func (l ContainerLabels) getInitIndex() (index int, ok bool) {
+	value := l.getValue(initIndexLabel)
+	if value == "" {
+		return 0, false
+	}
+	index, err := strconv.Atoi(value)
+	return index, err == nil
+}
+
+func (l ContainerLabels) getPodLabels() map[string]string {
+	prefix := buildLabelKeyFor(podLabelPrefix)
+	result := map[string]string{}
+	for key, value := range l {
+		if strings.HasPrefix(key, prefix) {
+			result[strings.TrimPrefix(key, prefix)] = value
+		}
+	}
+	if len(result) == 0 {
+		return nil
+	}
+	return result
+}
+
 func (l ContainerLabels) getValue(key string) string {
 	return l[buildLabelKeyFor(key)]
 }
@@ -36,5 +74,52 @@ func NewLabels(pod model.Pod, container model.Container) ContainerLabels {
 	labels := make(map[string]string)
 	labels[buildLabelKeyFor(podNameLabel)] = pod.Metadata.Name
 	labels[buildLabelKeyFor(containerNameLabel)] = container.Name
+	labels[buildLabelKeyFor(specHashLabel)] = model.GetContainerSpecHash(pod.Spec, container)
+	for i, initContainer := range pod.Spec.InitContainers {
+		if initContainer.Name == container.Name {
+			labels[buildLabelKeyFor(initIndexLabel)] = strconv.Itoa(i)
+		}
+	}
+	for key, value := range pod.Metadata.Labels {
+		labels[buildLabelKeyFor(podLabelPrefix+key)] = value
+	}
+	if pod.Status.Cordoned {
+		labels[buildLabelKeyFor(podCordonedLabel)] = "true"
+	}
 	return labels
 }
+
+// PodLabelsPatch return the container labels and the label keys to update to replace the pod labels
+// of existing container. Keys which are not in the returned labels get removed.
+func PodLabelsPatch(current ContainerLabels, podLabels map[string]string) (labels map[string]string, keys []string) {
+	prefix := buildLabelKeyFor(podLabelPrefix)
+	labels = map[string]string{}
+	for key := range current {
+		if strings.HasPrefix(key, prefix) {
+			keys = append(keys, key)
+		}
+	}
+	for key, value := range podLabels {
+		labelKey := buildLabelKeyFor(podLabelPrefix + key)
+		if _, exist := current[labelKey]; !exist {
+			keys = append(keys, labelKey)
+		}
+		labels[labelKey] = value
+	}
+	return labels, keys
+}
+
+// PodNamePatch return the container labels and the label keys to update to move existing container to another pod
+func PodNamePatch(podName string) (labels map[string]string, keys []string) {
+	key := buildLabelKeyFor(podNameLabel)
+	return map[string]string{key: podName}, []string{key}
+}
+
+// PodCordonPatch return the container labels and the label keys to update to cordon or uncordon existing container
+func PodCordonPatch(cordoned bool) (labels map[string]string, keys []string) {
+	key := buildLabelKeyFor(podCordonedLabel)
+	if !cordoned {
+		return map[string]string{}, []string{key}
+	}
+	return map[string]string{key: "true"}, []string{key}
+}
@@ -1,6 +1,7 @@
 package mapping
 
 import (
+	"sort"
 	"testing"
 
 	"github.com/ernoaapa/eliot/pkg/model"
@@ -20,4 +21,64 @@ func TestNewContainerLabels(t *testing.T) {
 	result := NewLabels(pod, container)
 
 	assert.Equal(t, "my-pod", result["io.eliot.pod.name"])
+	assert.Equal(t, model.GetContainerSpecHash(pod.Spec, container), result["io.eliot.container.spec-hash"])
+}
+
+func TestPodLabelsRoundTrip(t *testing.T) {
+	pod := model.Pod{
+		Metadata: model.Metadata{
+			Name:   "my-pod",
+			Labels: map[string]string{"app": "nginx"},
+		},
+	}
+	result := NewLabels(pod, model.Container{Name: "my-container"})
+
+	assert.Equal(t, "nginx", result["io.eliot.pod.label.app"])
+	assert.Equal(t, map[string]string{"app": "nginx"}, result.getPodLabels())
+}
+
+func TestPodLabelsPatch(t *testing.T) {
+	current := NewLabels(model.Pod{
+		Metadata: model.Metadata{
+			Name:   "my-pod",
+			Labels: map[string]string{"app": "nginx", "env": "dev"},
+		},
+	}, model.Container{Name: "my-container"})
+
+	labels, keys := PodLabelsPatch(current, map[string]string{"app": "web", "tier": "front"})
+
+	assert.Equal(t, map[string]string{"io.eliot.pod.label.app": "web", "io.eliot.pod.label.tier": "front"}, labels)
+	sort.Strings(keys)
+	assert.Equal(t, []string{"io.eliot.pod.label.app", "io.eliot.pod.label.env", "io.eliot.pod.label.tier"}, keys, "should update the removed env label to remove it")
+	assert.NotContains(t, keys, "io.eliot.pod.name", "should not touch other labels")
+}
+
+func TestInitContainerLabels(t *testing.T) {
+	pod := model.Pod{
+		Metadata: model.Metadata{Name: "my-pod"},
+		Spec: model.PodSpec{
+			Containers:     []model.Container{{Name: "app"}},
+			InitContainers: []model.Container{{Name: "migrate"}, {Name: "seed"}},
+		},
+	}
+
+	index, ok := NewLabels(pod, pod.Spec.InitContainers[1]).getInitIndex()
+	assert.True(t, ok)
+	assert.Equal(t, 1, index)
+
+	_, ok = NewLabels(pod, pod.Spec.Containers[0]).getInitIndex()
+	assert.False(t, ok, "should not mark the other containers")
+}
+
+func TestPodCordonPatch(t *testing.T) {
+	labels, keys := PodCordonPatch(true)
+	assert.True(t, ContainerLabels(labels).isCordoned())
+	assert.Equal(t, []string{"io.eliot.pod.cordoned"}, keys)
+
+	labels, keys = PodCordonPatch(false)
+	assert.Empty(t, labels, "should remove the label")
+	assert.Equal(t, []string{"io.eliot.pod.cordoned"}, keys)
+
+	pod := model.Pod{Metadata: model.Metadata{Name: "my-pod"}, Status: model.PodStatus{Cordoned: true}}
+	assert.True(t, NewLabels(pod, model.Container{Name: "my-container"}).isCordoned(), "recreated container should stay cordoned")
 }
@@ -0,0 +1,106 @@
+package containerd
+
+import (
+	"github.com/golang/protobuf/proto"
+	"github.com/pkg/errors"
+)
+
+// CgroupMetrics is subset of the github.com/containerd/cgroups Metrics message what the containerd
+// Linux runtime returns as task metrics. Only the fields used by Eliot are defined, the rest get
+// skipped when unmarshalled.
+type CgroupMetrics struct {
+	CPU    *CgroupCPUStat    `protobuf:"bytes,3,opt,name=cpu" json:"cpu,omitempty"`
+	Memory *CgroupMemoryStat `protobuf:"bytes,4,opt,name=memory" json:"memory,omitempty"`
+	Blkio  *CgroupBlkIOStat  `protobuf:"bytes,5,opt,name=blkio" json:"blkio,omitempty"`
+}
+
+func (m *CgroupMetrics) Reset()         { *m = CgroupMetrics{} }
+func (m *CgroupMetrics) String() string { return proto.CompactTextString(m) }
+func (*CgroupMetrics) ProtoMessage()    {}
+
+// CgroupCPUStat is the cgroup cpuacct statistics
+type CgroupCPUStat struct {
+	Usage *CgroupCPUUsage `protobuf:"bytes,1,opt,name=usage" json:"usage,omitempty"`
+}
+
+func (m *CgroupCPUStat) Reset()         { *m = CgroupCPUStat{} }
+func (m *CgroupCPUStat) String() string { return proto.CompactTextString(m) }
+func (*CgroupCPUStat) ProtoMessage()    {}
+
+// CgroupCPUUsage is the CPU time consumed, in nanoseconds
+type CgroupCPUUsage struct {
+	Total  uint64 `protobuf:"varint,1,opt,name=total" json:"total,omitempty"`
+	Kernel uint64 `protobuf:"varint,2,opt,name=kernel" json:"kernel,omitempty"`
+	User   uint64 `protobuf:"varint,3,opt,name=user" json:"user,omitempty"`
+}
+
+func (m *CgroupCPUUsage) Reset()         { *m = CgroupCPUUsage{} }
+func (m *CgroupCPUUsage) String() string { return proto.CompactTextString(m) }
+func (*CgroupCPUUsage) ProtoMessage()    {}
+
+// CgroupMemoryStat is the cgroup memory statistics
+type CgroupMemoryStat struct {
+	Usage *CgroupMemoryEntry `protobuf:"bytes,33,opt,name=usage" json:"usage,omitempty"`
+}
+
+func (m *CgroupMemoryStat) Reset()         { *m = CgroupMemoryStat{} }
+func (m *CgroupMemoryStat) String() string { return proto.CompactTextString(m) }
+func (*CgroupMemoryStat) ProtoMessage()    {}
+
+// CgroupMemoryEntry is the usage and limit of single memory type in bytes
+type CgroupMemoryEntry struct {
+	Limit uint64 `protobuf:"varint,1,opt,name=limit" json:"limit,omitempty"`
+	Usage uint64 `protobuf:"varint,2,opt,name=usage" json:"usage,omitempty"`
+	Max   uint64 `protobuf:"varint,3,opt,name=max" json:"max,omitempty"`
+}
+
+func (m *CgroupMemoryEntry) Reset()         { *m = CgroupMemoryEntry{} }
+func (m *CgroupMemoryEntry) String() string { return proto.CompactTextString(m) }
+func (*CgroupMemoryEntry) ProtoMessage()    {}
+
+// CgroupBlkIOStat is the cgroup blkio statistics
+type CgroupBlkIOStat struct {
+	IoServiceBytesRecursive []*CgroupBlkIOEntry `protobuf:"bytes,1,rep,name=io_service_bytes_recursive,json=ioServiceBytesRecursive" json:"io_service_bytes_recursive,omitempty"`
+}
+
+func (m *CgroupBlkIOStat) Reset()         { *m = CgroupBlkIOStat{} }
+func (m *CgroupBlkIOStat) String() string { return proto.CompactTextString(m) }
+func (*CgroupBlkIOStat) ProtoMessage()    {}
+
+// CgroupBlkIOEntry is single block device counter, e.g. bytes read from the device
+type CgroupBlkIOEntry struct {
+	Op     string `protobuf:"bytes,1,opt,name=op" json:"op,omitempty"`
+	Device string `protobuf:"bytes,2,opt,name=device" json:"device,omitempty"`
+	Major  uint64 `protobuf:"varint,3,opt,name=major" json:"major,omitempty"`
+	Minor  uint64 `protobuf:"varint,4,opt,name=minor" json:"minor,omitempty"`
+	Value  uint64 `protobuf:"varint,5,opt,name=value" json:"value,omitempty"`
+}
+
+func (m *CgroupBlkIOEntry) Reset()         { *m = CgroupBlkIOEntry{} }
+func (m *CgroupBlkIOEntry) String() string { return proto.CompactTextString(m) }
+func (*CgroupBlkIOEntry) ProtoMessage()    {}
+
+// UnmarshalCgroupMetrics decodes the containerd task metrics data
+func UnmarshalCgroupMetrics(data []byte) (*CgroupMetrics, error) {
+	metrics := &CgroupMetrics{}
+	if err := proto.Unmarshal(data, metrics); err != nil {
+		return nil, errors.Wrap(err, "Failed to unmarshal cgroup metrics")
+	}
+	return metrics, nil
+}
+
+// GetBlkioBytes return total bytes read and written to all block devices
+func (m *CgroupMetrics) GetBlkioBytes() (read, write uint64) {
+	if m.Blkio == nil {
+		return 0, 0
+	}
+	for _, entry := range m.Blkio.IoServiceBytesRecursive {
+		switch entry.Op {
+		case "Read", "read":
+			read += entry.Value
+		case "Write", "write":
+			write += entry.Value
+		}
+	}
+	return read, write
+}
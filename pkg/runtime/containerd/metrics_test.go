@@ -0,0 +1,32 @@
+package containerd
+
+import (
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUnmarshalCgroupMetrics(t *testing.T) {
+	data, err := proto.Marshal(&CgroupMetrics{
+		CPU:    &CgroupCPUStat{Usage: &CgroupCPUUsage{Total: 12345}},
+		Memory: &CgroupMemoryStat{Usage: &CgroupMemoryEntry{Usage: 1024, Limit: 4096}},
+		Blkio: &CgroupBlkIOStat{IoServiceBytesRecursive: []*CgroupBlkIOEntry{
+			{Op: "Read", Value: 10},
+			{Op: "Write", Value: 20},
+			{Op: "Total", Value: 30},
+			{Op: "Read", Value: 5},
+		}},
+	})
+	assert.NoError(t, err)
+
+	metrics, err := UnmarshalCgroupMetrics(data)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(12345), metrics.CPU.Usage.Total)
+	assert.Equal(t, uint64(1024), metrics.Memory.Usage.Usage)
+	assert.Equal(t, uint64(4096), metrics.Memory.Usage.Limit)
+
+	read, write := metrics.GetBlkioBytes()
+	assert.Equal(t, uint64(15), read)
+	assert.Equal(t, uint64(20), write)
+}
@@ -2,12 +2,19 @@ package containerd
 
 import (
 	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
 	"strings"
 
+	"github.com/containerd/containerd"
+	"github.com/containerd/containerd/api/types"
 	"github.com/containerd/containerd/containers"
 	"github.com/containerd/containerd/oci"
+	"github.com/containerd/typeurl"
 	"github.com/ernoaapa/eliot/pkg/model"
 	"github.com/ernoaapa/eliot/pkg/runtime/containerd/mapping"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
 	specs "github.com/opencontainers/runtime-spec/specs-go"
 )
 
@@ -30,6 +37,100 @@ func WithEnv(overrides []string) oci.SpecOpts {
 	}
 }
 
+// WithTaskCheckpoint is containerd.NewTaskOpts implementation what restores the task from the checkpoint blob
+// in the content store, instead of starting new process. Restoring requires CRIU in the node.
+func WithTaskCheckpoint(checkpoint ocispec.Descriptor) containerd.NewTaskOpts {
+	return func(_ context.Context, _ *containerd.Client, info *containerd.TaskInfo) error {
+		info.Checkpoint = &types.Descriptor{
+			MediaType: checkpoint.MediaType,
+			Size_:     checkpoint.Size,
+			Digest:    checkpoint.Digest,
+		}
+		return nil
+	}
+}
+
+// WithUpdatedEnv is containerd.UpdateContainerOpts implementation what sets the environment variables
+// in the stored container spec. Running task keeps the environment it was started with.
+func WithUpdatedEnv(env map[string]string) containerd.UpdateContainerOpts {
+	return func(_ context.Context, _ *containerd.Client, c *containers.Container) error {
+		if c.Spec == nil {
+			return fmt.Errorf("Container [%s] doesn't have spec", c.ID)
+		}
+		var spec specs.Spec
+		if err := json.Unmarshal(c.Spec.Value, &spec); err != nil {
+			return err
+		}
+		if spec.Process == nil {
+			return fmt.Errorf("Container [%s] spec doesn't have process", c.ID)
+		}
+
+		overrides := make([]string, 0, len(env))
+		for key, value := range env {
+			overrides = append(overrides, key+"="+value)
+		}
+		sort.Strings(overrides)
+		spec.Process.Env = replaceOrAppendEnvValues(spec.Process.Env, overrides)
+
+		any, err := typeurl.MarshalAny(&spec)
+		if err != nil {
+			return err
+		}
+		c.Spec = any
+		return nil
+	}
+}
+
+// WithUpdatedResources is containerd.UpdateContainerOpts implementation what sets the memory and CPU limits
+// in the stored container spec, so that the limits are kept when the task gets started again.
+// Only the limits set in the update are changed.
+func WithUpdatedResources(update *specs.LinuxResources) containerd.UpdateContainerOpts {
+	return func(_ context.Context, _ *containerd.Client, c *containers.Container) error {
+		if c.Spec == nil {
+			return fmt.Errorf("Container [%s] doesn't have spec", c.ID)
+		}
+		var spec specs.Spec
+		if err := json.Unmarshal(c.Spec.Value, &spec); err != nil {
+			return err
+		}
+		if spec.Linux == nil {
+			spec.Linux = &specs.Linux{}
+		}
+		if spec.Linux.Resources == nil {
+			spec.Linux.Resources = &specs.LinuxResources{}
+		}
+		resources := spec.Linux.Resources
+
+		if update.Memory != nil && update.Memory.Limit != nil {
+			if resources.Memory == nil {
+				resources.Memory = &specs.LinuxMemory{}
+			}
+			resources.Memory.Limit = update.Memory.Limit
+		}
+		if update.CPU != nil {
+			if resources.CPU == nil {
+				resources.CPU = &specs.LinuxCPU{}
+			}
+			if update.CPU.Shares != nil {
+				resources.CPU.Shares = update.CPU.Shares
+			}
+			if update.CPU.Quota != nil {
+				resources.CPU.Quota = update.CPU.Quota
+			}
+			if update.CPU.Period != nil {
+				resources.CPU.Period = update.CPU.Period
+			}
+		}
+
+		any, err := typeurl.MarshalAny(&spec)
+		if err != nil {
+			return err
+		}
+		c.Spec = any
+		return nil
+	}
+}
+
 // replaceOrAppendEnvValues returns the defaults with the overrides either
 // replaced by env key or appended to the list
 func replaceOrAppendEnvValues(defaults, overrides []string) []string {
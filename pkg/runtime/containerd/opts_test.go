@@ -1,8 +1,13 @@
 package containerd
 
 import (
+	"context"
+	"encoding/json"
 	"testing"
 
+	"github.com/containerd/containerd/containers"
+	"github.com/containerd/typeurl"
+	specs "github.com/opencontainers/runtime-spec/specs-go"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -21,3 +26,33 @@ func TestReplaceOrAppendEnvValues(t *testing.T) {
 		"OTHER=keep",
 	}, result)
 }
+
+func TestWithUpdatedEnv(t *testing.T) {
+	spec, err := typeurl.MarshalAny(&specs.Spec{Process: &specs.Process{Env: []string{"PATH=/bin", "LEVEL=info"}}})
+	assert.NoError(t, err)
+	container := &containers.Container{ID: "foo", Spec: spec}
+
+	err = WithUpdatedEnv(map[string]string{"LEVEL": "debug", "NEW": "value"})(context.Background(), nil, container)
+	assert.NoError(t, err)
+
+	var updated specs.Spec
+	assert.NoError(t, json.Unmarshal(container.Spec.Value, &updated))
+	assert.Equal(t, []string{"PATH=/bin", "LEVEL=debug", "NEW=value"}, updated.Process.Env)
+}
+
+func TestWithUpdatedResources(t *testing.T) {
+	limit, shares := int64(64*1024*1024), uint64(512)
+	spec, err := typeurl.MarshalAny(&specs.Spec{Linux: &specs.Linux{Resources: &specs.LinuxResources{
+		CPU: &specs.LinuxCPU{Shares: &shares},
+	}}})
+	assert.NoError(t, err)
+	container := &containers.Container{ID: "foo", Spec: spec}
+
+	err = WithUpdatedResources(&specs.LinuxResources{Memory: &specs.LinuxMemory{Limit: &limit}})(context.Background(), nil, container)
+	assert.NoError(t, err)
+
+	var updated specs.Spec
+	assert.NoError(t, json.Unmarshal(container.Spec.Value, &updated))
+	assert.Equal(t, limit, *updated.Linux.Resources.Memory.Limit)
+	assert.Equal(t, shares, *updated.Linux.Resources.CPU.Shares, "should keep the limits not in the update")
+}
@@ -37,6 +37,10 @@ func UpdateFetchProgress(done <-chan struct{}, client *containerd.Client, progre
 			}
 
 			for _, layer := range filter(progress.GetLayers(), activeDownloads) {
+				if isExtracting(layer) {
+					continue
+				}
+
 				info, err := client.ContentStore().Info(ctx, digest.FromString(layer.Digest))
 
 				if err != nil {
@@ -55,6 +59,11 @@ func UpdateFetchProgress(done <-chan struct{}, client *containerd.Client, progre
 	}
 }
 
+// isExtracting return true if the layer is downloaded and the pull is unpacking the image
+func isExtracting(status progress.Status) bool {
+	return status.Status == progress.StatusExtracting
+}
+
 func filter(statuses []progress.Status, refs []string) (result []progress.Status) {
 	for _, status := range statuses {
 		if !contains(refs, status.Ref) {
@@ -0,0 +1,125 @@
+package runtime
+
+import (
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/containerd/containerd"
+	"github.com/containerd/containerd/errdefs"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+)
+
+// DiskUsage is the container filesystem usage at the moment
+type DiskUsage struct {
+	Time time.Time
+	// WritableBytes is the size of the container writable layer, zero if the root filesystem is read-only
+	WritableBytes int64
+	// Mounts is the usage of each bind mount of the container
+	Mounts []MountUsage
+}
+
+// MountUsage is the disk space used by the files in the bind mount source directory.
+// Read-only mounts are not measured, the container cannot write to them, so UsedBytes is zero.
+type MountUsage struct {
+	Source      string
+	Destination string
+	UsedBytes   int64
+}
+
+// GetContainerFilesystemUsage returns the container writable layer size and the size of each bind mount.
+// Returns ErrNotRunning if the container task is not running, so the usage is tracked only while the container runs.
+func (c *ContainerdClient) GetContainerFilesystemUsage(namespace, name string) (DiskUsage, error) {
+	ctx, cancel := c.getContext()
+	defer cancel()
+
+	client, err := c.getConnection(namespace)
+	if err != nil {
+		return DiskUsage{}, err
+	}
+
+	container, err := client.LoadContainer(ctx, name)
+	if err != nil {
+		if errdefs.IsNotFound(err) {
+			return DiskUsage{}, ErrWithMessagef(ErrNotFound, "Container [%s] not found", name)
+		}
+		return DiskUsage{}, errors.Wrapf(err, "Failed to load container [%s], cannot get disk usage", name)
+	}
+
+	task, err := container.Task(ctx, nil)
+	if err != nil {
+		if errdefs.IsNotFound(err) {
+			return DiskUsage{}, ErrWithMessagef(ErrNotRunning, "Container [%s] is not running", name)
+		}
+		return DiskUsage{}, errors.Wrapf(err, "Unable to get task in container [%s], cannot get disk usage", name)
+	}
+
+	status, err := task.Status(ctx)
+	if err != nil {
+		return DiskUsage{}, errors.Wrapf(err, "Failed to resolve container [%s] task status", name)
+	}
+	if status.Status != containerd.Running {
+		return DiskUsage{}, ErrWithMessagef(ErrNotRunning, "Container [%s] is not running (%s)", name, status.Status)
+	}
+
+	info, err := container.Info(ctx)
+	if err != nil {
+		return DiskUsage{}, errors.Wrap(err, "Error while fetching container info")
+	}
+
+	spec, err := container.Spec(ctx)
+	if err != nil {
+		return DiskUsage{}, errors.Wrapf(err, "Failed to read container [%s] spec", name)
+	}
+
+	result := DiskUsage{Time: time.Now()}
+	if spec.Root != nil && !spec.Root.Readonly && info.SnapshotKey != "" {
+		usage, err := client.SnapshotService(info.Snapshotter).Usage(ctx, info.SnapshotKey)
+		if err != nil {
+			return DiskUsage{}, errors.Wrapf(err, "Failed to resolve container [%s] writable layer usage", name)
+		}
+		result.WritableBytes = usage.Size
+	}
+
+	for _, mount := range spec.Mounts {
+		if mount.Type != "bind" || !filepath.IsAbs(mount.Source) {
+			continue
+		}
+		usage := MountUsage{
+			Source:      mount.Source,
+			Destination: mount.Destination,
+		}
+		if !isReadOnlyMount(mount.Options) {
+			usage.UsedBytes = getDirSize(mount.Source)
+		}
+		result.Mounts = append(result.Mounts, usage)
+	}
+	return result, nil
+}
+
+// isReadOnlyMount return true if the mount options make the mount read-only
+func isReadOnlyMount(options []string) bool {
+	for _, option := range options {
+		if option == "ro" {
+			return true
+		}
+	}
+	return false
+}
+
+// getDirSize return the total size of the regular files in the directory, or the size of the file.
+// Files which cannot be read, or which get removed during the walk, are skipped.
+func getDirSize(path string) (size int64) {
+	filepath.Walk(path, func(file string, info os.FileInfo, err error) error {
+		if err != nil {
+			log.Debugf("Skip [%s] in disk usage: %s", file, err)
+			return nil
+		}
+		if info.Mode().IsRegular() {
+			size += info.Size()
+		}
+		return nil
+	})
+	return size
+}
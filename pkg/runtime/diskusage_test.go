@@ -0,0 +1,31 @@
+package runtime
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetDirSize(t *testing.T) {
+	dir, err := ioutil.TempDir("", "diskusage-test")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	assert.NoError(t, os.MkdirAll(filepath.Join(dir, "data", "logs"), 0755))
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(dir, "data", "db"), make([]byte, 100), 0644))
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(dir, "data", "logs", "app.log"), make([]byte, 20), 0644))
+	assert.NoError(t, os.Symlink(filepath.Join(dir, "data", "db"), filepath.Join(dir, "link")))
+
+	assert.Equal(t, int64(120), getDirSize(dir), "should count regular files only, not follow links")
+	assert.Equal(t, int64(20), getDirSize(filepath.Join(dir, "data", "logs", "app.log")))
+	assert.Equal(t, int64(0), getDirSize(filepath.Join(dir, "missing")))
+}
+
+func TestIsReadOnlyMount(t *testing.T) {
+	assert.True(t, isReadOnlyMount([]string{"rbind", "ro"}))
+	assert.False(t, isReadOnlyMount([]string{"rbind", "rw"}))
+	assert.False(t, isReadOnlyMount(nil))
+}
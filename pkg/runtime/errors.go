@@ -12,6 +12,8 @@ var (
 	ErrNotFound      = errors.New("not found")
 	ErrAlreadyExists = errors.New("already exists")
 	ErrNotSupported  = errors.New("not supported")
+	ErrNotRunning    = errors.New("not running")
+	ErrUnauthorized  = errors.New("unauthorized")
 )
 
 // IsNotFound returns true if the error is due to a missing resource
@@ -0,0 +1,171 @@
+package runtime
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/containerd/containerd/events"
+	opts "github.com/ernoaapa/eliot/pkg/runtime/containerd"
+)
+
+// EventType is the kind of runtime event
+type EventType string
+
+// Event types what the runtime publishes
+const (
+	EventImagePulled          EventType = "ImagePulled"
+	EventImagePullFailed      EventType = "ImagePullFailed"
+	EventContainerCreated     EventType = "ContainerCreated"
+	EventContainerStarted     EventType = "ContainerStarted"
+	EventContainerStartFailed EventType = "ContainerStartFailed"
+	EventContainerExited      EventType = "ContainerExited"
+	EventContainerOOMKilled   EventType = "ContainerOOMKilled"
+	EventContainerDeleted     EventType = "ContainerDeleted"
+)
+
+// EventTypes is list of all known event types
+var EventTypes = []EventType{
+	EventImagePulled,
+	EventImagePullFailed,
+	EventContainerCreated,
+	EventContainerStarted,
+	EventContainerStartFailed,
+	EventContainerExited,
+	EventContainerOOMKilled,
+	EventContainerDeleted,
+}
+
+// eventBufferSize is how many events can wait for a slow subscriber before new events get dropped
+const eventBufferSize = 100
+
+// Event is something that happened in the runtime, e.g. container exited
+type Event struct {
+	Type      EventType
+	Namespace string
+	// PodName is empty if the event is not related to any pod, e.g. image events
+	PodName string
+	// ContainerID is empty if the event is not related to any container
+	ContainerID string
+	// Image is set in image events
+	Image   string
+	Time    time.Time
+	Message string
+}
+
+// EventBroker delivers the events which eliot itself publishes, e.g. failures to start containers,
+// containerd doesn't publish events about those
+type EventBroker struct {
+	mu          sync.Mutex
+	subscribers map[chan Event]string
+}
+
+// NewEventBroker creates new EventBroker without subscribers
+func NewEventBroker() *EventBroker {
+	return &EventBroker{
+		subscribers: map[chan Event]string{},
+	}
+}
+
+// Subscribe returns channel which receives the events in the namespace, or in all namespaces if the namespace is empty.
+// The returned function must be called to stop the subscription.
+func (b *EventBroker) Subscribe(namespace string) (<-chan Event, func()) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	c := make(chan Event, eventBufferSize)
+	b.subscribers[c] = namespace
+	return c, func() {
+		b.mu.Lock()
+		defer b.mu.Unlock()
+		delete(b.subscribers, c)
+	}
+}
+
+// Publish sends the event to the subscribers, the event get dropped for subscribers which are not keeping up
+func (b *EventBroker) Publish(event Event) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for c, namespace := range b.subscribers {
+		if namespace != "" && namespace != event.Namespace {
+			continue
+		}
+		select {
+		case c <- event:
+		default:
+		}
+	}
+}
+
+// eventTopics are the containerd event topics what get mapped to the runtime events
+var eventTopics = []string{
+	opts.TaskStartTopic,
+	opts.TaskExitTopic,
+	opts.TaskOOMTopic,
+	opts.ContainerCreateTopic,
+	opts.ContainerDeleteTopic,
+	opts.ImageCreateTopic,
+}
+
+// eventFilters returns containerd subscription filters for the topics in the namespace, or in all namespaces if empty
+func eventFilters(namespace string) []string {
+	filters := []string{}
+	for _, topic := range eventTopics {
+		filter := fmt.Sprintf(`topic=="%s"`, topic)
+		if namespace != "" {
+			filter += fmt.Sprintf(`,namespace=="%s"`, namespace)
+		}
+		filters = append(filters, filter)
+	}
+	return filters
+}
+
+// mapEnvelope maps containerd event to runtime event, returns false if the event should be skipped.
+// PodName is not resolved because the event contains only the container id.
+func mapEnvelope(envelope *events.Envelope) (Event, bool, error) {
+	if envelope.Event == nil {
+		return Event{}, false, nil
+	}
+	payload, err := opts.UnmarshalEvent(envelope.Topic, envelope.Event.Value)
+	if err != nil || payload == nil {
+		return Event{}, false, err
+	}
+
+	event := Event{
+		Namespace: envelope.Namespace,
+		Time:      envelope.Timestamp,
+	}
+	switch e := payload.(type) {
+	case *opts.TaskStart:
+		event.Type = EventContainerStarted
+		event.ContainerID = e.ContainerID
+		event.Message = fmt.Sprintf("Container started (pid %d)", e.Pid)
+	case *opts.TaskExit:
+		if e.ID != e.ContainerID {
+			// Exec processes exit too, only the container main process is interesting
+			return Event{}, false, nil
+		}
+		event.Type = EventContainerExited
+		event.ContainerID = e.ContainerID
+		event.Message = fmt.Sprintf("Container exited with code %d", e.ExitStatus)
+	case *opts.TaskOOM:
+		event.Type = EventContainerOOMKilled
+		event.ContainerID = e.ContainerID
+		event.Message = "Container killed because it run out of memory"
+	case *opts.ContainerCreate:
+		event.Type = EventContainerCreated
+		event.ContainerID = e.ID
+		event.Image = e.Image
+		event.Message = fmt.Sprintf("Container created from image [%s]", e.Image)
+	case *opts.ContainerDelete:
+		event.Type = EventContainerDeleted
+		event.ContainerID = e.ID
+		event.Message = "Container deleted"
+	case *opts.ImageCreate:
+		event.Type = EventImagePulled
+		event.Image = e.Name
+		event.Message = fmt.Sprintf("Image [%s] pulled", e.Name)
+	default:
+		return Event{}, false, nil
+	}
+	return event, true, nil
+}
@@ -0,0 +1,60 @@
+package runtime
+
+import (
+	"testing"
+	"time"
+
+	"github.com/containerd/containerd/events"
+	opts "github.com/ernoaapa/eliot/pkg/runtime/containerd"
+	"github.com/gogo/protobuf/types"
+	"github.com/golang/protobuf/proto"
+	"github.com/stretchr/testify/assert"
+)
+
+func newEnvelope(t *testing.T, topic string, event proto.Message) *events.Envelope {
+	data, err := proto.Marshal(event)
+	assert.NoError(t, err)
+	return &events.Envelope{
+		Timestamp: time.Now(),
+		Namespace: "eliot",
+		Topic:     topic,
+		Event:     &types.Any{Value: data},
+	}
+}
+
+func TestMapEnvelope(t *testing.T) {
+	event, ok, err := mapEnvelope(newEnvelope(t, opts.TaskExitTopic, &opts.TaskExit{ContainerID: "foo", ID: "foo", ExitStatus: 137}))
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, EventContainerExited, event.Type)
+	assert.Equal(t, "eliot", event.Namespace)
+	assert.Equal(t, "foo", event.ContainerID)
+	assert.Equal(t, "Container exited with code 137", event.Message)
+
+	event, ok, err = mapEnvelope(newEnvelope(t, opts.ImageCreateTopic, &opts.ImageCreate{Name: "docker.io/library/nginx:latest"}))
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, EventImagePulled, event.Type)
+	assert.Equal(t, "docker.io/library/nginx:latest", event.Image)
+}
+
+func TestMapEnvelopeSkipsExecExit(t *testing.T) {
+	_, ok, err := mapEnvelope(newEnvelope(t, opts.TaskExitTopic, &opts.TaskExit{ContainerID: "foo", ID: "exec-1"}))
+	assert.NoError(t, err)
+	assert.False(t, ok)
+}
+
+func TestEventBrokerFiltersNamespace(t *testing.T) {
+	broker := NewEventBroker()
+	eliot, unsubscribe := broker.Subscribe("eliot")
+	defer unsubscribe()
+	all, unsubscribeAll := broker.Subscribe("")
+	defer unsubscribeAll()
+
+	broker.Publish(Event{Type: EventImagePullFailed, Namespace: "other"})
+	broker.Publish(Event{Type: EventContainerStartFailed, Namespace: "eliot"})
+
+	assert.Equal(t, EventContainerStartFailed, (<-eliot).Type)
+	assert.Equal(t, EventImagePullFailed, (<-all).Type)
+	assert.Equal(t, EventContainerStartFailed, (<-all).Type)
+}
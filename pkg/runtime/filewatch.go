@@ -0,0 +1,33 @@
+package runtime
+
+import "time"
+
+// watchFilePollInterval is how often WatchFile checks if the missing directory of the watched file has been created
+const watchFilePollInterval = time.Second
+
+// FileOp is the kind of change to the watched file
+type FileOp string
+
+// File operations what WatchFile reports
+const (
+	FileCreated FileOp = "create"
+	FileWritten FileOp = "write"
+	FileRemoved FileOp = "remove"
+)
+
+// FileEvent is change to the watched file
+type FileEvent struct {
+	Op FileOp
+	// Path is the watched path in the container
+	Path string
+	Time time.Time
+}
+
+// WatchFile calls the handler for each change of the file in the container until the done channel get closed.
+// The file doesn't need to exist, nor its directory, the changes are reported once they get created.
+// If the container is stopped or restarts, the watch continues when the container is running again.
+func (c *ContainerdClient) WatchFile(namespace, name, path string, done <-chan struct{}, handler func(FileEvent) error) error {
+	return watchFile(func() (string, error) {
+		return c.getContainerRoot(namespace, name)
+	}, path, done, handler)
+}
@@ -0,0 +1,127 @@
+package runtime
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+	"unsafe"
+
+	"github.com/pkg/errors"
+	"golang.org/x/sys/unix"
+)
+
+// watchFileMask are the inotify events of the directory entries what get mapped to FileEvents.
+// Directory is watched instead of the file, so that the file can be created later or replaced by renaming.
+const watchFileMask = unix.IN_CREATE | unix.IN_MOVED_TO | unix.IN_CLOSE_WRITE | unix.IN_DELETE | unix.IN_MOVED_FROM |
+	unix.IN_DELETE_SELF | unix.IN_MOVE_SELF
+
+// errWatchedDirGone is returned when the watched directory gets removed or the container root goes away
+var errWatchedDirGone = errors.New("watched directory removed")
+
+// watchFile calls the handler for changes of the file in the path inside the root, until the done channel get closed.
+// The root is resolved again when the watched directory goes away, e.g. the container restarts.
+func watchFile(resolveRoot func() (string, error), path string, done <-chan struct{}, handler func(FileEvent) error) error {
+	for {
+		dir, err := waitDir(resolveRoot, path, done)
+		if err != nil || dir == "" {
+			return err
+		}
+
+		if err := watchDir(dir, filepath.Base(filepath.Clean("/"+path)), path, done, handler); err != errWatchedDirGone {
+			return err
+		}
+	}
+}
+
+// waitDir waits until the directory of the path exists in the root and return it, or empty if the done channel get closed
+func waitDir(resolveRoot func() (string, error), path string, done <-chan struct{}) (string, error) {
+	for {
+		root, err := resolveRoot()
+		if err == nil {
+			file, err := resolveInRoot(root, path)
+			if err != nil {
+				return "", err
+			}
+			if info, err := os.Stat(filepath.Dir(file)); err == nil && info.IsDir() {
+				return filepath.Dir(file), nil
+			}
+		} else if errors.Cause(err) != ErrNotRunning {
+			return "", err
+		}
+
+		select {
+		case <-done:
+			return "", nil
+		case <-time.After(watchFilePollInterval):
+		}
+	}
+}
+
+// watchDir calls the handler for the inotify events of the file name in the directory
+func watchDir(dir, name, path string, done <-chan struct{}, handler func(FileEvent) error) error {
+	fd, err := unix.InotifyInit1(unix.IN_CLOEXEC | unix.IN_NONBLOCK)
+	if err != nil {
+		return errors.Wrap(err, "Failed to initialise inotify")
+	}
+	// Non-blocking file uses the runtime poller, so closing it interrupts the blocking read
+	events := os.NewFile(uintptr(fd), "inotify")
+	defer events.Close()
+
+	if _, err := unix.InotifyAddWatch(fd, dir, watchFileMask); err != nil {
+		if err == unix.ENOENT {
+			return errWatchedDirGone
+		}
+		return errors.Wrapf(err, "Failed to watch the directory of [%s]", path)
+	}
+
+	stop := make(chan struct{})
+	defer close(stop)
+	go func() {
+		select {
+		case <-done:
+			events.Close()
+		case <-stop:
+		}
+	}()
+
+	buf := make([]byte, 4096)
+	for {
+		n, err := events.Read(buf)
+		if err != nil {
+			select {
+			case <-done:
+				return nil
+			default:
+				return errors.Wrapf(err, "Failed to read changes of [%s]", path)
+			}
+		}
+
+		for offset := 0; offset+unix.SizeofInotifyEvent <= n; {
+			event := (*unix.InotifyEvent)(unsafe.Pointer(&buf[offset]))
+			start := offset + unix.SizeofInotifyEvent
+			offset = start + int(event.Len)
+
+			if event.Mask&(unix.IN_DELETE_SELF|unix.IN_MOVE_SELF|unix.IN_IGNORED|unix.IN_UNMOUNT) != 0 {
+				return errWatchedDirGone
+			}
+			if strings.TrimRight(string(buf[start:offset]), "\x00") != name {
+				continue
+			}
+
+			if err := handler(FileEvent{Op: mapInotifyMask(event.Mask), Path: path, Time: time.Now()}); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func mapInotifyMask(mask uint32) FileOp {
+	switch {
+	case mask&(unix.IN_CREATE|unix.IN_MOVED_TO) != 0:
+		return FileCreated
+	case mask&(unix.IN_DELETE|unix.IN_MOVED_FROM) != 0:
+		return FileRemoved
+	}
+	return FileWritten
+}
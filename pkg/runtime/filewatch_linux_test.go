@@ -0,0 +1,109 @@
+package runtime
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWatchFileWaitsDirectoryToBeCreated(t *testing.T) {
+	root, _ := ioutil.TempDir("", "root")
+	defer os.RemoveAll(root)
+	dir := filepath.Join(root, "etc", "app")
+	file := filepath.Join(dir, "config.yml")
+
+	events := make(chan FileEvent, 100)
+	done := make(chan struct{})
+	result := make(chan error)
+	go func() {
+		result <- watchFile(func() (string, error) { return root, nil }, "/etc/app/config.yml", done, func(event FileEvent) error {
+			events <- event
+			return nil
+		})
+	}()
+
+	assert.NoError(t, os.MkdirAll(dir, 0755))
+	// Write until the watch notices, the directory is checked only once in watchFilePollInterval
+	timeout := time.After(3 * watchFilePollInterval)
+	for len(events) == 0 {
+		assert.NoError(t, ioutil.WriteFile(file, []byte("foo: bar"), 0600))
+		select {
+		case <-timeout:
+			t.Fatal("WatchFile didn't notice the created file")
+		case <-time.After(50 * time.Millisecond):
+		}
+	}
+	drain(events)
+
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(dir, "other.yml"), []byte("other"), 0600))
+	assert.NoError(t, os.Remove(file))
+	assert.Equal(t, FileRemoved, nextFileEvent(t, events).Op, "should ignore other files")
+
+	assert.NoError(t, ioutil.WriteFile(file, []byte("foo: baz"), 0600))
+	event := nextFileEvent(t, events)
+	assert.Equal(t, FileCreated, event.Op)
+	assert.Equal(t, "/etc/app/config.yml", event.Path)
+	assert.Equal(t, FileWritten, nextFileEvent(t, events).Op)
+
+	close(done)
+	select {
+	case err := <-result:
+		assert.NoError(t, err)
+	case <-time.After(time.Second):
+		t.Fatal("WatchFile didn't return when done was closed")
+	}
+}
+
+func TestWatchFileWaitsDirectoryToBeCreatedAgain(t *testing.T) {
+	root, _ := ioutil.TempDir("", "root")
+	defer os.RemoveAll(root)
+	dir := filepath.Join(root, "app")
+	assert.NoError(t, os.Mkdir(dir, 0755))
+
+	events := make(chan FileEvent, 100)
+	done := make(chan struct{})
+	defer close(done)
+	go watchFile(func() (string, error) { return root, nil }, "/app/config.yml", done, func(event FileEvent) error {
+		events <- event
+		return nil
+	})
+
+	// Give the watch time to start, the removal must be from the watch, not from the initial check
+	time.Sleep(100 * time.Millisecond)
+	assert.NoError(t, os.RemoveAll(dir))
+	assert.NoError(t, os.Mkdir(dir, 0755))
+
+	timeout := time.After(3 * watchFilePollInterval)
+	for len(events) == 0 {
+		assert.NoError(t, ioutil.WriteFile(filepath.Join(dir, "config.yml"), []byte("foo: bar"), 0600))
+		select {
+		case <-timeout:
+			t.Fatal("WatchFile didn't notice the file in the created directory")
+		case <-time.After(50 * time.Millisecond):
+		}
+	}
+}
+
+func nextFileEvent(t *testing.T, events <-chan FileEvent) FileEvent {
+	select {
+	case event := <-events:
+		return event
+	case <-time.After(time.Second):
+		t.Fatal("Didn't receive file event")
+	}
+	return FileEvent{}
+}
+
+func drain(events <-chan FileEvent) {
+	for {
+		select {
+		case <-events:
+		case <-time.After(100 * time.Millisecond):
+			return
+		}
+	}
+}
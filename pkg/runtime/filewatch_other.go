@@ -0,0 +1,8 @@
+// +build !linux
+
+package runtime
+
+// watchFile is supported only in Linux, because it uses inotify
+func watchFile(resolveRoot func() (string, error), path string, done <-chan struct{}, handler func(FileEvent) error) error {
+	return ErrWithMessagef(ErrNotSupported, "Watching files is supported only in Linux")
+}
@@ -0,0 +1,96 @@
+package runtime
+
+import (
+	"time"
+
+	"github.com/containerd/containerd"
+	"github.com/containerd/containerd/errdefs"
+	"github.com/containerd/containerd/images"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+)
+
+// Image is image stored in the node
+type Image struct {
+	Name string
+	// Size is the total size of the image content, layers shared with other images are counted in each image
+	Size int64
+	// UpdatedAt is when the image was last pulled
+	UpdatedAt time.Time
+}
+
+// GetImages return the images in the namespace
+func (c *ContainerdClient) GetImages(namespace string) ([]Image, error) {
+	ctx, cancel := c.getContext()
+	defer cancel()
+
+	client, err := c.getConnection(namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	list, err := client.ImageService().List(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "Error while getting list of images")
+	}
+
+	result := []Image{}
+	for _, image := range list {
+		size, err := containerd.NewImage(client, image).Size(ctx)
+		if err != nil {
+			log.Warnf("Failed to resolve image [%s] size: %s", image.Name, err)
+		}
+		result = append(result, Image{
+			Name:      image.Name,
+			Size:      size,
+			UpdatedAt: image.UpdatedAt,
+		})
+	}
+	return result, nil
+}
+
+// DeleteImage removes the image, the layers get removed once no other image use them
+func (c *ContainerdClient) DeleteImage(namespace, name string) error {
+	ctx, cancel := c.getContext()
+	defer cancel()
+
+	client, err := c.getConnection(namespace)
+	if err != nil {
+		return err
+	}
+
+	if err := client.ImageService().Delete(ctx, name, images.SynchronousDelete()); err != nil {
+		if errdefs.IsNotFound(err) {
+			return ErrWithMessagef(ErrNotFound, "Image [%s] not found", name)
+		}
+		return errors.Wrapf(err, "Failed to delete image [%s]", name)
+	}
+	return nil
+}
+
+// GetContainerDiskUsage return how much disk space the container filesystem uses on top of the image
+func (c *ContainerdClient) GetContainerDiskUsage(namespace, name string) (int64, error) {
+	ctx, cancel := c.getContext()
+	defer cancel()
+
+	client, err := c.getConnection(namespace)
+	if err != nil {
+		return 0, err
+	}
+
+	container, err := client.LoadContainer(ctx, name)
+	if err != nil {
+		return 0, errors.Wrapf(err, "Failed to load container [%s]", name)
+	}
+
+	info, err := container.Info(ctx)
+	if err != nil {
+		return 0, errors.Wrap(err, "Error while fetching container info")
+	}
+
+	usage, err := client.SnapshotService(info.Snapshotter).Usage(ctx, info.SnapshotKey)
+	if err != nil {
+		return 0, errors.Wrapf(err, "Failed to resolve container [%s] disk usage", name)
+	}
+	return usage.Size, nil
+}
@@ -0,0 +1,91 @@
+package runtime
+
+import (
+	"time"
+
+	"github.com/containerd/containerd/errdefs"
+	specs "github.com/opencontainers/runtime-spec/specs-go"
+	"github.com/pkg/errors"
+)
+
+// ContainerInspect is everything the runtime knows about the container
+type ContainerInspect struct {
+	ID    string
+	Image string
+	// ImageDigest is the digest of the image manifest, empty if the image has been deleted
+	ImageDigest string
+	Labels      map[string]string
+	Runtime     string
+	Snapshotter string
+	SnapshotKey string
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+	// Status is the task status, e.g. running or stopped, empty if the container has no task
+	Status string
+	// Pid is the container main process id in the host, zero if the container has no task
+	Pid uint32
+	// Spec is the OCI runtime spec what the container runs with
+	Spec *specs.Spec
+}
+
+// Inspect return the container info, image and task state, and the OCI runtime spec
+func (c *ContainerdClient) Inspect(namespace, name string) (ContainerInspect, error) {
+	ctx, cancel := c.getContext()
+	defer cancel()
+
+	client, err := c.getConnection(namespace)
+	if err != nil {
+		return ContainerInspect{}, err
+	}
+
+	container, err := client.LoadContainer(ctx, name)
+	if err != nil {
+		if errdefs.IsNotFound(err) {
+			return ContainerInspect{}, ErrWithMessagef(ErrNotFound, "Container [%s] not found", name)
+		}
+		return ContainerInspect{}, errors.Wrapf(err, "Failed to load container [%s]", name)
+	}
+
+	info, err := container.Info(ctx)
+	if err != nil {
+		return ContainerInspect{}, errors.Wrapf(err, "Error while fetching container [%s] info", name)
+	}
+
+	spec, err := container.Spec(ctx)
+	if err != nil {
+		return ContainerInspect{}, errors.Wrapf(err, "Failed to read container [%s] spec", name)
+	}
+
+	result := ContainerInspect{
+		ID:          info.ID,
+		Image:       info.Image,
+		Labels:      info.Labels,
+		Runtime:     info.Runtime.Name,
+		Snapshotter: info.Snapshotter,
+		SnapshotKey: info.SnapshotKey,
+		CreatedAt:   info.CreatedAt,
+		UpdatedAt:   info.UpdatedAt,
+		Spec:        spec,
+	}
+
+	image, err := container.Image(ctx)
+	switch {
+	case err == nil:
+		result.ImageDigest = image.Target().Digest.String()
+	case !errdefs.IsNotFound(err):
+		return ContainerInspect{}, errors.Wrapf(err, "Failed to resolve container [%s] image", name)
+	}
+
+	task, err := container.Task(ctx, nil)
+	switch {
+	case err == nil:
+		result.Pid = task.Pid()
+		if status, err := task.Status(ctx); err == nil {
+			result.Status = string(status.Status)
+		}
+	case !errdefs.IsNotFound(err):
+		return ContainerInspect{}, errors.Wrapf(err, "Unable to get task in container [%s]", name)
+	}
+
+	return result, nil
+}
@@ -2,6 +2,7 @@ package runtime
 
 import (
 	"io"
+	"net"
 	"syscall"
 
 	"github.com/ernoaapa/eliot/pkg/model"
@@ -12,16 +13,46 @@ import (
 type Client interface {
 	GetPods(namespace string) ([]model.Pod, error)
 	GetPod(namespace, podName string) (model.Pod, error)
-	PullImage(namespace, ref string, status *progress.ImageFetch) error
+	SetPodLabels(namespace, podName string, labels map[string]string) error
+	RenamePod(namespace, podName, newName string) error
+	SetPodCordoned(namespace, podName string, cordoned bool) error
+	ResolveImage(ref string) (string, error)
+	PullImage(namespace, ref string, status *progress.ImageFetch, cancel <-chan struct{}) error
+	BuildImage(namespace string, buildContext io.Reader, opts BuildOptions, done <-chan struct{}, handler func(BuildLog) error) (digest string, err error)
+	TagImage(namespace, source, target string) error
+	PushImage(namespace, ref string, auth RegistryAuth, status *progress.ImageFetch, cancel <-chan struct{}) error
+	GetImages(namespace string) ([]Image, error)
+	DeleteImage(namespace, name string) error
 	CreateContainer(pod model.Pod, container model.Container) (model.ContainerStatus, error)
 	StartContainer(namespace, id string, io IOSet) (model.ContainerStatus, error)
 	StopContainer(namespace, id string) (model.ContainerStatus, error)
 	GetNamespaces() ([]string, error)
+	CreateNamespace(namespace string) error
 	IsContainerRunning(namespace, name string) (bool, error)
 	GetContainerTaskStatus(namespace, name string) string
-	Exec(namespace, podName, execID string, args []string, tty bool, attach AttachIO) error
+	GetContainerStatus(namespace, name string) (model.ContainerStatus, error)
+	SetContainerReadiness(namespace, name string, result ProbeResult)
+	Inspect(namespace, name string) (ContainerInspect, error)
+	Exec(namespace, podName, execID string, args []string, tty bool, attach AttachIO) (exitCode int, err error)
 	Attach(namespace, podName string, attach AttachIO) error
+	WaitContainer(namespace, name string, done <-chan struct{}) (exitCode int, err error)
 	Signal(namespace, name string, signal syscall.Signal) error
+	SetContainerEnv(namespace, name string, env map[string]string) error
+	UpdateContainerResources(namespace, name string, limits ResourceLimits) (ResourceLimits, error)
+	Logs(namespace, name string, opts LogOptions, done <-chan struct{}, handler func(LogLine) error) error
+	Events(namespace string, done <-chan struct{}, handler func(Event) error) error
+	GetContainerStats(namespace, name string) (ContainerStats, error)
+	GetContainerDiskUsage(namespace, name string) (int64, error)
+	GetContainerFilesystemUsage(namespace, name string) (DiskUsage, error)
+	Top(namespace, name string) ([]Process, error)
+	DialContainer(namespace, name string, port int) (net.Conn, error)
+	CopyTo(namespace, name, destPath string, archive io.Reader) error
+	CopyFrom(namespace, name, srcPath string, archive io.Writer) error
+	RemoveFrom(namespace, name, destPath string, paths []string) error
+	CheckpointContainer(namespace, name, dir string) error
+	RestoreContainer(namespace, name, dir string, io IOSet) (model.ContainerStatus, error)
+	WatchFile(namespace, name, path string, done <-chan struct{}, handler func(FileEvent) error) error
+	GetVersion() (string, error)
 }
 
 // AttachIO provides way to attach stdin,stdout and stderr to container
@@ -29,4 +60,12 @@ type AttachIO struct {
 	Stdin  io.Reader
 	Stdout io.Writer
 	Stderr io.Writer
+	// Resize receives client terminal size changes, nil if client don't have terminal
+	Resize <-chan TerminalSize
+}
+
+// TerminalSize is the client terminal width and height
+type TerminalSize struct {
+	Width  uint32
+	Height uint32
 }
@@ -0,0 +1,248 @@
+package runtime
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultLogBufferLines is how many latest output lines are kept in memory per container
+	DefaultLogBufferLines = 1000
+
+	// maxLogLineLength is maximum length of single line, longer lines get split
+	maxLogLineLength = 16 * 1024
+)
+
+// LogLine is single line of container output
+type LogLine struct {
+	Time   time.Time
+	Stderr bool
+	Line   []byte
+}
+
+// LogOptions defines which lines to return from container logs
+type LogOptions struct {
+	// Follow keeps streaming new lines until stopped
+	Follow bool
+	// Tail limits the result to last N lines. Zero means all lines.
+	Tail int
+	// Since filters out lines written before the time. Zero time means no filtering.
+	Since time.Time
+}
+
+// LogBuffer keeps latest output lines of single container in memory
+// and notifies followers when new lines arrive
+type LogBuffer struct {
+	mu       sync.Mutex
+	lines    []LogLine
+	max      int
+	first    int64 // sequence number of the first line in the buffer
+	attached int64 // sequence number of next line not yet delivered to any attached client
+	updated  chan struct{}
+	capture  chan struct{}
+}
+
+// NewLogBuffer creates new LogBuffer which keeps max number of lines
+func NewLogBuffer(max int) *LogBuffer {
+	return &LogBuffer{
+		max:     max,
+		updated: make(chan struct{}),
+	}
+}
+
+// Append adds line to the buffer and drops the oldest line if buffer is full
+func (b *LogBuffer) Append(line LogLine) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.lines = append(b.lines, line)
+	if len(b.lines) > b.max {
+		b.lines = b.lines[1:]
+		b.first++
+	}
+
+	close(b.updated)
+	b.updated = make(chan struct{})
+}
+
+// Capture reads container stdout and stderr line by line to the buffer until both reach EOF.
+// Nil reader is skipped, e.g. when stdout is piped to another container.
+func (b *LogBuffer) Capture(stdout, stderr io.Reader) {
+	b.mu.Lock()
+	done := make(chan struct{})
+	b.capture = done
+	b.mu.Unlock()
+
+	var wg sync.WaitGroup
+	for _, source := range []struct {
+		reader io.Reader
+		stderr bool
+	}{{stdout, false}, {stderr, true}} {
+		if source.reader == nil {
+			continue
+		}
+		wg.Add(1)
+		go func(reader io.Reader, stderr bool) {
+			defer wg.Done()
+			b.readLines(reader, stderr)
+		}(source.reader, source.stderr)
+	}
+
+	go func() {
+		wg.Wait()
+		b.mu.Lock()
+		defer b.mu.Unlock()
+		close(done)
+		if b.capture == done {
+			b.capture = nil
+		}
+	}()
+}
+
+// IsCapturing return true if container output is currently read to the buffer
+func (b *LogBuffer) IsCapturing() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.capture != nil
+}
+
+func (b *LogBuffer) readLines(reader io.Reader, stderr bool) {
+	buffered := bufio.NewReaderSize(reader, maxLogLineLength)
+	for {
+		line, err := buffered.ReadSlice('\n')
+		if len(line) > 0 {
+			b.Append(LogLine{
+				Time:   time.Now(),
+				Stderr: stderr,
+				Line:   append([]byte{}, line...),
+			})
+		}
+		if err != nil && err != bufio.ErrBufferFull {
+			return
+		}
+	}
+}
+
+// Lines return lines matching the options and sequence number of the next line
+func (b *LogBuffer) Lines(opts LogOptions) (result []LogLine, next int64) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for _, line := range b.lines {
+		if line.Time.Before(opts.Since) {
+			continue
+		}
+		result = append(result, line)
+	}
+
+	if opts.Tail > 0 && len(result) > opts.Tail {
+		result = result[len(result)-opts.Tail:]
+	}
+
+	return result, b.first + int64(len(b.lines))
+}
+
+// Follow calls handler for each line starting from the sequence number until stop channel closes.
+// All lines written before the stop are delivered before returning.
+func (b *LogBuffer) Follow(next int64, stop <-chan struct{}, handler func(LogLine) error) error {
+	return b.follow(next, stop, handler, false)
+}
+
+// FollowAttached is like Follow, but starts from the first line which is not yet delivered to any attached client.
+// This way the output written before anyone attached don't get lost, like with reading directly from the process stdout.
+func (b *LogBuffer) FollowAttached(stop <-chan struct{}, handler func(LogLine) error) error {
+	b.mu.Lock()
+	next := b.attached
+	b.mu.Unlock()
+
+	return b.follow(next, stop, handler, true)
+}
+
+func (b *LogBuffer) follow(next int64, stop <-chan struct{}, handler func(LogLine) error, attached bool) error {
+	for {
+		b.mu.Lock()
+		if next < b.first {
+			next = b.first
+		}
+		lines := append([]LogLine{}, b.lines[next-b.first:]...)
+		next += int64(len(lines))
+		if attached && next > b.attached {
+			b.attached = next
+		}
+		updated := b.updated
+		b.mu.Unlock()
+
+		for _, line := range lines {
+			if err := handler(line); err != nil {
+				return err
+			}
+		}
+
+		select {
+		case <-stop:
+			b.mu.Lock()
+			pending := b.first+int64(len(b.lines)) > next
+			b.mu.Unlock()
+			if !pending {
+				return nil
+			}
+		case <-updated:
+		}
+	}
+}
+
+// CaptureDone return channel which get closed when current capture ends.
+// If nothing is captured, the returned channel is already closed.
+func (b *LogBuffer) CaptureDone() <-chan struct{} {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.capture == nil {
+		done := make(chan struct{})
+		close(done)
+		return done
+	}
+	return b.capture
+}
+
+// LogStore holds LogBuffer for each container
+type LogStore struct {
+	mu      sync.Mutex
+	max     int
+	buffers map[string]*LogBuffer
+}
+
+// NewLogStore creates new LogStore where each container buffer keeps max number of lines
+func NewLogStore(max int) *LogStore {
+	return &LogStore{
+		max:     max,
+		buffers: map[string]*LogBuffer{},
+	}
+}
+
+// Get return the container LogBuffer, creates new one if not exist yet
+func (s *LogStore) Get(namespace, id string) *LogBuffer {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	key := logStoreKey(namespace, id)
+	if _, ok := s.buffers[key]; !ok {
+		s.buffers[key] = NewLogBuffer(s.max)
+	}
+	return s.buffers[key]
+}
+
+// Remove drops container LogBuffer
+func (s *LogStore) Remove(namespace, id string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.buffers, logStoreKey(namespace, id))
+}
+
+func logStoreKey(namespace, id string) string {
+	return fmt.Sprintf("%s/%s", namespace, id)
+}
@@ -0,0 +1,97 @@
+package runtime
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLogBufferLines(t *testing.T) {
+	buffer := NewLogBuffer(3)
+	start := time.Now()
+	for i := 0; i < 5; i++ {
+		buffer.Append(LogLine{Time: start.Add(time.Duration(i) * time.Second), Line: []byte(fmt.Sprintf("line %d\n", i))})
+	}
+
+	lines, next := buffer.Lines(LogOptions{})
+	assert.Equal(t, int64(5), next)
+	assert.Equal(t, []string{"line 2\n", "line 3\n", "line 4\n"}, toStrings(lines))
+
+	lines, _ = buffer.Lines(LogOptions{Tail: 2})
+	assert.Equal(t, []string{"line 3\n", "line 4\n"}, toStrings(lines))
+
+	lines, _ = buffer.Lines(LogOptions{Since: start.Add(4 * time.Second)})
+	assert.Equal(t, []string{"line 4\n"}, toStrings(lines))
+}
+
+func TestLogBufferFollowDoNotDropLinesAfterSnapshot(t *testing.T) {
+	buffer := NewLogBuffer(10)
+	buffer.Append(LogLine{Line: []byte("before\n")})
+
+	_, next := buffer.Lines(LogOptions{})
+	buffer.Append(LogLine{Line: []byte("between\n")})
+
+	stop := make(chan struct{})
+	close(stop)
+
+	result := []string{}
+	err := buffer.Follow(next, stop, func(line LogLine) error {
+		result = append(result, string(line.Line))
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"between\n"}, result)
+}
+
+func TestLogBufferCapture(t *testing.T) {
+	buffer := NewLogBuffer(10)
+	buffer.Capture(strings.NewReader("first\nsecond\nno newline"), bytes.NewBufferString("error\n"))
+	<-buffer.CaptureDone()
+
+	lines, _ := buffer.Lines(LogOptions{})
+	stdout := []string{}
+	stderr := []string{}
+	for _, line := range lines {
+		if line.Stderr {
+			stderr = append(stderr, string(line.Line))
+		} else {
+			stdout = append(stdout, string(line.Line))
+		}
+	}
+	assert.Equal(t, []string{"first\n", "second\n", "no newline"}, stdout)
+	assert.Equal(t, []string{"error\n"}, stderr)
+	assert.False(t, buffer.IsCapturing())
+}
+
+func TestLogBufferFollowAttachedDeliversOnlyUndeliveredLines(t *testing.T) {
+	buffer := NewLogBuffer(10)
+	buffer.Append(LogLine{Line: []byte("first\n")})
+
+	stop := make(chan struct{})
+	close(stop)
+
+	var first, second []string
+	buffer.FollowAttached(stop, func(line LogLine) error {
+		first = append(first, string(line.Line))
+		return nil
+	})
+	buffer.Append(LogLine{Line: []byte("second\n")})
+	buffer.FollowAttached(stop, func(line LogLine) error {
+		second = append(second, string(line.Line))
+		return nil
+	})
+
+	assert.Equal(t, []string{"first\n"}, first)
+	assert.Equal(t, []string{"second\n"}, second)
+}
+
+func toStrings(lines []LogLine) (result []string) {
+	for _, line := range lines {
+		result = append(result, string(line.Line))
+	}
+	return result
+}
@@ -0,0 +1,37 @@
+package runtime
+
+import "sync"
+
+// OOMStore keeps track of containers which the kernel killed because they run out of memory
+type OOMStore struct {
+	mu     sync.Mutex
+	killed map[string]bool
+}
+
+// NewOOMStore creates new empty OOMStore
+func NewOOMStore() *OOMStore {
+	return &OOMStore{
+		killed: map[string]bool{},
+	}
+}
+
+// Add marks the container to be killed because out of memory
+func (s *OOMStore) Add(namespace, id string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.killed[logStoreKey(namespace, id)] = true
+}
+
+// Remove clears the mark, e.g. when the container get started again
+func (s *OOMStore) Remove(namespace, id string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.killed, logStoreKey(namespace, id))
+}
+
+// IsKilled return true if the container has been killed because out of memory
+func (s *OOMStore) IsKilled(namespace, id string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.killed[logStoreKey(namespace, id)]
+}
@@ -0,0 +1,61 @@
+package runtime
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"runtime"
+	"time"
+
+	"github.com/pkg/errors"
+	"golang.org/x/sys/unix"
+)
+
+// portDialTimeout is how long to wait the connection to the port inside the container
+const portDialTimeout = 5 * time.Second
+
+// dialInNetworkNamespace connects to the TCP port in localhost of the process network namespace.
+// The socket gets created in the namespace, so the connection works even if the port is not exposed to the host.
+func dialInNetworkNamespace(pid uint32, port int) (net.Conn, error) {
+	type result struct {
+		conn net.Conn
+		err  error
+	}
+	resultc := make(chan result, 1)
+
+	// Switch the namespace in own goroutine, the thread gets thrown away if switching back fails
+	go func() {
+		runtime.LockOSThread()
+
+		original, err := os.Open(fmt.Sprintf("/proc/self/task/%d/ns/net", unix.Gettid()))
+		if err != nil {
+			runtime.UnlockOSThread()
+			resultc <- result{err: errors.Wrapf(err, "Failed to open current network namespace")}
+			return
+		}
+		defer original.Close()
+
+		target, err := os.Open(fmt.Sprintf("/proc/%d/ns/net", pid))
+		if err != nil {
+			runtime.UnlockOSThread()
+			resultc <- result{err: errors.Wrapf(err, "Failed to open process [%d] network namespace", pid)}
+			return
+		}
+		defer target.Close()
+
+		if err := unix.Setns(int(target.Fd()), unix.CLONE_NEWNET); err != nil {
+			runtime.UnlockOSThread()
+			resultc <- result{err: errors.Wrapf(err, "Failed to enter process [%d] network namespace", pid)}
+			return
+		}
+
+		conn, err := net.DialTimeout("tcp", fmt.Sprintf("127.0.0.1:%d", port), portDialTimeout)
+		if err := unix.Setns(int(original.Fd()), unix.CLONE_NEWNET); err == nil {
+			runtime.UnlockOSThread()
+		}
+		resultc <- result{conn: conn, err: err}
+	}()
+
+	res := <-resultc
+	return res.conn, res.err
+}
@@ -0,0 +1,184 @@
+package runtime
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// clockTicks is the kernel USER_HZ, the unit of process times in /proc/<pid>/stat
+const clockTicks = 100
+
+// Process is single process running inside the container
+type Process struct {
+	// PID is the process id inside the container pid namespace
+	PID uint32
+	// PPID is the parent process id inside the container pid namespace,
+	// zero if the parent is outside of the container (e.g. the init process)
+	PPID uint32
+	// HostPID is the process id in the host pid namespace
+	HostPID uint32
+	Command string
+	// CPUPercent is the CPU time used divided by the process running time, like in `ps`
+	CPUPercent float64
+	// RSSBytes is the process resident set size
+	RSSBytes uint64
+	// Init is true for the container main process (PID 1 inside the container)
+	Init bool
+}
+
+// readProcesses reads the process details from the proc filesystem.
+// Processes which exit while reading are left out from the result.
+func readProcesses(procRoot string, hostPids []uint32, initPid uint32) ([]Process, error) {
+	uptime, err := readUptime(procRoot)
+	if err != nil {
+		return nil, err
+	}
+
+	processes := []Process{}
+	for _, pid := range hostPids {
+		process, err := readProcess(procRoot, pid, uptime)
+		if err != nil {
+			if os.IsNotExist(errors.Cause(err)) {
+				continue
+			}
+			return nil, err
+		}
+		process.Init = pid == initPid
+		processes = append(processes, process)
+	}
+
+	// Parent pid is in the host namespace, map it to the container namespace
+	containerPids := map[uint32]uint32{}
+	for _, process := range processes {
+		containerPids[process.HostPID] = process.PID
+	}
+	for i := range processes {
+		processes[i].PPID = containerPids[processes[i].PPID]
+	}
+
+	sort.Slice(processes, func(i, j int) bool {
+		return processes[i].PID < processes[j].PID
+	})
+	return processes, nil
+}
+
+func readProcess(procRoot string, pid uint32, uptime float64) (Process, error) {
+	dir := filepath.Join(procRoot, strconv.FormatUint(uint64(pid), 10))
+
+	stat, err := ioutil.ReadFile(filepath.Join(dir, "stat"))
+	if err != nil {
+		return Process{}, errors.Wrapf(err, "Failed to read process [%d] stat", pid)
+	}
+
+	// Command name is in parentheses and can contain spaces, so the fields start after the last ')'
+	start := bytes.IndexByte(stat, '(')
+	end := bytes.LastIndexByte(stat, ')')
+	if start < 0 || end < start {
+		return Process{}, fmt.Errorf("Invalid process [%d] stat format", pid)
+	}
+	name := string(stat[start+1 : end])
+	// Fields from the state, which is the third field in proc(5)
+	fields := strings.Fields(string(stat[end+1:]))
+	if len(fields) < 22 {
+		return Process{}, fmt.Errorf("Invalid process [%d] stat format, expected at least 24 fields", pid)
+	}
+
+	ppid, err := strconv.ParseUint(fields[1], 10, 32)
+	if err != nil {
+		return Process{}, errors.Wrapf(err, "Invalid process [%d] parent pid", pid)
+	}
+	utime, err := strconv.ParseUint(fields[11], 10, 64)
+	if err != nil {
+		return Process{}, errors.Wrapf(err, "Invalid process [%d] user time", pid)
+	}
+	stime, err := strconv.ParseUint(fields[12], 10, 64)
+	if err != nil {
+		return Process{}, errors.Wrapf(err, "Invalid process [%d] system time", pid)
+	}
+	starttime, err := strconv.ParseUint(fields[19], 10, 64)
+	if err != nil {
+		return Process{}, errors.Wrapf(err, "Invalid process [%d] start time", pid)
+	}
+	rss, err := strconv.ParseInt(fields[21], 10, 64)
+	if err != nil {
+		return Process{}, errors.Wrapf(err, "Invalid process [%d] resident set size", pid)
+	}
+
+	cmdline, err := ioutil.ReadFile(filepath.Join(dir, "cmdline"))
+	if err != nil {
+		return Process{}, errors.Wrapf(err, "Failed to read process [%d] command line", pid)
+	}
+	command := strings.TrimSpace(strings.Replace(string(bytes.TrimRight(cmdline, "\x00")), "\x00", " ", -1))
+	if command == "" {
+		// Zombie processes and kernel threads don't have command line, show the name like ps does
+		command = fmt.Sprintf("[%s]", name)
+	}
+
+	nsPid, err := readNamespacePid(dir, pid)
+	if err != nil {
+		return Process{}, err
+	}
+
+	process := Process{
+		PID:     nsPid,
+		PPID:    uint32(ppid),
+		HostPID: pid,
+		Command: command,
+	}
+	if rss > 0 {
+		process.RSSBytes = uint64(rss) * uint64(os.Getpagesize())
+	}
+	if running := uptime - float64(starttime)/clockTicks; running > 0 {
+		process.CPUPercent = float64(utime+stime) / clockTicks / running * 100
+	}
+	return process, nil
+}
+
+// readNamespacePid return the process id in the innermost pid namespace, which is the last value of NSpid.
+// Returns the given pid if the kernel doesn't report NSpid.
+func readNamespacePid(dir string, pid uint32) (uint32, error) {
+	status, err := ioutil.ReadFile(filepath.Join(dir, "status"))
+	if err != nil {
+		return 0, errors.Wrapf(err, "Failed to read process [%d] status", pid)
+	}
+
+	for _, line := range strings.Split(string(status), "\n") {
+		if !strings.HasPrefix(line, "NSpid:") {
+			continue
+		}
+		values := strings.Fields(strings.TrimPrefix(line, "NSpid:"))
+		if len(values) == 0 {
+			break
+		}
+		nsPid, err := strconv.ParseUint(values[len(values)-1], 10, 32)
+		if err != nil {
+			return 0, errors.Wrapf(err, "Invalid process [%d] namespace pid", pid)
+		}
+		return uint32(nsPid), nil
+	}
+	return pid, nil
+}
+
+func readUptime(procRoot string) (float64, error) {
+	content, err := ioutil.ReadFile(filepath.Join(procRoot, "uptime"))
+	if err != nil {
+		return 0, errors.Wrapf(err, "Failed to read system uptime")
+	}
+	fields := strings.Fields(string(content))
+	if len(fields) == 0 {
+		return 0, fmt.Errorf("Invalid uptime format")
+	}
+	uptime, err := strconv.ParseFloat(fields[0], 64)
+	if err != nil {
+		return 0, errors.Wrapf(err, "Invalid system uptime")
+	}
+	return uptime, nil
+}
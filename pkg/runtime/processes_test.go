@@ -0,0 +1,69 @@
+package runtime
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func writeFakeProcess(t *testing.T, procRoot string, pid, nsPid, ppid uint32, name, cmdline string, ticks, start, rssPages uint64) {
+	dir := filepath.Join(procRoot, fmt.Sprintf("%d", pid))
+	assert.NoError(t, os.MkdirAll(dir, 0755))
+
+	// pid (comm) state ppid pgrp session tty_nr tpgid flags minflt cminflt majflt cmajflt utime stime
+	// cutime cstime priority nice num_threads itrealvalue starttime vsize rss
+	stat := fmt.Sprintf("%d (%s) S %d 1 1 0 -1 0 0 0 0 0 %d %d 0 0 20 0 1 0 %d 1000 %d 0 0\n", pid, name, ppid, ticks, ticks, start, rssPages)
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(dir, "stat"), []byte(stat), 0644))
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(dir, "cmdline"), []byte(cmdline), 0644))
+	status := fmt.Sprintf("Name:\t%s\nPid:\t%d\nNSpid:\t%d\t%d\n", name, pid, pid, nsPid)
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(dir, "status"), []byte(status), 0644))
+}
+
+func TestReadProcesses(t *testing.T) {
+	procRoot, _ := ioutil.TempDir("", "proc")
+	defer os.RemoveAll(procRoot)
+
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(procRoot, "uptime"), []byte("110.00 200.00\n"), 0644))
+	writeFakeProcess(t, procRoot, 5001, 7, 5000, "my worker", "worker\x00--verbose\x00", 50, 1000, 10)
+	writeFakeProcess(t, procRoot, 5000, 1, 4999, "sh", "sh\x00-c\x00run.sh\x00", 250, 1000, 2)
+	writeFakeProcess(t, procRoot, 5003, 9, 5000, "defunct", "", 0, 1000, 0)
+
+	processes, err := readProcesses(procRoot, []uint32{5001, 5000, 5002, 5003}, 5000)
+	assert.NoError(t, err)
+	assert.Len(t, processes, 3, "should leave out the exited process 5002")
+
+	init := processes[0]
+	assert.True(t, init.Init)
+	assert.Equal(t, uint32(1), init.PID)
+	assert.Equal(t, uint32(5000), init.HostPID)
+	assert.Equal(t, uint32(0), init.PPID, "parent outside of the container should be zero")
+	assert.Equal(t, "sh -c run.sh", init.Command)
+	assert.InDelta(t, 5.0, init.CPUPercent, 0.001)
+	assert.Equal(t, uint64(2*os.Getpagesize()), init.RSSBytes)
+
+	worker := processes[1]
+	assert.False(t, worker.Init)
+	assert.Equal(t, uint32(7), worker.PID)
+	assert.Equal(t, uint32(1), worker.PPID, "parent should be mapped to the container pid namespace")
+	assert.Equal(t, "worker --verbose", worker.Command)
+	assert.InDelta(t, 1.0, worker.CPUPercent, 0.001)
+
+	assert.Equal(t, "[defunct]", processes[2].Command)
+}
+
+func TestReadProcessesWithoutInit(t *testing.T) {
+	procRoot, _ := ioutil.TempDir("", "proc")
+	defer os.RemoveAll(procRoot)
+
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(procRoot, "uptime"), []byte("110.00 200.00\n"), 0644))
+	writeFakeProcess(t, procRoot, 5001, 7, 4999, "worker", "worker\x00", 0, 1000, 1)
+
+	processes, err := readProcesses(procRoot, []uint32{5001}, 5000)
+	assert.NoError(t, err)
+	assert.Len(t, processes, 1)
+	assert.False(t, processes[0].Init)
+}
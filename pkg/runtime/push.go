@@ -0,0 +1,166 @@
+package runtime
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/containerd/containerd"
+	"github.com/containerd/containerd/errdefs"
+	"github.com/containerd/containerd/images"
+	"github.com/containerd/containerd/remotes"
+	"github.com/containerd/containerd/remotes/docker"
+	"github.com/ernoaapa/eliot/pkg/progress"
+	imagespecs "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+)
+
+// RegistryAuth is the image registry credentials, empty username means anonymous access
+type RegistryAuth struct {
+	Username string
+	Password string
+}
+
+// TagImage stores the source image with the target name, the target gets replaced if it already exists
+func (c *ContainerdClient) TagImage(namespace, source, target string) error {
+	ctx, cancel := c.getContext()
+	defer cancel()
+
+	client, err := c.getConnection(namespace)
+	if err != nil {
+		return err
+	}
+
+	img, err := client.ImageService().Get(ctx, source)
+	if err != nil {
+		if errdefs.IsNotFound(err) {
+			return ErrWithMessagef(ErrNotFound, "Image [%s] not found", source)
+		}
+		return errors.Wrapf(err, "Failed to get image [%s]", source)
+	}
+
+	img.Name = target
+	if _, err := storeImage(ctx, client, img); err != nil {
+		return errors.Wrapf(err, "Failed to tag image [%s] as [%s]", source, target)
+	}
+	log.Debugf("Image [%s] tagged as [%s] in namespace [%s]", source, target, namespace)
+	return nil
+}
+
+// PushImage uploads the image from the namespace to the registry, the progress layers are the uploaded blobs.
+// Closing the cancel channel aborts the push. Returns ErrUnauthorized if the registry rejects the credentials.
+func (c *ContainerdClient) PushImage(namespace, ref string, auth RegistryAuth, progress *progress.ImageFetch, cancelPush <-chan struct{}) error {
+	ctx, cancel := c.getContext()
+	defer cancel()
+
+	client, err := c.getConnection(namespace)
+	if err != nil {
+		return err
+	}
+
+	img, err := client.ImageService().Get(ctx, ref)
+	if err != nil {
+		if errdefs.IsNotFound(err) {
+			return ErrWithMessagef(ErrNotFound, "Image [%s] not found", ref)
+		}
+		return errors.Wrapf(err, "Failed to get image [%s]", ref)
+	}
+
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		select {
+		case <-cancelPush:
+			cancel()
+		case <-done:
+		}
+	}()
+
+	tracker := newPushTracker(progress)
+	options := docker.ResolverOptions{
+		Client:  http.DefaultClient,
+		Tracker: tracker,
+	}
+	if auth.Username != "" {
+		options.Credentials = func(host string) (string, string, error) {
+			return auth.Username, auth.Password, nil
+		}
+	}
+
+	err = client.Push(
+		ctx,
+		ref,
+		img.Target,
+		containerd.WithResolver(docker.NewResolver(options)),
+		containerd.WithImageHandler(images.HandlerFunc(tracker.add)),
+	)
+	if isClosed(cancelPush) {
+		return ErrWithMessagef(context.Canceled, "Push of image [%s] from namespace [%s] cancelled", ref, namespace)
+	}
+	if err != nil {
+		if isUnauthorized(err) {
+			return ErrWithMessagef(ErrUnauthorized, "Registry rejected the credentials to push image [%s]: %s", ref, err)
+		}
+		return errors.Wrapf(err, "Error while pushing image [%s] from namespace [%s]", ref, namespace)
+	}
+
+	progress.AllDone()
+	return nil
+}
+
+// isUnauthorized return true if the registry rejected the push because of missing or invalid credentials
+func isUnauthorized(err error) bool {
+	if errors.Cause(err) == docker.ErrInvalidAuthorization {
+		return true
+	}
+	message := err.Error()
+	return strings.Contains(message, "401 Unauthorized") || strings.Contains(message, "403 Forbidden")
+}
+
+// pushTracker is docker.StatusTracker implementation what updates the upload status to the progress
+type pushTracker struct {
+	docker.StatusTracker
+	progress *progress.ImageFetch
+	// sizes of the blobs by ref, the registry doesn't report size of the blobs what it already has
+	sizes map[string]int64
+	mu    sync.Mutex
+}
+
+func newPushTracker(progress *progress.ImageFetch) *pushTracker {
+	return &pushTracker{
+		StatusTracker: docker.NewInMemoryTracker(),
+		progress:      progress,
+		sizes:         map[string]int64{},
+	}
+}
+
+// add is images.Handler what adds each pushed blob to the progress
+func (t *pushTracker) add(ctx context.Context, desc imagespecs.Descriptor) ([]imagespecs.Descriptor, error) {
+	ref := remotes.MakeRefKey(ctx, desc)
+	t.mu.Lock()
+	t.sizes[ref] = desc.Size
+	t.mu.Unlock()
+	t.progress.Add(ref, desc.Digest.String())
+	return nil, nil
+}
+
+func (t *pushTracker) SetStatus(ref string, status docker.Status) {
+	t.StatusTracker.SetStatus(ref, status)
+
+	if status.Total == 0 {
+		// The registry already has the blob
+		t.mu.Lock()
+		size := t.sizes[ref]
+		t.mu.Unlock()
+		t.progress.SetToDownloading(ref, size, size)
+		t.progress.SetToDone(ref)
+		return
+	}
+
+	t.progress.SetToDownloading(ref, status.Offset, status.Total)
+	if status.Offset == status.Total {
+		t.progress.SetToDone(ref)
+	}
+}
@@ -0,0 +1,49 @@
+package runtime
+
+import (
+	"context"
+	"testing"
+
+	"github.com/containerd/containerd/content"
+	"github.com/containerd/containerd/remotes/docker"
+	"github.com/ernoaapa/eliot/pkg/progress"
+	digest "github.com/opencontainers/go-digest"
+	imagespecs "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPushTrackerUpdatesProgress(t *testing.T) {
+	fetch := progress.NewImageFetch("", "docker.io/library/myapp:latest")
+	tracker := newPushTracker(fetch)
+
+	layer := imagespecs.Descriptor{MediaType: imagespecs.MediaTypeImageLayerGzip, Digest: digest.FromString("layer"), Size: 100}
+	existing := imagespecs.Descriptor{MediaType: imagespecs.MediaTypeImageLayerGzip, Digest: digest.FromString("existing"), Size: 50}
+	for _, desc := range []imagespecs.Descriptor{layer, existing} {
+		_, err := tracker.add(context.Background(), desc)
+		assert.NoError(t, err)
+	}
+
+	layerRef := "layer-" + layer.Digest.String()
+	tracker.SetStatus(layerRef, docker.Status{Status: content.Status{Ref: layerRef, Offset: 40, Total: 100}})
+	tracker.SetStatus("layer-"+existing.Digest.String(), docker.Status{Status: content.Status{Ref: "layer-" + existing.Digest.String()}})
+
+	current, total := fetch.GetProgress()
+	assert.Equal(t, int64(90), current)
+	assert.Equal(t, int64(150), total)
+	assert.False(t, fetch.IsDone())
+
+	tracker.SetStatus(layerRef, docker.Status{Status: content.Status{Ref: layerRef, Offset: 100, Total: 100}})
+	assert.True(t, fetch.IsDone())
+
+	status, err := tracker.GetStatus(layerRef)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(100), status.Offset, "should keep the status for the pusher")
+}
+
+func TestIsUnauthorized(t *testing.T) {
+	assert.True(t, isUnauthorized(errors.Wrap(docker.ErrInvalidAuthorization, "server message: invalid credentials")))
+	assert.True(t, isUnauthorized(errors.New("unexpected response: 401 Unauthorized")))
+	assert.True(t, isUnauthorized(errors.New("unexpected status: 403 Forbidden")))
+	assert.False(t, isUnauthorized(errors.New("unexpected response: 500 Internal Server Error")))
+}
@@ -0,0 +1,53 @@
+package runtime
+
+import (
+	"sync"
+	"time"
+)
+
+// ProbeResult is the result of the container readiness probe
+type ProbeResult struct {
+	// StartedAt is the container start time when the probe was run, the result is ignored after the container restarts
+	StartedAt time.Time
+	Ready     bool
+	// Message tells why the probe failed
+	Message string
+}
+
+// ReadinessStore keeps track of the latest readiness probe result of each container
+type ReadinessStore struct {
+	mu      sync.Mutex
+	results map[string]ProbeResult
+}
+
+// NewReadinessStore creates new empty ReadinessStore
+func NewReadinessStore() *ReadinessStore {
+	return &ReadinessStore{
+		results: map[string]ProbeResult{},
+	}
+}
+
+// Set stores the latest probe result of the container
+func (s *ReadinessStore) Set(namespace, id string, result ProbeResult) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.results[logStoreKey(namespace, id)] = result
+}
+
+// Remove clears the result, e.g. when the container get deleted
+func (s *ReadinessStore) Remove(namespace, id string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.results, logStoreKey(namespace, id))
+}
+
+// Get return the probe result of the container run which started at the time, ok false if the probe has not run yet
+func (s *ReadinessStore) Get(namespace, id string, startedAt time.Time) (result ProbeResult, ok bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	result, ok = s.results[logStoreKey(namespace, id)]
+	if !ok || !result.StartedAt.Equal(startedAt) {
+		return ProbeResult{}, false
+	}
+	return result, true
+}
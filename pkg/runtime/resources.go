@@ -0,0 +1,116 @@
+package runtime
+
+import (
+	"github.com/containerd/containerd"
+	"github.com/containerd/containerd/errdefs"
+	opts "github.com/ernoaapa/eliot/pkg/runtime/containerd"
+	specs "github.com/opencontainers/runtime-spec/specs-go"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+)
+
+// ResourceLimits are the container cgroup memory and CPU limits, zero means no limit or unchanged in update
+type ResourceLimits struct {
+	MemoryLimitBytes int64
+	// CPUShares is the relative CPU weight, CPUQuota and CPUPeriod the CPU time in microseconds per period
+	CPUShares uint64
+	CPUQuota  int64
+	CPUPeriod uint64
+}
+
+// UpdateContainerResources updates the limits of the running container task cgroup without restarting it.
+// The limits are stored also in the container spec, so they are kept when the container gets restarted.
+// Only the non-zero limits are changed. Return the limits of the updated container spec.
+// Returns ErrNotRunning if the container task is not running.
+func (c *ContainerdClient) UpdateContainerResources(namespace, name string, limits ResourceLimits) (ResourceLimits, error) {
+	ctx, cancel := c.getContext()
+	defer cancel()
+
+	client, err := c.getConnection(namespace)
+	if err != nil {
+		return ResourceLimits{}, err
+	}
+
+	container, err := client.LoadContainer(ctx, name)
+	if err != nil {
+		if errdefs.IsNotFound(err) {
+			return ResourceLimits{}, ErrWithMessagef(ErrNotFound, "Container [%s] not found", name)
+		}
+		return ResourceLimits{}, errors.Wrapf(err, "Failed to load container [%s], cannot update resources", name)
+	}
+
+	task, err := container.Task(ctx, nil)
+	if err != nil {
+		if errdefs.IsNotFound(err) {
+			return ResourceLimits{}, ErrWithMessagef(ErrNotRunning, "Container [%s] is not running", name)
+		}
+		return ResourceLimits{}, errors.Wrapf(err, "Unable to get task in container [%s], cannot update resources", name)
+	}
+
+	status, err := task.Status(ctx)
+	if err != nil {
+		return ResourceLimits{}, errors.Wrapf(err, "Failed to resolve container [%s] task status", name)
+	}
+	if status.Status != containerd.Running {
+		return ResourceLimits{}, ErrWithMessagef(ErrNotRunning, "Container [%s] is not running (%s)", name, status.Status)
+	}
+
+	resources := mapToLinuxResources(limits)
+	if err := updateTaskResources(ctx, task, resources); err != nil {
+		return ResourceLimits{}, errors.Wrapf(err, "Failed to update container [%s] resources", name)
+	}
+	log.Debugf("Container [%s] resources updated: %+v", name, limits)
+
+	if err := container.Update(ctx, opts.WithUpdatedResources(resources)); err != nil {
+		return ResourceLimits{}, errors.Wrapf(err, "Container [%s] resources updated, but failed to store them in the container spec", name)
+	}
+
+	spec, err := container.Spec(ctx)
+	if err != nil {
+		return ResourceLimits{}, errors.Wrapf(err, "Failed to read container [%s] spec", name)
+	}
+	return mapFromLinuxResources(spec), nil
+}
+
+// mapToLinuxResources return the OCI spec resources with only the non-zero limits set
+func mapToLinuxResources(limits ResourceLimits) *specs.LinuxResources {
+	resources := &specs.LinuxResources{}
+	if limits.MemoryLimitBytes != 0 {
+		resources.Memory = &specs.LinuxMemory{Limit: &limits.MemoryLimitBytes}
+	}
+	if limits.CPUShares != 0 || limits.CPUQuota != 0 || limits.CPUPeriod != 0 {
+		resources.CPU = &specs.LinuxCPU{}
+		if limits.CPUShares != 0 {
+			resources.CPU.Shares = &limits.CPUShares
+		}
+		if limits.CPUQuota != 0 {
+			resources.CPU.Quota = &limits.CPUQuota
+		}
+		if limits.CPUPeriod != 0 {
+			resources.CPU.Period = &limits.CPUPeriod
+		}
+	}
+	return resources
+}
+
+// mapFromLinuxResources return the limits in the OCI spec, zero for the ones which are not set
+func mapFromLinuxResources(spec *specs.Spec) (result ResourceLimits) {
+	if spec.Linux == nil || spec.Linux.Resources == nil {
+		return result
+	}
+	if memory := spec.Linux.Resources.Memory; memory != nil && memory.Limit != nil {
+		result.MemoryLimitBytes = *memory.Limit
+	}
+	if cpu := spec.Linux.Resources.CPU; cpu != nil {
+		if cpu.Shares != nil {
+			result.CPUShares = *cpu.Shares
+		}
+		if cpu.Quota != nil {
+			result.CPUQuota = *cpu.Quota
+		}
+		if cpu.Period != nil {
+			result.CPUPeriod = *cpu.Period
+		}
+	}
+	return result
+}
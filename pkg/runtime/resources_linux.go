@@ -0,0 +1,13 @@
+package runtime
+
+import (
+	"context"
+
+	"github.com/containerd/containerd"
+	specs "github.com/opencontainers/runtime-spec/specs-go"
+)
+
+// updateTaskResources updates the running task cgroup limits
+func updateTaskResources(ctx context.Context, task containerd.Task, resources *specs.LinuxResources) error {
+	return task.Update(ctx, containerd.WithResources(resources))
+}
@@ -0,0 +1,15 @@
+// +build !linux
+
+package runtime
+
+import (
+	"context"
+
+	"github.com/containerd/containerd"
+	specs "github.com/opencontainers/runtime-spec/specs-go"
+)
+
+// updateTaskResources is supported only in Linux, because the limits are cgroup limits
+func updateTaskResources(ctx context.Context, task containerd.Task, resources *specs.LinuxResources) error {
+	return ErrWithMessagef(ErrNotSupported, "Updating container resources is supported only in Linux")
+}
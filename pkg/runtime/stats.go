@@ -0,0 +1,17 @@
+package runtime
+
+import "time"
+
+// ContainerStats is the container resource usage at the moment
+type ContainerStats struct {
+	Time time.Time
+	// CPUNanoseconds is total CPU time consumed by the container
+	CPUNanoseconds uint64
+	// MemoryUsageBytes is current memory usage, including page cache
+	MemoryUsageBytes uint64
+	// MemoryLimitBytes is the memory limit of the container cgroup
+	MemoryLimitBytes uint64
+	// BlkioReadBytes and BlkioWriteBytes are total bytes read and written to block devices
+	BlkioReadBytes  uint64
+	BlkioWriteBytes uint64
+}
@@ -31,3 +31,13 @@ func getValues(podsByName map[string]*model.Pod) (result []model.Pod) {
 	}
 	return result
 }
+
+// isClosed return true if the channel is closed, nil channel is never closed
+func isClosed(ch <-chan struct{}) bool {
+	select {
+	case <-ch:
+		return true
+	default:
+		return false
+	}
+}
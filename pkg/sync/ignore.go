@@ -0,0 +1,94 @@
+package sync
+
+import (
+	"bufio"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// IgnoreFile is the file in the synced directory which lists the paths to not sync
+const IgnoreFile = ".syncignore"
+
+// Ignore matches the paths listed in .syncignore file.
+// Each line is a glob pattern, e.g. *.log. Pattern with slash, e.g. build/*.o, is matched against
+// the path relative to the synced directory, otherwise against each path element.
+// Pattern ending with slash matches only directories, empty lines and lines starting with # are skipped.
+type Ignore struct {
+	patterns []ignorePattern
+}
+
+type ignorePattern struct {
+	glob    string
+	dirOnly bool
+	rooted  bool
+}
+
+// LoadIgnore reads the .syncignore file from the directory, if there's no such file nothing is ignored
+func LoadIgnore(dir string) (*Ignore, error) {
+	file, err := os.Open(filepath.Join(dir, IgnoreFile))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return &Ignore{}, nil
+		}
+		return nil, errors.Wrapf(err, "Failed to read %s", IgnoreFile)
+	}
+	defer file.Close()
+
+	lines := []string{}
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		lines = append(lines, scanner.Text())
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, errors.Wrapf(err, "Failed to read %s", IgnoreFile)
+	}
+	return ParseIgnore(lines)
+}
+
+// ParseIgnore parses the .syncignore lines, returns error if some pattern is malformed
+func ParseIgnore(lines []string) (*Ignore, error) {
+	result := &Ignore{}
+	for _, line := range lines {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		pattern := ignorePattern{}
+		if strings.HasSuffix(line, "/") {
+			pattern.dirOnly = true
+			line = strings.TrimSuffix(line, "/")
+		}
+		pattern.rooted = strings.Contains(line, "/")
+		pattern.glob = strings.TrimPrefix(line, "/")
+
+		if _, err := path.Match(pattern.glob, ""); err != nil {
+			return nil, errors.Wrapf(err, "Invalid %s pattern [%s]", IgnoreFile, line)
+		}
+		result.patterns = append(result.patterns, pattern)
+	}
+	return result, nil
+}
+
+// Match return true if the path relative to the synced directory should not be synced.
+// Directories must be matched before their content, the content of ignored directory is not matched.
+func (i *Ignore) Match(relPath string, isDir bool) bool {
+	relPath = filepath.ToSlash(relPath)
+	for _, pattern := range i.patterns {
+		if pattern.dirOnly && !isDir {
+			continue
+		}
+		name := path.Base(relPath)
+		if pattern.rooted {
+			name = relPath
+		}
+		if matched, _ := path.Match(pattern.glob, name); matched {
+			return true
+		}
+	}
+	return false
+}
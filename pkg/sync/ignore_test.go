@@ -0,0 +1,48 @@
+package sync
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIgnoreMatch(t *testing.T) {
+	ignore, err := ParseIgnore([]string{
+		"# build output",
+		"*.log",
+		"",
+		"node_modules/",
+		"/build/*.o",
+	})
+	assert.NoError(t, err)
+
+	assert.True(t, ignore.Match("debug.log", false))
+	assert.True(t, ignore.Match("logs/debug.log", false), "should match the name in any directory")
+	assert.True(t, ignore.Match("web/node_modules", true))
+	assert.False(t, ignore.Match("node_modules", false), "should match only directories")
+	assert.True(t, ignore.Match("build/main.o", false))
+	assert.False(t, ignore.Match("src/build/main.o", false), "should match the pattern with slash from the root")
+	assert.False(t, ignore.Match("main.go", false))
+}
+
+func TestParseIgnoreInvalidPattern(t *testing.T) {
+	_, err := ParseIgnore([]string{"[a-"})
+	assert.Error(t, err)
+}
+
+func TestLoadIgnoreWithoutFile(t *testing.T) {
+	dir, _ := ioutil.TempDir("", "sync")
+	defer os.RemoveAll(dir)
+
+	ignore, err := LoadIgnore(dir)
+	assert.NoError(t, err)
+	assert.False(t, ignore.Match("debug.log", false))
+
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(dir, IgnoreFile), []byte("*.log\n"), 0644))
+	ignore, err = LoadIgnore(dir)
+	assert.NoError(t, err)
+	assert.True(t, ignore.Match("debug.log", false))
+}
@@ -0,0 +1,141 @@
+// Package template renders Pod manifests written as Go text/template, so the same pod can be
+// deployed to many nodes with per-node values, e.g. image: myapp:{{ .version | default "latest" }}
+package template
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	gotemplate "text/template"
+
+	pods "github.com/ernoaapa/eliot/pkg/api/services/pods/v1"
+	"github.com/ernoaapa/eliot/pkg/manifest"
+	"github.com/pkg/errors"
+)
+
+// templateName is the name of the template in the text/template errors
+const templateName = "pod"
+
+// errorPattern matches the text/template error, e.g. 'template: pod:3:14: executing "pod" at <.x>: ...'
+var errorPattern = regexp.MustCompile(`^template: ` + templateName + `:(\d+)(?::\d+)?: (.*)$`)
+
+// missingValue is what text/template writes for the values missing from the values map
+const missingValue = "<no value>"
+
+// RenderPodTemplate executes the manifest template with the values and decodes the result to the pod.
+// The template can use the Sprig style functions default, env, quote, required, lower, upper and trim.
+// Missing values are rendered as empty, use default to give a fallback or required to fail.
+// Returns TemplateError with the template line if the template is invalid or the execution fails,
+// or the manifest.ParseError if the rendered manifest is not valid pod.
+func RenderPodTemplate(tmpl []byte, values map[string]interface{}) (*pods.Pod, error) {
+	rendered, err := Render(tmpl, values)
+	if err != nil {
+		return nil, err
+	}
+
+	pod, err := manifest.LoadPod(bytes.NewReader(rendered))
+	if err != nil {
+		return nil, errors.Wrapf(err, "Rendered template is not valid pod manifest")
+	}
+	return pod, nil
+}
+
+// Render executes the manifest template with the values and returns the rendered manifest
+func Render(tmpl []byte, values map[string]interface{}) ([]byte, error) {
+	t, err := gotemplate.New(templateName).Funcs(funcs).Parse(string(tmpl))
+	if err != nil {
+		return nil, newTemplateError(tmpl, err)
+	}
+
+	var out bytes.Buffer
+	if err := t.Execute(&out, values); err != nil {
+		return nil, newTemplateError(tmpl, err)
+	}
+	return bytes.Replace(out.Bytes(), []byte(missingValue), []byte{}, -1), nil
+}
+
+var funcs = gotemplate.FuncMap{
+	"default":  defaultValue,
+	"env":      os.Getenv,
+	"quote":    quote,
+	"required": required,
+	"lower":    strings.ToLower,
+	"upper":    strings.ToUpper,
+	"trim":     strings.TrimSpace,
+}
+
+// defaultValue return the value, or the fallback if the value is missing or empty.
+// The value is variadic so that missing piped value, e.g. {{ .tag | default "latest" }}, works.
+func defaultValue(fallback interface{}, value ...interface{}) interface{} {
+	if len(value) == 0 || isEmpty(value[0]) {
+		return fallback
+	}
+	return value[0]
+}
+
+// required return error with the message if the value is missing or empty
+func required(message string, value interface{}) (interface{}, error) {
+	if isEmpty(value) {
+		return nil, errors.New(message)
+	}
+	return value, nil
+}
+
+// quote return the values as double quoted strings separated with space
+func quote(values ...interface{}) string {
+	result := []string{}
+	for _, value := range values {
+		if value != nil {
+			result = append(result, strconv.Quote(fmt.Sprint(value)))
+		}
+	}
+	return strings.Join(result, " ")
+}
+
+func isEmpty(value interface{}) bool {
+	if value == nil {
+		return true
+	}
+	v := reflect.ValueOf(value)
+	switch v.Kind() {
+	case reflect.Array, reflect.Map, reflect.Slice, reflect.String:
+		return v.Len() == 0
+	case reflect.Ptr, reflect.Interface:
+		return v.IsNil()
+	}
+	return reflect.DeepEqual(value, reflect.Zero(v.Type()).Interface())
+}
+
+// TemplateError is returned when the manifest template cannot be parsed or executed
+type TemplateError struct {
+	// Line is the template line number where the problem is, zero if unknown
+	Line int
+	// Source is the template line content, empty if the line is unknown
+	Source string
+	Err    error
+}
+
+func newTemplateError(tmpl []byte, err error) *TemplateError {
+	match := errorPattern.FindStringSubmatch(err.Error())
+	if match == nil {
+		return &TemplateError{Err: err}
+	}
+
+	line, _ := strconv.Atoi(match[1])
+	result := &TemplateError{Line: line, Err: errors.New(match[2])}
+	if lines := strings.Split(string(tmpl), "\n"); line > 0 && line <= len(lines) {
+		result.Source = strings.TrimSpace(lines[line-1])
+	}
+	return result
+}
+
+func (e *TemplateError) Error() string {
+	if e.Line == 0 {
+		return fmt.Sprintf("Failed to render template: %s", e.Err)
+	}
+	return fmt.Sprintf("Failed to render template at line %d [%s]: %s", e.Line, e.Source, e.Err)
+}
@@ -0,0 +1,85 @@
+package template
+
+import (
+	"os"
+	"testing"
+
+	"github.com/ernoaapa/eliot/pkg/manifest"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+const podTemplate = `
+metadata:
+  name: myapp-{{ .device }}
+  labels:
+    owner: {{ env "TEMPLATE_TEST_OWNER" | quote }}
+spec:
+  containers:
+    - name: myapp
+      image: registry.local/team/myapp:{{ .version | default "latest" }}
+      env:
+        - DEVICE={{ .device | upper }}
+`
+
+func TestRenderPodTemplate(t *testing.T) {
+	os.Setenv("TEMPLATE_TEST_OWNER", "ops team")
+	defer os.Unsetenv("TEMPLATE_TEST_OWNER")
+
+	pod, err := RenderPodTemplate([]byte(podTemplate), map[string]interface{}{"device": "pi42"})
+	assert.NoError(t, err)
+	assert.Equal(t, "myapp-pi42", pod.Metadata.Name)
+	assert.Equal(t, "eliot", pod.Metadata.Namespace, "should default the pod")
+	assert.Equal(t, "ops team", pod.Metadata.Labels["owner"])
+	assert.Equal(t, "registry.local/team/myapp:latest", pod.Spec.Containers[0].Image, "should use default for missing value")
+	assert.Equal(t, []string{"DEVICE=PI42"}, pod.Spec.Containers[0].Env)
+
+	pod, err = RenderPodTemplate([]byte(podTemplate), map[string]interface{}{"device": "pi42", "version": "1.0"})
+	assert.NoError(t, err)
+	assert.Equal(t, "registry.local/team/myapp:1.0", pod.Spec.Containers[0].Image)
+}
+
+func TestRenderMissingValueIsEmpty(t *testing.T) {
+	out, err := Render([]byte("name: foo{{ .missing }}"), map[string]interface{}{})
+	assert.NoError(t, err)
+	assert.Equal(t, "name: foo", string(out))
+}
+
+func TestRenderNestedValues(t *testing.T) {
+	out, err := Render([]byte(`{{ .node.labels.region }}`), map[string]interface{}{
+		"node": map[string]interface{}{"labels": map[string]string{"region": "eu"}},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "eu", string(out))
+}
+
+func TestRenderParseErrorHasLine(t *testing.T) {
+	_, err := Render([]byte("metadata:\n  name: {{ .name | unknown }}\n"), nil)
+
+	templateErr, ok := err.(*TemplateError)
+	assert.True(t, ok, "should return TemplateError, got: %v", err)
+	assert.Equal(t, 2, templateErr.Line)
+	assert.Equal(t, "name: {{ .name | unknown }}", templateErr.Source)
+	assert.Contains(t, err.Error(), "line 2")
+	assert.Contains(t, err.Error(), `function "unknown" not defined`)
+}
+
+func TestRenderRequired(t *testing.T) {
+	_, err := Render([]byte("metadata:\n  name: {{ required \"device is required\" .device }}\n"), map[string]interface{}{})
+
+	templateErr, ok := err.(*TemplateError)
+	assert.True(t, ok, "should return TemplateError, got: %v", err)
+	assert.Equal(t, 2, templateErr.Line)
+	assert.Contains(t, err.Error(), "device is required")
+}
+
+func TestRenderPodTemplateInvalidManifest(t *testing.T) {
+	_, err := RenderPodTemplate([]byte("metadata: [{{ .name }}"), map[string]interface{}{"name": "foo"})
+	_, ok := errors.Cause(err).(*manifest.ParseError)
+	assert.True(t, ok, "should return the manifest ParseError, got: %v", err)
+}
+
+func TestQuote(t *testing.T) {
+	assert.Equal(t, `"foo" "1"`, quote("foo", 1))
+	assert.Equal(t, ``, quote(nil))
+}